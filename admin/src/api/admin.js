@@ -93,6 +93,10 @@ export function getUserCenterOverview(id, params) {
   });
 }
 
+export function revealUserPII(id, reason) {
+  return http.post(`/admin/users/${encodeURIComponent(id)}/pii/reveal`, { reason });
+}
+
 export function updateUserSubscription(userID, subscriptionID, payload) {
   return http.put(
     `/admin/users/${encodeURIComponent(userID)}/subscriptions/${encodeURIComponent(subscriptionID)}`,
@@ -186,6 +190,21 @@ export function uploadNewsAttachmentFile(formData) {
   return http.post("/admin/news/attachments/upload", formData);
 }
 
+export function downloadNewsAttachmentFile(id) {
+  return http.get(`/admin/news/attachments/${encodeURIComponent(id)}/download`, {
+    responseType: "blob",
+    timeout: 120000
+  });
+}
+
+export function extractNewsAttachmentText(id) {
+  return http.post(`/admin/news/attachments/${encodeURIComponent(id)}/text/extract`, null, { timeout: 120000 });
+}
+
+export function traceNewsAttachmentWatermark(formData) {
+  return http.post("/admin/news/attachments/watermark/trace", formData);
+}
+
 export function deleteNewsAttachment(id) {
   return http.delete(`/admin/news/attachments/${encodeURIComponent(id)}`);
 }
@@ -395,6 +414,73 @@ export function reviewTaskDecision(id, status, reviewNote) {
   });
 }
 
+export function listApprovalPolicies() {
+  return http.get("/admin/workflow/approval-policies");
+}
+
+export function saveApprovalPolicy(module, payload) {
+  return http.put(`/admin/workflow/approval-policies/${encodeURIComponent(module)}`, payload);
+}
+
+export function listApprovalRequests(params) {
+  return http.get("/admin/workflow/approvals", { params: buildParams(params) });
+}
+
+export function getApprovalRequest(id) {
+  return http.get(`/admin/workflow/approvals/${encodeURIComponent(id)}`);
+}
+
+export function decideApprovalRequest(id, decision, note) {
+  return http.post(`/admin/workflow/approvals/${encodeURIComponent(id)}/decision`, {
+    decision,
+    note
+  });
+}
+
+export function retryApprovalExecution(id) {
+  return http.post(`/admin/workflow/approvals/${encodeURIComponent(id)}/execute`);
+}
+
+export function listReviewSLAPolicies() {
+  return http.get("/admin/workflow/sla/policies");
+}
+
+export function saveReviewSLAPolicy(module, payload) {
+  return http.put(`/admin/workflow/sla/policies/${encodeURIComponent(module)}`, payload);
+}
+
+export function listReviewSLAItems(params) {
+  return http.get("/admin/workflow/sla/items", { params: buildParams(params) });
+}
+
+export function getReviewSLAMetrics(params) {
+  return http.get("/admin/workflow/sla/metrics", { params: buildParams(params) });
+}
+
+export function requestExportJob(exportType, payload) {
+  return http.post(`/admin/exports/${encodeURIComponent(exportType)}`, payload);
+}
+
+export function listExportJobs(params) {
+  return http.get("/admin/exports/jobs", { params: buildParams(params) });
+}
+
+export function getExportJob(id) {
+  return http.get(`/admin/exports/jobs/${encodeURIComponent(id)}`);
+}
+
+export function createExportDownloadLink(id) {
+  return http.post(`/admin/exports/jobs/${encodeURIComponent(id)}/link`);
+}
+
+export function listAllExportJobs(params) {
+  return http.get("/admin/audit/exports", { params: buildParams(params) });
+}
+
+export function listExportDownloads(id) {
+  return http.get(`/admin/audit/exports/${encodeURIComponent(id)}/downloads`);
+}
+
 export function listSchedulerJobDefinitions(params) {
   return http.get("/admin/system/job-definitions", { params: buildParams(params) });
 }
@@ -1039,6 +1125,10 @@ export function listAuthLoginLogs(params) {
   return http.get("/admin/auth/login-logs", { params: buildParams(params) });
 }
 
+export function revealAuthLoginLogPII(id, reason) {
+  return http.post(`/admin/auth/login-logs/${encodeURIComponent(id)}/reveal`, { reason });
+}
+
 export function getAuthRiskConfig() {
   return http.get("/admin/auth/risk-config");
 }
@@ -32,6 +32,16 @@ type CommunityReportCreateRequest struct {
 	Reason     string `json:"reason" binding:"required"`
 }
 
+type CommunityFollowCreateRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=USER STOCK FUTURES TOPIC_TYPE"`
+	TargetID   string `json:"target_id" binding:"required,max=64"`
+}
+
+type CommunityBlockCreateRequest struct {
+	BlockedUserID string `json:"blocked_user_id" binding:"required,max=64"`
+	BlockType     string `json:"block_type" binding:"required,oneof=MUTE BLOCK"`
+}
+
 type CommunityStatusUpdateRequest struct {
 	Status string `json:"status" binding:"required,oneof=PUBLISHED PENDING_REVIEW HIDDEN DELETED"`
 }
@@ -40,3 +50,22 @@ type CommunityReportReviewRequest struct {
 	Status     string `json:"status" binding:"required,oneof=RESOLVED REJECTED"`
 	ReviewNote string `json:"review_note"`
 }
+
+type CommunitySensitiveWordCreateRequest struct {
+	Word     string `json:"word" binding:"required,max=64"`
+	Category string `json:"category" binding:"required,max=32"`
+}
+
+type CommunitySensitiveWordStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=ACTIVE DISABLED"`
+}
+
+type CommunityUserBanCreateRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Days   int    `json:"days" binding:"required,min=1,max=3650"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type CommunitySentimentRebuildRequest struct {
+	StatDate string `json:"stat_date" binding:"required"`
+}
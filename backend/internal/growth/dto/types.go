@@ -102,7 +102,7 @@ type NewsArticleRequest struct {
 	Content    string `json:"content" binding:"required"`
 	CoverURL   string `json:"cover_url"`
 	Visibility string `json:"visibility" binding:"required,oneof=PUBLIC VIP"`
-	Status     string `json:"status" binding:"required,oneof=PUBLISHED DRAFT DISABLED"`
+	Status     string `json:"status" binding:"required,oneof=PUBLISHED DRAFT DISABLED SCHEDULED"`
 }
 
 type NewsAttachmentRequest struct {
@@ -116,6 +116,15 @@ type NewsPublishRequest struct {
 	Status string `json:"status" binding:"required,oneof=PUBLISHED"`
 }
 
+type NewsArticleScheduleRequest struct {
+	PublishAt    string `json:"publish_at"`
+	EmbargoUntil string `json:"embargo_until"`
+}
+
+type NewsArticleMergeRequest struct {
+	CanonicalID string `json:"canonical_id" binding:"required"`
+}
+
 type DataSourceCreateRequest struct {
 	SourceKey  string                 `json:"source_key" binding:"required"`
 	Name       string                 `json:"name" binding:"required"`
@@ -218,7 +227,6 @@ type UpdateUserMemberLevelRequest struct {
 	MemberLevel string `json:"member_level" binding:"required"`
 }
 
-
 type AdminUserMessageCreateRequest struct {
 	UserIDs []string `json:"user_ids"`
 	Title   string   `json:"title" binding:"required"`
@@ -230,9 +238,8 @@ type UpdateUserProfileRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
-
 type SubscriptionCreateRequest struct {
-	Type      string `json:"type" binding:"required,oneof=STOCK_RECO FUTURES_STRATEGY ARBITRAGE EVENT"`
+	Type      string `json:"type" binding:"required,oneof=STOCK_RECO FUTURES_STRATEGY ARBITRAGE EVENT NEWS"`
 	Scope     string `json:"scope"`
 	Frequency string `json:"frequency" binding:"required,oneof=INSTANT DAILY WEEKLY"`
 }
@@ -272,10 +279,49 @@ type MembershipOrderStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=PENDING PAID CANCELED REFUNDED FAILED"`
 }
 
+type MembershipOrderRefundRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Offline bool   `json:"offline"`
+}
+
 type CreateMembershipOrderRequest struct {
-	ProductID  string                        `json:"product_id" binding:"required"`
-	PayChannel string                        `json:"pay_channel" binding:"required,oneof=ALIPAY WECHAT CARD YOLKPAY"`
-	Experiment *ExperimentAttributionRequest `json:"experiment"`
+	ProductID      string                        `json:"product_id" binding:"required"`
+	PayChannel     string                        `json:"pay_channel" binding:"required,oneof=ALIPAY WECHAT CARD YOLKPAY SANDBOX"`
+	CouponCode     string                        `json:"coupon_code" binding:"omitempty,max=32"`
+	Experiment     *ExperimentAttributionRequest `json:"experiment"`
+	SandboxOutcome string                        `json:"sandbox_outcome" binding:"omitempty,oneof=SUCCESS FAILURE DELAYED"`
+}
+
+type MembershipAutoRenewRequest struct {
+	ProductID  string `json:"product_id" binding:"required"`
+	PayChannel string `json:"pay_channel" binding:"required,oneof=YOLKPAY SANDBOX"`
+}
+
+type MembershipCouponQuoteRequest struct {
+	ProductID  string `json:"product_id" binding:"required"`
+	CouponCode string `json:"coupon_code" binding:"required,max=32"`
+}
+
+type MembershipCouponRequest struct {
+	Code              string   `json:"code" binding:"omitempty,max=32,alphanum"`
+	Name              string   `json:"name" binding:"required,max=64"`
+	DiscountType      string   `json:"discount_type" binding:"required,oneof=PERCENT FIXED"`
+	DiscountValue     float64  `json:"discount_value" binding:"required,gt=0"`
+	MaxDiscount       float64  `json:"max_discount" binding:"omitempty,gt=0"`
+	MinAmount         float64  `json:"min_amount" binding:"omitempty,gte=0"`
+	ValidFrom         string   `json:"valid_from"`
+	ValidTo           string   `json:"valid_to"`
+	TotalLimit        int      `json:"total_limit" binding:"omitempty,gte=0"`
+	PerUserLimit      int      `json:"per_user_limit" binding:"omitempty,gte=0"`
+	ProductIDs        []string `json:"product_ids"`
+	MemberLevels      []string `json:"member_levels"`
+	FirstPurchaseOnly bool     `json:"first_purchase_only"`
+	Source            string   `json:"source" binding:"omitempty,oneof=CAMPAIGN INVITE"`
+	Status            string   `json:"status" binding:"omitempty,oneof=ACTIVE DISABLED TEMPLATE"`
+}
+
+type MembershipCouponStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=ACTIVE DISABLED TEMPLATE"`
 }
 
 type VIPQuotaConfigRequest struct {
@@ -313,6 +359,7 @@ type ReviewSubmitRequest struct {
 	TargetID   string `json:"target_id" binding:"required"`
 	ReviewerID string `json:"reviewer_id"`
 	SubmitNote string `json:"submit_note"`
+	Priority   string `json:"priority" binding:"omitempty,oneof=HIGH NORMAL LOW"`
 }
 
 type ReviewDecisionRequest struct {
@@ -365,6 +412,60 @@ type SchedulerJobDefinitionStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=ACTIVE DISABLED"`
 }
 
+type SchedulerPipelineNodeRequest struct {
+	JobName   string   `json:"job_name" binding:"required"`
+	DependsOn []string `json:"depends_on"`
+}
+
+type SchedulerPipelineSaveRequest struct {
+	DisplayName string                         `json:"display_name"`
+	Status      string                         `json:"status" binding:"required,oneof=ACTIVE DISABLED"`
+	Nodes       []SchedulerPipelineNodeRequest `json:"nodes" binding:"required,min=1,dive"`
+}
+
+type SchedulerPipelineRunRequest struct {
+	TradeDate     string `json:"trade_date"`
+	TriggerSource string `json:"trigger_source" binding:"omitempty,oneof=MANUAL SYSTEM"`
+}
+
+type ApprovalStepRequest struct {
+	Name              string   `json:"name"`
+	RequiredApprovals int      `json:"required_approvals" binding:"required,min=1"`
+	ApproverRoles     []string `json:"approver_roles"`
+	ApproverIDs       []string `json:"approver_ids"`
+}
+
+type ApprovalPolicySaveRequest struct {
+	DisplayName string                `json:"display_name"`
+	Status      string                `json:"status" binding:"required,oneof=ACTIVE DISABLED"`
+	MinAmount   float64               `json:"min_amount" binding:"min=0"`
+	Steps       []ApprovalStepRequest `json:"steps" binding:"required,min=1,dive"`
+}
+
+type ApprovalDecisionRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=APPROVE REJECT"`
+	Note     string `json:"note"`
+}
+
+type ReviewSLAPolicySaveRequest struct {
+	Status            string `json:"status" binding:"required,oneof=ACTIVE DISABLED"`
+	HighMinutes       int    `json:"high_minutes" binding:"required,min=1"`
+	NormalMinutes     int    `json:"normal_minutes" binding:"required,min=1"`
+	LowMinutes        int    `json:"low_minutes" binding:"required,min=1"`
+	BusinessHoursOnly bool   `json:"business_hours_only"`
+	BusinessStart     string `json:"business_start"`
+	BusinessEnd       string `json:"business_end"`
+	Workdays          []int  `json:"workdays"`
+	Timezone          string `json:"timezone"`
+	BackupReviewerID  string `json:"backup_reviewer_id"`
+	BackupRole        string `json:"backup_role"`
+}
+
+type ExportJobCreateRequest struct {
+	Format  string            `json:"format" binding:"omitempty,oneof=CSV XLSX csv xlsx"`
+	Filters map[string]string `json:"filters"`
+}
+
 type WorkflowMessageReadRequest struct {
 	IsRead bool `json:"is_read"`
 }
@@ -441,6 +542,30 @@ type AdminRoleUpsertRequest struct {
 	Description     string   `json:"description"`
 	Status          string   `json:"status" binding:"required,oneof=ACTIVE DISABLED"`
 	PermissionCodes []string `json:"permission_codes" binding:"required,min=1,dive,required"`
+	Require2FA      *bool    `json:"require_2fa"`
+}
+
+type PIIRevealRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AdminResetTwoFactorRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type LoginTwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
 }
 
 type LoginResponse struct {
@@ -450,6 +575,21 @@ type LoginResponse struct {
 	ExpiresIn    int    `json:"expires_in"`
 	UserID       string `json:"user_id"`
 	Role         string `json:"role"`
+	// RecoveryCodes is only set when the login completed a 2FA enrollment.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type LoginTwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ChallengeType     string `json:"challenge_type"`
+	ExpiresIn         int    `json:"expires_in"`
+	UserID            string `json:"user_id"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
 }
 
 func OK(data interface{}) APIResponse {
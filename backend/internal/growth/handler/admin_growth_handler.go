@@ -1,20 +1,15 @@
 package handler
 
 import (
-	"bytes"
-	"crypto/hmac"
+	"context"
 	"crypto/rand"
-	"crypto/sha1"
 	"database/sql"
-	"encoding/base64"
-	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"mime"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
@@ -29,15 +24,22 @@ import (
 	"sercherai/backend/internal/growth/model"
 	"sercherai/backend/internal/growth/service"
 	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/objectstore"
+	"sercherai/backend/internal/platform/secrets"
 )
 
 type AdminGrowthHandler struct {
-	service service.GrowthService
-	cfg     config.Config
+	service           service.GrowthService
+	cfg               config.Config
+	configSecrets     *secrets.Keyring
+	approvalExecutors map[string]ApprovalExecutor
 }
 
 func NewAdminGrowthHandler(service service.GrowthService, cfg config.Config) *AdminGrowthHandler {
-	return &AdminGrowthHandler{service: service, cfg: cfg}
+	configSecrets, _ := secrets.Load(cfg)
+	h := &AdminGrowthHandler{service: service, cfg: cfg, configSecrets: configSecrets}
+	h.registerApprovalExecutors()
+	return h
 }
 
 var allowedNewsAttachmentMimePrefixes = []string{
@@ -61,6 +63,24 @@ const stockDefaultSourceFallback = "TUSHARE"
 const schedulerJobDailyFuturesStrategy = "daily_futures_strategy"
 const schedulerJobFuturesStrategyGenerate = "futures_strategy_generate"
 const schedulerJobFuturesStrategyEvaluate = "futures_strategy_evaluate"
+const schedulerJobFuturesArbitrageCompute = "futures_arbitrage_compute"
+const schedulerJobAuditLedgerCheckpoint = "audit_ledger_checkpoint"
+const schedulerJobInviteCommissionRelease = "invite_commission_release"
+const schedulerJobMembershipOrderPoll = "membership_order_poll"
+const schedulerJobMembershipAutoRenew = "membership_auto_renew"
+const schedulerJobCommunitySentiment = "community_sentiment_index"
+const schedulerJobNewsScheduledPublish = "news_scheduled_publish"
+const schedulerJobReviewSLAEscalation = "review_sla_escalation"
+const schedulerJobExportJobs = "export_jobs"
+const schedulerJobStockMasterSync = "stock_master_sync"
+const schedulerJobStockQuotesSync = "stock_quotes_sync"
+const schedulerJobStockDailyBasicSync = "stock_daily_basic_sync"
+const schedulerJobStockMoneyflowSync = "stock_moneyflow_sync"
+const schedulerJobStockTruthRebuild = "stock_truth_rebuild"
+const schedulerJobStockQuantRank = "stock_quant_rank"
+const schedulerJobDailyStockRecommendation = "daily_stock_recommendation"
+const schedulerJobStockSelectionRun = "stock_selection_run"
+const schedulerJobStockSelectionPublishReview = "stock_selection_publish_review"
 const schedulerAutoRetryEnabledConfigKey = "scheduler.auto_retry.enabled"
 const schedulerAutoRetryMaxRetriesConfigKey = "scheduler.auto_retry.max_retries"
 const schedulerAutoRetryBackoffSecondsConfigKey = "scheduler.auto_retry.backoff_seconds"
@@ -76,6 +96,13 @@ const ossQiniuRegionConfigKey = "oss.qiniu.region"
 const ossQiniuPathPrefixConfigKey = "oss.qiniu.path_prefix"
 const ossQiniuUseHTTPSConfigKey = "oss.qiniu.use_https"
 const ossUploadMaxSizeMBConfigKey = "oss.upload.max_size_mb"
+const ossS3EndpointConfigKey = "oss.s3.endpoint"
+const ossS3RegionConfigKey = "oss.s3.region"
+const ossS3BucketConfigKey = "oss.s3.bucket"
+const ossS3AccessKeyConfigKey = "oss.s3.access_key"
+const ossS3SecretKeyConfigKey = "oss.s3.secret_key"
+const ossS3PathStyleConfigKey = "oss.s3.path_style"
+const ossS3PathPrefixConfigKey = "oss.s3.path_prefix"
 
 type supportedSchedulerJob struct {
 	JobName     string `json:"job_name"`
@@ -90,9 +117,26 @@ var supportedSchedulerJobs = []supportedSchedulerJob{
 	{JobName: schedulerJobDailyFuturesStrategy, DisplayName: "每日期货策略", Module: "FUTURES"},
 	{JobName: schedulerJobFuturesStrategyGenerate, DisplayName: "期货策略生成(别名)", Module: "FUTURES", AliasOf: schedulerJobDailyFuturesStrategy},
 	{JobName: schedulerJobFuturesStrategyEvaluate, DisplayName: "期货策略评估", Module: "FUTURES"},
+	{JobName: schedulerJobFuturesArbitrageCompute, DisplayName: "期货套利价差计算", Module: "FUTURES"},
 	{JobName: "doc_fast_news_incremental", DisplayName: "DocFast资讯增量同步", Module: "NEWS"},
 	{JobName: "tushare_news_incremental", DisplayName: "Tushare资讯增量同步", Module: "NEWS"},
+	{JobName: schedulerJobNewsScheduledPublish, DisplayName: "资讯定时发布", Module: "NEWS"},
 	{JobName: "vip_membership_lifecycle", DisplayName: "VIP会员生命周期任务", Module: "SYSTEM"},
+	{JobName: schedulerJobAuditLedgerCheckpoint, DisplayName: "审计链签名检查点", Module: "SYSTEM"},
+	{JobName: schedulerJobInviteCommissionRelease, DisplayName: "邀请佣金解冻", Module: "SYSTEM"},
+	{JobName: schedulerJobMembershipOrderPoll, DisplayName: "待支付订单查单与超时关闭", Module: "SYSTEM"},
+	{JobName: schedulerJobMembershipAutoRenew, DisplayName: "会员自动续费与催缴", Module: "SYSTEM"},
+	{JobName: schedulerJobCommunitySentiment, DisplayName: "社区情绪指数", Module: "SYSTEM"},
+	{JobName: schedulerJobReviewSLAEscalation, DisplayName: "审核SLA超时升级", Module: "SYSTEM"},
+	{JobName: schedulerJobExportJobs, DisplayName: "后台导出任务处理", Module: "SYSTEM"},
+	{JobName: schedulerJobStockMasterSync, DisplayName: "股票主数据同步", Module: "STOCK"},
+	{JobName: schedulerJobStockQuotesSync, DisplayName: "股票日线行情同步", Module: "STOCK"},
+	{JobName: schedulerJobStockDailyBasicSync, DisplayName: "股票每日指标同步", Module: "STOCK"},
+	{JobName: schedulerJobStockMoneyflowSync, DisplayName: "股票资金流向同步", Module: "STOCK"},
+	{JobName: schedulerJobStockTruthRebuild, DisplayName: "股票日线真值重建", Module: "STOCK"},
+	{JobName: schedulerJobStockQuantRank, DisplayName: "股票量化评分", Module: "STOCK"},
+	{JobName: schedulerJobStockSelectionRun, DisplayName: "智能选股运行", Module: "STOCK"},
+	{JobName: schedulerJobStockSelectionPublishReview, DisplayName: "选股结果提交发布审核", Module: "STOCK"},
 }
 
 type ossUploadConfig struct {
@@ -106,6 +150,14 @@ type ossUploadConfig struct {
 	PathPrefix  string
 	UseHTTPS    bool
 	MaxUploadMB int
+
+	S3Endpoint   string
+	S3Region     string
+	S3Bucket     string
+	S3AccessKey  string
+	S3SecretKey  string
+	S3PathStyle  bool
+	S3PathPrefix string
 }
 
 func (h *AdminGrowthHandler) ListInviteRecords(c *gin.Context) {
@@ -245,6 +297,26 @@ func (h *AdminGrowthHandler) ReviewWithdrawRequest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	if req.Status == "APPROVED" {
+		withdraw, err := h.service.AdminGetWithdrawRequest(id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "withdraw request not found", Data: struct{}{}})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		if submitForApproval(c, h.service, model.ApprovalRequest{
+			Module:   model.ApprovalModuleWithdraw,
+			Action:   model.ApprovalActionWithdrawApprove,
+			TargetID: id,
+			Amount:   withdraw.Amount,
+			Summary:  fmt.Sprintf("提现审批通过 %s：用户 %s 金额 %.2f", id, withdraw.UserID, withdraw.Amount),
+		}, req) {
+			return
+		}
+	}
 	if err := h.service.AdminReviewWithdrawRequest(id, req.Status, req.Reason); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
@@ -337,6 +409,10 @@ func (h *AdminGrowthHandler) CreateNewsArticle(c *gin.Context) {
 	if authorID == "" {
 		authorID = "admin_unknown"
 	}
+	if req.Status == model.NewsArticleStatusScheduled {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "create the article as DRAFT and schedule it with /schedule", Data: struct{}{}})
+		return
+	}
 	id, err := h.service.AdminCreateNewsArticle(req.CategoryID, req.Title, req.Summary, req.Content, req.CoverURL, req.Visibility, req.Status, authorID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
@@ -352,8 +428,8 @@ func (h *AdminGrowthHandler) UpdateNewsArticle(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	if err := h.service.AdminUpdateNewsArticle(id, req.CategoryID, req.Title, req.Summary, req.Content, req.CoverURL, req.Visibility, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	if err := h.service.AdminUpdateNewsArticle(id, req.CategoryID, req.Title, req.Summary, req.Content, req.CoverURL, req.Visibility, req.Status, currentAdminOperator(c)); err != nil {
+		writeNewsArticleError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, dto.OK(struct{}{}))
@@ -458,6 +534,34 @@ func (h *AdminGrowthHandler) UploadNewsAttachment(c *gin.Context) {
 		return
 	}
 
+	// Covers are shown in public article lists and keep a public URL;
+	// everything else is a private, content-addressed attachment object.
+	if !strings.EqualFold(strings.TrimSpace(c.PostForm("purpose")), "cover") {
+		h.storeNewsAttachmentObject(c, ossCfg, originalName, ext, mimeType, payload)
+		return
+	}
+	// Only images are served from the public upload directory.
+	if !strings.HasPrefix(strings.ToLower(mimeType), "image/") {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "cover must be an image", Data: struct{}{}})
+		return
+	}
+
+	if ossCfg.Enabled && strings.EqualFold(strings.TrimSpace(ossCfg.Provider), objectstore.ProviderS3) {
+		store := newS3AttachmentStore(ossCfg)
+		objectKey := joinObjectPath(ossCfg.S3PathPrefix, "news", "covers", dateDir, targetName)
+		if err := store.Put(c.Request.Context(), objectKey, payload, mimeType); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusOK, dto.OK(gin.H{
+			"file_name": originalName,
+			"file_url":  store.PublicURL(objectKey),
+			"file_size": written,
+			"mime_type": mimeType,
+		}))
+		return
+	}
+
 	if ossCfg.Enabled && strings.EqualFold(strings.TrimSpace(ossCfg.Provider), "QINIU") {
 		objectKey := joinObjectPath(ossCfg.PathPrefix, "news", dateDir, targetName)
 		fileURL, uploadErr := h.uploadToQiniu(ossCfg, objectKey, payload, mimeType)
@@ -529,6 +633,20 @@ func (h *AdminGrowthHandler) ListNewsAttachments(c *gin.Context) {
 
 func (h *AdminGrowthHandler) DeleteNewsAttachment(c *gin.Context) {
 	id := c.Param("id")
+	item, err := h.service.AdminGetNewsAttachment(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40402, Message: "attachment not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	downloaderIDs, err := h.service.AdminListAttachmentDownloaderIDs(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
 	if err := h.service.AdminDeleteNewsAttachment(id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40402, Message: "attachment not found", Data: struct{}{}})
@@ -537,7 +655,10 @@ func (h *AdminGrowthHandler) DeleteNewsAttachment(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	h.writeOperationLog(c, "NEWS", "DELETE_ATTACHMENT", "NEWS_ATTACHMENT", id, "", "DELETED", "")
+	// The row is gone either way; a leftover object only costs storage and
+	// stays unreachable without a row pointing at it.
+	_ = h.deleteAttachmentObjects(c.Request.Context(), id, item.FileURL, downloaderIDs)
+	h.writeOperationLog(c, "NEWS", "DELETE_ATTACHMENT", "NEWS_ATTACHMENT", id, item.FileURL, "DELETED", "")
 	c.JSON(http.StatusOK, dto.OK(struct{}{}))
 }
 
@@ -588,15 +709,24 @@ func randomHex(n int) string {
 }
 
 func (h *AdminGrowthHandler) resolveOSSUploadConfig() ossUploadConfig {
+	return loadOSSUploadConfig(h.service, h.configSecrets, h.cfg.AttachmentUploadMaxMB)
+}
+
+// loadOSSUploadConfig reads the oss.* system configs; user handlers need it
+// too to open the store an attachment was written to.
+func loadOSSUploadConfig(svc service.GrowthService, configSecrets *secrets.Keyring, defaultMaxMB int) ossUploadConfig {
 	cfg := ossUploadConfig{
-		Provider:    "QINIU",
-		Enabled:     false,
-		Region:      "z0",
-		PathPrefix:  "uploads/",
-		UseHTTPS:    true,
-		MaxUploadMB: h.cfg.AttachmentUploadMaxMB,
-	}
-	items, _, err := h.service.AdminListSystemConfigs("oss.", 1, 300)
+		Provider:     "QINIU",
+		Enabled:      false,
+		Region:       "z0",
+		PathPrefix:   "uploads/",
+		UseHTTPS:     true,
+		MaxUploadMB:  defaultMaxMB,
+		S3Region:     "us-east-1",
+		S3PathStyle:  true,
+		S3PathPrefix: "uploads/",
+	}
+	items, _, err := svc.AdminListSystemConfigs("oss.", 1, 300)
 	if err != nil {
 		if cfg.MaxUploadMB <= 0 {
 			cfg.MaxUploadMB = 20
@@ -614,9 +744,9 @@ func (h *AdminGrowthHandler) resolveOSSUploadConfig() ossUploadConfig {
 		case ossEnabledConfigKey:
 			cfg.Enabled = parseConfigBool(value, cfg.Enabled)
 		case ossQiniuAccessKeyConfigKey:
-			cfg.AccessKey = value
+			cfg.AccessKey = openSensitiveSystemConfigValue(configSecrets, key, value)
 		case ossQiniuSecretKeyConfigKey:
-			cfg.SecretKey = value
+			cfg.SecretKey = openSensitiveSystemConfigValue(configSecrets, key, value)
 		case ossQiniuBucketConfigKey:
 			cfg.Bucket = value
 		case ossQiniuDomainConfigKey:
@@ -631,6 +761,22 @@ func (h *AdminGrowthHandler) resolveOSSUploadConfig() ossUploadConfig {
 			cfg.UseHTTPS = parseConfigBool(value, cfg.UseHTTPS)
 		case ossUploadMaxSizeMBConfigKey:
 			cfg.MaxUploadMB = parseConfigInt(value, cfg.MaxUploadMB)
+		case ossS3EndpointConfigKey:
+			cfg.S3Endpoint = value
+		case ossS3RegionConfigKey:
+			if value != "" {
+				cfg.S3Region = value
+			}
+		case ossS3BucketConfigKey:
+			cfg.S3Bucket = value
+		case ossS3AccessKeyConfigKey:
+			cfg.S3AccessKey = openSensitiveSystemConfigValue(configSecrets, key, value)
+		case ossS3SecretKeyConfigKey:
+			cfg.S3SecretKey = openSensitiveSystemConfigValue(configSecrets, key, value)
+		case ossS3PathStyleConfigKey:
+			cfg.S3PathStyle = parseConfigBool(value, cfg.S3PathStyle)
+		case ossS3PathPrefixConfigKey:
+			cfg.S3PathPrefix = value
 		}
 	}
 	if cfg.MaxUploadMB <= 0 {
@@ -672,7 +818,11 @@ func (h *AdminGrowthHandler) resolveYolkPayConfig() (yolkPayRuntimeConfig, error
 		case paymentChannelYolkPayPIDConfigKey:
 			cfg.PID = value
 		case paymentChannelYolkPayKeyConfigKey:
-			cfg.Key = value
+			key, err := h.configSecrets.Open(paymentChannelYolkPayKeyConfigKey, value)
+			if err != nil {
+				return cfg, fmt.Errorf("open %s: %w", paymentChannelYolkPayKeyConfigKey, err)
+			}
+			cfg.Key = strings.TrimSpace(key)
 		case paymentChannelYolkPayGatewayConfigKey:
 			if value != "" {
 				cfg.Gateway = value
@@ -714,131 +864,12 @@ func joinObjectPath(parts ...string) string {
 	return strings.Join(normalized, "/")
 }
 
-func buildQiniuUploadHost(region string, useHTTPS bool) string {
-	regionHost := map[string]string{
-		"z0":  "up-z0.qiniup.com",
-		"z1":  "up-z1.qiniup.com",
-		"z2":  "up-z2.qiniup.com",
-		"na0": "up-na0.qiniup.com",
-		"as0": "up-as0.qiniup.com",
-	}
-	host, ok := regionHost[strings.ToLower(strings.TrimSpace(region))]
-	if !ok || host == "" {
-		host = "up-z0.qiniup.com"
-	}
-	scheme := "https://"
-	if !useHTTPS {
-		scheme = "http://"
-	}
-	return scheme + host
-}
-
-func buildQiniuPublicURL(cfg ossUploadConfig, objectKey string) string {
-	domain := strings.TrimSpace(cfg.Domain)
-	if domain == "" {
-		return objectKey
-	}
-	if strings.HasPrefix(strings.ToLower(domain), "http://") || strings.HasPrefix(strings.ToLower(domain), "https://") {
-		return strings.TrimRight(domain, "/") + "/" + strings.TrimLeft(objectKey, "/")
-	}
-	scheme := "https://"
-	if !cfg.UseHTTPS {
-		scheme = "http://"
-	}
-	return scheme + strings.TrimRight(domain, "/") + "/" + strings.TrimLeft(objectKey, "/")
-}
-
-func buildQiniuUploadToken(accessKey string, secretKey string, bucket string, objectKey string) (string, error) {
-	ak := strings.TrimSpace(accessKey)
-	sk := strings.TrimSpace(secretKey)
-	bk := strings.TrimSpace(bucket)
-	key := strings.TrimSpace(objectKey)
-	if ak == "" || sk == "" || bk == "" || key == "" {
-		return "", errors.New("qiniu credential or object key missing")
-	}
-	policy := map[string]interface{}{
-		"scope":    bk + ":" + key,
-		"deadline": time.Now().Add(1 * time.Hour).Unix(),
-	}
-	policyJSON, err := json.Marshal(policy)
-	if err != nil {
-		return "", err
-	}
-	encodedPolicy := base64.URLEncoding.EncodeToString(policyJSON)
-	mac := hmac.New(sha1.New, []byte(sk))
-	if _, err := mac.Write([]byte(encodedPolicy)); err != nil {
-		return "", err
-	}
-	sign := base64.URLEncoding.EncodeToString(mac.Sum(nil))
-	return ak + ":" + sign + ":" + encodedPolicy, nil
-}
-
 func (h *AdminGrowthHandler) uploadToQiniu(cfg ossUploadConfig, objectKey string, payload []byte, mimeType string) (string, error) {
-	if strings.TrimSpace(cfg.AccessKey) == "" || strings.TrimSpace(cfg.SecretKey) == "" ||
-		strings.TrimSpace(cfg.Bucket) == "" || strings.TrimSpace(cfg.Domain) == "" {
-		return "", errors.New("oss qiniu config incomplete, require access_key/secret_key/bucket/domain")
-	}
-	if len(payload) == 0 {
-		return "", errors.New("empty upload payload")
-	}
-	token, err := buildQiniuUploadToken(cfg.AccessKey, cfg.SecretKey, cfg.Bucket, objectKey)
-	if err != nil {
-		return "", err
-	}
-
-	var body bytes.Buffer
-	writer := multipart.NewWriter(&body)
-	if err := writer.WriteField("token", token); err != nil {
-		return "", err
-	}
-	if err := writer.WriteField("key", objectKey); err != nil {
-		return "", err
-	}
-	part, err := writer.CreateFormFile("file", filepath.Base(objectKey))
-	if err != nil {
+	store := newQiniuAttachmentStore(cfg)
+	if err := store.Put(context.Background(), objectKey, payload, mimeType); err != nil {
 		return "", err
 	}
-	if _, err := part.Write(payload); err != nil {
-		return "", err
-	}
-	if err := writer.Close(); err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, buildQiniuUploadHost(cfg.Region, cfg.UseHTTPS), &body)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if strings.TrimSpace(mimeType) != "" {
-		req.Header.Set("X-Qiniu-MimeType", mimeType)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	respBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 16*1024))
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		msg := strings.TrimSpace(string(respBytes))
-		if msg == "" {
-			msg = resp.Status
-		}
-		return "", fmt.Errorf("qiniu upload failed: %s", msg)
-	}
-	if len(respBytes) > 0 {
-		var result map[string]interface{}
-		if json.Unmarshal(respBytes, &result) == nil {
-			if errText, ok := result["error"].(string); ok && strings.TrimSpace(errText) != "" {
-				return "", fmt.Errorf("qiniu upload failed: %s", strings.TrimSpace(errText))
-			}
-			if key, ok := result["key"].(string); ok && strings.TrimSpace(key) != "" {
-				objectKey = strings.TrimSpace(key)
-			}
-		}
-	}
-	return buildQiniuPublicURL(cfg, objectKey), nil
+	return store.PublicURL(objectKey), nil
 }
 
 func normalizeAdminDateTime(value string) (string, error) {
@@ -899,8 +930,14 @@ func normalizeStockQuoteSyncMode(raw string, symbols []string) string {
 }
 
 func parseQuantEvaluationQuery(c *gin.Context) (int, int) {
+	return quantEvaluationWindow(c.Query("days"), c.Query("top_n"))
+}
+
+// quantEvaluationWindow turns the days and top_n filters into a window of
+// 20 to 365 days and a top 1 to 30, defaulting to 60 and 10.
+func quantEvaluationWindow(days string, top string) (int, int) {
 	windowDays := 60
-	if parsed, err := strconv.Atoi(strings.TrimSpace(c.DefaultQuery("days", "60"))); err == nil && parsed > 0 {
+	if parsed, err := strconv.Atoi(strings.TrimSpace(days)); err == nil && parsed > 0 {
 		windowDays = parsed
 	}
 	if windowDays < 20 {
@@ -910,7 +947,7 @@ func parseQuantEvaluationQuery(c *gin.Context) (int, int) {
 		windowDays = 365
 	}
 	topN := 10
-	if parsed, err := strconv.Atoi(strings.TrimSpace(c.DefaultQuery("top_n", "10"))); err == nil && parsed > 0 {
+	if parsed, err := strconv.Atoi(strings.TrimSpace(top)); err == nil && parsed > 0 {
 		topN = parsed
 	}
 	if topN < 1 {
@@ -1165,31 +1202,30 @@ func (h *AdminGrowthHandler) ListQuantEvaluation(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportQuantEvaluationCSV(c *gin.Context) {
-	windowDays, topN := parseQuantEvaluationQuery(c)
-	summary, points, riskItems, rotationItems, err := h.service.AdminGetQuantEvaluation(windowDays, topN)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"section", "field", "value"})
-	_ = writer.Write([]string{"summary", "window_days", strconv.Itoa(summary.WindowDays)})
-	_ = writer.Write([]string{"summary", "top_n", strconv.Itoa(summary.TopN)})
-	_ = writer.Write([]string{"summary", "sample_days", strconv.Itoa(summary.SampleDays)})
-	_ = writer.Write([]string{"summary", "sample_count", strconv.Itoa(summary.SampleCount)})
-	_ = writer.Write([]string{"summary", "avg_return_5", fmt.Sprintf("%.6f", summary.AvgReturn5)})
-	_ = writer.Write([]string{"summary", "hit_rate_5", fmt.Sprintf("%.6f", summary.HitRate5)})
-	_ = writer.Write([]string{"summary", "max_drawdown_5", fmt.Sprintf("%.6f", summary.MaxDrawdown5)})
-	_ = writer.Write([]string{"summary", "avg_return_10", fmt.Sprintf("%.6f", summary.AvgReturn10)})
-	_ = writer.Write([]string{"summary", "hit_rate_10", fmt.Sprintf("%.6f", summary.HitRate10)})
-	_ = writer.Write([]string{"summary", "max_drawdown_10", fmt.Sprintf("%.6f", summary.MaxDrawdown10)})
-	_ = writer.Write([]string{"summary", "benchmark_avg_return_5", fmt.Sprintf("%.6f", summary.BenchmarkAvgReturn5)})
-	_ = writer.Write([]string{"summary", "benchmark_avg_return_10", fmt.Sprintf("%.6f", summary.BenchmarkAvgReturn10)})
-	_ = writer.Write([]string{"summary", "generated_at", summary.GeneratedAt})
-	_ = writer.Write([]string{})
-
-	_ = writer.Write([]string{
+	h.serveExportCSV(c, model.ExportTypeQuantEvaluation)
+}
+
+// quantEvaluationRows lays the evaluation out below a section/field/value
+// header: the summary fields, then the daily points, risk buckets and
+// rotations, each under its own header row and separated by blank rows.
+func quantEvaluationRows(summary model.StockQuantEvaluationSummary, points []model.StockQuantEvaluationPoint, riskItems []model.StockQuantRiskPerformance, rotationItems []model.StockQuantRotationPoint) [][]string {
+	rows := make([][]string, 0, 20+len(points)+len(riskItems)+len(rotationItems))
+	rows = append(rows, []string{"summary", "window_days", strconv.Itoa(summary.WindowDays)})
+	rows = append(rows, []string{"summary", "top_n", strconv.Itoa(summary.TopN)})
+	rows = append(rows, []string{"summary", "sample_days", strconv.Itoa(summary.SampleDays)})
+	rows = append(rows, []string{"summary", "sample_count", strconv.Itoa(summary.SampleCount)})
+	rows = append(rows, []string{"summary", "avg_return_5", fmt.Sprintf("%.6f", summary.AvgReturn5)})
+	rows = append(rows, []string{"summary", "hit_rate_5", fmt.Sprintf("%.6f", summary.HitRate5)})
+	rows = append(rows, []string{"summary", "max_drawdown_5", fmt.Sprintf("%.6f", summary.MaxDrawdown5)})
+	rows = append(rows, []string{"summary", "avg_return_10", fmt.Sprintf("%.6f", summary.AvgReturn10)})
+	rows = append(rows, []string{"summary", "hit_rate_10", fmt.Sprintf("%.6f", summary.HitRate10)})
+	rows = append(rows, []string{"summary", "max_drawdown_10", fmt.Sprintf("%.6f", summary.MaxDrawdown10)})
+	rows = append(rows, []string{"summary", "benchmark_avg_return_5", fmt.Sprintf("%.6f", summary.BenchmarkAvgReturn5)})
+	rows = append(rows, []string{"summary", "benchmark_avg_return_10", fmt.Sprintf("%.6f", summary.BenchmarkAvgReturn10)})
+	rows = append(rows, []string{"summary", "generated_at", summary.GeneratedAt})
+	rows = append(rows, []string{})
+
+	rows = append(rows, []string{
 		"points_trade_date",
 		"sample_count",
 		"avg_return_5",
@@ -1206,7 +1242,7 @@ func (h *AdminGrowthHandler) ExportQuantEvaluationCSV(c *gin.Context) {
 		"cumulative_excess_10",
 	})
 	for _, item := range points {
-		_ = writer.Write([]string{
+		rows = append(rows, []string{
 			item.TradeDate,
 			strconv.Itoa(item.SampleCount),
 			fmt.Sprintf("%.6f", item.AvgReturn5),
@@ -1223,11 +1259,11 @@ func (h *AdminGrowthHandler) ExportQuantEvaluationCSV(c *gin.Context) {
 			fmt.Sprintf("%.6f", item.CumulativeExcess10),
 		})
 	}
-	_ = writer.Write([]string{})
+	rows = append(rows, []string{})
 
-	_ = writer.Write([]string{"risk_level", "sample_count", "avg_return_5", "hit_rate_5", "avg_return_10", "hit_rate_10"})
+	rows = append(rows, []string{"risk_level", "sample_count", "avg_return_5", "hit_rate_5", "avg_return_10", "hit_rate_10"})
 	for _, item := range riskItems {
-		_ = writer.Write([]string{
+		rows = append(rows, []string{
 			item.RiskLevel,
 			strconv.Itoa(item.SampleCount),
 			fmt.Sprintf("%.6f", item.AvgReturn5),
@@ -1236,11 +1272,11 @@ func (h *AdminGrowthHandler) ExportQuantEvaluationCSV(c *gin.Context) {
 			fmt.Sprintf("%.6f", item.HitRate10),
 		})
 	}
-	_ = writer.Write([]string{})
+	rows = append(rows, []string{})
 
-	_ = writer.Write([]string{"rotation_trade_date", "top_symbols", "entered", "exited", "stayed_count", "changed_count"})
+	rows = append(rows, []string{"rotation_trade_date", "top_symbols", "entered", "exited", "stayed_count", "changed_count"})
 	for _, item := range rotationItems {
-		_ = writer.Write([]string{
+		rows = append(rows, []string{
 			item.TradeDate,
 			strings.Join(item.TopSymbols, "|"),
 			strings.Join(item.Entered, "|"),
@@ -1250,16 +1286,7 @@ func (h *AdminGrowthHandler) ExportQuantEvaluationCSV(c *gin.Context) {
 		})
 	}
 
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-
-	fileName := fmt.Sprintf("stock_quant_evaluation_%dd_top%d.csv", windowDays, topN)
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename="+fileName)
-	c.String(http.StatusOK, buf.String())
+	return rows
 }
 
 func (h *AdminGrowthHandler) GenerateDailyStockRecommendations(c *gin.Context) {
@@ -1366,6 +1393,36 @@ func (h *AdminGrowthHandler) GenerateDailyFuturesStrategies(c *gin.Context) {
 	}))
 }
 
+func (h *AdminGrowthHandler) RunFuturesArbitrageEngine(c *gin.Context) {
+	tradeDate := strings.TrimSpace(c.Query("trade_date"))
+	if tradeDate != "" {
+		if _, err := time.Parse("2006-01-02", tradeDate); err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid trade_date", Data: struct{}{}})
+			return
+		}
+	}
+	result, err := h.service.AdminRunFuturesArbitrageEngine(tradeDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "FUTURES", "COMPUTE_ARBITRAGE", "BATCH", result.TradeDate, "", formatFuturesArbitrageEngineSummary(result), "")
+	c.JSON(http.StatusOK, dto.OK(result))
+}
+
+func formatFuturesArbitrageEngineSummary(result model.FuturesArbitrageEngineResult) string {
+	return fmt.Sprintf(
+		"trade_date=%s pairs=%d computed=%d skipped=%d open=%d exit=%d expired=%d",
+		result.TradeDate,
+		result.PairCount,
+		result.Computed,
+		result.Skipped,
+		result.OpenSignals,
+		result.ExitSignals,
+		result.Expired,
+	)
+}
+
 func (h *AdminGrowthHandler) ListStrategyEngineFuturesPublishHistory(c *gin.Context) {
 	items, err := h.service.AdminListStrategyEnginePublishHistory("futures-strategy")
 	if err != nil {
@@ -1643,6 +1700,7 @@ func (h *AdminGrowthHandler) ListUsers(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	maskAdminUsers(items)
 	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
 }
 
@@ -1668,6 +1726,7 @@ func (h *AdminGrowthHandler) ListBrowseHistories(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	maskBrowseHistories(items)
 	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
 }
 
@@ -1706,35 +1765,12 @@ func (h *AdminGrowthHandler) ListBrowseUserSegments(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	maskBrowseUserSegments(items)
 	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items}))
 }
 
 func (h *AdminGrowthHandler) ExportBrowseHistoriesCSV(c *gin.Context) {
-	userID := strings.TrimSpace(c.Query("user_id"))
-	contentType := strings.ToUpper(strings.TrimSpace(c.Query("content_type")))
-	keyword := strings.TrimSpace(c.Query("keyword"))
-
-	items, _, err := h.service.AdminListBrowseHistories(userID, contentType, keyword, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "user_id", "user_phone", "content_type", "content_id", "title", "source_page", "viewed_at"})
-	for _, it := range items {
-		_ = writer.Write([]string{it.ID, it.UserID, it.UserPhone, it.ContentType, it.ContentID, it.Title, it.SourcePage, it.ViewedAt})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=browse_histories.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeBrowseHistories)
 }
 
 func (h *AdminGrowthHandler) ListUserMessages(c *gin.Context) {
@@ -1747,6 +1783,7 @@ func (h *AdminGrowthHandler) ListUserMessages(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	maskUserMessages(items)
 	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
 }
 
@@ -1901,7 +1938,7 @@ func (h *AdminGrowthHandler) GetUserCenterOverview(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dto.OK(gin.H{
-		"user_profile":     profile,
+		"user_profile":     maskUserProfile(profile),
 		"membership_quota": quota,
 		"payment_summary": gin.H{
 			"paid_order_count":    paidOrderCount,
@@ -1985,40 +2022,7 @@ func (h *AdminGrowthHandler) UpdateUserSubscription(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportUsersCSV(c *gin.Context) {
-	status := c.Query("status")
-	memberLevel := c.Query("member_level")
-	registrationSource := c.Query("registration_source")
-	items, _, err := h.service.AdminListUsers(status, memberLevel, registrationSource, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "phone", "email", "status", "member_level", "registration_source", "inviter_user_id", "invite_code", "invite_registered_at", "created_at"})
-	for _, it := range items {
-		_ = writer.Write([]string{
-			it.ID,
-			it.Phone,
-			it.Email,
-			it.Status,
-			it.MemberLevel,
-			it.RegistrationSource,
-			it.InviterUserID,
-			it.InviteCode,
-			it.InviteRegisteredAt,
-			it.CreatedAt,
-		})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=admin_users.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeUsers)
 }
 
 func (h *AdminGrowthHandler) UpdateUserStatus(c *gin.Context) {
@@ -2028,7 +2032,9 @@ func (h *AdminGrowthHandler) UpdateUserStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	if err := h.service.AdminUpdateUserStatus(id, req.Status); err != nil {
+	operatorVal, _ := c.Get("user_id")
+	operator, _ := operatorVal.(string)
+	if err := h.service.AdminUpdateUserStatus(id, req.Status, operator); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
@@ -2074,7 +2080,9 @@ func (h *AdminGrowthHandler) ResetUserPassword(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	if err := h.service.AdminResetUserPasswordHash(id, passwordHash); err != nil {
+	operatorVal, _ := c.Get("user_id")
+	operator, _ := operatorVal.(string)
+	if err := h.service.AdminResetUserPasswordHash(id, passwordHash, operator); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "user not found", Data: struct{}{}})
 			return
@@ -2138,28 +2146,12 @@ func (h *AdminGrowthHandler) GetAuditEventSummary(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportOperationLogsCSV(c *gin.Context) {
-	module := c.Query("module")
-	action := c.Query("action")
-	operator := c.Query("operator_user_id")
-	items, _, err := h.service.AdminListOperationLogs(module, action, operator, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "module", "action", "target_type", "target_id", "operator_user_id", "before_value", "after_value", "reason", "created_at"})
-	for _, it := range items {
-		_ = writer.Write([]string{it.ID, it.Module, it.Action, it.TargetType, it.TargetID, it.OperatorUserID, it.BeforeValue, it.AfterValue, it.Reason, it.CreatedAt})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=admin_operation_logs.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeOperationLogs)
+}
+
+// ExportLoginLogsCSV exports auth_login_logs with phones and IPs masked.
+func (h *AdminGrowthHandler) ExportLoginLogsCSV(c *gin.Context) {
+	h.serveExportCSV(c, model.ExportTypeLoginLogs)
 }
 
 func (h *AdminGrowthHandler) ListMembershipProducts(c *gin.Context) {
@@ -2239,31 +2231,7 @@ func (h *AdminGrowthHandler) ListMembershipOrders(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportMembershipOrdersCSV(c *gin.Context) {
-	status := c.Query("status")
-	userID := c.Query("user_id")
-	items, _, err := h.service.AdminListMembershipOrders(status, userID, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "user_id", "product_id", "amount", "status", "paid_at", "created_at"})
-	for _, it := range items {
-		_ = writer.Write([]string{
-			it.ID, it.UserID, it.ProductID,
-			strconv.FormatFloat(it.Amount, 'f', -1, 64),
-			it.Status, it.PaidAt, it.CreatedAt,
-		})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=membership_orders.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeMembershipOrders)
 }
 
 func (h *AdminGrowthHandler) UpdateMembershipOrderStatus(c *gin.Context) {
@@ -2273,8 +2241,19 @@ func (h *AdminGrowthHandler) UpdateMembershipOrderStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	if strings.EqualFold(req.Status, "REFUNDED") {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "use POST /admin/membership/orders/:id/refund to refund an order", Data: struct{}{}})
+		return
+	}
 	if err := h.service.AdminUpdateMembershipOrderStatus(id, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "membership order not found", Data: struct{}{}})
+		case errors.Is(err, model.ErrMembershipOrderPaid):
+			c.JSON(http.StatusConflict, dto.APIResponse{Code: 40903, Message: err.Error(), Data: struct{}{}})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		}
 		return
 	}
 	h.writeOperationLog(c, "MEMBERSHIP", "UPDATE_ORDER_STATUS", "MEMBERSHIP_ORDER", id, "", req.Status, "")
@@ -2537,6 +2516,27 @@ func (h *AdminGrowthHandler) UpsertSystemConfig(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	if isPaymentConfigKey(req.ConfigKey) {
+		// Seal before parking the change so a pending request never holds a
+		// plaintext credential.
+		payload := req
+		if isSensitiveSystemConfigKey(req.ConfigKey) {
+			sealed, err := h.configSecrets.Seal(req.ConfigKey, req.ConfigValue)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+				return
+			}
+			payload.ConfigValue = sealed
+		}
+		if submitForApproval(c, h.service, model.ApprovalRequest{
+			Module:   model.ApprovalModulePaymentConfig,
+			Action:   model.ApprovalActionPaymentConfigUpsert,
+			TargetID: req.ConfigKey,
+			Summary:  fmt.Sprintf("修改支付配置 %s = %s", req.ConfigKey, maskSystemConfigValueForAudit(req.ConfigKey, req.ConfigValue)),
+		}, payload) {
+			return
+		}
+	}
 	operatorVal, _ := c.Get("user_id")
 	operator, _ := operatorVal.(string)
 	if err := h.service.AdminUpsertSystemConfig(req.ConfigKey, req.ConfigValue, req.Description, operator); err != nil {
@@ -2556,6 +2556,30 @@ func (h *AdminGrowthHandler) UpsertSystemConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.OK(struct{}{}))
 }
 
+// RotateConfigSecrets re-seals every sensitive config value under the primary
+// master key. Run it after prepending a new key to CONFIG_MASTER_KEYS; the old
+// key can be dropped once the result reports no failures.
+func (h *AdminGrowthHandler) RotateConfigSecrets(c *gin.Context) {
+	operatorVal, _ := c.Get("user_id")
+	operator, _ := operatorVal.(string)
+	result, err := h.service.AdminRotateConfigSecrets(operator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(
+		c,
+		"SYSTEM",
+		"ROTATE_CONFIG_SECRETS",
+		"SYSTEM_CONFIG",
+		result.PrimaryKeyID,
+		"",
+		fmt.Sprintf("scanned=%d sealed=%d rotated=%d failed=%d", result.Scanned, result.Sealed, result.Rotated, result.Failed),
+		"",
+	)
+	c.JSON(http.StatusOK, dto.OK(result))
+}
+
 func (h *AdminGrowthHandler) TestOSSQiniuConfig(c *gin.Context) {
 	cfg := h.resolveOSSUploadConfig()
 	if !strings.EqualFold(strings.TrimSpace(cfg.Provider), "QINIU") {
@@ -2678,29 +2702,7 @@ func (h *AdminGrowthHandler) ListReviewTasks(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportReviewTasksCSV(c *gin.Context) {
-	module := c.Query("module")
-	status := c.Query("status")
-	submitterID := c.Query("submitter_id")
-	reviewerID := c.Query("reviewer_id")
-	items, _, err := h.service.AdminListReviewTasks(module, status, submitterID, reviewerID, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "module", "target_id", "submitter_id", "reviewer_id", "status", "submit_note", "review_note", "submitted_at", "reviewed_at"})
-	for _, it := range items {
-		_ = writer.Write([]string{it.ID, it.Module, it.TargetID, it.SubmitterID, it.ReviewerID, it.Status, it.SubmitNote, it.ReviewNote, it.SubmittedAt, it.ReviewedAt})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=review_tasks.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeReviewTasks)
 }
 
 func (h *AdminGrowthHandler) WorkflowMetrics(c *gin.Context) {
@@ -2726,7 +2728,7 @@ func (h *AdminGrowthHandler) SubmitReviewTask(c *gin.Context) {
 	}
 	operatorVal, _ := c.Get("user_id")
 	operator, _ := operatorVal.(string)
-	id, err := h.service.AdminSubmitReviewTask(req.Module, req.TargetID, operator, req.ReviewerID, req.SubmitNote)
+	id, err := h.service.AdminSubmitReviewTask(req.Module, req.TargetID, operator, req.ReviewerID, req.SubmitNote, req.Priority)
 	if err != nil {
 		status, code := resolveWorkflowReviewHTTPError(err)
 		c.JSON(status, dto.APIResponse{Code: code, Message: err.Error(), Data: struct{}{}})
@@ -2802,27 +2804,7 @@ func (h *AdminGrowthHandler) ListNewsSyncRunDetails(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportSchedulerJobRunsCSV(c *gin.Context) {
-	jobName := c.Query("job_name")
-	status := c.Query("status")
-	items, _, err := h.service.AdminListSchedulerJobRuns(jobName, status, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "parent_run_id", "retry_count", "job_name", "trigger_source", "status", "started_at", "finished_at", "result_summary", "error_message", "operator_id"})
-	for _, it := range items {
-		_ = writer.Write([]string{it.ID, it.ParentRunID, strconv.Itoa(it.RetryCount), it.JobName, it.TriggerSource, it.Status, it.StartedAt, it.FinishedAt, it.ResultSummary, it.ErrorMessage, it.OperatorID})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=scheduler_job_runs.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeSchedulerJobRuns)
 }
 
 func (h *AdminGrowthHandler) SchedulerJobMetrics(c *gin.Context) {
@@ -2955,6 +2937,16 @@ func (h *AdminGrowthHandler) TriggerSchedulerJob(c *gin.Context) {
 		c.JSON(http.StatusOK, dto.OK(gin.H{"id": id, "status": simulateStatus}))
 		return
 	}
+	blockedReason, err := h.schedulerJobPipelineBlocker(req.JobName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if blockedReason != "" {
+		h.writeOperationLog(c, "SCHEDULER", "TRIGGER_JOB", "JOB", req.JobName, "", model.SchedulerPipelineNodeBlocked, blockedReason)
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40908, Message: blockedReason, Data: struct{}{}})
+		return
+	}
 	syncOptions := buildTushareNewsSyncOptions(req.NewsSources, req.Symbols, req.SyncTypes, req.BatchSize)
 	execResult, err := h.runSchedulerJob(req.JobName, syncOptions)
 	status := "SUCCESS"
@@ -3029,6 +3021,16 @@ func (h *AdminGrowthHandler) RetrySchedulerJobRun(c *gin.Context) {
 		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "job run not found", Data: struct{}{}})
 		return
 	}
+	blockedReason, err := h.schedulerJobPipelineBlocker(jobName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if blockedReason != "" {
+		h.writeOperationLog(c, "SCHEDULER", "RETRY_JOB", "JOB_RUN", runID, "", model.SchedulerPipelineNodeBlocked, blockedReason)
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40908, Message: blockedReason, Data: struct{}{}})
+		return
+	}
 	syncOptions := buildTushareNewsSyncOptions(req.NewsSources, req.Symbols, req.SyncTypes, req.BatchSize)
 	execResult, runErr := h.runSchedulerJob(jobName, syncOptions)
 	status := "SUCCESS"
@@ -3167,37 +3169,7 @@ func (h *AdminGrowthHandler) ListWorkflowMessages(c *gin.Context) {
 }
 
 func (h *AdminGrowthHandler) ExportWorkflowMessagesCSV(c *gin.Context) {
-	module := c.Query("module")
-	eventType := c.Query("event_type")
-	isRead := c.Query("is_read")
-	receiverID := strings.TrimSpace(c.Query("receiver_id"))
-	if receiverID == "" {
-		operatorVal, _ := c.Get("user_id")
-		receiverID, _ = operatorVal.(string)
-	}
-	items, _, err := h.service.AdminListWorkflowMessages(module, eventType, isRead, receiverID, 1, 10000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "review_id", "target_id", "module", "receiver_id", "sender_id", "event_type", "title", "content", "is_read", "created_at", "read_at"})
-	for _, it := range items {
-		isReadVal := "false"
-		if it.IsRead {
-			isReadVal = "true"
-		}
-		_ = writer.Write([]string{it.ID, it.ReviewID, it.TargetID, it.Module, it.ReceiverID, it.SenderID, it.EventType, it.Title, it.Content, isReadVal, it.CreatedAt, it.ReadAt})
-	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename=workflow_messages.csv")
-	c.String(http.StatusOK, buf.String())
+	h.serveExportCSV(c, model.ExportTypeWorkflowMessages)
 }
 
 func (h *AdminGrowthHandler) UpdateWorkflowMessageRead(c *gin.Context) {
@@ -3297,22 +3269,17 @@ func buildTushareNewsSyncOptions(newsSources []string, symbols []string, syncTyp
 }
 
 func isSensitiveSystemConfigKey(rawKey string) bool {
-	key := strings.ToLower(strings.TrimSpace(rawKey))
-	if key == "" {
-		return false
-	}
-	if strings.Contains(key, "source_key") || strings.Contains(key, "policy_key") {
-		return false
-	}
-	if strings.Contains(key, "secret") ||
-		strings.Contains(key, "token") ||
-		strings.Contains(key, "password") ||
-		strings.Contains(key, "private_key") ||
-		strings.Contains(key, "access_key") ||
-		strings.Contains(key, "api_v3_key") {
-		return true
+	return secrets.IsSensitiveConfigKey(rawKey)
+}
+
+// openSensitiveSystemConfigValue decrypts a sealed config value at its point of
+// use. A value that cannot be opened is treated as unset.
+func openSensitiveSystemConfigValue(keyring *secrets.Keyring, configKey string, value string) string {
+	plaintext, err := keyring.Open(configKey, value)
+	if err != nil {
+		return ""
 	}
-	return strings.HasSuffix(key, ".key") || strings.HasSuffix(key, "_key")
+	return strings.TrimSpace(plaintext)
 }
 
 func maskSensitiveSystemConfigValue(rawValue string) string {
@@ -3360,6 +3327,12 @@ func (h *AdminGrowthHandler) writeOperationLog(c *gin.Context, module string, ac
 }
 
 func (h *AdminGrowthHandler) writeAuditEvent(c *gin.Context, item model.AdminAuditEvent) {
+	_ = h.recordAuditEvent(c, item)
+}
+
+// recordAuditEvent is writeAuditEvent for callers that must not go ahead
+// without the audit trail, such as PII reveals.
+func (h *AdminGrowthHandler) recordAuditEvent(c *gin.Context, item model.AdminAuditEvent) error {
 	operatorVal, _ := c.Get("user_id")
 	operator, _ := operatorVal.(string)
 	operator = strings.TrimSpace(operator)
@@ -3369,7 +3342,7 @@ func (h *AdminGrowthHandler) writeAuditEvent(c *gin.Context, item model.AdminAud
 	if strings.TrimSpace(item.ActorUserID) == "" {
 		item.ActorUserID = operator
 	}
-	_ = h.service.AdminCreateAuditEvent(item)
+	return h.service.AdminCreateAuditEvent(item)
 }
 
 type schedulerJobExecutionResult struct {
@@ -3485,6 +3458,16 @@ func (h *AdminGrowthHandler) executeSchedulerAutoRetry(jobName string, baseRunID
 		if policy.BackoffSeconds > 0 {
 			time.Sleep(time.Duration(policy.BackoffSeconds*attempt) * time.Second)
 		}
+		// An upstream pipeline node may have failed or been rerun during the
+		// backoff; retrying a blocked node would run it on stale inputs.
+		blockedReason, blockErr := h.schedulerJobPipelineBlocker(jobName)
+		if blockErr != nil {
+			return finalRunID, finalStatus, finalSummary, finalError, retryAttempts, blockErr
+		}
+		if blockedReason != "" {
+			finalError = strings.TrimSpace(fmt.Sprintf("%s; auto retry stopped: %s", finalError, blockedReason))
+			break
+		}
 		execResult, runErr := h.runSchedulerJob(jobName, syncOptions)
 		summary := execResult.Summary
 		status := "SUCCESS"
@@ -3533,7 +3516,7 @@ func (h *AdminGrowthHandler) runSchedulerJob(jobName string, syncOptions model.T
 		if err != nil && sourceKey == "MOCK" {
 			return schedulerJobExecutionResult{}, err
 		}
-		topItems, err := h.service.AdminGetQuantTopStocks(10, 180)
+		topItems, err := h.service.AdminGetQuantTopStocksForTradeDate(tradeDate, 10, 180)
 		if err != nil {
 			return schedulerJobExecutionResult{}, err
 		}
@@ -3551,13 +3534,16 @@ func (h *AdminGrowthHandler) runSchedulerJob(jobName string, syncOptions model.T
 				recoResult.Count,
 			),
 		}, nil
-	case "daily_stock_recommendation":
+	case schedulerJobDailyStockRecommendation:
 		tradeDate := time.Now().Format("2006-01-02")
 		result, err := h.service.AdminGenerateDailyStockRecommendations(tradeDate)
 		if err != nil {
 			return schedulerJobExecutionResult{}, err
 		}
 		return schedulerJobExecutionResult{Summary: fmt.Sprintf("generated %d recommendations", result.Count)}, nil
+	case schedulerJobStockMasterSync, schedulerJobStockQuotesSync, schedulerJobStockDailyBasicSync, schedulerJobStockMoneyflowSync,
+		schedulerJobStockTruthRebuild, schedulerJobStockQuantRank, schedulerJobStockSelectionRun, schedulerJobStockSelectionPublishReview:
+		return h.runTradeDateSchedulerJob(jobName, time.Now().Format("2006-01-02"), "system")
 	case schedulerJobDailyFuturesStrategy, schedulerJobFuturesStrategyGenerate:
 		tradeDate := time.Now().Format("2006-01-02")
 		result, err := h.service.AdminGenerateDailyFuturesStrategies(tradeDate)
@@ -3573,8 +3559,20 @@ func (h *AdminGrowthHandler) runSchedulerJob(jobName string, syncOptions model.T
 			return schedulerJobExecutionResult{}, err
 		}
 		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobFuturesArbitrageCompute:
+		result, err := h.service.AdminRunFuturesArbitrageEngine("")
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: formatFuturesArbitrageEngineSummary(result)}, nil
+	case schedulerJobAuditLedgerCheckpoint:
+		item, err := h.service.AdminCreateAuditCheckpoint("system")
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: fmt.Sprintf("checkpoint=%s seq=%d", item.ID, item.Seq)}, nil
 	case "doc_fast_news_incremental":
-		summary, err := h.service.AdminSyncDocFastNewsIncremental(0)
+		summary, err := h.SyncDocFastNews(context.Background())
 		if err != nil {
 			return schedulerJobExecutionResult{}, err
 		}
@@ -3600,6 +3598,48 @@ func (h *AdminGrowthHandler) runSchedulerJob(jobName string, syncOptions model.T
 			return schedulerJobExecutionResult{}, err
 		}
 		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobInviteCommissionRelease:
+		summary, err := h.service.AdminReleaseInviteCommissions()
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobMembershipOrderPoll:
+		summary, err := h.service.AdminPollPendingMembershipOrders(h.PaymentChannels())
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobMembershipAutoRenew:
+		summary, err := h.service.AdminRunMembershipAutoRenewals(h.PaymentChannels())
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobCommunitySentiment:
+		summary, err := h.service.AdminRunCommunitySentimentIndex()
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobNewsScheduledPublish:
+		summary, err := h.service.AdminRunNewsScheduledPublish()
+		if err != nil {
+			return schedulerJobExecutionResult{Summary: summary}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobReviewSLAEscalation:
+		summary, err := h.service.AdminRunReviewSLAEscalation()
+		if err != nil {
+			return schedulerJobExecutionResult{Summary: summary}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
+	case schedulerJobExportJobs:
+		summary, err := h.RunExportJobs(context.Background())
+		if err != nil {
+			return schedulerJobExecutionResult{Summary: summary}, err
+		}
+		return schedulerJobExecutionResult{Summary: summary}, nil
 	default:
 		return schedulerJobExecutionResult{}, fmt.Errorf("unknown job: %s", jobName)
 	}
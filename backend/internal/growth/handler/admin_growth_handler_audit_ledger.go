@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+)
+
+func (h *AdminGrowthHandler) VerifyAuditLedger(c *gin.Context) {
+	result, err := h.service.AdminVerifyAuditLedger()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(result))
+}
+
+func (h *AdminGrowthHandler) ListAuditCheckpoints(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListAuditCheckpoints(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) CreateAuditCheckpoint(c *gin.Context) {
+	item, err := h.service.AdminCreateAuditCheckpoint(h.auditLedgerOperator(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "AUDIT", "CREATE_CHECKPOINT", "AUDIT_CHECKPOINT", item.ID, "", fmt.Sprintf("seq=%d hash=%s", item.Seq, item.EntryHash), "")
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// ExportAuditLedger packs the chained entries and a signed manifest into one zip
+// so auditors can recompute every hash offline with the published public key.
+func (h *AdminGrowthHandler) ExportAuditLedger(c *gin.Context) {
+	startDate := strings.TrimSpace(c.Query("start_date"))
+	endDate := strings.TrimSpace(c.Query("end_date"))
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid start_date", Data: struct{}{}})
+		return
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid end_date", Data: struct{}{}})
+		return
+	}
+	if end.Before(start) || end.Sub(start) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "export range must be within 366 days", Data: struct{}{}})
+		return
+	}
+
+	export, err := h.service.AdminExportAuditLedger(startDate, endDate, h.auditLedgerOperator(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	manifest, err := json.MarshalIndent(export.Manifest, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range []struct {
+		name string
+		body []byte
+	}{
+		{name: "entries.jsonl", body: export.EntriesJSONL},
+		{name: "manifest.json", body: manifest},
+	} {
+		writer, err := archive.Create(file.name)
+		if err == nil {
+			_, err = writer.Write(file.body)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+
+	h.writeOperationLog(c, "AUDIT", "EXPORT_LEDGER", "AUDIT_LEDGER", startDate+"~"+endDate, "", fmt.Sprintf("entries=%d last_seq=%d", export.Manifest.EntryCount, export.Manifest.LastSeq), "")
+	filename := fmt.Sprintf("audit_ledger_%s_%s.zip", strings.ReplaceAll(startDate, "-", ""), strings.ReplaceAll(endDate, "-", ""))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+func (h *AdminGrowthHandler) auditLedgerOperator(c *gin.Context) string {
+	operatorVal, _ := c.Get("user_id")
+	operator, _ := operatorVal.(string)
+	operator = strings.TrimSpace(operator)
+	if operator == "" {
+		return "admin_unknown"
+	}
+	return operator
+}
@@ -104,7 +104,7 @@ func (h *AdminGrowthHandler) ReviewCommunityReport(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	if err := h.service.AdminReviewCommunityReport(id, req.Status, req.ReviewNote); err != nil {
+	if err := h.service.AdminReviewCommunityReport(id, req.Status, currentAdminOperator(c), req.ReviewNote); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "report not found", Data: struct{}{}})
 			return
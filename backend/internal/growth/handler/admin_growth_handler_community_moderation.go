@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+func (h *AdminGrowthHandler) ListCommunitySensitiveWords(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListCommunitySensitiveWords(
+		strings.TrimSpace(c.Query("status")),
+		strings.TrimSpace(c.Query("category")),
+		strings.TrimSpace(c.Query("keyword")),
+		page,
+		pageSize,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) CreateCommunitySensitiveWord(c *gin.Context) {
+	var req dto.CommunitySensitiveWordCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if strings.TrimSpace(req.Word) == "" {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "word is required", Data: struct{}{}})
+		return
+	}
+	id, err := h.service.AdminCreateCommunitySensitiveWord(req.Word, req.Category, h.auditLedgerOperator(c))
+	if err != nil {
+		if isDuplicateEntry(err) {
+			c.JSON(http.StatusConflict, dto.APIResponse{Code: 40907, Message: "sensitive word already exists", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "COMMUNITY", "CREATE_SENSITIVE_WORD", "COMMUNITY_SENSITIVE_WORD", id, "", strings.TrimSpace(req.Word), strings.ToUpper(strings.TrimSpace(req.Category)))
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": id}))
+}
+
+func (h *AdminGrowthHandler) UpdateCommunitySensitiveWordStatus(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.CommunitySensitiveWordStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if err := h.service.AdminUpdateCommunitySensitiveWordStatus(id, req.Status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "sensitive word not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "COMMUNITY", "UPDATE_SENSITIVE_WORD_STATUS", "COMMUNITY_SENSITIVE_WORD", id, "", req.Status, "")
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+func (h *AdminGrowthHandler) ListCommunityModerationFlags(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListCommunityModerationFlags(model.CommunityModerationFlagQuery{
+		Status:     strings.TrimSpace(c.Query("status")),
+		TargetType: strings.TrimSpace(c.Query("target_type")),
+		UserID:     strings.TrimSpace(c.Query("user_id")),
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) ListCommunityUserBans(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListCommunityUserBans(strings.TrimSpace(c.Query("status")), strings.TrimSpace(c.Query("user_id")), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) CreateCommunityUserBan(c *gin.Context) {
+	var req dto.CommunityUserBanCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	id, err := h.service.AdminCreateCommunityUserBan(req.UserID, req.Days, req.Reason, h.auditLedgerOperator(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "COMMUNITY", "BAN_USER", "COMMUNITY_USER_BAN", id, "", fmt.Sprintf("%s %dd", strings.TrimSpace(req.UserID), req.Days), strings.TrimSpace(req.Reason))
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": id}))
+}
+
+func (h *AdminGrowthHandler) LiftCommunityUserBan(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.AdminLiftCommunityUserBan(id, h.auditLedgerOperator(c)); err != nil {
+		if errors.Is(err, model.ErrCommunityUserBanNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40407, Message: "active community ban not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "COMMUNITY", "LIFT_USER_BAN", "COMMUNITY_USER_BAN", id, "ACTIVE", "LIFTED", "")
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+func (h *AdminGrowthHandler) ListCommunitySentiment(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListCommunitySentiment(model.CommunitySentimentQuery{
+		AssetClass: strings.ToUpper(strings.TrimSpace(c.Query("asset_class"))),
+		Symbol:     strings.TrimSpace(c.Query("symbol")),
+		StartDate:  strings.TrimSpace(c.Query("start_date")),
+		EndDate:    strings.TrimSpace(c.Query("end_date")),
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) RebuildCommunitySentiment(c *gin.Context) {
+	var req dto.CommunitySentimentRebuildRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	statDate := strings.TrimSpace(req.StatDate)
+	if _, err := time.Parse("2006-01-02", statDate); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "stat_date must be YYYY-MM-DD", Data: struct{}{}})
+		return
+	}
+	count, err := h.service.AdminRebuildCommunitySentiment(statDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "COMMUNITY", "REBUILD_SENTIMENT", "COMMUNITY_SENTIMENT", statDate, "", fmt.Sprintf("symbols=%d", count), "")
+	c.JSON(http.StatusOK, dto.OK(gin.H{"stat_date": statDate, "symbol_count": count}))
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+)
+
+func (h *AdminGrowthHandler) ListMembershipAutoRenewals(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListMembershipAutoRenewals(c.Query("status"), strings.TrimSpace(c.Query("user_id")), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) ListMembershipRenewalAttempts(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListMembershipRenewalAttempts(strings.TrimSpace(c.Query("renewal_id")), c.Query("result"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+func (h *AdminGrowthHandler) ListMembershipCoupons(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListMembershipCoupons(c.Query("status"), c.Query("source"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) CreateMembershipCoupon(c *gin.Context) {
+	var req dto.MembershipCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if req.DiscountType == "PERCENT" && req.DiscountValue > 100 {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "percent discount must not exceed 100", Data: struct{}{}})
+		return
+	}
+	id, err := h.service.AdminCreateMembershipCoupon(model.MembershipCoupon{
+		Code:              req.Code,
+		Name:              req.Name,
+		DiscountType:      req.DiscountType,
+		DiscountValue:     req.DiscountValue,
+		MaxDiscount:       req.MaxDiscount,
+		MinAmount:         req.MinAmount,
+		ValidFrom:         strings.TrimSpace(req.ValidFrom),
+		ValidTo:           strings.TrimSpace(req.ValidTo),
+		TotalLimit:        req.TotalLimit,
+		PerUserLimit:      req.PerUserLimit,
+		ProductIDs:        req.ProductIDs,
+		MemberLevels:      req.MemberLevels,
+		FirstPurchaseOnly: req.FirstPurchaseOnly,
+		Source:            req.Source,
+		Status:            req.Status,
+		CreatedBy:         h.auditLedgerOperator(c),
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(strings.ToLower(err.Error()), "duplicate"):
+			c.JSON(http.StatusConflict, dto.APIResponse{Code: 40905, Message: "coupon code already exists", Data: struct{}{}})
+		case strings.HasPrefix(err.Error(), "invalid valid_"):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		}
+		return
+	}
+	h.writeOperationLog(c, "MEMBERSHIP", "CREATE_COUPON", "MEMBERSHIP_COUPON", id, "", fmt.Sprintf("%s %s %.2f", req.Code, req.DiscountType, req.DiscountValue), req.Name)
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": id}))
+}
+
+func (h *AdminGrowthHandler) UpdateMembershipCouponStatus(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.MembershipCouponStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if err := h.service.AdminUpdateMembershipCouponStatus(id, req.Status); err != nil {
+		if errors.Is(err, model.ErrMembershipCouponNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40405, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "MEMBERSHIP", "UPDATE_COUPON_STATUS", "MEMBERSHIP_COUPON", id, "", req.Status, "")
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+func (h *AdminGrowthHandler) ListMembershipCouponRedemptions(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListMembershipCouponRedemptions(c.Query("coupon_id"), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+func (h *AdminGrowthHandler) RefundMembershipOrder(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	var req dto.MembershipOrderRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	item, err := h.service.AdminRefundMembershipOrder(id, req.Reason, h.auditLedgerOperator(c), h.refundChannelResolver(req.Offline))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "membership order not found", Data: struct{}{}})
+		case errors.Is(err, model.ErrMembershipOrderNotRefundable):
+			c.JSON(http.StatusConflict, dto.APIResponse{Code: 40903, Message: err.Error(), Data: struct{}{}})
+		case errors.Is(err, payment.ErrRefundUnsupported):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error() + "; retry with offline=true once settled manually", Data: struct{}{}})
+		case errors.Is(err, payment.ErrRefundFailed):
+			c.JSON(http.StatusBadGateway, dto.APIResponse{Code: 50201, Message: err.Error(), Data: struct{}{}})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		}
+		return
+	}
+	h.writeOperationLog(c, "MEMBERSHIP", "REFUND_ORDER", "MEMBERSHIP_ORDER", id, "PAID", fmt.Sprintf("REFUNDED amount=%.2f channel=%s", item.Amount, item.RefundChannel), req.Reason)
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+func (h *AdminGrowthHandler) ListMembershipRefunds(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListMembershipRefunds(c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+// refundChannelResolver maps an order's pay_channel to the gateway that can
+// refund it. Channels without an API integration only refund offline.
+func (h *AdminGrowthHandler) refundChannelResolver(offline bool) payment.Resolver {
+	return payment.ResolverFunc(func(name string) (payment.Channel, error) {
+		if offline {
+			return payment.OfflineChannel{}, nil
+		}
+		switch strings.ToUpper(strings.TrimSpace(name)) {
+		case "YOLKPAY":
+			cfg, err := h.resolveYolkPayConfig()
+			if err != nil {
+				return nil, fmt.Errorf("加载蛋黄支付配置失败: %w", err)
+			}
+			return payment.NewYolkPayChannel(cfg.channelConfig()), nil
+		case "SANDBOX":
+			if h.cfg.AppEnv != "production" {
+				return payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{}), nil
+			}
+		case "OFFLINE":
+			return payment.OfflineChannel{}, nil
+		}
+		return nil, fmt.Errorf("%w: %s", payment.ErrRefundUnsupported, name)
+	})
+}
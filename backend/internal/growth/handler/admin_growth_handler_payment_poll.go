@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/platform/payment"
+)
+
+func (h *AdminGrowthHandler) ListPaymentPollLogs(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListPaymentPollLogs(strings.TrimSpace(c.Query("order_no")), c.Query("result"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+// PaymentChannels resolves the gateways the order poller queries and the
+// auto-renew job charges. Channels without an API integration report
+// payment.ErrUnsupported, which both jobs record and skip. The sandbox plays
+// out payment.channel.sandbox.outcome so dunning can be rehearsed.
+func (h *AdminGrowthHandler) PaymentChannels() payment.Resolver {
+	return payment.ResolverFunc(func(name string) (payment.Channel, error) {
+		switch strings.ToUpper(strings.TrimSpace(name)) {
+		case "YOLKPAY":
+			cfg, err := h.resolveYolkPayConfig()
+			if err != nil {
+				return nil, fmt.Errorf("加载蛋黄支付配置失败: %w", err)
+			}
+			if !cfg.Enabled {
+				return nil, errors.New("payment.channel.yolkpay.enabled 未开启")
+			}
+			return payment.NewYolkPayChannel(cfg.channelConfig()), nil
+		case "SANDBOX":
+			if h.cfg.AppEnv != "production" {
+				return payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{
+					Outcome: h.resolveDefaultConfigValue(paymentChannelSandboxOutcomeConfigKey, payment.SandboxOutcomeSuccess),
+				}), nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", payment.ErrUnsupported, name)
+	})
+}
@@ -20,6 +20,7 @@ func TestIsSensitiveSystemConfigKey(t *testing.T) {
 		{key: "payment.channel.wechat.api_v3_key", sensitive: true},
 		{key: "stock.quotes.default_source_key", sensitive: false},
 		{key: "market.stock.daily.routing_policy_key", sensitive: false},
+		{key: "growth.forecast_l3.default_engine_key", sensitive: false},
 		{key: "scheduler.auto_retry.enabled", sensitive: false},
 		{key: "oss.qiniu.domain", sensitive: false},
 	}
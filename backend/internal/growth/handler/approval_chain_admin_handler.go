@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+// ApprovalExecutor runs a guarded action from the payload stored when it was
+// submitted. It only runs after the action's approval chain completes.
+type ApprovalExecutor func(ctx context.Context, item model.ApprovalRequest) error
+
+// approvalGate is what a guarded action needs to hand itself to an
+// approval chain instead of running straight away.
+type approvalGate interface {
+	authOperationLogWriter
+	AdminMatchApprovalPolicy(module string, amount float64) (model.ApprovalPolicy, bool, error)
+	AdminCreateApprovalRequest(item model.ApprovalRequest) (model.ApprovalRequest, error)
+}
+
+// submitForApproval opens an approval chain for item when its module has an
+// active policy covering item.Amount. It returns true once it has written
+// the response (202 with the chain, or an error); false means no policy
+// applies and the caller should run the action itself.
+func submitForApproval(c *gin.Context, gate approvalGate, item model.ApprovalRequest, payload interface{}) bool {
+	if gate == nil {
+		return false
+	}
+	policy, required, err := gate.AdminMatchApprovalPolicy(item.Module, item.Amount)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return true
+	}
+	if !required {
+		return false
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return true
+	}
+	item.PolicyID = policy.ID
+	item.Steps = policy.Steps
+	item.Payload = string(body)
+	item.SubmitterID = currentAdminOperator(c)
+	created, err := gate.AdminCreateApprovalRequest(item)
+	if err != nil {
+		writeApprovalError(c, err)
+		return true
+	}
+	_ = gate.AdminCreateOperationLog("WORKFLOW", "SUBMIT_APPROVAL", created.Action, created.TargetID, created.SubmitterID, "", created.Status, created.Summary)
+	c.JSON(http.StatusAccepted, dto.OK(gin.H{"approval_required": true, "approval": created}))
+	return true
+}
+
+// RegisterApprovalExecutor sets the executor for a guarded action. The
+// router uses it for actions owned by other handlers, such as RBAC changes.
+func (h *AdminGrowthHandler) RegisterApprovalExecutor(action string, executor ApprovalExecutor) {
+	if h.approvalExecutors == nil {
+		h.approvalExecutors = map[string]ApprovalExecutor{}
+	}
+	h.approvalExecutors[action] = executor
+}
+
+func (h *AdminGrowthHandler) registerApprovalExecutors() {
+	h.RegisterApprovalExecutor(model.ApprovalActionStockSelectionPublish, func(ctx context.Context, item model.ApprovalRequest) error {
+		var req adminStockSelectionApproveRequest
+		if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+			return err
+		}
+		_, err := h.service.AdminApproveStockSelectionReview(item.TargetID, item.SubmitterID, req.ReviewNote, req.Force, req.OverrideReason)
+		return err
+	})
+	h.RegisterApprovalExecutor(model.ApprovalActionFuturesSelectionPublish, func(ctx context.Context, item model.ApprovalRequest) error {
+		var req adminFuturesSelectionApproveRequest
+		if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+			return err
+		}
+		_, err := h.service.AdminApproveFuturesSelectionReview(item.TargetID, item.SubmitterID, req.ReviewNote, req.Force, req.OverrideReason)
+		return err
+	})
+	h.RegisterApprovalExecutor(model.ApprovalActionWithdrawApprove, func(ctx context.Context, item model.ApprovalRequest) error {
+		var req dto.ReviewWithdrawRequest
+		if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+			return err
+		}
+		return h.service.AdminReviewWithdrawRequest(item.TargetID, req.Status, req.Reason)
+	})
+	h.RegisterApprovalExecutor(model.ApprovalActionPaymentConfigUpsert, func(ctx context.Context, item model.ApprovalRequest) error {
+		var req dto.SystemConfigUpsertRequest
+		if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+			return err
+		}
+		if err := h.service.AdminUpsertSystemConfig(req.ConfigKey, req.ConfigValue, req.Description, item.SubmitterID); err != nil {
+			return err
+		}
+		_ = h.service.AdminCreateOperationLog("SYSTEM", "UPSERT_CONFIG", "SYSTEM_CONFIG", req.ConfigKey, item.SubmitterID, "", maskSystemConfigValueForAudit(req.ConfigKey, req.ConfigValue), req.Description)
+		return nil
+	})
+	h.RegisterApprovalExecutor(model.ApprovalActionPolicySave, func(ctx context.Context, item model.ApprovalRequest) error {
+		var policy model.ApprovalPolicy
+		if err := json.Unmarshal([]byte(item.Payload), &policy); err != nil {
+			return err
+		}
+		saved, err := h.service.AdminSaveApprovalPolicy(policy, item.SubmitterID)
+		if err != nil {
+			return err
+		}
+		_ = h.service.AdminCreateOperationLog("WORKFLOW", "SAVE_APPROVAL_POLICY", "APPROVAL_POLICY", saved.Module, item.SubmitterID, "", saved.Status, fmt.Sprintf("steps=%d min_amount=%.2f", len(saved.Steps), saved.MinAmount))
+		return nil
+	})
+}
+
+// executeApprovalRequest claims a completed chain and runs its action. The
+// claim makes sure the action runs once even if approvers race; a failed
+// action is recorded as EXECUTE_FAILED and can be retried.
+func (h *AdminGrowthHandler) executeApprovalRequest(ctx context.Context, id string) (model.ApprovalRequest, error) {
+	item, err := h.service.AdminClaimApprovalExecution(id)
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	executeError := ""
+	if executor, ok := h.approvalExecutors[item.Action]; !ok {
+		executeError = "no executor registered for action " + item.Action
+	} else if err := executor(ctx, item); err != nil {
+		executeError = err.Error()
+	}
+	if err := h.service.AdminFinishApprovalExecution(item.ID, executeError); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	return h.service.AdminGetApprovalRequest(item.ID)
+}
+
+func (h *AdminGrowthHandler) ListApprovalPolicies(c *gin.Context) {
+	items, err := h.service.AdminListApprovalPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "total": len(items)}))
+}
+
+func (h *AdminGrowthHandler) SaveApprovalPolicy(c *gin.Context) {
+	var req dto.ApprovalPolicySaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	policy := model.ApprovalPolicy{
+		Module:      c.Param("module"),
+		DisplayName: req.DisplayName,
+		Status:      req.Status,
+		MinAmount:   req.MinAmount,
+		Steps:       make([]model.ApprovalStep, 0, len(req.Steps)),
+	}
+	for _, step := range req.Steps {
+		policy.Steps = append(policy.Steps, model.ApprovalStep{
+			Name:              step.Name,
+			RequiredApprovals: step.RequiredApprovals,
+			ApproverRoles:     step.ApproverRoles,
+			ApproverIDs:       step.ApproverIDs,
+		})
+	}
+	policy, err := policy.Normalized()
+	if err != nil {
+		writeApprovalError(c, err)
+		return
+	}
+	current, err := h.service.AdminGetApprovalPolicy(policy.Module)
+	if err != nil && !errors.Is(err, model.ErrApprovalPolicyNotFound) {
+		writeApprovalError(c, err)
+		return
+	}
+	// An edit that weakens an active chain has to be signed off by that
+	// chain, otherwise one admin could switch it off and act alone.
+	if err == nil && current.Relaxes(policy) {
+		if submitForApproval(c, h.service, model.ApprovalRequest{
+			Module:   current.Module,
+			Action:   model.ApprovalActionPolicySave,
+			TargetID: current.Module,
+			Amount:   current.MinAmount,
+			Summary:  fmt.Sprintf("修改审批策略 %s：状态 %s，%d 步，起审金额 %.2f", current.Module, policy.Status, len(policy.Steps), policy.MinAmount),
+		}, policy) {
+			return
+		}
+	}
+	item, err := h.service.AdminSaveApprovalPolicy(policy, currentAdminOperator(c))
+	if err != nil {
+		writeApprovalError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "WORKFLOW", "SAVE_APPROVAL_POLICY", "APPROVAL_POLICY", item.Module, "", item.Status, fmt.Sprintf("steps=%d min_amount=%.2f", len(item.Steps), item.MinAmount))
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+func (h *AdminGrowthHandler) ListApprovalRequests(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListApprovalRequests(c.Query("module"), c.Query("status"), c.Query("submitter_id"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) GetApprovalRequest(c *gin.Context) {
+	item, err := h.service.AdminGetApprovalRequest(c.Param("id"))
+	if err != nil {
+		writeApprovalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// DecideApprovalRequest signs the current step of a chain. The decision that
+// completes the last step also runs the guarded action.
+func (h *AdminGrowthHandler) DecideApprovalRequest(c *gin.Context) {
+	var req dto.ApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	item, err := h.service.AdminDecideApprovalRequest(c.Param("id"), currentAdminOperator(c), req.Decision, req.Note)
+	if err != nil {
+		writeApprovalError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "WORKFLOW", "APPROVAL_DECISION", "APPROVAL_REQUEST", item.ID, model.ApprovalRequestPending, req.Decision, req.Note)
+	if item.Status == model.ApprovalRequestApproved {
+		executed, err := h.executeApprovalRequest(c.Request.Context(), item.ID)
+		if err != nil {
+			writeApprovalError(c, err)
+			return
+		}
+		h.writeOperationLog(c, "WORKFLOW", "EXECUTE_APPROVAL", executed.Action, executed.TargetID, item.Status, executed.Status, executed.ExecuteError)
+		item = executed
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// RetryApprovalExecution re-runs the action of a completed chain whose
+// execution failed, e.g. after a publish conflict has been resolved.
+func (h *AdminGrowthHandler) RetryApprovalExecution(c *gin.Context) {
+	item, err := h.executeApprovalRequest(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeApprovalError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "WORKFLOW", "EXECUTE_APPROVAL", item.Action, item.TargetID, model.ApprovalRequestExecuteFailed, item.Status, item.ExecuteError)
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+func writeApprovalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrApprovalPolicyInvalid):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrApprovalSelfApproval),
+		errors.Is(err, model.ErrApprovalAlreadyDecided),
+		errors.Is(err, model.ErrApprovalNotEligible):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40310, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrApprovalPolicyNotFound), errors.Is(err, model.ErrApprovalRequestNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40412, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrApprovalRequestDuplicate),
+		errors.Is(err, model.ErrApprovalRequestNotPending),
+		errors.Is(err, model.ErrApprovalNotExecutable):
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40912, Message: err.Error(), Data: struct{}{}})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	}
+}
+
+// isPaymentConfigKey reports whether a system config belongs to payments and
+// falls under the PAYMENT_CONFIG approval policy.
+func isPaymentConfigKey(configKey string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(configKey)), "payment.")
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// newApprovalChainRouterForTest acts as whichever admin is named in the
+// X-Test-Admin header, so makers and checkers can take turns on one chain.
+func newApprovalChainRouterForTest(t *testing.T) (*gin.Engine, *AdminGrowthHandler) {
+	t.Helper()
+	growthHandler := newAdminGrowthHandlerForTest(t)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-Test-Admin"))
+		c.Next()
+	})
+	router.PUT("/api/v1/admin/workflow/approval-policies/:module", growthHandler.SaveApprovalPolicy)
+	router.GET("/api/v1/admin/workflow/approvals/:id", growthHandler.GetApprovalRequest)
+	router.POST("/api/v1/admin/workflow/approvals/:id/decision", growthHandler.DecideApprovalRequest)
+	router.POST("/api/v1/admin/workflow/approvals/:id/execute", growthHandler.RetryApprovalExecution)
+	router.PUT("/api/v1/admin/users/withdraw-requests/:id/review", growthHandler.ReviewWithdrawRequest)
+	router.PUT("/api/v1/admin/system/configs", growthHandler.UpsertSystemConfig)
+	return router, growthHandler
+}
+
+func serveApprovalChainRequest(t *testing.T, router *gin.Engine, adminID string, method string, path string, body string) (int, int, json.RawMessage) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-Admin", adminID)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var payload struct {
+		Code int             `json:"code"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return rec.Code, payload.Code, payload.Data
+}
+
+func decodeApprovalRequest(t *testing.T, data json.RawMessage) model.ApprovalRequest {
+	t.Helper()
+	var item model.ApprovalRequest
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatalf("unmarshal approval request: %v", err)
+	}
+	return item
+}
+
+func TestWithdrawApprovalRunsOnlyAfterChainCompletes(t *testing.T) {
+	router, growthHandler := newApprovalChainRouterForTest(t)
+	executed := 0
+	growthHandler.RegisterApprovalExecutor(model.ApprovalActionWithdrawApprove, func(ctx context.Context, item model.ApprovalRequest) error {
+		executed++
+		return nil
+	})
+
+	status, code, _ := serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, "/api/v1/admin/workflow/approval-policies/WITHDRAW", `{
+		"status":"ACTIVE",
+		"min_amount":5000,
+		"steps":[
+			{"name":"运营复核","required_approvals":1,"approver_roles":["OPS_ADMIN"]},
+			{"name":"超管终审","required_approvals":1,"approver_roles":["SUPER_ADMIN"]}
+		]
+	}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("save policy: status=%d code=%d", status, code)
+	}
+
+	status, _, _ = serveApprovalChainRequest(t, router, "admin_003", http.MethodPut, "/api/v1/admin/users/withdraw-requests/wd_001/review", `{"status":"APPROVED"}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected withdraw below the threshold to be approved directly, got %d", status)
+	}
+
+	status, code, data := serveApprovalChainRequest(t, router, "admin_003", http.MethodPut, "/api/v1/admin/users/withdraw-requests/wd_002/review", `{"status":"APPROVED","reason":"大额提现"}`)
+	if status != http.StatusAccepted || code != 0 {
+		t.Fatalf("expected 202 for guarded withdraw, got status=%d code=%d", status, code)
+	}
+	var submitted struct {
+		ApprovalRequired bool                  `json:"approval_required"`
+		Approval         model.ApprovalRequest `json:"approval"`
+	}
+	if err := json.Unmarshal(data, &submitted); err != nil {
+		t.Fatalf("unmarshal submit response: %v", err)
+	}
+	if !submitted.ApprovalRequired || submitted.Approval.ID == "" || submitted.Approval.CurrentStep != 1 {
+		t.Fatalf("unexpected submit response: %+v", submitted)
+	}
+	decisionPath := "/api/v1/admin/workflow/approvals/" + submitted.Approval.ID + "/decision"
+
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_003", http.MethodPut, "/api/v1/admin/users/withdraw-requests/wd_002/review", `{"status":"APPROVED"}`)
+	if status != http.StatusConflict || code != 40912 {
+		t.Fatalf("expected duplicate submission to conflict, got status=%d code=%d", status, code)
+	}
+
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_003", http.MethodPost, decisionPath, `{"decision":"APPROVE"}`)
+	if status != http.StatusForbidden || code != 40310 {
+		t.Fatalf("expected self-approval to be refused, got status=%d code=%d", status, code)
+	}
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_001", http.MethodPost, decisionPath, `{"decision":"APPROVE"}`)
+	if status != http.StatusForbidden || code != 40310 {
+		t.Fatalf("expected super admin to be ineligible for the ops step, got status=%d code=%d", status, code)
+	}
+
+	status, code, data = serveApprovalChainRequest(t, router, "admin_002", http.MethodPost, decisionPath, `{"decision":"APPROVE","note":"金额核对无误"}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("ops approval: status=%d code=%d", status, code)
+	}
+	if item := decodeApprovalRequest(t, data); item.Status != model.ApprovalRequestPending || item.CurrentStep != 2 {
+		t.Fatalf("expected chain to move to step 2, got %+v", item)
+	}
+	if executed != 0 {
+		t.Fatalf("withdraw must not be executed before the last step, executed=%d", executed)
+	}
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_002", http.MethodPost, decisionPath, `{"decision":"APPROVE"}`)
+	if status != http.StatusForbidden || code != 40310 {
+		t.Fatalf("expected a second signature from the same admin to be refused, got status=%d code=%d", status, code)
+	}
+
+	status, code, data = serveApprovalChainRequest(t, router, "admin_001", http.MethodPost, decisionPath, `{"decision":"APPROVE"}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("final approval: status=%d code=%d", status, code)
+	}
+	item := decodeApprovalRequest(t, data)
+	if item.Status != model.ApprovalRequestExecuted || len(item.Decisions) != 2 {
+		t.Fatalf("expected chain to be executed with two decisions, got %+v", item)
+	}
+	if executed != 1 {
+		t.Fatalf("expected withdraw to be executed once, executed=%d", executed)
+	}
+
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_001", http.MethodPost, "/api/v1/admin/workflow/approvals/"+item.ID+"/execute", "")
+	if status != http.StatusConflict || code != 40912 {
+		t.Fatalf("expected executed chain not to run again, got status=%d code=%d", status, code)
+	}
+}
+
+func TestPaymentConfigApprovalRetriesFailedExecution(t *testing.T) {
+	router, growthHandler := newApprovalChainRouterForTest(t)
+	attempts := 0
+	growthHandler.RegisterApprovalExecutor(model.ApprovalActionPaymentConfigUpsert, func(ctx context.Context, item model.ApprovalRequest) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("payment gateway unreachable")
+		}
+		return nil
+	})
+
+	status, _, _ := serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, "/api/v1/admin/workflow/approval-policies/PAYMENT_CONFIG", `{
+		"status":"ACTIVE",
+		"steps":[{"required_approvals":1,"approver_ids":["admin_001"]}]
+	}`)
+	if status != http.StatusOK {
+		t.Fatalf("save policy: status=%d", status)
+	}
+
+	status, code, _ := serveApprovalChainRequest(t, router, "admin_002", http.MethodPut, "/api/v1/admin/system/configs", `{"config_key":"app.theme","config_value":"dark"}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("expected non-payment config to save directly, got status=%d code=%d", status, code)
+	}
+
+	status, _, data := serveApprovalChainRequest(t, router, "admin_002", http.MethodPut, "/api/v1/admin/system/configs", `{"config_key":"payment.yolkpay.callback_url","config_value":"https://pay.example.com/cb"}`)
+	if status != http.StatusAccepted {
+		t.Fatalf("expected payment config to wait for approval, got status=%d", status)
+	}
+	var submitted struct {
+		Approval model.ApprovalRequest `json:"approval"`
+	}
+	if err := json.Unmarshal(data, &submitted); err != nil {
+		t.Fatalf("unmarshal submit response: %v", err)
+	}
+
+	status, _, data = serveApprovalChainRequest(t, router, "admin_001", http.MethodPost, "/api/v1/admin/workflow/approvals/"+submitted.Approval.ID+"/decision", `{"decision":"APPROVE"}`)
+	if status != http.StatusOK {
+		t.Fatalf("approval: status=%d", status)
+	}
+	if item := decodeApprovalRequest(t, data); item.Status != model.ApprovalRequestExecuteFailed || item.ExecuteError == "" {
+		t.Fatalf("expected failed execution to be recorded, got %+v", item)
+	}
+
+	status, _, data = serveApprovalChainRequest(t, router, "admin_001", http.MethodPost, "/api/v1/admin/workflow/approvals/"+submitted.Approval.ID+"/execute", "")
+	if status != http.StatusOK {
+		t.Fatalf("retry: status=%d", status)
+	}
+	if item := decodeApprovalRequest(t, data); item.Status != model.ApprovalRequestExecuted || attempts != 2 {
+		t.Fatalf("expected retry to execute, got %+v attempts=%d", item, attempts)
+	}
+}
+
+func TestRBACCreateActionsWaitForApproval(t *testing.T) {
+	router, growthHandler := newApprovalChainRouterForTest(t)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	authHandler := &AuthHandler{db: db, operationLogs: growthHandler.service, approvals: growthHandler.service}
+	router.POST("/api/v1/admin/access/roles", authHandler.AdminCreateRole)
+	router.POST("/api/v1/admin/access/admin-users", authHandler.AdminCreateAdminUser)
+	executors := authHandler.ApprovalExecutors()
+	for _, action := range []string{model.ApprovalActionRoleCreate, model.ApprovalActionAdminUserCreate} {
+		if executors[action] == nil {
+			t.Fatalf("expected an executor for %s", action)
+		}
+	}
+
+	status, _, _ := serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, "/api/v1/admin/workflow/approval-policies/RBAC", `{
+		"status":"ACTIVE",
+		"steps":[{"required_approvals":1,"approver_roles":["SUPER_ADMIN"]}]
+	}`)
+	if status != http.StatusOK {
+		t.Fatalf("save policy: status=%d", status)
+	}
+
+	status, _, data := serveApprovalChainRequest(t, router, "admin_002", http.MethodPost, "/api/v1/admin/access/roles", `{
+		"role_key":"auditor","role_name":"审计员","status":"ACTIVE","permission_codes":["audit.view"]
+	}`)
+	if status != http.StatusAccepted {
+		t.Fatalf("expected role creation to wait for approval, got status=%d", status)
+	}
+	var submitted struct {
+		Approval model.ApprovalRequest `json:"approval"`
+	}
+	if err := json.Unmarshal(data, &submitted); err != nil {
+		t.Fatalf("unmarshal submit response: %v", err)
+	}
+	if submitted.Approval.Action != model.ApprovalActionRoleCreate || submitted.Approval.Status != model.ApprovalRequestPending || submitted.Approval.TargetID != "AUDITOR" {
+		t.Fatalf("unexpected role approval %+v", submitted.Approval)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE phone = ?")).
+		WithArgs("13900000009").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	status, _, data = serveApprovalChainRequest(t, router, "admin_002", http.MethodPost, "/api/v1/admin/access/admin-users", `{
+		"phone":"13900000009","password":"Passw0rd!","status":"ACTIVE","role_ids":["role_ops_admin"]
+	}`)
+	if status != http.StatusAccepted {
+		t.Fatalf("expected admin creation to wait for approval, got status=%d", status)
+	}
+	if err := json.Unmarshal(data, &submitted); err != nil {
+		t.Fatalf("unmarshal submit response: %v", err)
+	}
+	if submitted.Approval.Action != model.ApprovalActionAdminUserCreate || submitted.Approval.Status != model.ApprovalRequestPending {
+		t.Fatalf("unexpected admin approval %+v", submitted.Approval)
+	}
+	stored, err := growthHandler.service.AdminGetApprovalRequest(submitted.Approval.ID)
+	if err != nil {
+		t.Fatalf("AdminGetApprovalRequest() error = %v", err)
+	}
+	if strings.Contains(stored.Payload, "Passw0rd!") || !strings.Contains(stored.Payload, "password_hash") {
+		t.Fatalf("expected the stored payload to carry a password hash only, got %s", stored.Payload)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestSingleAdminCannotWeakenActiveApprovalChain(t *testing.T) {
+	router, growthHandler := newApprovalChainRouterForTest(t)
+	policyPath := "/api/v1/admin/workflow/approval-policies/WITHDRAW"
+
+	status, code, _ := serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, policyPath, `{
+		"status":"ACTIVE",
+		"min_amount":5000,
+		"steps":[
+			{"name":"运营复核","required_approvals":1,"approver_roles":["OPS_ADMIN"]},
+			{"name":"超管终审","required_approvals":1,"approver_roles":["SUPER_ADMIN"]}
+		]
+	}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("enable policy: status=%d code=%d", status, code)
+	}
+
+	status, code, data := serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, policyPath, `{
+		"status":"DISABLED",
+		"min_amount":5000,
+		"steps":[
+			{"name":"运营复核","required_approvals":1,"approver_roles":["OPS_ADMIN"]},
+			{"name":"超管终审","required_approvals":1,"approver_roles":["SUPER_ADMIN"]}
+		]
+	}`)
+	if status != http.StatusAccepted || code != 0 {
+		t.Fatalf("expected disabling the chain to need approval, got status=%d code=%d", status, code)
+	}
+	var submitted struct {
+		Approval model.ApprovalRequest `json:"approval"`
+	}
+	if err := json.Unmarshal(data, &submitted); err != nil {
+		t.Fatalf("unmarshal submit response: %v", err)
+	}
+	if submitted.Approval.Action != model.ApprovalActionPolicySave || len(submitted.Approval.Steps) != 2 {
+		t.Fatalf("expected the edit to go through the current two-step chain, got %+v", submitted.Approval)
+	}
+
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, policyPath, `{
+		"status":"ACTIVE",
+		"min_amount":5000,
+		"steps":[{"name":"运营复核","required_approvals":1,"approver_roles":["OPS_ADMIN"]}]
+	}`)
+	if status != http.StatusConflict || code != 40912 {
+		t.Fatalf("expected a second weakening edit to wait for the open one, got status=%d code=%d", status, code)
+	}
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_001", http.MethodPost, "/api/v1/admin/workflow/approvals/"+submitted.Approval.ID+"/decision", `{"decision":"APPROVE"}`)
+	if status != http.StatusForbidden || code != 40310 {
+		t.Fatalf("expected the submitter not to sign their own edit, got status=%d code=%d", status, code)
+	}
+
+	policy, err := growthHandler.service.AdminGetApprovalPolicy(model.ApprovalModuleWithdraw)
+	if err != nil {
+		t.Fatalf("get policy: %v", err)
+	}
+	if policy.Status != model.ApprovalPolicyStatusActive || len(policy.Steps) != 2 {
+		t.Fatalf("expected the chain to stay in force, got %+v", policy)
+	}
+
+	status, code, _ = serveApprovalChainRequest(t, router, "admin_001", http.MethodPut, policyPath, `{
+		"status":"ACTIVE",
+		"min_amount":1000,
+		"steps":[
+			{"name":"运营复核","required_approvals":2,"approver_roles":["OPS_ADMIN"]},
+			{"name":"超管终审","required_approvals":1,"approver_roles":["SUPER_ADMIN"]}
+		]
+	}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("expected tightening the chain to save directly, got status=%d code=%d", status, code)
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/platform/config"
+)
+
+const legacyAttachmentMigrateBatchSize = 50
+
+// legacyUploadURLPrefixes are the file_url forms of files written to the
+// public upload directory: a bare /uploads path, or one prefixed with
+// PUBLIC_BASE_URL.
+func legacyUploadURLPrefixes(cfg config.Config) []string {
+	prefixes := []string{"/uploads/"}
+	if baseURL := strings.TrimRight(strings.TrimSpace(cfg.PublicBaseURL), "/"); baseURL != "" {
+		prefixes = append(prefixes, baseURL+"/uploads/")
+	}
+	return prefixes
+}
+
+// legacyUploadPath maps a legacy /uploads file_url to its path on disk, or
+// returns "" when fileURL is not one.
+func legacyUploadPath(cfg config.Config, fileURL string) string {
+	target := strings.TrimSpace(fileURL)
+	if baseURL := strings.TrimRight(strings.TrimSpace(cfg.PublicBaseURL), "/"); baseURL != "" {
+		target = strings.TrimPrefix(target, baseURL)
+	}
+	if !strings.HasPrefix(target, "/uploads/") {
+		return ""
+	}
+	uploadRoot := strings.TrimSpace(cfg.AttachmentUploadDir)
+	if uploadRoot == "" {
+		uploadRoot = "./uploads"
+	}
+	// Cleaning a rooted path keeps ".." from leaving the upload dir.
+	relPath := filepath.Clean("/" + strings.TrimPrefix(target, "/uploads/"))
+	return filepath.Join(uploadRoot, filepath.FromSlash(relPath))
+}
+
+// MigrateLegacyAttachments moves up to limit attachments still pointing at
+// the public upload directory into the attachment store, relinks their rows
+// to the storage:// reference and removes the public copy. Files missing
+// from disk are counted and left for an admin to re-upload.
+func (h *AdminGrowthHandler) MigrateLegacyAttachments(ctx context.Context, limit int) (string, error) {
+	items, err := h.service.AdminListLegacyNewsAttachments(legacyUploadURLPrefixes(h.cfg), limit)
+	if err != nil {
+		return "", err
+	}
+	ossCfg := h.resolveOSSUploadConfig()
+	migrated, missing := 0, 0
+	seen := map[string]bool{}
+	for _, item := range items {
+		if seen[item.FileURL] {
+			continue
+		}
+		seen[item.FileURL] = true
+		localPath := legacyUploadPath(h.cfg, item.FileURL)
+		if localPath == "" {
+			continue
+		}
+		payload, err := os.ReadFile(localPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				missing++
+				continue
+			}
+			return "", err
+		}
+		ext := strings.ToLower(path.Ext(item.FileURL))
+		if ext == "" {
+			ext = resolveUploadExt(item.FileName, item.MimeType)
+		}
+		mimeType := strings.TrimSpace(item.MimeType)
+		if mimeType == "" {
+			mimeType = http.DetectContentType(payload)
+		}
+		fileURL, _, _, _, err := h.putNewsAttachmentObject(ctx, ossCfg, ext, mimeType, payload)
+		if err != nil {
+			return "", err
+		}
+		if _, err := h.service.AdminRelinkNewsAttachmentFile(item.FileURL, fileURL); err != nil {
+			return "", err
+		}
+		if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		migrated++
+	}
+	return fmt.Sprintf("attachment_legacy_migrated=%d attachment_legacy_missing=%d", migrated, missing), nil
+}
+
+// MigrateLegacyNewsAttachments runs one batch of MigrateLegacyAttachments.
+// Admins call it until migrated is 0.
+func (h *AdminGrowthHandler) MigrateLegacyNewsAttachments(c *gin.Context) {
+	summary, err := h.MigrateLegacyAttachments(c.Request.Context(), legacyAttachmentMigrateBatchSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "NEWS", "MIGRATE_LEGACY_ATTACHMENTS", "NEWS_ATTACHMENT", "", "", summary, "")
+	c.JSON(http.StatusOK, dto.OK(gin.H{"summary": summary}))
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/objectstore"
+)
+
+const defaultAttachmentStorageDir = "./storage/attachments"
+
+func newQiniuAttachmentStore(cfg ossUploadConfig) *objectstore.QiniuStore {
+	return &objectstore.QiniuStore{
+		AccessKey: cfg.AccessKey,
+		SecretKey: cfg.SecretKey,
+		Bucket:    cfg.Bucket,
+		Domain:    cfg.Domain,
+		Region:    cfg.Region,
+		UseHTTPS:  cfg.UseHTTPS,
+	}
+}
+
+func newS3AttachmentStore(cfg ossUploadConfig) *objectstore.S3Store {
+	return &objectstore.S3Store{
+		Endpoint:  cfg.S3Endpoint,
+		Region:    cfg.S3Region,
+		Bucket:    cfg.S3Bucket,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+		PathStyle: cfg.S3PathStyle,
+	}
+}
+
+// activeAttachmentProvider is where new attachments are written: the
+// configured OSS provider when enabled, otherwise local disk.
+func activeAttachmentProvider(cfg ossUploadConfig) string {
+	if !cfg.Enabled {
+		return objectstore.ProviderLocal
+	}
+	switch provider := strings.ToUpper(strings.TrimSpace(cfg.Provider)); provider {
+	case objectstore.ProviderQiniu, objectstore.ProviderS3:
+		return provider
+	default:
+		return objectstore.ProviderLocal
+	}
+}
+
+// openAttachmentStore opens the store for provider. Stored references carry
+// their provider, so attachments stay readable after the active provider
+// changes as long as the old provider's config is kept.
+func openAttachmentStore(cfg ossUploadConfig, appCfg config.Config, provider string) (objectstore.Store, error) {
+	switch strings.ToUpper(strings.TrimSpace(provider)) {
+	case objectstore.ProviderLocal:
+		root := strings.TrimSpace(appCfg.AttachmentStorageDir)
+		if root == "" {
+			root = defaultAttachmentStorageDir
+		}
+		return objectstore.NewLocalStore(root), nil
+	case objectstore.ProviderQiniu:
+		return newQiniuAttachmentStore(cfg), nil
+	case objectstore.ProviderS3:
+		return newS3AttachmentStore(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage provider %q", provider)
+	}
+}
+
+func attachmentObjectPrefix(cfg ossUploadConfig, provider string) string {
+	switch provider {
+	case objectstore.ProviderQiniu:
+		return joinObjectPath(cfg.PathPrefix, "news", "attachments")
+	case objectstore.ProviderS3:
+		return joinObjectPath(cfg.S3PathPrefix, "news", "attachments")
+	default:
+		return joinObjectPath("news", "attachments")
+	}
+}
+
+// putNewsAttachmentObject writes payload to the active store under a key
+// derived from its SHA-256, skipping the upload when the same content is
+// already stored, and returns the storage:// reference.
+func (h *AdminGrowthHandler) putNewsAttachmentObject(ctx context.Context, ossCfg ossUploadConfig, ext string, mimeType string, payload []byte) (fileURL string, objectKey string, digest string, deduplicated bool, err error) {
+	provider := activeAttachmentProvider(ossCfg)
+	store, err := openAttachmentStore(ossCfg, h.cfg, provider)
+	if err != nil {
+		return "", "", "", false, err
+	}
+	objectKey, digest = objectstore.ContentKey(attachmentObjectPrefix(ossCfg, provider), payload, ext)
+	deduplicated = true
+	if _, err := store.Head(ctx, objectKey); err != nil {
+		if !errors.Is(err, objectstore.ErrNotFound) {
+			return "", "", "", false, err
+		}
+		if err := store.Put(ctx, objectKey, payload, mimeType); err != nil {
+			return "", "", "", false, err
+		}
+		deduplicated = false
+	}
+	return objectstore.FormatRef(provider, objectKey), objectKey, digest, deduplicated, nil
+}
+
+// storeNewsAttachmentObject stores an uploaded attachment. The returned
+// file_url is a storage:// reference that only the download endpoints
+// resolve.
+func (h *AdminGrowthHandler) storeNewsAttachmentObject(c *gin.Context, ossCfg ossUploadConfig, originalName string, ext string, mimeType string, payload []byte) {
+	fileURL, objectKey, digest, deduplicated, err := h.putNewsAttachmentObject(c.Request.Context(), ossCfg, ext, mimeType, payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	// Text is indexed by file_url so search works as soon as an attachment
+	// row points at this object; a failed write is retried by the pending
+	// extraction job.
+	text := extractNewsAttachmentText(fileURL, originalName, mimeType, payload)
+	if err := h.service.UpsertNewsAttachmentText(text); err != nil {
+		text.Status = ""
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{
+		"file_name":        originalName,
+		"file_url":         fileURL,
+		"file_size":        len(payload),
+		"mime_type":        mimeType,
+		"storage_provider": activeAttachmentProvider(ossCfg),
+		"object_key":       objectKey,
+		"content_sha256":   digest,
+		"deduplicated":     deduplicated,
+		"text_status":      text.Status,
+	}))
+}
+
+// serveAttachmentObject redirects to a presigned URL, or streams the object
+// when the store cannot presign (local disk).
+func serveAttachmentObject(c *gin.Context, store objectstore.Store, key string, fileName string, mimeType string, ttl time.Duration) {
+	ctx := c.Request.Context()
+	signedURL, err := store.PresignGet(ctx, key, ttl)
+	if err == nil {
+		c.Redirect(http.StatusFound, signedURL)
+		return
+	}
+	if !errors.Is(err, objectstore.ErrPresignUnsupported) {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	streamAttachmentObject(c, store, key, fileName, mimeType)
+}
+
+// streamAttachmentObject proxies the object body through the backend.
+func streamAttachmentObject(c *gin.Context, store objectstore.Store, key string, fileName string, mimeType string) {
+	body, info, err := store.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, objectstore.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40402, Message: "attachment file not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	defer body.Close()
+	contentType := strings.TrimSpace(mimeType)
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, info.Size, contentType, body, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": fileName}),
+		"Cache-Control":       "private, no-store",
+	})
+}
+
+func attachmentDownloadTTL(cfg config.Config) time.Duration {
+	ttl := time.Duration(cfg.AttachmentSigningTTLSecond) * time.Second
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return ttl
+}
+
+// DownloadNewsAttachment lets admins open any attachment, including
+// unpublished and VIP-only ones. Stored objects are always streamed so the
+// admin console can fetch them with its bearer token and no bucket CORS.
+func (h *AdminGrowthHandler) DownloadNewsAttachment(c *gin.Context) {
+	item, err := h.service.AdminGetNewsAttachment(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40402, Message: "attachment not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	store, key, ok, err := openAttachmentObject(c.Request.Context(), h.resolveOSSUploadConfig(), h.cfg, item.FileURL, item.MimeType, false)
+	if err != nil {
+		writeAttachmentObjectError(c, err)
+		return
+	}
+	if !ok {
+		c.Redirect(http.StatusFound, item.FileURL)
+		return
+	}
+	streamAttachmentObject(c, store, key, item.FileName, item.MimeType)
+}
+
+// externalAttachmentMirrorKey is where the copy of an attachment that is
+// not a stored object (a legacy /uploads file, a doc_fast or Tushare link)
+// is kept so it can be streamed and watermarked like one. It is keyed by
+// the URL so the source is fetched once.
+func externalAttachmentMirrorKey(cfg ossUploadConfig, provider string, fileURL string) string {
+	fileURL = strings.TrimSpace(fileURL)
+	ext := ""
+	if parsed, err := url.Parse(fileURL); err == nil {
+		ext = strings.ToLower(path.Ext(parsed.Path))
+	}
+	if len(ext) > 10 {
+		ext = ""
+	}
+	sum := sha256.Sum256([]byte(fileURL))
+	return objectstore.JoinKey(attachmentObjectPrefix(cfg, provider), "mirror", hex.EncodeToString(sum[:])+ext)
+}
+
+// attachmentSourceObject returns the store and key an attachment is served
+// from without fetching anything: its own object for storage:// references,
+// otherwise its mirror in the active store.
+func attachmentSourceObject(cfg ossUploadConfig, appCfg config.Config, fileURL string) (objectstore.Store, string, error) {
+	if provider, key, ok := objectstore.ParseRef(fileURL); ok {
+		store, err := openAttachmentStore(cfg, appCfg, provider)
+		return store, key, err
+	}
+	provider := activeAttachmentProvider(cfg)
+	store, err := openAttachmentStore(cfg, appCfg, provider)
+	return store, externalAttachmentMirrorKey(cfg, provider, fileURL), err
+}
+
+// openAttachmentObject resolves fileURL to an object to serve. Files that
+// are not stored objects are mirrored into the active store when mirror is
+// set, and always when they are legacy /uploads files, which are no longer
+// served publicly. Otherwise ok is false and the caller redirects to
+// fileURL.
+func openAttachmentObject(ctx context.Context, cfg ossUploadConfig, appCfg config.Config, fileURL string, mimeType string, mirror bool) (objectstore.Store, string, bool, error) {
+	_, _, stored := objectstore.ParseRef(fileURL)
+	if !stored && !mirror && legacyUploadPath(appCfg, fileURL) == "" {
+		return nil, "", false, nil
+	}
+	store, key, err := attachmentSourceObject(cfg, appCfg, fileURL)
+	if err != nil || stored {
+		return store, key, err == nil, err
+	}
+	if _, err := store.Head(ctx, key); err == nil {
+		return store, key, true, nil
+	} else if !errors.Is(err, objectstore.ErrNotFound) {
+		return nil, "", false, err
+	}
+	body, err := openExternalAttachment(ctx, appCfg, fileURL)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", false, objectstore.ErrNotFound
+		}
+		return nil, "", false, err
+	}
+	payload, err := readAttachmentPayload(body, attachmentMaxBytes(appCfg))
+	if err != nil {
+		return nil, "", false, err
+	}
+	if err := store.Put(ctx, key, payload, mimeType); err != nil {
+		return nil, "", false, err
+	}
+	return store, key, true, nil
+}
+
+func writeAttachmentObjectError(c *gin.Context, err error) {
+	if errors.Is(err, objectstore.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40402, Message: "attachment file not found", Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+}
+
+// deleteAttachmentObjects removes what a deleted attachment leaves behind:
+// the stamped copies made for downloaderIDs and, once no attachment row
+// points at the file any more, the stored object or mirror itself. Dedupe
+// means several attachments can share one object.
+func (h *AdminGrowthHandler) deleteAttachmentObjects(ctx context.Context, attachmentID string, fileURL string, downloaderIDs []string) error {
+	store, key, err := attachmentSourceObject(h.resolveOSSUploadConfig(), h.cfg, fileURL)
+	if err != nil {
+		return err
+	}
+	for _, userID := range downloaderIDs {
+		if err := store.Delete(ctx, watermarkVariantKey(key, attachmentID, userID)); err != nil {
+			return err
+		}
+	}
+	refs, err := h.service.AdminCountNewsAttachmentsByFileURL(fileURL)
+	if err != nil || refs > 0 {
+		return err
+	}
+	return store.Delete(ctx, key)
+}
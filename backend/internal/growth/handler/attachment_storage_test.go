@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/objectstore"
+)
+
+func uploadNewsAttachmentForTest(t *testing.T, router *gin.Engine, fileName string, payload []byte) map[string]interface{} {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", fileName)
+	_, _ = part.Write(payload)
+	_ = writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/news/attachments/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return response.Data
+}
+
+func TestUploadNewsAttachmentStoresPrivateDeduplicatedObject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	storageDir := t.TempDir()
+	growthHandler := NewAdminGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{AttachmentStorageDir: storageDir})
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/admin/news/attachments/upload", growthHandler.UploadNewsAttachment)
+
+	payload := []byte("%PDF-1.4\nquarterly research note\n")
+	first := uploadNewsAttachmentForTest(t, router, "note.pdf", payload)
+	second := uploadNewsAttachmentForTest(t, router, "note-copy.pdf", payload)
+	fileURL, _ := first["file_url"].(string)
+	if !strings.HasPrefix(fileURL, "storage://LOCAL/") {
+		t.Fatalf("expected a local storage reference, got %v", first)
+	}
+	if first["deduplicated"] != false || second["deduplicated"] != true || second["file_url"] != fileURL {
+		t.Fatalf("expected the second upload to reuse the first object, got %v / %v", first, second)
+	}
+
+	provider, key, _ := objectstore.ParseRef(fileURL)
+	store, err := openAttachmentStore(ossUploadConfig{}, growthHandler.cfg, provider)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	downloadRouter := gin.New()
+	downloadRouter.GET("/download", func(c *gin.Context) {
+		serveAttachmentObject(c, store, key, "note.pdf", "application/pdf", time.Minute)
+	})
+	rec := httptest.NewRecorder()
+	downloadRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/download", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != string(payload) {
+		t.Fatalf("expected local object to be streamed, got %d %q", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), `filename=note.pdf`) {
+		t.Fatalf("unexpected content disposition %q", rec.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestMigrateLegacyAttachmentsMovesPublicUploadsIntoTheStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uploadDir := t.TempDir()
+	cfg := config.Config{AttachmentUploadDir: uploadDir, AttachmentStorageDir: t.TempDir()}
+	growthHandler := NewAdminGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), cfg)
+
+	legacyPath := legacyUploadPath(cfg, "/uploads/news/20260120/1768872000000000000_a1b2c3d4.pdf")
+	payload := []byte("%PDF-1.4\nweekly\n")
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0o755); err != nil {
+		t.Fatalf("create upload dir: %v", err)
+	}
+	if err := os.WriteFile(legacyPath, payload, 0o644); err != nil {
+		t.Fatalf("write legacy upload: %v", err)
+	}
+
+	summary, err := growthHandler.MigrateLegacyAttachments(context.Background(), 10)
+	if err != nil || summary != "attachment_legacy_migrated=1 attachment_legacy_missing=0" {
+		t.Fatalf("unexpected first run summary=%q err=%v", summary, err)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the public copy to be removed, stat err=%v", err)
+	}
+	store, err := openAttachmentStore(ossUploadConfig{}, cfg, objectstore.ProviderLocal)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	key, _ := objectstore.ContentKey(attachmentObjectPrefix(ossUploadConfig{}, objectstore.ProviderLocal), payload, ".pdf")
+	if _, err := store.Head(context.Background(), key); err != nil {
+		t.Fatalf("expected the file in the attachment store: %v", err)
+	}
+
+	summary, err = growthHandler.MigrateLegacyAttachments(context.Background(), 10)
+	if err != nil || summary != "attachment_legacy_migrated=0 attachment_legacy_missing=0" {
+		t.Fatalf("expected nothing left to migrate, summary=%q err=%v", summary, err)
+	}
+}
+
+func TestLegacyUploadPathStaysInsideTheUploadDir(t *testing.T) {
+	cfg := config.Config{AttachmentUploadDir: "/srv/uploads", PublicBaseURL: "https://cdn.example.com/"}
+	cases := map[string]string{
+		"/uploads/news/20260120/a.pdf":                           "/srv/uploads/news/20260120/a.pdf",
+		"https://cdn.example.com/uploads/news/20260120/a.pdf":    "/srv/uploads/news/20260120/a.pdf",
+		"/uploads/../../etc/passwd":                              "/srv/uploads/etc/passwd",
+		"storage://LOCAL/news/attachments/ab/abcdef.pdf":         "",
+		"https://img.cloudup518.top/uploads/news/20260120/a.pdf": "",
+	}
+	for fileURL, want := range cases {
+		if got := legacyUploadPath(cfg, fileURL); got != filepath.FromSlash(want) {
+			t.Fatalf("legacyUploadPath(%q) = %q, want %q", fileURL, got, want)
+		}
+	}
+}
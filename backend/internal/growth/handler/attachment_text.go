@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/objectstore"
+	"sercherai/backend/internal/platform/textextract"
+)
+
+const (
+	attachmentTextBatchSize    = 20
+	attachmentTextFetchTimeout = 30 * time.Second
+)
+
+// extractNewsAttachmentText runs extraction and records the outcome; formats
+// without a text layer are stored as UNSUPPORTED so they are not retried.
+func extractNewsAttachmentText(fileURL string, fileName string, mimeType string, payload []byte) model.NewsAttachmentText {
+	item := model.NewsAttachmentText{FileURL: fileURL}
+	doc, err := textextract.Extract(payload, fileName, mimeType)
+	switch {
+	case errors.Is(err, textextract.ErrUnsupported):
+		item.Status = "UNSUPPORTED"
+	case err != nil:
+		item.Status = "FAILED"
+		item.ErrorMessage = err.Error()
+	default:
+		item.Status = "EXTRACTED"
+		item.Format = doc.Format
+		item.PageCount = doc.PageCount()
+		item.PageOffsets = doc.PageOffsets
+		item.Content = doc.Text
+	}
+	return item
+}
+
+// attachmentMaxBytes bounds what extraction and mirroring will download.
+func attachmentMaxBytes(cfg config.Config) int64 {
+	maxMB := cfg.AttachmentUploadMaxMB
+	if maxMB <= 0 {
+		maxMB = 20
+	}
+	return int64(maxMB) * 1024 * 1024
+}
+
+// openExternalAttachment opens an attachment that is not a stored object:
+// a legacy /uploads path from the upload directory or a remote link
+// (doc_fast, Tushare) over HTTP.
+func openExternalAttachment(ctx context.Context, cfg config.Config, fileURL string) (io.ReadCloser, error) {
+	target := strings.TrimSpace(fileURL)
+	if localPath := legacyUploadPath(cfg, target); localPath != "" {
+		return os.Open(localPath)
+	}
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return nil, fmt.Errorf("unsupported attachment url %q", fileURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Timeout: attachmentTextFetchTimeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetch attachment: http %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// readAttachmentPayload reads body up to maxBytes and closes it.
+func readAttachmentPayload(body io.ReadCloser, maxBytes int64) ([]byte, error) {
+	defer body.Close()
+	payload, err := io.ReadAll(io.LimitReader(body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > maxBytes {
+		return nil, fmt.Errorf("attachment exceeds %d bytes", maxBytes)
+	}
+	return payload, nil
+}
+
+// loadNewsAttachmentPayload reads an attachment's bytes: stored objects
+// through their store, anything else through openExternalAttachment.
+func (h *AdminGrowthHandler) loadNewsAttachmentPayload(ctx context.Context, fileURL string) ([]byte, error) {
+	var body io.ReadCloser
+	var err error
+	if provider, key, ok := objectstore.ParseRef(fileURL); ok {
+		store, openErr := openAttachmentStore(h.resolveOSSUploadConfig(), h.cfg, provider)
+		if openErr != nil {
+			return nil, openErr
+		}
+		body, _, err = store.Get(ctx, key)
+	} else {
+		body, err = openExternalAttachment(ctx, h.cfg, fileURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return readAttachmentPayload(body, attachmentMaxBytes(h.cfg))
+}
+
+// indexNewsAttachmentFile fetches, extracts and stores the text of one
+// attachment file. Fetch errors are stored as FAILED too, so a dead link
+// does not hold up later batches.
+func (h *AdminGrowthHandler) indexNewsAttachmentFile(ctx context.Context, item model.NewsAttachment) (model.NewsAttachmentText, error) {
+	payload, err := h.loadNewsAttachmentPayload(ctx, item.FileURL)
+	text := model.NewsAttachmentText{FileURL: item.FileURL, Status: "FAILED"}
+	if err != nil {
+		text.ErrorMessage = err.Error()
+	} else {
+		text = extractNewsAttachmentText(item.FileURL, item.FileName, item.MimeType, payload)
+	}
+	return text, h.service.UpsertNewsAttachmentText(text)
+}
+
+// ExtractPendingAttachmentTexts extracts text for up to limit attachments
+// that have none yet and returns a key=value summary.
+func (h *AdminGrowthHandler) ExtractPendingAttachmentTexts(ctx context.Context, limit int) (string, error) {
+	items, err := h.service.AdminListNewsAttachmentsMissingText(limit)
+	if err != nil {
+		return "", err
+	}
+	counts := map[string]int{}
+	seen := map[string]bool{}
+	for _, item := range items {
+		if seen[item.FileURL] {
+			continue
+		}
+		seen[item.FileURL] = true
+		text, err := h.indexNewsAttachmentFile(ctx, item)
+		if err != nil {
+			return "", err
+		}
+		counts[text.Status]++
+	}
+	return fmt.Sprintf(
+		"attachment_text_extracted=%d attachment_text_unsupported=%d attachment_text_failed=%d",
+		counts["EXTRACTED"],
+		counts["UNSUPPORTED"],
+		counts["FAILED"],
+	), nil
+}
+
+// SyncDocFastNews runs the doc_fast incremental sync, then extracts text
+// for the attachments it brought in. Extraction problems are reported in
+// the summary without failing the sync, which has already committed.
+func (h *AdminGrowthHandler) SyncDocFastNews(ctx context.Context) (string, error) {
+	summary, err := h.service.AdminSyncDocFastNewsIncremental(0)
+	if err != nil {
+		return summary, err
+	}
+	textSummary, textErr := h.ExtractPendingAttachmentTexts(ctx, attachmentTextBatchSize)
+	if textErr != nil {
+		textSummary = fmt.Sprintf("attachment_text_error=%q", textErr.Error())
+	}
+	return strings.TrimSpace(summary + " " + textSummary), nil
+}
+
+// ExtractNewsAttachmentText re-runs extraction for one attachment, e.g.
+// after a FAILED fetch.
+func (h *AdminGrowthHandler) ExtractNewsAttachmentText(c *gin.Context) {
+	item, err := h.service.AdminGetNewsAttachment(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40402, Message: "attachment not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	text, err := h.indexNewsAttachmentFile(c.Request.Context(), item)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	text.ExtractedAt = time.Now().Format(time.RFC3339)
+	h.writeOperationLog(c, "NEWS", "EXTRACT_ATTACHMENT_TEXT", "NEWS_ATTACHMENT", item.ID, "", text.Status, "")
+	c.JSON(http.StatusOK, dto.OK(text))
+}
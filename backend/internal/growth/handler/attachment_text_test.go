@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+)
+
+func TestUploadNewsAttachmentExtractsText(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthHandler := NewAdminGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{AttachmentStorageDir: t.TempDir()})
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/admin/news/attachments/upload", growthHandler.UploadNewsAttachment)
+
+	data := uploadNewsAttachmentForTest(t, router, "notes.txt", []byte("白酒板块 估值修复\n"))
+	if data["text_status"] != "EXTRACTED" {
+		t.Fatalf("expected text to be extracted on upload, got %v", data)
+	}
+	image := uploadNewsAttachmentForTest(t, router, "chart.png", []byte("\x89PNG\r\n\x1a\n0000IHDR"))
+	if image["text_status"] != "UNSUPPORTED" {
+		t.Fatalf("expected images to be marked unsupported, got %v", image)
+	}
+}
+
+func TestIndexNewsAttachmentFileFetchesRemoteLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/report.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("symbol,target\n600519.SH,1880\n"))
+	}))
+	defer server.Close()
+	growthHandler := newAdminGrowthHandlerForTest(t)
+
+	text, err := growthHandler.indexNewsAttachmentFile(context.Background(), model.NewsAttachment{FileName: "report.csv", FileURL: server.URL + "/report.csv"})
+	if err != nil || text.Status != "EXTRACTED" || text.Content != "symbol,target\n600519.SH,1880" {
+		t.Fatalf("expected remote csv to be extracted, got %+v %v", text, err)
+	}
+	missing, err := growthHandler.indexNewsAttachmentFile(context.Background(), model.NewsAttachment{FileName: "gone.pdf", FileURL: server.URL + "/gone.pdf"})
+	if err != nil || missing.Status != "FAILED" || missing.ErrorMessage == "" {
+		t.Fatalf("expected a dead link to be recorded as FAILED, got %+v %v", missing, err)
+	}
+}
+
+func TestSearchGlobalIncludesAttachmentHits(t *testing.T) {
+	growthHandler := newUserGrowthHandlerForTest(t)
+	router := gin.New()
+	attachUserID(router, "u_001")
+	router.GET("/api/v1/search/global", growthHandler.SearchGlobal)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/search/global?keyword=%E7%99%BD%E9%85%92", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response struct {
+		Data struct {
+			Attachments struct {
+				Items []model.NewsAttachmentSearchHit `json:"items"`
+				Total int                             `json:"total"`
+			} `json:"attachments"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	hits := response.Data.Attachments
+	if hits.Total != 1 || len(hits.Items) != 1 || hits.Items[0].Page != 2 || hits.Items[0].AttachmentID == "" {
+		t.Fatalf("expected an attachment hit with its page, got %+v", hits)
+	}
+}
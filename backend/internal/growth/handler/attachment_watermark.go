@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/objectstore"
+	"sercherai/backend/internal/platform/pii"
+	"sercherai/backend/internal/platform/watermark"
+)
+
+// attachmentWatermarkSecret keys download markers. It falls back to the JWT
+// secret so markers stay verifiable when signed download links are off.
+func attachmentWatermarkSecret(cfg config.Config) string {
+	if secret := strings.TrimSpace(cfg.AttachmentSigningSecret); secret != "" {
+		return secret
+	}
+	return cfg.JWTSecret
+}
+
+func isWatermarkableAttachment(mimeType string, key string) bool {
+	switch strings.ToLower(strings.TrimSpace(mimeType)) {
+	case "application/pdf", "image/png", "image/jpeg":
+		return true
+	}
+	switch strings.ToLower(path.Ext(key)) {
+	case ".pdf", ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+// watermarkVariantKey places a user's stamped copy next to the source, keyed
+// by the source object name (its content hash) and a hash of the attachment
+// and user IDs, so object keys carry no account identifiers and attachments
+// sharing one object each get a copy with their own marker.
+func watermarkVariantKey(sourceKey string, attachmentID string, userID string) string {
+	base := path.Base(sourceKey)
+	ext := path.Ext(base)
+	prefix := path.Dir(path.Dir(sourceKey))
+	if prefix == "." {
+		prefix = ""
+	}
+	userHash := sha256.Sum256([]byte(attachmentID + "\x00" + userID))
+	return objectstore.JoinKey(prefix, "watermarked", strings.TrimSuffix(base, ext), hex.EncodeToString(userHash[:8])+ext)
+}
+
+// resolveWatermarkedAttachment returns the key to serve to userID: a cached
+// per-user stamped variant, created on first download, or the source key
+// when watermarking is off or the format can't be stamped. The variants are
+// removed with the attachment by deleteAttachmentObjects.
+func (h *UserGrowthHandler) resolveWatermarkedAttachment(ctx context.Context, store objectstore.Store, sourceKey string, userID string, attachmentID string, mimeType string) (string, error) {
+	if !h.cfg.AttachmentWatermarkEnabled || !isWatermarkableAttachment(mimeType, sourceKey) {
+		return sourceKey, nil
+	}
+	variantKey := watermarkVariantKey(sourceKey, attachmentID, userID)
+	if _, err := store.Head(ctx, variantKey); err == nil {
+		return variantKey, nil
+	} else if !errors.Is(err, objectstore.ErrNotFound) {
+		return "", err
+	}
+
+	body, _, err := store.Get(ctx, sourceKey)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	stampedAt := time.Now()
+	token := watermark.Sign(attachmentWatermarkSecret(h.cfg), watermark.Mark{AttachmentID: attachmentID, UserID: userID, StampedAt: stampedAt})
+	stamped, err := watermark.Stamp(payload, h.attachmentWatermarkLabel(userID, stampedAt), token)
+	if err != nil {
+		if errors.Is(err, watermark.ErrUnsupported) {
+			return sourceKey, nil
+		}
+		return "", err
+	}
+	if err := store.Put(ctx, variantKey, stamped, mimeType); err != nil {
+		return "", err
+	}
+	return variantKey, nil
+}
+
+// attachmentWatermarkLabel is the visible stamp: user ID, masked phone and
+// stamp time.
+func (h *UserGrowthHandler) attachmentWatermarkLabel(userID string, stampedAt time.Time) string {
+	parts := []string{userID}
+	if profile, err := h.service.GetUserProfile(userID); err == nil {
+		phone := strings.TrimSpace(profile.Phone)
+		if len(phone) >= 4 {
+			parts = append(parts, "****"+phone[len(phone)-4:])
+		}
+	}
+	parts = append(parts, stampedAt.Format("2006-01-02 15:04"))
+	return strings.Join(parts, " ")
+}
+
+// TraceAttachmentWatermark takes a leaked file, extracts its download
+// marker and returns the account it was stamped for with that account's
+// download records.
+func (h *AdminGrowthHandler) TraceAttachmentWatermark(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "missing file", Data: struct{}{}})
+		return
+	}
+	maxUploadMB := h.cfg.AttachmentUploadMaxMB
+	if maxUploadMB <= 0 {
+		maxUploadMB = 20
+	}
+	src, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: "open file failed", Data: struct{}{}})
+		return
+	}
+	defer src.Close()
+	payload, err := io.ReadAll(io.LimitReader(src, int64(maxUploadMB)*1024*1024))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: "read file failed", Data: struct{}{}})
+		return
+	}
+
+	token, err := watermark.Extract(payload)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40411, Message: "no watermark marker found in file", Data: struct{}{}})
+		return
+	}
+	mark, err := watermark.Parse(attachmentWatermarkSecret(h.cfg), token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40411, Message: "watermark marker signature invalid", Data: struct{}{}})
+		return
+	}
+	downloads, err := h.service.AdminListAttachmentDownloads(mark.AttachmentID, mark.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	trace := model.AttachmentWatermarkTrace{
+		AttachmentID: mark.AttachmentID,
+		UserID:       mark.UserID,
+		StampedAt:    mark.StampedAt.Format(time.RFC3339),
+		Format:       watermark.Detect(payload),
+		Downloads:    downloads,
+	}
+	if profile, err := h.service.GetUserProfile(mark.UserID); err == nil {
+		trace.UserPhone = pii.MaskPhone(profile.Phone)
+	}
+	h.writeOperationLog(c, "NEWS", "TRACE_ATTACHMENT_WATERMARK", "NEWS_ATTACHMENT", mark.AttachmentID, "", mark.UserID, "")
+	c.JSON(http.StatusOK, dto.OK(trace))
+}
+
+// openUserAttachmentObject resolves an attachment for a user download.
+// Files outside the store are mirrored into it when they will be stamped,
+// so doc_fast, Tushare and legacy /uploads attachments are watermarked too.
+func (h *UserGrowthHandler) openUserAttachmentObject(ctx context.Context, info model.AttachmentFileInfo) (objectstore.Store, string, bool, error) {
+	ossCfg := loadOSSUploadConfig(h.service, h.configSecrets, h.cfg.AttachmentUploadMaxMB)
+	mirror := h.cfg.AttachmentWatermarkEnabled && isWatermarkableAttachment(info.MimeType, info.FileURL)
+	return openAttachmentObject(ctx, ossCfg, h.cfg, info.FileURL, info.MimeType, mirror)
+}
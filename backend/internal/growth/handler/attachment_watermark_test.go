@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/objectstore"
+)
+
+func TestWatermarkedAttachmentIsCachedAndTraceable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Config{AttachmentSigningSecret: "attachment-secret", AttachmentWatermarkEnabled: true}
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	userHandler := NewUserGrowthHandler(growthService, cfg)
+	adminHandler := NewAdminGrowthHandler(growthService, cfg)
+
+	img := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	for i := range img.Pix {
+		img.Pix[i] = 0xF0
+	}
+	img.Set(0, 0, color.RGBA{A: 0xFF})
+	var source bytes.Buffer
+	_ = png.Encode(&source, img)
+
+	ctx := context.Background()
+	store := objectstore.NewLocalStore(t.TempDir())
+	sourceKey, _ := objectstore.ContentKey("news/attachments", source.Bytes(), ".png")
+	if err := store.Put(ctx, sourceKey, source.Bytes(), "image/png"); err != nil {
+		t.Fatalf("put source: %v", err)
+	}
+
+	variantKey, err := userHandler.resolveWatermarkedAttachment(ctx, store, sourceKey, "u_1001", "att_001", "image/png")
+	if err != nil || variantKey == sourceKey {
+		t.Fatalf("expected a watermarked variant, got %s %v", variantKey, err)
+	}
+	body, _, err := store.Get(ctx, variantKey)
+	if err != nil {
+		t.Fatalf("get variant: %v", err)
+	}
+	stamped, _ := io.ReadAll(body)
+	body.Close()
+	again, err := userHandler.resolveWatermarkedAttachment(ctx, store, sourceKey, "u_1001", "att_001", "image/png")
+	if err != nil || again != variantKey {
+		t.Fatalf("expected the cached variant to be reused, got %s %v", again, err)
+	}
+	other, _ := userHandler.resolveWatermarkedAttachment(ctx, store, sourceKey, "u_2002", "att_001", "image/png")
+	if other == variantKey {
+		t.Fatalf("expected each user to get their own variant")
+	}
+
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/admin/news/attachments/watermark/trace", adminHandler.TraceAttachmentWatermark)
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile("file", "leaked.png")
+	_, _ = part.Write(stamped)
+	_ = writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/news/attachments/watermark/trace", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Data struct {
+			AttachmentID string        `json:"attachment_id"`
+			UserID       string        `json:"user_id"`
+			Format       string        `json:"format"`
+			Downloads    []interface{} `json:"downloads"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if payload.Data.AttachmentID != "att_001" || payload.Data.UserID != "u_1001" || payload.Data.Format != "PNG" || len(payload.Data.Downloads) == 0 {
+		t.Fatalf("unexpected trace %+v", payload.Data)
+	}
+
+	// A clean copy has no marker.
+	form.Reset()
+	writer = multipart.NewWriter(&form)
+	part, _ = writer.CreateFormFile("file", "clean.png")
+	_, _ = part.Write(source.Bytes())
+	_ = writer.Close()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/news/attachments/watermark/trace", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unmarked file, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRemoteAttachmentIsMirroredAndStamped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	fetches := 0
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write(pdf)
+	}))
+	defer source.Close()
+
+	cfg := config.Config{AttachmentSigningSecret: "attachment-secret", AttachmentWatermarkEnabled: true, AttachmentStorageDir: t.TempDir()}
+	userHandler := NewUserGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), cfg)
+	info := model.AttachmentFileInfo{ArticleID: "article_demo_001", FileName: "report.pdf", FileURL: source.URL + "/files/report.pdf", MimeType: "application/pdf"}
+
+	ctx := context.Background()
+	store, key, ok, err := userHandler.openUserAttachmentObject(ctx, info)
+	if err != nil || !ok {
+		t.Fatalf("expected the remote file to be mirrored, ok=%v err=%v", ok, err)
+	}
+	if _, _, ok, err = userHandler.openUserAttachmentObject(ctx, info); err != nil || !ok || fetches != 1 {
+		t.Fatalf("expected the mirror to be reused, fetches=%d err=%v", fetches, err)
+	}
+	variantKey, err := userHandler.resolveWatermarkedAttachment(ctx, store, key, "u_1001", "nat_df_1_1", info.MimeType)
+	if err != nil || variantKey == key {
+		t.Fatalf("expected a stamped variant of the mirror, got %s %v", variantKey, err)
+	}
+
+	userHandler.cfg.AttachmentWatermarkEnabled = false
+	if _, _, ok, err := userHandler.openUserAttachmentObject(ctx, info); err != nil || ok {
+		t.Fatalf("expected remote links to be redirected when nothing is stamped, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestDeleteNewsAttachmentRemovesStampedCopies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Config{AttachmentWatermarkEnabled: true, AttachmentStorageDir: t.TempDir()}
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	adminHandler := NewAdminGrowthHandler(growthService, cfg)
+	item, err := growthService.AdminGetNewsAttachment("att_001")
+	if err != nil {
+		t.Fatalf("get attachment: %v", err)
+	}
+	if err := growthService.LogAttachmentDownload("u_1001", item.ID, item.ArticleID); err != nil {
+		t.Fatalf("log download: %v", err)
+	}
+
+	ctx := context.Background()
+	store, key, err := attachmentSourceObject(ossUploadConfig{}, cfg, item.FileURL)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	variantKey := watermarkVariantKey(key, item.ID, "u_1001")
+	for _, objectKey := range []string{key, variantKey} {
+		if err := store.Put(ctx, objectKey, []byte("%PDF-1.4\n"), "application/pdf"); err != nil {
+			t.Fatalf("put %s: %v", objectKey, err)
+		}
+	}
+
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.DELETE("/api/v1/admin/news/attachments/:id", adminHandler.DeleteNewsAttachment)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/news/attachments/"+item.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	for _, objectKey := range []string{key, variantKey} {
+		if _, err := store.Head(ctx, objectKey); !errors.Is(err, objectstore.ErrNotFound) {
+			t.Fatalf("expected %s to be deleted, got %v", objectKey, err)
+		}
+	}
+}
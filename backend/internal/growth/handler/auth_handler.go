@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"database/sql"
-	"encoding/csv"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -23,6 +21,10 @@ import (
 	"sercherai/backend/internal/growth/dto"
 	"sercherai/backend/internal/growth/model"
 	"sercherai/backend/internal/platform/auth"
+	"sercherai/backend/internal/platform/pii"
+	"sercherai/backend/internal/platform/secrets"
+	"sercherai/backend/internal/platform/session"
+	"sercherai/backend/internal/platform/streamticket"
 )
 
 type AuthHandler struct {
@@ -37,6 +39,19 @@ type AuthHandler struct {
 	relaxLocalRisk   bool
 	db               *sql.DB
 	redis            *redis.Client
+	sessions         *session.Store
+	configSecrets    *secrets.Keyring
+	operationLogs    authOperationLogWriter
+	approvals        approvalGate
+	streamTickets    *streamticket.Store
+}
+
+// authOperationLogWriter lets auth admin actions land in the shared admin
+// operation log and audit events (and their audit ledger) without the
+// handler owning a repo.
+type authOperationLogWriter interface {
+	AdminCreateOperationLog(module string, action string, targetType string, targetID string, operatorUserID string, beforeValue string, afterValue string, reason string) error
+	AdminCreateAuditEvent(item model.AdminAuditEvent) error
 }
 
 type riskConfig struct {
@@ -48,7 +63,7 @@ type riskConfig struct {
 
 var authIDSequence atomic.Uint64
 
-func NewAuthHandler(jwtSecret string, defaultExpires int, refreshExpires int, failThreshold int, ipFailThreshold int, ipPhoneThreshold int, lockSeconds int, allowMockLogin bool, relaxLocalRisk bool, db *sql.DB, redisClient *redis.Client) *AuthHandler {
+func NewAuthHandler(jwtSecret string, defaultExpires int, refreshExpires int, failThreshold int, ipFailThreshold int, ipPhoneThreshold int, lockSeconds int, allowMockLogin bool, relaxLocalRisk bool, db *sql.DB, redisClient *redis.Client, sessions *session.Store, configSecrets *secrets.Keyring, growth approvalGate) *AuthHandler {
 	if defaultExpires <= 0 {
 		defaultExpires = 86400
 	}
@@ -79,6 +94,11 @@ func NewAuthHandler(jwtSecret string, defaultExpires int, refreshExpires int, fa
 		relaxLocalRisk:   relaxLocalRisk,
 		db:               db,
 		redis:            redisClient,
+		sessions:         sessions,
+		configSecrets:    configSecrets,
+		operationLogs:    growth,
+		approvals:        growth,
+		streamTickets:    streamticket.NewStore(redisClient),
 	}
 }
 
@@ -99,7 +119,12 @@ func (h *AuthHandler) MockLogin(c *gin.Context) {
 	}
 	role := strings.ToUpper(strings.TrimSpace(req.Role))
 
-	token, err := auth.SignToken(h.jwtSecret, req.UserID, role, "ACCESS", time.Duration(expires)*time.Second)
+	sessionID, err := h.createSession(c, req.UserID, role, time.Now().Add(time.Duration(expires)*time.Second))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	token, err := auth.SignSessionToken(h.jwtSecret, req.UserID, role, "ACCESS", sessionID, time.Duration(expires)*time.Second)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
@@ -190,7 +215,7 @@ VALUES (?, ?, ?, ?, 'ACTIVE', 'APPROVED', 'FREE', ?, ?)`,
 	}
 
 	role := resolveRole(userID)
-	accessToken, refreshToken, err := h.issueTokenPair(userID, role, h.defaultExpires)
+	accessToken, refreshToken, _, err := h.issueTokenPair(c, userID, role, h.defaultExpires)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
@@ -305,8 +330,36 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			_, _ = h.db.Exec("UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?", bcryptPass, time.Now(), userID)
 		}
 	}
+	mfa, err := h.loadMFAState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if mfa.challengeRequired() {
+		challengeType := mfaChallengeVerify
+		if !mfa.enabled() {
+			challengeType = mfaChallengeEnroll
+		}
+		challengeToken, err := h.createMFAChallenge(c, userID, challengeType, expires)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusOK, dto.OK(dto.LoginTwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+			ChallengeType:     challengeType,
+			ExpiresIn:         int(mfaChallengeTTL.Seconds()),
+			UserID:            userID,
+		}))
+		_ = h.clearLoginFailures(riskPhone)
+		_ = h.clearRedisFailures(clientIP, riskPhone)
+		h.writeAuthLog(userID, logPhone, "LOGIN", "2FA_REQUIRED", strings.ToLower(challengeType), c)
+		return
+	}
+
 	role := resolveRole(userID)
-	accessToken, refreshToken, err := h.issueTokenPair(userID, role, expires)
+	accessToken, refreshToken, _, err := h.issueTokenPair(c, userID, role, expires)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
@@ -343,7 +396,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	oldTokenID, userID, err := h.loadActiveRefreshToken(req.RefreshToken)
+	oldTokenID, userID, sessionID, err := h.loadActiveRefreshToken(req.RefreshToken)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			c.JSON(http.StatusUnauthorized, dto.APIResponse{Code: 40103, Message: "invalid token", Data: struct{}{}})
@@ -355,20 +408,36 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	}
 
 	role := resolveRole(userID)
-	accessToken, err := auth.SignToken(h.jwtSecret, userID, role, "ACCESS", time.Duration(h.defaultExpires)*time.Second)
+	now := time.Now()
+	expAt := now.Add(time.Duration(h.refreshExpires) * time.Second)
+	if sessionID == "" {
+		// Refresh tokens issued before the session registry carry no session;
+		// the first refresh moves them onto one.
+		sessionID, err = h.createSession(c, userID, role, expAt)
+	} else if h.sessions != nil {
+		err = h.sessions.Extend(sessionID, userID, expAt, c.ClientIP())
+	}
+	if errors.Is(err, session.ErrSessionNotFound) {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Code: 40106, Message: "session revoked", Data: struct{}{}})
+		h.writeAuthLog(userID, "", "REFRESH", "FAILED", "session_revoked", c)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	newRefreshToken, err := auth.SignToken(h.jwtSecret, userID, role, "REFRESH", time.Duration(h.refreshExpires)*time.Second)
+	accessToken, err := auth.SignSessionToken(h.jwtSecret, userID, role, "ACCESS", sessionID, time.Duration(h.defaultExpires)*time.Second)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	newRefreshToken, err := auth.SignSessionToken(h.jwtSecret, userID, role, "REFRESH", sessionID, time.Duration(h.refreshExpires)*time.Second)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
 
 	newTokenID := newID("rt")
-	now := time.Now()
-	expAt := now.Add(time.Duration(h.refreshExpires) * time.Second)
 	tx, err := h.db.Begin()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
@@ -377,9 +446,9 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	defer tx.Rollback()
 
 	_, err = tx.Exec(`
-INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, revoked, created_at)
-VALUES (?, ?, ?, ?, 0, ?)`,
-		newTokenID, userID, sha256Hex(newRefreshToken), expAt, now,
+INSERT INTO refresh_tokens (id, user_id, session_id, token_hash, expires_at, revoked, created_at)
+VALUES (?, ?, ?, ?, ?, 0, ?)`,
+		newTokenID, userID, sessionID, sha256Hex(newRefreshToken), expAt, now,
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
@@ -428,7 +497,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	tokenID, userID, err := h.loadActiveRefreshToken(req.RefreshToken)
+	tokenID, userID, sessionID, err := h.loadActiveRefreshToken(req.RefreshToken)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			c.JSON(http.StatusOK, dto.OK(struct{}{}))
@@ -443,6 +512,12 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	if sessionID != "" && h.sessions != nil {
+		if err := h.sessions.Revoke(userID, sessionID, userID, "logout"); err != nil && !errors.Is(err, session.ErrSessionNotFound) {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
 	c.JSON(http.StatusOK, dto.OK(struct{}{}))
 	h.writeAuthLog(userID, "", "LOGOUT", "SUCCESS", "", c)
 }
@@ -463,10 +538,15 @@ func (h *AuthHandler) LogoutAll(c *gin.Context) {
 		return
 	}
 
-	_, err := h.db.Exec(`
+	var err error
+	if h.sessions != nil {
+		_, err = h.sessions.RevokeAllForUser(userID, userID, "logout_all")
+	} else {
+		_, err = h.db.Exec(`
 UPDATE refresh_tokens
 SET revoked = 1, revoked_at = ?
 WHERE user_id = ? AND revoked = 0`, time.Now(), userID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
@@ -530,11 +610,11 @@ LIMIT ? OFFSET ?`
 		items = append(items, gin.H{
 			"id":         id,
 			"user_id":    userID.String,
-			"phone":      phone.String,
+			"phone":      pii.MaskPhone(phone.String),
 			"action":     act,
 			"status":     st,
 			"reason":     reason.String,
-			"ip":         ip.String,
+			"ip":         pii.MaskIP(ip.String),
 			"user_agent": userAgent.String,
 			"created_at": createdAt.Format(time.RFC3339),
 		})
@@ -542,82 +622,59 @@ LIMIT ? OFFSET ?`
 	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
 }
 
-func (h *AuthHandler) AdminExportLoginLogsCSV(c *gin.Context) {
+// AdminRevealLoginLogPII returns the unmasked phone and IP of one login log
+// entry. Like RevealUserPII it needs a reason and records a PII_REVEALED
+// audit event, and reveals nothing when that write fails.
+func (h *AuthHandler) AdminRevealLoginLogPII(c *gin.Context) {
 	if h.db == nil {
 		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
 		return
 	}
-	action := strings.ToUpper(strings.TrimSpace(c.Query("action")))
-	status := strings.ToUpper(strings.TrimSpace(c.Query("status")))
-	dateFrom := strings.TrimSpace(c.Query("date_from"))
-	dateTo := strings.TrimSpace(c.Query("date_to"))
-	if dateFrom != "" {
-		if _, err := time.Parse("2006-01-02", dateFrom); err != nil {
-			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40003, Message: "invalid date_from format, expect YYYY-MM-DD", Data: struct{}{}})
-			return
-		}
-	}
-	if dateTo != "" {
-		if _, err := time.Parse("2006-01-02", dateTo); err != nil {
-			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40004, Message: "invalid date_to format, expect YYYY-MM-DD", Data: struct{}{}})
-			return
-		}
-	}
-
-	args := []interface{}{}
-	filter := " WHERE 1=1"
-	if action != "" {
-		filter += " AND action = ?"
-		args = append(args, action)
-	}
-	if status != "" {
-		filter += " AND status = ?"
-		args = append(args, status)
+	logID := strings.TrimSpace(c.Param("id"))
+	var req dto.PIIRevealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
 	}
-	if dateFrom != "" {
-		filter += " AND created_at >= ?"
-		args = append(args, dateFrom+" 00:00:00")
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "reason required", Data: struct{}{}})
+		return
 	}
-	if dateTo != "" {
-		filter += " AND created_at < ?"
-		args = append(args, dateTo+" 23:59:59")
+	var userID, phone, ip sql.NullString
+	err := h.db.QueryRow(`SELECT user_id, phone, ip FROM auth_login_logs WHERE id = ?`, logID).Scan(&userID, &phone, &ip)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "login log not found", Data: struct{}{}})
+		return
 	}
-
-	rows, err := h.db.Query(`
-SELECT id, user_id, phone, action, status, reason, ip, user_agent, created_at
-FROM auth_login_logs`+filter+`
-ORDER BY created_at DESC`, args...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	defer rows.Close()
-
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	_ = writer.Write([]string{"id", "user_id", "phone", "action", "status", "reason", "ip", "user_agent", "created_at"})
-	for rows.Next() {
-		var id, act, st string
-		var userID, phone, reason, ip, userAgent sql.NullString
-		var createdAt time.Time
-		if err := rows.Scan(&id, &userID, &phone, &act, &st, &reason, &ip, &userAgent, &createdAt); err != nil {
-			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-			return
-		}
-		_ = writer.Write([]string{
-			id, userID.String, phone.String, act, st, reason.String, ip.String, userAgent.String, createdAt.Format(time.RFC3339),
-		})
+	if h.operationLogs == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "audit log unavailable", Data: struct{}{}})
+		return
 	}
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	operator := contextString(c, "user_id")
+	// Fail closed: no audit event, no PII.
+	if err := h.operationLogs.AdminCreateAuditEvent(model.AdminAuditEvent{
+		EventDomain: "ACCESS",
+		EventType:   "PII_REVEALED",
+		Level:       "WARNING",
+		Module:      "AUTH",
+		ObjectType:  "LOGIN_LOG",
+		ObjectID:    logID,
+		ActorUserID: operator,
+		Title:       "查看登录日志完整个人信息",
+		Summary:     operator + " 查看了登录日志 " + logID + " 的手机号和IP",
+		Detail:      reason,
+		Status:      "OPEN",
+		Metadata:    map[string]any{"fields": "phone,ip", "user_id": userID.String},
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: "record audit event failed", Data: struct{}{}})
 		return
 	}
-
-	fileName := "auth_login_logs.csv"
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", "attachment; filename="+fileName)
-	c.String(http.StatusOK, buf.String())
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": logID, "user_id": userID.String, "phone": phone.String, "ip": ip.String}))
 }
 
 func (h *AuthHandler) AdminGetRiskConfig(c *gin.Context) {
@@ -850,8 +907,8 @@ LIMIT ? OFFSET ?`
 		items = append(items, gin.H{
 			"id":               id,
 			"operator_user_id": operatorUserID,
-			"phone":            phoneVal.String,
-			"ip":               ipVal.String,
+			"phone":            pii.MaskPhone(phoneVal.String),
+			"ip":               pii.MaskIP(ipVal.String),
 			"reason":           reason.String,
 			"created_at":       createdAt.Format(time.RFC3339),
 		})
@@ -994,6 +1051,7 @@ SELECT
   r.description,
   r.status,
   r.built_in,
+  r.require_2fa,
   r.created_at,
   r.updated_at,
   (SELECT COUNT(*) FROM rbac_user_roles ur WHERE ur.role_id = r.id) AS user_count
@@ -1014,7 +1072,7 @@ LIMIT ? OFFSET ?`
 		var item model.AdminRole
 		var description sql.NullString
 		var createdAt, updatedAt time.Time
-		var builtIn int
+		var builtIn, require2FA int
 		if err := rows.Scan(
 			&item.ID,
 			&item.RoleKey,
@@ -1022,6 +1080,7 @@ LIMIT ? OFFSET ?`
 			&description,
 			&item.Status,
 			&builtIn,
+			&require2FA,
 			&createdAt,
 			&updatedAt,
 			&item.UserCount,
@@ -1033,6 +1092,7 @@ LIMIT ? OFFSET ?`
 			item.Description = description.String
 		}
 		item.BuiltIn = builtIn == 1
+		item.Require2FA = require2FA == 1
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 		items = append(items, item)
@@ -1087,50 +1147,74 @@ func (h *AuthHandler) AdminCreateRole(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.db.Begin()
+	if submitForApproval(c, h.approvals, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRBAC,
+		Action:   model.ApprovalActionRoleCreate,
+		TargetID: roleKey,
+		Summary:  fmt.Sprintf("新建角色 %s（%s）：status=%s permissions=%s", roleKey, roleName, status, strings.Join(permissionCodes, ",")),
+	}, req) {
+		return
+	}
+	roleID, err := h.createRole(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		writeAuthActionError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": roleID}))
+}
+
+// createRole inserts a custom role with its permission set; it is also what
+// an approved RBAC_ROLE_CREATE chain runs.
+func (h *AuthHandler) createRole(req dto.AdminRoleUpsertRequest) (string, error) {
+	roleKey := strings.ToUpper(strings.TrimSpace(req.RoleKey))
+	roleName := strings.TrimSpace(req.RoleName)
+	status := strings.ToUpper(strings.TrimSpace(req.Status))
+	if status == "" {
+		status = "ACTIVE"
+	}
+	permissionCodes := normalizeCodeList(req.PermissionCodes, false)
+	if roleKey == "" || roleName == "" || len(permissionCodes) == 0 {
+		return "", authActionError{status: http.StatusBadRequest, code: 40002, message: "role_key, role_name and permission_codes required"}
+	}
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		return "", err
+	}
 	defer tx.Rollback()
 
 	if err := h.assertPermissionsExistTx(tx, permissionCodes); err != nil {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
-		return
+		return "", authActionError{status: http.StatusBadRequest, code: 40002, message: err.Error()}
 	}
 
 	roleID := newID("role")
 	now := time.Now()
 	_, err = tx.Exec(`
-INSERT INTO rbac_roles (id, role_key, role_name, description, status, built_in, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, 0, ?, ?)`,
+INSERT INTO rbac_roles (id, role_key, role_name, description, status, built_in, require_2fa, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?)`,
 		roleID,
 		roleKey,
 		roleName,
 		strings.TrimSpace(req.Description),
 		status,
+		req.Require2FA != nil && *req.Require2FA,
 		now,
 		now,
 	)
 	if err != nil {
 		if isDuplicateEntry(err) {
-			c.JSON(http.StatusConflict, dto.APIResponse{Code: 40902, Message: "role_key already exists", Data: struct{}{}})
-			return
+			return "", authActionError{status: http.StatusConflict, code: 40902, message: "role_key already exists"}
 		}
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return "", err
 	}
 
 	if err := h.replaceRolePermissionsTx(tx, roleID, permissionCodes); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return "", err
 	}
-
 	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return "", err
 	}
-	c.JSON(http.StatusOK, dto.OK(gin.H{"id": roleID}))
+	return roleID, nil
 }
 
 func (h *AuthHandler) AdminUpdateRole(c *gin.Context) {
@@ -1163,10 +1247,37 @@ func (h *AuthHandler) AdminUpdateRole(c *gin.Context) {
 		return
 	}
 
+	if submitForApproval(c, h.approvals, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRBAC,
+		Action:   model.ApprovalActionRoleUpdate,
+		TargetID: roleID,
+		Summary:  fmt.Sprintf("修改角色 %s（%s）：status=%s permissions=%s", roleID, roleName, status, strings.Join(permissionCodes, ",")),
+	}, req) {
+		return
+	}
+	if err := h.updateRole(roleID, req); err != nil {
+		writeAuthActionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+// updateRole replaces a role's attributes and permission set; it is also
+// what an approved RBAC_ROLE_UPDATE chain runs.
+func (h *AuthHandler) updateRole(roleID string, req dto.AdminRoleUpsertRequest) error {
+	roleName := strings.TrimSpace(req.RoleName)
+	status := strings.ToUpper(strings.TrimSpace(req.Status))
+	if status == "" {
+		status = "ACTIVE"
+	}
+	permissionCodes := normalizeCodeList(req.PermissionCodes, false)
+	if roleName == "" || len(permissionCodes) == 0 {
+		return authActionError{status: http.StatusBadRequest, code: 40002, message: "role_name and permission_codes required"}
+	}
+
 	tx, err := h.db.Begin()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
 	defer tx.Rollback()
 
@@ -1174,19 +1285,15 @@ func (h *AuthHandler) AdminUpdateRole(c *gin.Context) {
 	var builtIn int
 	if err := tx.QueryRow("SELECT role_key, built_in FROM rbac_roles WHERE id = ? LIMIT 1", roleID).Scan(&roleKey, &builtIn); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "role not found", Data: struct{}{}})
-			return
+			return authActionError{status: http.StatusNotFound, code: 40404, message: "role not found"}
 		}
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
 	if strings.EqualFold(roleKey, "SUPER_ADMIN") && status != "ACTIVE" {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "SUPER_ADMIN must stay ACTIVE", Data: struct{}{}})
-		return
+		return authActionError{status: http.StatusBadRequest, code: 40002, message: "SUPER_ADMIN must stay ACTIVE"}
 	}
 	if err := h.assertPermissionsExistTx(tx, permissionCodes); err != nil {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
-		return
+		return authActionError{status: http.StatusBadRequest, code: 40002, message: err.Error()}
 	}
 
 	_, err = tx.Exec(`
@@ -1200,18 +1307,18 @@ WHERE id = ?`,
 		roleID,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
-	if err := h.replaceRolePermissionsTx(tx, roleID, permissionCodes); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+	// Older clients do not send require_2fa; leave the flag alone for them.
+	if req.Require2FA != nil {
+		if _, err := tx.Exec("UPDATE rbac_roles SET require_2fa = ? WHERE id = ?", *req.Require2FA, roleID); err != nil {
+			return err
+		}
 	}
-	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+	if err := h.replaceRolePermissionsTx(tx, roleID, permissionCodes); err != nil {
+		return err
 	}
-	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+	return tx.Commit()
 }
 
 func (h *AuthHandler) AdminUpdateRoleStatus(c *gin.Context) {
@@ -1225,25 +1332,35 @@ func (h *AuthHandler) AdminUpdateRoleStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	status := strings.ToUpper(strings.TrimSpace(req.Status))
+	if submitForApproval(c, h.approvals, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRBAC,
+		Action:   model.ApprovalActionRoleStatus,
+		TargetID: roleID,
+		Summary:  fmt.Sprintf("修改角色 %s 状态为 %s", roleID, strings.ToUpper(strings.TrimSpace(req.Status))),
+	}, req) {
+		return
+	}
+	if err := h.updateRoleStatus(roleID, req.Status); err != nil {
+		writeAuthActionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+func (h *AuthHandler) updateRoleStatus(roleID string, status string) error {
+	status = strings.ToUpper(strings.TrimSpace(status))
 	var roleKey string
 	if err := h.db.QueryRow("SELECT role_key FROM rbac_roles WHERE id = ? LIMIT 1", roleID).Scan(&roleKey); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "role not found", Data: struct{}{}})
-			return
+			return authActionError{status: http.StatusNotFound, code: 40404, message: "role not found"}
 		}
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
 	if strings.EqualFold(roleKey, "SUPER_ADMIN") && status != "ACTIVE" {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "SUPER_ADMIN must stay ACTIVE", Data: struct{}{}})
-		return
-	}
-	if _, err := h.db.Exec("UPDATE rbac_roles SET status = ?, updated_at = ? WHERE id = ?", status, time.Now(), roleID); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return authActionError{status: http.StatusBadRequest, code: 40002, message: "SUPER_ADMIN must stay ACTIVE"}
 	}
-	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+	_, err := h.db.Exec("UPDATE rbac_roles SET status = ?, updated_at = ? WHERE id = ?", status, time.Now(), roleID)
+	return err
 }
 
 func (h *AuthHandler) AdminListAdminUsers(c *gin.Context) {
@@ -1302,9 +1419,8 @@ LIMIT ? OFFSET ?`
 			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 			return
 		}
-		if email.Valid {
-			item.Email = email.String
-		}
+		item.Phone = pii.MaskPhone(item.Phone)
+		item.Email = pii.MaskEmail(email.String)
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		items = append(items, item)
 		userIDs = append(userIDs, item.ID)
@@ -1320,6 +1436,11 @@ LIMIT ? OFFSET ?`
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	twoFactorMap, err := h.loadUserTwoFactorMap(userIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
 	for i := range items {
 		roleEntries := roleMap[items[i].ID]
 		items[i].RoleIDs = make([]string, 0, len(roleEntries))
@@ -1333,6 +1454,10 @@ LIMIT ? OFFSET ?`
 		if lastLoginAt, exists := lastLoginMap[items[i].ID]; exists {
 			items[i].LastLogin = lastLoginAt
 		}
+		items[i].TwoFactor = "DISABLED"
+		if status, exists := twoFactorMap[items[i].ID]; exists {
+			items[i].TwoFactor = status
+		}
 	}
 
 	c.JSON(http.StatusOK, dto.OK(gin.H{
@@ -1380,17 +1505,58 @@ func (h *AuthHandler) AdminCreateAdminUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	account := adminUserCreate{
+		Phone:        phone,
+		Email:        strings.TrimSpace(req.Email),
+		PasswordHash: passwordHash,
+		Status:       status,
+		RoleIDs:      roleIDs,
+	}
 
-	tx, err := h.db.Begin()
+	if submitForApproval(c, h.approvals, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRBAC,
+		Action:   model.ApprovalActionAdminUserCreate,
+		TargetID: pii.MaskPhone(phone),
+		Summary:  fmt.Sprintf("新建管理员 %s，角色为 %s", pii.MaskPhone(phone), strings.Join(roleIDs, ",")),
+	}, account) {
+		return
+	}
+	userID, err := h.createAdminUser(account)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		writeAuthActionError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": userID}))
+}
+
+// adminUserCreate is an admin account waiting to be created. It is what an
+// RBAC_ADMIN_USER_CREATE chain stores, so it carries the password hash
+// rather than the password.
+type adminUserCreate struct {
+	Phone        string   `json:"phone"`
+	Email        string   `json:"email"`
+	PasswordHash string   `json:"password_hash"`
+	Status       string   `json:"status"`
+	RoleIDs      []string `json:"role_ids"`
+}
+
+// createAdminUser inserts an admin account with its roles; it is also what
+// an approved RBAC_ADMIN_USER_CREATE chain runs.
+func (h *AuthHandler) createAdminUser(account adminUserCreate) (string, error) {
+	if exists, err := h.phoneExists(account.Phone); err != nil {
+		return "", err
+	} else if exists {
+		return "", authActionError{status: http.StatusConflict, code: 40902, message: "phone already exists"}
+	}
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		return "", err
+	}
 	defer tx.Rollback()
 
-	if err := h.assertRolesExistTx(tx, roleIDs); err != nil {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
-		return
+	if err := h.assertRolesExistTx(tx, account.RoleIDs); err != nil {
+		return "", authActionError{status: http.StatusBadRequest, code: 40002, message: err.Error()}
 	}
 
 	userID := fmt.Sprintf("admin_%d", time.Now().UnixNano())
@@ -1399,26 +1565,23 @@ func (h *AuthHandler) AdminCreateAdminUser(c *gin.Context) {
 INSERT INTO users (id, phone, email, password_hash, status, kyc_status, member_level, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, 'APPROVED', 'VIP3', ?, ?)`,
 		userID,
-		phone,
-		strings.TrimSpace(req.Email),
-		passwordHash,
-		status,
+		account.Phone,
+		account.Email,
+		account.PasswordHash,
+		account.Status,
 		now,
 		now,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return "", err
 	}
-	if err := h.replaceUserRolesTx(tx, userID, roleIDs); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+	if err := h.replaceUserRolesTx(tx, userID, account.RoleIDs); err != nil {
+		return "", err
 	}
 	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return "", err
 	}
-	c.JSON(http.StatusOK, dto.OK(gin.H{"id": userID}))
+	return userID, nil
 }
 
 func (h *AuthHandler) AdminUpdateAdminUserStatus(c *gin.Context) {
@@ -1452,6 +1615,12 @@ func (h *AuthHandler) AdminUpdateAdminUserStatus(c *gin.Context) {
 		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "admin user not found", Data: struct{}{}})
 		return
 	}
+	if strings.ToUpper(req.Status) != "ACTIVE" && h.sessions != nil {
+		if _, err := h.sessions.RevokeAllForUser(userID, operator, "status_"+strings.ToLower(req.Status)); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
 	c.JSON(http.StatusOK, dto.OK(struct{}{}))
 }
 
@@ -1475,36 +1644,42 @@ func (h *AuthHandler) AdminAssignAdminUserRoles(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "role_ids required", Data: struct{}{}})
 		return
 	}
+	if submitForApproval(c, h.approvals, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRBAC,
+		Action:   model.ApprovalActionAdminUserRoles,
+		TargetID: userID,
+		Summary:  fmt.Sprintf("设置管理员 %s 的角色为 %s", userID, strings.Join(roleIDs, ",")),
+	}, req) {
+		return
+	}
+	if err := h.assignAdminUserRoles(userID, roleIDs); err != nil {
+		writeAuthActionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
 
+func (h *AuthHandler) assignAdminUserRoles(userID string, roleIDs []string) error {
 	tx, err := h.db.Begin()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
 	defer tx.Rollback()
 
 	var exists int
 	if err := tx.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", userID).Scan(&exists); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
 	if exists == 0 {
-		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "admin user not found", Data: struct{}{}})
-		return
+		return authActionError{status: http.StatusNotFound, code: 40404, message: "admin user not found"}
 	}
 	if err := h.assertRolesExistTx(tx, roleIDs); err != nil {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
-		return
+		return authActionError{status: http.StatusBadRequest, code: 40002, message: err.Error()}
 	}
 	if err := h.replaceUserRolesTx(tx, userID, roleIDs); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
-	}
-	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
-		return
+		return err
 	}
-	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+	return tx.Commit()
 }
 
 func (h *AuthHandler) AdminResetAdminUserPassword(c *gin.Context) {
@@ -1537,6 +1712,14 @@ func (h *AuthHandler) AdminResetAdminUserPassword(c *gin.Context) {
 		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "admin user not found", Data: struct{}{}})
 		return
 	}
+	if h.sessions != nil {
+		operatorID, _ := c.Get("user_id")
+		operator, _ := operatorID.(string)
+		if _, err := h.sessions.RevokeAllForUser(userID, operator, "password_reset"); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
 	c.JSON(http.StatusOK, dto.OK(struct{}{}))
 }
 
@@ -1657,9 +1840,59 @@ VALUES (?, ?, ?, ?, ?, 'REGISTERED', 'NORMAL')`,
 	if err := h.grantInviteRegisterVIPReward(tx, inviterUserID, inviteeUserID, inviteRecordID, now); err != nil {
 		return err
 	}
+	if err := h.issueInviteeCoupon(tx, inviteeUserID, inviteRecordID, now); err != nil {
+		return err
+	}
 	return nil
 }
 
+// issueInviteeCoupon copies the TEMPLATE coupon named by
+// coupon.invitee.template_code into a single-use INVITE coupon owned by the
+// invitee, valid for coupon.invitee.valid_days. The template itself is never
+// redeemable, so its code is useless to anyone who learns it. The code is
+// derived from the invite record, so a replayed registration does not issue a
+// second coupon.
+func (h *AuthHandler) issueInviteeCoupon(tx *sql.Tx, inviteeUserID string, inviteRecordID string, now time.Time) error {
+	if tx == nil || strings.TrimSpace(inviteeUserID) == "" || strings.TrimSpace(inviteRecordID) == "" {
+		return nil
+	}
+	var templateCode, validDaysRaw sql.NullString
+	_ = tx.QueryRow("SELECT config_value FROM system_configs WHERE config_key = 'coupon.invitee.template_code' LIMIT 1").Scan(&templateCode)
+	templateCode.String = strings.ToUpper(strings.TrimSpace(templateCode.String))
+	if templateCode.String == "" {
+		return nil
+	}
+	_ = tx.QueryRow("SELECT config_value FROM system_configs WHERE config_key = 'coupon.invitee.valid_days' LIMIT 1").Scan(&validDaysRaw)
+	validDays, err := strconv.Atoi(strings.TrimSpace(validDaysRaw.String))
+	if err != nil || validDays <= 0 || validDays > 3650 {
+		validDays = 30
+	}
+	code := "INV" + strings.ToUpper(sha256Hex(inviteRecordID)[:12])
+	_, err = tx.Exec(`
+INSERT INTO membership_coupons (
+	id, code, name, discount_type, discount_value, max_discount, min_amount, valid_from, valid_to,
+	total_limit, per_user_limit, product_ids, member_levels, first_purchase_only,
+	source, owner_user_id, invite_record_id, status, created_by, created_at, updated_at
+)
+SELECT ?, ?, name, discount_type, discount_value, max_discount, min_amount, ?, ?,
+	1, 1, product_ids, member_levels, first_purchase_only,
+	'INVITE', ?, ?, 'ACTIVE', 'system', ?, ?
+FROM membership_coupons
+WHERE code = ? AND status = 'TEMPLATE'
+ON DUPLICATE KEY UPDATE updated_at = updated_at`,
+		newID("cp"),
+		code,
+		now,
+		now.AddDate(0, 0, validDays),
+		inviteeUserID,
+		inviteRecordID,
+		now,
+		now,
+		templateCode.String,
+	)
+	return err
+}
+
 func (h *AuthHandler) loadInviteRegisterVIPDays(tx *sql.Tx) int {
 	const (
 		configKey   = "invite.register.reward.vip_days"
@@ -1859,40 +2092,60 @@ func optionalString(value string) interface{} {
 	return trimmed
 }
 
-func (h *AuthHandler) issueTokenPair(userID string, role string, accessExpires int) (string, string, error) {
-	accessToken, err := auth.SignToken(h.jwtSecret, userID, role, "ACCESS", time.Duration(accessExpires)*time.Second)
+func (h *AuthHandler) issueTokenPair(c *gin.Context, userID string, role string, accessExpires int) (string, string, string, error) {
+	now := time.Now()
+	refreshExpiresAt := now.Add(time.Duration(h.refreshExpires) * time.Second)
+	sessionID, err := h.createSession(c, userID, role, refreshExpiresAt)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
-	refreshToken, err := auth.SignToken(h.jwtSecret, userID, role, "REFRESH", time.Duration(h.refreshExpires)*time.Second)
+	accessToken, err := auth.SignSessionToken(h.jwtSecret, userID, role, "ACCESS", sessionID, time.Duration(accessExpires)*time.Second)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
+	}
+	refreshToken, err := auth.SignSessionToken(h.jwtSecret, userID, role, "REFRESH", sessionID, time.Duration(h.refreshExpires)*time.Second)
+	if err != nil {
+		return "", "", "", err
 	}
 
-	now := time.Now()
 	_, err = h.db.Exec(`
-INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, revoked, created_at)
-VALUES (?, ?, ?, ?, 0, ?)`,
-		newID("rt"), userID, sha256Hex(refreshToken), now.Add(time.Duration(h.refreshExpires)*time.Second), now,
+INSERT INTO refresh_tokens (id, user_id, session_id, token_hash, expires_at, revoked, created_at)
+VALUES (?, ?, ?, ?, ?, 0, ?)`,
+		newID("rt"), userID, sessionID, sha256Hex(refreshToken), refreshExpiresAt, now,
 	)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
-	return accessToken, refreshToken, nil
+	return accessToken, refreshToken, sessionID, nil
 }
 
-func (h *AuthHandler) loadActiveRefreshToken(token string) (string, string, error) {
+// createSession registers a login session that lives as long as its refresh
+// chain. Without a session store the id is still used as the token jti.
+func (h *AuthHandler) createSession(c *gin.Context, userID string, role string, expiresAt time.Time) (string, error) {
+	sessionID := session.NewID()
+	if h.sessions == nil {
+		return sessionID, nil
+	}
+	item := session.Session{ID: sessionID, UserID: userID, Role: role, Device: c.Request.UserAgent(), IP: c.ClientIP()}
+	if err := h.sessions.Create(item, expiresAt); err != nil {
+		return "", err
+	}
+	return sessionID, nil
+}
+
+func (h *AuthHandler) loadActiveRefreshToken(token string) (string, string, string, error) {
 	var id, userID string
+	var sessionID sql.NullString
 	err := h.db.QueryRow(`
-SELECT id, user_id
+SELECT id, user_id, session_id
 FROM refresh_tokens
 WHERE token_hash = ? AND revoked = 0 AND expires_at > ?
 LIMIT 1`, sha256Hex(token), time.Now(),
-	).Scan(&id, &userID)
+	).Scan(&id, &userID, &sessionID)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
-	return id, userID, nil
+	return id, userID, sessionID.String, nil
 }
 
 func (h *AuthHandler) checkLoginLock(phone string) (string, bool, error) {
@@ -2238,6 +2491,30 @@ GROUP BY user_id`, args...)
 	return result, nil
 }
 
+func (h *AuthHandler) loadUserTwoFactorMap(userIDs []string) (map[string]string, error) {
+	result := map[string]string{}
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	args := make([]interface{}, 0, len(userIDs))
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+	rows, err := h.db.Query("SELECT user_id, status FROM auth_user_mfa WHERE user_id IN ("+sqlPlaceholders(len(userIDs))+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID, status string
+		if err := rows.Scan(&userID, &status); err != nil {
+			return nil, err
+		}
+		result[userID] = status
+	}
+	return result, rows.Err()
+}
+
 func (h *AuthHandler) assertPermissionsExistTx(tx *sql.Tx, permissionCodes []string) error {
 	if len(permissionCodes) == 0 {
 		return errors.New("permission_codes required")
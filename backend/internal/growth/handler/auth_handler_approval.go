@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+// authActionError carries the HTTP status and business code of an RBAC
+// change that failed validation, so the same change can be run from a
+// request handler or from an approved chain.
+type authActionError struct {
+	status  int
+	code    int
+	message string
+}
+
+func (e authActionError) Error() string {
+	return e.message
+}
+
+func writeAuthActionError(c *gin.Context, err error) {
+	var actionErr authActionError
+	if errors.As(err, &actionErr) {
+		c.JSON(actionErr.status, dto.APIResponse{Code: actionErr.code, Message: actionErr.message, Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+}
+
+// ApprovalExecutors returns the executors for RBAC changes parked behind
+// the RBAC approval policy. The router registers them on the admin growth
+// handler, which owns the approval endpoints.
+func (h *AuthHandler) ApprovalExecutors() map[string]ApprovalExecutor {
+	withDB := func(apply ApprovalExecutor) ApprovalExecutor {
+		return func(ctx context.Context, item model.ApprovalRequest) error {
+			if h.db == nil {
+				return errors.New("auth db unavailable")
+			}
+			return apply(ctx, item)
+		}
+	}
+	return map[string]ApprovalExecutor{
+		model.ApprovalActionRoleCreate: withDB(func(ctx context.Context, item model.ApprovalRequest) error {
+			var req dto.AdminRoleUpsertRequest
+			if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+				return err
+			}
+			_, err := h.createRole(req)
+			return err
+		}),
+		model.ApprovalActionRoleUpdate: withDB(func(ctx context.Context, item model.ApprovalRequest) error {
+			var req dto.AdminRoleUpsertRequest
+			if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+				return err
+			}
+			return h.updateRole(item.TargetID, req)
+		}),
+		model.ApprovalActionRoleStatus: withDB(func(ctx context.Context, item model.ApprovalRequest) error {
+			var req dto.AdminUpdateAccountStatusRequest
+			if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+				return err
+			}
+			return h.updateRoleStatus(item.TargetID, req.Status)
+		}),
+		model.ApprovalActionAdminUserRoles: withDB(func(ctx context.Context, item model.ApprovalRequest) error {
+			var req dto.AdminAssignRolesRequest
+			if err := json.Unmarshal([]byte(item.Payload), &req); err != nil {
+				return err
+			}
+			return h.assignAdminUserRoles(item.TargetID, normalizeCodeList(req.RoleIDs, false))
+		}),
+		model.ApprovalActionAdminUserCreate: withDB(func(ctx context.Context, item model.ApprovalRequest) error {
+			var account adminUserCreate
+			if err := json.Unmarshal([]byte(item.Payload), &account); err != nil {
+				return err
+			}
+			_, err := h.createAdminUser(account)
+			return err
+		}),
+	}
+}
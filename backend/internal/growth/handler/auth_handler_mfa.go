@@ -0,0 +1,688 @@
+package handler
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/platform/auth"
+	"sercherai/backend/internal/platform/session"
+)
+
+const (
+	mfaStatusPending = "PENDING"
+	mfaStatusEnabled = "ENABLED"
+
+	mfaChallengeVerify = "VERIFY"
+	mfaChallengeEnroll = "ENROLL"
+
+	mfaIssuer              = "SercherAI"
+	mfaChallengeTTL        = 5 * time.Minute
+	mfaChallengeMaxAttempt = 5
+	mfaRecoveryCodeCount   = 10
+	mfaUserMaxFailures     = 5
+	mfaUserLockDuration    = 15 * time.Minute
+)
+
+var (
+	errInvalidMFACode     = errors.New("invalid 2fa code")
+	errMFAChallengeClosed = errors.New("2fa challenge expired or already used")
+	errMFANotPending      = errors.New("2fa enrollment not started")
+	errMFALocked          = errors.New("too many failed 2fa attempts")
+)
+
+type mfaState struct {
+	userID         string
+	hasRole        bool
+	required       bool
+	status         string
+	secret         string
+	lastStep       int64
+	failedAttempts int
+	lockedUntil    time.Time
+}
+
+func (s mfaState) enabled() bool {
+	return s.status == mfaStatusEnabled
+}
+
+func (s mfaState) locked(now time.Time) bool {
+	return s.lockedUntil.After(now)
+}
+
+// challengeRequired is true for accounts that hold an RBAC role and either
+// enrolled in 2FA or belong to a role that requires it. Consumer accounts
+// without roles keep the single-step login.
+func (s mfaState) challengeRequired() bool {
+	return s.hasRole && (s.enabled() || s.required)
+}
+
+type mfaChallenge struct {
+	id            string
+	userID        string
+	challengeType string
+	expiresIn     int
+}
+
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req dto.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	challenge, err := h.loadMFAChallenge(req.ChallengeToken)
+	if err != nil {
+		h.writeMFAChallengeError(c, "", err)
+		return
+	}
+	state, err := h.loadMFAState(challenge.userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+
+	var recoveryCodes []string
+	if challenge.challengeType == mfaChallengeEnroll {
+		recoveryCodes, err = h.activateMFA(challenge.userID, state, req.Code)
+	} else {
+		err = h.verifyMFACode(challenge.userID, state, req.Code, req.RecoveryCode)
+	}
+	if err != nil {
+		if errors.Is(err, errInvalidMFACode) || errors.Is(err, errMFANotPending) {
+			_, _ = h.db.Exec("UPDATE auth_mfa_challenges SET attempts = attempts + 1 WHERE id = ?", challenge.id)
+		}
+		h.writeMFAChallengeError(c, challenge.userID, err)
+		return
+	}
+
+	result, err := h.db.Exec("UPDATE auth_mfa_challenges SET consumed_at = ? WHERE id = ? AND consumed_at IS NULL", time.Now(), challenge.id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		h.writeMFAChallengeError(c, challenge.userID, errMFAChallengeClosed)
+		return
+	}
+
+	var status string
+	if err := h.db.QueryRow("SELECT status FROM users WHERE id = ?", challenge.userID).Scan(&status); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if strings.ToUpper(status) != "ACTIVE" {
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40303, Message: "user status invalid", Data: struct{}{}})
+		h.writeAuthLog(challenge.userID, "", "LOGIN", "FAILED", "status_not_active", c)
+		return
+	}
+
+	role := resolveRole(challenge.userID)
+	accessToken, refreshToken, sessionID, err := h.issueTokenPair(c, challenge.userID, role, challenge.expiresIn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if h.sessions != nil {
+		if _, err := h.sessions.MarkMFAVerified(sessionID); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.OK(dto.LoginResponse{
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		TokenType:     "Bearer",
+		ExpiresIn:     challenge.expiresIn,
+		UserID:        challenge.userID,
+		Role:          role,
+		RecoveryCodes: recoveryCodes,
+	}))
+	h.writeAuthLog(challenge.userID, "", "LOGIN", "SUCCESS", "2fa_"+strings.ToLower(challenge.challengeType), c)
+}
+
+// LoginTwoFactorEnroll hands out the TOTP secret to an account that must
+// enroll before its first login completes; the code is then submitted to
+// LoginTwoFactor with the same challenge token.
+func (h *AuthHandler) LoginTwoFactorEnroll(c *gin.Context) {
+	var req dto.LoginTwoFactorEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	challenge, err := h.loadMFAChallenge(req.ChallengeToken)
+	if err != nil {
+		h.writeMFAChallengeError(c, "", err)
+		return
+	}
+	if challenge.challengeType != mfaChallengeEnroll {
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40901, Message: "2fa already enabled", Data: struct{}{}})
+		return
+	}
+	h.respondMFAEnrollment(c, challenge.userID)
+}
+
+func (h *AuthHandler) GetTwoFactorStatus(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := contextString(c, "user_id")
+	state, err := h.loadMFAState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	var remaining int
+	if err := h.db.QueryRow("SELECT COUNT(*) FROM auth_mfa_recovery_codes WHERE user_id = ? AND used_at IS NULL", userID).Scan(&remaining); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	stepUpActive := false
+	if h.sessions != nil {
+		stepUpActive, err = h.sessions.MFAVerifiedWithin(contextString(c, "session_id"), session.StepUpWindow)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
+	status := state.status
+	if status == "" {
+		status = "DISABLED"
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{
+		"status":                   status,
+		"required":                 state.hasRole && state.required,
+		"recovery_codes_remaining": remaining,
+		"step_up_active":           stepUpActive,
+	}))
+}
+
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	h.respondMFAEnrollment(c, contextString(c, "user_id"))
+}
+
+func (h *AuthHandler) ActivateTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := contextString(c, "user_id")
+	state, err := h.loadMFAState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	recoveryCodes, err := h.activateMFA(userID, state, req.Code)
+	if err != nil {
+		h.writeMFACodeError(c, userID, "2FA_ACTIVATE", err)
+		return
+	}
+	verifiedUntil := h.markSessionMFAVerified(c)
+	c.JSON(http.StatusOK, dto.OK(gin.H{"recovery_codes": recoveryCodes, "step_up_until": verifiedUntil}))
+	h.writeAuthLog(userID, "", "2FA_ACTIVATE", "SUCCESS", "", c)
+}
+
+// VerifyTwoFactor is the step-up check: a fresh code unlocks operations guarded
+// by middleware.StepUpRequired on the current session for session.StepUpWindow.
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := contextString(c, "user_id")
+	state, err := h.loadMFAState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if !state.enabled() {
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40307, Message: "2fa enrollment required", Data: struct{}{}})
+		return
+	}
+	if err := h.verifyMFACode(userID, state, req.Code, req.RecoveryCode); err != nil {
+		h.writeMFACodeError(c, userID, "2FA_STEP_UP", err)
+		return
+	}
+	verifiedUntil := h.markSessionMFAVerified(c)
+	c.JSON(http.StatusOK, dto.OK(gin.H{"step_up_until": verifiedUntil}))
+	h.writeAuthLog(userID, "", "2FA_STEP_UP", "SUCCESS", "", c)
+}
+
+func (h *AuthHandler) RegenerateTwoFactorRecoveryCodes(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := contextString(c, "user_id")
+	state, err := h.loadMFAState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if !state.enabled() {
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40307, Message: "2fa enrollment required", Data: struct{}{}})
+		return
+	}
+	// A recovery code cannot mint new recovery codes; that needs the device.
+	if err := h.verifyMFACode(userID, state, req.Code, ""); err != nil {
+		h.writeMFACodeError(c, userID, "2FA_RECOVERY_CODES", err)
+		return
+	}
+	tx, err := h.db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	defer tx.Rollback()
+	recoveryCodes, err := replaceMFARecoveryCodesTx(tx, userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"recovery_codes": recoveryCodes}))
+	h.writeAuthLog(userID, "", "2FA_RECOVERY_CODES", "SUCCESS", "", c)
+}
+
+func (h *AuthHandler) AdminResetUserTwoFactor(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := strings.TrimSpace(c.Param("id"))
+	var req dto.AdminResetTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "reason required", Data: struct{}{}})
+		return
+	}
+	operator := contextString(c, "user_id")
+	if strings.EqualFold(operator, userID) {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "cannot reset 2fa of current account", Data: struct{}{}})
+		return
+	}
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	defer tx.Rollback()
+	var beforeStatus string
+	if err := tx.QueryRow("SELECT status FROM auth_user_mfa WHERE user_id = ? FOR UPDATE", userID).Scan(&beforeStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40404, Message: "2fa not enrolled", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if _, err := tx.Exec("DELETE FROM auth_user_mfa WHERE user_id = ?", userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if _, err := tx.Exec("DELETE FROM auth_mfa_recovery_codes WHERE user_id = ?", userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if _, err := tx.Exec("UPDATE auth_mfa_challenges SET consumed_at = ? WHERE user_id = ? AND consumed_at IS NULL", time.Now(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	// Sessions that passed the old second factor must not outlive it.
+	if h.sessions != nil {
+		if _, err := h.sessions.RevokeAllForUser(userID, operator, "2fa_reset"); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+	h.writeAuthLog(userID, "", "2FA_RESET", "SUCCESS", reason, c)
+	if h.operationLogs != nil {
+		_ = h.operationLogs.AdminCreateOperationLog("ACCESS", "RESET_2FA", "USER", userID, operator, beforeStatus, "DISABLED", reason)
+	}
+}
+
+func (h *AuthHandler) respondMFAEnrollment(c *gin.Context, userID string) {
+	state, err := h.loadMFAState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if state.enabled() {
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40901, Message: "2fa already enabled", Data: struct{}{}})
+		return
+	}
+	var phone string
+	var email sql.NullString
+	if err := h.db.QueryRow("SELECT phone, email FROM users WHERE id = ?", userID).Scan(&phone, &email); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	account := phone
+	if strings.TrimSpace(email.String) != "" {
+		account = strings.TrimSpace(email.String)
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	sealed, err := h.configSecrets.Seal(mfaSecretName(userID), secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	now := time.Now()
+	_, err = h.db.Exec(`
+INSERT INTO auth_user_mfa (user_id, secret, status, last_used_step, created_at, updated_at)
+VALUES (?, ?, 'PENDING', 0, ?, ?)
+ON DUPLICATE KEY UPDATE secret = VALUES(secret), status = 'PENDING', last_used_step = 0, updated_at = VALUES(updated_at)`,
+		userID, sealed, now, now,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(dto.TwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(mfaIssuer, account, secret),
+	}))
+	h.writeAuthLog(userID, "", "2FA_ENROLL", "SUCCESS", "", c)
+}
+
+func (h *AuthHandler) loadMFAState(userID string) (mfaState, error) {
+	state := mfaState{userID: userID}
+	var roleCount, required int
+	err := h.db.QueryRow(`
+SELECT COUNT(*), COALESCE(MAX(r.require_2fa), 0)
+FROM rbac_user_roles ur
+JOIN rbac_roles r ON r.id = ur.role_id
+WHERE ur.user_id = ? AND r.status = 'ACTIVE'`, userID).Scan(&roleCount, &required)
+	if err != nil {
+		return state, err
+	}
+	state.hasRole = roleCount > 0
+	state.required = required > 0
+
+	var lockedUntil sql.NullTime
+	err = h.db.QueryRow("SELECT secret, status, last_used_step, failed_attempts, locked_until FROM auth_user_mfa WHERE user_id = ?", userID).Scan(&state.secret, &state.status, &state.lastStep, &state.failedAttempts, &lockedUntil)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return state, err
+	}
+	if lockedUntil.Valid {
+		state.lockedUntil = lockedUntil.Time
+	}
+	return state, nil
+}
+
+// mfaSecretName binds a sealed TOTP secret to its user, so a secret copied to
+// another row does not open.
+func mfaSecretName(userID string) string {
+	return "auth_user_mfa." + strings.TrimSpace(userID)
+}
+
+func (h *AuthHandler) checkTOTP(state mfaState, code string) (int64, error) {
+	secret, err := h.configSecrets.Open(mfaSecretName(state.userID), state.secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := auth.VerifyTOTP(secret, code, time.Now())
+	if !ok || step <= state.lastStep {
+		return 0, errInvalidMFACode
+	}
+	return step, nil
+}
+
+// verifyMFACode checks a TOTP code or, failing that, burns one recovery code.
+// The accepted TOTP step is stored so the same code cannot be replayed within
+// its validity window. Wrong codes count against the user across challenges
+// and sessions; mfaUserMaxFailures of them lock 2FA for mfaUserLockDuration.
+func (h *AuthHandler) verifyMFACode(userID string, state mfaState, code string, recoveryCode string) error {
+	if !state.enabled() {
+		return errInvalidMFACode
+	}
+	now := time.Now()
+	if state.locked(now) {
+		return errMFALocked
+	}
+	err := h.checkMFACode(userID, state, code, recoveryCode)
+	if errors.Is(err, errInvalidMFACode) {
+		if recordErr := h.recordMFAFailure(userID, now); recordErr != nil {
+			return recordErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if state.failedAttempts > 0 || !state.lockedUntil.IsZero() {
+		_, err = h.db.Exec("UPDATE auth_user_mfa SET failed_attempts = 0, locked_until = NULL WHERE user_id = ?", userID)
+	}
+	return err
+}
+
+// recordMFAFailure counts one wrong code. locked_until is assigned before
+// failed_attempts because MySQL evaluates SET assignments left to right.
+func (h *AuthHandler) recordMFAFailure(userID string, now time.Time) error {
+	_, err := h.db.Exec(`
+UPDATE auth_user_mfa
+SET locked_until = CASE WHEN failed_attempts + 1 >= ? THEN ? ELSE locked_until END,
+    failed_attempts = CASE WHEN failed_attempts + 1 >= ? THEN 0 ELSE failed_attempts + 1 END,
+    updated_at = ?
+WHERE user_id = ?`, mfaUserMaxFailures, now.Add(mfaUserLockDuration), mfaUserMaxFailures, now, userID)
+	return err
+}
+
+func (h *AuthHandler) checkMFACode(userID string, state mfaState, code string, recoveryCode string) error {
+	if strings.TrimSpace(code) != "" {
+		step, err := h.checkTOTP(state, code)
+		if err != nil {
+			return err
+		}
+		result, err := h.db.Exec("UPDATE auth_user_mfa SET last_used_step = ?, updated_at = ? WHERE user_id = ? AND last_used_step < ?", step, time.Now(), userID, step)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return errInvalidMFACode
+		}
+		return nil
+	}
+	normalized := normalizeRecoveryCode(recoveryCode)
+	if normalized == "" {
+		return errInvalidMFACode
+	}
+	result, err := h.db.Exec("UPDATE auth_mfa_recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL", time.Now(), userID, sha256Hex(normalized))
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return errInvalidMFACode
+	}
+	return nil
+}
+
+func (h *AuthHandler) activateMFA(userID string, state mfaState, code string) ([]string, error) {
+	if state.status != mfaStatusPending {
+		return nil, errMFANotPending
+	}
+	step, err := h.checkTOTP(state, code)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tx, err := h.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec(`
+UPDATE auth_user_mfa
+SET status = 'ENABLED', last_used_step = ?, enabled_at = ?, updated_at = ?
+WHERE user_id = ? AND status = 'PENDING'`, step, now, now, userID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, errMFANotPending
+	}
+	recoveryCodes, err := replaceMFARecoveryCodesTx(tx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+func (h *AuthHandler) createMFAChallenge(c *gin.Context, userID string, challengeType string, expiresIn int) (string, error) {
+	token, err := randomMFAToken("mfac_", 24)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	_, err = h.db.Exec(`
+INSERT INTO auth_mfa_challenges (id, user_id, token_hash, challenge_type, expires_in, attempts, ip, expires_at, created_at)
+VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?)`,
+		newID("mfac"), userID, sha256Hex(token), challengeType, expiresIn, c.ClientIP(), now.Add(mfaChallengeTTL), now,
+	)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (h *AuthHandler) loadMFAChallenge(token string) (mfaChallenge, error) {
+	var item mfaChallenge
+	err := h.db.QueryRow(`
+SELECT id, user_id, challenge_type, expires_in
+FROM auth_mfa_challenges
+WHERE token_hash = ? AND consumed_at IS NULL AND expires_at > ? AND attempts < ?
+LIMIT 1`, sha256Hex(strings.TrimSpace(token)), time.Now(), mfaChallengeMaxAttempt,
+	).Scan(&item.id, &item.userID, &item.challengeType, &item.expiresIn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return item, errMFAChallengeClosed
+	}
+	return item, err
+}
+
+func (h *AuthHandler) markSessionMFAVerified(c *gin.Context) string {
+	if h.sessions == nil {
+		return ""
+	}
+	until, err := h.sessions.MarkMFAVerified(contextString(c, "session_id"))
+	if err != nil {
+		return ""
+	}
+	return until.Format(time.RFC3339)
+}
+
+func (h *AuthHandler) writeMFAChallengeError(c *gin.Context, userID string, err error) {
+	switch {
+	case errors.Is(err, errMFAChallengeClosed):
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Code: 40103, Message: err.Error(), Data: struct{}{}})
+		h.writeAuthLog(userID, "", "LOGIN_2FA", "FAILED", "challenge_closed", c)
+	default:
+		h.writeMFACodeError(c, userID, "LOGIN_2FA", err)
+	}
+}
+
+func (h *AuthHandler) writeMFACodeError(c *gin.Context, userID string, action string, err error) {
+	switch {
+	case errors.Is(err, errInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Code: 40107, Message: err.Error(), Data: struct{}{}})
+		h.writeAuthLog(userID, "", action, "FAILED", "invalid_code", c)
+	case errors.Is(err, errMFANotPending):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, errMFALocked):
+		c.JSON(http.StatusTooManyRequests, dto.APIResponse{Code: 42901, Message: err.Error(), Data: struct{}{}})
+		h.writeAuthLog(userID, "", action, "FAILED", "locked", c)
+	default:
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	}
+}
+
+func replaceMFARecoveryCodesTx(tx *sql.Tx, userID string, now time.Time) ([]string, error) {
+	if _, err := tx.Exec("DELETE FROM auth_mfa_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		raw, err := randomMFAToken("", 5)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+		if _, err := tx.Exec(`
+INSERT INTO auth_mfa_recovery_codes (id, user_id, code_hash, created_at)
+VALUES (?, ?, ?, ?)`, newID("mfar"), userID, sha256Hex(normalizeRecoveryCode(code)), now); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode lets users type recovery codes with or without the
+// dash and in any case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+func randomMFAToken(prefix string, size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/platform/auth"
+)
+
+const testMFASecret = "JBSWY3DPEHPK3PXP"
+
+func newTwoFactorRouterForTest(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	authHandler := &AuthHandler{db: db}
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/auth/2fa/verify", authHandler.VerifyTwoFactor)
+	return router, mock
+}
+
+func expectMFAState(mock sqlmock.Sqlmock, failedAttempts int, lockedUntil interface{}) {
+	mock.ExpectQuery(regexp.QuoteMeta("FROM rbac_user_roles ur")).
+		WithArgs("admin_001").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "required"}).AddRow(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT secret, status, last_used_step, failed_attempts, locked_until FROM auth_user_mfa WHERE user_id = ?")).
+		WithArgs("admin_001").
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "status", "last_used_step", "failed_attempts", "locked_until"}).
+			AddRow(testMFASecret, mfaStatusEnabled, 0, failedAttempts, lockedUntil))
+}
+
+func TestVerifyTwoFactorCountsWrongCodesAndLocksOut(t *testing.T) {
+	router, mock := newTwoFactorRouterForTest(t)
+
+	expectMFAState(mock, mfaUserMaxFailures-1, nil)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_user_mfa\nSET locked_until = CASE WHEN failed_attempts + 1 >= ? THEN ? ELSE locked_until END")).
+		WithArgs(mfaUserMaxFailures, sqlmock.AnyArg(), mfaUserMaxFailures, sqlmock.AnyArg(), "admin_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth_login_logs")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/auth/2fa/verify", `{"code":"12345"}`)
+	if status != http.StatusUnauthorized || code != 40107 {
+		t.Fatalf("expected a wrong code to be rejected with 40107, got status=%d code=%d", status, code)
+	}
+
+	// Once locked, even a valid code is refused without being checked.
+	validCode, err := auth.TOTPCode(testMFASecret, time.Now().Unix()/30)
+	if err != nil {
+		t.Fatalf("TOTPCode() error = %v", err)
+	}
+	expectMFAState(mock, 0, time.Now().Add(mfaUserLockDuration))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth_login_logs")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/auth/2fa/verify", `{"code":"`+validCode+`"}`)
+	if status != http.StatusTooManyRequests || code != 42901 {
+		t.Fatalf("expected a locked user to get 42901, got status=%d code=%d", status, code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyTwoFactorClearsFailuresAfterLockExpires(t *testing.T) {
+	router, mock := newTwoFactorRouterForTest(t)
+	validCode, err := auth.TOTPCode(testMFASecret, time.Now().Unix()/30)
+	if err != nil {
+		t.Fatalf("TOTPCode() error = %v", err)
+	}
+
+	expectMFAState(mock, 2, time.Now().Add(-time.Minute))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_user_mfa SET last_used_step = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_user_mfa SET failed_attempts = 0, locked_until = NULL WHERE user_id = ?")).
+		WithArgs("admin_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth_login_logs")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/auth/2fa/verify", `{"code":"`+validCode+`"}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("expected the code to pass once the lock expired, got status=%d code=%d", status, code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/platform/pii"
+	"sercherai/backend/internal/platform/session"
+)
+
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	if h.sessions == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := contextString(c, "user_id")
+	items, err := h.sessions.ListByUser(userID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	currentID := contextString(c, "session_id")
+	for i := range items {
+		items[i].Current = items[i].ID == currentID
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items}))
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	if h.sessions == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := contextString(c, "user_id")
+	sessionID := strings.TrimSpace(c.Param("id"))
+	if err := h.sessions.Revoke(userID, sessionID, userID, "user_revoked"); err != nil {
+		if errors.Is(err, session.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "session not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+	h.writeAuthLog(userID, "", "REVOKE_SESSION", "SUCCESS", sessionID, c)
+}
+
+func (h *AuthHandler) AdminListUserSessions(c *gin.Context) {
+	if h.sessions == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	includeInactive := strings.EqualFold(strings.TrimSpace(c.Query("include_inactive")), "true")
+	items, err := h.sessions.ListByUser(strings.TrimSpace(c.Param("id")), includeInactive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	for i := range items {
+		items[i].IP = pii.MaskIP(items[i].IP)
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items}))
+}
+
+func (h *AuthHandler) AdminRevokeUserSession(c *gin.Context) {
+	if h.sessions == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "auth db unavailable", Data: struct{}{}})
+		return
+	}
+	userID := strings.TrimSpace(c.Param("id"))
+	sessionID := strings.TrimSpace(c.Param("session_id"))
+	operator := contextString(c, "user_id")
+	if err := h.sessions.Revoke(userID, sessionID, operator, "admin_revoked"); err != nil {
+		if errors.Is(err, session.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "session not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+	h.writeAuthLog(userID, "", "ADMIN_REVOKE_SESSION", "SUCCESS", operator+":"+sessionID, c)
+}
+
+func contextString(c *gin.Context, key string) string {
+	value, _ := c.Get(key)
+	text, _ := value.(string)
+	return text
+}
@@ -1,6 +1,15 @@
 package handler
 
-import "testing"
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/platform/auth"
+)
 
 func TestShouldBypassRiskControl(t *testing.T) {
 	handler := &AuthHandler{relaxLocalRisk: true}
@@ -32,3 +41,82 @@ func TestShouldBypassRiskControlDisabled(t *testing.T) {
 		t.Fatal("expected local risk control bypass to stay disabled")
 	}
 }
+
+func TestCheckTOTPRejectsReplayedStep(t *testing.T) {
+	// RFC 6238 appendix B SHA1 seed "12345678901234567890".
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Now()
+	step := now.Unix() / 30
+	code, err := auth.TOTPCode(secret, step)
+	if err != nil {
+		t.Fatalf("TOTPCode() error = %v", err)
+	}
+	if vector, _ := auth.TOTPCode(secret, 1); vector != "287082" {
+		t.Fatalf("TOTPCode() at T=59 = %s, want RFC 6238 vector 287082", vector)
+	}
+
+	handler := &AuthHandler{}
+	state := mfaState{status: mfaStatusEnabled, secret: secret, lastStep: step - 2}
+	if got, err := handler.checkTOTP(state, code); err != nil || got != step {
+		t.Fatalf("checkTOTP() = %d, %v; want step %d", got, err, step)
+	}
+	state.lastStep = step
+	if _, err := handler.checkTOTP(state, code); !errors.Is(err, errInvalidMFACode) {
+		t.Fatalf("expected replayed code to be rejected, got %v", err)
+	}
+}
+
+func TestMFAChallengeRequiredOnlyForRoleHolders(t *testing.T) {
+	cases := []struct {
+		name  string
+		state mfaState
+		want  bool
+	}{
+		{name: "consumer enrolled", state: mfaState{status: mfaStatusEnabled}, want: false},
+		{name: "admin enrolled", state: mfaState{hasRole: true, status: mfaStatusEnabled}, want: true},
+		{name: "admin required not enrolled", state: mfaState{hasRole: true, required: true}, want: true},
+		{name: "admin pending not required", state: mfaState{hasRole: true, status: mfaStatusPending}, want: false},
+	}
+	for _, tc := range cases {
+		if got := tc.state.challengeRequired(); got != tc.want {
+			t.Fatalf("%s: challengeRequired() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+	if normalizeRecoveryCode(" AB12C-de34F ") != "ab12cde34f" {
+		t.Fatalf("recovery codes should be matched case and dash insensitively")
+	}
+}
+
+func TestIssueInviteeCouponCopiesOnlyTemplateCoupons(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	handler := &AuthHandler{db: db}
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("config_key = 'coupon.invitee.template_code'")).
+		WillReturnRows(sqlmock.NewRows([]string{"config_value"}).AddRow("welcome10"))
+	mock.ExpectQuery(regexp.QuoteMeta("config_key = 'coupon.invitee.valid_days'")).
+		WillReturnRows(sqlmock.NewRows([]string{"config_value"}).AddRow("7"))
+	mock.ExpectExec(regexp.QuoteMeta("'INVITE', ?, ?, 'ACTIVE', 'system', ?, ?\nFROM membership_coupons\nWHERE code = ? AND status = 'TEMPLATE'")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), now, now.AddDate(0, 0, 7), "u_invitee", "ir_001", now, now, "WELCOME10").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := handler.issueInviteeCoupon(tx, "u_invitee", "ir_001", now); err != nil {
+		t.Fatalf("issueInviteeCoupon() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/platform/streamticket"
+)
+
+// StreamTickets is the store the stream routes redeem tickets from, so the
+// router can hand the same one to middleware.StreamAuthRequired.
+func (h *AuthHandler) StreamTickets() *streamticket.Store {
+	return h.streamTickets
+}
+
+// IssueStreamTicket trades the caller's access token for a ticket that opens
+// one message stream. The ticket is valid for streamticket.TTL and is used
+// up by the stream it opens; a reconnect needs a new one.
+func (h *AuthHandler) IssueStreamTicket(c *gin.Context) {
+	userIDVal, _ := c.Get("user_id")
+	sessionIDVal, _ := c.Get("session_id")
+	roleVal, _ := c.Get("role")
+	userID, _ := userIDVal.(string)
+	sessionID, _ := sessionIDVal.(string)
+	role, _ := roleVal.(string)
+	ticket, err := h.streamTickets.Issue(c.Request.Context(), streamticket.Ticket{UserID: userID, SessionID: sessionID, Role: role})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, dto.APIResponse{Code: 50301, Message: "stream ticket store unavailable", Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{
+		"ticket":     ticket,
+		"expires_in": int(streamticket.TTL.Seconds()),
+	}))
+}
@@ -302,3 +302,111 @@ func TestListMyCommunityCommentsReturnsOnlyCurrentUserComments(t *testing.T) {
 		}
 	}
 }
+
+func TestCommunityModerationHoldsScamCommentAndBlocksBannedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	userHandler := NewUserGrowthHandler(growthService, config.Config{})
+	adminHandler := NewAdminGrowthHandler(growthService, config.Config{})
+	router := gin.New()
+	attachUserID(router, "u_demo_003")
+	router.POST("/api/v1/community/topics/:id/comments", userHandler.CreateCommunityComment)
+	router.POST("/api/v1/admin/community/bans", adminHandler.CreateCommunityUserBan)
+	call := func(path string, body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		return rec.Code, payload
+	}
+
+	code, payload := call("/api/v1/community/topics/ct_demo_001/comments", `{"content":"扫码进群，每天推牛股"}`)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %+v", code, payload)
+	}
+	data := payload["data"].(map[string]any)
+	reasons, _ := data["moderation_reasons"].([]any)
+	if data["status"] != string(model.CommunityCommentStatusPendingReview) || len(reasons) != 1 {
+		t.Fatalf("expected comment held for review, got %+v", data)
+	}
+
+	if code, payload := call("/api/v1/admin/community/bans", `{"user_id":"u_demo_003","days":3,"reason":"引流"}`); code != http.StatusOK {
+		t.Fatalf("expected ban to be created, got %d: %+v", code, payload)
+	}
+	code, payload = call("/api/v1/community/topics/ct_demo_001/comments", `{"content":"正常讨论"}`)
+	if code != http.StatusForbidden || payload["code"] != float64(40308) {
+		t.Fatalf("expected banned user to get 40308, got %d: %+v", code, payload)
+	}
+}
+
+func TestCommunityMentionNotifiesUntilMutedAndFollowErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthRepo := repo.NewInMemoryGrowthRepo()
+	growthService := service.NewGrowthService(growthRepo)
+	userHandler := NewUserGrowthHandler(growthService, config.Config{})
+	countMentions := func() int {
+		items, _, err := growthRepo.ListMessages("u_demo_002", 1, 100)
+		if err != nil {
+			t.Fatalf("ListMessages() error = %v", err)
+		}
+		count := 0
+		for _, item := range items {
+			if item.Title == "有人在社区提到了你" {
+				count++
+			}
+		}
+		return count
+	}
+
+	// Mail addresses are not mentions, and repeated handles notify once.
+	if _, err := growthService.CreateCommunityComment(model.CommunityCommentCreateInput{
+		UserID:  "u_demo_001",
+		TopicID: "ct_demo_001",
+		Content: "@u_demo_002 你看量能 @u_demo_002，资料发 me@u_demo_002.com",
+	}); err != nil {
+		t.Fatalf("CreateCommunityComment() error = %v", err)
+	}
+	if got := countMentions(); got != 1 {
+		t.Fatalf("expected one mention notification, got %d", got)
+	}
+
+	router := gin.New()
+	attachUserID(router, "u_demo_002")
+	router.POST("/api/v1/community/blocks", userHandler.CreateCommunityBlock)
+	router.POST("/api/v1/community/follows", userHandler.CreateCommunityFollow)
+	call := func(path string, body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		return rec.Code, payload
+	}
+	if code, payload := call("/api/v1/community/blocks", `{"blocked_user_id":"u_demo_001","block_type":"MUTE"}`); code != http.StatusOK {
+		t.Fatalf("expected mute to be created, got %d: %+v", code, payload)
+	}
+	if _, err := growthService.CreateCommunityComment(model.CommunityCommentCreateInput{
+		UserID:  "u_demo_001",
+		TopicID: "ct_demo_001",
+		Content: "再提醒一次 @u_demo_002",
+	}); err != nil {
+		t.Fatalf("CreateCommunityComment() error = %v", err)
+	}
+	if got := countMentions(); got != 1 {
+		t.Fatalf("expected muted user's mention to stay silent, got %d", got)
+	}
+
+	if code, payload := call("/api/v1/community/follows", `{"target_type":"USER","target_id":"u_demo_002"}`); code != http.StatusBadRequest || payload["code"] != float64(40002) {
+		t.Fatalf("expected self follow to get 40002, got %d: %+v", code, payload)
+	}
+	if code, payload := call("/api/v1/community/follows", `{"target_type":"USER","target_id":"u_missing"}`); code != http.StatusNotFound || payload["code"] != float64(40408) {
+		t.Fatalf("expected unknown user follow to get 40408, got %d: %+v", code, payload)
+	}
+}
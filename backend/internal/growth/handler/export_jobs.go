@@ -0,0 +1,494 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/objectstore"
+	"sercherai/backend/internal/platform/tabular"
+)
+
+const (
+	// exportPageSize is how many records an export job reads per query.
+	exportPageSize = 500
+	// exportClaimBatch bounds the jobs one worker tick runs; the rest wait
+	// for the next tick.
+	exportClaimBatch = 5
+	// A RUNNING job older than exportStaleAfter is taken to have lost its
+	// worker and is run again.
+	exportStaleAfter  = 30 * time.Minute
+	exportExpireBatch = 100
+
+	defaultExportMaxRows        = 200000
+	defaultExportRetentionHours = 72
+	defaultExportLinkTTLMinutes = 15
+)
+
+type exportSettings struct {
+	MaxRows   int
+	Retention time.Duration
+	LinkTTL   time.Duration
+}
+
+// ExportPermissions returns the permission needed to request the export
+// named by the :type path parameter, for middleware.PermissionRequiredFor.
+// Unknown types need none here and are rejected by RequestExportJob.
+func (h *AdminGrowthHandler) ExportPermissions(c *gin.Context) []string {
+	source, ok := exportSources[strings.ToUpper(strings.TrimSpace(c.Param("type")))]
+	if !ok {
+		return nil
+	}
+	return []string{source.permission}
+}
+
+// RequestExportJob queues a background export of the dataset named by
+// :type. Filters are the ones the dataset's list endpoint takes; others are
+// ignored.
+func (h *AdminGrowthHandler) RequestExportJob(c *gin.Context) {
+	exportType := strings.ToUpper(strings.TrimSpace(c.Param("type")))
+	source, ok := exportSources[exportType]
+	if !ok {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: model.ErrExportTypeNotSupported.Error(), Data: struct{}{}})
+		return
+	}
+	var req dto.ExportJobCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	filters := exportFilters(c, source, func(key string) string { return req.Filters[key] })
+	if source.validate != nil {
+		if err := source.validate(filters); err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
+	job, err := h.service.AdminCreateExportJob(model.ExportJob{
+		ExportType:  exportType,
+		Format:      model.NormalizeExportFormat(req.Format),
+		Filters:     filters,
+		RequestedBy: currentAdminOperator(c),
+		RequestIP:   c.ClientIP(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "EXPORT", "REQUEST_EXPORT", "EXPORT_JOB", job.ID, "", exportType+" "+job.Format, formatExportFilters(filters))
+	c.JSON(http.StatusOK, dto.OK(job))
+}
+
+// ListExportJobs lists the caller's own export jobs, newest first.
+func (h *AdminGrowthHandler) ListExportJobs(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListExportJobs(currentAdminOperator(c), c.Query("export_type"), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) GetExportJob(c *gin.Context) {
+	job, ok := h.loadOwnExportJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(job))
+}
+
+// CreateExportDownloadLink hands the requester a time-limited link to a
+// finished export. With EXPORT_SIGNING_SECRET set the link goes through the
+// backend, which records each download; without it the object store
+// presigns the URL and issuing the link is recorded as the download.
+func (h *AdminGrowthHandler) CreateExportDownloadLink(c *gin.Context) {
+	job, ok := h.loadOwnExportJob(c)
+	if !ok {
+		return
+	}
+	if job.Status != model.ExportJobStatusSucceeded {
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40913, Message: model.ErrExportJobNotReady.Error(), Data: struct{}{}})
+		return
+	}
+	ttl := h.loadExportSettings().LinkTTL
+	if expiresAt, err := time.Parse(time.RFC3339, job.ExpiresAt); err == nil && time.Until(expiresAt) < ttl {
+		ttl = time.Until(expiresAt)
+	}
+	if strings.TrimSpace(h.cfg.ExportSigningSecret) != "" {
+		expiresAt := time.Now().Add(ttl)
+		token := signExportToken(h.cfg.ExportSigningSecret, job.ID, job.RequestedBy, expiresAt)
+		signedURL := fmt.Sprintf("%s/api/v1/exports/%s/download?token=%s", strings.TrimRight(h.cfg.PublicBaseURL, "/"), job.ID, url.QueryEscape(token))
+		c.JSON(http.StatusOK, dto.OK(model.SignedURL{SignedURL: signedURL, ExpiredAt: expiresAt.Format(time.RFC3339)}))
+		return
+	}
+	provider, key, _ := objectstore.ParseRef(job.StorageRef)
+	store, err := openAttachmentStore(h.resolveOSSUploadConfig(), h.cfg, provider)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	signedURL, err := store.PresignGet(c.Request.Context(), key, ttl)
+	if err != nil {
+		if errors.Is(err, objectstore.ErrPresignUnsupported) {
+			c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40302, Message: "export signing disabled", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if err := h.recordExportDownload(c, job, job.RequestedBy, model.ExportDownloadChannelPresigned); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(model.SignedURL{SignedURL: signedURL, ExpiredAt: time.Now().Add(ttl).Format(time.RFC3339)}))
+}
+
+// DownloadExportFile serves a signed export link. The token is the only
+// credential, so the route sits outside admin auth like attachment
+// downloads do.
+func (h *AdminGrowthHandler) DownloadExportFile(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Code: 40101, Message: "missing token", Data: struct{}{}})
+		return
+	}
+	if strings.TrimSpace(h.cfg.ExportSigningSecret) == "" {
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40302, Message: "export signing disabled", Data: struct{}{}})
+		return
+	}
+	jobID, userID, ok := verifyExportToken(h.cfg.ExportSigningSecret, token)
+	if !ok || jobID != c.Param("id") {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Code: 40103, Message: "invalid token", Data: struct{}{}})
+		return
+	}
+	job, err := h.service.AdminGetExportJob(jobID)
+	if err != nil {
+		if errors.Is(err, model.ErrExportJobNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40413, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if job.Status != model.ExportJobStatusSucceeded || job.RequestedBy != userID {
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40913, Message: model.ErrExportJobNotReady.Error(), Data: struct{}{}})
+		return
+	}
+	provider, key, _ := objectstore.ParseRef(job.StorageRef)
+	store, err := openAttachmentStore(h.resolveOSSUploadConfig(), h.cfg, provider)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if err := h.recordExportDownload(c, job, userID, model.ExportDownloadChannelSignedLink); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	streamAttachmentObject(c, store, key, job.FileName, job.ContentType())
+}
+
+// ListAllExportJobs is the data-protection view of every export, whoever
+// requested it.
+func (h *AdminGrowthHandler) ListAllExportJobs(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListExportJobs(c.Query("requested_by"), c.Query("export_type"), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) ListExportDownloads(c *gin.Context) {
+	job, err := h.service.AdminGetExportJob(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, model.ErrExportJobNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40413, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	items, err := h.service.AdminListExportDownloads(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"job": job, "items": items, "total": len(items)}))
+}
+
+// loadOwnExportJob loads :id for its requester. Other admins' jobs read as
+// not found.
+func (h *AdminGrowthHandler) loadOwnExportJob(c *gin.Context) (model.ExportJob, bool) {
+	job, err := h.service.AdminGetExportJob(c.Param("id"))
+	if err == nil && job.RequestedBy != currentAdminOperator(c) {
+		err = model.ErrExportJobNotFound
+	}
+	if err != nil {
+		if errors.Is(err, model.ErrExportJobNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40413, Message: err.Error(), Data: struct{}{}})
+			return model.ExportJob{}, false
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return model.ExportJob{}, false
+	}
+	return job, true
+}
+
+func (h *AdminGrowthHandler) recordExportDownload(c *gin.Context, job model.ExportJob, userID string, channel string) error {
+	return h.service.AdminRecordExportDownload(model.ExportDownload{
+		JobID:     job.ID,
+		UserID:    userID,
+		Channel:   channel,
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+}
+
+// RunExportJobs is the export worker tick: it runs pending jobs, then
+// deletes files past their retention.
+func (h *AdminGrowthHandler) RunExportJobs(ctx context.Context) (string, error) {
+	settings := h.loadExportSettings()
+	jobs, err := h.service.AdminClaimExportJobs(exportClaimBatch, time.Now().Add(-exportStaleAfter))
+	if err != nil {
+		return "", err
+	}
+	exported, failed := 0, 0
+	for _, job := range jobs {
+		if h.runExportJob(ctx, job, settings) {
+			exported++
+		} else {
+			failed++
+		}
+	}
+	expired, err := h.expireExportFiles(ctx)
+	return fmt.Sprintf("exported=%d failed=%d expired=%d", exported, failed, expired), err
+}
+
+func (h *AdminGrowthHandler) runExportJob(ctx context.Context, job model.ExportJob, settings exportSettings) bool {
+	source, ok := exportSources[job.ExportType]
+	if !ok {
+		h.finishExportJob(job, model.ErrExportTypeNotSupported)
+		return false
+	}
+	payload, rowCount, err := h.buildExportFile(job, source, settings.MaxRows)
+	if err != nil {
+		h.finishExportJob(job, err)
+		return false
+	}
+	ossCfg := h.resolveOSSUploadConfig()
+	provider := activeAttachmentProvider(ossCfg)
+	store, err := openAttachmentStore(ossCfg, h.cfg, provider)
+	if err != nil {
+		h.finishExportJob(job, err)
+		return false
+	}
+	now := time.Now()
+	key := objectstore.JoinKey(exportObjectPrefix(ossCfg, provider), now.Format("2006/01/02"), job.ID+"."+job.FileExtension())
+	if err := store.Put(ctx, key, payload, job.ContentType()); err != nil {
+		h.finishExportJob(job, err)
+		return false
+	}
+	sum := sha256.Sum256(payload)
+	job.Status = model.ExportJobStatusSucceeded
+	job.RowCount = rowCount
+	job.FileName = source.fileName + "_" + now.Format("20060102_150405") + "." + job.FileExtension()
+	job.FileSize = int64(len(payload))
+	job.ContentSHA256 = hex.EncodeToString(sum[:])
+	job.StorageRef = objectstore.FormatRef(provider, key)
+	job.ExpiresAt = now.Add(settings.Retention).Format(time.RFC3339)
+	h.finishExportJob(job, nil)
+	return true
+}
+
+// finishExportJob records the outcome and tells the requester through a
+// workflow message.
+func (h *AdminGrowthHandler) finishExportJob(job model.ExportJob, runErr error) {
+	eventType := "EXPORT_READY"
+	title := "导出文件已生成"
+	content := fmt.Sprintf("%s 导出（%s，%d 行）已完成，可下载至 %s", job.ExportType, job.Format, job.RowCount, job.ExpiresAt)
+	if runErr != nil {
+		job.Status = model.ExportJobStatusFailed
+		job.ErrorMessage = runErr.Error()
+		eventType = "EXPORT_FAILED"
+		title = "导出任务失败"
+		content = fmt.Sprintf("%s 导出（%s）失败：%s", job.ExportType, job.Format, runErr.Error())
+	}
+	if err := h.service.AdminFinishExportJob(job); err != nil {
+		return
+	}
+	_ = h.service.AdminCreateWorkflowMessage("", job.ID, "EXPORT", job.RequestedBy, "system", eventType, title, content)
+}
+
+func (h *AdminGrowthHandler) expireExportFiles(ctx context.Context) (int, error) {
+	jobs, err := h.service.AdminListExpiredExportJobs(time.Now(), exportExpireBatch)
+	if err != nil {
+		return 0, err
+	}
+	ossCfg := h.resolveOSSUploadConfig()
+	expired := 0
+	for _, job := range jobs {
+		if provider, key, ok := objectstore.ParseRef(job.StorageRef); ok {
+			store, err := openAttachmentStore(ossCfg, h.cfg, provider)
+			if err != nil {
+				return expired, err
+			}
+			if err := store.Delete(ctx, key); err != nil && !errors.Is(err, objectstore.ErrNotFound) {
+				return expired, err
+			}
+		}
+		if err := h.service.AdminExpireExportJob(job.ID); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// buildExportFile pages through the dataset and streams every row into a
+// CSV or XLSX file. The row count is checked against maxRows on the first
+// page, before anything large is built.
+func (h *AdminGrowthHandler) buildExportFile(job model.ExportJob, source exportSource, maxRows int) ([]byte, int, error) {
+	var buf bytes.Buffer
+	var writer tabular.Writer
+	if job.Format == model.ExportFormatXLSX {
+		xlsx, err := tabular.NewXLSXWriter(&buf, source.fileName)
+		if err != nil {
+			return nil, 0, err
+		}
+		writer = xlsx
+	} else {
+		writer = tabular.NewCSVWriter(&buf)
+	}
+	if err := writer.WriteRow(source.header); err != nil {
+		return nil, 0, err
+	}
+	written := 0
+	for page := 1; ; page++ {
+		rows, total, err := source.fetch(h.service, job.Filters, page, exportPageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		if total > maxRows {
+			return nil, 0, fmt.Errorf("export matches %d rows, over the export.max_rows limit of %d; narrow the filters", total, maxRows)
+		}
+		for _, row := range rows {
+			if err := writer.WriteRow(row); err != nil {
+				return nil, 0, err
+			}
+		}
+		written += len(rows)
+		if len(rows) == 0 || written >= total {
+			break
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), written, nil
+}
+
+func exportObjectPrefix(cfg ossUploadConfig, provider string) string {
+	switch provider {
+	case objectstore.ProviderQiniu:
+		return joinObjectPath(cfg.PathPrefix, "exports")
+	case objectstore.ProviderS3:
+		return joinObjectPath(cfg.S3PathPrefix, "exports")
+	default:
+		return "exports"
+	}
+}
+
+func (h *AdminGrowthHandler) loadExportSettings() exportSettings {
+	settings := exportSettings{
+		MaxRows:   defaultExportMaxRows,
+		Retention: defaultExportRetentionHours * time.Hour,
+		LinkTTL:   defaultExportLinkTTLMinutes * time.Minute,
+	}
+	items, _, err := h.service.AdminListSystemConfigs("export.", 1, 50)
+	if err != nil {
+		return settings
+	}
+	for _, item := range items {
+		value, err := strconv.Atoi(strings.TrimSpace(item.ConfigValue))
+		if err != nil || value <= 0 {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(item.ConfigKey)) {
+		case "export.max_rows":
+			settings.MaxRows = value
+		case "export.retention_hours":
+			settings.Retention = time.Duration(value) * time.Hour
+		case "export.link_ttl_minutes":
+			settings.LinkTTL = time.Duration(value) * time.Minute
+		}
+	}
+	return settings
+}
+
+func formatExportFilters(filters map[string]string) string {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+filters[key])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Export tokens bind a job to its requester until expiresAt, in the same
+// payload.signature shape as attachment tokens.
+func signExportToken(secret string, jobID string, userID string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("export|%s|%s|%d", jobID, userID, expiresAt.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func verifyExportToken(secret string, token string) (jobID string, userID string, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", "", false
+	}
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 4 || fields[0] != "export" {
+		return "", "", false
+	}
+	expUnix, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil || time.Now().After(time.Unix(expUnix, 0)) {
+		return "", "", false
+	}
+	return fields[1], fields[2], true
+}
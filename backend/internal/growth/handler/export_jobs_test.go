@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+)
+
+func newExportHandlerForTest(t *testing.T) *AdminGrowthHandler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	return NewAdminGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{
+		AttachmentStorageDir: t.TempDir(),
+		ExportSigningSecret:  "export-secret",
+		PublicBaseURL:        "https://admin.example.com",
+	})
+}
+
+func newExportRouterForTest(growthHandler *AdminGrowthHandler, userID string) *gin.Engine {
+	router := gin.New()
+	attachUserID(router, userID)
+	router.POST("/api/v1/admin/exports/:type", growthHandler.RequestExportJob)
+	router.GET("/api/v1/admin/exports/jobs/:id", growthHandler.GetExportJob)
+	router.POST("/api/v1/admin/exports/jobs/:id/link", growthHandler.CreateExportDownloadLink)
+	router.GET("/api/v1/admin/audit/exports/:id/downloads", growthHandler.ListExportDownloads)
+	router.GET("/api/v1/exports/:id/download", growthHandler.DownloadExportFile)
+	return router
+}
+
+func TestRequestExportJobRejectsUnknownType(t *testing.T) {
+	router := newExportRouterForTest(newExportHandlerForTest(t), "admin_001")
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/exports/PASSWORDS", `{"format":"CSV"}`)
+	if status != http.StatusBadRequest || code != 40001 {
+		t.Fatalf("expected unknown export type to be rejected, got status=%d code=%d", status, code)
+	}
+}
+
+func TestExportJobRunsAndServesSignedDownload(t *testing.T) {
+	growthHandler := newExportHandlerForTest(t)
+	router := newExportRouterForTest(growthHandler, "admin_001")
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/exports/users", `{"format":"xlsx","filters":{"status":"ACTIVE","ignored":"x"}}`)
+	if status != http.StatusOK {
+		t.Fatalf("request export: status=%d", status)
+	}
+	var job model.ExportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatalf("unmarshal export job: %v", err)
+	}
+	if job.Status != model.ExportJobStatusPending || job.Format != model.ExportFormatXLSX || job.Filters["status"] != "ACTIVE" || job.Filters["ignored"] != "" {
+		t.Fatalf("unexpected queued job %+v", job)
+	}
+
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/exports/jobs/"+job.ID+"/link", "")
+	if status != http.StatusConflict || code != 40913 {
+		t.Fatalf("expected link before completion to be refused, got status=%d code=%d", status, code)
+	}
+
+	summary, err := growthHandler.RunExportJobs(context.Background())
+	if err != nil || summary != "exported=1 failed=0 expired=0" {
+		t.Fatalf("run export jobs: summary=%q err=%v", summary, err)
+	}
+	finished, err := growthHandler.service.AdminGetExportJob(job.ID)
+	if err != nil {
+		t.Fatalf("get export job: %v", err)
+	}
+	if finished.Status != model.ExportJobStatusSucceeded || finished.FileSize == 0 || finished.ContentSHA256 == "" || finished.ExpiresAt == "" {
+		t.Fatalf("unexpected finished job %+v", finished)
+	}
+	messages, _, err := growthHandler.service.AdminListWorkflowMessages("EXPORT", "EXPORT_READY", "", "admin_001", 1, 10)
+	if err != nil || len(messages) != 1 || messages[0].TargetID != job.ID {
+		t.Fatalf("expected an EXPORT_READY message for the requester, got %+v err=%v", messages, err)
+	}
+
+	otherRouter := newExportRouterForTest(growthHandler, "admin_002")
+	status, code, _ = serveAdminJSONRequest(t, otherRouter, http.MethodGet, "/api/v1/admin/exports/jobs/"+job.ID, "")
+	if status != http.StatusNotFound || code != 40413 {
+		t.Fatalf("expected another admin's job to read as missing, got status=%d code=%d", status, code)
+	}
+
+	status, _, data = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/exports/jobs/"+job.ID+"/link", "")
+	if status != http.StatusOK {
+		t.Fatalf("create link: status=%d", status)
+	}
+	var link model.SignedURL
+	if err := json.Unmarshal(data, &link); err != nil {
+		t.Fatalf("unmarshal link: %v", err)
+	}
+	if !strings.HasPrefix(link.SignedURL, "https://admin.example.com/api/v1/exports/"+job.ID+"/download?token=") {
+		t.Fatalf("unexpected signed url %q", link.SignedURL)
+	}
+	parsed, err := url.Parse(link.SignedURL)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, parsed.RequestURI(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "PK") {
+		t.Fatalf("expected xlsx download, got status=%d body=%q", rec.Code, rec.Body.String())
+	}
+
+	status, _, _ = serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/exports/"+job.ID+"/download?token=forged.token", "")
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected forged token to be rejected, got status=%d", status)
+	}
+
+	status, _, data = serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/audit/exports/"+job.ID+"/downloads", "")
+	if status != http.StatusOK {
+		t.Fatalf("list downloads: status=%d", status)
+	}
+	var downloads struct {
+		Job   model.ExportJob        `json:"job"`
+		Items []model.ExportDownload `json:"items"`
+	}
+	if err := json.Unmarshal(data, &downloads); err != nil {
+		t.Fatalf("unmarshal downloads: %v", err)
+	}
+	if len(downloads.Items) != 1 || downloads.Items[0].UserID != "admin_001" || downloads.Items[0].Channel != model.ExportDownloadChannelSignedLink || downloads.Job.DownloadCount != 1 {
+		t.Fatalf("unexpected download audit %+v", downloads)
+	}
+}
+
+func TestSyncExportIsRecordedAsDeliveredJob(t *testing.T) {
+	growthHandler := newExportHandlerForTest(t)
+	router := newExportRouterForTest(growthHandler, "admin_001")
+	router.GET("/api/v1/admin/auth/login-logs/export.csv", growthHandler.ExportLoginLogsCSV)
+	router.GET("/api/v1/admin/stocks/quant/evaluation/export.csv", growthHandler.ExportQuantEvaluationCSV)
+
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/auth/login-logs/export.csv?date_from=2026/02/01", "")
+	if status != http.StatusBadRequest || code != 40001 {
+		t.Fatalf("expected malformed date_from to be rejected, got status=%d code=%d", status, code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/auth/login-logs/export.csv?status=success&date_from=2026-02-01", nil)
+	req.Header.Set("User-Agent", "export-test")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "13800000001") || !strings.Contains(rec.Body.String(), "138****0001") {
+		t.Fatalf("expected masked login log csv, got status=%d body=%q", rec.Code, rec.Body.String())
+	}
+
+	jobs, total, err := growthHandler.service.AdminListExportJobs("admin_001", model.ExportTypeLoginLogs, model.ExportJobStatusDelivered, 1, 10)
+	if err != nil || total != 1 {
+		t.Fatalf("expected one delivered login log export, got %+v err=%v", jobs, err)
+	}
+	job := jobs[0]
+	if job.RowCount != 1 || job.FileName != "auth_login_logs.csv" || job.FileSize != int64(rec.Body.Len()) || job.ContentSHA256 == "" || job.Filters["date_from"] != "2026-02-01" || job.DownloadCount != 1 {
+		t.Fatalf("unexpected delivered job %+v", job)
+	}
+	downloads, err := growthHandler.service.AdminListExportDownloads(job.ID)
+	if err != nil || len(downloads) != 1 || downloads[0].Channel != model.ExportDownloadChannelDirect || downloads[0].UserID != "admin_001" || downloads[0].UserAgent != "export-test" {
+		t.Fatalf("expected one direct download, got %+v err=%v", downloads, err)
+	}
+
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/exports/jobs/"+job.ID+"/link", "")
+	if status != http.StatusConflict || code != 40913 {
+		t.Fatalf("expected no download link for a delivered export, got status=%d code=%d", status, code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/stocks/quant/evaluation/export.csv?days=30&top_n=5", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "section,field,value\nsummary,window_days,") {
+		t.Fatalf("expected sectioned quant evaluation csv, got status=%d body=%q", rec.Code, rec.Body.String())
+	}
+	if _, total, _ := growthHandler.service.AdminListExportJobs("admin_001", model.ExportTypeQuantEvaluation, model.ExportJobStatusDelivered, 1, 10); total != 1 {
+		t.Fatalf("expected the quant evaluation export to be recorded, got %d", total)
+	}
+}
+
+// failingExportRecordRepo cannot record delivered exports.
+type failingExportRecordRepo struct {
+	repo.GrowthRepo
+}
+
+func (failingExportRecordRepo) AdminRecordDeliveredExport(item model.ExportJob, download model.ExportDownload) (model.ExportJob, error) {
+	return model.ExportJob{}, errors.New("export store unavailable")
+}
+
+func TestSyncExportSendsNothingWhenTheRecordFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthHandler := NewAdminGrowthHandler(service.NewGrowthService(failingExportRecordRepo{GrowthRepo: repo.NewInMemoryGrowthRepo()}), config.Config{})
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.GET("/api/v1/admin/users/export.csv", growthHandler.ExportUsersCSV)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/export.csv", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "138****0001") {
+		t.Fatalf("expected 500 without the file, got status=%d body=%q", rec.Code, rec.Body.String())
+	}
+}
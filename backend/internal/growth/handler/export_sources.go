@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/pii"
+	"sercherai/backend/internal/platform/tabular"
+)
+
+// syncExportRowLimit caps the synchronous export.csv endpoints; anything
+// larger should go through a background export job.
+const syncExportRowLimit = 10000
+
+// exportSource is one exportable dataset. The synchronous export.csv
+// endpoints and background export jobs share it, so both produce the same
+// columns from the same filters.
+type exportSource struct {
+	// permission is required to export the dataset, the same one its list
+	// endpoint needs.
+	permission string
+	fileName   string
+	filterKeys []string
+	// defaults fills filters the list endpoint derives from the caller.
+	defaults func(c *gin.Context, filters map[string]string)
+	// validate rejects filters the dataset cannot be queried with.
+	validate func(filters map[string]string) error
+	header   []string
+	// fetch returns one page of rows with phones and emails already masked.
+	fetch func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error)
+}
+
+var exportSources = map[string]exportSource{
+	model.ExportTypeUsers: {
+		permission: "users.view",
+		fileName:   "admin_users",
+		filterKeys: []string{"status", "member_level", "registration_source"},
+		header:     []string{"id", "phone", "email", "status", "member_level", "registration_source", "inviter_user_id", "invite_code", "invite_registered_at", "created_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListUsers(filters["status"], filters["member_level"], filters["registration_source"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, pii.MaskPhone(it.Phone), pii.MaskEmail(it.Email), it.Status, it.MemberLevel, it.RegistrationSource, it.InviterUserID, it.InviteCode, it.InviteRegisteredAt, it.CreatedAt})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeBrowseHistories: {
+		permission: "users.view",
+		fileName:   "browse_histories",
+		filterKeys: []string{"user_id", "content_type", "keyword"},
+		header:     []string{"id", "user_id", "user_phone", "content_type", "content_id", "title", "source_page", "viewed_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListBrowseHistories(filters["user_id"], strings.ToUpper(filters["content_type"]), filters["keyword"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.UserID, pii.MaskPhone(it.UserPhone), it.ContentType, it.ContentID, it.Title, it.SourcePage, it.ViewedAt})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeMembershipOrders: {
+		permission: "membership.view",
+		fileName:   "membership_orders",
+		filterKeys: []string{"status", "user_id"},
+		header:     []string{"id", "user_id", "product_id", "amount", "status", "paid_at", "created_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListMembershipOrders(filters["status"], filters["user_id"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.UserID, it.ProductID, strconv.FormatFloat(it.Amount, 'f', -1, 64), it.Status, it.PaidAt, it.CreatedAt})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeOperationLogs: {
+		permission: "audit.view",
+		fileName:   "admin_operation_logs",
+		filterKeys: []string{"module", "action", "operator_user_id"},
+		header:     []string{"id", "module", "action", "target_type", "target_id", "operator_user_id", "before_value", "after_value", "reason", "created_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListOperationLogs(filters["module"], filters["action"], filters["operator_user_id"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.Module, it.Action, it.TargetType, it.TargetID, it.OperatorUserID, it.BeforeValue, it.AfterValue, it.Reason, it.CreatedAt})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeSchedulerJobRuns: {
+		permission: "system_job.view",
+		fileName:   "scheduler_job_runs",
+		filterKeys: []string{"job_name", "status"},
+		header:     []string{"id", "parent_run_id", "retry_count", "job_name", "trigger_source", "status", "started_at", "finished_at", "result_summary", "error_message", "operator_id"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListSchedulerJobRuns(filters["job_name"], filters["status"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.ParentRunID, strconv.Itoa(it.RetryCount), it.JobName, it.TriggerSource, it.Status, it.StartedAt, it.FinishedAt, it.ResultSummary, it.ErrorMessage, it.OperatorID})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeReviewTasks: {
+		permission: "review.view",
+		fileName:   "review_tasks",
+		filterKeys: []string{"module", "status", "submitter_id", "reviewer_id"},
+		header:     []string{"id", "module", "target_id", "submitter_id", "reviewer_id", "status", "submit_note", "review_note", "submitted_at", "reviewed_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListReviewTasks(filters["module"], filters["status"], filters["submitter_id"], filters["reviewer_id"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.Module, it.TargetID, it.SubmitterID, it.ReviewerID, it.Status, it.SubmitNote, it.ReviewNote, it.SubmittedAt, it.ReviewedAt})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeWorkflowMessages: {
+		permission: "workflow.view",
+		fileName:   "workflow_messages",
+		filterKeys: []string{"module", "event_type", "is_read", "receiver_id"},
+		// Like the message list, an export without receiver_id covers the
+		// caller's own messages.
+		defaults: func(c *gin.Context, filters map[string]string) {
+			if filters["receiver_id"] == "" {
+				operatorVal, _ := c.Get("user_id")
+				filters["receiver_id"], _ = operatorVal.(string)
+			}
+		},
+		header: []string{"id", "review_id", "target_id", "module", "receiver_id", "sender_id", "event_type", "title", "content", "is_read", "created_at", "read_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListWorkflowMessages(filters["module"], filters["event_type"], filters["is_read"], filters["receiver_id"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.ReviewID, it.TargetID, it.Module, it.ReceiverID, it.SenderID, it.EventType, it.Title, it.Content, strconv.FormatBool(it.IsRead), it.CreatedAt, it.ReadAt})
+			}
+			return rows, total, err
+		},
+	},
+	model.ExportTypeLoginLogs: {
+		permission: "auth_security.view",
+		fileName:   "auth_login_logs",
+		filterKeys: []string{"action", "status", "date_from", "date_to"},
+		validate: func(filters map[string]string) error {
+			for _, key := range []string{"date_from", "date_to"} {
+				if value := filters[key]; value != "" {
+					if _, err := time.Parse("2006-01-02", value); err != nil {
+						return fmt.Errorf("invalid %s format, expect YYYY-MM-DD", key)
+					}
+				}
+			}
+			return nil
+		},
+		header: []string{"id", "user_id", "phone", "action", "status", "reason", "ip", "user_agent", "created_at"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			items, total, err := svc.AdminListLoginLogs(filters["action"], filters["status"], filters["date_from"], filters["date_to"], page, pageSize)
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				rows = append(rows, []string{it.ID, it.UserID, pii.MaskPhone(it.Phone), it.Action, it.Status, it.Reason, pii.MaskIP(it.IP), it.UserAgent, it.CreatedAt})
+			}
+			return rows, total, err
+		},
+	},
+	// The quant evaluation is one report rather than a list: its summary,
+	// daily points, risk buckets and rotations go out as consecutive
+	// sections, all on the first page.
+	model.ExportTypeQuantEvaluation: {
+		permission: "market.view",
+		fileName:   "stock_quant_evaluation",
+		filterKeys: []string{"days", "top_n"},
+		header:     []string{"section", "field", "value"},
+		fetch: func(svc service.GrowthService, filters map[string]string, page int, pageSize int) ([][]string, int, error) {
+			windowDays, topN := quantEvaluationWindow(filters["days"], filters["top_n"])
+			summary, points, riskItems, rotationItems, err := svc.AdminGetQuantEvaluation(windowDays, topN)
+			if err != nil {
+				return nil, 0, err
+			}
+			rows := quantEvaluationRows(summary, points, riskItems, rotationItems)
+			if page > 1 {
+				return nil, len(rows), nil
+			}
+			return rows, len(rows), nil
+		},
+	},
+}
+
+// exportFilters keeps the filters source accepts from raw, whether query
+// parameters or a job request, and applies the source's defaults.
+func exportFilters(c *gin.Context, source exportSource, raw func(key string) string) map[string]string {
+	filters := make(map[string]string, len(source.filterKeys))
+	for _, key := range source.filterKeys {
+		if value := strings.TrimSpace(raw(key)); value != "" {
+			filters[key] = value
+		}
+	}
+	if source.defaults != nil {
+		source.defaults(c, filters)
+	}
+	return filters
+}
+
+// serveExportCSV answers a synchronous export.csv request with the first
+// syncExportRowLimit rows of the dataset. The response is recorded as a
+// DELIVERED export job with one DIRECT download, like a background export,
+// and no file is sent when that record cannot be written.
+func (h *AdminGrowthHandler) serveExportCSV(c *gin.Context, exportType string) {
+	source := exportSources[exportType]
+	filters := exportFilters(c, source, c.Query)
+	if source.validate != nil {
+		if err := source.validate(filters); err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+	}
+	rows, _, err := source.fetch(h.service, filters, 1, syncExportRowLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	var buf bytes.Buffer
+	writer := tabular.NewCSVWriter(&buf)
+	_ = writer.WriteRow(source.header)
+	for _, row := range rows {
+		_ = writer.WriteRow(row)
+	}
+	if err := writer.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	fileName := source.fileName + ".csv"
+	sum := sha256.Sum256(buf.Bytes())
+	operator := currentAdminOperator(c)
+	if _, err := h.service.AdminRecordDeliveredExport(model.ExportJob{
+		ExportType:    exportType,
+		Format:        model.ExportFormatCSV,
+		Filters:       filters,
+		RowCount:      len(rows),
+		FileName:      fileName,
+		FileSize:      int64(buf.Len()),
+		ContentSHA256: hex.EncodeToString(sum[:]),
+		RequestedBy:   operator,
+		RequestIP:     c.ClientIP(),
+	}, model.ExportDownload{
+		UserID:    operator,
+		Channel:   model.ExportDownloadChannelDirect,
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: "record export failed", Data: struct{}{}})
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.String(http.StatusOK, buf.String())
+}
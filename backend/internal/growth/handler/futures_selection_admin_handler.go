@@ -341,6 +341,14 @@ func (h *AdminGrowthHandler) ApproveFuturesSelectionReview(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	if submitForApproval(c, h.service, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRecommendationPublish,
+		Action:   model.ApprovalActionFuturesSelectionPublish,
+		TargetID: c.Param("run_id"),
+		Summary:  "发布期货推荐运行 " + c.Param("run_id"),
+	}, req) {
+		return
+	}
 	operator := currentAdminOperator(c)
 	item, err := h.service.AdminApproveFuturesSelectionReview(c.Param("run_id"), operator, req.ReviewNote, req.Force, req.OverrideReason)
 	if err != nil {
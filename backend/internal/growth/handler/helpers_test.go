@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveAdminJSONRequest sends a JSON request through router and returns the
+// HTTP status with the envelope's code and data.
+func serveAdminJSONRequest(t *testing.T, router *gin.Engine, method string, path string, body string) (int, int, json.RawMessage) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var payload struct {
+		Code int             `json:"code"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return rec.Code, payload.Code, payload.Data
+}
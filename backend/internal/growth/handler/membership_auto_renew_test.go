@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/payment"
+)
+
+func TestMembershipAutoRenewSignsAndReleasesAgreement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthHandler := NewUserGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{})
+	router := gin.New()
+	attachUserID(router, "u_renew")
+	router.GET("/api/v1/membership/auto-renew", growthHandler.GetMembershipAutoRenew)
+	router.POST("/api/v1/membership/auto-renew", growthHandler.EnableMembershipAutoRenew)
+	router.DELETE("/api/v1/membership/auto-renew", growthHandler.CancelMembershipAutoRenew)
+	call := func(method string, body string) (int, map[string]any) {
+		req := httptest.NewRequest(method, "/api/v1/membership/auto-renew", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		return rec.Code, payload
+	}
+
+	if code, payload := call(http.MethodGet, ""); code != http.StatusNotFound || payload["code"] != float64(40406) {
+		t.Fatalf("expected no auto-renew yet, got %d: %+v", code, payload)
+	}
+	if code, _ := call(http.MethodPost, `{"product_id":"mp_demo_001","pay_channel":"ALIPAY"}`); code != http.StatusBadRequest {
+		t.Fatalf("expected channel without agreements to be rejected, got %d", code)
+	}
+	code, payload := call(http.MethodPost, `{"product_id":"mp_demo_001","pay_channel":"SANDBOX"}`)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %+v", code, payload)
+	}
+	data := payload["data"].(map[string]any)
+	agreementNo, _ := data["agreement_no"].(string)
+	if data["status"] != "ACTIVE" || agreementNo == "" || data["next_charge_at"] == "" {
+		t.Fatalf("unexpected auto-renew: %+v", data)
+	}
+
+	if code, payload := call(http.MethodDelete, ""); code != http.StatusOK {
+		t.Fatalf("expected cancel to succeed, got %d: %+v", code, payload)
+	}
+	channel := payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{})
+	if _, err := channel.ChargeAgreement(payment.AgreementCharge{AgreementNo: agreementNo, OrderNo: "mo_after_cancel", Amount: 99}); !errors.Is(err, payment.ErrAgreementInactive) {
+		t.Fatalf("expected agreement to be released, got %v", err)
+	}
+	if code, payload := call(http.MethodDelete, ""); code != http.StatusNotFound || payload["code"] != float64(40406) {
+		t.Fatalf("expected second cancel to 404, got %d: %+v", code, payload)
+	}
+}
+
+func TestMembershipAutoRenewRejectsChannelsThatCannotChargeAgreements(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthHandler := NewUserGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{AppEnv: "production"})
+	router := gin.New()
+	attachUserID(router, "u_renew")
+	router.POST("/api/v1/membership/auto-renew", growthHandler.EnableMembershipAutoRenew)
+
+	for _, channel := range []string{"YOLKPAY", "SANDBOX"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/membership/auto-renew", bytes.NewBufferString(`{"product_id":"mp_demo_001","pay_channel":"`+channel+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		if rec.Code != http.StatusConflict || payload["code"] != float64(40906) {
+			t.Fatalf("%s: expected enrollment to be refused, got %d: %+v", channel, rec.Code, payload)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+)
+
+func TestCreateMembershipOrderAppliesCouponOncePerUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	growthHandler := NewUserGrowthHandler(growthService, config.Config{})
+	if _, err := growthService.AdminCreateMembershipCoupon(model.MembershipCoupon{
+		Code:              "welcome30",
+		Name:              "新客立减",
+		DiscountType:      "FIXED",
+		DiscountValue:     30,
+		PerUserLimit:      1,
+		FirstPurchaseOnly: true,
+	}); err != nil {
+		t.Fatalf("AdminCreateMembershipCoupon() error = %v", err)
+	}
+
+	body := `{"product_id":"mp_demo_001","pay_channel":"SANDBOX","coupon_code":"WELCOME30"}`
+	code, payload := createSandboxMembershipOrder(t, growthHandler, "u_coupon", body)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %+v", code, payload)
+	}
+	data := payload["data"].(map[string]any)
+	if data["amount"] != 69.0 || data["original_amount"] != 99.0 || data["coupon_code"] != "WELCOME30" {
+		t.Fatalf("unexpected order pricing: %+v", data)
+	}
+
+	code, payload = createSandboxMembershipOrder(t, growthHandler, "u_coupon", body)
+	if code != http.StatusConflict || payload["code"] != float64(40904) {
+		t.Fatalf("expected coupon rejection, got %d: %+v", code, payload)
+	}
+
+	code, payload = createSandboxMembershipOrder(t, growthHandler, "u_coupon", `{"product_id":"mp_demo_001","pay_channel":"SANDBOX","coupon_code":"NOPE"}`)
+	if code != http.StatusNotFound || payload["code"] != float64(40405) {
+		t.Fatalf("expected unknown coupon 404, got %d: %+v", code, payload)
+	}
+
+	redemptions, _, err := growthService.AdminListMembershipCouponRedemptions("", "REDEEMED", 1, 20)
+	if err != nil || len(redemptions) != 1 {
+		t.Fatalf("expected one redeemed coupon use, got %+v (err=%v)", redemptions, err)
+	}
+}
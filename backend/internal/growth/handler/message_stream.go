@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/realtime"
+)
+
+const (
+	messageStreamBatchSize = 100
+	// Streams re-read this long after a ping in case it was published before
+	// the writing transaction committed.
+	messageStreamSettleDelay = time.Second
+	// Safety polls catch pings lost in transit. Without Redis, pings from
+	// other replicas never arrive, so streams poll much more often.
+	messageStreamPollDistributed = 30 * time.Second
+	messageStreamPollLocal       = 5 * time.Second
+	messageStreamClientRetryMS   = 5000
+)
+
+// messageStreamKeepAlive is how often an idle stream sends a comment ping
+// and re-checks the session it was opened with. Tests shorten it.
+var messageStreamKeepAlive = 25 * time.Second
+
+// messageStreamLateCommitWindow is how long a stream keeps re-reading
+// behind its cursor. seq is assigned at insert, so a row whose transaction
+// commits after a later row's shows up below the cursor; re-reading rows
+// delivered in the last window picks it up.
+const messageStreamLateCommitWindow = 10 * time.Second
+
+// The SSE event id is the row's seq, so a reconnect with Last-Event-ID
+// resumes right after it.
+func parseMessageStreamCursor(raw string) (int64, bool) {
+	seq, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false
+	}
+	return seq, true
+}
+
+// messageStreamReplay tracks what a stream has delivered. Reads start at
+// floor, which trails the cursor by messageStreamLateCommitWindow, and rows
+// already delivered above floor are skipped.
+type messageStreamReplay struct {
+	cursor    int64
+	floor     int64
+	delivered map[int64]struct{}
+	marks     []messageStreamMark
+}
+
+type messageStreamMark struct {
+	at     time.Time
+	cursor int64
+}
+
+func newMessageStreamReplay(cursor int64) *messageStreamReplay {
+	return &messageStreamReplay{cursor: cursor, floor: cursor, delivered: map[int64]struct{}{}}
+}
+
+// fresh reports whether seq is new to the stream and records it.
+func (r *messageStreamReplay) fresh(seq int64) bool {
+	if seq <= r.floor {
+		return false
+	}
+	if _, ok := r.delivered[seq]; ok {
+		return false
+	}
+	r.delivered[seq] = struct{}{}
+	if seq > r.cursor {
+		r.cursor = seq
+	}
+	return true
+}
+
+// settle raises floor to the cursor the stream had a window ago, once every
+// row below it has had that long to commit.
+func (r *messageStreamReplay) settle(now time.Time) {
+	r.marks = append(r.marks, messageStreamMark{at: now, cursor: r.cursor})
+	cutoff := now.Add(-messageStreamLateCommitWindow)
+	settled := 0
+	for settled < len(r.marks) && !r.marks[settled].at.After(cutoff) {
+		r.floor = r.marks[settled].cursor
+		settled++
+	}
+	if settled == 0 {
+		return
+	}
+	r.marks = append(r.marks[:0], r.marks[settled:]...)
+	for seq := range r.delivered {
+		if seq <= r.floor {
+			delete(r.delivered, seq)
+		}
+	}
+}
+
+type messageStreamEvent struct {
+	Seq  int64
+	Name string
+	Data interface{}
+}
+
+// userMessageStreamEvent names the SSE event after the message type so
+// clients can route community notifications and alerts separately.
+func userMessageStreamEvent(item model.UserMessage) messageStreamEvent {
+	name := "user_message"
+	switch strings.ToUpper(item.Type) {
+	case "COMMUNITY":
+		name = "community_notification"
+	case "ALERT":
+		name = "alert"
+	}
+	return messageStreamEvent{Seq: item.Seq, Name: name, Data: item}
+}
+
+// serveMessageStream runs a Server-Sent Events response until the client
+// disconnects. It starts after the Last-Event-ID cursor (or the latest row,
+// from head), then re-reads rows after its floor whenever the hub pings one
+// of topics, a settle delay after each ping, and on a safety poll. Each
+// keepalive also re-runs the auth middleware's session check; once the
+// session is revoked the stream sends session_revoked and closes.
+func serveMessageStream(c *gin.Context, hub *realtime.Hub, topics []string, head func() (int64, error), fetch func(afterSeq int64) ([]messageStreamEvent, error)) {
+	cursor, ok := parseMessageStreamCursor(c.GetHeader("Last-Event-ID"))
+	if !ok {
+		cursor, ok = parseMessageStreamCursor(c.Query("last_event_id"))
+	}
+	if !ok {
+		latest, err := head()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		cursor = latest
+	}
+	replay := newMessageStreamReplay(cursor)
+
+	mode := "pubsub"
+	pollInterval := messageStreamPollDistributed
+	if !hub.Distributed() {
+		mode = "polling"
+		pollInterval = messageStreamPollLocal
+	}
+
+	var sessionActive func() (bool, error)
+	if value, ok := c.Get("session_check"); ok {
+		sessionActive, _ = value.(func() (bool, error))
+	}
+
+	ctx := c.Request.Context()
+	wake := hub.Subscribe(ctx, topics...)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ready, _ := json.Marshal(gin.H{"mode": mode, "poll_interval_seconds": int(pollInterval / time.Second)})
+	fmt.Fprintf(c.Writer, "retry: %d\nevent: ready\ndata: %s\n\n", messageStreamClientRetryMS, ready)
+	c.Writer.Flush()
+
+	deliver := func() {
+		after := replay.floor
+		for {
+			events, err := fetch(after)
+			if err != nil {
+				log.Printf("message stream fetch failed: %v", err)
+				return
+			}
+			for _, event := range events {
+				after = event.Seq
+				if !replay.fresh(event.Seq) {
+					continue
+				}
+				data, err := json.Marshal(event.Data)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Name, data)
+			}
+			c.Writer.Flush()
+			if len(events) < messageStreamBatchSize {
+				replay.settle(time.Now())
+				return
+			}
+		}
+	}
+	deliver()
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(messageStreamKeepAlive)
+	defer keepAlive.Stop()
+	settle := time.NewTimer(messageStreamSettleDelay)
+	settle.Stop()
+	defer settle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+			deliver()
+			settle.Reset(messageStreamSettleDelay)
+		case <-settle.C:
+			deliver()
+		case <-poll.C:
+			deliver()
+		case <-keepAlive.C:
+			if sessionActive != nil {
+				active, err := sessionActive()
+				if err != nil {
+					// The store only errors when MySQL is down too; keep the
+					// stream and check again on the next keepalive.
+					log.Printf("message stream session check failed: %v", err)
+				} else if !active {
+					fmt.Fprint(c.Writer, "event: session_revoked\ndata: {}\n\n")
+					c.Writer.Flush()
+					return
+				}
+			}
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// StreamMessages pushes new user messages, community notifications and
+// alerts to the signed-in user as Server-Sent Events.
+func (h *UserGrowthHandler) StreamMessages(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	head := func() (int64, error) { return h.service.LatestUserMessageSeq(userID) }
+	serveMessageStream(c, h.service.Realtime(), []string{realtime.UserTopic(userID)}, head, func(afterSeq int64) ([]messageStreamEvent, error) {
+		items, err := h.service.ListUserMessagesAfter(userID, afterSeq, messageStreamBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		events := make([]messageStreamEvent, 0, len(items))
+		for _, item := range items {
+			events = append(events, userMessageStreamEvent(item))
+		}
+		return events, nil
+	})
+}
+
+// StreamWorkflowMessages pushes workflow messages addressed to the signed-in
+// admin, or to no one in particular, as Server-Sent Events.
+func (h *AdminGrowthHandler) StreamWorkflowMessages(c *gin.Context) {
+	operatorVal, _ := c.Get("user_id")
+	adminID, _ := operatorVal.(string)
+	head := func() (int64, error) { return h.service.AdminLatestWorkflowMessageSeq(adminID) }
+	serveMessageStream(c, h.service.Realtime(), []string{realtime.AdminTopic(adminID), realtime.AdminBroadcastTopic}, head, func(afterSeq int64) ([]messageStreamEvent, error) {
+		items, err := h.service.AdminListWorkflowMessagesAfter(adminID, afterSeq, messageStreamBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		events := make([]messageStreamEvent, 0, len(items))
+		for _, item := range items {
+			events = append(events, messageStreamEvent{Seq: item.Seq, Name: "workflow_message", Data: item})
+		}
+		return events, nil
+	})
+}
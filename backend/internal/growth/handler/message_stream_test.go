@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/middleware"
+	"sercherai/backend/internal/platform/session"
+	"sercherai/backend/internal/platform/streamticket"
+)
+
+type sseEvent struct {
+	ID   string
+	Name string
+	Data string
+}
+
+func openMessageStream(t *testing.T, ctx context.Context, url string, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", got)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event.Name != "" {
+				return event
+			}
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestStreamMessagesPushesNotificationsAndResumesAfterLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthRepo := repo.NewInMemoryGrowthRepo()
+	growthHandler := NewUserGrowthHandler(service.NewGrowthService(growthRepo), config.Config{})
+	router := gin.New()
+	attachUserID(router, "u_stream_001")
+	router.GET("/api/v1/messages/stream", growthHandler.StreamMessages)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := openMessageStream(t, ctx, server.URL+"/api/v1/messages/stream", "")
+	if ready := readSSEEvent(t, stream); ready.Name != "ready" || !strings.Contains(ready.Data, `"mode":"polling"`) {
+		t.Fatalf("expected polling ready event without redis, got %+v", ready)
+	}
+	if err := growthRepo.CreateCommunityNotification(model.CommunityNotificationInput{
+		UserID: "u_stream_001", Title: "有人回复了你", Content: "第一条", MessageType: "COMMUNITY",
+	}); err != nil {
+		t.Fatalf("CreateCommunityNotification() error = %v", err)
+	}
+	first := readSSEEvent(t, stream)
+	if first.Name != "community_notification" || !strings.Contains(first.Data, "第一条") || first.ID == "" {
+		t.Fatalf("expected pushed community notification, got %+v", first)
+	}
+
+	if err := growthRepo.CreateCommunityNotification(model.CommunityNotificationInput{
+		UserID: "u_stream_001", Title: "有人回复了你", Content: "第二条", MessageType: "COMMUNITY",
+	}); err != nil {
+		t.Fatalf("CreateCommunityNotification() error = %v", err)
+	}
+	resumed := openMessageStream(t, ctx, server.URL+"/api/v1/messages/stream", first.ID)
+	readSSEEvent(t, resumed)
+	if next := readSSEEvent(t, resumed); !strings.Contains(next.Data, "第二条") {
+		t.Fatalf("expected resume to skip the delivered message, got %+v", next)
+	}
+}
+
+func TestMessageStreamReplayPicksUpLateCommits(t *testing.T) {
+	start := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	replay := newMessageStreamReplay(10)
+
+	// Rows 11 and 13 are visible; 12 is still in an open transaction.
+	for _, seq := range []int64{11, 13} {
+		if !replay.fresh(seq) {
+			t.Fatalf("expected seq %d to be delivered", seq)
+		}
+	}
+	replay.settle(start)
+	if replay.cursor != 13 || replay.floor != 10 {
+		t.Fatalf("expected cursor 13 and floor 10, got cursor=%d floor=%d", replay.cursor, replay.floor)
+	}
+
+	// 12 commits; the next read starts at the floor and delivers only it.
+	delivered := make([]int64, 0)
+	for _, seq := range []int64{11, 12, 13} {
+		if replay.fresh(seq) {
+			delivered = append(delivered, seq)
+		}
+	}
+	if len(delivered) != 1 || delivered[0] != 12 {
+		t.Fatalf("expected only the late row to be delivered, got %v", delivered)
+	}
+	replay.settle(start.Add(time.Second))
+
+	replay.settle(start.Add(messageStreamLateCommitWindow + time.Second))
+	if replay.floor != 13 || len(replay.delivered) != 0 {
+		t.Fatalf("expected the floor to catch up once the window passed, got floor=%d delivered=%v", replay.floor, replay.delivered)
+	}
+	if replay.fresh(12) {
+		t.Fatal("expected rows below the floor to be ignored")
+	}
+}
+
+func TestMessageStreamOpensWithASingleUseTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthHandler := NewUserGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{})
+	authHandler := &AuthHandler{streamTickets: streamticket.NewStore(nil)}
+	router := gin.New()
+	router.POST("/api/v1/auth/stream-ticket", func(c *gin.Context) {
+		c.Set("user_id", "u_stream_002")
+		c.Set("session_id", "sess_stream_002")
+		c.Set("role", "USER")
+		c.Next()
+	}, authHandler.IssueStreamTicket)
+	router.GET("/api/v1/messages/stream", middleware.StreamAuthRequired("jwt-secret", nil, authHandler.StreamTickets()), middleware.RoleRequired("USER"), growthHandler.StreamMessages)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/auth/stream-ticket", "")
+	if status != http.StatusOK {
+		t.Fatalf("issue ticket: status=%d", status)
+	}
+	var issued struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &issued); err != nil || issued.Ticket == "" || issued.ExpiresIn != 30 {
+		t.Fatalf("unexpected ticket %+v err=%v", issued, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := openMessageStream(t, ctx, server.URL+"/api/v1/messages/stream?ticket="+issued.Ticket, "")
+	if ready := readSSEEvent(t, stream); ready.Name != "ready" {
+		t.Fatalf("expected the ticket to open the stream, got %+v", ready)
+	}
+
+	for _, query := range []string{"?ticket=" + issued.Ticket, "?access_token=not-a-ticket"} {
+		resp, err := http.Get(server.URL + "/api/v1/messages/stream" + query)
+		if err != nil {
+			t.Fatalf("open stream %s: %v", query, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestMessageStreamClosesOnceTheSessionIsRevoked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	sessionRow := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id", "status", "expires_at"}).AddRow("u_stream_003", status, time.Now().Add(time.Hour))
+	}
+	mock.ExpectQuery("SELECT user_id, status, expires_at FROM auth_sessions").
+		WithArgs("sess_stream_003").
+		WillReturnRows(sessionRow(session.StatusActive))
+	mock.ExpectExec("UPDATE auth_sessions").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT user_id, status, expires_at FROM auth_sessions").
+		WithArgs("sess_stream_003").
+		WillReturnRows(sessionRow(session.StatusRevoked))
+
+	keepAlive := messageStreamKeepAlive
+	messageStreamKeepAlive = 50 * time.Millisecond
+	defer func() { messageStreamKeepAlive = keepAlive }()
+
+	growthHandler := NewUserGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{})
+	authHandler := &AuthHandler{streamTickets: streamticket.NewStore(nil)}
+	router := gin.New()
+	router.POST("/api/v1/auth/stream-ticket", func(c *gin.Context) {
+		c.Set("user_id", "u_stream_003")
+		c.Set("session_id", "sess_stream_003")
+		c.Set("role", "USER")
+		c.Next()
+	}, authHandler.IssueStreamTicket)
+	router.GET("/api/v1/messages/stream", middleware.StreamAuthRequired("jwt-secret", session.NewStore(db, nil), authHandler.StreamTickets()), growthHandler.StreamMessages)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/auth/stream-ticket", "")
+	var issued struct {
+		Ticket string `json:"ticket"`
+	}
+	if err := json.Unmarshal(data, &issued); status != http.StatusOK || err != nil || issued.Ticket == "" {
+		t.Fatalf("issue ticket: status=%d ticket=%+v err=%v", status, issued, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := openMessageStream(t, ctx, server.URL+"/api/v1/messages/stream?ticket="+issued.Ticket, "")
+	if ready := readSSEEvent(t, stream); ready.Name != "ready" {
+		t.Fatalf("expected the stream to open, got %+v", ready)
+	}
+	if revoked := readSSEEvent(t, stream); revoked.Name != "session_revoked" {
+		t.Fatalf("expected the stream to report the revoke, got %+v", revoked)
+	}
+	if _, err := stream.ReadString('\n'); err == nil {
+		t.Fatal("expected the stream to be closed after the revoke")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+// ScheduleNewsArticle sets a future publish time and/or an embargo on an
+// unpublished article. With only embargo_until the article is scheduled for
+// the embargo time, and a publish_at earlier than the embargo is moved to it.
+func (h *AdminGrowthHandler) ScheduleNewsArticle(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.NewsArticleScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	var publishAt, embargoUntil time.Time
+	if raw := strings.TrimSpace(req.PublishAt); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid publish_at, expected RFC3339", Data: struct{}{}})
+			return
+		}
+		publishAt = parsed
+	}
+	if raw := strings.TrimSpace(req.EmbargoUntil); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid embargo_until, expected RFC3339", Data: struct{}{}})
+			return
+		}
+		embargoUntil = parsed
+	}
+	if publishAt.IsZero() && embargoUntil.IsZero() {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "publish_at or embargo_until is required", Data: struct{}{}})
+		return
+	}
+	if publishAt.Before(embargoUntil) {
+		publishAt = embargoUntil
+	}
+	if !publishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "publish time must be in the future; use /publish to publish now", Data: struct{}{}})
+		return
+	}
+	if err := h.service.AdminScheduleNewsArticle(id, publishAt, embargoUntil); err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "NEWS", "SCHEDULE_ARTICLE", "NEWS_ARTICLE", id, "", model.NewsArticleStatusScheduled, publishAt.Format(time.RFC3339))
+	c.JSON(http.StatusOK, dto.OK(gin.H{
+		"id":                   id,
+		"status":               model.NewsArticleStatusScheduled,
+		"scheduled_publish_at": publishAt.Format(time.RFC3339),
+		"embargo_until":        formatOptionalNewsTime(embargoUntil),
+	}))
+}
+
+func (h *AdminGrowthHandler) CancelNewsArticleSchedule(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.AdminCancelNewsArticleSchedule(id); err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "NEWS", "CANCEL_ARTICLE_SCHEDULE", "NEWS_ARTICLE", id, model.NewsArticleStatusScheduled, model.NewsArticleStatusDraft, "")
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+func (h *AdminGrowthHandler) ListNewsArticleRevisions(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListNewsArticleRevisions(c.Param("id"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) GetNewsArticleRevision(c *gin.Context) {
+	revisionNo, ok := parseNewsRevisionNo(c, c.Param("revision_no"))
+	if !ok {
+		return
+	}
+	item, err := h.service.AdminGetNewsArticleRevision(c.Param("id"), revisionNo)
+	if err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// DiffNewsArticleRevision compares a revision with ?from= (default: the
+// revision just before it).
+func (h *AdminGrowthHandler) DiffNewsArticleRevision(c *gin.Context) {
+	revisionNo, ok := parseNewsRevisionNo(c, c.Param("revision_no"))
+	if !ok {
+		return
+	}
+	fromRevision := 0
+	if raw := strings.TrimSpace(c.Query("from")); raw != "" {
+		if fromRevision, ok = parseNewsRevisionNo(c, raw); !ok {
+			return
+		}
+	}
+	diff, err := h.service.AdminDiffNewsArticleRevisions(c.Param("id"), fromRevision, revisionNo)
+	if err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(diff))
+}
+
+func (h *AdminGrowthHandler) RestoreNewsArticleRevision(c *gin.Context) {
+	id := c.Param("id")
+	revisionNo, ok := parseNewsRevisionNo(c, c.Param("revision_no"))
+	if !ok {
+		return
+	}
+	item, err := h.service.AdminRestoreNewsArticleRevision(id, revisionNo, currentAdminOperator(c))
+	if err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "NEWS", "RESTORE_ARTICLE_REVISION", "NEWS_ARTICLE", id, strconv.Itoa(revisionNo), strconv.Itoa(item.RevisionNo), "")
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+func parseNewsRevisionNo(c *gin.Context, raw string) (int, bool) {
+	revisionNo, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || revisionNo <= 0 {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid revision number", Data: struct{}{}})
+		return 0, false
+	}
+	return revisionNo, true
+}
+
+func formatOptionalNewsTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
+
+func writeNewsArticleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "article not found", Data: struct{}{}})
+	case errors.Is(err, model.ErrNewsArticleRevisionNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40410, Message: "article revision not found", Data: struct{}{}})
+	case errors.Is(err, model.ErrNewsArticleStatusConflict):
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40910, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrNewsArticleMergeConflict):
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40911, Message: err.Error(), Data: struct{}{}})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestScheduleNewsArticleMovesPublishTimeToEmbargo(t *testing.T) {
+	growthHandler := newAdminGrowthHandlerForTest(t)
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/admin/news/articles/:id/schedule", growthHandler.ScheduleNewsArticle)
+
+	publishAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	embargoUntil := publishAt.Add(30 * time.Minute)
+	body, _ := json.Marshal(map[string]string{
+		"publish_at":    publishAt.Format(time.RFC3339),
+		"embargo_until": embargoUntil.Format(time.RFC3339),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/news/articles/na_001/schedule", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Data struct {
+			Status             string `json:"status"`
+			ScheduledPublishAt string `json:"scheduled_publish_at"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if payload.Data.Status != "SCHEDULED" || payload.Data.ScheduledPublishAt != embargoUntil.Format(time.RFC3339) {
+		t.Fatalf("expected publish to wait for the embargo, got %+v", payload.Data)
+	}
+
+	past, _ := json.Marshal(map[string]string{"publish_at": time.Now().Add(-time.Minute).Format(time.RFC3339)})
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/news/articles/na_001/schedule", bytes.NewReader(past))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected past publish_at to be rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+)
+
+// MergeNewsArticleDuplicate manually links an article into another article's
+// near-duplicate group, for stories the SimHash linking missed.
+func (h *AdminGrowthHandler) MergeNewsArticleDuplicate(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.NewsArticleMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	canonicalID := strings.TrimSpace(req.CanonicalID)
+	if err := h.service.AdminMergeNewsArticleDuplicate(id, canonicalID); err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "NEWS", "MERGE_ARTICLE_DUPLICATE", "NEWS_ARTICLE", id, "", canonicalID, "")
+	c.JSON(http.StatusOK, dto.OK(gin.H{"id": id, "canonical_id": canonicalID}))
+}
+
+func (h *AdminGrowthHandler) UnmergeNewsArticleDuplicate(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.AdminUnmergeNewsArticleDuplicate(id); err != nil {
+		writeNewsArticleError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "NEWS", "UNMERGE_ARTICLE_DUPLICATE", "NEWS_ARTICLE", id, "", "", "")
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
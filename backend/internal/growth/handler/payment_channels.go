@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+// sandboxLedger is shared by the user and admin handlers so a sandbox order
+// can be paid, queried and refunded within one process.
+var sandboxLedger = payment.NewSandboxLedger()
+
+// isGatewayPayChannel reports whether orders on payChannel are paid through
+// a payment.Channel integration rather than settled by hand.
+func isGatewayPayChannel(payChannel string) bool {
+	switch strings.ToUpper(strings.TrimSpace(payChannel)) {
+	case "YOLKPAY", "SANDBOX":
+		return true
+	}
+	return false
+}
+
+// paymentChannel builds the gateway for a pay_channel from the payment.*
+// configs. sandboxOutcome overrides payment.channel.sandbox.outcome for a
+// single order; SANDBOX is never available in production.
+func (h *UserGrowthHandler) paymentChannel(name string, sandboxOutcome string) (payment.Channel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "YOLKPAY":
+		cfg, err := h.resolveYolkPayConfig()
+		if err != nil {
+			return nil, fmt.Errorf("加载蛋黄支付配置失败: %w", err)
+		}
+		if !cfg.Enabled {
+			return nil, errors.New("payment.channel.yolkpay.enabled 未开启")
+		}
+		return payment.NewYolkPayChannel(cfg.channelConfig()), nil
+	case "SANDBOX":
+		if h.cfg.AppEnv == "production" {
+			return nil, errors.New("sandbox payment channel is disabled in production")
+		}
+		configMap, err := h.loadSystemConfigMap("payment.channel.sandbox.")
+		if err != nil {
+			return nil, err
+		}
+		outcome := strings.TrimSpace(sandboxOutcome)
+		if outcome == "" {
+			outcome = configMap[paymentChannelSandboxOutcomeConfigKey]
+		}
+		delaySeconds, _ := strconv.Atoi(configMap[paymentChannelSandboxDelayConfigKey])
+		return payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{
+			Outcome: outcome,
+			Delay:   time.Duration(delaySeconds) * time.Second,
+			Notify:  h.deliverSandboxCallback,
+		}), nil
+	}
+	return nil, fmt.Errorf("%w: %s", payment.ErrUnsupported, name)
+}
+
+func (h *UserGrowthHandler) createPaymentAction(c *gin.Context, order model.MembershipOrderAdmin, sandboxOutcome string) (payment.PaymentAction, error) {
+	if !strings.EqualFold(order.PayChannel, "SANDBOX") {
+		enabled, err := h.lookupSystemConfigValue(paymentEnabledConfigKey)
+		if err != nil {
+			return payment.PaymentAction{}, fmt.Errorf("加载支付配置失败: %w", err)
+		}
+		if !parseConfigBool(enabled, false) {
+			return payment.PaymentAction{}, errors.New("payment.enabled 未开启")
+		}
+	}
+	channel, err := h.paymentChannel(order.PayChannel, sandboxOutcome)
+	if err != nil {
+		return payment.PaymentAction{}, err
+	}
+	return channel.CreatePayment(payment.PaymentRequest{
+		OrderNo:  strings.TrimSpace(order.OrderNo),
+		UserID:   strings.TrimSpace(order.UserID),
+		Subject:  fmt.Sprintf("会员订阅-%s", strings.TrimSpace(order.OrderNo)),
+		Amount:   order.Amount,
+		ClientIP: c.ClientIP(),
+	})
+}
+
+// HandlePaymentNotify receives gateway notifications on
+// /payment/callbacks/:channel/notify. Gateways retry until they read
+// "success", so duplicates and non-paid statuses are acknowledged too.
+func (h *UserGrowthHandler) HandlePaymentNotify(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.String(http.StatusOK, "fail")
+		return
+	}
+	params := make(map[string]string, len(c.Request.Form))
+	for key, values := range c.Request.Form {
+		if len(values) == 0 {
+			continue
+		}
+		params[key] = strings.TrimSpace(values[0])
+	}
+	channel, err := h.paymentChannel(c.Param("channel"), "")
+	if err != nil {
+		c.String(http.StatusOK, "fail")
+		return
+	}
+	if err := h.settlePaymentCallback(channel, params); err != nil {
+		c.String(http.StatusOK, "fail")
+		return
+	}
+	c.String(http.StatusOK, "success")
+}
+
+// settlePaymentCallback verifies params with the channel and grants the
+// order once the trade is paid in full, in the order's currency.
+func (h *UserGrowthHandler) settlePaymentCallback(channel payment.Channel, params map[string]string) error {
+	callback, err := channel.ParseCallback(params)
+	if err != nil {
+		return err
+	}
+	if callback.Status != payment.TradeStatusPaid {
+		return nil
+	}
+	order, err := h.service.GetMembershipOrder(callback.OrderNo)
+	if err != nil {
+		return err
+	}
+	if err := callback.Matches(order.Amount, payment.CurrencyCNY); err != nil {
+		log.Printf("%s payment callback for %s rejected: %v", channel.Name(), callback.OrderNo, err)
+		return err
+	}
+	err = h.service.HandlePaymentCallback(channel.Name(), callback.OrderNo, callback.ChannelTxnNo, callback.IdempotencyKey, callback.Sign, true)
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "duplicate") {
+		return nil
+	}
+	return err
+}
+
+// deliverSandboxCallback stands in for the sandbox gateway POSTing to the
+// notify URL.
+func (h *UserGrowthHandler) deliverSandboxCallback(params map[string]string) {
+	channel := payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{})
+	if err := h.settlePaymentCallback(channel, params); err != nil {
+		log.Printf("sandbox payment callback for %s failed: %v", params["out_trade_no"], err)
+	}
+}
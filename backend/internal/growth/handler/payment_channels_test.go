@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/payment"
+)
+
+func createSandboxMembershipOrder(t *testing.T, growthHandler *UserGrowthHandler, userID string, body string) (int, map[string]any) {
+	t.Helper()
+	router := gin.New()
+	attachUserID(router, userID)
+	router.POST("/api/v1/membership/orders", growthHandler.CreateMembershipOrder)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/membership/orders", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var payload map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return rec.Code, payload
+}
+
+func findMembershipOrder(t *testing.T, growthService service.GrowthService, userID string, orderNo string) model.MembershipOrderAdmin {
+	t.Helper()
+	items, _, err := growthService.ListMembershipOrders(userID, "", 1, 20)
+	if err != nil {
+		t.Fatalf("ListMembershipOrders() error = %v", err)
+	}
+	for _, item := range items {
+		if item.OrderNo == orderNo {
+			return item
+		}
+	}
+	t.Fatalf("order %s not found in %+v", orderNo, items)
+	return model.MembershipOrderAdmin{}
+}
+
+func TestCreateMembershipOrderSandboxSuccessGrantsVIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	growthHandler := NewUserGrowthHandler(growthService, config.Config{})
+
+	code, payload := createSandboxMembershipOrder(t, growthHandler, "u_sandbox_ok", `{"product_id":"mp_demo_001","pay_channel":"SANDBOX"}`)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %+v", code, payload)
+	}
+	data := payload["data"].(map[string]any)
+	if data["payment_initialized"] != true {
+		t.Fatalf("expected sandbox payment initialized, got %+v", data)
+	}
+	orderNo := data["order_no"].(string)
+	order := findMembershipOrder(t, growthService, "u_sandbox_ok", orderNo)
+	if order.Status != "PAID" || order.PayChannel != "SANDBOX" {
+		t.Fatalf("expected order paid through sandbox, got %+v", order)
+	}
+	messages, _, err := growthService.ListMessages("u_sandbox_ok", 1, 20)
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	granted := false
+	for _, item := range messages {
+		granted = granted || item.Title == "VIP会员开通成功"
+	}
+	if !granted {
+		t.Fatalf("expected VIP grant message, got %+v", messages)
+	}
+}
+
+func TestCreateMembershipOrderSandboxFailureLeavesOrderPending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	growthHandler := NewUserGrowthHandler(growthService, config.Config{})
+
+	_, payload := createSandboxMembershipOrder(t, growthHandler, "u_sandbox_fail", `{"product_id":"mp_demo_001","pay_channel":"SANDBOX","sandbox_outcome":"FAILURE"}`)
+	orderNo := payload["data"].(map[string]any)["order_no"].(string)
+	if order := findMembershipOrder(t, growthService, "u_sandbox_fail", orderNo); order.Status != "PENDING" {
+		t.Fatalf("expected failed sandbox payment to keep order pending, got %+v", order)
+	}
+	trade, err := payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{}).QueryOrder(orderNo)
+	if err != nil || trade.Status != payment.TradeStatusFailed {
+		t.Fatalf("expected sandbox trade FAILED, got %+v err=%v", trade, err)
+	}
+}
+
+func TestSandboxDelayedCallbackPaysOrderLater(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	growthHandler := NewUserGrowthHandler(growthService, config.Config{})
+	order, err := growthService.CreateMembershipOrder("u_sandbox_delay", "mp_demo_001", "SANDBOX", "")
+	if err != nil {
+		t.Fatalf("CreateMembershipOrder() error = %v", err)
+	}
+
+	channel := payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{
+		Outcome: payment.SandboxOutcomeDelayed,
+		Delay:   20 * time.Millisecond,
+		Notify:  growthHandler.deliverSandboxCallback,
+	})
+	if _, err := channel.CreatePayment(payment.PaymentRequest{OrderNo: order.OrderNo, Amount: order.Amount}); err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	if got := findMembershipOrder(t, growthService, "u_sandbox_delay", order.OrderNo); got.Status != "PENDING" {
+		t.Fatalf("expected order pending before delayed callback, got %+v", got)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for findMembershipOrder(t, growthService, "u_sandbox_delay", order.OrderNo).Status != "PAID" {
+		if time.Now().After(deadline) {
+			t.Fatal("delayed sandbox callback never paid the order")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandlePaymentNotifyRejectsForgedSandboxCallback(t *testing.T) {
+	growthHandler := newUserGrowthHandlerForTest(t)
+	router := gin.New()
+	router.POST("/api/v1/payment/callbacks/:channel/notify", growthHandler.HandlePaymentNotify)
+
+	form := url.Values{}
+	form.Set("out_trade_no", "mo_demo_002")
+	form.Set("trade_no", "SBX0001")
+	form.Set("money", "99.00")
+	form.Set("trade_status", payment.TradeStatusPaid)
+	form.Set("sign", strings.Repeat("0", 64))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/callbacks/sandbox/notify", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "fail" {
+		t.Fatalf("expected forged callback rejected, got %q", rec.Body.String())
+	}
+}
+
+func TestCreateMembershipOrderRejectsSandboxInProduction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthHandler := NewUserGrowthHandler(service.NewGrowthService(repo.NewInMemoryGrowthRepo()), config.Config{AppEnv: "production"})
+
+	code, payload := createSandboxMembershipOrder(t, growthHandler, "u_sandbox_prod", `{"product_id":"mp_demo_001","pay_channel":"SANDBOX"}`)
+	if code != http.StatusBadRequest || int(payload["code"].(float64)) != 40002 {
+		t.Fatalf("expected 400/40002, got %d %+v", code, payload)
+	}
+}
+
+func TestSettlePaymentCallbackRejectsUnderpaidOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(repo.NewInMemoryGrowthRepo())
+	growthHandler := NewUserGrowthHandler(growthService, config.Config{})
+	order, err := growthService.CreateMembershipOrder("u_sandbox_underpaid", "mp_demo_001", "SANDBOX", "")
+	if err != nil {
+		t.Fatalf("CreateMembershipOrder() error = %v", err)
+	}
+
+	var delivered map[string]string
+	channel := payment.NewSandboxChannel(sandboxLedger, payment.SandboxConfig{Notify: func(params map[string]string) { delivered = params }})
+	if _, err := channel.CreatePayment(payment.PaymentRequest{OrderNo: order.OrderNo, Amount: 0.01}); err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	if err := growthHandler.settlePaymentCallback(channel, delivered); !errors.Is(err, payment.ErrAmountMismatch) {
+		t.Fatalf("expected ErrAmountMismatch for a signed underpaid callback, got %v", err)
+	}
+	if got := findMembershipOrder(t, growthService, "u_sandbox_underpaid", order.OrderNo); got.Status != "PENDING" {
+		t.Fatalf("expected underpaid order to stay pending, got %+v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/pii"
+)
+
+// Admin user views show phones and emails masked whatever the caller's
+// permissions. The full values of one user are only returned by
+// RevealUserPII, which takes a reason and leaves an audit event.
+
+func maskAdminUsers(items []model.AdminUser) {
+	for i := range items {
+		items[i].Phone = pii.MaskPhone(items[i].Phone)
+		items[i].Email = pii.MaskEmail(items[i].Email)
+	}
+}
+
+func maskUserProfile(profile model.UserProfile) model.UserProfile {
+	profile.Phone = pii.MaskPhone(profile.Phone)
+	profile.Email = pii.MaskEmail(profile.Email)
+	return profile
+}
+
+func maskBrowseHistories(items []model.AdminBrowseHistory) {
+	for i := range items {
+		items[i].UserPhone = pii.MaskPhone(items[i].UserPhone)
+	}
+}
+
+func maskUserMessages(items []model.AdminUserMessage) {
+	for i := range items {
+		items[i].UserPhone = pii.MaskPhone(items[i].UserPhone)
+	}
+}
+
+func maskBrowseUserSegments(items []model.AdminBrowseUserSegment) {
+	for i := range items {
+		items[i].UserPhone = pii.MaskPhone(items[i].UserPhone)
+	}
+}
+
+// RevealUserPII returns the unmasked phone and email of one user. It needs
+// users.pii_reveal and a reason, and records a PII_REVEALED audit event;
+// when that write fails nothing is revealed.
+func (h *AdminGrowthHandler) RevealUserPII(c *gin.Context) {
+	userID := strings.TrimSpace(c.Param("id"))
+	var req dto.PIIRevealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "reason required", Data: struct{}{}})
+		return
+	}
+	profile, err := h.service.GetUserProfile(userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "user not found", Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	// Fail closed: no audit event, no PII.
+	if err := h.recordAuditEvent(c, model.AdminAuditEvent{
+		EventDomain: "ACCESS",
+		EventType:   "PII_REVEALED",
+		Level:       "WARNING",
+		Module:      "USERS",
+		ObjectType:  "USER",
+		ObjectID:    profile.ID,
+		Title:       "查看用户完整个人信息",
+		Summary:     currentAdminOperator(c) + " 查看了用户 " + profile.ID + " 的手机号和邮箱",
+		Detail:      reason,
+		Status:      "OPEN",
+		Metadata:    map[string]any{"fields": "phone,email"},
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: "record audit event failed", Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"user_id": profile.ID, "phone": profile.Phone, "email": profile.Email}))
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/growth/service"
+	"sercherai/backend/internal/platform/config"
+)
+
+func TestAdminUserViewsAndExportsMaskPII(t *testing.T) {
+	growthHandler := newAdminGrowthHandlerForTest(t)
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.GET("/api/v1/admin/users", growthHandler.ListUsers)
+	router.GET("/api/v1/admin/users/export.csv", growthHandler.ExportUsersCSV)
+	router.GET("/api/v1/admin/users/:id/center-overview", growthHandler.GetUserCenterOverview)
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/users", "")
+	if status != http.StatusOK {
+		t.Fatalf("list users: status=%d", status)
+	}
+	var page struct {
+		Items []model.AdminUser `json:"items"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		t.Fatalf("unmarshal users: %v", err)
+	}
+	if len(page.Items) == 0 {
+		t.Fatal("expected demo users")
+	}
+	for _, item := range page.Items {
+		if !strings.Contains(item.Phone, "****") || (item.Email != "" && !strings.Contains(item.Email, "***@")) {
+			t.Fatalf("expected masked phone and email, got %+v", item)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/export.csv", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "13800000001") || !strings.Contains(rec.Body.String(), "138****0001") {
+		t.Fatalf("expected masked csv export, got status=%d body=%q", rec.Code, rec.Body.String())
+	}
+
+	status, _, data = serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/users/u_demo_001/center-overview", "")
+	if status != http.StatusOK {
+		t.Fatalf("center overview: status=%d", status)
+	}
+	var overview struct {
+		UserProfile model.UserProfile `json:"user_profile"`
+	}
+	if err := json.Unmarshal(data, &overview); err != nil {
+		t.Fatalf("unmarshal overview: %v", err)
+	}
+	if overview.UserProfile.Phone != "138****0001" || overview.UserProfile.Email != "d***@sercherai.local" {
+		t.Fatalf("expected masked profile, got %+v", overview.UserProfile)
+	}
+}
+
+func TestRevealUserPIIRequiresReasonAndRecordsAuditEvent(t *testing.T) {
+	growthHandler := newAdminGrowthHandlerForTest(t)
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/admin/users/:id/pii/reveal", growthHandler.RevealUserPII)
+
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/users/u_demo_001/pii/reveal", `{"reason":"  "}`)
+	if status != http.StatusBadRequest || code != 40002 {
+		t.Fatalf("expected blank reason to be rejected, got status=%d code=%d", status, code)
+	}
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/users/u_demo_001/pii/reveal", `{"reason":"客服工单 T-1024 回访 13800000001"}`)
+	if status != http.StatusOK {
+		t.Fatalf("reveal: status=%d", status)
+	}
+	var revealed struct {
+		Phone string `json:"phone"`
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(data, &revealed); err != nil {
+		t.Fatalf("unmarshal reveal: %v", err)
+	}
+	if revealed.Phone != "13800000001" || revealed.Email != "demo@sercherai.local" {
+		t.Fatalf("expected unmasked values, got %+v", revealed)
+	}
+
+	events, _, err := growthHandler.service.AdminListAuditEvents(model.AdminAuditEventFilter{EventType: "PII_REVEALED"}, 1, 10)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected one PII_REVEALED event, got %+v err=%v", events, err)
+	}
+	if events[0].ObjectID != "u_demo_001" || events[0].ActorUserID != "admin_001" || events[0].Detail != "客服工单 T-1024 回访 138****0001" {
+		t.Fatalf("unexpected audit event %+v", events[0])
+	}
+}
+
+// failingAuditRepo loses every audit event write.
+type failingAuditRepo struct {
+	repo.GrowthRepo
+}
+
+func (failingAuditRepo) AdminCreateAuditEvent(item model.AdminAuditEvent) error {
+	return errors.New("audit store unavailable")
+}
+
+func TestRevealPIIFailsClosedWhenTheAuditWriteFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	growthService := service.NewGrowthService(failingAuditRepo{GrowthRepo: repo.NewInMemoryGrowthRepo()})
+	growthHandler := NewAdminGrowthHandler(growthService, config.Config{})
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	authHandler := &AuthHandler{db: db, operationLogs: growthService}
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.POST("/api/v1/admin/users/:id/pii/reveal", growthHandler.RevealUserPII)
+	router.POST("/api/v1/admin/auth/login-logs/:id/pii/reveal", authHandler.AdminRevealLoginLogPII)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, phone, ip FROM auth_login_logs WHERE id = ?")).
+		WithArgs("ll_001").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "phone", "ip"}).AddRow("u_demo_001", "13800000001", "203.0.113.45"))
+
+	for _, target := range []string{"/api/v1/admin/users/u_demo_001/pii/reveal", "/api/v1/admin/auth/login-logs/ll_001/pii/reveal"} {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"reason":"客服工单 T-1024"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("%s: expected 500, got %d: %s", target, rec.Code, rec.Body.String())
+		}
+		if body := rec.Body.String(); strings.Contains(body, "13800000001") || strings.Contains(body, "demo@sercherai.local") || strings.Contains(body, "203.0.113.45") {
+			t.Fatalf("%s: expected no PII in the response, got %s", target, body)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestAdminListAdminUsersMasksPhoneAndEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	authHandler := &AuthHandler{db: db}
+	router := gin.New()
+	router.GET("/api/v1/admin/access/admin-users", authHandler.AdminListAdminUsers)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users u")).
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT u.id, u.phone, u.email, u.status, u.created_at")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "phone", "email", "status", "created_at"}).
+			AddRow("admin_003", "13900000003", "ops@sercherai.local", "ACTIVE", time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM rbac_user_roles ur")).
+		WithArgs("admin_003").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "id", "role_key", "role_name"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth_login_logs")).
+		WithArgs("admin_003").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "last_login_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, status FROM auth_user_mfa")).
+		WithArgs("admin_003").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}))
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/access/admin-users", "")
+	if status != http.StatusOK {
+		t.Fatalf("list admin users: status=%d", status)
+	}
+	var page struct {
+		Items []model.AdminAccount `json:"items"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		t.Fatalf("unmarshal admin users: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Phone != "139****0003" || page.Items[0].Email != "o***@sercherai.local" {
+		t.Fatalf("expected masked admin accounts, got %+v", page.Items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+func (h *AdminGrowthHandler) ListReviewSLAPolicies(c *gin.Context) {
+	items, err := h.service.AdminListReviewSLAPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "total": len(items)}))
+}
+
+func (h *AdminGrowthHandler) SaveReviewSLAPolicy(c *gin.Context) {
+	var req dto.ReviewSLAPolicySaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	item, err := h.service.AdminSaveReviewSLAPolicy(model.ReviewSLAPolicy{
+		Module:            c.Param("module"),
+		Status:            req.Status,
+		HighMinutes:       req.HighMinutes,
+		NormalMinutes:     req.NormalMinutes,
+		LowMinutes:        req.LowMinutes,
+		BusinessHoursOnly: req.BusinessHoursOnly,
+		BusinessStart:     req.BusinessStart,
+		BusinessEnd:       req.BusinessEnd,
+		Workdays:          req.Workdays,
+		Timezone:          req.Timezone,
+		BackupReviewerID:  req.BackupReviewerID,
+		BackupRole:        req.BackupRole,
+	}, currentAdminOperator(c))
+	if err != nil {
+		if errors.Is(err, model.ErrReviewSLAPolicyInvalid) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "WORKFLOW", "SAVE_REVIEW_SLA_POLICY", "REVIEW_SLA_POLICY", item.Module, "", item.Status, "")
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// ListReviewSLAItems lists SLA clocks, soonest due first. status accepts a
+// comma-separated list, e.g. OPEN,BREACHED for everything still waiting.
+func (h *AdminGrowthHandler) ListReviewSLAItems(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListReviewSLAItems(c.Query("module"), c.Query("status"), c.Query("assignee_id"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) ReviewSLAMetrics(c *gin.Context) {
+	days, _ := strconv.Atoi(strings.TrimSpace(c.DefaultQuery("days", "30")))
+	item, err := h.service.AdminGetReviewSLAMetrics(c.Query("module"), c.Query("reviewer_id"), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
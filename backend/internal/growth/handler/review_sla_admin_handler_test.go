@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func newReviewSLARouterForTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	growthHandler := newAdminGrowthHandlerForTest(t)
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.PUT("/api/v1/admin/workflow/sla/policies/:module", growthHandler.SaveReviewSLAPolicy)
+	router.GET("/api/v1/admin/workflow/sla/items", growthHandler.ListReviewSLAItems)
+	router.GET("/api/v1/admin/workflow/sla/metrics", growthHandler.ReviewSLAMetrics)
+	router.POST("/api/v1/admin/workflow/reviews/submit", growthHandler.SubmitReviewTask)
+	return router
+}
+
+func TestSaveReviewSLAPolicyRejectsInvalidBusinessHours(t *testing.T) {
+	router := newReviewSLARouterForTest(t)
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/workflow/sla/policies/NEWS", `{
+		"status":"ACTIVE","high_minutes":30,"normal_minutes":60,"low_minutes":120,
+		"business_hours_only":true,"business_start":"18:00","business_end":"09:00"
+	}`)
+	if status != http.StatusBadRequest || code != 40001 {
+		t.Fatalf("expected invalid hours to be rejected, got status=%d code=%d", status, code)
+	}
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/workflow/sla/policies/UNKNOWN", `{
+		"status":"ACTIVE","high_minutes":30,"normal_minutes":60,"low_minutes":120
+	}`)
+	if status != http.StatusBadRequest || code != 40001 {
+		t.Fatalf("expected unknown module to be rejected, got status=%d code=%d", status, code)
+	}
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/workflow/sla/policies/NEWS", `{
+		"status":"ACTIVE","high_minutes":30,"normal_minutes":60,"low_minutes":120,"timezone":"Asia/Shangai"
+	}`)
+	if status != http.StatusBadRequest || code != 40001 {
+		t.Fatalf("expected unknown timezone to be rejected, got status=%d code=%d", status, code)
+	}
+}
+
+func TestSubmitReviewTaskStartsSLAClockByPriority(t *testing.T) {
+	router := newReviewSLARouterForTest(t)
+	status, _, _ := serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/workflow/sla/policies/NEWS", `{
+		"status":"ACTIVE","high_minutes":15,"normal_minutes":60,"low_minutes":240,"backup_role":"OPS_ADMIN"
+	}`)
+	if status != http.StatusOK {
+		t.Fatalf("save policy: status=%d", status)
+	}
+	status, _, _ = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/workflow/reviews/submit", `{
+		"module":"NEWS","target_id":"na_sla_1","reviewer_id":"admin_002","priority":"HIGH"
+	}`)
+	if status != http.StatusOK {
+		t.Fatalf("submit review: status=%d", status)
+	}
+
+	status, _, data := serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/workflow/sla/items?module=NEWS&status=OPEN,BREACHED", "")
+	if status != http.StatusOK {
+		t.Fatalf("list sla items: status=%d", status)
+	}
+	var page struct {
+		Items []model.ReviewSLAItem `json:"items"`
+		Total int                   `json:"total"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		t.Fatalf("unmarshal sla items: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].Priority != "HIGH" || page.Items[0].AssigneeID != "admin_002" || page.Items[0].DueAt == "" {
+		t.Fatalf("unexpected sla items %+v", page)
+	}
+
+	status, _, data = serveAdminJSONRequest(t, router, http.MethodGet, "/api/v1/admin/workflow/sla/metrics?module=NEWS", "")
+	if status != http.StatusOK {
+		t.Fatalf("sla metrics: status=%d", status)
+	}
+	var metrics model.ReviewSLAMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("unmarshal sla metrics: %v", err)
+	}
+	if metrics.Open != 1 || len(metrics.Reviewers) != 1 || metrics.Reviewers[0].ReviewerID != "admin_002" {
+		t.Fatalf("unexpected sla metrics %+v", metrics)
+	}
+}
@@ -0,0 +1,420 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+func (h *AdminGrowthHandler) ListSchedulerPipelines(c *gin.Context) {
+	items, err := h.service.AdminListSchedulerPipelines(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "total": len(items)}))
+}
+
+func (h *AdminGrowthHandler) SaveSchedulerPipeline(c *gin.Context) {
+	var req dto.SchedulerPipelineSaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	pipeline := model.SchedulerPipeline{
+		PipelineKey: c.Param("key"),
+		DisplayName: req.DisplayName,
+		Status:      req.Status,
+		Nodes:       make([]model.SchedulerPipelineNode, 0, len(req.Nodes)),
+	}
+	for _, node := range req.Nodes {
+		if !isSupportedSchedulerJob(node.JobName) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "unsupported job: " + node.JobName, Data: struct{}{}})
+			return
+		}
+		pipeline.Nodes = append(pipeline.Nodes, model.SchedulerPipelineNode{JobName: node.JobName, DependsOn: node.DependsOn})
+	}
+	item, err := h.service.AdminSaveSchedulerPipeline(pipeline, currentAdminOperator(c))
+	if err != nil {
+		writeSchedulerPipelineError(c, err)
+		return
+	}
+	h.writeOperationLog(c, "SCHEDULER", "SAVE_PIPELINE", "PIPELINE", item.PipelineKey, "", item.Status, fmt.Sprintf("nodes=%d", len(item.Nodes)))
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// TriggerSchedulerPipeline runs a pipeline for a trade date (today by
+// default). Triggering a date that already has a run resumes it: nodes that
+// succeeded are kept and the rest run again once their upstreams succeed.
+func (h *AdminGrowthHandler) TriggerSchedulerPipeline(c *gin.Context) {
+	var req dto.SchedulerPipelineRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	tradeDate := strings.TrimSpace(req.TradeDate)
+	if tradeDate == "" {
+		tradeDate = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", tradeDate); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "invalid trade_date, expected YYYY-MM-DD", Data: struct{}{}})
+		return
+	}
+	triggerSource := strings.TrimSpace(req.TriggerSource)
+	if triggerSource == "" {
+		triggerSource = "MANUAL"
+	}
+	pipeline, err := h.service.AdminGetSchedulerPipeline(c.Param("key"))
+	if err != nil {
+		writeSchedulerPipelineError(c, err)
+		return
+	}
+	if pipeline.Status != model.SchedulerPipelineStatusActive {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: "pipeline is disabled", Data: struct{}{}})
+		return
+	}
+	operator := currentAdminOperator(c)
+	run, err := h.service.AdminClaimSchedulerPipelineRun(pipeline, tradeDate, triggerSource, operator, nil)
+	if err != nil {
+		writeSchedulerPipelineError(c, err)
+		return
+	}
+	run, err = h.executeSchedulerPipelineRun(run, operator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "SCHEDULER", "TRIGGER_PIPELINE", "PIPELINE_RUN", run.ID, "", run.Status, fmt.Sprintf("pipeline=%s trade_date=%s", run.PipelineKey, run.TradeDate))
+	c.JSON(http.StatusOK, dto.OK(run))
+}
+
+func (h *AdminGrowthHandler) ListSchedulerPipelineRuns(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.AdminListSchedulerPipelineRuns(c.Query("pipeline_key"), c.Query("trade_date"), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *AdminGrowthHandler) GetSchedulerPipelineRun(c *gin.Context) {
+	run, err := h.service.AdminGetSchedulerPipelineRun(c.Param("id"))
+	if err != nil {
+		writeSchedulerPipelineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(run))
+}
+
+// RerunSchedulerPipelineNode resets a node and everything downstream of it
+// to PENDING and runs them again for the run's trade date.
+func (h *AdminGrowthHandler) RerunSchedulerPipelineNode(c *gin.Context) {
+	run, err := h.service.AdminGetSchedulerPipelineRun(c.Param("id"))
+	if err != nil {
+		writeSchedulerPipelineError(c, err)
+		return
+	}
+	jobName := strings.ToLower(strings.TrimSpace(c.Param("job_name")))
+	found := false
+	for _, node := range run.Nodes {
+		if node.JobName == jobName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40409, Message: "pipeline node not found", Data: struct{}{}})
+		return
+	}
+	operator := currentAdminOperator(c)
+	reset := run.Downstream(jobName)
+	run, err = h.service.AdminClaimSchedulerPipelineRun(model.SchedulerPipeline{PipelineKey: run.PipelineKey}, run.TradeDate, "MANUAL", operator, reset)
+	if err != nil {
+		writeSchedulerPipelineError(c, err)
+		return
+	}
+	run, err = h.executeSchedulerPipelineRun(run, operator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.writeOperationLog(c, "SCHEDULER", "RERUN_PIPELINE_NODE", "PIPELINE_RUN", run.ID, "", run.Status, fmt.Sprintf("node=%s reset=%s", jobName, strings.Join(reset, ",")))
+	c.JSON(http.StatusOK, dto.OK(run))
+}
+
+// executeSchedulerPipelineRun walks the claimed run's nodes in topological
+// order. Succeeded nodes are kept, a node whose upstream did not succeed is
+// marked BLOCKED without running, and every executed node is also recorded
+// as a scheduler job run with trigger source PIPELINE.
+func (h *AdminGrowthHandler) executeSchedulerPipelineRun(run model.SchedulerPipelineRun, operator string) (model.SchedulerPipelineRun, error) {
+	statuses := make(map[string]string, len(run.Nodes))
+	for i := range run.Nodes {
+		node := &run.Nodes[i]
+		if node.Status == model.SchedulerPipelineNodeSuccess {
+			statuses[node.JobName] = node.Status
+			continue
+		}
+		blockedBy := ""
+		for _, upstream := range node.DependsOn {
+			if statuses[upstream] != model.SchedulerPipelineNodeSuccess {
+				blockedBy = upstream
+				break
+			}
+		}
+		now := time.Now().Format(time.RFC3339)
+		if blockedBy != "" {
+			node.Status = model.SchedulerPipelineNodeBlocked
+			node.BlockedBy = blockedBy
+			node.ResultSummary = ""
+			node.ErrorMessage = fmt.Sprintf("upstream %s is %s", blockedBy, statuses[blockedBy])
+			node.StartedAt = ""
+			node.FinishedAt = now
+			statuses[node.JobName] = node.Status
+			if err := h.service.AdminUpdateSchedulerPipelineNodeRun(*node); err != nil {
+				return run, err
+			}
+			continue
+		}
+
+		node.Status = model.SchedulerPipelineNodeRunning
+		node.Attempt++
+		node.BlockedBy = ""
+		node.ErrorMessage = ""
+		node.StartedAt = now
+		node.FinishedAt = ""
+		if err := h.service.AdminUpdateSchedulerPipelineNodeRun(*node); err != nil {
+			return run, err
+		}
+		result, runErr := h.runSchedulerPipelineJob(node.JobName, run.TradeDate, operator)
+		node.Status = model.SchedulerPipelineNodeSuccess
+		node.ResultSummary = result.Summary
+		if runErr != nil {
+			node.Status = model.SchedulerPipelineNodeFailed
+			node.ErrorMessage = runErr.Error()
+		}
+		node.FinishedAt = time.Now().Format(time.RFC3339)
+		if jobRunID, err := h.service.AdminCreateSchedulerJobRun(node.JobName, "PIPELINE", node.Status, node.ResultSummary, node.ErrorMessage, operator); err == nil {
+			node.JobRunID = jobRunID
+		}
+		statuses[node.JobName] = node.Status
+		if err := h.service.AdminUpdateSchedulerPipelineNodeRun(*node); err != nil {
+			return run, err
+		}
+	}
+
+	run.Status = model.SchedulerPipelineRunSuccess
+	for _, node := range run.Nodes {
+		if node.Status != model.SchedulerPipelineNodeSuccess {
+			run.Status = model.SchedulerPipelineRunFailed
+			break
+		}
+	}
+	if err := h.service.AdminFinishSchedulerPipelineRun(run.ID, run.Status); err != nil {
+		return run, err
+	}
+	run.FinishedAt = time.Now().Format(time.RFC3339)
+	return run, nil
+}
+
+// runSchedulerPipelineJob runs a pipeline node for the run's trade date.
+// Jobs without a trade date fall back to their regular scheduler behaviour.
+func (h *AdminGrowthHandler) runSchedulerPipelineJob(jobName string, tradeDate string, operator string) (schedulerJobExecutionResult, error) {
+	switch strings.ToLower(strings.TrimSpace(jobName)) {
+	case schedulerJobStockMasterSync, schedulerJobStockQuotesSync, schedulerJobStockDailyBasicSync, schedulerJobStockMoneyflowSync,
+		schedulerJobStockTruthRebuild, schedulerJobStockQuantRank, schedulerJobDailyStockRecommendation,
+		schedulerJobStockSelectionRun, schedulerJobStockSelectionPublishReview:
+		return h.runTradeDateSchedulerJob(jobName, tradeDate, operator)
+	default:
+		return h.runSchedulerJob(jobName, model.TushareNewsSyncOptions{})
+	}
+}
+
+// runTradeDateSchedulerJob runs the daily stock market stages for one trade
+// date. Syncs cover a few days back to the trade date so a late rerun still
+// fills it.
+func (h *AdminGrowthHandler) runTradeDateSchedulerJob(jobName string, tradeDate string, operator string) (schedulerJobExecutionResult, error) {
+	sourceKey := h.resolveDefaultStockQuoteSourceKey()
+	days := schedulerPipelineSyncDays(tradeDate)
+	switch strings.ToLower(strings.TrimSpace(jobName)) {
+	case schedulerJobStockMasterSync:
+		result, err := h.service.AdminSyncStockInstrumentMaster(sourceKey, nil)
+		return schedulerJobExecutionResult{Summary: formatSchedulerPipelineSyncSummary(tradeDate, result)}, err
+	case schedulerJobStockQuotesSync:
+		result, err := h.service.AdminSyncStockQuotesFromMaster(sourceKey, days)
+		return schedulerJobExecutionResult{Summary: formatSchedulerPipelineSyncSummary(tradeDate, result)}, err
+	case schedulerJobStockDailyBasicSync:
+		result, err := h.service.AdminSyncStockDailyBasics(sourceKey, nil, days)
+		return schedulerJobExecutionResult{Summary: formatSchedulerPipelineSyncSummary(tradeDate, result)}, err
+	case schedulerJobStockMoneyflowSync:
+		result, err := h.service.AdminSyncStockMoneyflows(sourceKey, nil, days)
+		return schedulerJobExecutionResult{Summary: formatSchedulerPipelineSyncSummary(tradeDate, result)}, err
+	case schedulerJobStockTruthRebuild:
+		to, _ := time.Parse("2006-01-02", tradeDate)
+		from := to.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+		result, err := h.service.AdminRebuildMarketDailyTruthDetailed("STOCK", sourceKey, nil, from, tradeDate)
+		return schedulerJobExecutionResult{Summary: formatSchedulerPipelineSyncSummary(tradeDate, result)}, err
+	case schedulerJobStockQuantRank:
+		items, err := h.service.AdminGetQuantTopStocksForTradeDate(tradeDate, 10, 180)
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		if len(items) == 0 {
+			return schedulerJobExecutionResult{}, fmt.Errorf("no quant scores available for trade_date=%s", tradeDate)
+		}
+		return schedulerJobExecutionResult{Summary: fmt.Sprintf("trade_date=%s top=%d leader=%s", tradeDate, len(items), items[0].Symbol)}, nil
+	case schedulerJobDailyStockRecommendation:
+		result, err := h.service.AdminGenerateDailyStockRecommendations(tradeDate)
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: fmt.Sprintf("trade_date=%s recommendations=%d", tradeDate, result.Count)}, nil
+	case schedulerJobStockSelectionRun:
+		run, err := h.service.AdminCreateStockSelectionRun(model.StockSelectionRunCreateRequest{TradeDate: tradeDate, CompareWithLastPublished: true}, operator)
+		if err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: fmt.Sprintf("trade_date=%s run_id=%s status=%s selected=%d", tradeDate, run.RunID, run.Status, run.SelectedCount)}, nil
+	case schedulerJobStockSelectionPublishReview:
+		return h.submitStockSelectionForPublishReview(tradeDate, operator)
+	default:
+		return schedulerJobExecutionResult{}, fmt.Errorf("unknown job: %s", jobName)
+	}
+}
+
+// submitStockSelectionForPublishReview hands the trade date's finished
+// selection run to the publish reviewers through a workflow message; the
+// run's publish review itself is created PENDING when the run completes.
+func (h *AdminGrowthHandler) submitStockSelectionForPublishReview(tradeDate string, operator string) (schedulerJobExecutionResult, error) {
+	runs, _, err := h.service.AdminListStockSelectionRuns("SUCCEEDED", "PENDING", "", 1, 50)
+	if err != nil {
+		return schedulerJobExecutionResult{}, err
+	}
+	for _, run := range runs {
+		if run.TradeDate != tradeDate {
+			continue
+		}
+		if err := h.service.AdminCreateWorkflowMessage(
+			"",
+			run.RunID,
+			"STOCK_SELECTION",
+			"",
+			operator,
+			"PUBLISH_REVIEW_READY",
+			"选股结果待发布审核",
+			fmt.Sprintf("交易日 %s 的选股运行 %s 已完成，入选 %d 只，等待发布审核", tradeDate, run.RunID, run.SelectedCount),
+		); err != nil {
+			return schedulerJobExecutionResult{}, err
+		}
+		return schedulerJobExecutionResult{Summary: fmt.Sprintf("trade_date=%s run_id=%s review=PENDING", tradeDate, run.RunID)}, nil
+	}
+	return schedulerJobExecutionResult{}, fmt.Errorf("no succeeded stock selection run awaiting review for trade_date=%s", tradeDate)
+}
+
+// schedulerJobPipelineBlocker explains why a standalone trigger, manual
+// retry or auto retry of jobName must wait: it names the first direct upstream in an active pipeline that
+// has not succeeded for today's trade date. Jobs outside pipelines, and
+// pipeline roots, are never blocked.
+func (h *AdminGrowthHandler) schedulerJobPipelineBlocker(jobName string) (string, error) {
+	jobName = strings.ToLower(strings.TrimSpace(jobName))
+	pipelines, err := h.service.AdminListSchedulerPipelines(model.SchedulerPipelineStatusActive)
+	if err != nil {
+		return "", err
+	}
+	tradeDate := time.Now().Format("2006-01-02")
+	for _, pipeline := range pipelines {
+		var dependsOn []string
+		for _, node := range pipeline.Nodes {
+			if node.JobName == jobName {
+				dependsOn = node.DependsOn
+				break
+			}
+		}
+		if len(dependsOn) == 0 {
+			continue
+		}
+		run, err := h.service.AdminFindSchedulerPipelineRun(pipeline.PipelineKey, tradeDate)
+		if errors.Is(err, model.ErrSchedulerPipelineRunNotFound) {
+			return fmt.Sprintf("blocked by pipeline %s: no run for trade_date %s yet", pipeline.PipelineKey, tradeDate), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		statuses := make(map[string]string, len(run.Nodes))
+		for _, node := range run.Nodes {
+			statuses[node.JobName] = node.Status
+		}
+		for _, upstream := range dependsOn {
+			if status := statuses[upstream]; status != model.SchedulerPipelineNodeSuccess {
+				if status == "" {
+					status = model.SchedulerPipelineNodePending
+				}
+				return fmt.Sprintf("blocked by pipeline %s: upstream %s is %s for trade_date %s", pipeline.PipelineKey, upstream, status, tradeDate), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func isSupportedSchedulerJob(jobName string) bool {
+	jobName = strings.ToLower(strings.TrimSpace(jobName))
+	for _, item := range supportedSchedulerJobs {
+		if item.JobName == jobName {
+			return true
+		}
+	}
+	return false
+}
+
+func schedulerPipelineSyncDays(tradeDate string) int {
+	days := 5
+	if parsed, err := time.ParseInLocation("2006-01-02", tradeDate, time.Local); err == nil {
+		if back := int(time.Since(parsed).Hours()/24) + 1; back > days {
+			days = back
+		}
+	}
+	if days > 365 {
+		days = 365
+	}
+	return days
+}
+
+func formatSchedulerPipelineSyncSummary(tradeDate string, result model.MarketSyncResult) string {
+	source := result.SelectedSource
+	if source == "" {
+		source = result.RequestedSourceKey
+	}
+	return fmt.Sprintf(
+		"trade_date=%s kind=%s source=%s bars=%d truth=%d inserted=%d updated=%d",
+		tradeDate,
+		result.DataKind,
+		source,
+		result.BarCount,
+		result.TruthCount,
+		result.InsertedCount,
+		result.UpdatedCount,
+	)
+}
+
+func writeSchedulerPipelineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrSchedulerPipelineInvalid):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrSchedulerPipelineNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40409, Message: "pipeline not found", Data: struct{}{}})
+	case errors.Is(err, model.ErrSchedulerPipelineRunNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40409, Message: "pipeline run not found", Data: struct{}{}})
+	case errors.Is(err, model.ErrSchedulerPipelineRunBusy):
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40909, Message: err.Error(), Data: struct{}{}})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func newSchedulerPipelineRouterForTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	growthHandler := newAdminGrowthHandlerForTest(t)
+	router := gin.New()
+	attachUserID(router, "admin_001")
+	router.PUT("/api/v1/admin/system/pipelines/:key", growthHandler.SaveSchedulerPipeline)
+	router.POST("/api/v1/admin/system/pipelines/:key/runs", growthHandler.TriggerSchedulerPipeline)
+	router.POST("/api/v1/admin/system/pipeline-runs/:id/nodes/:job_name/rerun", growthHandler.RerunSchedulerPipelineNode)
+	router.POST("/api/v1/admin/system/job-runs/trigger", growthHandler.TriggerSchedulerJob)
+	router.POST("/api/v1/admin/system/job-runs/:id/retry", growthHandler.RetrySchedulerJobRun)
+	return router
+}
+
+func schedulerPipelineNodeStatuses(t *testing.T, data json.RawMessage) (model.SchedulerPipelineRun, map[string]model.SchedulerPipelineNodeRun) {
+	t.Helper()
+	var run model.SchedulerPipelineRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		t.Fatalf("unmarshal run: %v", err)
+	}
+	nodes := make(map[string]model.SchedulerPipelineNodeRun, len(run.Nodes))
+	for _, node := range run.Nodes {
+		nodes[node.JobName] = node
+	}
+	return run, nodes
+}
+
+func TestSaveSchedulerPipelineRejectsCycle(t *testing.T) {
+	router := newSchedulerPipelineRouterForTest(t)
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/system/pipelines/loop", `{
+		"status":"ACTIVE",
+		"nodes":[
+			{"job_name":"stock_quotes_sync","depends_on":["stock_truth_rebuild"]},
+			{"job_name":"stock_truth_rebuild","depends_on":["stock_quotes_sync"]}
+		]
+	}`)
+	if status != http.StatusBadRequest || code != 40001 {
+		t.Fatalf("expected cycle to be rejected with 40001, got status=%d code=%d", status, code)
+	}
+}
+
+func TestSchedulerPipelineFailureBlocksDownstreamAndRerunResetsIt(t *testing.T) {
+	router := newSchedulerPipelineRouterForTest(t)
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/system/pipelines/review_chain", `{
+		"display_name":"审核链路",
+		"status":"ACTIVE",
+		"nodes":[
+			{"job_name":"daily_stock_recommendation","depends_on":["stock_selection_publish_review"]},
+			{"job_name":"stock_selection_publish_review","depends_on":["stock_master_sync"]},
+			{"job_name":"stock_master_sync"}
+		]
+	}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("save pipeline: status=%d code=%d", status, code)
+	}
+
+	// No selection run exists for this trade date, so the review node fails.
+	status, code, data := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/system/pipelines/review_chain/runs", `{"trade_date":"2020-01-02"}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("trigger pipeline: status=%d code=%d", status, code)
+	}
+	run, nodes := schedulerPipelineNodeStatuses(t, data)
+	if run.Status != model.SchedulerPipelineRunFailed || run.Nodes[0].JobName != "stock_master_sync" {
+		t.Fatalf("expected failed run in dependency order, got %+v", run)
+	}
+	if nodes["stock_master_sync"].Status != model.SchedulerPipelineNodeSuccess || nodes["stock_selection_publish_review"].Status != model.SchedulerPipelineNodeFailed {
+		t.Fatalf("unexpected node statuses: %+v", nodes)
+	}
+	blocked := nodes["daily_stock_recommendation"]
+	if blocked.Status != model.SchedulerPipelineNodeBlocked || blocked.BlockedBy != "stock_selection_publish_review" || blocked.Attempt != 0 {
+		t.Fatalf("expected recommendation to be blocked without running, got %+v", blocked)
+	}
+
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/system/pipelines/review_chain/runs", `{"trade_date":"2020-01-02"}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("resume pipeline: status=%d code=%d", status, code)
+	}
+
+	status, code, data = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/system/pipeline-runs/"+run.ID+"/nodes/stock_master_sync/rerun", "")
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("rerun node: status=%d code=%d", status, code)
+	}
+	rerun, nodes := schedulerPipelineNodeStatuses(t, data)
+	if rerun.ID != run.ID {
+		t.Fatalf("expected rerun to reuse run %s, got %s", run.ID, rerun.ID)
+	}
+	if nodes["stock_master_sync"].Attempt != 2 || nodes["stock_selection_publish_review"].Attempt != 3 {
+		t.Fatalf("expected master to rerun once and review on every pass, got %+v", nodes)
+	}
+	if nodes["daily_stock_recommendation"].Status != model.SchedulerPipelineNodeBlocked {
+		t.Fatalf("expected recommendation to stay blocked, got %+v", nodes["daily_stock_recommendation"])
+	}
+
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/system/pipeline-runs/"+run.ID+"/nodes/unknown_job/rerun", "")
+	if status != http.StatusNotFound || code != 40409 {
+		t.Fatalf("expected unknown node to return 40409, got status=%d code=%d", status, code)
+	}
+}
+
+func TestTriggerSchedulerJobBlockedByPipelineUpstream(t *testing.T) {
+	router := newSchedulerPipelineRouterForTest(t)
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/system/job-runs/trigger", `{"job_name":"stock_quant_rank","trigger_source":"MANUAL"}`)
+	if status != http.StatusConflict || code != 40908 {
+		t.Fatalf("expected quant rank to wait for today's pipeline, got status=%d code=%d", status, code)
+	}
+}
+
+func TestRetrySchedulerJobRunBlockedByPipelineUpstream(t *testing.T) {
+	router := newSchedulerPipelineRouterForTest(t)
+	status, code, _ := serveAdminJSONRequest(t, router, http.MethodPut, "/api/v1/admin/system/pipelines/retry_chain", `{
+		"status":"ACTIVE",
+		"nodes":[
+			{"job_name":"daily_stock_recommendation","depends_on":["stock_master_sync"]},
+			{"job_name":"stock_master_sync"}
+		]
+	}`)
+	if status != http.StatusOK || code != 0 {
+		t.Fatalf("save pipeline: status=%d code=%d", status, code)
+	}
+
+	// The in-memory run jr_001 belongs to daily_stock_recommendation, whose
+	// upstream has not run for today's trade date.
+	status, code, _ = serveAdminJSONRequest(t, router, http.MethodPost, "/api/v1/admin/system/job-runs/jr_001/retry", `{}`)
+	if status != http.StatusConflict || code != 40908 {
+		t.Fatalf("expected retry to wait for the pipeline upstream, got status=%d code=%d", status, code)
+	}
+}
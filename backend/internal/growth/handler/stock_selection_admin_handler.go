@@ -420,6 +420,14 @@ func (h *AdminGrowthHandler) ApproveStockSelectionReview(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
+	if submitForApproval(c, h.service, model.ApprovalRequest{
+		Module:   model.ApprovalModuleRecommendationPublish,
+		Action:   model.ApprovalActionStockSelectionPublish,
+		TargetID: c.Param("run_id"),
+		Summary:  "发布股票推荐运行 " + c.Param("run_id"),
+	}, req) {
+		return
+	}
 	operator := currentAdminOperator(c)
 	item, err := h.service.AdminApproveStockSelectionReview(c.Param("run_id"), operator, req.ReviewNote, req.Force, req.OverrideReason)
 	if err != nil {
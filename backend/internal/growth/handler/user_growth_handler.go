@@ -6,10 +6,8 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -22,15 +20,18 @@ import (
 	"sercherai/backend/internal/growth/model"
 	"sercherai/backend/internal/growth/service"
 	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/secrets"
 )
 
 type UserGrowthHandler struct {
-	service service.GrowthService
-	cfg     config.Config
+	service       service.GrowthService
+	cfg           config.Config
+	configSecrets *secrets.Keyring
 }
 
 func NewUserGrowthHandler(service service.GrowthService, cfg config.Config) *UserGrowthHandler {
-	return &UserGrowthHandler{service: service, cfg: cfg}
+	configSecrets, _ := secrets.Load(cfg)
+	return &UserGrowthHandler{service: service, cfg: cfg, configSecrets: configSecrets}
 }
 
 type yolkPayRuntimeConfig struct {
@@ -336,8 +337,15 @@ func (h *UserGrowthHandler) CreateMembershipOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	order, err := h.service.CreateMembershipOrder(userID, req.ProductID, req.PayChannel)
+	if req.PayChannel == "SANDBOX" && h.cfg.AppEnv == "production" {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: "sandbox payment channel is disabled in production", Data: struct{}{}})
+		return
+	}
+	order, err := h.service.CreateMembershipOrder(userID, req.ProductID, req.PayChannel, req.CouponCode)
 	if err != nil {
+		if writeMembershipCouponError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
@@ -365,12 +373,12 @@ func (h *UserGrowthHandler) CreateMembershipOrder(c *gin.Context) {
 			experimentBindingError = err.Error()
 		}
 	}
-	if strings.EqualFold(strings.TrimSpace(req.PayChannel), "YOLKPAY") {
+	if isGatewayPayChannel(req.PayChannel) {
 		payload := membershipOrderToMap(order)
 		if experimentBindingError != "" {
 			payload["experiment_binding_error"] = experimentBindingError
 		}
-		paymentAction, payErr := h.createYolkPayPaymentAction(c, order)
+		paymentAction, payErr := h.createPaymentAction(c, order, req.SandboxOutcome)
 		if payErr != nil {
 			payload["payment_initialized"] = false
 			payload["payment_error"] = payErr.Error()
@@ -394,100 +402,67 @@ func (h *UserGrowthHandler) CreateMembershipOrder(c *gin.Context) {
 
 func membershipOrderToMap(order model.MembershipOrderAdmin) gin.H {
 	return gin.H{
-		"id":          order.ID,
-		"order_no":    order.OrderNo,
-		"user_id":     order.UserID,
-		"product_id":  order.ProductID,
-		"amount":      order.Amount,
-		"pay_channel": order.PayChannel,
-		"status":      order.Status,
-		"paid_at":     order.PaidAt,
-		"created_at":  order.CreatedAt,
+		"id":              order.ID,
+		"order_no":        order.OrderNo,
+		"user_id":         order.UserID,
+		"product_id":      order.ProductID,
+		"amount":          order.Amount,
+		"original_amount": order.OriginalAmount,
+		"discount_amount": order.DiscountAmount,
+		"coupon_code":     order.CouponCode,
+		"pay_channel":     order.PayChannel,
+		"status":          order.Status,
+		"paid_at":         order.PaidAt,
+		"created_at":      order.CreatedAt,
+	}
+}
+
+// writeMembershipCouponError answers coupon rejections as business errors so
+// the client can drop the code and retry at full price.
+func writeMembershipCouponError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrMembershipCouponNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40405, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrMembershipCouponNotApplicable):
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40904, Message: err.Error(), Data: struct{}{}})
+	default:
+		return false
 	}
+	return true
 }
 
-func (h *UserGrowthHandler) createYolkPayPaymentAction(c *gin.Context, order model.MembershipOrderAdmin) (gin.H, error) {
-	cfg, err := h.resolveYolkPayConfig()
-	if err != nil {
-		return nil, fmt.Errorf("加载蛋黄支付配置失败: %w", err)
-	}
-	if !cfg.PaymentEnabled {
-		return nil, errors.New("payment.enabled 未开启")
-	}
-	if !cfg.Enabled {
-		return nil, errors.New("payment.channel.yolkpay.enabled 未开启")
-	}
-	if strings.TrimSpace(cfg.PID) == "" || strings.TrimSpace(cfg.Key) == "" {
-		return nil, errors.New("payment.channel.yolkpay.pid 或 key 未配置")
-	}
-
-	clientIP := strings.TrimSpace(c.ClientIP())
-	if clientIP == "" || clientIP == "::1" || clientIP == "0:0:0:0:0:0:0:1" {
-		clientIP = "127.0.0.1"
-	}
-	params := map[string]string{
-		"pid":          strings.TrimSpace(cfg.PID),
-		"type":         strings.TrimSpace(cfg.PayType),
-		"out_trade_no": strings.TrimSpace(order.OrderNo),
-		"notify_url":   strings.TrimSpace(cfg.NotifyURL),
-		"return_url":   strings.TrimSpace(cfg.ReturnURL),
-		"name":         fmt.Sprintf("会员订阅-%s", strings.TrimSpace(order.OrderNo)),
-		"money":        fmt.Sprintf("%.2f", order.Amount),
-		"clientip":     clientIP,
-		"device":       strings.TrimSpace(cfg.Device),
-		"param":        strings.TrimSpace(order.UserID),
-	}
-	params["sign"] = buildYolkPaySign(params, cfg.Key)
-	params["sign_type"] = "MD5"
-
-	form := url.Values{}
-	for key, value := range params {
-		form.Set(key, value)
+func (h *UserGrowthHandler) QuoteMembershipCoupon(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
 	}
-	req, err := http.NewRequest(http.MethodPost, buildYolkPayGatewayURL(cfg.Gateway, cfg.MAPIPath), strings.NewReader(form.Encode()))
-	if err != nil {
-		return nil, err
+	var req dto.MembershipCouponQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(req)
+	quote, err := h.service.QuoteMembershipCoupon(userID, req.ProductID, req.CouponCode)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 128*1024))
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		msg := strings.TrimSpace(string(bodyBytes))
-		if msg == "" {
-			msg = resp.Status
+		if writeMembershipCouponError(c, err) {
+			return
 		}
-		return nil, fmt.Errorf("蛋黄支付网关返回异常: %s", msg)
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
 	}
-	var payload map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &payload); err != nil {
-		return nil, fmt.Errorf("蛋黄支付返回解析失败: %w", err)
+	c.JSON(http.StatusOK, dto.OK(quote))
+}
+
+func (h *UserGrowthHandler) ListMembershipCoupons(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
 	}
-	code := parseYolkPayCode(payload["code"])
-	msg := stringifyYolkPayValue(payload["msg"])
-	if code != 1 {
-		if msg == "" {
-			msg = "下单失败"
-		}
-		return nil, fmt.Errorf("蛋黄支付下单失败: %s", msg)
+	items, err := h.service.ListUserMembershipCoupons(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
 	}
-	payURL := stringifyYolkPayValue(payload["payurl"])
-	qrCode := stringifyYolkPayValue(payload["qrcode"])
-	urlScheme := stringifyYolkPayValue(payload["urlscheme"])
-	tradeNo := stringifyYolkPayValue(payload["trade_no"])
-	return gin.H{
-		"channel":   "YOLKPAY",
-		"pay_url":   payURL,
-		"qrcode":    qrCode,
-		"urlscheme": urlScheme,
-		"trade_no":  tradeNo,
-		"raw":       payload,
-	}, nil
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items}))
 }
 
 func (h *UserGrowthHandler) ListMembershipOrders(c *gin.Context) {
@@ -551,7 +526,34 @@ func (h *UserGrowthHandler) GetAttachmentSignedURL(c *gin.Context) {
 		return
 	}
 	if strings.TrimSpace(h.cfg.AttachmentSigningSecret) == "" {
-		c.JSON(http.StatusOK, dto.OK(model.SignedURL{SignedURL: info.FileURL, ExpiredAt: ""}))
+		// Without a backend signing secret, stored objects can only be
+		// handed out when the store itself presigns.
+		store, key, stored, err := h.openUserAttachmentObject(c.Request.Context(), info)
+		if err != nil {
+			writeAttachmentObjectError(c, err)
+			return
+		}
+		if !stored {
+			c.JSON(http.StatusOK, dto.OK(model.SignedURL{SignedURL: info.FileURL, ExpiredAt: ""}))
+			return
+		}
+		// The presigned URL is the download here, so stamp and log it now.
+		key, err = h.resolveWatermarkedAttachment(c.Request.Context(), store, key, userID, attachmentID, info.MimeType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		ttl := attachmentDownloadTTL(h.cfg)
+		signedURL, err := store.PresignGet(c.Request.Context(), key, ttl)
+		if err != nil {
+			c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40302, Message: "attachment signing disabled", Data: struct{}{}})
+			return
+		}
+		if err := h.service.LogAttachmentDownload(userID, attachmentID, info.ArticleID); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+			return
+		}
+		c.JSON(http.StatusOK, dto.OK(model.SignedURL{SignedURL: signedURL, ExpiredAt: time.Now().Add(ttl).Format(time.RFC3339)}))
 		return
 	}
 	ttl := time.Duration(h.cfg.AttachmentSigningTTLSecond) * time.Second
@@ -590,7 +592,21 @@ func (h *UserGrowthHandler) DownloadAttachment(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
-	c.Redirect(http.StatusFound, info.FileURL)
+	store, key, stored, err := h.openUserAttachmentObject(c.Request.Context(), info)
+	if err != nil {
+		writeAttachmentObjectError(c, err)
+		return
+	}
+	if !stored {
+		c.Redirect(http.StatusFound, info.FileURL)
+		return
+	}
+	key, err = h.resolveWatermarkedAttachment(c.Request.Context(), store, key, userID, attachmentID, info.MimeType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	serveAttachmentObject(c, store, key, info.FileName, info.MimeType, attachmentDownloadTTL(h.cfg))
 }
 
 func (h *UserGrowthHandler) ListNewsCategories(c *gin.Context) {
@@ -643,6 +659,24 @@ func (h *UserGrowthHandler) SearchGlobal(c *gin.Context) {
 		return
 	}
 
+	// Attachment text can quote VIP research, so hits follow the download
+	// rules; accounts that are not active search as anonymous visitors.
+	attachmentUserID := userID
+	if userID != "" {
+		profile, ok := h.loadAccessProfile(c, userID)
+		if !ok {
+			return
+		}
+		if !strings.EqualFold(profile.Status, "ACTIVE") {
+			attachmentUserID = ""
+		}
+	}
+	attachmentHits, attachmentTotal, err := h.service.SearchNewsAttachmentTexts(attachmentUserID, keyword, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+
 	stocks := make([]model.StockRecommendation, 0)
 	strategies := make([]model.FuturesStrategy, 0)
 	stockTotal := 0
@@ -679,6 +713,10 @@ func (h *UserGrowthHandler) SearchGlobal(c *gin.Context) {
 			"items": newsItems,
 			"total": newsTotal,
 		},
+		"attachments": gin.H{
+			"items": attachmentHits,
+			"total": attachmentTotal,
+		},
 	}))
 }
 
@@ -757,56 +795,6 @@ func (h *UserGrowthHandler) CreateWithdrawRequest(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.OK(gin.H{"withdraw_id": withdrawID}))
 }
 
-func (h *UserGrowthHandler) HandleYolkPayCallback(c *gin.Context) {
-	if err := c.Request.ParseForm(); err != nil {
-		c.String(http.StatusOK, "fail")
-		return
-	}
-	params := make(map[string]string, len(c.Request.Form))
-	for key, values := range c.Request.Form {
-		if len(values) == 0 {
-			continue
-		}
-		params[key] = strings.TrimSpace(values[0])
-	}
-	sign := strings.TrimSpace(params["sign"])
-	orderNo := strings.TrimSpace(params["out_trade_no"])
-	tradeNo := strings.TrimSpace(params["trade_no"])
-	tradeStatus := strings.ToUpper(strings.TrimSpace(params["trade_status"]))
-
-	cfg, err := h.resolveYolkPayConfig()
-	if err != nil || !cfg.Enabled || strings.TrimSpace(cfg.Key) == "" {
-		c.String(http.StatusOK, "fail")
-		return
-	}
-	if sign == "" || !verifyYolkPaySign(params, sign, cfg.Key) {
-		c.String(http.StatusOK, "fail")
-		return
-	}
-	if orderNo == "" {
-		c.String(http.StatusOK, "fail")
-		return
-	}
-	if tradeStatus != "TRADE_SUCCESS" {
-		c.String(http.StatusOK, "success")
-		return
-	}
-	idempotencyKey := "yolkpay:" + orderNo
-	if tradeNo != "" {
-		idempotencyKey = "yolkpay:" + tradeNo
-	}
-	err = h.service.HandlePaymentCallback("YOLKPAY", orderNo, tradeNo, idempotencyKey, sign, true)
-	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-			c.String(http.StatusOK, "success")
-			return
-		}
-		c.String(http.StatusOK, "fail")
-		return
-	}
-	c.String(http.StatusOK, "success")
-}
-
 func (h *UserGrowthHandler) HandlePaymentCallback(c *gin.Context) {
 	channel := c.Param("channel")
 	var req dto.PaymentCallbackRequest
@@ -1474,7 +1462,11 @@ func (h *UserGrowthHandler) resolveYolkPayConfig() (yolkPayRuntimeConfig, error)
 	cfg.PaymentEnabled = parseConfigBool(configMap[paymentEnabledConfigKey], cfg.PaymentEnabled)
 	cfg.Enabled = parseConfigBool(configMap[paymentChannelYolkPayEnabledConfigKey], cfg.Enabled)
 	cfg.PID = strings.TrimSpace(configMap[paymentChannelYolkPayPIDConfigKey])
-	cfg.Key = strings.TrimSpace(configMap[paymentChannelYolkPayKeyConfigKey])
+	key, err := h.configSecrets.Open(paymentChannelYolkPayKeyConfigKey, configMap[paymentChannelYolkPayKeyConfigKey])
+	if err != nil {
+		return cfg, fmt.Errorf("open %s: %w", paymentChannelYolkPayKeyConfigKey, err)
+	}
+	cfg.Key = strings.TrimSpace(key)
 	if gateway := strings.TrimSpace(configMap[paymentChannelYolkPayGatewayConfigKey]); gateway != "" {
 		cfg.Gateway = gateway
 	}
@@ -1502,7 +1494,11 @@ func (h *UserGrowthHandler) resolveYolkPayConfig() (yolkPayRuntimeConfig, error)
 func (h *UserGrowthHandler) verifyPaymentSignature(channel string, orderNo string, channelTxnNo string, idempotencyKey string, sign string) (bool, error) {
 	secret := strings.TrimSpace(h.cfg.PaymentSigningSecret)
 	if dbSecret, err := h.lookupSystemConfigValue(paymentSigningSecretConfigKey); err == nil && strings.TrimSpace(dbSecret) != "" {
-		secret = strings.TrimSpace(dbSecret)
+		opened, openErr := h.configSecrets.Open(paymentSigningSecretConfigKey, dbSecret)
+		if openErr != nil {
+			return false, fmt.Errorf("open %s: %w", paymentSigningSecretConfigKey, openErr)
+		}
+		secret = strings.TrimSpace(opened)
 	}
 	if secret == "" {
 		if h.cfg.AppEnv == "production" {
@@ -125,6 +125,9 @@ func (h *UserGrowthHandler) CreateCommunityTopic(c *gin.Context) {
 		TargetSnapshot: req.TargetSnapshot,
 	})
 	if err != nil {
+		if writeCommunityModerationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
@@ -153,6 +156,9 @@ func (h *UserGrowthHandler) CreateCommunityComment(c *gin.Context) {
 			c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40401, Message: "topic not found", Data: struct{}{}})
 			return
 		}
+		if writeCommunityModerationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
 		return
 	}
@@ -225,3 +231,17 @@ func (h *UserGrowthHandler) CreateCommunityReport(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, dto.OK(item))
 }
+
+// writeCommunityModerationError answers posts the moderation gate rejected
+// outright; content it merely holds for review is created as PENDING_REVIEW.
+func writeCommunityModerationError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrCommunityPostingBanned):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40308, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrCommunityPostingRateLimited):
+		c.JSON(http.StatusTooManyRequests, dto.APIResponse{Code: 42902, Message: err.Error(), Data: struct{}{}})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+)
+
+func (h *UserGrowthHandler) ListCommunityFeed(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.ListCommunityFeed(userID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *UserGrowthHandler) ListCommunityFollows(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.ListCommunityFollows(userID, strings.TrimSpace(c.Query("target_type")), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *UserGrowthHandler) CreateCommunityFollow(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req dto.CommunityFollowCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	item, err := h.service.CreateCommunityFollow(userID, req.TargetType, req.TargetID)
+	if err != nil {
+		writeCommunitySocialError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+func (h *UserGrowthHandler) DeleteCommunityFollow(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteCommunityFollow(userID, c.Param("id")); err != nil {
+		writeCommunitySocialError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+func (h *UserGrowthHandler) ListCommunityBlocks(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.ListCommunityBlocks(userID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func (h *UserGrowthHandler) CreateCommunityBlock(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req dto.CommunityBlockCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	item, err := h.service.CreateCommunityBlock(userID, req.BlockedUserID, req.BlockType)
+	if err != nil {
+		writeCommunitySocialError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+func (h *UserGrowthHandler) DeleteCommunityBlock(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteCommunityBlock(userID, c.Param("id")); err != nil {
+		writeCommunitySocialError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+func (h *UserGrowthHandler) ListCommunityMentions(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c)
+	items, total, err := h.service.ListCommunityMentions(userID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(gin.H{"items": items, "page": page, "page_size": pageSize, "total": total}))
+}
+
+func writeCommunitySocialError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrCommunityFollowSelf), errors.Is(err, model.ErrCommunityFollowInvalid):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40002, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrCommunityFollowBlocked):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Code: 40309, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40408, Message: "follow target not found", Data: struct{}{}})
+	case errors.Is(err, model.ErrCommunityFollowNotFound), errors.Is(err, model.ErrCommunityBlockNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40408, Message: err.Error(), Data: struct{}{}})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+	}
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/growth/dto"
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+func writeMembershipAutoRenewError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrMembershipAutoRenewNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Code: 40406, Message: err.Error(), Data: struct{}{}})
+	case errors.Is(err, model.ErrMembershipAutoRenewNotEligible), errors.Is(err, model.ErrMembershipAutoRenewUnsupported):
+		c.JSON(http.StatusConflict, dto.APIResponse{Code: 40906, Message: err.Error(), Data: struct{}{}})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *UserGrowthHandler) GetMembershipAutoRenew(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	item, err := h.service.GetMembershipAutoRenewal(userID)
+	if err != nil {
+		if writeMembershipAutoRenewError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// EnableMembershipAutoRenew signs a withholding agreement with the chosen
+// channel and opts the user into renewing. Re-enabling with a new channel
+// replaces the previous agreement, which is then released.
+func (h *UserGrowthHandler) EnableMembershipAutoRenew(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req dto.MembershipAutoRenewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Code: 40001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	channel, charger, err := h.autoRenewCharger(req.PayChannel)
+	if err != nil {
+		writeMembershipAutoRenewError(c, err)
+		return
+	}
+	previous, _ := h.service.GetMembershipAutoRenewal(userID)
+	agreementNo, err := charger.SignAgreement(payment.AgreementRequest{UserID: userID, ProductID: req.ProductID, Subject: "会员自动续费"})
+	if err != nil {
+		c.JSON(http.StatusBadGateway, dto.APIResponse{Code: 50201, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	item, err := h.service.EnableMembershipAutoRenewal(userID, req.ProductID, req.PayChannel, agreementNo)
+	if err != nil {
+		h.releaseAutoRenewAgreement(channel.Name(), agreementNo)
+		if writeMembershipAutoRenewError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	if previous.AgreementNo != "" && previous.AgreementNo != agreementNo && (previous.Status == "ACTIVE" || previous.Status == "PAST_DUE") {
+		h.releaseAutoRenewAgreement(previous.PayChannel, previous.AgreementNo)
+	}
+	c.JSON(http.StatusOK, dto.OK(item))
+}
+
+// autoRenewCharger resolves the channel an agreement would be signed and
+// charged through. Enrollment is refused with
+// ErrMembershipAutoRenewUnsupported unless the channel is available and can
+// charge an agreement, which rules out YOLKPAY and, in production, SANDBOX.
+func (h *UserGrowthHandler) autoRenewCharger(payChannel string) (payment.Channel, payment.AgreementCharger, error) {
+	channel, err := h.paymentChannel(payChannel, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", model.ErrMembershipAutoRenewUnsupported, err)
+	}
+	charger, ok := channel.(payment.AgreementCharger)
+	if !ok {
+		return nil, nil, model.ErrMembershipAutoRenewUnsupported
+	}
+	return channel, charger, nil
+}
+
+func (h *UserGrowthHandler) CancelMembershipAutoRenew(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	item, err := h.service.CancelMembershipAutoRenewal(userID)
+	if err != nil {
+		if writeMembershipAutoRenewError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Code: 50001, Message: err.Error(), Data: struct{}{}})
+		return
+	}
+	h.releaseAutoRenewAgreement(item.PayChannel, item.AgreementNo)
+	c.JSON(http.StatusOK, dto.OK(struct{}{}))
+}
+
+// releaseAutoRenewAgreement is best effort: the renewal is already stopped on
+// our side, so a channel that fails to unsign only leaves an unused agreement.
+func (h *UserGrowthHandler) releaseAutoRenewAgreement(payChannel string, agreementNo string) {
+	if strings.TrimSpace(agreementNo) == "" {
+		return
+	}
+	channel, err := h.paymentChannel(payChannel, "")
+	if err == nil {
+		if charger, ok := channel.(payment.AgreementCharger); ok {
+			err = charger.CancelAgreement(agreementNo)
+		}
+	}
+	if err != nil {
+		log.Printf("release %s agreement %s failed: %v", payChannel, agreementNo, err)
+	}
+}
@@ -1,12 +1,11 @@
 package handler
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
+
+	"sercherai/backend/internal/platform/payment"
 )
 
 const paymentEnabledConfigKey = "payment.enabled"
@@ -21,39 +20,22 @@ const paymentChannelYolkPayNotifyURLConfigKey = "payment.channel.yolkpay.notify_
 const paymentChannelYolkPayReturnURLConfigKey = "payment.channel.yolkpay.return_url"
 const paymentChannelYolkPayPayTypeConfigKey = "payment.channel.yolkpay.pay_type"
 const paymentChannelYolkPayDeviceConfigKey = "payment.channel.yolkpay.device"
+const paymentChannelSandboxOutcomeConfigKey = "payment.channel.sandbox.outcome"
+const paymentChannelSandboxDelayConfigKey = "payment.channel.sandbox.delay_seconds"
 
-func buildYolkPaySign(params map[string]string, merchantKey string) string {
-	filtered := make([]string, 0, len(params))
-	values := make(map[string]string, len(params))
-	for rawKey, rawValue := range params {
-		key := strings.TrimSpace(rawKey)
-		if key == "" {
-			continue
-		}
-		lowerKey := strings.ToLower(key)
-		if lowerKey == "sign" || lowerKey == "sign_type" {
-			continue
-		}
-		value := strings.TrimSpace(rawValue)
-		if value == "" {
-			continue
-		}
-		filtered = append(filtered, key)
-		values[key] = value
+// channelConfig hands the gateway part of the runtime config to the payment
+// package; the enable switches stay with the handlers.
+func (cfg yolkPayRuntimeConfig) channelConfig() payment.YolkPayConfig {
+	return payment.YolkPayConfig{
+		PID:       cfg.PID,
+		Key:       cfg.Key,
+		Gateway:   cfg.Gateway,
+		MAPIPath:  cfg.MAPIPath,
+		NotifyURL: cfg.NotifyURL,
+		ReturnURL: cfg.ReturnURL,
+		PayType:   cfg.PayType,
+		Device:    cfg.Device,
 	}
-	sort.Strings(filtered)
-	parts := make([]string, 0, len(filtered))
-	for _, key := range filtered {
-		parts = append(parts, key+"="+values[key])
-	}
-	signSource := strings.Join(parts, "&") + strings.TrimSpace(merchantKey)
-	hash := md5.Sum([]byte(signSource))
-	return strings.ToLower(hex.EncodeToString(hash[:]))
-}
-
-func verifyYolkPaySign(params map[string]string, sign string, merchantKey string) bool {
-	expected := buildYolkPaySign(params, merchantKey)
-	return strings.EqualFold(strings.TrimSpace(sign), strings.TrimSpace(expected))
 }
 
 func buildYolkPayGatewayURL(gateway string, mapiPath string) string {
@@ -0,0 +1,76 @@
+package model
+
+type AdminAuditLedgerEntry struct {
+	Seq         int64  `json:"seq"`
+	SourceType  string `json:"source_type"`
+	SourceID    string `json:"source_id"`
+	Action      string `json:"action"`
+	PayloadJSON string `json:"payload_json"`
+	PayloadHash string `json:"payload_hash"`
+	PrevHash    string `json:"prev_hash"`
+	EntryHash   string `json:"entry_hash"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type AdminAuditLedgerIssue struct {
+	Code       string `json:"code"`
+	Seq        int64  `json:"seq,omitempty"`
+	SourceType string `json:"source_type,omitempty"`
+	SourceID   string `json:"source_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+type AdminAuditLedgerVerification struct {
+	Valid              bool                    `json:"valid"`
+	CheckedEntries     int                     `json:"checked_entries"`
+	CheckedSources     int                     `json:"checked_sources"`
+	LegacySources      int                     `json:"legacy_sources"`
+	CheckedCheckpoints int                     `json:"checked_checkpoints"`
+	FirstSeq           int64                   `json:"first_seq"`
+	LastSeq            int64                   `json:"last_seq"`
+	HeadHash           string                  `json:"head_hash"`
+	Issues             []AdminAuditLedgerIssue `json:"issues"`
+	VerifiedAt         string                  `json:"verified_at"`
+}
+
+type AdminAuditCheckpoint struct {
+	ID         string `json:"id"`
+	Seq        int64  `json:"seq"`
+	EntryHash  string `json:"entry_hash"`
+	EntryCount int64  `json:"entry_count"`
+	Algorithm  string `json:"algorithm"`
+	KeyID      string `json:"key_id"`
+	PublicKey  string `json:"public_key"`
+	Signature  string `json:"signature"`
+	CreatedBy  string `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type AdminAuditLedgerManifest struct {
+	Format         string                 `json:"format"`
+	HashAlgorithm  string                 `json:"hash_algorithm"`
+	PayloadRule    string                 `json:"payload_rule"`
+	EntryHashRule  string                 `json:"entry_hash_rule"`
+	StartDate      string                 `json:"start_date"`
+	EndDate        string                 `json:"end_date"`
+	EntryCount     int                    `json:"entry_count"`
+	FirstSeq       int64                  `json:"first_seq"`
+	LastSeq        int64                  `json:"last_seq"`
+	AnchorPrevHash string                 `json:"anchor_prev_hash"`
+	LastEntryHash  string                 `json:"last_entry_hash"`
+	EntriesSHA256  string                 `json:"entries_sha256"`
+	Checkpoints    []AdminAuditCheckpoint `json:"checkpoints"`
+	SignatureRule  string                 `json:"signature_rule"`
+	Algorithm      string                 `json:"algorithm"`
+	KeyID          string                 `json:"key_id"`
+	PublicKey      string                 `json:"public_key"`
+	Signature      string                 `json:"signature"`
+	GeneratedAt    string                 `json:"generated_at"`
+	GeneratedBy    string                 `json:"generated_by"`
+}
+
+type AdminAuditLedgerExport struct {
+	Entries      []AdminAuditLedgerEntry  `json:"entries"`
+	EntriesJSONL []byte                   `json:"-"`
+	Manifest     AdminAuditLedgerManifest `json:"manifest"`
+}
@@ -0,0 +1,318 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrApprovalPolicyNotFound    = errors.New("approval policy not found")
+	ErrApprovalPolicyInvalid     = errors.New("invalid approval policy")
+	ErrApprovalRequestNotFound   = errors.New("approval request not found")
+	ErrApprovalRequestDuplicate  = errors.New("an approval request for this target is already open")
+	ErrApprovalRequestNotPending = errors.New("approval request is not pending")
+	ErrApprovalNotExecutable     = errors.New("approval request is not waiting to be executed")
+	ErrApprovalSelfApproval      = errors.New("submitter cannot approve their own request")
+	ErrApprovalAlreadyDecided    = errors.New("approver has already signed this request")
+	ErrApprovalNotEligible       = errors.New("approver is not eligible for the current step")
+)
+
+// Modules an approval policy can guard. Each policy covers one module.
+const (
+	ApprovalModuleRecommendationPublish = "RECOMMENDATION_PUBLISH"
+	ApprovalModuleWithdraw              = "WITHDRAW"
+	ApprovalModulePaymentConfig         = "PAYMENT_CONFIG"
+	ApprovalModuleRBAC                  = "RBAC"
+)
+
+// Guarded actions. The action decides which executor runs the stored
+// payload once the chain completes.
+const (
+	ApprovalActionStockSelectionPublish   = "STOCK_SELECTION_PUBLISH"
+	ApprovalActionFuturesSelectionPublish = "FUTURES_SELECTION_PUBLISH"
+	ApprovalActionWithdrawApprove         = "WITHDRAW_APPROVE"
+	ApprovalActionPaymentConfigUpsert     = "PAYMENT_CONFIG_UPSERT"
+	ApprovalActionRoleCreate              = "RBAC_ROLE_CREATE"
+	ApprovalActionRoleUpdate              = "RBAC_ROLE_UPDATE"
+	ApprovalActionRoleStatus              = "RBAC_ROLE_STATUS"
+	ApprovalActionAdminUserRoles          = "RBAC_ADMIN_USER_ROLES"
+	ApprovalActionAdminUserCreate         = "RBAC_ADMIN_USER_CREATE"
+	ApprovalActionPolicySave              = "APPROVAL_POLICY_SAVE"
+)
+
+const (
+	ApprovalPolicyStatusActive   = "ACTIVE"
+	ApprovalPolicyStatusDisabled = "DISABLED"
+
+	ApprovalRequestPending       = "PENDING"
+	ApprovalRequestApproved      = "APPROVED"
+	ApprovalRequestExecuting     = "EXECUTING"
+	ApprovalRequestExecuted      = "EXECUTED"
+	ApprovalRequestExecuteFailed = "EXECUTE_FAILED"
+	ApprovalRequestRejected      = "REJECTED"
+
+	ApprovalDecisionApprove = "APPROVE"
+	ApprovalDecisionReject  = "REJECT"
+)
+
+// ApprovalStep needs RequiredApprovals distinct approvers (N) out of the
+// admins allowed to sign it (M): holders of any ApproverRoles, narrowed to
+// ApproverIDs when that list is set.
+type ApprovalStep struct {
+	Name              string   `json:"name,omitempty"`
+	RequiredApprovals int      `json:"required_approvals"`
+	ApproverRoles     []string `json:"approver_roles,omitempty"`
+	ApproverIDs       []string `json:"approver_ids,omitempty"`
+}
+
+// ApprovalPolicy is the chain a module's sensitive actions go through. For
+// WITHDRAW only amounts at or above MinAmount need approval.
+type ApprovalPolicy struct {
+	ID          string         `json:"id"`
+	Module      string         `json:"module"`
+	DisplayName string         `json:"display_name"`
+	Status      string         `json:"status"`
+	MinAmount   float64        `json:"min_amount"`
+	Steps       []ApprovalStep `json:"steps"`
+	UpdatedBy   string         `json:"updated_by,omitempty"`
+	CreatedAt   string         `json:"created_at,omitempty"`
+	UpdatedAt   string         `json:"updated_at,omitempty"`
+}
+
+// ApprovalRequest is one guarded action waiting on, or done with, its chain.
+// Steps is the policy as it was at submission, so editing a policy does not
+// change chains already in flight. Payload holds the action's arguments and
+// is never sent to clients; Summary describes it for approvers.
+type ApprovalRequest struct {
+	ID           string             `json:"id"`
+	PolicyID     string             `json:"policy_id"`
+	Module       string             `json:"module"`
+	Action       string             `json:"action"`
+	TargetID     string             `json:"target_id"`
+	Amount       float64            `json:"amount,omitempty"`
+	Summary      string             `json:"summary"`
+	Payload      string             `json:"-"`
+	SubmitterID  string             `json:"submitter_id"`
+	Status       string             `json:"status"`
+	CurrentStep  int                `json:"current_step"`
+	Steps        []ApprovalStep     `json:"steps"`
+	Decisions    []ApprovalDecision `json:"decisions,omitempty"`
+	ExecuteError string             `json:"execute_error,omitempty"`
+	CreatedAt    string             `json:"created_at"`
+	UpdatedAt    string             `json:"updated_at,omitempty"`
+	CompletedAt  string             `json:"completed_at,omitempty"`
+	ExecutedAt   string             `json:"executed_at,omitempty"`
+}
+
+// ApprovalDecision is one approver's signature on one step (1-based).
+type ApprovalDecision struct {
+	ID         string `json:"id"`
+	RequestID  string `json:"request_id"`
+	Step       int    `json:"step"`
+	ApproverID string `json:"approver_id"`
+	Decision   string `json:"decision"`
+	Note       string `json:"note,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// IsApprovalModule reports whether module is one a policy can guard.
+func IsApprovalModule(module string) bool {
+	switch module {
+	case ApprovalModuleRecommendationPublish, ApprovalModuleWithdraw, ApprovalModulePaymentConfig, ApprovalModuleRBAC:
+		return true
+	}
+	return false
+}
+
+// Normalized upper-cases module, status and role keys and drops duplicate
+// roles and approvers. A step that names fewer approvers than it needs can
+// never complete and is rejected with ErrApprovalPolicyInvalid.
+func (p ApprovalPolicy) Normalized() (ApprovalPolicy, error) {
+	p.Module = strings.ToUpper(strings.TrimSpace(p.Module))
+	p.DisplayName = strings.TrimSpace(p.DisplayName)
+	p.Status = strings.ToUpper(strings.TrimSpace(p.Status))
+	if p.Status == "" {
+		p.Status = ApprovalPolicyStatusActive
+	}
+	if !IsApprovalModule(p.Module) {
+		return p, fmt.Errorf("%w: unsupported module %s", ErrApprovalPolicyInvalid, p.Module)
+	}
+	if p.Status != ApprovalPolicyStatusActive && p.Status != ApprovalPolicyStatusDisabled {
+		return p, fmt.Errorf("%w: unsupported status %s", ErrApprovalPolicyInvalid, p.Status)
+	}
+	if p.MinAmount < 0 {
+		return p, fmt.Errorf("%w: min_amount cannot be negative", ErrApprovalPolicyInvalid)
+	}
+	if len(p.Steps) == 0 {
+		return p, fmt.Errorf("%w: at least one step is required", ErrApprovalPolicyInvalid)
+	}
+	steps := make([]ApprovalStep, 0, len(p.Steps))
+	for i, step := range p.Steps {
+		step.Name = strings.TrimSpace(step.Name)
+		step.ApproverRoles = normalizeApprovalList(step.ApproverRoles, true)
+		step.ApproverIDs = normalizeApprovalList(step.ApproverIDs, false)
+		if step.RequiredApprovals < 1 {
+			return p, fmt.Errorf("%w: step %d needs at least one approval", ErrApprovalPolicyInvalid, i+1)
+		}
+		if len(step.ApproverIDs) > 0 && step.RequiredApprovals > len(step.ApproverIDs) {
+			return p, fmt.Errorf("%w: step %d needs %d approvals but names %d approvers", ErrApprovalPolicyInvalid, i+1, step.RequiredApprovals, len(step.ApproverIDs))
+		}
+		steps = append(steps, step)
+	}
+	p.Steps = steps
+	return p, nil
+}
+
+// Applies reports whether an action of the given amount must go through
+// this policy.
+func (p ApprovalPolicy) Applies(amount float64) bool {
+	return p.Status == ApprovalPolicyStatusActive && len(p.Steps) > 0 && amount >= p.MinAmount
+}
+
+// Relaxes reports whether replacing p with next could let an action through
+// with fewer or different sign-offs: disabling an active chain, raising its
+// threshold, dropping steps, lowering a step's approvals or changing who may
+// sign a step. Adding steps or approvals only tightens the chain. Both
+// policies are expected to be normalized.
+func (p ApprovalPolicy) Relaxes(next ApprovalPolicy) bool {
+	if p.Status != ApprovalPolicyStatusActive {
+		return false
+	}
+	if next.Status != ApprovalPolicyStatusActive || next.MinAmount > p.MinAmount || len(next.Steps) < len(p.Steps) {
+		return true
+	}
+	for i, step := range p.Steps {
+		changed := next.Steps[i]
+		if changed.RequiredApprovals < step.RequiredApprovals ||
+			!sameApprovalList(changed.ApproverRoles, step.ApproverRoles) ||
+			!sameApprovalList(changed.ApproverIDs, step.ApproverIDs) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decide records approverID's decision on the current step and returns the
+// request as it stands afterwards. roleKeys are the approver's active RBAC
+// role keys. The submitter can never sign, and nobody signs a chain twice,
+// so N-of-M counts distinct people across the whole chain. One rejection
+// ends the chain; the last approval of the last step marks it APPROVED.
+func (r ApprovalRequest) Decide(approverID string, roleKeys []string, decision string, note string, now string) (ApprovalRequest, ApprovalDecision, error) {
+	approverID = strings.TrimSpace(approverID)
+	decision = strings.ToUpper(strings.TrimSpace(decision))
+	if decision != ApprovalDecisionApprove && decision != ApprovalDecisionReject {
+		return r, ApprovalDecision{}, fmt.Errorf("unsupported decision %s", decision)
+	}
+	if r.Status != ApprovalRequestPending {
+		return r, ApprovalDecision{}, ErrApprovalRequestNotPending
+	}
+	if r.CurrentStep < 1 || r.CurrentStep > len(r.Steps) {
+		return r, ApprovalDecision{}, fmt.Errorf("approval request %s has no step %d", r.ID, r.CurrentStep)
+	}
+	if approverID == "" || strings.EqualFold(approverID, r.SubmitterID) {
+		return r, ApprovalDecision{}, ErrApprovalSelfApproval
+	}
+	for _, item := range r.Decisions {
+		if strings.EqualFold(item.ApproverID, approverID) {
+			return r, ApprovalDecision{}, ErrApprovalAlreadyDecided
+		}
+	}
+	step := r.Steps[r.CurrentStep-1]
+	if !step.allows(approverID, roleKeys) {
+		return r, ApprovalDecision{}, ErrApprovalNotEligible
+	}
+
+	item := ApprovalDecision{
+		RequestID:  r.ID,
+		Step:       r.CurrentStep,
+		ApproverID: approverID,
+		Decision:   decision,
+		Note:       strings.TrimSpace(note),
+		CreatedAt:  now,
+	}
+	r.Decisions = append(append([]ApprovalDecision(nil), r.Decisions...), item)
+	r.UpdatedAt = now
+	if decision == ApprovalDecisionReject {
+		r.Status = ApprovalRequestRejected
+		r.CompletedAt = now
+		return r, item, nil
+	}
+	approvals := 0
+	for _, existing := range r.Decisions {
+		if existing.Step == r.CurrentStep && existing.Decision == ApprovalDecisionApprove {
+			approvals++
+		}
+	}
+	if approvals >= step.RequiredApprovals {
+		if r.CurrentStep == len(r.Steps) {
+			r.Status = ApprovalRequestApproved
+			r.CompletedAt = now
+		} else {
+			r.CurrentStep++
+		}
+	}
+	return r, item, nil
+}
+
+func (s ApprovalStep) allows(approverID string, roleKeys []string) bool {
+	if len(s.ApproverIDs) > 0 {
+		named := false
+		for _, id := range s.ApproverIDs {
+			if strings.EqualFold(id, approverID) {
+				named = true
+				break
+			}
+		}
+		if !named {
+			return false
+		}
+	}
+	if len(s.ApproverRoles) == 0 {
+		return true
+	}
+	for _, required := range s.ApproverRoles {
+		for _, held := range roleKeys {
+			if strings.EqualFold(required, strings.TrimSpace(held)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeApprovalList(items []string, upper bool) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		value := strings.TrimSpace(item)
+		if upper {
+			value = strings.ToUpper(value)
+		}
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[strings.ToLower(value)]; ok {
+			continue
+		}
+		seen[strings.ToLower(value)] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+func sameApprovalList(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]struct{}, len(a))
+	for _, item := range a {
+		seen[strings.ToUpper(item)] = struct{}{}
+	}
+	for _, item := range b {
+		if _, ok := seen[strings.ToUpper(item)]; !ok {
+			return false
+		}
+	}
+	return true
+}
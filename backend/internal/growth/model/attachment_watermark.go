@@ -0,0 +1,21 @@
+package model
+
+// AttachmentDownloadRecord is one row of attachment_download_logs.
+type AttachmentDownloadRecord struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	AttachmentID string `json:"attachment_id"`
+	ArticleID    string `json:"article_id"`
+	DownloadedAt string `json:"downloaded_at"`
+}
+
+// AttachmentWatermarkTrace is what the marker in a leaked file resolves to:
+// the account it was stamped for and that account's downloads of the file.
+type AttachmentWatermarkTrace struct {
+	AttachmentID string                     `json:"attachment_id"`
+	UserID       string                     `json:"user_id"`
+	UserPhone    string                     `json:"user_phone,omitempty"`
+	StampedAt    string                     `json:"stamped_at"`
+	Format       string                     `json:"format"`
+	Downloads    []AttachmentDownloadRecord `json:"downloads"`
+}
@@ -112,6 +112,8 @@ type CommunityTopicDetail struct {
 	LikedByMe     bool               `json:"liked_by_me"`
 	FavoritedByMe bool               `json:"favorited_by_me"`
 	LinkedTarget  CommunityTopicLink `json:"linked_target"`
+	// ModerationReasons is only set on create when the topic was held for review.
+	ModerationReasons []CommunityModerationReason `json:"moderation_reasons,omitempty"`
 }
 
 type CommunityTopicCreateInput struct {
@@ -145,6 +147,8 @@ type CommunityComment struct {
 	UpdatedAt       string `json:"updated_at"`
 	LikedByMe       bool   `json:"liked_by_me"`
 	LinkedTarget    CommunityTopicLink `json:"linked_target"`
+	// ModerationReasons is only set on create when the comment was held for review.
+	ModerationReasons []CommunityModerationReason `json:"moderation_reasons,omitempty"`
 }
 
 type CommunityCommentListQuery struct {
@@ -220,4 +224,7 @@ type CommunityNotificationInput struct {
 	Title       string `json:"title"`
 	Content     string `json:"content"`
 	MessageType string `json:"message_type"`
+	// ActorUserID is the user whose action triggered the notification; it is
+	// dropped when the recipient has muted or blocked the actor.
+	ActorUserID string `json:"actor_user_id,omitempty"`
 }
@@ -0,0 +1,75 @@
+package model
+
+import "errors"
+
+var (
+	ErrCommunityPostingBanned      = errors.New("community posting banned")
+	ErrCommunityPostingRateLimited = errors.New("community posting rate limited")
+	ErrCommunityUserBanNotFound    = errors.New("community user ban not found")
+)
+
+// Moderation reason codes attached to content routed to PENDING_REVIEW.
+const (
+	CommunityModerationReasonSensitiveWord = "SENSITIVE_WORD"
+	CommunityModerationReasonContactInfo   = "CONTACT_INFO"
+	CommunityModerationReasonQRCode        = "QR_CODE"
+	CommunityModerationReasonExternalLink  = "EXTERNAL_LINK"
+	CommunityModerationReasonLowTrust      = "LOW_TRUST"
+)
+
+type CommunityModerationReason struct {
+	Code   string `json:"code"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// CommunitySensitiveWord is one entry of the admin-managed dictionary the
+// pre-publish matcher runs over topics and comments. Only ACTIVE words match.
+type CommunitySensitiveWord struct {
+	ID        string `json:"id"`
+	Word      string `json:"word"`
+	Category  string `json:"category"`
+	Status    string `json:"status"`
+	CreatedBy string `json:"created_by,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// CommunityModerationFlag records why a topic or comment was held for
+// review. It stays PENDING until an admin publishes (APPROVED) or hides or
+// deletes (REJECTED) the content; every flag that was not approved counts
+// as a violation against the author.
+type CommunityModerationFlag struct {
+	ID         string                      `json:"id"`
+	UserID     string                      `json:"user_id"`
+	TargetType string                      `json:"target_type"`
+	TargetID   string                      `json:"target_id"`
+	Reasons    []CommunityModerationReason `json:"reasons"`
+	TrustScore int                         `json:"trust_score"`
+	Status     string                      `json:"status"`
+	CreatedAt  string                      `json:"created_at"`
+	ReviewedAt string                      `json:"reviewed_at,omitempty"`
+}
+
+// CommunityUserBan blocks a user from creating topics and comments until
+// ExpiresAt. Bans are issued automatically to repeat offenders or manually
+// by admins, and can be LIFTED early.
+type CommunityUserBan struct {
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	Reason         string `json:"reason"`
+	ViolationCount int    `json:"violation_count"`
+	Status         string `json:"status"`
+	ExpiresAt      string `json:"expires_at"`
+	CreatedBy      string `json:"created_by"`
+	LiftedBy       string `json:"lifted_by,omitempty"`
+	LiftedAt       string `json:"lifted_at,omitempty"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type CommunityModerationFlagQuery struct {
+	Status     string `json:"status"`
+	TargetType string `json:"target_type"`
+	UserID     string `json:"user_id"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+}
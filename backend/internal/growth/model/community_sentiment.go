@@ -0,0 +1,34 @@
+package model
+
+const (
+	CommunitySentimentExtremeEuphoria = "EUPHORIA"
+	CommunitySentimentExtremePanic    = "PANIC"
+)
+
+// CommunitySentimentDaily is the crowd stance on one stock symbol or futures
+// product over the window_days ending on stat_date. SentimentScore runs from
+// -1 (all bearish) to 1 (all bullish); Extreme marks a crowded reading that
+// strategies may want to fade.
+type CommunitySentimentDaily struct {
+	AssetClass     string  `json:"asset_class"`
+	Symbol         string  `json:"symbol"`
+	StatDate       string  `json:"stat_date"`
+	WindowDays     int     `json:"window_days"`
+	TopicCount     int     `json:"topic_count"`
+	AuthorCount    int     `json:"author_count"`
+	BullishWeight  float64 `json:"bullish_weight"`
+	BearishWeight  float64 `json:"bearish_weight"`
+	WatchWeight    float64 `json:"watch_weight"`
+	SentimentScore float64 `json:"sentiment_score"`
+	Extreme        string  `json:"extreme,omitempty"`
+	UpdatedAt      string  `json:"updated_at"`
+}
+
+type CommunitySentimentQuery struct {
+	AssetClass string `json:"asset_class"`
+	Symbol     string `json:"symbol"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+}
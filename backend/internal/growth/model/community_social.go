@@ -0,0 +1,73 @@
+package model
+
+import "errors"
+
+var (
+	ErrCommunityFollowBlocked  = errors.New("community follow blocked by target user")
+	ErrCommunityFollowSelf     = errors.New("community cannot follow or block yourself")
+	ErrCommunityFollowInvalid  = errors.New("community follow target invalid")
+	ErrCommunityFollowNotFound = errors.New("community follow not found")
+	ErrCommunityBlockNotFound  = errors.New("community block not found")
+)
+
+// Follow targets. STOCK and FUTURES follows are stored under the same key the
+// sentiment index uses, so following RB2405 follows every RB contract.
+const (
+	CommunityFollowTargetUser      = "USER"
+	CommunityFollowTargetStock     = "STOCK"
+	CommunityFollowTargetFutures   = "FUTURES"
+	CommunityFollowTargetTopicType = "TOPIC_TYPE"
+)
+
+// MUTE hides a user's topics from my feed and drops notifications they
+// trigger for me. BLOCK additionally stops them from following or
+// mentioning me and hides my topics from their feed.
+const (
+	CommunityBlockTypeMute  = "MUTE"
+	CommunityBlockTypeBlock = "BLOCK"
+)
+
+type CommunityFollow struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type CommunityUserBlock struct {
+	ID            string `json:"id"`
+	UserID        string `json:"user_id"`
+	BlockedUserID string `json:"blocked_user_id"`
+	BlockType     string `json:"block_type"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// CommunityMention is one @user_id reference in a published topic or
+// comment, listed for the mentioned user.
+type CommunityMention struct {
+	ID              string `json:"id"`
+	TargetType      string `json:"target_type"`
+	TargetID        string `json:"target_id"`
+	TopicID         string `json:"topic_id"`
+	TopicTitle      string `json:"topic_title"`
+	MentionedUserID string `json:"mentioned_user_id"`
+	ActorUserID     string `json:"actor_user_id"`
+	CreatedAt       string `json:"created_at"`
+}
+
+type CommunityMentionInput struct {
+	ActorUserID      string
+	TargetType       string
+	TargetID         string
+	TopicID          string
+	MentionedUserIDs []string
+}
+
+// CommunityFeedItem is a topic ranked for one viewer. FeedReasons lists the
+// personal signals that boosted it (FOLLOWED_USER, FOLLOWED_SYMBOL, ...).
+type CommunityFeedItem struct {
+	CommunityTopicListItem
+	FeedScore   float64  `json:"feed_score"`
+	FeedReasons []string `json:"feed_reasons"`
+}
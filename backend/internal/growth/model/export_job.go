@@ -0,0 +1,126 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrExportJobNotFound      = errors.New("export job not found")
+	ErrExportJobNotReady      = errors.New("export file is not ready")
+	ErrExportTypeNotSupported = errors.New("export type not supported")
+)
+
+// Datasets that can be exported in the background. Each maps to one of the
+// admin list endpoints and takes the same filters.
+const (
+	ExportTypeUsers            = "USERS"
+	ExportTypeBrowseHistories  = "BROWSE_HISTORIES"
+	ExportTypeMembershipOrders = "MEMBERSHIP_ORDERS"
+	ExportTypeOperationLogs    = "OPERATION_LOGS"
+	ExportTypeSchedulerJobRuns = "SCHEDULER_JOB_RUNS"
+	ExportTypeReviewTasks      = "REVIEW_TASKS"
+	ExportTypeWorkflowMessages = "WORKFLOW_MESSAGES"
+	ExportTypeLoginLogs        = "LOGIN_LOGS"
+	ExportTypeQuantEvaluation  = "QUANT_EVALUATION"
+)
+
+const (
+	ExportFormatCSV  = "CSV"
+	ExportFormatXLSX = "XLSX"
+)
+
+// A job is PENDING until the export worker claims it (RUNNING), then ends
+// SUCCEEDED or FAILED. SUCCEEDED files are deleted from storage once they
+// pass ExpiresAt and the job becomes EXPIRED; the row itself is kept as the
+// audit record. A synchronous export.csv response is recorded as a
+// DELIVERED job with no stored file.
+const (
+	ExportJobStatusPending   = "PENDING"
+	ExportJobStatusRunning   = "RUNNING"
+	ExportJobStatusSucceeded = "SUCCEEDED"
+	ExportJobStatusFailed    = "FAILED"
+	ExportJobStatusExpired   = "EXPIRED"
+	ExportJobStatusDelivered = "DELIVERED"
+)
+
+// How a download was handed out: through the backend's signed link, as a
+// presigned object store URL when no export signing secret is configured,
+// or directly in an export.csv response.
+const (
+	ExportDownloadChannelSignedLink = "SIGNED_LINK"
+	ExportDownloadChannelPresigned  = "PRESIGNED"
+	ExportDownloadChannelDirect     = "DIRECT"
+)
+
+// ExportJob is one requested export and, once it has run, the file it
+// produced. StorageRef is a storage:// reference and never leaves the
+// backend; requesters download through a time-limited link.
+type ExportJob struct {
+	ID               string            `json:"id"`
+	ExportType       string            `json:"export_type"`
+	Format           string            `json:"format"`
+	Filters          map[string]string `json:"filters"`
+	Status           string            `json:"status"`
+	RowCount         int               `json:"row_count"`
+	FileName         string            `json:"file_name,omitempty"`
+	FileSize         int64             `json:"file_size"`
+	ContentSHA256    string            `json:"content_sha256,omitempty"`
+	StorageRef       string            `json:"-"`
+	ErrorMessage     string            `json:"error_message,omitempty"`
+	RequestedBy      string            `json:"requested_by"`
+	RequestIP        string            `json:"request_ip,omitempty"`
+	DownloadCount    int               `json:"download_count"`
+	CreatedAt        string            `json:"created_at"`
+	StartedAt        string            `json:"started_at,omitempty"`
+	FinishedAt       string            `json:"finished_at,omitempty"`
+	ExpiresAt        string            `json:"expires_at,omitempty"`
+	LastDownloadedAt string            `json:"last_downloaded_at,omitempty"`
+}
+
+// ExportDownload records one download of an export file.
+type ExportDownload struct {
+	ID           string `json:"id"`
+	JobID        string `json:"job_id"`
+	UserID       string `json:"user_id"`
+	Channel      string `json:"channel"`
+	ClientIP     string `json:"client_ip,omitempty"`
+	UserAgent    string `json:"user_agent,omitempty"`
+	DownloadedAt string `json:"downloaded_at"`
+}
+
+func NormalizeExportFormat(format string) string {
+	switch strings.ToUpper(strings.TrimSpace(format)) {
+	case ExportFormatXLSX:
+		return ExportFormatXLSX
+	default:
+		return ExportFormatCSV
+	}
+}
+
+func (j ExportJob) FileExtension() string {
+	if j.Format == ExportFormatXLSX {
+		return "xlsx"
+	}
+	return "csv"
+}
+
+func (j ExportJob) ContentType() string {
+	if j.Format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// LoginLog is one auth_login_logs entry.
+type LoginLog struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Phone     string `json:"phone"`
+	Action    string `json:"action"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+type ArbitrageSpreadPoint struct {
+	TradeDate  string  `json:"trade_date"`
+	Spread     float64 `json:"spread"`
+	ZScore     float64 `json:"z_score"`
+	Percentile float64 `json:"percentile"`
+}
+
+type FuturesArbitrageEngineItem struct {
+	ID         string  `json:"id"`
+	PairKey    string  `json:"pair_key"`
+	Type       string  `json:"type"`
+	ContractA  string  `json:"contract_a"`
+	ContractB  string  `json:"contract_b"`
+	Spread     float64 `json:"spread"`
+	Percentile float64 `json:"percentile"`
+	ZScore     float64 `json:"z_score"`
+	HalfLife   float64 `json:"half_life"`
+	SampleDays int     `json:"sample_days"`
+	Direction  string  `json:"direction,omitempty"`
+	RiskLevel  string  `json:"risk_level,omitempty"`
+	Status     string  `json:"status"`
+}
+
+type FuturesArbitrageEngineResult struct {
+	TradeDate   string                       `json:"trade_date"`
+	PairCount   int                          `json:"pair_count"`
+	Computed    int                          `json:"computed"`
+	Skipped     int                          `json:"skipped"`
+	Expired     int                          `json:"expired"`
+	OpenSignals int                          `json:"open_signals"`
+	ExitSignals int                          `json:"exit_signals"`
+	Items       []FuturesArbitrageEngineItem `json:"items"`
+	Warnings    []string                     `json:"warnings,omitempty"`
+}
@@ -56,6 +56,10 @@ type MarketNewsItem struct {
 	PublishedAt   string   `json:"published_at"`
 	CreatedAt     string   `json:"created_at,omitempty"`
 	UpdatedAt     string   `json:"updated_at,omitempty"`
+	// DuplicateOf and CanonicalTitle are filled during sync when the item is a
+	// near-duplicate of an already stored story from another source.
+	DuplicateOf    string `json:"duplicate_of,omitempty"`
+	CanonicalTitle string `json:"-"`
 }
 
 type FuturesInventorySnapshot struct {
@@ -0,0 +1,54 @@
+package model
+
+import "errors"
+
+var (
+	ErrMembershipAutoRenewNotFound    = errors.New("membership auto-renew not found")
+	ErrMembershipAutoRenewNotEligible = errors.New("membership auto-renew not eligible")
+	// ErrMembershipAutoRenewUnsupported means the pay channel cannot charge
+	// a withholding agreement. YOLKPAY has no agreement API, so auto-renew
+	// is only available where the SANDBOX channel is.
+	ErrMembershipAutoRenewUnsupported = errors.New("pay channel does not support auto-renew")
+)
+
+// MembershipAutoRenewal is a user's standing instruction to renew ProductID
+// through a signed channel agreement. ACTIVE renewals are charged
+// membership.auto_renew.lead_hours before CurrentPeriodEnd. A failed charge
+// moves the renewal to PAST_DUE: VIP is kept until GraceUntil while
+// NextChargeAt walks the retry schedule. Running out of retries LAPSES it;
+// CANCELED is the user opting out.
+type MembershipAutoRenewal struct {
+	ID               string `json:"id"`
+	UserID           string `json:"user_id"`
+	ProductID        string `json:"product_id"`
+	PayChannel       string `json:"pay_channel"`
+	AgreementNo      string `json:"agreement_no"`
+	Status           string `json:"status"`
+	CurrentPeriodEnd string `json:"current_period_end,omitempty"`
+	NextChargeAt     string `json:"next_charge_at,omitempty"`
+	FailedAttempts   int    `json:"failed_attempts"`
+	GraceUntil       string `json:"grace_until,omitempty"`
+	RemindedAt       string `json:"reminded_at,omitempty"`
+	LastOrderNo      string `json:"last_order_no,omitempty"`
+	LastError        string `json:"last_error,omitempty"`
+	CanceledAt       string `json:"canceled_at,omitempty"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+// MembershipRenewalAttempt logs one pass of the auto-renew job over a due
+// renewal. Result is PAID, PENDING, FAILED, LAPSED (failed with no retry
+// left) or SYNCED (the period was already extended by another payment).
+type MembershipRenewalAttempt struct {
+	ID           string `json:"id"`
+	RenewalID    string `json:"renewal_id"`
+	UserID       string `json:"user_id"`
+	OrderNo      string `json:"order_no,omitempty"`
+	PayChannel   string `json:"pay_channel"`
+	Attempt      int    `json:"attempt"`
+	Result       string `json:"result"`
+	TradeStatus  string `json:"trade_status,omitempty"`
+	ChannelTxnNo string `json:"channel_txn_no,omitempty"`
+	Message      string `json:"message,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
@@ -0,0 +1,80 @@
+package model
+
+import "errors"
+
+var (
+	ErrMembershipCouponNotFound      = errors.New("membership coupon not found")
+	ErrMembershipCouponNotApplicable = errors.New("membership coupon not applicable")
+)
+
+// MembershipCoupon is a discount code for membership products. DiscountType is
+// PERCENT (DiscountValue is the percentage off, capped by MaxDiscount when set)
+// or FIXED (DiscountValue is taken off the price). Zero limits mean unlimited.
+// ProductIDs and MemberLevels restrict which products the code applies to; an
+// empty list allows all. INVITE coupons are issued to one invitee (OwnerUserID)
+// when they register through an invite link, copied from a TEMPLATE coupon
+// that cannot be redeemed itself.
+type MembershipCoupon struct {
+	ID                string   `json:"id"`
+	Code              string   `json:"code"`
+	Name              string   `json:"name"`
+	DiscountType      string   `json:"discount_type"`
+	DiscountValue     float64  `json:"discount_value"`
+	MaxDiscount       float64  `json:"max_discount,omitempty"`
+	MinAmount         float64  `json:"min_amount,omitempty"`
+	ValidFrom         string   `json:"valid_from,omitempty"`
+	ValidTo           string   `json:"valid_to,omitempty"`
+	TotalLimit        int      `json:"total_limit"`
+	PerUserLimit      int      `json:"per_user_limit"`
+	RedeemedCount     int      `json:"redeemed_count"`
+	ProductIDs        []string `json:"product_ids"`
+	MemberLevels      []string `json:"member_levels"`
+	FirstPurchaseOnly bool     `json:"first_purchase_only"`
+	Source            string   `json:"source"`
+	OwnerUserID       string   `json:"owner_user_id,omitempty"`
+	InviteRecordID    string   `json:"invite_record_id,omitempty"`
+	Status            string   `json:"status"`
+	CreatedBy         string   `json:"created_by,omitempty"`
+	CreatedAt         string   `json:"created_at,omitempty"`
+	UpdatedAt         string   `json:"updated_at,omitempty"`
+}
+
+// MembershipCouponRedemption ties a coupon to the order it discounted. It is
+// RESERVED while the order is pending, REDEEMED once paid and RELEASED when
+// the order is closed unpaid, so abandoned orders give the quota back.
+type MembershipCouponRedemption struct {
+	ID             string  `json:"id"`
+	CouponID       string  `json:"coupon_id"`
+	CouponCode     string  `json:"coupon_code"`
+	UserID         string  `json:"user_id"`
+	OrderID        string  `json:"order_id"`
+	OrderNo        string  `json:"order_no"`
+	ProductID      string  `json:"product_id"`
+	OriginalAmount float64 `json:"original_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+	Amount         float64 `json:"amount"`
+	Status         string  `json:"status"`
+	CreatedAt      string  `json:"created_at"`
+	RedeemedAt     string  `json:"redeemed_at,omitempty"`
+}
+
+// MembershipCouponQuote is the price a user would pay for a product with a
+// coupon applied.
+type MembershipCouponQuote struct {
+	CouponCode     string  `json:"coupon_code"`
+	ProductID      string  `json:"product_id"`
+	OriginalAmount float64 `json:"original_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+	Amount         float64 `json:"amount"`
+}
+
+type AdminExperimentAnalyticsCouponItem struct {
+	CouponCode     string  `json:"coupon_code"`
+	ExperimentKey  string  `json:"experiment_key,omitempty"`
+	VariantKey     string  `json:"variant_key,omitempty"`
+	PaidOrderCount int     `json:"paid_order_count"`
+	OriginalAmount float64 `json:"original_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+	Revenue        float64 `json:"revenue"`
+	LastPaidAt     string  `json:"last_paid_at,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "errors"
+
+var (
+	ErrMembershipOrderNotRefundable = errors.New("membership order is not paid and cannot be refunded")
+	ErrMembershipOrderPaid          = errors.New("membership order is paid; use the refund flow to close it")
+)
+
+// MembershipRefund is one entry of the refund ledger. Amount is the prorated
+// unused part of OrderAmount; UnusedSeconds is the VIP time taken back.
+type MembershipRefund struct {
+	ID              string  `json:"id"`
+	OrderID         string  `json:"order_id"`
+	OrderNo         string  `json:"order_no"`
+	UserID          string  `json:"user_id"`
+	PayChannel      string  `json:"pay_channel"`
+	RefundChannel   string  `json:"refund_channel"`
+	OrderAmount     float64 `json:"order_amount"`
+	Amount          float64 `json:"amount"`
+	UnusedSeconds   int64   `json:"unused_seconds"`
+	Status          string  `json:"status"`
+	ChannelRefundNo string  `json:"channel_refund_no,omitempty"`
+	FailureReason   string  `json:"failure_reason,omitempty"`
+	Reason          string  `json:"reason"`
+	Operator        string  `json:"operator"`
+	CreatedAt       string  `json:"created_at"`
+	CompletedAt     string  `json:"completed_at,omitempty"`
+}
@@ -96,14 +96,17 @@ type InviteSummary struct {
 }
 
 type RewardRecord struct {
-	ID           string  `json:"id"`
-	InviterUser  string  `json:"inviter_user_id,omitempty"`
-	InviteeUser  string  `json:"invitee_user_id,omitempty"`
-	RewardType   string  `json:"reward_type"`
-	RewardValue  float64 `json:"reward_value"`
-	TriggerEvent string  `json:"trigger_event"`
-	Status       string  `json:"status"`
-	IssuedAt     string  `json:"issued_at"`
+	ID            string  `json:"id"`
+	InviterUser   string  `json:"inviter_user_id,omitempty"`
+	InviteeUser   string  `json:"invitee_user_id,omitempty"`
+	RewardType    string  `json:"reward_type"`
+	RewardValue   float64 `json:"reward_value"`
+	TriggerEvent  string  `json:"trigger_event"`
+	Status        string  `json:"status"`
+	IssuedAt      string  `json:"issued_at"`
+	OrderID       string  `json:"order_id,omitempty"`
+	ReferralLevel int     `json:"referral_level,omitempty"`
+	AvailableAt   string  `json:"available_at,omitempty"`
 }
 
 type UserProfile struct {
@@ -139,6 +142,8 @@ type UserMessage struct {
 	Type       string `json:"type"`
 	ReadStatus string `json:"read_status"`
 	CreatedAt  string `json:"created_at"`
+	// Seq is the row's insert sequence, which the message streams page on.
+	Seq int64 `json:"-"`
 }
 
 type AdminUserMessage struct {
@@ -178,6 +183,7 @@ type SignedURL struct {
 type RewardWallet struct {
 	CashBalance    float64 `json:"cash_balance"`
 	CashFrozen     float64 `json:"cash_frozen"`
+	CashDebt       float64 `json:"cash_debt"`
 	CouponBalance  float64 `json:"coupon_balance"`
 	VIPDaysBalance int     `json:"vip_days_balance"`
 }
@@ -248,6 +254,16 @@ type ArbitrageRecommendation struct {
 	TriggerRule string  `json:"trigger_rule,omitempty"`
 	Status      string  `json:"status,omitempty"`
 	ValidTo     string  `json:"valid_to,omitempty"`
+	ZScore      float64 `json:"z_score,omitempty"`
+	HalfLife    float64 `json:"half_life,omitempty"`
+	SpreadMean  float64 `json:"spread_mean,omitempty"`
+	SpreadStd   float64 `json:"spread_std,omitempty"`
+	SampleDays  int     `json:"sample_days,omitempty"`
+	Direction   string  `json:"direction,omitempty"`
+	RiskLevel   string  `json:"risk_level,omitempty"`
+	TradeDate   string  `json:"trade_date,omitempty"`
+
+	History []ArbitrageSpreadPoint `json:"history,omitempty"`
 }
 
 type FuturesReview struct {
@@ -304,6 +320,15 @@ type NewsArticle struct {
 	PublishedAt     string `json:"published_at,omitempty"`
 	AuthorID        string `json:"author_id,omitempty"`
 	AttachmentCount int    `json:"attachment_count,omitempty"`
+	// ScheduledPublishAt is set while Status is SCHEDULED; EmbargoUntil keeps
+	// the article hidden from users until then even once published.
+	ScheduledPublishAt string `json:"scheduled_publish_at,omitempty"`
+	EmbargoUntil       string `json:"embargo_until,omitempty"`
+	// DuplicateOf is the canonical article of the near-duplicate group this
+	// article was merged into; canonical articles carry their alternates.
+	DuplicateOf          string                       `json:"duplicate_of,omitempty"`
+	AlternateSourceCount int                          `json:"alternate_source_count,omitempty"`
+	AlternateSources     []NewsArticleAlternateSource `json:"alternate_sources,omitempty"`
 }
 
 type NewsAttachment struct {
@@ -682,6 +707,7 @@ type AdminRole struct {
 	Status          string   `json:"status"`
 	BuiltIn         bool     `json:"built_in"`
 	PermissionCodes []string `json:"permission_codes"`
+	Require2FA      bool     `json:"require_2fa"`
 	UserCount       int      `json:"user_count"`
 	CreatedAt       string   `json:"created_at"`
 	UpdatedAt       string   `json:"updated_at"`
@@ -695,6 +721,7 @@ type AdminAccount struct {
 	RoleIDs   []string `json:"role_ids"`
 	RoleKeys  []string `json:"role_keys"`
 	RoleNames []string `json:"role_names"`
+	TwoFactor string   `json:"two_factor_status"`
 	LastLogin string   `json:"last_login"`
 	CreatedAt string   `json:"created_at"`
 }
@@ -764,15 +791,19 @@ type MembershipProduct struct {
 }
 
 type MembershipOrderAdmin struct {
-	ID         string  `json:"id"`
-	OrderNo    string  `json:"order_no,omitempty"`
-	UserID     string  `json:"user_id"`
-	ProductID  string  `json:"product_id"`
-	Amount     float64 `json:"amount"`
-	PayChannel string  `json:"pay_channel,omitempty"`
-	Status     string  `json:"status"`
-	PaidAt     string  `json:"paid_at,omitempty"`
-	CreatedAt  string  `json:"created_at,omitempty"`
+	ID             string  `json:"id"`
+	OrderNo        string  `json:"order_no,omitempty"`
+	UserID         string  `json:"user_id"`
+	ProductID      string  `json:"product_id"`
+	Amount         float64 `json:"amount"`
+	OriginalAmount float64 `json:"original_amount,omitempty"`
+	DiscountAmount float64 `json:"discount_amount,omitempty"`
+	CouponCode     string  `json:"coupon_code,omitempty"`
+	PayChannel     string  `json:"pay_channel,omitempty"`
+	OrderSource    string  `json:"order_source,omitempty"`
+	Status         string  `json:"status"`
+	PaidAt         string  `json:"paid_at,omitempty"`
+	CreatedAt      string  `json:"created_at,omitempty"`
 }
 
 type UserAccessProfile struct {
@@ -786,6 +817,8 @@ type UserAccessProfile struct {
 type AttachmentFileInfo struct {
 	FileURL   string `json:"file_url"`
 	ArticleID string `json:"article_id"`
+	FileName  string `json:"file_name"`
+	MimeType  string `json:"mime_type"`
 }
 
 type VIPQuotaConfig struct {
@@ -855,6 +888,17 @@ type SystemConfig struct {
 	UpdatedAt   string `json:"updated_at"`
 }
 
+type SystemConfigSecretRotationResult struct {
+	PrimaryKeyID string   `json:"primary_key_id"`
+	Scanned      int      `json:"scanned"`
+	Sealed       int      `json:"sealed"`
+	Rotated      int      `json:"rotated"`
+	Unchanged    int      `json:"unchanged"`
+	Failed       int      `json:"failed"`
+	Failures     []string `json:"failures,omitempty"`
+	RotatedAt    string   `json:"rotated_at"`
+}
+
 type ReviewTask struct {
 	ID          string `json:"id"`
 	Module      string `json:"module"`
@@ -1026,6 +1070,8 @@ type WorkflowMessage struct {
 	IsRead     bool   `json:"is_read"`
 	CreatedAt  string `json:"created_at"`
 	ReadAt     string `json:"read_at,omitempty"`
+	// Seq is the row's insert sequence, which the message streams page on.
+	Seq int64 `json:"-"`
 }
 
 type WorkflowMetrics struct {
@@ -1212,6 +1258,7 @@ type AdminExperimentAnalyticsPayChannelItem struct {
 	PayChannel          string  `json:"pay_channel"`
 	PaymentSuccessCount int     `json:"payment_success_count"`
 	RenewalSuccessCount int     `json:"renewal_success_count"`
+	RenewalFailedCount  int     `json:"renewal_failed_count"`
 	PaidSuccessCount    int     `json:"paid_success_count"`
 	PaidShareRate       float64 `json:"paid_share_rate"`
 	LastEventAt         string  `json:"last_event_at,omitempty"`
@@ -1282,4 +1329,5 @@ type AdminExperimentAnalyticsSummary struct {
 	DeviceBreakdown     []AdminExperimentAnalyticsDeviceItem        `json:"device_breakdown"`
 	UserStageBreakdown  []AdminExperimentAnalyticsUserStageItem     `json:"user_stage_breakdown"`
 	VariantDailyTrend   []AdminExperimentAnalyticsVariantTrendPoint `json:"variant_daily_trend"`
+	CouponBreakdown     []AdminExperimentAnalyticsCouponItem        `json:"coupon_breakdown"`
 }
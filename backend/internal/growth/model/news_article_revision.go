@@ -0,0 +1,65 @@
+package model
+
+import "errors"
+
+var (
+	ErrNewsArticleRevisionNotFound = errors.New("news article revision not found")
+	ErrNewsArticleStatusConflict   = errors.New("news article status does not allow this change")
+)
+
+const (
+	NewsArticleStatusDraft     = "DRAFT"
+	NewsArticleStatusScheduled = "SCHEDULED"
+	NewsArticleStatusPublished = "PUBLISHED"
+	NewsArticleStatusDisabled  = "DISABLED"
+
+	NewsArticleRevisionCreate  = "CREATE"
+	NewsArticleRevisionUpdate  = "UPDATE"
+	NewsArticleRevisionRestore = "RESTORE"
+)
+
+// NewsArticleRevision is a snapshot of an article's editable fields taken
+// after every create, update and restore. RevisionNo starts at 1 per article.
+type NewsArticleRevision struct {
+	ID           string `json:"id"`
+	ArticleID    string `json:"article_id"`
+	RevisionNo   int    `json:"revision_no"`
+	Action       string `json:"action"`
+	RestoredFrom int    `json:"restored_from,omitempty"`
+	CategoryID   string `json:"category_id"`
+	Title        string `json:"title"`
+	Summary      string `json:"summary"`
+	Content      string `json:"content,omitempty"`
+	CoverURL     string `json:"cover_url,omitempty"`
+	Visibility   string `json:"visibility"`
+	Status       string `json:"status"`
+	EditorID     string `json:"editor_id"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// NewsArticleRevisionDiff compares two revisions of the same article. Fields
+// lists only the fields that changed; multi-line fields carry a line diff.
+type NewsArticleRevisionDiff struct {
+	ArticleID    string                         `json:"article_id"`
+	FromRevision int                            `json:"from_revision"`
+	ToRevision   int                            `json:"to_revision"`
+	FromEditorID string                         `json:"from_editor_id"`
+	ToEditorID   string                         `json:"to_editor_id"`
+	FromAt       string                         `json:"from_at"`
+	ToAt         string                         `json:"to_at"`
+	Fields       []NewsArticleRevisionFieldDiff `json:"fields"`
+}
+
+type NewsArticleRevisionFieldDiff struct {
+	Field  string                        `json:"field"`
+	Before string                        `json:"before,omitempty"`
+	After  string                        `json:"after,omitempty"`
+	Lines  []NewsArticleRevisionDiffLine `json:"lines,omitempty"`
+}
+
+// NewsArticleRevisionDiffLine is one line of a line diff. Op is "=" for an
+// unchanged line, "-" for a removed line and "+" for an added line.
+type NewsArticleRevisionDiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
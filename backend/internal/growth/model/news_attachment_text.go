@@ -0,0 +1,28 @@
+package model
+
+// NewsAttachmentText is the extracted text of one attachment file, shared by
+// every attachment row with the same file_url.
+type NewsAttachmentText struct {
+	FileURL      string `json:"file_url"`
+	Format       string `json:"format"`
+	Status       string `json:"status"`
+	PageCount    int    `json:"page_count"`
+	PageOffsets  []int  `json:"page_offsets,omitempty"`
+	Content      string `json:"-"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	ExtractedAt  string `json:"extracted_at"`
+}
+
+// NewsAttachmentSearchHit is an attachment whose text matched a search, with
+// the page of the first match and the text around it.
+type NewsAttachmentSearchHit struct {
+	AttachmentID string `json:"attachment_id"`
+	ArticleID    string `json:"article_id"`
+	ArticleTitle string `json:"article_title"`
+	Visibility   string `json:"visibility"`
+	FileName     string `json:"file_name"`
+	MimeType     string `json:"mime_type"`
+	PageCount    int    `json:"page_count"`
+	Page         int    `json:"page"`
+	Snippet      string `json:"snippet"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "errors"
+
+// ErrNewsArticleMergeConflict is returned when an article cannot be merged
+// into the requested group, or unmerged because it is not a duplicate.
+var ErrNewsArticleMergeConflict = errors.New("news article merge conflict")
+
+// NewsArticleAlternateSource is another article in the same near-duplicate
+// group, usually the same story synced from a different source.
+type NewsArticleAlternateSource struct {
+	ID          string `json:"id"`
+	CategoryID  string `json:"category_id"`
+	Title       string `json:"title"`
+	PublishedAt string `json:"published_at,omitempty"`
+	Canonical   bool   `json:"canonical,omitempty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+// PaymentPollLog is one active status query of a pending membership order.
+// Result is PAID, PENDING, FAILED, CANCELED, NOT_FOUND, MISMATCH, UNSUPPORTED
+// or ERROR.
+type PaymentPollLog struct {
+	ID           string `json:"id"`
+	OrderID      string `json:"order_id"`
+	OrderNo      string `json:"order_no"`
+	PayChannel   string `json:"pay_channel"`
+	Result       string `json:"result"`
+	TradeStatus  string `json:"trade_status,omitempty"`
+	ChannelTxnNo string `json:"channel_txn_no,omitempty"`
+	Message      string `json:"message,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
@@ -0,0 +1,350 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrReviewSLAPolicyNotFound = errors.New("review sla policy not found")
+	ErrReviewSLAPolicyInvalid  = errors.New("invalid review sla policy")
+)
+
+// Work items an SLA clock can run on. Review tasks cover NEWS, STOCK,
+// FUTURES and STOCK_EVENT; community reports are tracked under COMMUNITY.
+const (
+	ReviewSLASubjectReviewTask      = "REVIEW_TASK"
+	ReviewSLASubjectCommunityReport = "COMMUNITY_REPORT"
+
+	ReviewSLAModuleCommunity = "COMMUNITY"
+)
+
+const (
+	ReviewSLAPriorityHigh   = "HIGH"
+	ReviewSLAPriorityNormal = "NORMAL"
+	ReviewSLAPriorityLow    = "LOW"
+)
+
+// An item is OPEN until it is either resolved or passes its due time. The
+// scheduler moves overdue items to BREACHED and escalates them; resolving
+// an item records whether it was MET or MISSED.
+const (
+	ReviewSLAStatusOpen     = "OPEN"
+	ReviewSLAStatusBreached = "BREACHED"
+	ReviewSLAStatusMet      = "MET"
+	ReviewSLAStatusMissed   = "MISSED"
+)
+
+const reviewSLADefaultTimezone = "Asia/Shanghai"
+
+// ReviewSLAPolicy sets how long a module's work items may wait, by
+// priority. With BusinessHoursOnly the clock only runs between
+// BusinessStart and BusinessEnd on Workdays (1 = Monday ... 7 = Sunday), so
+// a HIGH item raised overnight falls due shortly after the next open.
+type ReviewSLAPolicy struct {
+	Module            string `json:"module"`
+	Status            string `json:"status"`
+	HighMinutes       int    `json:"high_minutes"`
+	NormalMinutes     int    `json:"normal_minutes"`
+	LowMinutes        int    `json:"low_minutes"`
+	BusinessHoursOnly bool   `json:"business_hours_only"`
+	BusinessStart     string `json:"business_start"`
+	BusinessEnd       string `json:"business_end"`
+	Workdays          []int  `json:"workdays"`
+	Timezone          string `json:"timezone"`
+	BackupReviewerID  string `json:"backup_reviewer_id,omitempty"`
+	BackupRole        string `json:"backup_role,omitempty"`
+	UpdatedBy         string `json:"updated_by,omitempty"`
+	UpdatedAt         string `json:"updated_at,omitempty"`
+}
+
+// ReviewSLAItem is the SLA clock of one review task or community report.
+type ReviewSLAItem struct {
+	ID          string `json:"id"`
+	SubjectType string `json:"subject_type"`
+	SubjectID   string `json:"subject_id"`
+	Module      string `json:"module"`
+	TargetID    string `json:"target_id"`
+	Priority    string `json:"priority"`
+	AssigneeID  string `json:"assignee_id,omitempty"`
+	Status      string `json:"status"`
+	StartedAt   string `json:"started_at"`
+	DueAt       string `json:"due_at"`
+	BreachedAt  string `json:"breached_at,omitempty"`
+	// BreachedAssigneeID is who held the item when it went past due, before
+	// escalation handed it to anyone else.
+	BreachedAssigneeID string `json:"breached_assignee_id,omitempty"`
+	EscalatedTo        string `json:"escalated_to,omitempty"`
+	EscalatedAt        string `json:"escalated_at,omitempty"`
+	ResolvedBy         string `json:"resolved_by,omitempty"`
+	ResolvedAt         string `json:"resolved_at,omitempty"`
+}
+
+// ReviewerSLAStat is one reviewer's SLA record. ComplianceRate is the share
+// of resolved items that were MET.
+type ReviewerSLAStat struct {
+	ReviewerID        string  `json:"reviewer_id"`
+	Resolved          int     `json:"resolved"`
+	Met               int     `json:"met"`
+	Missed            int     `json:"missed"`
+	ComplianceRate    float64 `json:"compliance_rate"`
+	AvgResolveMinutes float64 `json:"avg_resolve_minutes"`
+	OpenItems         int     `json:"open_items"`
+	OpenBreached      int     `json:"open_breached"`
+}
+
+type ReviewSLAMetrics struct {
+	Open           int               `json:"open"`
+	OpenBreached   int               `json:"open_breached"`
+	Resolved       int               `json:"resolved"`
+	Met            int               `json:"met"`
+	Missed         int               `json:"missed"`
+	ComplianceRate float64           `json:"compliance_rate"`
+	Reviewers      []ReviewerSLAStat `json:"reviewers"`
+}
+
+// IsReviewSLAModule reports whether module has work items an SLA policy
+// can cover.
+func IsReviewSLAModule(module string) bool {
+	switch module {
+	case "NEWS", "STOCK", "FUTURES", "STOCK_EVENT", ReviewSLAModuleCommunity:
+		return true
+	}
+	return false
+}
+
+// NormalizeReviewSLAPriority maps an empty or unknown priority to NORMAL.
+func NormalizeReviewSLAPriority(priority string) string {
+	switch value := strings.ToUpper(strings.TrimSpace(priority)); value {
+	case ReviewSLAPriorityHigh, ReviewSLAPriorityLow:
+		return value
+	}
+	return ReviewSLAPriorityNormal
+}
+
+// Normalized fills in defaults and validates the policy. Due minutes must
+// be positive, business hours must be a valid HH:MM window on at least one
+// workday, and Timezone must be an IANA zone this host can load.
+func (p ReviewSLAPolicy) Normalized() (ReviewSLAPolicy, error) {
+	p.Module = strings.ToUpper(strings.TrimSpace(p.Module))
+	p.Status = strings.ToUpper(strings.TrimSpace(p.Status))
+	if p.Status == "" {
+		p.Status = ApprovalPolicyStatusActive
+	}
+	p.BusinessStart = strings.TrimSpace(p.BusinessStart)
+	p.BusinessEnd = strings.TrimSpace(p.BusinessEnd)
+	if p.BusinessStart == "" {
+		p.BusinessStart = "09:00"
+	}
+	if p.BusinessEnd == "" {
+		p.BusinessEnd = "18:00"
+	}
+	p.Timezone = strings.TrimSpace(p.Timezone)
+	if p.Timezone == "" {
+		p.Timezone = reviewSLADefaultTimezone
+	}
+	p.BackupReviewerID = strings.TrimSpace(p.BackupReviewerID)
+	p.BackupRole = strings.ToUpper(strings.TrimSpace(p.BackupRole))
+	if !IsReviewSLAModule(p.Module) {
+		return p, fmt.Errorf("%w: unsupported module %s", ErrReviewSLAPolicyInvalid, p.Module)
+	}
+	if p.Status != ApprovalPolicyStatusActive && p.Status != ApprovalPolicyStatusDisabled {
+		return p, fmt.Errorf("%w: unsupported status %s", ErrReviewSLAPolicyInvalid, p.Status)
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil && p.Timezone != reviewSLADefaultTimezone {
+		return p, fmt.Errorf("%w: unknown timezone %s", ErrReviewSLAPolicyInvalid, p.Timezone)
+	}
+	if p.HighMinutes <= 0 || p.NormalMinutes <= 0 || p.LowMinutes <= 0 {
+		return p, fmt.Errorf("%w: due minutes must be positive for every priority", ErrReviewSLAPolicyInvalid)
+	}
+	openMinute, okOpen := parseReviewSLAClock(p.BusinessStart)
+	closeMinute, okClose := parseReviewSLAClock(p.BusinessEnd)
+	if !okOpen || !okClose || openMinute >= closeMinute {
+		return p, fmt.Errorf("%w: business hours must be HH:MM with start before end", ErrReviewSLAPolicyInvalid)
+	}
+	seen := map[int]bool{}
+	workdays := make([]int, 0, len(p.Workdays))
+	for _, day := range p.Workdays {
+		if day < 1 || day > 7 {
+			return p, fmt.Errorf("%w: workday %d is out of range 1-7", ErrReviewSLAPolicyInvalid, day)
+		}
+		if !seen[day] {
+			seen[day] = true
+			workdays = append(workdays, day)
+		}
+	}
+	if len(workdays) == 0 {
+		workdays = []int{1, 2, 3, 4, 5}
+	}
+	sort.Ints(workdays)
+	p.Workdays = workdays
+	return p, nil
+}
+
+// DueMinutes returns the time allowed for an item of this priority.
+func (p ReviewSLAPolicy) DueMinutes(priority string) int {
+	switch NormalizeReviewSLAPriority(priority) {
+	case ReviewSLAPriorityHigh:
+		return p.HighMinutes
+	case ReviewSLAPriorityLow:
+		return p.LowMinutes
+	}
+	return p.NormalMinutes
+}
+
+// DueAt returns when an item of this priority raised at start falls due.
+// Outside business hours the clock is paused.
+func (p ReviewSLAPolicy) DueAt(start time.Time, priority string) time.Time {
+	remaining := time.Duration(p.DueMinutes(priority)) * time.Minute
+	if !p.BusinessHoursOnly {
+		return start.Add(remaining)
+	}
+	openMinute, okOpen := parseReviewSLAClock(p.BusinessStart)
+	closeMinute, okClose := parseReviewSLAClock(p.BusinessEnd)
+	if !okOpen || !okClose || openMinute >= closeMinute {
+		return start.Add(remaining)
+	}
+	workdays := map[int]bool{}
+	for _, day := range p.Workdays {
+		workdays[day] = true
+	}
+	if len(workdays) == 0 {
+		workdays = map[int]bool{1: true, 2: true, 3: true, 4: true, 5: true}
+	}
+
+	cursor := start.In(p.location())
+	// A year of skipped days is enough for any sane workday set.
+	for i := 0; i < 400; i++ {
+		dayStart := time.Date(cursor.Year(), cursor.Month(), cursor.Day(), 0, 0, 0, 0, cursor.Location())
+		openAt := dayStart.Add(time.Duration(openMinute) * time.Minute)
+		closeAt := dayStart.Add(time.Duration(closeMinute) * time.Minute)
+		weekday := int(cursor.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		if !workdays[weekday] || !cursor.Before(closeAt) {
+			cursor = dayStart.AddDate(0, 0, 1)
+			continue
+		}
+		if cursor.Before(openAt) {
+			cursor = openAt
+		}
+		available := closeAt.Sub(cursor)
+		if remaining <= available {
+			return cursor.Add(remaining)
+		}
+		remaining -= available
+		cursor = dayStart.AddDate(0, 0, 1)
+	}
+	return start.Add(time.Duration(p.DueMinutes(priority)) * time.Minute)
+}
+
+func (p ReviewSLAPolicy) location() *time.Location {
+	if loc, err := time.LoadLocation(p.Timezone); err == nil {
+		return loc
+	}
+	// Hosts without tzdata still get the exchange's fixed UTC+8 clock for
+	// the default zone; Normalized rejects any other zone they cannot load.
+	return time.FixedZone("CST", 8*60*60)
+}
+
+func parseReviewSLAClock(value string) (int, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return parsed.Hour()*60 + parsed.Minute(), true
+}
+
+// ResolvedStatus is the final status of an item resolved at resolvedAt.
+func (i ReviewSLAItem) ResolvedStatus(resolvedAt time.Time) string {
+	due, err := time.Parse(time.RFC3339, i.DueAt)
+	if i.Status == ReviewSLAStatusBreached || (err == nil && resolvedAt.After(due)) {
+		return ReviewSLAStatusMissed
+	}
+	return ReviewSLAStatusMet
+}
+
+// ReviewerID is the reviewer an item counts towards: the current assignee
+// while it is open, whoever held it at breach time once it is MISSED, and
+// whoever resolved it otherwise. A backup reviewer who picks up an escalated
+// item is not charged for the miss.
+func (i ReviewSLAItem) ReviewerID() string {
+	switch i.Status {
+	case ReviewSLAStatusOpen, ReviewSLAStatusBreached:
+		return i.AssigneeID
+	case ReviewSLAStatusMissed:
+		if i.BreachedAssigneeID != "" {
+			return i.BreachedAssigneeID
+		}
+	}
+	if i.ResolvedBy != "" {
+		return i.ResolvedBy
+	}
+	return i.AssigneeID
+}
+
+// BuildReviewSLAMetrics totals items into overall and per-reviewer SLA
+// compliance, each item counting towards its ReviewerID.
+func BuildReviewSLAMetrics(items []ReviewSLAItem) ReviewSLAMetrics {
+	result := ReviewSLAMetrics{Reviewers: []ReviewerSLAStat{}}
+	stats := map[string]*ReviewerSLAStat{}
+	resolveMinutes := map[string]float64{}
+	statFor := func(reviewerID string) *ReviewerSLAStat {
+		reviewerID = strings.TrimSpace(reviewerID)
+		if reviewerID == "" {
+			reviewerID = "UNASSIGNED"
+		}
+		stat, ok := stats[reviewerID]
+		if !ok {
+			stat = &ReviewerSLAStat{ReviewerID: reviewerID}
+			stats[reviewerID] = stat
+		}
+		return stat
+	}
+	for _, item := range items {
+		switch item.Status {
+		case ReviewSLAStatusOpen, ReviewSLAStatusBreached:
+			stat := statFor(item.ReviewerID())
+			stat.OpenItems++
+			result.Open++
+			if item.Status == ReviewSLAStatusBreached {
+				stat.OpenBreached++
+				result.OpenBreached++
+			}
+		case ReviewSLAStatusMet, ReviewSLAStatusMissed:
+			stat := statFor(item.ReviewerID())
+			stat.Resolved++
+			result.Resolved++
+			if item.Status == ReviewSLAStatusMet {
+				stat.Met++
+				result.Met++
+			} else {
+				stat.Missed++
+				result.Missed++
+			}
+			started, errStart := time.Parse(time.RFC3339, item.StartedAt)
+			resolved, errResolved := time.Parse(time.RFC3339, item.ResolvedAt)
+			if errStart == nil && errResolved == nil && resolved.After(started) {
+				resolveMinutes[stat.ReviewerID] += resolved.Sub(started).Minutes()
+			}
+		}
+	}
+	if result.Resolved > 0 {
+		result.ComplianceRate = float64(result.Met) / float64(result.Resolved)
+	}
+	for reviewerID, stat := range stats {
+		if stat.Resolved > 0 {
+			stat.ComplianceRate = float64(stat.Met) / float64(stat.Resolved)
+			stat.AvgResolveMinutes = resolveMinutes[reviewerID] / float64(stat.Resolved)
+		}
+		result.Reviewers = append(result.Reviewers, *stat)
+	}
+	sort.Slice(result.Reviewers, func(i, j int) bool {
+		return result.Reviewers[i].ReviewerID < result.Reviewers[j].ReviewerID
+	})
+	return result
+}
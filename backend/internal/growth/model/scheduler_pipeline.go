@@ -0,0 +1,202 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrSchedulerPipelineNotFound    = errors.New("scheduler pipeline not found")
+	ErrSchedulerPipelineInvalid     = errors.New("invalid scheduler pipeline")
+	ErrSchedulerPipelineRunNotFound = errors.New("scheduler pipeline run not found")
+	ErrSchedulerPipelineRunBusy     = errors.New("scheduler pipeline run is already running")
+)
+
+const (
+	SchedulerPipelineStatusActive   = "ACTIVE"
+	SchedulerPipelineStatusDisabled = "DISABLED"
+
+	SchedulerPipelineRunRunning = "RUNNING"
+	SchedulerPipelineRunSuccess = "SUCCESS"
+	SchedulerPipelineRunFailed  = "FAILED"
+
+	SchedulerPipelineNodePending = "PENDING"
+	SchedulerPipelineNodeRunning = "RUNNING"
+	SchedulerPipelineNodeSuccess = "SUCCESS"
+	SchedulerPipelineNodeFailed  = "FAILED"
+	SchedulerPipelineNodeBlocked = "BLOCKED"
+)
+
+// SchedulerPipelineNode is one scheduler job inside a pipeline. It runs for a
+// trade date only after every job in DependsOn succeeded for that date.
+type SchedulerPipelineNode struct {
+	JobName   string   `json:"job_name"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+type SchedulerPipeline struct {
+	ID          string                  `json:"id"`
+	PipelineKey string                  `json:"pipeline_key"`
+	DisplayName string                  `json:"display_name"`
+	Status      string                  `json:"status"`
+	Nodes       []SchedulerPipelineNode `json:"nodes"`
+	UpdatedBy   string                  `json:"updated_by,omitempty"`
+	CreatedAt   string                  `json:"created_at,omitempty"`
+	UpdatedAt   string                  `json:"updated_at,omitempty"`
+}
+
+// SchedulerPipelineRun is one pipeline execution for a trade date. Nodes keep
+// the dependency graph the run was created with, in topological order.
+type SchedulerPipelineRun struct {
+	ID            string                     `json:"id"`
+	PipelineKey   string                     `json:"pipeline_key"`
+	TradeDate     string                     `json:"trade_date"`
+	Status        string                     `json:"status"`
+	TriggerSource string                     `json:"trigger_source"`
+	OperatorID    string                     `json:"operator_id,omitempty"`
+	StartedAt     string                     `json:"started_at"`
+	FinishedAt    string                     `json:"finished_at,omitempty"`
+	Nodes         []SchedulerPipelineNodeRun `json:"nodes,omitempty"`
+}
+
+type SchedulerPipelineNodeRun struct {
+	ID            string   `json:"id"`
+	RunID         string   `json:"run_id"`
+	Seq           int      `json:"seq"`
+	JobName       string   `json:"job_name"`
+	DependsOn     []string `json:"depends_on,omitempty"`
+	Status        string   `json:"status"`
+	Attempt       int      `json:"attempt"`
+	JobRunID      string   `json:"job_run_id,omitempty"`
+	BlockedBy     string   `json:"blocked_by,omitempty"`
+	ResultSummary string   `json:"result_summary,omitempty"`
+	ErrorMessage  string   `json:"error_message,omitempty"`
+	StartedAt     string   `json:"started_at,omitempty"`
+	FinishedAt    string   `json:"finished_at,omitempty"`
+}
+
+// Normalized trims and lowercases job names, drops duplicate dependencies and
+// orders nodes so every node comes after its upstreams. It rejects unknown
+// upstreams, duplicate nodes and cycles with ErrSchedulerPipelineInvalid.
+func (p SchedulerPipeline) Normalized() (SchedulerPipeline, error) {
+	p.PipelineKey = strings.ToLower(strings.TrimSpace(p.PipelineKey))
+	p.DisplayName = strings.TrimSpace(p.DisplayName)
+	p.Status = strings.ToUpper(strings.TrimSpace(p.Status))
+	if p.Status == "" {
+		p.Status = SchedulerPipelineStatusActive
+	}
+	if p.PipelineKey == "" {
+		return p, fmt.Errorf("%w: pipeline_key is required", ErrSchedulerPipelineInvalid)
+	}
+	if p.Status != SchedulerPipelineStatusActive && p.Status != SchedulerPipelineStatusDisabled {
+		return p, fmt.Errorf("%w: unsupported status %s", ErrSchedulerPipelineInvalid, p.Status)
+	}
+	if len(p.Nodes) == 0 {
+		return p, fmt.Errorf("%w: at least one node is required", ErrSchedulerPipelineInvalid)
+	}
+
+	nodes := make([]SchedulerPipelineNode, 0, len(p.Nodes))
+	index := map[string]int{}
+	for _, node := range p.Nodes {
+		name := strings.ToLower(strings.TrimSpace(node.JobName))
+		if name == "" {
+			return p, fmt.Errorf("%w: job_name is required", ErrSchedulerPipelineInvalid)
+		}
+		if _, exists := index[name]; exists {
+			return p, fmt.Errorf("%w: duplicate node %s", ErrSchedulerPipelineInvalid, name)
+		}
+		index[name] = len(nodes)
+		nodes = append(nodes, SchedulerPipelineNode{JobName: name, DependsOn: normalizeSchedulerJobNames(node.DependsOn)})
+	}
+
+	indegree := make([]int, len(nodes))
+	children := make([][]int, len(nodes))
+	for i, node := range nodes {
+		for _, upstream := range node.DependsOn {
+			j, ok := index[upstream]
+			if !ok {
+				return p, fmt.Errorf("%w: %s depends on unknown node %s", ErrSchedulerPipelineInvalid, node.JobName, upstream)
+			}
+			if j == i {
+				return p, fmt.Errorf("%w: %s depends on itself", ErrSchedulerPipelineInvalid, node.JobName)
+			}
+			indegree[i]++
+			children[j] = append(children[j], i)
+		}
+	}
+	// Kahn's algorithm; ties keep the submitted order so the stored order is
+	// stable across saves.
+	ready := make([]int, 0, len(nodes))
+	for i := range nodes {
+		if indegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	ordered := make([]SchedulerPipelineNode, 0, len(nodes))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, nodes[i])
+		for _, child := range children[i] {
+			indegree[child]--
+			if indegree[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	if len(ordered) != len(nodes) {
+		return p, fmt.Errorf("%w: dependency cycle detected", ErrSchedulerPipelineInvalid)
+	}
+	p.Nodes = ordered
+	return p, nil
+}
+
+// Downstream lists jobName and every node that transitively depends on it,
+// which is what a rerun of jobName has to reset.
+func (r SchedulerPipelineRun) Downstream(jobName string) []string {
+	dependents := map[string][]string{}
+	for _, node := range r.Nodes {
+		for _, upstream := range node.DependsOn {
+			dependents[upstream] = append(dependents[upstream], node.JobName)
+		}
+	}
+	jobName = strings.ToLower(strings.TrimSpace(jobName))
+	return append([]string{jobName}, walkSchedulerPipeline(dependents, jobName)...)
+}
+
+func walkSchedulerPipeline(edges map[string][]string, start string) []string {
+	seen := map[string]struct{}{start: {}}
+	result := make([]string, 0)
+	queue := append([]string(nil), edges[start]...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result = append(result, name)
+		queue = append(queue, edges[name]...)
+	}
+	return result
+}
+
+func normalizeSchedulerJobNames(items []string) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		name := strings.ToLower(strings.TrimSpace(item))
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result = append(result, name)
+	}
+	return result
+}
@@ -133,6 +133,18 @@ type StrategyEngineStockSeed struct {
 	Sector           string   `json:"sector,omitempty"`
 	ThemeTags        []string `json:"theme_tags,omitempty"`
 	RiskFlags        []string `json:"risk_flags,omitempty"`
+	// CrowdSentiment is nil when the community has no recent view on the
+	// symbol; strategy profiles decide whether to use it.
+	CrowdSentiment *StrategyEngineCrowdSentiment `json:"crowd_sentiment,omitempty"`
+}
+
+type StrategyEngineCrowdSentiment struct {
+	Score       float64 `json:"score"`
+	TopicCount  int     `json:"topic_count"`
+	AuthorCount int     `json:"author_count"`
+	Extreme     string  `json:"extreme,omitempty"`
+	StatDate    string  `json:"stat_date"`
+	WindowDays  int     `json:"window_days"`
 }
 
 type StrategyEngineStockSelectionContextMeta struct {
@@ -200,6 +212,9 @@ type StrategyEngineFuturesSeed struct {
 	SpreadPair                 string  `json:"spread_pair"`
 	NewsBias                   float64 `json:"news_bias"`
 	Regime                     string  `json:"regime"`
+	// CrowdSentiment is keyed by product, so every contract of a product
+	// shares the same reading.
+	CrowdSentiment *StrategyEngineCrowdSentiment `json:"crowd_sentiment,omitempty"`
 }
 
 type StrategyEngineFuturesStrategyContextMeta struct {
@@ -15,7 +15,7 @@ func (r *InMemoryGrowthRepo) AdminCreateAuditEvent(item model.AdminAuditEvent) e
 	nowText := now.Format(time.RFC3339)
 
 	var (
-		stored     model.AdminAuditEvent
+		stored      model.AdminAuditEvent
 		shouldRoute bool
 	)
 
@@ -32,6 +32,7 @@ func (r *InMemoryGrowthRepo) AdminCreateAuditEvent(item model.AdminAuditEvent) e
 			item.CreatedAt = existing.CreatedAt
 			stored = mergeOpenAuditEvent(existing, item, now)
 			r.adminAuditEvents[id] = stored
+			r.appendAuditLedgerEntryLocked(auditLedgerSourceAuditEvent, stored.ID, auditLedgerActionUpdate, inMemoryAuditEventLedgerPayload(stored), now)
 			r.mu.Unlock()
 			return nil
 		}
@@ -45,6 +46,7 @@ func (r *InMemoryGrowthRepo) AdminCreateAuditEvent(item model.AdminAuditEvent) e
 	}
 	stored = item
 	r.adminAuditEvents[item.ID] = stored
+	r.appendAuditLedgerEntryLocked(auditLedgerSourceAuditEvent, stored.ID, auditLedgerActionCreate, inMemoryAuditEventLedgerPayload(stored), now)
 	shouldRoute = true
 	r.mu.Unlock()
 
@@ -269,7 +269,12 @@ func (r *MySQLGrowthRepo) updateOpenAuditEvent(existing model.AdminAuditEvent, i
 	now := time.Now()
 	merged := mergeOpenAuditEvent(existing, incoming, now)
 	metadataText := marshalAdminAuditEventMetadata(merged.Metadata)
-	_, err := r.db.Exec(`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`
 UPDATE admin_audit_events
 SET level = ?, actor_user_id = ?, title = ?, summary = ?, detail = ?, metadata_json = ?
 WHERE id = ?`,
@@ -280,8 +285,13 @@ WHERE id = ?`,
 		nullableString(merged.Detail),
 		nullableString(metadataText),
 		existing.ID,
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	if err := appendAuditLedgerEntry(tx, auditLedgerSourceAuditEvent, existing.ID, auditLedgerActionUpdate); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *MySQLGrowthRepo) AdminCreateAuditEvent(item model.AdminAuditEvent) error {
@@ -299,10 +309,16 @@ func (r *MySQLGrowthRepo) AdminCreateAuditEvent(item model.AdminAuditEvent) erro
 		}
 	}
 	metadataText := marshalAdminAuditEventMetadata(item.Metadata)
-	_, err := r.db.Exec(`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	id := newID("ae")
+	if _, err := tx.Exec(`
 INSERT INTO admin_audit_events (id, event_domain, event_type, level, module, object_type, object_id, actor_user_id, title, summary, detail, status, metadata_json, dedupe_key, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		newID("ae"),
+		id,
 		item.EventDomain,
 		item.EventType,
 		item.Level,
@@ -317,11 +333,17 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		nullableString(metadataText),
 		item.DedupeKey,
 		time.Now(),
-	)
-	if err == nil {
-		r.routeAuditEventWorkflowMessage(item)
+	); err != nil {
+		return err
+	}
+	if err := appendAuditLedgerEntry(tx, auditLedgerSourceAuditEvent, id, auditLedgerActionCreate); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
 	}
-	return err
+	r.routeAuditEventWorkflowMessage(item)
+	return nil
 }
 
 func (r *MySQLGrowthRepo) AdminListAuditEvents(filter model.AdminAuditEventFilter, page int, pageSize int) ([]model.AdminAuditEvent, int, error) {
@@ -125,6 +125,7 @@ LIMIT 1`)).
 			"id", "event_domain", "event_type", "level", "module", "object_type", "object_id", "actor_user_id",
 			"title", "summary", "detail", "status", "metadata_json", "created_at",
 		}))
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(`
 INSERT INTO admin_audit_events (id, event_domain, event_type, level, module, object_type, object_id, actor_user_id, title, summary, detail, status, metadata_json, dedupe_key, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)).
@@ -146,6 +147,8 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)).
 			sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAuditLedgerAppend(mock, auditLedgerSourceAuditEvent, sqlmock.AnyArg(), auditLedgerActionCreate)
+	mock.ExpectCommit()
 
 	err = repo.persistStrategyConfig(
 		strategyPublishPolicyConfigPrefix+"policy_default_all",
@@ -378,6 +381,7 @@ LIMIT 1`)).
 			"ae_001", "DATA", "DATA_SOURCE_UNHEALTHY", "WARNING", "SYSTEM", "DATA_SOURCE", "TUSHARE", "system",
 			"数据源健康告警", "首次失败", "", "OPEN", `{"receiver_id":"ops_admin"}`, "2026-03-24T09:00:00Z",
 		))
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(`
 UPDATE admin_audit_events
 SET level = ?, actor_user_id = ?, title = ?, summary = ?, detail = ?, metadata_json = ?
@@ -392,6 +396,8 @@ WHERE id = ?`)).
 			"ae_001",
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAuditLedgerAppend(mock, auditLedgerSourceAuditEvent, "ae_001", auditLedgerActionUpdate)
+	mock.ExpectCommit()
 
 	err = repo.AdminCreateAuditEvent(model.AdminAuditEvent{
 		EventDomain: "DATA",
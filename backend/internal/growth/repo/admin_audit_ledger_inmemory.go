@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+var inMemoryAuditLedgerSigner = newAuditLedgerSigner("")
+
+func inMemoryAuditEventLedgerPayload(item model.AdminAuditEvent) string {
+	return marshalAuditLedgerPayload(auditLedgerAuditEventPayload{
+		ID:           item.ID,
+		EventDomain:  item.EventDomain,
+		EventType:    item.EventType,
+		Level:        item.Level,
+		Module:       item.Module,
+		ObjectType:   item.ObjectType,
+		ObjectID:     item.ObjectID,
+		ActorUserID:  item.ActorUserID,
+		Title:        item.Title,
+		Summary:      item.Summary,
+		Detail:       item.Detail,
+		Status:       item.Status,
+		MetadataJSON: marshalJSONSilently(item.Metadata),
+		DedupeKey:    item.DedupeKey,
+		CreatedAt:    item.CreatedAt,
+	})
+}
+
+// appendAuditLedgerEntryLocked expects r.mu to be held by the caller.
+func (r *InMemoryGrowthRepo) appendAuditLedgerEntryLocked(sourceType string, sourceID string, action string, payload string, now time.Time) {
+	var prevSeq int64
+	prevHash := auditLedgerGenesisHash
+	if len(r.auditLedger) > 0 {
+		last := r.auditLedger[len(r.auditLedger)-1]
+		prevSeq = last.Seq
+		prevHash = last.EntryHash
+	}
+	r.auditLedger = append(r.auditLedger, buildAuditLedgerEntry(prevSeq, prevHash, sourceType, sourceID, action, payload, now))
+}
+
+func (r *InMemoryGrowthRepo) AdminVerifyAuditLedger() (model.AdminAuditLedgerVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	verifier := newAuditLedgerChainVerifier(inMemoryAuditLedgerSigner, r.auditCheckpoints)
+	for _, entry := range r.auditLedger {
+		verifier.checkEntry(entry)
+	}
+	for _, item := range r.adminAuditEvents {
+		createdAt, _ := time.Parse(time.RFC3339, item.CreatedAt)
+		verifier.checkSource(auditLedgerSourceAuditEvent, item.ID, inMemoryAuditEventLedgerPayload(item), createdAt)
+	}
+	var headSeq int64
+	headHash := auditLedgerGenesisHash
+	if len(r.auditLedger) > 0 {
+		headSeq = r.auditLedger[len(r.auditLedger)-1].Seq
+		headHash = r.auditLedger[len(r.auditLedger)-1].EntryHash
+	}
+	return verifier.finish(headSeq, headHash, time.Now()), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCreateAuditCheckpoint(operator string) (model.AdminAuditCheckpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.auditLedger) == 0 {
+		return model.AdminAuditCheckpoint{}, fmt.Errorf("audit ledger is empty")
+	}
+	head := r.auditLedger[len(r.auditLedger)-1]
+	for _, existing := range r.auditCheckpoints {
+		if existing.Seq == head.Seq {
+			return existing, nil
+		}
+	}
+	signer := inMemoryAuditLedgerSigner
+	item := model.AdminAuditCheckpoint{
+		ID:         fmt.Sprintf("acp_%03d", len(r.auditCheckpoints)+1),
+		Seq:        head.Seq,
+		EntryHash:  head.EntryHash,
+		EntryCount: head.Seq,
+		Algorithm:  auditLedgerSignatureAlg,
+		KeyID:      signer.keyID,
+		PublicKey:  signer.publicKeyText(),
+		CreatedBy:  firstNonEmpty(strings.TrimSpace(operator), "system"),
+		CreatedAt:  time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+	}
+	item.Signature = signer.sign(auditCheckpointMessage(item))
+	r.auditCheckpoints = append(r.auditCheckpoints, item)
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListAuditCheckpoints(page int, pageSize int) ([]model.AdminAuditCheckpoint, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := append([]model.AdminAuditCheckpoint(nil), r.auditCheckpoints...)
+	sort.Slice(items, func(i, j int) bool { return items[i].Seq > items[j].Seq })
+	total := len(items)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	start := (page - 1) * pageSize
+	if start >= total {
+		return []model.AdminAuditCheckpoint{}, total, nil
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminExportAuditLedger(startDate string, endDate string, operator string) (model.AdminAuditLedgerExport, error) {
+	start, end, err := parseAuditLedgerExportRange(startDate, endDate)
+	if err != nil {
+		return model.AdminAuditLedgerExport{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	until := end.AddDate(0, 0, 1)
+	entries := make([]model.AdminAuditLedgerEntry, 0)
+	for _, entry := range r.auditLedger {
+		createdAt, err := time.Parse(time.RFC3339, entry.CreatedAt)
+		if err != nil || createdAt.Before(start) || !createdAt.Before(until) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	checkpoints := make([]model.AdminAuditCheckpoint, 0)
+	if len(entries) > 0 {
+		for _, checkpoint := range r.auditCheckpoints {
+			if checkpoint.Seq >= entries[0].Seq && checkpoint.Seq <= entries[len(entries)-1].Seq {
+				checkpoints = append(checkpoints, checkpoint)
+			}
+		}
+	}
+	return buildAuditLedgerManifest(
+		entries,
+		checkpoints,
+		start.Format("2006-01-02"),
+		end.Format("2006-01-02"),
+		strings.TrimSpace(operator),
+		inMemoryAuditLedgerSigner,
+		time.Now(),
+	), nil
+}
@@ -0,0 +1,703 @@
+package repo
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/config"
+)
+
+const (
+	auditLedgerSourceOperationLog = "OPERATION_LOG"
+	auditLedgerSourceAuditEvent   = "AUDIT_EVENT"
+
+	auditLedgerActionCreate = "CREATE"
+	auditLedgerActionUpdate = "UPDATE"
+
+	auditLedgerHeadID       = "HEAD"
+	auditLedgerVerifyBatch  = 500
+	auditLedgerExportLimit  = 200000
+	auditLedgerSignatureAlg = "ED25519"
+)
+
+var auditLedgerGenesisHash = strings.Repeat("0", 64)
+
+const (
+	auditLedgerPayloadRule   = "payload_hash = sha256_hex(payload_json)"
+	auditLedgerEntryHashRule = "entry_hash = sha256_hex(prev_hash|seq|source_type|source_id|action|payload_hash); the first entry uses 64 zeros as prev_hash"
+	auditLedgerExportSigRule = "signature = ed25519(base64) over sercherai-audit-export|first_seq|last_seq|anchor_prev_hash|last_entry_hash|entries_sha256"
+)
+
+// auditLedgerQueryer is satisfied by both *sql.DB and *sql.Tx so payload snapshots
+// can be read inside the writing transaction and again during verification.
+type auditLedgerQueryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type auditLedgerOperationLogPayload struct {
+	ID             string `json:"id"`
+	Module         string `json:"module"`
+	Action         string `json:"action"`
+	TargetType     string `json:"target_type"`
+	TargetID       string `json:"target_id"`
+	OperatorUserID string `json:"operator_user_id"`
+	BeforeValue    string `json:"before_value"`
+	AfterValue     string `json:"after_value"`
+	Reason         string `json:"reason"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type auditLedgerAuditEventPayload struct {
+	ID           string `json:"id"`
+	EventDomain  string `json:"event_domain"`
+	EventType    string `json:"event_type"`
+	Level        string `json:"level"`
+	Module       string `json:"module"`
+	ObjectType   string `json:"object_type"`
+	ObjectID     string `json:"object_id"`
+	ActorUserID  string `json:"actor_user_id"`
+	Title        string `json:"title"`
+	Summary      string `json:"summary"`
+	Detail       string `json:"detail"`
+	Status       string `json:"status"`
+	MetadataJSON string `json:"metadata_json"`
+	DedupeKey    string `json:"dedupe_key"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type auditLedgerSigner struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	keyID      string
+}
+
+// auditSigningSecret picks the secret that seeds the audit ledger key. The JWT
+// secret is only a fallback for setups that predate AUDIT_SIGNING_SECRET:
+// rotating it would invalidate every published checkpoint, so the router warns
+// at startup whenever the fallback is in use.
+func auditSigningSecret(cfg config.Config) string {
+	if secret := strings.TrimSpace(cfg.AuditSigningSecret); secret != "" {
+		return secret
+	}
+	return strings.TrimSpace(cfg.JWTSecret)
+}
+
+// newAuditLedgerSigner derives a deterministic ed25519 key from the configured
+// secret, so checkpoints stay verifiable across restarts while auditors only need
+// the published public key.
+func newAuditLedgerSigner(secret string) *auditLedgerSigner {
+	seed := sha256.Sum256([]byte("sercherai.audit.ledger|" + secret))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	keySum := sha256.Sum256(publicKey)
+	return &auditLedgerSigner{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		keyID:      hex.EncodeToString(keySum[:])[:16],
+	}
+}
+
+func (s *auditLedgerSigner) sign(message string) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, []byte(message)))
+}
+
+func (s *auditLedgerSigner) verify(message string, signature string) bool {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(s.publicKey, []byte(message), raw)
+}
+
+func (s *auditLedgerSigner) publicKeyText() string {
+	return base64.StdEncoding.EncodeToString(s.publicKey)
+}
+
+func (r *MySQLGrowthRepo) resolveAuditLedgerSigner() *auditLedgerSigner {
+	if r.auditSigner != nil {
+		return r.auditSigner
+	}
+	return newAuditLedgerSigner("")
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func computeAuditLedgerEntryHash(prevHash string, seq int64, sourceType string, sourceID string, action string, payloadHash string) string {
+	return sha256Hex(fmt.Sprintf("%s|%d|%s|%s|%s|%s", prevHash, seq, sourceType, sourceID, action, payloadHash))
+}
+
+func buildAuditLedgerEntry(prevSeq int64, prevHash string, sourceType string, sourceID string, action string, payload string, now time.Time) model.AdminAuditLedgerEntry {
+	if strings.TrimSpace(prevHash) == "" {
+		prevHash = auditLedgerGenesisHash
+	}
+	entry := model.AdminAuditLedgerEntry{
+		Seq:         prevSeq + 1,
+		SourceType:  sourceType,
+		SourceID:    sourceID,
+		Action:      action,
+		PayloadJSON: payload,
+		PayloadHash: sha256Hex(payload),
+		PrevHash:    prevHash,
+		CreatedAt:   now.Format(time.RFC3339),
+	}
+	entry.EntryHash = computeAuditLedgerEntryHash(entry.PrevHash, entry.Seq, entry.SourceType, entry.SourceID, entry.Action, entry.PayloadHash)
+	return entry
+}
+
+func auditCheckpointMessage(item model.AdminAuditCheckpoint) string {
+	return fmt.Sprintf("sercherai-audit-checkpoint|%d|%s|%d|%s", item.Seq, item.EntryHash, item.EntryCount, item.CreatedAt)
+}
+
+func auditExportMessage(manifest model.AdminAuditLedgerManifest) string {
+	return fmt.Sprintf(
+		"sercherai-audit-export|%d|%d|%s|%s|%s",
+		manifest.FirstSeq,
+		manifest.LastSeq,
+		manifest.AnchorPrevHash,
+		manifest.LastEntryHash,
+		manifest.EntriesSHA256,
+	)
+}
+
+func marshalAuditLedgerPayload(payload any) string {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	return string(body)
+}
+
+func auditLedgerSourceKey(sourceType string, sourceID string) string {
+	return sourceType + "|" + sourceID
+}
+
+func encodeAuditLedgerJSONLines(entries []model.AdminAuditLedgerEntry) []byte {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		body, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		buf.Write(body)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func buildAuditLedgerManifest(entries []model.AdminAuditLedgerEntry, checkpoints []model.AdminAuditCheckpoint, startDate string, endDate string, operator string, signer *auditLedgerSigner, now time.Time) model.AdminAuditLedgerExport {
+	body := encodeAuditLedgerJSONLines(entries)
+	sum := sha256.Sum256(body)
+	manifest := model.AdminAuditLedgerManifest{
+		Format:         "jsonl",
+		HashAlgorithm:  "SHA-256",
+		PayloadRule:    auditLedgerPayloadRule,
+		EntryHashRule:  auditLedgerEntryHashRule,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		EntryCount:     len(entries),
+		EntriesSHA256:  hex.EncodeToString(sum[:]),
+		Checkpoints:    checkpoints,
+		SignatureRule:  auditLedgerExportSigRule,
+		Algorithm:      auditLedgerSignatureAlg,
+		KeyID:          signer.keyID,
+		PublicKey:      signer.publicKeyText(),
+		GeneratedAt:    now.Format(time.RFC3339),
+		GeneratedBy:    operator,
+		AnchorPrevHash: auditLedgerGenesisHash,
+	}
+	if manifest.Checkpoints == nil {
+		manifest.Checkpoints = []model.AdminAuditCheckpoint{}
+	}
+	if len(entries) > 0 {
+		manifest.FirstSeq = entries[0].Seq
+		manifest.LastSeq = entries[len(entries)-1].Seq
+		manifest.AnchorPrevHash = entries[0].PrevHash
+		manifest.LastEntryHash = entries[len(entries)-1].EntryHash
+	}
+	manifest.Signature = signer.sign(auditExportMessage(manifest))
+	return model.AdminAuditLedgerExport{Entries: entries, EntriesJSONL: body, Manifest: manifest}
+}
+
+// auditLedgerChainVerifier walks entries in seq order and collects every break it
+// can prove: missing sequence numbers, relinked or edited entries, checkpoints
+// that no longer match, and source rows whose current content differs from the
+// last chained snapshot.
+type auditLedgerChainVerifier struct {
+	signer      *auditLedgerSigner
+	checkpoints map[int64]model.AdminAuditCheckpoint
+	latest      map[string]string
+	prevSeq     int64
+	prevHash    string
+	genesisAt   string
+	result      model.AdminAuditLedgerVerification
+}
+
+func newAuditLedgerChainVerifier(signer *auditLedgerSigner, checkpoints []model.AdminAuditCheckpoint) *auditLedgerChainVerifier {
+	verifier := &auditLedgerChainVerifier{
+		signer:      signer,
+		checkpoints: make(map[int64]model.AdminAuditCheckpoint, len(checkpoints)),
+		latest:      make(map[string]string),
+		prevHash:    auditLedgerGenesisHash,
+		result: model.AdminAuditLedgerVerification{
+			Issues: make([]model.AdminAuditLedgerIssue, 0),
+		},
+	}
+	for _, checkpoint := range checkpoints {
+		verifier.checkpoints[checkpoint.Seq] = checkpoint
+		verifier.result.CheckedCheckpoints++
+		if !signer.verify(auditCheckpointMessage(checkpoint), checkpoint.Signature) {
+			verifier.addIssue(model.AdminAuditLedgerIssue{Code: "CHECKPOINT_SIGNATURE_INVALID", Seq: checkpoint.Seq, Message: "checkpoint " + checkpoint.ID + " signature does not verify"})
+		}
+	}
+	return verifier
+}
+
+func (v *auditLedgerChainVerifier) addIssue(issue model.AdminAuditLedgerIssue) {
+	v.result.Issues = append(v.result.Issues, issue)
+}
+
+func (v *auditLedgerChainVerifier) checkEntry(entry model.AdminAuditLedgerEntry) {
+	if v.result.CheckedEntries == 0 {
+		v.result.FirstSeq = entry.Seq
+		v.genesisAt = entry.CreatedAt
+		if entry.Seq != 1 {
+			v.addIssue(model.AdminAuditLedgerIssue{Code: "SEQ_GAP", Seq: entry.Seq, Message: fmt.Sprintf("ledger starts at seq %d instead of 1", entry.Seq)})
+		}
+	} else if entry.Seq != v.prevSeq+1 {
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "SEQ_GAP", Seq: entry.Seq, Message: fmt.Sprintf("missing seq %d-%d", v.prevSeq+1, entry.Seq-1)})
+	}
+	if entry.Seq == 1 || v.result.CheckedEntries > 0 {
+		if entry.PrevHash != v.prevHash {
+			v.addIssue(model.AdminAuditLedgerIssue{Code: "PREV_HASH_MISMATCH", Seq: entry.Seq, SourceType: entry.SourceType, SourceID: entry.SourceID, Message: "prev_hash does not link to the previous entry"})
+		}
+	}
+	if sha256Hex(entry.PayloadJSON) != entry.PayloadHash {
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "PAYLOAD_HASH_MISMATCH", Seq: entry.Seq, SourceType: entry.SourceType, SourceID: entry.SourceID, Message: "payload_json was modified after chaining"})
+	}
+	expected := computeAuditLedgerEntryHash(entry.PrevHash, entry.Seq, entry.SourceType, entry.SourceID, entry.Action, entry.PayloadHash)
+	if expected != entry.EntryHash {
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "ENTRY_HASH_MISMATCH", Seq: entry.Seq, SourceType: entry.SourceType, SourceID: entry.SourceID, Message: "entry_hash does not match entry content"})
+	}
+	if checkpoint, ok := v.checkpoints[entry.Seq]; ok && checkpoint.EntryHash != entry.EntryHash {
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "CHECKPOINT_MISMATCH", Seq: entry.Seq, Message: "entry differs from signed checkpoint " + checkpoint.ID})
+	}
+	v.latest[auditLedgerSourceKey(entry.SourceType, entry.SourceID)] = entry.PayloadHash
+	v.prevSeq = entry.Seq
+	v.prevHash = entry.EntryHash
+	v.result.CheckedEntries++
+}
+
+// checkSource compares a live source row with its last chained snapshot. Rows
+// created before the ledger existed are counted as legacy rather than flagged.
+func (v *auditLedgerChainVerifier) checkSource(sourceType string, sourceID string, payload string, createdAt time.Time) {
+	v.result.CheckedSources++
+	key := auditLedgerSourceKey(sourceType, sourceID)
+	chainedHash, ok := v.latest[key]
+	if !ok {
+		genesisAt, err := time.Parse(time.RFC3339, v.genesisAt)
+		if v.genesisAt == "" || (err == nil && createdAt.Before(genesisAt)) {
+			v.result.LegacySources++
+			return
+		}
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "SOURCE_UNCHAINED", SourceType: sourceType, SourceID: sourceID, Message: "record exists without a ledger entry"})
+		return
+	}
+	delete(v.latest, key)
+	if sha256Hex(payload) != chainedHash {
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "SOURCE_MODIFIED", SourceType: sourceType, SourceID: sourceID, Message: "record differs from its last chained snapshot"})
+	}
+}
+
+func (v *auditLedgerChainVerifier) finish(headSeq int64, headHash string, now time.Time) model.AdminAuditLedgerVerification {
+	for key := range v.latest {
+		parts := strings.SplitN(key, "|", 2)
+		issue := model.AdminAuditLedgerIssue{Code: "SOURCE_MISSING", Message: "chained record no longer exists"}
+		if len(parts) == 2 {
+			issue.SourceType = parts[0]
+			issue.SourceID = parts[1]
+		}
+		v.addIssue(issue)
+	}
+	for seq, checkpoint := range v.checkpoints {
+		if seq > v.prevSeq {
+			v.addIssue(model.AdminAuditLedgerIssue{Code: "CHECKPOINT_ORPHANED", Seq: seq, Message: "ledger ends before signed checkpoint " + checkpoint.ID})
+		}
+	}
+	if headSeq != v.prevSeq || (headSeq > 0 && headHash != v.prevHash) {
+		v.addIssue(model.AdminAuditLedgerIssue{Code: "HEAD_MISMATCH", Seq: headSeq, Message: fmt.Sprintf("ledger head points at seq %d but chain ends at %d", headSeq, v.prevSeq)})
+	}
+	v.result.LastSeq = v.prevSeq
+	v.result.HeadHash = v.prevHash
+	v.result.Valid = len(v.result.Issues) == 0
+	v.result.VerifiedAt = now.Format(time.RFC3339)
+	return v.result
+}
+
+func loadOperationLogLedgerPayload(q auditLedgerQueryer, id string) (string, time.Time, error) {
+	var payload auditLedgerOperationLogPayload
+	var createdAt time.Time
+	err := q.QueryRow(`
+SELECT id, module, action, target_type, target_id, operator_user_id, COALESCE(before_value, ''), COALESCE(after_value, ''), COALESCE(reason, ''), created_at
+FROM admin_operation_logs
+WHERE id = ?`, id).Scan(
+		&payload.ID, &payload.Module, &payload.Action, &payload.TargetType, &payload.TargetID, &payload.OperatorUserID,
+		&payload.BeforeValue, &payload.AfterValue, &payload.Reason, &createdAt,
+	)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	payload.CreatedAt = createdAt.Format(time.RFC3339)
+	return marshalAuditLedgerPayload(payload), createdAt, nil
+}
+
+func loadAuditEventLedgerPayload(q auditLedgerQueryer, id string) (string, time.Time, error) {
+	var payload auditLedgerAuditEventPayload
+	var createdAt time.Time
+	err := q.QueryRow(`
+SELECT id, event_domain, event_type, level, module, object_type, object_id, actor_user_id, title, summary, COALESCE(detail, ''), status, COALESCE(metadata_json, ''), dedupe_key, created_at
+FROM admin_audit_events
+WHERE id = ?`, id).Scan(
+		&payload.ID, &payload.EventDomain, &payload.EventType, &payload.Level, &payload.Module, &payload.ObjectType, &payload.ObjectID,
+		&payload.ActorUserID, &payload.Title, &payload.Summary, &payload.Detail, &payload.Status, &payload.MetadataJSON, &payload.DedupeKey, &createdAt,
+	)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	payload.CreatedAt = createdAt.Format(time.RFC3339)
+	return marshalAuditLedgerPayload(payload), createdAt, nil
+}
+
+// appendAuditLedgerEntry chains the current snapshot of a source row inside the
+// caller's transaction. The head row is locked so concurrent writers serialize
+// on seq assignment.
+func appendAuditLedgerEntry(tx *sql.Tx, sourceType string, sourceID string, action string) error {
+	var payload string
+	var err error
+	switch sourceType {
+	case auditLedgerSourceOperationLog:
+		payload, _, err = loadOperationLogLedgerPayload(tx, sourceID)
+	case auditLedgerSourceAuditEvent:
+		payload, _, err = loadAuditEventLedgerPayload(tx, sourceID)
+	default:
+		return fmt.Errorf("unsupported audit ledger source: %s", sourceType)
+	}
+	if err != nil {
+		return err
+	}
+
+	var headSeq int64
+	var headHash string
+	if err := tx.QueryRow(`
+SELECT last_seq, last_hash
+FROM admin_audit_ledger_head
+WHERE id = ?
+FOR UPDATE`, auditLedgerHeadID).Scan(&headSeq, &headHash); err != nil {
+		return err
+	}
+	now := time.Now()
+	entry := buildAuditLedgerEntry(headSeq, headHash, sourceType, sourceID, action, payload, now)
+	if _, err := tx.Exec(`
+INSERT INTO admin_audit_ledger (seq, source_type, source_id, action, payload_json, payload_hash, prev_hash, entry_hash, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Seq,
+		entry.SourceType,
+		entry.SourceID,
+		entry.Action,
+		entry.PayloadJSON,
+		entry.PayloadHash,
+		entry.PrevHash,
+		entry.EntryHash,
+		now,
+	); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+UPDATE admin_audit_ledger_head
+SET last_seq = ?, last_hash = ?, updated_at = ?
+WHERE id = ?`, entry.Seq, entry.EntryHash, now, auditLedgerHeadID)
+	return err
+}
+
+func scanAuditLedgerEntries(rows *sql.Rows) ([]model.AdminAuditLedgerEntry, error) {
+	defer rows.Close()
+	items := make([]model.AdminAuditLedgerEntry, 0)
+	for rows.Next() {
+		var item model.AdminAuditLedgerEntry
+		var createdAt time.Time
+		if err := rows.Scan(
+			&item.Seq, &item.SourceType, &item.SourceID, &item.Action, &item.PayloadJSON,
+			&item.PayloadHash, &item.PrevHash, &item.EntryHash, &createdAt,
+		); err != nil {
+			return nil, err
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) listAllAuditCheckpoints() ([]model.AdminAuditCheckpoint, error) {
+	rows, err := r.db.Query(`
+SELECT id, seq, entry_hash, entry_count, algorithm, key_id, public_key, signature, created_by, created_at
+FROM admin_audit_checkpoints
+ORDER BY seq ASC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanAuditCheckpoints(rows)
+}
+
+func scanAuditCheckpoints(rows *sql.Rows) ([]model.AdminAuditCheckpoint, error) {
+	defer rows.Close()
+	items := make([]model.AdminAuditCheckpoint, 0)
+	for rows.Next() {
+		var item model.AdminAuditCheckpoint
+		var createdAt time.Time
+		if err := rows.Scan(
+			&item.ID, &item.Seq, &item.EntryHash, &item.EntryCount, &item.Algorithm,
+			&item.KeyID, &item.PublicKey, &item.Signature, &item.CreatedBy, &createdAt,
+		); err != nil {
+			return nil, err
+		}
+		item.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminVerifyAuditLedger() (model.AdminAuditLedgerVerification, error) {
+	checkpoints, err := r.listAllAuditCheckpoints()
+	if err != nil {
+		return model.AdminAuditLedgerVerification{}, err
+	}
+	verifier := newAuditLedgerChainVerifier(r.resolveAuditLedgerSigner(), checkpoints)
+
+	var afterSeq int64
+	for {
+		rows, err := r.db.Query(`
+SELECT seq, source_type, source_id, action, payload_json, payload_hash, prev_hash, entry_hash, created_at
+FROM admin_audit_ledger
+WHERE seq > ?
+ORDER BY seq ASC
+LIMIT ?`, afterSeq, auditLedgerVerifyBatch)
+		if err != nil {
+			return model.AdminAuditLedgerVerification{}, err
+		}
+		entries, err := scanAuditLedgerEntries(rows)
+		if err != nil {
+			return model.AdminAuditLedgerVerification{}, err
+		}
+		for _, entry := range entries {
+			verifier.checkEntry(entry)
+			afterSeq = entry.Seq
+		}
+		if len(entries) < auditLedgerVerifyBatch {
+			break
+		}
+	}
+
+	if err := r.verifyAuditLedgerOperationLogs(verifier); err != nil {
+		return model.AdminAuditLedgerVerification{}, err
+	}
+	if err := r.verifyAuditLedgerAuditEvents(verifier); err != nil {
+		return model.AdminAuditLedgerVerification{}, err
+	}
+
+	var headSeq int64
+	var headHash string
+	if err := r.db.QueryRow("SELECT last_seq, last_hash FROM admin_audit_ledger_head WHERE id = ?", auditLedgerHeadID).Scan(&headSeq, &headHash); err != nil && err != sql.ErrNoRows {
+		return model.AdminAuditLedgerVerification{}, err
+	}
+	return verifier.finish(headSeq, headHash, time.Now()), nil
+}
+
+func (r *MySQLGrowthRepo) verifyAuditLedgerOperationLogs(verifier *auditLedgerChainVerifier) error {
+	rows, err := r.db.Query(`
+SELECT id, module, action, target_type, target_id, operator_user_id, COALESCE(before_value, ''), COALESCE(after_value, ''), COALESCE(reason, ''), created_at
+FROM admin_operation_logs`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var payload auditLedgerOperationLogPayload
+		var createdAt time.Time
+		if err := rows.Scan(
+			&payload.ID, &payload.Module, &payload.Action, &payload.TargetType, &payload.TargetID, &payload.OperatorUserID,
+			&payload.BeforeValue, &payload.AfterValue, &payload.Reason, &createdAt,
+		); err != nil {
+			return err
+		}
+		payload.CreatedAt = createdAt.Format(time.RFC3339)
+		verifier.checkSource(auditLedgerSourceOperationLog, payload.ID, marshalAuditLedgerPayload(payload), createdAt)
+	}
+	return rows.Err()
+}
+
+func (r *MySQLGrowthRepo) verifyAuditLedgerAuditEvents(verifier *auditLedgerChainVerifier) error {
+	rows, err := r.db.Query(`
+SELECT id, event_domain, event_type, level, module, object_type, object_id, actor_user_id, title, summary, COALESCE(detail, ''), status, COALESCE(metadata_json, ''), dedupe_key, created_at
+FROM admin_audit_events`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var payload auditLedgerAuditEventPayload
+		var createdAt time.Time
+		if err := rows.Scan(
+			&payload.ID, &payload.EventDomain, &payload.EventType, &payload.Level, &payload.Module, &payload.ObjectType, &payload.ObjectID,
+			&payload.ActorUserID, &payload.Title, &payload.Summary, &payload.Detail, &payload.Status, &payload.MetadataJSON, &payload.DedupeKey, &createdAt,
+		); err != nil {
+			return err
+		}
+		payload.CreatedAt = createdAt.Format(time.RFC3339)
+		verifier.checkSource(auditLedgerSourceAuditEvent, payload.ID, marshalAuditLedgerPayload(payload), createdAt)
+	}
+	return rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminCreateAuditCheckpoint(operator string) (model.AdminAuditCheckpoint, error) {
+	var headSeq int64
+	var headHash string
+	if err := r.db.QueryRow("SELECT last_seq, last_hash FROM admin_audit_ledger_head WHERE id = ?", auditLedgerHeadID).Scan(&headSeq, &headHash); err != nil {
+		return model.AdminAuditCheckpoint{}, err
+	}
+	if headSeq <= 0 {
+		return model.AdminAuditCheckpoint{}, fmt.Errorf("audit ledger is empty")
+	}
+	rows, err := r.db.Query(`
+SELECT id, seq, entry_hash, entry_count, algorithm, key_id, public_key, signature, created_by, created_at
+FROM admin_audit_checkpoints
+WHERE seq = ?
+LIMIT 1`, headSeq)
+	if err != nil {
+		return model.AdminAuditCheckpoint{}, err
+	}
+	existing, err := scanAuditCheckpoints(rows)
+	if err != nil {
+		return model.AdminAuditCheckpoint{}, err
+	}
+	if len(existing) > 0 {
+		return existing[0], nil
+	}
+
+	signer := r.resolveAuditLedgerSigner()
+	now := time.Now().UTC().Truncate(time.Second)
+	item := model.AdminAuditCheckpoint{
+		ID:         newID("acp"),
+		Seq:        headSeq,
+		EntryHash:  headHash,
+		EntryCount: headSeq,
+		Algorithm:  auditLedgerSignatureAlg,
+		KeyID:      signer.keyID,
+		PublicKey:  signer.publicKeyText(),
+		CreatedBy:  firstNonEmpty(strings.TrimSpace(operator), "system"),
+		CreatedAt:  now.Format(time.RFC3339),
+	}
+	item.Signature = signer.sign(auditCheckpointMessage(item))
+	_, err = r.db.Exec(`
+INSERT INTO admin_audit_checkpoints (id, seq, entry_hash, entry_count, algorithm, key_id, public_key, signature, created_by, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.ID, item.Seq, item.EntryHash, item.EntryCount, item.Algorithm, item.KeyID, item.PublicKey, item.Signature, item.CreatedBy, now,
+	)
+	if err != nil {
+		return model.AdminAuditCheckpoint{}, err
+	}
+	return item, nil
+}
+
+func (r *MySQLGrowthRepo) AdminListAuditCheckpoints(page int, pageSize int) ([]model.AdminAuditCheckpoint, int, error) {
+	offset := (page - 1) * pageSize
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM admin_audit_checkpoints").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`
+SELECT id, seq, entry_hash, entry_count, algorithm, key_id, public_key, signature, created_by, created_at
+FROM admin_audit_checkpoints
+ORDER BY seq DESC
+LIMIT ? OFFSET ?`, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := scanAuditCheckpoints(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *MySQLGrowthRepo) AdminExportAuditLedger(startDate string, endDate string, operator string) (model.AdminAuditLedgerExport, error) {
+	start, end, err := parseAuditLedgerExportRange(startDate, endDate)
+	if err != nil {
+		return model.AdminAuditLedgerExport{}, err
+	}
+	rows, err := r.db.Query(`
+SELECT seq, source_type, source_id, action, payload_json, payload_hash, prev_hash, entry_hash, created_at
+FROM admin_audit_ledger
+WHERE created_at >= ? AND created_at < ?
+ORDER BY seq ASC
+LIMIT ?`, start, end.AddDate(0, 0, 1), auditLedgerExportLimit)
+	if err != nil {
+		return model.AdminAuditLedgerExport{}, err
+	}
+	entries, err := scanAuditLedgerEntries(rows)
+	if err != nil {
+		return model.AdminAuditLedgerExport{}, err
+	}
+	checkpoints := make([]model.AdminAuditCheckpoint, 0)
+	if len(entries) > 0 {
+		checkpointRows, err := r.db.Query(`
+SELECT id, seq, entry_hash, entry_count, algorithm, key_id, public_key, signature, created_by, created_at
+FROM admin_audit_checkpoints
+WHERE seq BETWEEN ? AND ?
+ORDER BY seq ASC`, entries[0].Seq, entries[len(entries)-1].Seq)
+		if err != nil {
+			return model.AdminAuditLedgerExport{}, err
+		}
+		checkpoints, err = scanAuditCheckpoints(checkpointRows)
+		if err != nil {
+			return model.AdminAuditLedgerExport{}, err
+		}
+	}
+	return buildAuditLedgerManifest(
+		entries,
+		checkpoints,
+		start.Format("2006-01-02"),
+		end.Format("2006-01-02"),
+		strings.TrimSpace(operator),
+		r.resolveAuditLedgerSigner(),
+		time.Now(),
+	), nil
+}
+
+func parseAuditLedgerExportRange(startDate string, endDate string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(startDate), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date")
+	}
+	end, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(endDate), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date must not be before start_date")
+	}
+	if end.Sub(start) > 366*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("export range must not exceed 366 days")
+	}
+	return start, end, nil
+}
@@ -0,0 +1,205 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/config"
+)
+
+func expectAuditLedgerAppend(mock sqlmock.Sqlmock, sourceType string, sourceID driver.Value, action string) {
+	createdAt := time.Date(2026, 3, 30, 10, 0, 0, 0, time.Local)
+	switch sourceType {
+	case auditLedgerSourceOperationLog:
+		mock.ExpectQuery(regexp.QuoteMeta("FROM admin_operation_logs\nWHERE id = ?")).
+			WithArgs(sourceID).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "module", "action", "target_type", "target_id", "operator_user_id", "before_value", "after_value", "reason", "created_at",
+			}).AddRow("aol_001", "USER", "UPDATE_STATUS", "USER", "u_001", "admin_001", "ACTIVE", "DISABLED", "", createdAt))
+	case auditLedgerSourceAuditEvent:
+		mock.ExpectQuery(regexp.QuoteMeta("FROM admin_audit_events\nWHERE id = ?")).
+			WithArgs(sourceID).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "event_domain", "event_type", "level", "module", "object_type", "object_id", "actor_user_id",
+				"title", "summary", "detail", "status", "metadata_json", "dedupe_key", "created_at",
+			}).AddRow("ae_001", "DATA", "DATA_SOURCE_UNHEALTHY", "WARNING", "SYSTEM", "DATA_SOURCE", "TUSHARE", "system",
+				"数据源健康告警", "首次失败", "", "OPEN", "", "", createdAt))
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM admin_audit_ledger_head")).
+		WithArgs(auditLedgerHeadID).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq", "last_hash"}).AddRow(int64(7), "prevhash"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO admin_audit_ledger")).
+		WithArgs(int64(8), sourceType, sourceID, action, sqlmock.AnyArg(), sqlmock.AnyArg(), "prevhash", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE admin_audit_ledger_head")).
+		WithArgs(int64(8), sqlmock.AnyArg(), sqlmock.AnyArg(), auditLedgerHeadID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func buildTestAuditLedgerChain(payloads ...string) []model.AdminAuditLedgerEntry {
+	now := time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC)
+	entries := make([]model.AdminAuditLedgerEntry, 0, len(payloads))
+	prevSeq, prevHash := int64(0), ""
+	for i, payload := range payloads {
+		entry := buildAuditLedgerEntry(prevSeq, prevHash, auditLedgerSourceOperationLog, "aol_00"+string(rune('1'+i)), auditLedgerActionCreate, payload, now)
+		entries = append(entries, entry)
+		prevSeq, prevHash = entry.Seq, entry.EntryHash
+	}
+	return entries
+}
+
+func TestAuditLedgerChainVerifierAcceptsIntactChain(t *testing.T) {
+	signer := newAuditLedgerSigner("test")
+	entries := buildTestAuditLedgerChain(`{"id":"aol_001"}`, `{"id":"aol_002"}`, `{"id":"aol_003"}`)
+	checkpoint := model.AdminAuditCheckpoint{ID: "acp_1", Seq: 2, EntryHash: entries[1].EntryHash, EntryCount: 2, CreatedAt: "2026-03-30T10:00:00Z"}
+	checkpoint.Signature = signer.sign(auditCheckpointMessage(checkpoint))
+
+	verifier := newAuditLedgerChainVerifier(signer, []model.AdminAuditCheckpoint{checkpoint})
+	for _, entry := range entries {
+		verifier.checkEntry(entry)
+	}
+	verifier.checkSource(auditLedgerSourceOperationLog, "aol_001", `{"id":"aol_001"}`, time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC))
+	verifier.checkSource(auditLedgerSourceOperationLog, "aol_002", `{"id":"aol_002"}`, time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC))
+	verifier.checkSource(auditLedgerSourceOperationLog, "aol_003", `{"id":"aol_003"}`, time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC))
+	verifier.checkSource(auditLedgerSourceOperationLog, "aol_legacy", `{"id":"aol_legacy"}`, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	result := verifier.finish(3, entries[2].EntryHash, time.Now())
+
+	if !result.Valid || len(result.Issues) != 0 {
+		t.Fatalf("expected intact chain to verify, got %+v", result.Issues)
+	}
+	if result.CheckedEntries != 3 || result.LastSeq != 3 || result.LegacySources != 1 || result.CheckedCheckpoints != 1 {
+		t.Fatalf("unexpected verification counters: %+v", result)
+	}
+}
+
+func TestAuditLedgerChainVerifierDetectsTampering(t *testing.T) {
+	signer := newAuditLedgerSigner("test")
+	entries := buildTestAuditLedgerChain(`{"id":"aol_001"}`, `{"id":"aol_002"}`, `{"id":"aol_003"}`, `{"id":"aol_004"}`)
+	checkpoint := model.AdminAuditCheckpoint{ID: "acp_1", Seq: 4, EntryHash: entries[3].EntryHash, EntryCount: 4, CreatedAt: "2026-03-30T10:00:00Z"}
+	checkpoint.Signature = signer.sign(auditCheckpointMessage(checkpoint))
+
+	entries[0].PayloadJSON = `{"id":"aol_001","after_value":"forged"}`
+	verifier := newAuditLedgerChainVerifier(signer, []model.AdminAuditCheckpoint{checkpoint})
+	verifier.checkEntry(entries[0])
+	verifier.checkEntry(entries[2])
+	verifier.checkSource(auditLedgerSourceOperationLog, "aol_003", `{"id":"aol_003","reason":"edited"}`, time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC))
+	verifier.checkSource(auditLedgerSourceOperationLog, "aol_009", `{"id":"aol_009"}`, time.Date(2026, 3, 30, 11, 0, 0, 0, time.UTC))
+	result := verifier.finish(4, entries[3].EntryHash, time.Now())
+
+	codes := make(map[string]int)
+	for _, issue := range result.Issues {
+		codes[issue.Code]++
+	}
+	for _, code := range []string{"PAYLOAD_HASH_MISMATCH", "SEQ_GAP", "PREV_HASH_MISMATCH", "SOURCE_MODIFIED", "SOURCE_UNCHAINED", "SOURCE_MISSING", "CHECKPOINT_ORPHANED", "HEAD_MISMATCH"} {
+		if codes[code] == 0 {
+			t.Fatalf("expected issue %s, got %+v", code, result.Issues)
+		}
+	}
+	if result.Valid {
+		t.Fatalf("expected tampered chain to be invalid")
+	}
+}
+
+func TestAuditLedgerCheckpointRejectsForgedSignature(t *testing.T) {
+	signer := newAuditLedgerSigner("test")
+	checkpoint := model.AdminAuditCheckpoint{ID: "acp_1", Seq: 1, EntryHash: "abc", EntryCount: 1, CreatedAt: "2026-03-30T10:00:00Z"}
+	checkpoint.Signature = newAuditLedgerSigner("other").sign(auditCheckpointMessage(checkpoint))
+
+	verifier := newAuditLedgerChainVerifier(signer, []model.AdminAuditCheckpoint{checkpoint})
+	if len(verifier.result.Issues) != 1 || verifier.result.Issues[0].Code != "CHECKPOINT_SIGNATURE_INVALID" {
+		t.Fatalf("expected forged checkpoint signature to be flagged, got %+v", verifier.result.Issues)
+	}
+}
+
+func TestBuildAuditLedgerManifestIsOfflineVerifiable(t *testing.T) {
+	signer := newAuditLedgerSigner("test")
+	entries := buildTestAuditLedgerChain(`{"id":"aol_001"}`, `{"id":"aol_002"}`)
+
+	export := buildAuditLedgerManifest(entries, nil, "2026-03-30", "2026-03-30", "admin_001", signer, time.Now())
+
+	sum := sha256.Sum256(export.EntriesJSONL)
+	if export.Manifest.EntriesSHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("manifest digest does not match exported jsonl")
+	}
+	if export.Manifest.AnchorPrevHash != auditLedgerGenesisHash || export.Manifest.LastEntryHash != entries[1].EntryHash {
+		t.Fatalf("unexpected chain anchors: %+v", export.Manifest)
+	}
+	if !signer.verify(auditExportMessage(export.Manifest), export.Manifest.Signature) {
+		t.Fatalf("expected manifest signature to verify with published key")
+	}
+	if export.Manifest.PublicKey != signer.publicKeyText() || export.Manifest.KeyID == "" {
+		t.Fatalf("expected manifest to publish the verification key, got %+v", export.Manifest)
+	}
+}
+
+func TestMySQLAdminCreateOperationLogAppendsLedgerEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := &MySQLGrowthRepo{db: db}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO admin_operation_logs")).
+		WithArgs(sqlmock.AnyArg(), "USER", "UPDATE_STATUS", "USER", "u_001", "admin_001", "ACTIVE", "DISABLED", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAuditLedgerAppend(mock, auditLedgerSourceOperationLog, sqlmock.AnyArg(), auditLedgerActionCreate)
+	mock.ExpectCommit()
+
+	if err := repo.AdminCreateOperationLog("user", "update_status", "user", "u_001", "admin_001", "ACTIVE", "DISABLED", ""); err != nil {
+		t.Fatalf("AdminCreateOperationLog() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestInMemoryAuditLedgerDetectsEditedAuditEvent(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	if err := repo.AdminCreateAuditEvent(model.AdminAuditEvent{EventDomain: "SYSTEM", EventType: "CONFIG_CHANGED", Level: "INFO", Title: "配置变更"}); err != nil {
+		t.Fatalf("AdminCreateAuditEvent() error = %v", err)
+	}
+	checkpoint, err := repo.AdminCreateAuditCheckpoint("admin_001")
+	if err != nil {
+		t.Fatalf("AdminCreateAuditCheckpoint() error = %v", err)
+	}
+	if result, _ := repo.AdminVerifyAuditLedger(); !result.Valid || result.LastSeq != checkpoint.Seq {
+		t.Fatalf("expected fresh ledger to verify, got %+v", result)
+	}
+
+	repo.mu.Lock()
+	for id, item := range repo.adminAuditEvents {
+		item.Title = "篡改"
+		repo.adminAuditEvents[id] = item
+	}
+	repo.mu.Unlock()
+
+	result, _ := repo.AdminVerifyAuditLedger()
+	if result.Valid || len(result.Issues) != 1 || result.Issues[0].Code != "SOURCE_MODIFIED" {
+		t.Fatalf("expected edited event to be flagged, got %+v", result.Issues)
+	}
+}
+
+func TestAuditSigningSecretPrefersItsOwnKey(t *testing.T) {
+	separate := newAuditLedgerSigner(auditSigningSecret(config.Config{AuditSigningSecret: "audit-secret", JWTSecret: "jwt-secret"}))
+	rotatedJWT := newAuditLedgerSigner(auditSigningSecret(config.Config{AuditSigningSecret: "audit-secret", JWTSecret: "jwt-rotated"}))
+	if separate.keyID != rotatedJWT.keyID {
+		t.Fatalf("expected the audit key to survive a JWT rotation, got %s and %s", separate.keyID, rotatedJWT.keyID)
+	}
+
+	fallback := newAuditLedgerSigner(auditSigningSecret(config.Config{AuditSigningSecret: "  ", JWTSecret: "jwt-secret"}))
+	if fallback.keyID != newAuditLedgerSigner("jwt-secret").keyID {
+		t.Fatalf("expected an unset audit secret to fall back to the JWT secret")
+	}
+	if fallback.keyID == separate.keyID {
+		t.Fatal("expected the fallback key to differ from the dedicated audit key")
+	}
+}
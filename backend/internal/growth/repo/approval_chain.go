@@ -0,0 +1,398 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const approvalPolicyColumns = `id, module, display_name, status, min_amount, CAST(steps_json AS CHAR), updated_by, created_at, updated_at`
+
+const approvalRequestColumns = `id, policy_id, module, action, target_id, amount, summary, payload, submitter_id, status, current_step,
+  CAST(steps_json AS CHAR), execute_error, created_at, updated_at, completed_at, executed_at`
+
+func (r *MySQLGrowthRepo) AdminListApprovalPolicies() ([]model.ApprovalPolicy, error) {
+	rows, err := r.db.Query(`SELECT ` + approvalPolicyColumns + ` FROM approval_policies ORDER BY module ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ApprovalPolicy, 0)
+	for rows.Next() {
+		item, err := scanApprovalPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminGetApprovalPolicy(module string) (model.ApprovalPolicy, error) {
+	item, err := scanApprovalPolicy(r.db.QueryRow(`SELECT `+approvalPolicyColumns+` FROM approval_policies WHERE module = ?`, strings.ToUpper(strings.TrimSpace(module))))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ApprovalPolicy{}, model.ErrApprovalPolicyNotFound
+	}
+	return item, err
+}
+
+// AdminSaveApprovalPolicy upserts by module. Requests already submitted keep
+// the steps they were created with.
+func (r *MySQLGrowthRepo) AdminSaveApprovalPolicy(item model.ApprovalPolicy, operator string) (model.ApprovalPolicy, error) {
+	stepsJSON, err := json.Marshal(item.Steps)
+	if err != nil {
+		return model.ApprovalPolicy{}, err
+	}
+	displayName := item.DisplayName
+	if displayName == "" {
+		displayName = item.Module
+	}
+	now := time.Now()
+	_, err = r.db.Exec(`
+INSERT INTO approval_policies (id, module, display_name, status, min_amount, steps_json, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE display_name = VALUES(display_name), status = VALUES(status), min_amount = VALUES(min_amount), steps_json = VALUES(steps_json), updated_by = VALUES(updated_by), updated_at = VALUES(updated_at)`,
+		newID("ap"), item.Module, displayName, item.Status, item.MinAmount, string(stepsJSON), strings.TrimSpace(operator), now, now,
+	)
+	if err != nil {
+		return model.ApprovalPolicy{}, err
+	}
+	return r.AdminGetApprovalPolicy(item.Module)
+}
+
+// AdminCreateApprovalRequest opens a chain at step 1. Only one chain per
+// action and target may be open at a time, so a second submission while the
+// first is pending or executing fails with ErrApprovalRequestDuplicate.
+func (r *MySQLGrowthRepo) AdminCreateApprovalRequest(item model.ApprovalRequest) (model.ApprovalRequest, error) {
+	stepsJSON, err := json.Marshal(item.Steps)
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	defer tx.Rollback()
+
+	var openID string
+	err = tx.QueryRow(`
+SELECT id FROM approval_requests
+WHERE action = ? AND target_id = ? AND status IN (?, ?, ?)
+LIMIT 1 FOR UPDATE`,
+		item.Action, item.TargetID, model.ApprovalRequestPending, model.ApprovalRequestApproved, model.ApprovalRequestExecuting,
+	).Scan(&openID)
+	if err == nil {
+		return model.ApprovalRequest{}, model.ErrApprovalRequestDuplicate
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.ApprovalRequest{}, err
+	}
+
+	now := time.Now()
+	item.ID = newID("apr")
+	item.Status = model.ApprovalRequestPending
+	item.CurrentStep = 1
+	_, err = tx.Exec(`
+INSERT INTO approval_requests (id, policy_id, module, action, target_id, amount, summary, payload, submitter_id, status, current_step, steps_json, execute_error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '', ?, ?)`,
+		item.ID, item.PolicyID, item.Module, item.Action, item.TargetID, item.Amount,
+		truncateByRunes(item.Summary, 512), item.Payload, item.SubmitterID, item.Status, item.CurrentStep, string(stepsJSON), now, now,
+	)
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	item.CreatedAt = now.Format(time.RFC3339)
+	item.UpdatedAt = item.CreatedAt
+	return item, nil
+}
+
+func (r *MySQLGrowthRepo) AdminListApprovalRequests(module string, status string, submitterID string, page int, pageSize int) ([]model.ApprovalRequest, int, error) {
+	filter := " WHERE 1=1"
+	args := []interface{}{}
+	if module = strings.ToUpper(strings.TrimSpace(module)); module != "" {
+		filter += " AND module = ?"
+		args = append(args, module)
+	}
+	if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+		filter += " AND status = ?"
+		args = append(args, status)
+	}
+	if submitterID = strings.TrimSpace(submitterID); submitterID != "" {
+		filter += " AND submitter_id = ?"
+		args = append(args, submitterID)
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM approval_requests"+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, (page-1)*pageSize)
+	rows, err := r.db.Query(`
+SELECT `+approvalRequestColumns+`
+FROM approval_requests`+filter+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.ApprovalRequest, 0)
+	for rows.Next() {
+		item, err := scanApprovalRequest(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminGetApprovalRequest(id string) (model.ApprovalRequest, error) {
+	item, err := scanApprovalRequest(r.db.QueryRow(`SELECT `+approvalRequestColumns+` FROM approval_requests WHERE id = ?`, strings.TrimSpace(id)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ApprovalRequest{}, model.ErrApprovalRequestNotFound
+	}
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	item.Decisions, err = loadApprovalDecisions(r.db, item.ID)
+	return item, err
+}
+
+// AdminDecideApprovalRequest signs the current step under a row lock, so
+// concurrent approvers cannot both complete the same step or chain. The
+// approver's roles are read inside the same transaction.
+func (r *MySQLGrowthRepo) AdminDecideApprovalRequest(id string, approverID string, decision string, note string) (model.ApprovalRequest, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	defer tx.Rollback()
+
+	item, err := scanApprovalRequest(tx.QueryRow(`SELECT `+approvalRequestColumns+` FROM approval_requests WHERE id = ? FOR UPDATE`, strings.TrimSpace(id)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ApprovalRequest{}, model.ErrApprovalRequestNotFound
+	}
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	if item.Decisions, err = loadApprovalDecisions(tx, item.ID); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	roleKeys, err := loadApproverRoleKeys(tx, approverID)
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	now := time.Now()
+	next, signed, err := item.Decide(approverID, roleKeys, decision, note, now.Format(time.RFC3339))
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	signed.ID = newID("apd")
+	if _, err := tx.Exec(`
+INSERT INTO approval_decisions (id, request_id, step, approver_id, decision, note, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		signed.ID, signed.RequestID, signed.Step, signed.ApproverID, signed.Decision, truncateByRunes(signed.Note, 512), now,
+	); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	var completedAt interface{}
+	if next.CompletedAt != "" {
+		completedAt = now
+	}
+	if _, err := tx.Exec(`
+UPDATE approval_requests
+SET status = ?, current_step = ?, updated_at = ?, completed_at = COALESCE(?, completed_at)
+WHERE id = ?`,
+		next.Status, next.CurrentStep, now, completedAt, next.ID,
+	); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	next.Decisions[len(next.Decisions)-1] = signed
+
+	if next.Status == model.ApprovalRequestApproved || next.Status == model.ApprovalRequestRejected {
+		level := "INFO"
+		if next.Status == model.ApprovalRequestRejected {
+			level = "WARNING"
+		}
+		_ = r.AdminCreateAuditEvent(model.AdminAuditEvent{
+			EventDomain: "WORKFLOW",
+			EventType:   "APPROVAL_" + next.Status,
+			Level:       level,
+			Module:      next.Module,
+			ObjectType:  "APPROVAL_REQUEST",
+			ObjectID:    next.ID,
+			ActorUserID: signed.ApproverID,
+			Title:       "审批链结果通知",
+			Summary:     "审批结果为 " + next.Status + "，操作 " + next.Action + "，目标 " + next.TargetID,
+			Detail:      truncateByRunes(normalizeUTF8Text(signed.Note), 512),
+			Status:      "OPEN",
+			Metadata: map[string]any{
+				"approval_id":  next.ID,
+				"action":       next.Action,
+				"target_id":    next.TargetID,
+				"submitter_id": next.SubmitterID,
+				"steps":        len(next.Steps),
+				"decisions":    len(next.Decisions),
+			},
+		})
+	}
+	return next, nil
+}
+
+// AdminClaimApprovalExecution moves a completed chain (or one whose action
+// failed) to EXECUTING. Only one caller wins the claim, so the guarded
+// action cannot run twice.
+func (r *MySQLGrowthRepo) AdminClaimApprovalExecution(id string) (model.ApprovalRequest, error) {
+	id = strings.TrimSpace(id)
+	res, err := r.db.Exec(`
+UPDATE approval_requests SET status = ?, updated_at = ?
+WHERE id = ? AND status IN (?, ?)`,
+		model.ApprovalRequestExecuting, time.Now(), id, model.ApprovalRequestApproved, model.ApprovalRequestExecuteFailed,
+	)
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	item, err := r.AdminGetApprovalRequest(id)
+	if err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	if affected == 0 {
+		return model.ApprovalRequest{}, model.ErrApprovalNotExecutable
+	}
+	return item, nil
+}
+
+// AdminFinishApprovalExecution records the outcome of a claimed execution;
+// an empty executeError marks the request EXECUTED.
+func (r *MySQLGrowthRepo) AdminFinishApprovalExecution(id string, executeError string) error {
+	status := model.ApprovalRequestExecuted
+	var executedAt interface{} = time.Now()
+	if executeError != "" {
+		status = model.ApprovalRequestExecuteFailed
+		executedAt = nil
+	}
+	_, err := r.db.Exec(`
+UPDATE approval_requests
+SET status = ?, execute_error = ?, executed_at = ?, updated_at = ?
+WHERE id = ? AND status = ?`,
+		status, truncateByRunes(executeError, 512), executedAt, time.Now(), strings.TrimSpace(id), model.ApprovalRequestExecuting,
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) AdminGetWithdrawRequest(id string) (model.WithdrawRequestInfo, error) {
+	var item model.WithdrawRequestInfo
+	var appliedAt time.Time
+	err := r.db.QueryRow("SELECT id, user_id, amount, status, applied_at FROM withdraw_requests WHERE id = ?", id).
+		Scan(&item.ID, &item.UserID, &item.Amount, &item.Status, &appliedAt)
+	if err != nil {
+		return model.WithdrawRequestInfo{}, err
+	}
+	item.AppliedAt = appliedAt.Format(time.RFC3339)
+	return item, nil
+}
+
+type approvalQueryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func loadApprovalDecisions(db approvalQueryer, requestID string) ([]model.ApprovalDecision, error) {
+	rows, err := db.Query(`
+SELECT id, request_id, step, approver_id, decision, note, created_at
+FROM approval_decisions
+WHERE request_id = ?
+ORDER BY created_at ASC, id ASC`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ApprovalDecision, 0)
+	for rows.Next() {
+		var item model.ApprovalDecision
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.RequestID, &item.Step, &item.ApproverID, &item.Decision, &item.Note, &createdAt); err != nil {
+			return nil, err
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// loadApproverRoleKeys returns the approver's ACTIVE role keys, the same set
+// the admin access profile is built from.
+func loadApproverRoleKeys(db approvalQueryer, userID string) ([]string, error) {
+	rows, err := db.Query(`
+SELECT r.role_key
+FROM rbac_user_roles ur
+JOIN rbac_roles r ON r.id = ur.role_id
+WHERE ur.user_id = ? AND r.status = 'ACTIVE'`, strings.TrimSpace(userID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func scanApprovalPolicy(scanner interface{ Scan(dest ...any) error }) (model.ApprovalPolicy, error) {
+	var item model.ApprovalPolicy
+	var stepsJSON string
+	var createdAt, updatedAt time.Time
+	if err := scanner.Scan(&item.ID, &item.Module, &item.DisplayName, &item.Status, &item.MinAmount, &stepsJSON, &item.UpdatedBy, &createdAt, &updatedAt); err != nil {
+		return model.ApprovalPolicy{}, err
+	}
+	if err := json.Unmarshal([]byte(stepsJSON), &item.Steps); err != nil {
+		return model.ApprovalPolicy{}, err
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return item, nil
+}
+
+func scanApprovalRequest(scanner interface{ Scan(dest ...any) error }) (model.ApprovalRequest, error) {
+	var item model.ApprovalRequest
+	var stepsJSON string
+	var createdAt, updatedAt time.Time
+	var completedAt, executedAt sql.NullTime
+	if err := scanner.Scan(
+		&item.ID, &item.PolicyID, &item.Module, &item.Action, &item.TargetID, &item.Amount, &item.Summary, &item.Payload,
+		&item.SubmitterID, &item.Status, &item.CurrentStep, &stepsJSON, &item.ExecuteError,
+		&createdAt, &updatedAt, &completedAt, &executedAt,
+	); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	if err := json.Unmarshal([]byte(stepsJSON), &item.Steps); err != nil {
+		return model.ApprovalRequest{}, err
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	if completedAt.Valid {
+		item.CompletedAt = completedAt.Time.Format(time.RFC3339)
+	}
+	if executedAt.Valid {
+		item.ExecutedAt = executedAt.Time.Format(time.RFC3339)
+	}
+	return item, nil
+}
@@ -0,0 +1,223 @@
+package repo
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// seedApprovalPolicies mirrors the DISABLED policies and the admin role
+// bindings seeded by the 20260330_19 and admin RBAC migrations.
+func (r *InMemoryGrowthRepo) seedApprovalPolicies() {
+	now := time.Now().Format(time.RFC3339)
+	seed := []model.ApprovalPolicy{
+		{ID: "ap_recommendation_publish", Module: model.ApprovalModuleRecommendationPublish, DisplayName: "推荐发布双人复核", Steps: []model.ApprovalStep{
+			{Name: "运营复核", RequiredApprovals: 1, ApproverRoles: []string{"OPS_ADMIN", "SUPER_ADMIN"}},
+		}},
+		{ID: "ap_withdraw", Module: model.ApprovalModuleWithdraw, DisplayName: "大额提现审批", MinAmount: 5000, Steps: []model.ApprovalStep{
+			{Name: "运营复核", RequiredApprovals: 1, ApproverRoles: []string{"OPS_ADMIN"}},
+			{Name: "财务终审", RequiredApprovals: 1, ApproverRoles: []string{"SUPER_ADMIN"}},
+		}},
+		{ID: "ap_payment_config", Module: model.ApprovalModulePaymentConfig, DisplayName: "支付配置变更审批", Steps: []model.ApprovalStep{
+			{Name: "超级管理员复核", RequiredApprovals: 1, ApproverRoles: []string{"SUPER_ADMIN"}},
+		}},
+		{ID: "ap_rbac", Module: model.ApprovalModuleRBAC, DisplayName: "角色权限变更审批", Steps: []model.ApprovalStep{
+			{Name: "超级管理员复核", RequiredApprovals: 1, ApproverRoles: []string{"SUPER_ADMIN"}},
+		}},
+	}
+	for _, item := range seed {
+		item.Status = model.ApprovalPolicyStatusDisabled
+		item.UpdatedBy = "system"
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		r.approvalPolicies[item.Module] = item
+	}
+	r.approverRoles = map[string][]string{
+		"admin_001": {"SUPER_ADMIN"},
+		"admin_002": {"OPS_ADMIN"},
+	}
+}
+
+func (r *InMemoryGrowthRepo) AdminListApprovalPolicies() ([]model.ApprovalPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.ApprovalPolicy, 0, len(r.approvalPolicies))
+	for _, item := range r.approvalPolicies {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Module < items[j].Module })
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetApprovalPolicy(module string) (model.ApprovalPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.approvalPolicies[strings.ToUpper(strings.TrimSpace(module))]
+	if !ok {
+		return model.ApprovalPolicy{}, model.ErrApprovalPolicyNotFound
+	}
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminSaveApprovalPolicy(item model.ApprovalPolicy, operator string) (model.ApprovalPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().Format(time.RFC3339)
+	if existing, ok := r.approvalPolicies[item.Module]; ok {
+		item.ID = existing.ID
+		item.CreatedAt = existing.CreatedAt
+	} else {
+		item.ID = newID("ap")
+		item.CreatedAt = now
+	}
+	if item.DisplayName == "" {
+		item.DisplayName = item.Module
+	}
+	item.UpdatedBy = strings.TrimSpace(operator)
+	item.UpdatedAt = now
+	r.approvalPolicies[item.Module] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCreateApprovalRequest(item model.ApprovalRequest) (model.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.approvalRequests {
+		if existing.Action != item.Action || existing.TargetID != item.TargetID {
+			continue
+		}
+		switch existing.Status {
+		case model.ApprovalRequestPending, model.ApprovalRequestApproved, model.ApprovalRequestExecuting:
+			return model.ApprovalRequest{}, model.ErrApprovalRequestDuplicate
+		}
+	}
+	now := time.Now().Format(time.RFC3339)
+	item.ID = newID("apr")
+	item.Status = model.ApprovalRequestPending
+	item.CurrentStep = 1
+	item.Decisions = nil
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	r.approvalRequests[item.ID] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListApprovalRequests(module string, status string, submitterID string, page int, pageSize int) ([]model.ApprovalRequest, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	module = strings.ToUpper(strings.TrimSpace(module))
+	status = strings.ToUpper(strings.TrimSpace(status))
+	submitterID = strings.TrimSpace(submitterID)
+	items := make([]model.ApprovalRequest, 0, len(r.approvalRequests))
+	for _, item := range r.approvalRequests {
+		if (module != "" && item.Module != module) || (status != "" && item.Status != status) || (submitterID != "" && item.SubmitterID != submitterID) {
+			continue
+		}
+		item.Decisions = nil
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID > items[j].ID })
+	total := len(items)
+	start := (page - 1) * pageSize
+	if start >= total {
+		return []model.ApprovalRequest{}, total, nil
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetApprovalRequest(id string) (model.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.approvalRequests[strings.TrimSpace(id)]
+	if !ok {
+		return model.ApprovalRequest{}, model.ErrApprovalRequestNotFound
+	}
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminDecideApprovalRequest(id string, approverID string, decision string, note string) (model.ApprovalRequest, error) {
+	r.mu.Lock()
+	item, ok := r.approvalRequests[strings.TrimSpace(id)]
+	if !ok {
+		r.mu.Unlock()
+		return model.ApprovalRequest{}, model.ErrApprovalRequestNotFound
+	}
+	next, signed, err := item.Decide(approverID, r.approverRoles[strings.TrimSpace(approverID)], decision, note, time.Now().Format(time.RFC3339))
+	if err != nil {
+		r.mu.Unlock()
+		return model.ApprovalRequest{}, err
+	}
+	signed.ID = newID("apd")
+	next.Decisions[len(next.Decisions)-1] = signed
+	r.approvalRequests[next.ID] = next
+	r.mu.Unlock()
+
+	if next.Status == model.ApprovalRequestApproved || next.Status == model.ApprovalRequestRejected {
+		_ = r.AdminCreateAuditEvent(model.AdminAuditEvent{
+			EventDomain: "WORKFLOW",
+			EventType:   "APPROVAL_" + next.Status,
+			Level:       "INFO",
+			Module:      next.Module,
+			ObjectType:  "APPROVAL_REQUEST",
+			ObjectID:    next.ID,
+			ActorUserID: signed.ApproverID,
+			Title:       "审批链结果通知",
+			Summary:     "审批结果为 " + next.Status + "，操作 " + next.Action + "，目标 " + next.TargetID,
+			Status:      "OPEN",
+		})
+	}
+	return next, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminClaimApprovalExecution(id string) (model.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.approvalRequests[strings.TrimSpace(id)]
+	if !ok {
+		return model.ApprovalRequest{}, model.ErrApprovalRequestNotFound
+	}
+	if item.Status != model.ApprovalRequestApproved && item.Status != model.ApprovalRequestExecuteFailed {
+		return model.ApprovalRequest{}, model.ErrApprovalNotExecutable
+	}
+	item.Status = model.ApprovalRequestExecuting
+	item.UpdatedAt = time.Now().Format(time.RFC3339)
+	r.approvalRequests[item.ID] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminFinishApprovalExecution(id string, executeError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.approvalRequests[strings.TrimSpace(id)]
+	if !ok || item.Status != model.ApprovalRequestExecuting {
+		return nil
+	}
+	now := time.Now().Format(time.RFC3339)
+	item.Status = model.ApprovalRequestExecuted
+	item.ExecuteError = executeError
+	item.ExecutedAt = now
+	if executeError != "" {
+		item.Status = model.ApprovalRequestExecuteFailed
+		item.ExecutedAt = ""
+	}
+	item.UpdatedAt = now
+	r.approvalRequests[item.ID] = item
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetWithdrawRequest(id string) (model.WithdrawRequestInfo, error) {
+	items, _, _ := r.AdminListWithdrawRequests(1, 20)
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return model.WithdrawRequestInfo{}, sql.ErrNoRows
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func approvalRequestRowsForTest(status string, currentStep int) *sqlmock.Rows {
+	now := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	steps := `[{"name":"运营复核","required_approvals":2,"approver_roles":["OPS_ADMIN"]},{"required_approvals":1,"approver_roles":["SUPER_ADMIN"]}]`
+	return sqlmock.NewRows([]string{
+		"id", "policy_id", "module", "action", "target_id", "amount", "summary", "payload", "submitter_id", "status", "current_step",
+		"steps_json", "execute_error", "created_at", "updated_at", "completed_at", "executed_at",
+	}).AddRow("apr_1", "ap_withdraw", "WITHDRAW", "WITHDRAW_APPROVE", "wd_9", 8000.0, "提现", `{"status":"APPROVED"}`, "admin_maker", status, currentStep,
+		steps, "", now, now, nil, nil)
+}
+
+func TestMySQLAdminDecideApprovalRequestCountsApprovalsPerStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	decisionColumns := []string{"id", "request_id", "step", "approver_id", "decision", "note", "created_at"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM approval_requests WHERE id = ? FOR UPDATE")).
+		WithArgs("apr_1").
+		WillReturnRows(approvalRequestRowsForTest(model.ApprovalRequestPending, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM approval_decisions")).
+		WithArgs("apr_1").
+		WillReturnRows(sqlmock.NewRows(decisionColumns).AddRow("apd_1", "apr_1", 1, "admin_ops_1", "APPROVE", "", time.Now()))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM rbac_user_roles ur")).
+		WithArgs("admin_ops_2").
+		WillReturnRows(sqlmock.NewRows([]string{"role_key"}).AddRow("OPS_ADMIN"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO approval_decisions")).
+		WithArgs(sqlmock.AnyArg(), "apr_1", 1, "admin_ops_2", "APPROVE", "金额核对无误", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE approval_requests")).
+		WithArgs(model.ApprovalRequestPending, 2, sqlmock.AnyArg(), nil, "apr_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	item, err := repo.AdminDecideApprovalRequest("apr_1", "admin_ops_2", "APPROVE", "金额核对无误")
+	if err != nil {
+		t.Fatalf("AdminDecideApprovalRequest() error = %v", err)
+	}
+	if item.Status != model.ApprovalRequestPending || item.CurrentStep != 2 || len(item.Decisions) != 2 {
+		t.Fatalf("expected the second approval to advance to step 2, got %+v", item)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM approval_requests WHERE id = ? FOR UPDATE")).
+		WithArgs("apr_1").
+		WillReturnRows(approvalRequestRowsForTest(model.ApprovalRequestPending, 2))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM approval_decisions")).
+		WithArgs("apr_1").
+		WillReturnRows(sqlmock.NewRows(decisionColumns))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM rbac_user_roles ur")).
+		WithArgs("admin_maker").
+		WillReturnRows(sqlmock.NewRows([]string{"role_key"}).AddRow("SUPER_ADMIN"))
+	mock.ExpectRollback()
+	if _, err := repo.AdminDecideApprovalRequest("apr_1", "admin_maker", "APPROVE", ""); !errors.Is(err, model.ErrApprovalSelfApproval) {
+		t.Fatalf("expected ErrApprovalSelfApproval for the submitter, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
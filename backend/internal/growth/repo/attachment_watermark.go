@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// AdminListAttachmentDownloads returns a user's downloads of one
+// attachment, newest first.
+func (r *MySQLGrowthRepo) AdminListAttachmentDownloads(attachmentID string, userID string) ([]model.AttachmentDownloadRecord, error) {
+	rows, err := r.db.Query(`
+SELECT id, user_id, attachment_id, article_id, downloaded_at
+FROM attachment_download_logs
+WHERE attachment_id = ? AND user_id = ?
+ORDER BY downloaded_at DESC
+LIMIT 200`, attachmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]model.AttachmentDownloadRecord, 0)
+	for rows.Next() {
+		var item model.AttachmentDownloadRecord
+		var downloadedAt time.Time
+		if err := rows.Scan(&item.ID, &item.UserID, &item.AttachmentID, &item.ArticleID, &downloadedAt); err != nil {
+			return nil, err
+		}
+		item.DownloadedAt = downloadedAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AdminListAttachmentDownloaderIDs returns every user who downloaded the
+// attachment, i.e. everyone who may have a stamped copy cached.
+func (r *MySQLGrowthRepo) AdminListAttachmentDownloaderIDs(attachmentID string) ([]string, error) {
+	rows, err := r.db.Query("SELECT DISTINCT user_id FROM attachment_download_logs WHERE attachment_id = ?", attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]string, 0)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		items = append(items, userID)
+	}
+	return items, rows.Err()
+}
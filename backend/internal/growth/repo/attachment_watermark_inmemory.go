@@ -0,0 +1,15 @@
+package repo
+
+import "sercherai/backend/internal/growth/model"
+
+func (r *InMemoryGrowthRepo) AdminListAttachmentDownloads(attachmentID string, userID string) ([]model.AttachmentDownloadRecord, error) {
+	return []model.AttachmentDownloadRecord{
+		{ID: "adl_demo_001", UserID: userID, AttachmentID: attachmentID, ArticleID: "article_demo_001", DownloadedAt: "2026-02-25T10:05:00+08:00"},
+	}, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListAttachmentDownloaderIDs(attachmentID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.attachmentDownloaders[attachmentID]...), nil
+}
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// AdminListLoginLogs pages auth_login_logs newest first. dateFrom and dateTo
+// are YYYY-MM-DD days and are both inclusive.
+func (r *MySQLGrowthRepo) AdminListLoginLogs(action string, status string, dateFrom string, dateTo string, page int, pageSize int) ([]model.LoginLog, int, error) {
+	offset := (page - 1) * pageSize
+	args := []interface{}{}
+	filter := " WHERE 1=1"
+	if action != "" {
+		filter += " AND action = ?"
+		args = append(args, strings.ToUpper(action))
+	}
+	if status != "" {
+		filter += " AND status = ?"
+		args = append(args, strings.ToUpper(status))
+	}
+	if dateFrom != "" {
+		filter += " AND created_at >= ?"
+		args = append(args, dateFrom+" 00:00:00")
+	}
+	if dateTo != "" {
+		filter += " AND created_at <= ?"
+		args = append(args, dateTo+" 23:59:59")
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM auth_login_logs"+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	query := `
+SELECT id, user_id, phone, action, status, reason, ip, user_agent, created_at
+FROM auth_login_logs` + filter + `
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.LoginLog, 0)
+	for rows.Next() {
+		var item model.LoginLog
+		var userID, phone, reason, ip, userAgent sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &userID, &phone, &item.Action, &item.Status, &reason, &ip, &userAgent, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		item.UserID = userID.String
+		item.Phone = phone.String
+		item.Reason = reason.String
+		item.IP = ip.String
+		item.UserAgent = userAgent.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
@@ -0,0 +1,19 @@
+package repo
+
+import "sercherai/backend/internal/growth/model"
+
+func (r *InMemoryGrowthRepo) AdminListLoginLogs(action string, status string, dateFrom string, dateTo string, page int, pageSize int) ([]model.LoginLog, int, error) {
+	items := []model.LoginLog{
+		{
+			ID:        "ll_001",
+			UserID:    "u_demo_001",
+			Phone:     "13800000001",
+			Action:    "LOGIN",
+			Status:    "SUCCESS",
+			IP:        "203.0.113.45",
+			UserAgent: "Mozilla/5.0",
+			CreatedAt: "2026-02-25T09:00:00+08:00",
+		},
+	}
+	return items, len(items), nil
+}
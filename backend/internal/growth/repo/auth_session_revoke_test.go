@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/platform/session"
+)
+
+func TestMySQLAdminUpdateUserStatusRevokesSessionsWhenDisabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := &MySQLGrowthRepo{db: db, sessions: session.NewStore(db, nil)}
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET status = ?")).
+		WithArgs("DISABLED", sqlmock.AnyArg(), "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM auth_sessions WHERE user_id = ? AND status = 'ACTIVE' FOR UPDATE")).
+		WithArgs("u_001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("sess_a").AddRow("sess_b"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_sessions")).
+		WithArgs(sqlmock.AnyArg(), "admin_007", "status_disabled", "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked = 1")).
+		WithArgs(sqlmock.AnyArg(), "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	if err := repo.AdminUpdateUserStatus("u_001", "DISABLED", "admin_007"); err != nil {
+		t.Fatalf("AdminUpdateUserStatus() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminUpdateUserStatusKeepsSessionsWhenActivated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := &MySQLGrowthRepo{db: db, sessions: session.NewStore(db, nil)}
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET status = ?")).
+		WithArgs("ACTIVE", sqlmock.AnyArg(), "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.AdminUpdateUserStatus("u_001", "ACTIVE", "admin_007"); err != nil {
+		t.Fatalf("AdminUpdateUserStatus() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminResetUserPasswordHashRevokesSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := &MySQLGrowthRepo{db: db, sessions: session.NewStore(db, nil)}
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password_hash = ?")).
+		WithArgs("hash", sqlmock.AnyArg(), "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM auth_sessions")).
+		WithArgs("u_001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked = 1")).
+		WithArgs(sqlmock.AnyArg(), "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.AdminResetUserPasswordHash("u_001", "hash", ""); err != nil {
+		t.Fatalf("AdminResetUserPasswordHash() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/realtime"
 )
 
 func (r *InMemoryGrowthRepo) seedCommunityData() {
@@ -101,6 +102,7 @@ func (r *InMemoryGrowthRepo) seedCommunityData() {
 	r.communityComments[pendingComment.ID] = pendingComment
 	r.communityReports[report.ID] = report
 	r.communityReacts[communityReactionKey("u_demo_002", "TOPIC", topic.ID, "LIKE")] = struct{}{}
+	r.seedCommunitySensitiveWords()
 }
 
 func communityReactionKey(userID string, targetType string, targetID string, reactionType string) string {
@@ -161,7 +163,12 @@ func (r *InMemoryGrowthRepo) CreateCommunityTopic(input model.CommunityTopicCrea
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	now := time.Now().Format(time.RFC3339)
+	current := time.Now()
+	verdict, err := r.moderateCommunityPostLocked(strings.TrimSpace(input.UserID), "TOPIC", communityModerationText(input.Title, input.Summary, input.Content, input.ReasonText, input.RiskText), current)
+	if err != nil {
+		return model.CommunityTopicDetail{}, err
+	}
+	now := current.Format(time.RFC3339)
 	id := newID("ct")
 	topic := model.CommunityTopicDetail{
 		ID:           id,
@@ -187,7 +194,12 @@ func (r *InMemoryGrowthRepo) CreateCommunityTopic(input model.CommunityTopicCrea
 	if topic.Summary == "" {
 		topic.Summary = summarizeCommunityText(topic.Content)
 	}
+	if verdict.held() {
+		topic.Status = string(model.CommunityTopicStatusPendingReview)
+		r.flagCommunityPostLocked(verdict, topic.UserID, "TOPIC", id, current)
+	}
 	r.communityTopics[id] = topic
+	topic.ModerationReasons = verdict.Reasons
 	return topic, nil
 }
 
@@ -250,11 +262,16 @@ func (r *InMemoryGrowthRepo) CreateCommunityComment(input model.CommunityComment
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	current := time.Now()
+	verdict, err := r.moderateCommunityPostLocked(strings.TrimSpace(input.UserID), "COMMENT", input.Content, current)
+	if err != nil {
+		return model.CommunityComment{}, err
+	}
 	topic, ok := r.communityTopics[input.TopicID]
 	if !ok {
 		return model.CommunityComment{}, sql.ErrNoRows
 	}
-	now := time.Now().Format(time.RFC3339)
+	now := current.Format(time.RFC3339)
 	comment := model.CommunityComment{
 		ID:              newID("cc"),
 		TopicID:         strings.TrimSpace(input.TopicID),
@@ -266,6 +283,13 @@ func (r *InMemoryGrowthRepo) CreateCommunityComment(input model.CommunityComment
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
+	if verdict.held() {
+		comment.Status = string(model.CommunityCommentStatusPendingReview)
+		r.flagCommunityPostLocked(verdict, comment.UserID, "COMMENT", comment.ID, current)
+		r.communityComments[comment.ID] = comment
+		comment.ModerationReasons = verdict.Reasons
+		return comment, nil
+	}
 	r.communityComments[comment.ID] = comment
 
 	topic.CommentCount++
@@ -305,7 +329,8 @@ func (r *InMemoryGrowthRepo) CreateCommunityReport(input model.CommunityReportCr
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	now := time.Now().Format(time.RFC3339)
+	startedAt := time.Now()
+	now := startedAt.Format(time.RFC3339)
 	report := model.CommunityReport{
 		ID:             newID("cr"),
 		ReporterUserID: strings.TrimSpace(input.ReporterUserID),
@@ -322,6 +347,7 @@ func (r *InMemoryGrowthRepo) CreateCommunityReport(input model.CommunityReportCr
 		topic.UpdatedAt = now
 		r.communityTopics[topic.ID] = topic
 	}
+	r.openReviewSLALocked(model.ReviewSLASubjectCommunityReport, report.ID, model.ReviewSLAModuleCommunity, report.TargetID, model.ReviewSLAPriorityNormal, "", startedAt)
 	return report, nil
 }
 
@@ -330,6 +356,9 @@ func (r *InMemoryGrowthRepo) CreateCommunityNotification(input model.CommunityNo
 	defer r.mu.Unlock()
 
 	userID := strings.TrimSpace(input.UserID)
+	if r.communityNotificationSuppressedLocked(userID, strings.TrimSpace(input.ActorUserID)) {
+		return nil
+	}
 	r.userMessages[userID] = append(r.userMessages[userID], model.UserMessage{
 		ID:         newID("msg"),
 		Title:      strings.TrimSpace(input.Title),
@@ -337,7 +366,9 @@ func (r *InMemoryGrowthRepo) CreateCommunityNotification(input model.CommunityNo
 		Type:       strings.TrimSpace(input.MessageType),
 		ReadStatus: "UNREAD",
 		CreatedAt:  time.Now().Format(time.RFC3339),
+		Seq:        r.nextMessageSeqLocked(),
 	})
+	r.realtime.Notify(realtime.UserTopic(userID))
 	return nil
 }
 
@@ -377,9 +408,11 @@ func (r *InMemoryGrowthRepo) AdminUpdateCommunityTopicStatus(id string, status s
 	if !ok {
 		return sql.ErrNoRows
 	}
+	now := time.Now()
 	topic.Status = strings.ToUpper(strings.TrimSpace(status))
-	topic.UpdatedAt = time.Now().Format(time.RFC3339)
+	topic.UpdatedAt = now.Format(time.RFC3339)
 	r.communityTopics[id] = topic
+	r.resolveCommunityModerationFlagsLocked("TOPIC", id, topic.Status, now)
 	return nil
 }
 
@@ -425,9 +458,20 @@ func (r *InMemoryGrowthRepo) AdminUpdateCommunityCommentStatus(id string, status
 	if !ok {
 		return sql.ErrNoRows
 	}
-	item.Status = strings.ToUpper(strings.TrimSpace(status))
-	item.UpdatedAt = time.Now().Format(time.RFC3339)
+	now := time.Now()
+	nextStatus := strings.ToUpper(strings.TrimSpace(status))
+	if item.Status == string(model.CommunityCommentStatusPendingReview) && nextStatus == string(model.CommunityCommentStatusPublished) {
+		if topic, ok := r.communityTopics[item.TopicID]; ok {
+			topic.CommentCount++
+			topic.LastActiveAt = now.Format(time.RFC3339)
+			topic.UpdatedAt = topic.LastActiveAt
+			r.communityTopics[topic.ID] = topic
+		}
+	}
+	item.Status = nextStatus
+	item.UpdatedAt = now.Format(time.RFC3339)
 	r.communityComments[id] = item
+	r.resolveCommunityModerationFlagsLocked("COMMENT", id, item.Status, now)
 	return nil
 }
 
@@ -479,7 +523,7 @@ func (r *InMemoryGrowthRepo) AdminListCommunityReports(query model.CommunityAdmi
 	return rows[start:end], total, nil
 }
 
-func (r *InMemoryGrowthRepo) AdminReviewCommunityReport(id string, status string, reviewNote string) error {
+func (r *InMemoryGrowthRepo) AdminReviewCommunityReport(id string, status string, reviewerID string, reviewNote string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -487,10 +531,14 @@ func (r *InMemoryGrowthRepo) AdminReviewCommunityReport(id string, status string
 	if !ok {
 		return sql.ErrNoRows
 	}
+	now := time.Now()
 	item.Status = strings.ToUpper(strings.TrimSpace(status))
 	item.ReviewNote = strings.TrimSpace(reviewNote)
-	item.UpdatedAt = time.Now().Format(time.RFC3339)
+	item.UpdatedAt = now.Format(time.RFC3339)
 	r.communityReports[id] = item
+	if item.Status != string(model.CommunityReportStatusPending) {
+		r.resolveReviewSLALocked(reviewSLASubjectMatcher(model.ReviewSLASubjectCommunityReport, id), reviewerID, now)
+	}
 	return nil
 }
 
@@ -149,6 +149,16 @@ LIMIT 1`, strings.TrimSpace(userID), strings.TrimSpace(userID), strings.TrimSpac
 }
 
 func (r *MySQLGrowthRepo) CreateCommunityTopic(input model.CommunityTopicCreateInput) (model.CommunityTopicDetail, error) {
+	userID := strings.TrimSpace(input.UserID)
+	verdict, _, err := r.moderateCommunityPost(userID, "TOPIC", communityModerationText(input.Title, input.Summary, input.Content, input.ReasonText, input.RiskText))
+	if err != nil {
+		return model.CommunityTopicDetail{}, err
+	}
+	status := model.CommunityTopicStatusPublished
+	if verdict.held() {
+		status = model.CommunityTopicStatusPendingReview
+	}
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return model.CommunityTopicDetail{}, err
@@ -177,7 +187,7 @@ INSERT INTO discussion_topics (
 		strings.ToUpper(strings.TrimSpace(input.TimeHorizon)),
 		strings.TrimSpace(input.ReasonText),
 		strings.TrimSpace(input.RiskText),
-		string(model.CommunityTopicStatusPublished),
+		string(status),
 		now,
 		now,
 		now,
@@ -198,10 +208,22 @@ VALUES (?, ?, ?, ?, ?, ?)`,
 		return model.CommunityTopicDetail{}, err
 	}
 
+	if verdict.held() {
+		if err := flagCommunityPostTx(tx, verdict, userID, "TOPIC", id, now); err != nil {
+			return model.CommunityTopicDetail{}, err
+		}
+		r.notifyUserMessage(userID)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return model.CommunityTopicDetail{}, err
 	}
-	return r.GetCommunityTopic(strings.TrimSpace(input.UserID), id)
+	item, err := r.GetCommunityTopic(userID, id)
+	if err != nil {
+		return model.CommunityTopicDetail{}, err
+	}
+	item.ModerationReasons = verdict.Reasons
+	return item, nil
 }
 
 func (r *MySQLGrowthRepo) ListCommunityComments(userID string, topicID string, query model.CommunityCommentListQuery) ([]model.CommunityComment, int, error) {
@@ -328,6 +350,16 @@ LIMIT ? OFFSET ?`, trimmedUserID, trimmedUserID, pageSize, offset)
 }
 
 func (r *MySQLGrowthRepo) CreateCommunityComment(input model.CommunityCommentCreateInput) (model.CommunityComment, error) {
+	userID := strings.TrimSpace(input.UserID)
+	verdict, _, err := r.moderateCommunityPost(userID, "COMMENT", input.Content)
+	if err != nil {
+		return model.CommunityComment{}, err
+	}
+	status := model.CommunityCommentStatusPublished
+	if verdict.held() {
+		status = model.CommunityCommentStatusPendingReview
+	}
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return model.CommunityComment{}, err
@@ -354,24 +386,40 @@ INSERT INTO discussion_comments (
 		nullIfBlank(input.ParentCommentID),
 		nullIfBlank(input.ReplyToUserID),
 		strings.TrimSpace(input.Content),
-		string(model.CommunityCommentStatusPublished),
+		string(status),
 		now,
 		now,
 	); err != nil {
 		return model.CommunityComment{}, err
 	}
 
-	if _, err := tx.Exec(`
-UPDATE discussion_topics
-SET comment_count = comment_count + 1, last_active_at = ?, updated_at = ?
-WHERE id = ?`, now, now, strings.TrimSpace(input.TopicID)); err != nil {
+	// Held comments only count towards the topic once an admin publishes them.
+	if verdict.held() {
+		if err := flagCommunityPostTx(tx, verdict, userID, "COMMENT", id, now); err != nil {
+			return model.CommunityComment{}, err
+		}
+		r.notifyUserMessage(userID)
+	} else if err := bumpCommunityTopicCommentCountTx(tx, strings.TrimSpace(input.TopicID), now); err != nil {
 		return model.CommunityComment{}, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return model.CommunityComment{}, err
 	}
-	return r.getCommunityCommentByID(strings.TrimSpace(input.UserID), id)
+	item, err := r.getCommunityCommentByID(userID, id)
+	if err != nil {
+		return model.CommunityComment{}, err
+	}
+	item.ModerationReasons = verdict.Reasons
+	return item, nil
+}
+
+func bumpCommunityTopicCommentCountTx(tx *sql.Tx, topicID string, now time.Time) error {
+	_, err := tx.Exec(`
+UPDATE discussion_topics
+SET comment_count = comment_count + 1, last_active_at = ?, updated_at = ?
+WHERE id = ?`, now, now, topicID)
+	return err
 }
 
 func (r *MySQLGrowthRepo) CreateCommunityReaction(input model.CommunityReactionInput) error {
@@ -479,11 +527,16 @@ VALUES (?, ?, ?, ?, ?, ?, NULL, ?, ?)`,
 	if err := tx.Commit(); err != nil {
 		return model.CommunityReport{}, err
 	}
+	_ = r.openReviewSLA(model.ReviewSLASubjectCommunityReport, item.ID, model.ReviewSLAModuleCommunity, item.TargetID, model.ReviewSLAPriorityNormal, "", now)
 	return item, nil
 }
 
 func (r *MySQLGrowthRepo) CreateCommunityNotification(input model.CommunityNotificationInput) error {
-	_, err := r.db.Exec(`
+	suppressed, err := r.communityNotificationSuppressed(strings.TrimSpace(input.UserID), strings.TrimSpace(input.ActorUserID))
+	if err != nil || suppressed {
+		return err
+	}
+	_, err = r.db.Exec(`
 INSERT INTO messages (id, user_id, title, content, type, read_status, created_at)
 VALUES (?, ?, ?, ?, ?, 'UNREAD', ?)`,
 		newID("msg"),
@@ -493,6 +546,9 @@ VALUES (?, ?, ?, ?, ?, 'UNREAD', ?)`,
 		strings.TrimSpace(input.MessageType),
 		time.Now(),
 	)
+	if err == nil {
+		r.notifyUserMessage(input.UserID)
+	}
 	return err
 }
 
@@ -573,14 +629,26 @@ LIMIT ? OFFSET ?`, append(args, pageSize, offset)...)
 }
 
 func (r *MySQLGrowthRepo) AdminUpdateCommunityTopicStatus(id string, status string) error {
-	res, err := r.db.Exec("UPDATE discussion_topics SET status = ?, updated_at = ? WHERE id = ?", strings.ToUpper(strings.TrimSpace(status)), time.Now(), strings.TrimSpace(id))
+	moderation := r.resolveCommunityModerationConfig()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	nextStatus := strings.ToUpper(strings.TrimSpace(status))
+	res, err := tx.Exec("UPDATE discussion_topics SET status = ?, updated_at = ? WHERE id = ?", nextStatus, now, strings.TrimSpace(id))
 	if err != nil {
 		return err
 	}
 	if affected, _ := res.RowsAffected(); affected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	if err := resolveCommunityModerationFlagsTx(tx, moderation, "TOPIC", strings.TrimSpace(id), nextStatus, now); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *MySQLGrowthRepo) AdminListCommunityComments(query model.CommunityAdminCommentQuery) ([]model.CommunityComment, int, error) {
@@ -660,14 +728,31 @@ LIMIT ? OFFSET ?`, append(args, pageSize, offset)...)
 }
 
 func (r *MySQLGrowthRepo) AdminUpdateCommunityCommentStatus(id string, status string) error {
-	res, err := r.db.Exec("UPDATE discussion_comments SET status = ?, updated_at = ? WHERE id = ?", strings.ToUpper(strings.TrimSpace(status)), time.Now(), strings.TrimSpace(id))
+	moderation := r.resolveCommunityModerationConfig()
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
-	if affected, _ := res.RowsAffected(); affected == 0 {
-		return sql.ErrNoRows
+	defer tx.Rollback()
+
+	var topicID, currentStatus string
+	if err := tx.QueryRow("SELECT topic_id, status FROM discussion_comments WHERE id = ? FOR UPDATE", strings.TrimSpace(id)).Scan(&topicID, &currentStatus); err != nil {
+		return err
 	}
-	return nil
+	now := time.Now()
+	nextStatus := strings.ToUpper(strings.TrimSpace(status))
+	if _, err := tx.Exec("UPDATE discussion_comments SET status = ?, updated_at = ? WHERE id = ?", nextStatus, now, strings.TrimSpace(id)); err != nil {
+		return err
+	}
+	if currentStatus == string(model.CommunityCommentStatusPendingReview) && nextStatus == string(model.CommunityCommentStatusPublished) {
+		if err := bumpCommunityTopicCommentCountTx(tx, topicID, now); err != nil {
+			return err
+		}
+	}
+	if err := resolveCommunityModerationFlagsTx(tx, moderation, "COMMENT", strings.TrimSpace(id), nextStatus, now); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *MySQLGrowthRepo) AdminListCommunityReports(query model.CommunityAdminReportQuery) ([]model.CommunityReport, int, error) {
@@ -806,14 +891,15 @@ LIMIT ? OFFSET ?`, append(args, pageSize, offset)...)
 	return items, total, rows.Err()
 }
 
-func (r *MySQLGrowthRepo) AdminReviewCommunityReport(id string, status string, reviewNote string) error {
+func (r *MySQLGrowthRepo) AdminReviewCommunityReport(id string, status string, reviewerID string, reviewNote string) error {
+	now := time.Now()
 	res, err := r.db.Exec(`
 UPDATE discussion_reports
 SET status = ?, review_note = ?, updated_at = ?
 WHERE id = ?`,
 		strings.ToUpper(strings.TrimSpace(status)),
 		strings.TrimSpace(reviewNote),
-		time.Now(),
+		now,
 		strings.TrimSpace(id),
 	)
 	if err != nil {
@@ -822,6 +908,9 @@ WHERE id = ?`,
 	if affected, _ := res.RowsAffected(); affected == 0 {
 		return sql.ErrNoRows
 	}
+	if strings.ToUpper(strings.TrimSpace(status)) != string(model.CommunityReportStatusPending) {
+		_ = r.resolveReviewSLA(model.ReviewSLASubjectCommunityReport, strings.TrimSpace(id), reviewerID, now)
+	}
 	return nil
 }
 
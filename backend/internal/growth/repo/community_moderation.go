@@ -0,0 +1,716 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const (
+	communityModerationFlagPending  = "PENDING"
+	communityModerationFlagApproved = "APPROVED"
+	communityModerationFlagRejected = "REJECTED"
+
+	communityUserBanActive = "ACTIVE"
+	communityUserBanLifted = "LIFTED"
+
+	// communitySensitiveWordTTL bounds how stale another instance's copy of
+	// the dictionary can be; writes through this repo invalidate it at once.
+	communitySensitiveWordTTL = time.Minute
+)
+
+type communityModerationConfig struct {
+	Enabled           bool
+	TopicsPerHour     int
+	CommentsPerMinute int
+	CommentsPerHour   int
+	LowTrustScore     int
+	LinkTrustScore    int
+	BanThreshold      int
+	BanWindowDays     int
+	BanDays           int
+}
+
+func defaultCommunityModerationConfig() communityModerationConfig {
+	return communityModerationConfig{
+		Enabled:           true,
+		TopicsPerHour:     5,
+		CommentsPerMinute: 3,
+		CommentsPerHour:   60,
+		LowTrustScore:     20,
+		LinkTrustScore:    60,
+		BanThreshold:      3,
+		BanWindowDays:     30,
+		BanDays:           7,
+	}
+}
+
+func (r *MySQLGrowthRepo) resolveCommunityModerationConfig() communityModerationConfig {
+	cfg := defaultCommunityModerationConfig()
+	items, _, err := r.AdminListSystemConfigs("community.moderation.", 1, 50)
+	if err != nil {
+		return cfg
+	}
+	for _, item := range items {
+		value := strings.TrimSpace(item.ConfigValue)
+		switch strings.ToLower(strings.TrimSpace(item.ConfigKey)) {
+		case "community.moderation.enabled":
+			cfg.Enabled = parseRepoConfigBool(value, cfg.Enabled)
+		case "community.moderation.topics_per_hour":
+			cfg.TopicsPerHour = parseRepoConfigInt(value, cfg.TopicsPerHour)
+		case "community.moderation.comments_per_minute":
+			cfg.CommentsPerMinute = parseRepoConfigInt(value, cfg.CommentsPerMinute)
+		case "community.moderation.comments_per_hour":
+			cfg.CommentsPerHour = parseRepoConfigInt(value, cfg.CommentsPerHour)
+		case "community.moderation.low_trust_score":
+			cfg.LowTrustScore = parseRepoConfigInt(value, cfg.LowTrustScore)
+		case "community.moderation.link_trust_score":
+			cfg.LinkTrustScore = parseRepoConfigInt(value, cfg.LinkTrustScore)
+		case "community.moderation.ban_threshold":
+			cfg.BanThreshold = parseRepoConfigInt(value, cfg.BanThreshold)
+		case "community.moderation.ban_window_days":
+			cfg.BanWindowDays = parseRepoConfigInt(value, cfg.BanWindowDays)
+		case "community.moderation.ban_days":
+			cfg.BanDays = parseRepoConfigInt(value, cfg.BanDays)
+		}
+	}
+	if cfg.BanWindowDays < 1 {
+		cfg.BanWindowDays = 1
+	}
+	if cfg.BanDays < 1 {
+		cfg.BanDays = 1
+	}
+	return cfg
+}
+
+// sensitiveWordMatcher is an Aho-Corasick automaton over the normalized
+// dictionary, so one pass over a post finds every dictionary word in it
+// regardless of how many words the dictionary holds.
+type sensitiveWordMatcher struct {
+	words []string
+	nodes []sensitiveWordNode
+}
+
+type sensitiveWordNode struct {
+	next   map[rune]int
+	fail   int
+	output []int
+}
+
+func newSensitiveWordMatcher(words []string) *sensitiveWordMatcher {
+	m := &sensitiveWordMatcher{nodes: []sensitiveWordNode{{next: map[rune]int{}}}}
+	seen := make(map[string]struct{}, len(words))
+	for _, raw := range words {
+		word := normalizeModerationText(raw)
+		if word == "" {
+			continue
+		}
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+		state := 0
+		for _, ch := range word {
+			child, ok := m.nodes[state].next[ch]
+			if !ok {
+				m.nodes = append(m.nodes, sensitiveWordNode{next: map[rune]int{}})
+				child = len(m.nodes) - 1
+				m.nodes[state].next[ch] = child
+			}
+			state = child
+		}
+		m.nodes[state].output = append(m.nodes[state].output, len(m.words))
+		m.words = append(m.words, word)
+	}
+
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for ch, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].next[ch]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if target, ok := m.nodes[fail].next[ch]; ok && target != child {
+				m.nodes[child].fail = target
+			}
+			m.nodes[child].output = append(m.nodes[child].output, m.nodes[m.nodes[child].fail].output...)
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Match returns the distinct dictionary words found in text, in the order
+// they first end in it.
+func (m *sensitiveWordMatcher) Match(text string) []string {
+	if m == nil || len(m.words) == 0 {
+		return nil
+	}
+	found := make([]string, 0)
+	hit := make(map[int]bool)
+	state := 0
+	for _, ch := range normalizeModerationText(text) {
+		for state != 0 {
+			if _, ok := m.nodes[state].next[ch]; ok {
+				break
+			}
+			state = m.nodes[state].fail
+		}
+		if next, ok := m.nodes[state].next[ch]; ok {
+			state = next
+		}
+		for _, idx := range m.nodes[state].output {
+			if !hit[idx] {
+				hit[idx] = true
+				found = append(found, m.words[idx])
+			}
+		}
+	}
+	return found
+}
+
+// foldModerationText lowercases text and maps full-width ASCII to its
+// half-width form, so "ＶＸ" and "vx" look the same to the checks.
+func foldModerationText(text string) string {
+	return strings.Map(func(ch rune) rune {
+		if ch == '　' {
+			return ' '
+		}
+		if ch >= '！' && ch <= '～' {
+			ch -= 0xfee0
+		}
+		return unicode.ToLower(ch)
+	}, text)
+}
+
+// normalizeModerationText also drops spaces, punctuation and symbols, which
+// are the usual way of splitting a banned word ("荐 股", "荐*股").
+func normalizeModerationText(text string) string {
+	return strings.Map(func(ch rune) rune {
+		if unicode.IsSpace(ch) || unicode.IsPunct(ch) || unicode.IsSymbol(ch) {
+			return -1
+		}
+		return ch
+	}, foldModerationText(text))
+}
+
+var (
+	communityPhonePattern     = regexp.MustCompile(`(?:^|\D)1[3-9](?:[\s-]?\d){9}(?:\D|$)`)
+	communityIMAccountPattern = regexp.MustCompile(`(?:微信|威信|薇信|v信|vx|wx|weixin|wechat|qq|扣扣|企鹅)\s*(?:号|id)?\s*[:=]?\s*[a-z0-9_-]{5,}|加\s*(?:微信|威信|薇信|v信|vx|qq|扣扣|群)|加\s*v(?:[^a-z]|$)`)
+	communityQRCodePattern    = regexp.MustCompile(`二维码|扫码|扫一扫|qr\s*code`)
+	communityLinkPattern      = regexp.MustCompile(`(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|cn|net|org|top|xyz|vip|cc|io|me|info|link|club)\b(?:/\S*)?`)
+)
+
+// evaluateCommunityContent runs the pre-publish checks over a post and
+// returns why it should be held for review; no reasons means it can be
+// published. Contact details and QR codes are always held since they are
+// how stock-tip scams pull users off the platform; links are only held for
+// users below link_trust_score, and everything is held below low_trust_score.
+func evaluateCommunityContent(matcher *sensitiveWordMatcher, text string, trustScore int, cfg communityModerationConfig) []model.CommunityModerationReason {
+	reasons := make([]model.CommunityModerationReason, 0)
+	for _, word := range matcher.Match(text) {
+		reasons = append(reasons, model.CommunityModerationReason{Code: model.CommunityModerationReasonSensitiveWord, Detail: word})
+	}
+	folded := foldModerationText(text)
+	if communityPhonePattern.MatchString(folded) {
+		reasons = append(reasons, model.CommunityModerationReason{Code: model.CommunityModerationReasonContactInfo, Detail: "PHONE"})
+	}
+	if communityIMAccountPattern.MatchString(folded) {
+		reasons = append(reasons, model.CommunityModerationReason{Code: model.CommunityModerationReasonContactInfo, Detail: "IM_ACCOUNT"})
+	}
+	if communityQRCodePattern.MatchString(folded) {
+		reasons = append(reasons, model.CommunityModerationReason{Code: model.CommunityModerationReasonQRCode})
+	}
+	if trustScore < cfg.LinkTrustScore {
+		if link := communityLinkPattern.FindString(folded); link != "" {
+			reasons = append(reasons, model.CommunityModerationReason{Code: model.CommunityModerationReasonExternalLink, Detail: truncateByRunes(link, 64)})
+		}
+	}
+	if trustScore < cfg.LowTrustScore {
+		reasons = append(reasons, model.CommunityModerationReason{Code: model.CommunityModerationReasonLowTrust, Detail: fmt.Sprintf("trust_score=%d", trustScore)})
+	}
+	return reasons
+}
+
+// communityTrustScore scores an author from 0 to 100: a new account starts
+// at 40, earns up to 30 more over its first 180 days and 20 more while VIP,
+// and loses 15 per violation in the ban window.
+func communityTrustScore(accountAge time.Duration, vip bool, violations int) int {
+	days := int(accountAge.Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	if days > 180 {
+		days = 180
+	}
+	score := 40 + days*30/180 - violations*15
+	if vip {
+		score += 20
+	}
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
+
+func communityModerationText(parts ...string) string {
+	return strings.Join(parts, "\n")
+}
+
+type communityModerationVerdict struct {
+	Reasons    []model.CommunityModerationReason
+	TrustScore int
+}
+
+func (v communityModerationVerdict) held() bool {
+	return len(v.Reasons) > 0
+}
+
+type communitySensitiveWordCache struct {
+	mu       sync.Mutex
+	matcher  *sensitiveWordMatcher
+	loadedAt time.Time
+}
+
+func (r *MySQLGrowthRepo) communitySensitiveWordMatcher() (*sensitiveWordMatcher, error) {
+	r.sensitiveWords.mu.Lock()
+	defer r.sensitiveWords.mu.Unlock()
+	if r.sensitiveWords.matcher != nil && time.Since(r.sensitiveWords.loadedAt) < communitySensitiveWordTTL {
+		return r.sensitiveWords.matcher, nil
+	}
+	rows, err := r.db.Query("SELECT word FROM community_sensitive_words WHERE status = 'ACTIVE'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	words := make([]string, 0)
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	r.sensitiveWords.matcher = newSensitiveWordMatcher(words)
+	r.sensitiveWords.loadedAt = time.Now()
+	return r.sensitiveWords.matcher, nil
+}
+
+func (r *MySQLGrowthRepo) invalidateCommunitySensitiveWords() {
+	r.sensitiveWords.mu.Lock()
+	r.sensitiveWords.matcher = nil
+	r.sensitiveWords.mu.Unlock()
+}
+
+// moderateCommunityPost gates a new topic or comment: banned users and users
+// over their posting rate are rejected outright, everyone else gets a
+// verdict saying whether the content must wait for review.
+func (r *MySQLGrowthRepo) moderateCommunityPost(userID string, targetType string, text string) (communityModerationVerdict, communityModerationConfig, error) {
+	cfg := r.resolveCommunityModerationConfig()
+	now := time.Now()
+	var expiresAt time.Time
+	err := r.db.QueryRow(`
+SELECT expires_at FROM community_user_bans
+WHERE user_id = ? AND status = 'ACTIVE' AND expires_at > ?
+ORDER BY expires_at DESC
+LIMIT 1`, userID, now).Scan(&expiresAt)
+	if err == nil {
+		return communityModerationVerdict{}, cfg, fmt.Errorf("%w until %s", model.ErrCommunityPostingBanned, expiresAt.Format(time.RFC3339))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return communityModerationVerdict{}, cfg, err
+	}
+	if !cfg.Enabled {
+		return communityModerationVerdict{}, cfg, nil
+	}
+
+	if targetType == "TOPIC" {
+		var lastHour int
+		if err := r.db.QueryRow("SELECT COUNT(*) FROM discussion_topics WHERE user_id = ? AND created_at >= ?", userID, now.Add(-time.Hour)).Scan(&lastHour); err != nil {
+			return communityModerationVerdict{}, cfg, err
+		}
+		if cfg.TopicsPerHour > 0 && lastHour >= cfg.TopicsPerHour {
+			return communityModerationVerdict{}, cfg, fmt.Errorf("%w: at most %d topics per hour", model.ErrCommunityPostingRateLimited, cfg.TopicsPerHour)
+		}
+	} else {
+		var lastMinute, lastHour int
+		if err := r.db.QueryRow(
+			"SELECT COALESCE(SUM(created_at >= ?), 0), COUNT(*) FROM discussion_comments WHERE user_id = ? AND created_at >= ?",
+			now.Add(-time.Minute), userID, now.Add(-time.Hour),
+		).Scan(&lastMinute, &lastHour); err != nil {
+			return communityModerationVerdict{}, cfg, err
+		}
+		if cfg.CommentsPerMinute > 0 && lastMinute >= cfg.CommentsPerMinute {
+			return communityModerationVerdict{}, cfg, fmt.Errorf("%w: at most %d comments per minute", model.ErrCommunityPostingRateLimited, cfg.CommentsPerMinute)
+		}
+		if cfg.CommentsPerHour > 0 && lastHour >= cfg.CommentsPerHour {
+			return communityModerationVerdict{}, cfg, fmt.Errorf("%w: at most %d comments per hour", model.ErrCommunityPostingRateLimited, cfg.CommentsPerHour)
+		}
+	}
+
+	var createdAt time.Time
+	var memberLevel string
+	var vipExpireAt sql.NullTime
+	err = r.db.QueryRow("SELECT created_at, member_level, vip_expire_at FROM users WHERE id = ?", userID).Scan(&createdAt, &memberLevel, &vipExpireAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		createdAt = now
+	} else if err != nil {
+		return communityModerationVerdict{}, cfg, err
+	}
+	violations, err := countCommunityViolations(r.db, userID, now.AddDate(0, 0, -cfg.BanWindowDays))
+	if err != nil {
+		return communityModerationVerdict{}, cfg, err
+	}
+	vip := strings.HasPrefix(strings.ToUpper(memberLevel), "VIP") && vipExpireAt.Valid && vipExpireAt.Time.After(now)
+	verdict := communityModerationVerdict{TrustScore: communityTrustScore(now.Sub(createdAt), vip, violations)}
+
+	matcher, err := r.communitySensitiveWordMatcher()
+	if err != nil {
+		return communityModerationVerdict{}, cfg, err
+	}
+	verdict.Reasons = evaluateCommunityContent(matcher, text, verdict.TrustScore, cfg)
+	return verdict, cfg, nil
+}
+
+// countCommunityViolations counts the posts an admin rejected. Flags still
+// PENDING are held content awaiting review and are not violations yet.
+func countCommunityViolations(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}, userID string, since time.Time) (int, error) {
+	var count int
+	err := q.QueryRow(
+		"SELECT COUNT(*) FROM community_moderation_flags WHERE user_id = ? AND status = 'REJECTED' AND created_at >= ?",
+		userID, since,
+	).Scan(&count)
+	return count, err
+}
+
+// flagCommunityPostTx records why a post was held for review.
+func flagCommunityPostTx(tx *sql.Tx, verdict communityModerationVerdict, userID string, targetType string, targetID string, now time.Time) error {
+	reasons, err := json.Marshal(verdict.Reasons)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+INSERT INTO community_moderation_flags (id, user_id, target_type, target_id, reasons, trust_score, status, created_at)
+VALUES (?, ?, ?, ?, ?, ?, 'PENDING', ?)`,
+		newID("cmf"), userID, targetType, targetID, string(reasons), verdict.TrustScore, now,
+	)
+	return err
+}
+
+// banCommunityRepeatOffenderTx bans userID once their rejected posts within
+// the ban window reach ban_threshold.
+func banCommunityRepeatOffenderTx(tx *sql.Tx, cfg communityModerationConfig, userID string, now time.Time) error {
+	if cfg.BanThreshold <= 0 {
+		return nil
+	}
+	violations, err := countCommunityViolations(tx, userID, now.AddDate(0, 0, -cfg.BanWindowDays))
+	if err != nil {
+		return err
+	}
+	if violations < cfg.BanThreshold {
+		return nil
+	}
+	var activeBans int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM community_user_bans WHERE user_id = ? AND status = 'ACTIVE' AND expires_at > ?", userID, now).Scan(&activeBans); err != nil {
+		return err
+	}
+	if activeBans > 0 {
+		return nil
+	}
+	expiresAt := now.AddDate(0, 0, cfg.BanDays)
+	if _, err := tx.Exec(`
+INSERT INTO community_user_bans (id, user_id, reason, violation_count, status, expires_at, created_by, created_at)
+VALUES (?, ?, ?, ?, 'ACTIVE', ?, 'system', ?)`,
+		newID("cub"), userID, fmt.Sprintf("%d violations in %d days", violations, cfg.BanWindowDays), violations, expiresAt, now,
+	); err != nil {
+		return err
+	}
+	return insertSystemMessageTx(tx, userID, "社区发言已被限制",
+		fmt.Sprintf("你近%d天内有%d条内容未通过审核，社区发帖和评论已暂停至%s。", cfg.BanWindowDays, violations, expiresAt.Format("2006-01-02 15:04")), now)
+}
+
+// resolveCommunityModerationFlagsTx closes the pending flags on a target once
+// an admin publishes (APPROVED) or hides or deletes (REJECTED) it. A
+// rejection is a violation, so the flagged authors are then checked against
+// ban_threshold.
+func resolveCommunityModerationFlagsTx(tx *sql.Tx, cfg communityModerationConfig, targetType string, targetID string, status string, now time.Time) error {
+	result := ""
+	switch status {
+	case string(model.CommunityTopicStatusPublished):
+		result = communityModerationFlagApproved
+	case string(model.CommunityTopicStatusHidden), string(model.CommunityTopicStatusDeleted):
+		result = communityModerationFlagRejected
+	default:
+		return nil
+	}
+	userIDs := make([]string, 0, 1)
+	if result == communityModerationFlagRejected {
+		rows, err := tx.Query(
+			"SELECT DISTINCT user_id FROM community_moderation_flags WHERE target_type = ? AND target_id = ? AND status = 'PENDING'",
+			targetType, targetID,
+		)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var userID string
+			if err := rows.Scan(&userID); err != nil {
+				rows.Close()
+				return err
+			}
+			userIDs = append(userIDs, userID)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+	}
+	if _, err := tx.Exec(
+		"UPDATE community_moderation_flags SET status = ?, reviewed_at = ? WHERE target_type = ? AND target_id = ? AND status = 'PENDING'",
+		result, now, targetType, targetID,
+	); err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := banCommunityRepeatOffenderTx(tx, cfg, userID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MySQLGrowthRepo) AdminListCommunitySensitiveWords(status string, category string, keyword string, page int, pageSize int) ([]model.CommunitySensitiveWord, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 3)
+	args := []interface{}{}
+	if strings.TrimSpace(status) != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(status)))
+	}
+	if strings.TrimSpace(category) != "" {
+		filters = append(filters, "category = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(category)))
+	}
+	if strings.TrimSpace(keyword) != "" {
+		filters = append(filters, "word LIKE ?")
+		args = append(args, "%"+strings.TrimSpace(keyword)+"%")
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_sensitive_words"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, word, category, status, created_by, created_at, updated_at
+FROM community_sensitive_words`+where+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.CommunitySensitiveWord, 0)
+	for rows.Next() {
+		var item model.CommunitySensitiveWord
+		var createdBy sql.NullString
+		var createdAt, updatedAt time.Time
+		if err := rows.Scan(&item.ID, &item.Word, &item.Category, &item.Status, &createdBy, &createdAt, &updatedAt); err != nil {
+			return nil, 0, err
+		}
+		item.CreatedBy = createdBy.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminCreateCommunitySensitiveWord(word string, category string, operator string) (string, error) {
+	id := newID("csw")
+	now := time.Now()
+	if _, err := r.db.Exec(`
+INSERT INTO community_sensitive_words (id, word, category, status, created_by, created_at, updated_at)
+VALUES (?, ?, ?, 'ACTIVE', ?, ?, ?)`,
+		id, strings.TrimSpace(word), strings.ToUpper(strings.TrimSpace(category)), nullIfBlank(operator), now, now,
+	); err != nil {
+		return "", err
+	}
+	r.invalidateCommunitySensitiveWords()
+	return id, nil
+}
+
+func (r *MySQLGrowthRepo) AdminUpdateCommunitySensitiveWordStatus(id string, status string) error {
+	res, err := r.db.Exec("UPDATE community_sensitive_words SET status = ?, updated_at = ? WHERE id = ?", strings.ToUpper(strings.TrimSpace(status)), time.Now(), strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+	r.invalidateCommunitySensitiveWords()
+	return nil
+}
+
+func (r *MySQLGrowthRepo) AdminListCommunityModerationFlags(query model.CommunityModerationFlagQuery) ([]model.CommunityModerationFlag, int, error) {
+	offset := (query.Page - 1) * query.PageSize
+	filters := make([]string, 0, 3)
+	args := []interface{}{}
+	if strings.TrimSpace(query.Status) != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(query.Status)))
+	}
+	if strings.TrimSpace(query.TargetType) != "" {
+		filters = append(filters, "target_type = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(query.TargetType)))
+	}
+	if strings.TrimSpace(query.UserID) != "" {
+		filters = append(filters, "user_id = ?")
+		args = append(args, strings.TrimSpace(query.UserID))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_moderation_flags"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, query.PageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, user_id, target_type, target_id, reasons, trust_score, status, created_at, reviewed_at
+FROM community_moderation_flags`+where+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.CommunityModerationFlag, 0)
+	for rows.Next() {
+		var item model.CommunityModerationFlag
+		var reasons string
+		var createdAt time.Time
+		var reviewedAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.UserID, &item.TargetType, &item.TargetID, &reasons, &item.TrustScore, &item.Status, &createdAt, &reviewedAt); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal([]byte(reasons), &item.Reasons); err != nil {
+			return nil, 0, err
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		if reviewedAt.Valid {
+			item.ReviewedAt = reviewedAt.Time.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminListCommunityUserBans(status string, userID string, page int, pageSize int) ([]model.CommunityUserBan, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 2)
+	args := []interface{}{}
+	if strings.TrimSpace(status) != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(status)))
+	}
+	if strings.TrimSpace(userID) != "" {
+		filters = append(filters, "user_id = ?")
+		args = append(args, strings.TrimSpace(userID))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_user_bans"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, user_id, reason, violation_count, status, expires_at, created_by, lifted_by, lifted_at, created_at
+FROM community_user_bans`+where+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.CommunityUserBan, 0)
+	for rows.Next() {
+		var item model.CommunityUserBan
+		var liftedBy sql.NullString
+		var expiresAt, createdAt time.Time
+		var liftedAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.UserID, &item.Reason, &item.ViolationCount, &item.Status, &expiresAt, &item.CreatedBy, &liftedBy, &liftedAt, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		item.ExpiresAt = expiresAt.Format(time.RFC3339)
+		item.LiftedBy = liftedBy.String
+		if liftedAt.Valid {
+			item.LiftedAt = liftedAt.Time.Format(time.RFC3339)
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminCreateCommunityUserBan(userID string, days int, reason string, operator string) (string, error) {
+	id := newID("cub")
+	now := time.Now()
+	if _, err := r.db.Exec(`
+INSERT INTO community_user_bans (id, user_id, reason, violation_count, status, expires_at, created_by, created_at)
+VALUES (?, ?, ?, 0, 'ACTIVE', ?, ?, ?)`,
+		id, strings.TrimSpace(userID), truncateByRunes(strings.TrimSpace(reason), 255), now.AddDate(0, 0, days), strings.TrimSpace(operator), now,
+	); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *MySQLGrowthRepo) AdminLiftCommunityUserBan(id string, operator string) error {
+	res, err := r.db.Exec(
+		"UPDATE community_user_bans SET status = 'LIFTED', lifted_by = ?, lifted_at = ? WHERE id = ? AND status = 'ACTIVE'",
+		strings.TrimSpace(operator), time.Now(), strings.TrimSpace(id),
+	)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.ErrCommunityUserBanNotFound
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// The in-memory repo has no user accounts, so every author is scored as a
+// brand-new non-VIP user and moderation always runs with the default
+// community.moderation.* settings.
+func (r *InMemoryGrowthRepo) seedCommunitySensitiveWords() {
+	now := time.Now().Format(time.RFC3339)
+	for i, word := range []string{"荐股", "带单", "内幕消息", "稳赚不赔", "保本保收益", "拉你进群"} {
+		id := fmt.Sprintf("csw_seed_%03d", i+1)
+		r.communityWords[id] = model.CommunitySensitiveWord{ID: id, Word: word, Category: "STOCK_TIP", Status: "ACTIVE", CreatedBy: "system", CreatedAt: now, UpdatedAt: now}
+	}
+}
+
+func (r *InMemoryGrowthRepo) moderateCommunityPostLocked(userID string, targetType string, text string, now time.Time) (communityModerationVerdict, error) {
+	cfg := defaultCommunityModerationConfig()
+	for _, ban := range r.communityBans {
+		expiresAt, _ := time.Parse(time.RFC3339, ban.ExpiresAt)
+		if ban.UserID == userID && ban.Status == communityUserBanActive && expiresAt.After(now) {
+			return communityModerationVerdict{}, fmt.Errorf("%w until %s", model.ErrCommunityPostingBanned, ban.ExpiresAt)
+		}
+	}
+
+	if targetType == "TOPIC" {
+		lastHour := 0
+		for _, item := range r.communityTopics {
+			if createdAt, err := time.Parse(time.RFC3339, item.CreatedAt); err == nil && item.UserID == userID && !createdAt.Before(now.Add(-time.Hour)) {
+				lastHour++
+			}
+		}
+		if lastHour >= cfg.TopicsPerHour {
+			return communityModerationVerdict{}, fmt.Errorf("%w: at most %d topics per hour", model.ErrCommunityPostingRateLimited, cfg.TopicsPerHour)
+		}
+	} else {
+		lastMinute, lastHour := 0, 0
+		for _, item := range r.communityComments {
+			createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+			if err != nil || item.UserID != userID || createdAt.Before(now.Add(-time.Hour)) {
+				continue
+			}
+			lastHour++
+			if !createdAt.Before(now.Add(-time.Minute)) {
+				lastMinute++
+			}
+		}
+		if lastMinute >= cfg.CommentsPerMinute {
+			return communityModerationVerdict{}, fmt.Errorf("%w: at most %d comments per minute", model.ErrCommunityPostingRateLimited, cfg.CommentsPerMinute)
+		}
+		if lastHour >= cfg.CommentsPerHour {
+			return communityModerationVerdict{}, fmt.Errorf("%w: at most %d comments per hour", model.ErrCommunityPostingRateLimited, cfg.CommentsPerHour)
+		}
+	}
+
+	words := make([]string, 0, len(r.communityWords))
+	for _, item := range r.communityWords {
+		if item.Status == "ACTIVE" {
+			words = append(words, item.Word)
+		}
+	}
+	verdict := communityModerationVerdict{TrustScore: communityTrustScore(0, false, r.countCommunityViolationsLocked(userID, now.AddDate(0, 0, -cfg.BanWindowDays)))}
+	verdict.Reasons = evaluateCommunityContent(newSensitiveWordMatcher(words), text, verdict.TrustScore, cfg)
+	return verdict, nil
+}
+
+func (r *InMemoryGrowthRepo) countCommunityViolationsLocked(userID string, since time.Time) int {
+	count := 0
+	for _, item := range r.communityFlags {
+		createdAt, _ := time.Parse(time.RFC3339, item.CreatedAt)
+		if item.UserID == userID && item.Status == communityModerationFlagRejected && !createdAt.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *InMemoryGrowthRepo) flagCommunityPostLocked(verdict communityModerationVerdict, userID string, targetType string, targetID string, now time.Time) {
+	r.communityFlags = append(r.communityFlags, model.CommunityModerationFlag{
+		ID:         newID("cmf"),
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reasons:    verdict.Reasons,
+		TrustScore: verdict.TrustScore,
+		Status:     communityModerationFlagPending,
+		CreatedAt:  now.Format(time.RFC3339),
+	})
+}
+
+func (r *InMemoryGrowthRepo) banCommunityRepeatOffenderLocked(userID string, now time.Time) {
+	cfg := defaultCommunityModerationConfig()
+	violations := r.countCommunityViolationsLocked(userID, now.AddDate(0, 0, -cfg.BanWindowDays))
+	if violations < cfg.BanThreshold {
+		return
+	}
+	for _, ban := range r.communityBans {
+		expiresAt, _ := time.Parse(time.RFC3339, ban.ExpiresAt)
+		if ban.UserID == userID && ban.Status == communityUserBanActive && expiresAt.After(now) {
+			return
+		}
+	}
+	r.communityBans = append(r.communityBans, model.CommunityUserBan{
+		ID:             newID("cub"),
+		UserID:         userID,
+		Reason:         fmt.Sprintf("%d violations in %d days", violations, cfg.BanWindowDays),
+		ViolationCount: violations,
+		Status:         communityUserBanActive,
+		ExpiresAt:      now.AddDate(0, 0, cfg.BanDays).Format(time.RFC3339),
+		CreatedBy:      "system",
+		CreatedAt:      now.Format(time.RFC3339),
+	})
+}
+
+func (r *InMemoryGrowthRepo) resolveCommunityModerationFlagsLocked(targetType string, targetID string, status string, now time.Time) {
+	result := ""
+	switch status {
+	case string(model.CommunityTopicStatusPublished):
+		result = communityModerationFlagApproved
+	case string(model.CommunityTopicStatusHidden), string(model.CommunityTopicStatusDeleted):
+		result = communityModerationFlagRejected
+	default:
+		return
+	}
+	userIDs := make([]string, 0, 1)
+	for i, item := range r.communityFlags {
+		if item.TargetType == targetType && item.TargetID == targetID && item.Status == communityModerationFlagPending {
+			r.communityFlags[i].Status = result
+			r.communityFlags[i].ReviewedAt = now.Format(time.RFC3339)
+			userIDs = append(userIDs, item.UserID)
+		}
+	}
+	if result != communityModerationFlagRejected {
+		return
+	}
+	for _, userID := range userIDs {
+		r.banCommunityRepeatOffenderLocked(userID, now)
+	}
+}
+
+func (r *InMemoryGrowthRepo) AdminListCommunitySensitiveWords(status string, category string, keyword string, page int, pageSize int) ([]model.CommunitySensitiveWord, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.CommunitySensitiveWord, 0, len(r.communityWords))
+	for _, item := range r.communityWords {
+		if status != "" && !strings.EqualFold(item.Status, status) {
+			continue
+		}
+		if category != "" && !strings.EqualFold(item.Category, category) {
+			continue
+		}
+		if keyword != "" && !strings.Contains(item.Word, strings.TrimSpace(keyword)) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID > items[j].ID
+	})
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunitySensitiveWord{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCreateCommunitySensitiveWord(word string, category string, operator string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	word = strings.TrimSpace(word)
+	for _, item := range r.communityWords {
+		if item.Word == word {
+			return "", fmt.Errorf("Error 1062: Duplicate entry '%s' for key 'uk_community_sensitive_words_word'", word)
+		}
+	}
+	now := time.Now().Format(time.RFC3339)
+	item := model.CommunitySensitiveWord{
+		ID:        newID("csw"),
+		Word:      word,
+		Category:  strings.ToUpper(strings.TrimSpace(category)),
+		Status:    "ACTIVE",
+		CreatedBy: strings.TrimSpace(operator),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.communityWords[item.ID] = item
+	return item.ID, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminUpdateCommunitySensitiveWordStatus(id string, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.communityWords[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	item.Status = strings.ToUpper(strings.TrimSpace(status))
+	item.UpdatedAt = time.Now().Format(time.RFC3339)
+	r.communityWords[id] = item
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListCommunityModerationFlags(query model.CommunityModerationFlagQuery) ([]model.CommunityModerationFlag, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.CommunityModerationFlag, 0)
+	for i := len(r.communityFlags) - 1; i >= 0; i-- {
+		item := r.communityFlags[i]
+		if query.Status != "" && !strings.EqualFold(item.Status, query.Status) {
+			continue
+		}
+		if query.TargetType != "" && !strings.EqualFold(item.TargetType, query.TargetType) {
+			continue
+		}
+		if query.UserID != "" && item.UserID != query.UserID {
+			continue
+		}
+		items = append(items, item)
+	}
+	total := len(items)
+	start, end := paginateBounds(query.Page, query.PageSize, total)
+	if start >= total {
+		return []model.CommunityModerationFlag{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListCommunityUserBans(status string, userID string, page int, pageSize int) ([]model.CommunityUserBan, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.CommunityUserBan, 0)
+	for i := len(r.communityBans) - 1; i >= 0; i-- {
+		item := r.communityBans[i]
+		if (status == "" || strings.EqualFold(item.Status, status)) && (userID == "" || item.UserID == userID) {
+			items = append(items, item)
+		}
+	}
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunityUserBan{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCreateCommunityUserBan(userID string, days int, reason string, operator string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	item := model.CommunityUserBan{
+		ID:        newID("cub"),
+		UserID:    strings.TrimSpace(userID),
+		Reason:    strings.TrimSpace(reason),
+		Status:    communityUserBanActive,
+		ExpiresAt: now.AddDate(0, 0, days).Format(time.RFC3339),
+		CreatedBy: strings.TrimSpace(operator),
+		CreatedAt: now.Format(time.RFC3339),
+	}
+	r.communityBans = append(r.communityBans, item)
+	return item.ID, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminLiftCommunityUserBan(id string, operator string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, item := range r.communityBans {
+		if item.ID == id && item.Status == communityUserBanActive {
+			r.communityBans[i].Status = communityUserBanLifted
+			r.communityBans[i].LiftedBy = strings.TrimSpace(operator)
+			r.communityBans[i].LiftedAt = time.Now().Format(time.RFC3339)
+			return nil
+		}
+	}
+	return model.ErrCommunityUserBanNotFound
+}
@@ -0,0 +1,258 @@
+package repo
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestSensitiveWordMatcherFindsOverlappingAndSplitWords(t *testing.T) {
+	matcher := newSensitiveWordMatcher([]string{"内幕", "内幕消息", "幕消", "荐股", "ＶＩＰ群", ""})
+
+	got := matcher.Match("独家内幕消息，老师荐 * 股，进vip群")
+	want := []string{"内幕", "幕消", "内幕消息", "荐股", "vip群"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Match() = %v, want %v", got, want)
+	}
+	if got := matcher.Match("量能继续放大，先观察"); len(got) != 0 {
+		t.Fatalf("expected clean text to match nothing, got %v", got)
+	}
+}
+
+func TestEvaluateCommunityContentFlagsScamPatternsByTrust(t *testing.T) {
+	cfg := defaultCommunityModerationConfig()
+	matcher := newSensitiveWordMatcher(nil)
+	codes := func(reasons []model.CommunityModerationReason) []string {
+		out := make([]string, 0, len(reasons))
+		for _, reason := range reasons {
+			out = append(out, reason.Code+":"+reason.Detail)
+		}
+		return out
+	}
+
+	cases := []struct {
+		name  string
+		text  string
+		trust int
+		want  []string
+	}{
+		{"clean", "600519 站上 1800，先看量能", 40, []string{}},
+		{"phone", "咨询请打 138-1234-5678", 90, []string{"CONTACT_INFO:PHONE"}},
+		{"im account", "加ＶＸ：abc_12345 免费领牛股", 90, []string{"CONTACT_INFO:IM_ACCOUNT"}},
+		{"qr code", "扫码进群", 90, []string{"QR_CODE:"}},
+		{"link below trust", "详情见 https://t.me/tips", 40, []string{"EXTERNAL_LINK:https://t.me/tips"}},
+		{"link trusted", "详情见 https://t.me/tips", 90, []string{}},
+		{"low trust", "先观察", 10, []string{"LOW_TRUST:trust_score=10"}},
+	}
+	for _, tc := range cases {
+		if got := codes(evaluateCommunityContent(matcher, tc.text, tc.trust, cfg)); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: evaluateCommunityContent() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if got := communityTrustScore(365*24*time.Hour, true, 0); got != 90 {
+		t.Fatalf("expected veteran VIP trust 90, got %d", got)
+	}
+	if got := communityTrustScore(0, false, 3); got != 0 {
+		t.Fatalf("expected new account with 3 violations to floor at 0, got %d", got)
+	}
+}
+
+func TestInMemoryCommunityModerationHoldsContentAndBansRepeatOffenders(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+
+	topic, err := repo.CreateCommunityTopic(model.CommunityTopicCreateInput{
+		UserID: "u_scam", Title: "明天涨停", Content: "内幕消息，加微信 abc12345 进群", TopicType: "STOCK", Stance: "BULLISH",
+		ReasonText: "消息面", RiskText: "无", TargetType: "STOCK", TargetID: "600519",
+	})
+	if err != nil {
+		t.Fatalf("CreateCommunityTopic() error = %v", err)
+	}
+	if topic.Status != string(model.CommunityTopicStatusPendingReview) || len(topic.ModerationReasons) != 2 {
+		t.Fatalf("expected topic held with 2 reasons, got %s %+v", topic.Status, topic.ModerationReasons)
+	}
+
+	before := repo.communityTopics["ct_demo_001"].CommentCount
+	comment, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_scam", TopicID: "ct_demo_001", Content: "扫码领取荐股名单"})
+	if err != nil {
+		t.Fatalf("CreateCommunityComment() error = %v", err)
+	}
+	if comment.Status != string(model.CommunityCommentStatusPendingReview) || repo.communityTopics["ct_demo_001"].CommentCount != before {
+		t.Fatalf("expected held comment not to count, got %s count=%d", comment.Status, repo.communityTopics["ct_demo_001"].CommentCount)
+	}
+	if err := repo.AdminUpdateCommunityCommentStatus(comment.ID, "PUBLISHED"); err != nil {
+		t.Fatalf("AdminUpdateCommunityCommentStatus() error = %v", err)
+	}
+	if repo.communityTopics["ct_demo_001"].CommentCount != before+1 {
+		t.Fatal("expected approved comment to count towards the topic")
+	}
+	flags, _, _ := repo.AdminListCommunityModerationFlags(model.CommunityModerationFlagQuery{TargetType: "COMMENT", Page: 1, PageSize: 20})
+	if len(flags) != 1 || flags[0].Status != communityModerationFlagApproved {
+		t.Fatalf("expected approved flag, got %+v", flags)
+	}
+
+	// The approved comment does not count. Rejecting the topic and two more
+	// held comments reaches the threshold.
+	if err := repo.AdminUpdateCommunityTopicStatus(topic.ID, "HIDDEN"); err != nil {
+		t.Fatalf("AdminUpdateCommunityTopicStatus() error = %v", err)
+	}
+	held := make([]string, 0, 2)
+	for _, content := range []string{"稳赚不赔", "带单稳赚"} {
+		item, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_scam", TopicID: "ct_demo_001", Content: content})
+		if err != nil {
+			t.Fatalf("CreateCommunityComment(%q) error = %v", content, err)
+		}
+		held = append(held, item.ID)
+	}
+	if bans, _, _ := repo.AdminListCommunityUserBans("ACTIVE", "u_scam", 1, 20); len(bans) != 0 {
+		t.Fatalf("expected held comments awaiting review not to ban, got %+v", bans)
+	}
+	for _, id := range held {
+		if err := repo.AdminUpdateCommunityCommentStatus(id, "DELETED"); err != nil {
+			t.Fatalf("AdminUpdateCommunityCommentStatus(%s) error = %v", id, err)
+		}
+	}
+	bans, _, _ := repo.AdminListCommunityUserBans("ACTIVE", "u_scam", 1, 20)
+	if len(bans) != 1 || bans[0].ViolationCount != 3 || bans[0].CreatedBy != "system" {
+		t.Fatalf("expected automatic ban after 3 rejected posts, got %+v", bans)
+	}
+	if _, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_scam", TopicID: "ct_demo_001", Content: "正常讨论"}); !errors.Is(err, model.ErrCommunityPostingBanned) {
+		t.Fatalf("expected banned user to be rejected, got %v", err)
+	}
+
+	if err := repo.AdminLiftCommunityUserBan(bans[0].ID, "admin_001"); err != nil {
+		t.Fatalf("AdminLiftCommunityUserBan() error = %v", err)
+	}
+	if err := repo.AdminLiftCommunityUserBan(bans[0].ID, "admin_001"); !errors.Is(err, model.ErrCommunityUserBanNotFound) {
+		t.Fatalf("expected lifted ban to be gone, got %v", err)
+	}
+}
+
+func TestInMemoryCommunityModerationPendingHoldsDoNotBan(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	threshold := defaultCommunityModerationConfig().BanThreshold
+	for i := 0; i < threshold; i++ {
+		comment, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_held", TopicID: "ct_demo_001", Content: "荐股群"})
+		if err != nil {
+			t.Fatalf("comment %d error = %v", i, err)
+		}
+		if comment.Status != string(model.CommunityCommentStatusPendingReview) {
+			t.Fatalf("expected comment %d to be held, got %s", i, comment.Status)
+		}
+	}
+	if bans, _, _ := repo.AdminListCommunityUserBans("", "u_held", 1, 20); len(bans) != 0 {
+		t.Fatalf("expected no ban while held posts await review, got %+v", bans)
+	}
+	if got := repo.countCommunityViolationsLocked("u_held", time.Now().AddDate(0, 0, -30)); got != 0 {
+		t.Fatalf("expected pending flags not to count as violations, got %d", got)
+	}
+}
+
+func TestInMemoryCommunityModerationRateLimitsComments(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	limit := defaultCommunityModerationConfig().CommentsPerMinute
+	for i := 0; i < limit; i++ {
+		if _, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_fast", TopicID: "ct_demo_001", Content: "同意"}); err != nil {
+			t.Fatalf("comment %d error = %v", i, err)
+		}
+	}
+	if _, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_fast", TopicID: "ct_demo_001", Content: "同意"}); !errors.Is(err, model.ErrCommunityPostingRateLimited) {
+		t.Fatalf("expected rate limit after %d comments, got %v", limit, err)
+	}
+}
+
+func TestMySQLCreateCommunityCommentHoldsFlaggedContent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT expires_at FROM community_user_bans")).
+		WithArgs("u_1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"expires_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM discussion_comments WHERE user_id = ? AND created_at >= ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"last_minute", "last_hour"}).AddRow(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT created_at, member_level, vip_expire_at FROM users WHERE id = ?")).
+		WithArgs("u_1").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "member_level", "vip_expire_at"}).AddRow(now.AddDate(-1, 0, 0), "VIP1", now.AddDate(0, 1, 0)))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM community_moderation_flags WHERE user_id = ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT word FROM community_sensitive_words WHERE status = 'ACTIVE'")).
+		WillReturnRows(sqlmock.NewRows([]string{"word"}).AddRow("荐股"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM discussion_topics WHERE id = ?")).
+		WithArgs("ct_1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO discussion_comments")).
+		WithArgs(sqlmock.AnyArg(), "ct_1", "u_1", nil, nil, "老师荐股，加微信abc12345", "PENDING_REVIEW", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO community_moderation_flags")).
+		WithArgs(sqlmock.AnyArg(), "u_1", "COMMENT", sqlmock.AnyArg(), `[{"code":"SENSITIVE_WORD","detail":"荐股"},{"code":"CONTACT_INFO","detail":"IM_ACCOUNT"}]`, 90, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM discussion_comments c")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "topic_id", "user_id", "parent_comment_id", "reply_to_user_id", "content", "status", "like_count", "created_at", "updated_at", "liked_by_me",
+		}).AddRow("cc_1", "ct_1", "u_1", "", "", "老师荐股，加微信abc12345", "PENDING_REVIEW", 0, now, now, 0))
+
+	repo := &MySQLGrowthRepo{db: db}
+	comment, err := repo.CreateCommunityComment(model.CommunityCommentCreateInput{UserID: "u_1", TopicID: "ct_1", Content: "老师荐股，加微信abc12345"})
+	if err != nil {
+		t.Fatalf("CreateCommunityComment() error = %v", err)
+	}
+	if comment.Status != "PENDING_REVIEW" || len(comment.ModerationReasons) != 2 {
+		t.Fatalf("expected held comment with reasons, got %+v", comment)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminUpdateCommunityTopicStatusBansOnRejection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE discussion_topics SET status = ?")).
+		WithArgs("HIDDEN", sqlmock.AnyArg(), "ct_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT user_id FROM community_moderation_flags")).
+		WithArgs("TOPIC", "ct_1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("u_1"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE community_moderation_flags SET status = ?")).
+		WithArgs(communityModerationFlagRejected, sqlmock.AnyArg(), "TOPIC", "ct_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM community_moderation_flags WHERE user_id = ? AND status = 'REJECTED'")).
+		WithArgs("u_1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM community_user_bans")).
+		WithArgs("u_1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO community_user_bans")).
+		WithArgs(sqlmock.AnyArg(), "u_1", "3 violations in 30 days", 3, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	if err := repo.AdminUpdateCommunityTopicStatus("ct_1", "hidden"); err != nil {
+		t.Fatalf("AdminUpdateCommunityTopicStatus() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,418 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// strategyEngineCrowdSentimentLookbackDays lets a strategy context fall back
+// to the latest sentiment row when the index has not run for the trade date.
+const strategyEngineCrowdSentimentLookbackDays = 3
+
+type communitySentimentConfig struct {
+	Enabled      bool
+	WindowDays   int
+	ExtremeScore float64
+	MinTopics    int
+}
+
+func defaultCommunitySentimentConfig() communitySentimentConfig {
+	return communitySentimentConfig{
+		Enabled:      true,
+		WindowDays:   5,
+		ExtremeScore: 0.6,
+		MinTopics:    5,
+	}
+}
+
+func (r *MySQLGrowthRepo) resolveCommunitySentimentConfig() communitySentimentConfig {
+	cfg := defaultCommunitySentimentConfig()
+	items, _, err := r.AdminListSystemConfigs("community.sentiment.", 1, 50)
+	if err != nil {
+		return cfg
+	}
+	for _, item := range items {
+		value := strings.TrimSpace(item.ConfigValue)
+		switch strings.ToLower(strings.TrimSpace(item.ConfigKey)) {
+		case "community.sentiment.enabled":
+			cfg.Enabled = parseRepoConfigBool(value, cfg.Enabled)
+		case "community.sentiment.window_days":
+			cfg.WindowDays = parseRepoConfigInt(value, cfg.WindowDays)
+		case "community.sentiment.extreme_score":
+			cfg.ExtremeScore = parseRepoConfigFloat(value, cfg.ExtremeScore)
+		case "community.sentiment.min_topics":
+			cfg.MinTopics = parseRepoConfigInt(value, cfg.MinTopics)
+		}
+	}
+	if cfg.WindowDays < 1 {
+		cfg.WindowDays = 1
+	}
+	if cfg.ExtremeScore <= 0 || cfg.ExtremeScore > 1 {
+		cfg.ExtremeScore = defaultCommunitySentimentConfig().ExtremeScore
+	}
+	return cfg
+}
+
+type communitySentimentSample struct {
+	AssetClass    string
+	Symbol        string
+	UserID        string
+	Stance        string
+	LikeCount     int
+	FavoriteCount int
+	TrustScore    int
+}
+
+// communityLinkedSymbol maps a topic link onto the key the strategy
+// contexts use: a canonical instrument key for stocks and the product code
+// for futures, so RB2405 and RB2410 topics feed the same RB reading.
+func communityLinkedSymbol(targetType string, targetID string) (string, string) {
+	switch strings.ToUpper(strings.TrimSpace(targetType)) {
+	case marketAssetClassStock:
+		return marketAssetClassStock, canonicalStockInstrumentKey(targetID)
+	case marketAssetClassFutures:
+		return marketAssetClassFutures, deriveMarketProductKey(marketAssetClassFutures, targetID, "")
+	}
+	return "", ""
+}
+
+// communitySentimentTopicWeight grows with the log of reactions, so a viral
+// topic counts for more without drowning everyone else, and scales with the
+// author's trust so throwaway accounts barely move the index.
+func communitySentimentTopicWeight(likeCount int, favoriteCount int, trustScore int) float64 {
+	reactions := float64(likeCount + 2*favoriteCount)
+	if reactions < 0 {
+		reactions = 0
+	}
+	return (1 + math.Log1p(reactions)) * float64(trustScore) / 100
+}
+
+// aggregateCommunitySentiment folds topic samples into one row per symbol.
+// Each author's topics on a symbol are averaged first, so one author posting
+// the same call repeatedly counts as a single voice.
+func aggregateCommunitySentiment(samples []communitySentimentSample, statDate string, cfg communitySentimentConfig) []model.CommunitySentimentDaily {
+	type authorTally struct {
+		bullish float64
+		bearish float64
+		watch   float64
+		topics  int
+	}
+	type symbolTally struct {
+		assetClass string
+		symbol     string
+		topics     int
+		authors    map[string]*authorTally
+	}
+	tallies := make(map[string]*symbolTally)
+	for _, sample := range samples {
+		if sample.AssetClass == "" || sample.Symbol == "" {
+			continue
+		}
+		key := sample.AssetClass + "|" + sample.Symbol
+		tally, ok := tallies[key]
+		if !ok {
+			tally = &symbolTally{assetClass: sample.AssetClass, symbol: sample.Symbol, authors: map[string]*authorTally{}}
+			tallies[key] = tally
+		}
+		author, ok := tally.authors[sample.UserID]
+		if !ok {
+			author = &authorTally{}
+			tally.authors[sample.UserID] = author
+		}
+		weight := communitySentimentTopicWeight(sample.LikeCount, sample.FavoriteCount, sample.TrustScore)
+		switch strings.ToUpper(sample.Stance) {
+		case string(model.CommunityStanceBullish):
+			author.bullish += weight
+		case string(model.CommunityStanceBearish):
+			author.bearish += weight
+		default:
+			author.watch += weight
+		}
+		author.topics++
+		tally.topics++
+	}
+
+	items := make([]model.CommunitySentimentDaily, 0, len(tallies))
+	for _, tally := range tallies {
+		item := model.CommunitySentimentDaily{
+			AssetClass:  tally.assetClass,
+			Symbol:      tally.symbol,
+			StatDate:    statDate,
+			WindowDays:  cfg.WindowDays,
+			TopicCount:  tally.topics,
+			AuthorCount: len(tally.authors),
+		}
+		for _, author := range tally.authors {
+			n := float64(author.topics)
+			item.BullishWeight += author.bullish / n
+			item.BearishWeight += author.bearish / n
+			item.WatchWeight += author.watch / n
+		}
+		if total := item.BullishWeight + item.BearishWeight + item.WatchWeight; total > 0 {
+			item.SentimentScore = roundTo((item.BullishWeight-item.BearishWeight)/total, 4)
+		}
+		item.BullishWeight = roundTo(item.BullishWeight, 4)
+		item.BearishWeight = roundTo(item.BearishWeight, 4)
+		item.WatchWeight = roundTo(item.WatchWeight, 4)
+		if item.TopicCount >= cfg.MinTopics {
+			if item.SentimentScore >= cfg.ExtremeScore {
+				item.Extreme = model.CommunitySentimentExtremeEuphoria
+			} else if item.SentimentScore <= -cfg.ExtremeScore {
+				item.Extreme = model.CommunitySentimentExtremePanic
+			}
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].AssetClass != items[j].AssetClass {
+			return items[i].AssetClass < items[j].AssetClass
+		}
+		return items[i].Symbol < items[j].Symbol
+	})
+	return items
+}
+
+// communitySentimentQuerySymbols normalizes an admin symbol filter; without an
+// asset class it tries both the stock and the futures reading of the input.
+func communitySentimentQuerySymbols(assetClass string, raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	assetClass = strings.ToUpper(strings.TrimSpace(assetClass))
+	if assetClass != "" {
+		if _, symbol := communityLinkedSymbol(assetClass, raw); symbol != "" {
+			return []string{symbol}
+		}
+		return nil
+	}
+	_, stock := communityLinkedSymbol(marketAssetClassStock, raw)
+	_, futures := communityLinkedSymbol(marketAssetClassFutures, raw)
+	return compactStrings([]string{stock, futures})
+}
+
+func parseCommunitySentimentDate(raw string) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(raw), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid stat_date %q: %w", raw, err)
+	}
+	return day, nil
+}
+
+func (r *MySQLGrowthRepo) loadCommunitySentimentSamples(statDay time.Time, cfg communitySentimentConfig, banWindowDays int) ([]communitySentimentSample, error) {
+	now := time.Now()
+	rows, err := r.db.Query(`
+SELECT l.target_type, l.target_id, t.user_id, t.stance, t.like_count, t.favorite_count,
+  u.created_at, u.member_level, u.vip_expire_at,
+  (SELECT COUNT(*) FROM community_moderation_flags f
+   WHERE f.user_id = t.user_id AND f.status = 'REJECTED' AND f.created_at >= ?) AS violations
+FROM discussion_topics t
+JOIN discussion_topic_links l ON l.topic_id = t.id
+LEFT JOIN users u ON u.id = t.user_id
+WHERE t.status = 'PUBLISHED'
+  AND l.target_type IN ('STOCK', 'FUTURES')
+  AND t.created_at >= ? AND t.created_at < ?`,
+		now.AddDate(0, 0, -banWindowDays), statDay.AddDate(0, 0, 1-cfg.WindowDays), statDay.AddDate(0, 0, 1),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := make([]communitySentimentSample, 0)
+	for rows.Next() {
+		var targetType, targetID string
+		var sample communitySentimentSample
+		var createdAt sql.NullTime
+		var memberLevel sql.NullString
+		var vipExpireAt sql.NullTime
+		var violations int
+		if err := rows.Scan(&targetType, &targetID, &sample.UserID, &sample.Stance, &sample.LikeCount, &sample.FavoriteCount, &createdAt, &memberLevel, &vipExpireAt, &violations); err != nil {
+			return nil, err
+		}
+		sample.AssetClass, sample.Symbol = communityLinkedSymbol(targetType, targetID)
+		accountAge := time.Duration(0)
+		if createdAt.Valid {
+			accountAge = now.Sub(createdAt.Time)
+		}
+		vip := strings.HasPrefix(strings.ToUpper(memberLevel.String), "VIP") && vipExpireAt.Valid && vipExpireAt.Time.After(now)
+		sample.TrustScore = communityTrustScore(accountAge, vip, violations)
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+// AdminRebuildCommunitySentiment replaces every row for stat_date, so topics
+// hidden since the last run drop out of the index.
+func (r *MySQLGrowthRepo) AdminRebuildCommunitySentiment(statDate string) (int, error) {
+	day, err := parseCommunitySentimentDate(statDate)
+	if err != nil {
+		return 0, err
+	}
+	cfg := r.resolveCommunitySentimentConfig()
+	samples, err := r.loadCommunitySentimentSamples(day, cfg, r.resolveCommunityModerationConfig().BanWindowDays)
+	if err != nil {
+		return 0, err
+	}
+	items := aggregateCommunitySentiment(samples, day.Format("2006-01-02"), cfg)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM community_sentiment_daily WHERE stat_date = ?", day.Format("2006-01-02")); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	for _, item := range items {
+		if _, err := tx.Exec(`
+INSERT INTO community_sentiment_daily
+  (asset_class, symbol, stat_date, window_days, topic_count, author_count, bullish_weight, bearish_weight, watch_weight, sentiment_score, extreme, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			item.AssetClass, item.Symbol, item.StatDate, item.WindowDays, item.TopicCount, item.AuthorCount,
+			item.BullishWeight, item.BearishWeight, item.WatchWeight, item.SentimentScore, item.Extreme, now,
+		); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// AdminRunCommunitySentimentIndex rebuilds yesterday as well as today so
+// reactions that arrive after midnight still settle into the closed day.
+func (r *MySQLGrowthRepo) AdminRunCommunitySentimentIndex() (string, error) {
+	return runCommunitySentimentIndex(r.AdminRebuildCommunitySentiment)
+}
+
+func runCommunitySentimentIndex(rebuild func(statDate string) (int, error)) (string, error) {
+	now := time.Now()
+	parts := make([]string, 0, 2)
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		statDate := day.Format("2006-01-02")
+		count, err := rebuild(statDate)
+		if err != nil {
+			return strings.Join(parts, ", "), fmt.Errorf("rebuild %s: %w", statDate, err)
+		}
+		parts = append(parts, fmt.Sprintf("%s symbols=%d", statDate, count))
+	}
+	return "community sentiment rebuilt: " + strings.Join(parts, ", "), nil
+}
+
+func (r *MySQLGrowthRepo) AdminListCommunitySentiment(query model.CommunitySentimentQuery) ([]model.CommunitySentimentDaily, int, error) {
+	offset := (query.Page - 1) * query.PageSize
+	filters := make([]string, 0, 4)
+	args := []interface{}{}
+	if assetClass := strings.ToUpper(strings.TrimSpace(query.AssetClass)); assetClass != "" {
+		filters = append(filters, "asset_class = ?")
+		args = append(args, assetClass)
+	}
+	if symbols := communitySentimentQuerySymbols(query.AssetClass, query.Symbol); len(symbols) > 0 {
+		filters = append(filters, "symbol IN ("+strings.TrimSuffix(strings.Repeat("?,", len(symbols)), ",")+")")
+		for _, symbol := range symbols {
+			args = append(args, symbol)
+		}
+	}
+	if startDate := strings.TrimSpace(query.StartDate); startDate != "" {
+		filters = append(filters, "stat_date >= ?")
+		args = append(args, startDate)
+	}
+	if endDate := strings.TrimSpace(query.EndDate); endDate != "" {
+		filters = append(filters, "stat_date <= ?")
+		args = append(args, endDate)
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_sentiment_daily"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, query.PageSize, offset)
+	rows, err := r.db.Query(`
+SELECT asset_class, symbol, stat_date, window_days, topic_count, author_count, bullish_weight, bearish_weight, watch_weight, sentiment_score, extreme, updated_at
+FROM community_sentiment_daily`+where+`
+ORDER BY stat_date DESC, asset_class ASC, symbol ASC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.CommunitySentimentDaily, 0)
+	for rows.Next() {
+		var item model.CommunitySentimentDaily
+		var statDate, updatedAt time.Time
+		if err := rows.Scan(&item.AssetClass, &item.Symbol, &statDate, &item.WindowDays, &item.TopicCount, &item.AuthorCount,
+			&item.BullishWeight, &item.BearishWeight, &item.WatchWeight, &item.SentimentScore, &item.Extreme, &updatedAt); err != nil {
+			return nil, 0, err
+		}
+		item.StatDate = statDate.Format("2006-01-02")
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// loadStrategyCrowdSentiment returns the latest sentiment row at or before the
+// trade date for each symbol. Symbols nobody discussed are simply absent.
+func (r *MySQLGrowthRepo) loadStrategyCrowdSentiment(assetClass string, symbols []string, selectedTradeDate time.Time) (map[string]model.StrategyEngineCrowdSentiment, error) {
+	if len(symbols) == 0 {
+		return map[string]model.StrategyEngineCrowdSentiment{}, nil
+	}
+	args := []interface{}{
+		assetClass,
+		selectedTradeDate.AddDate(0, 0, -strategyEngineCrowdSentimentLookbackDays).Format("2006-01-02"),
+		selectedTradeDate.Format("2006-01-02"),
+	}
+	placeholders := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		placeholders = append(placeholders, "?")
+		args = append(args, symbol)
+	}
+	rows, err := r.db.Query(`
+SELECT symbol, stat_date, window_days, topic_count, author_count, sentiment_score, extreme
+FROM community_sentiment_daily
+WHERE asset_class = ? AND stat_date >= ? AND stat_date <= ? AND symbol IN (`+strings.Join(placeholders, ",")+`)
+ORDER BY stat_date DESC`, args...)
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return map[string]model.StrategyEngineCrowdSentiment{}, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]model.StrategyEngineCrowdSentiment, len(symbols))
+	for rows.Next() {
+		var symbol string
+		var statDate time.Time
+		var item model.StrategyEngineCrowdSentiment
+		if err := rows.Scan(&symbol, &statDate, &item.WindowDays, &item.TopicCount, &item.AuthorCount, &item.Score, &item.Extreme); err != nil {
+			return nil, err
+		}
+		if _, ok := result[symbol]; ok {
+			continue
+		}
+		item.StatDate = statDate.Format("2006-01-02")
+		result[symbol] = item
+	}
+	return result, rows.Err()
+}
+
+func crowdSentimentPointer(items map[string]model.StrategyEngineCrowdSentiment, symbol string) *model.StrategyEngineCrowdSentiment {
+	item, ok := items[symbol]
+	if !ok {
+		return nil
+	}
+	return &item
+}
@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func (r *InMemoryGrowthRepo) AdminRebuildCommunitySentiment(statDate string) (int, error) {
+	day, err := parseCommunitySentimentDate(statDate)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cfg := defaultCommunitySentimentConfig()
+	since := day.AddDate(0, 0, 1-cfg.WindowDays)
+	until := day.AddDate(0, 0, 1)
+	violationsSince := time.Now().AddDate(0, 0, -defaultCommunityModerationConfig().BanWindowDays)
+
+	samples := make([]communitySentimentSample, 0)
+	for _, topic := range r.communityTopics {
+		createdAt, err := time.Parse(time.RFC3339, topic.CreatedAt)
+		if err != nil || topic.Status != string(model.CommunityTopicStatusPublished) || createdAt.Before(since) || !createdAt.Before(until) {
+			continue
+		}
+		assetClass, symbol := communityLinkedSymbol(topic.LinkedTarget.TargetType, topic.LinkedTarget.TargetID)
+		if symbol == "" {
+			continue
+		}
+		samples = append(samples, communitySentimentSample{
+			AssetClass:    assetClass,
+			Symbol:        symbol,
+			UserID:        topic.UserID,
+			Stance:        topic.Stance,
+			LikeCount:     topic.LikeCount,
+			FavoriteCount: topic.FavoriteCount,
+			TrustScore:    communityTrustScore(0, false, r.countCommunityViolationsLocked(topic.UserID, violationsSince)),
+		})
+	}
+
+	statKey := day.Format("2006-01-02")
+	for key, item := range r.communitySentiment {
+		if item.StatDate == statKey {
+			delete(r.communitySentiment, key)
+		}
+	}
+	now := time.Now().Format(time.RFC3339)
+	items := aggregateCommunitySentiment(samples, statKey, cfg)
+	for _, item := range items {
+		item.UpdatedAt = now
+		r.communitySentiment[strings.Join([]string{item.AssetClass, item.Symbol, item.StatDate}, "|")] = item
+	}
+	return len(items), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRunCommunitySentimentIndex() (string, error) {
+	return runCommunitySentimentIndex(r.AdminRebuildCommunitySentiment)
+}
+
+func (r *InMemoryGrowthRepo) AdminListCommunitySentiment(query model.CommunitySentimentQuery) ([]model.CommunitySentimentDaily, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	symbols := symbolSet(communitySentimentQuerySymbols(query.AssetClass, query.Symbol))
+	items := make([]model.CommunitySentimentDaily, 0)
+	for _, item := range r.communitySentiment {
+		if query.AssetClass != "" && !strings.EqualFold(item.AssetClass, strings.TrimSpace(query.AssetClass)) {
+			continue
+		}
+		if _, ok := symbols[item.Symbol]; len(symbols) > 0 && !ok {
+			continue
+		}
+		if query.StartDate != "" && item.StatDate < strings.TrimSpace(query.StartDate) {
+			continue
+		}
+		if query.EndDate != "" && item.StatDate > strings.TrimSpace(query.EndDate) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].StatDate != items[j].StatDate {
+			return items[i].StatDate > items[j].StatDate
+		}
+		if items[i].AssetClass != items[j].AssetClass {
+			return items[i].AssetClass < items[j].AssetClass
+		}
+		return items[i].Symbol < items[j].Symbol
+	})
+	total := len(items)
+	start, end := paginateBounds(query.Page, query.PageSize, total)
+	if start >= total {
+		return []model.CommunitySentimentDaily{}, total, nil
+	}
+	return items[start:end], total, nil
+}
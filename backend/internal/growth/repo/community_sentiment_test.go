@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestAggregateCommunitySentimentWeightsAuthorsAndFlagsExtremes(t *testing.T) {
+	cfg := defaultCommunitySentimentConfig()
+	samples := []communitySentimentSample{
+		// One loud author posting the same call three times still counts once.
+		{AssetClass: "STOCK", Symbol: "600519.SH", UserID: "u_loud", Stance: "BULLISH", TrustScore: 50},
+		{AssetClass: "STOCK", Symbol: "600519.SH", UserID: "u_loud", Stance: "BULLISH", TrustScore: 50},
+		{AssetClass: "STOCK", Symbol: "600519.SH", UserID: "u_loud", Stance: "BULLISH", TrustScore: 50},
+		{AssetClass: "STOCK", Symbol: "600519.SH", UserID: "u_bear", Stance: "BEARISH", TrustScore: 50},
+		{AssetClass: "FUTURES", Symbol: "RB", UserID: "u_1", Stance: "BEARISH", LikeCount: 3, TrustScore: 80},
+		{AssetClass: "FUTURES", Symbol: "RB", UserID: "u_2", Stance: "BEARISH", TrustScore: 60},
+		{AssetClass: "FUTURES", Symbol: "RB", UserID: "u_3", Stance: "BEARISH", TrustScore: 60},
+		{AssetClass: "FUTURES", Symbol: "RB", UserID: "u_4", Stance: "BEARISH", TrustScore: 60},
+		{AssetClass: "FUTURES", Symbol: "RB", UserID: "u_5", Stance: "WATCH", TrustScore: 40},
+		{AssetClass: "FUTURES", Symbol: "RB", UserID: "u_6", Stance: "BULLISH", TrustScore: 0},
+		{AssetClass: "", Symbol: "", UserID: "u_news", Stance: "BULLISH", TrustScore: 90},
+	}
+
+	items := aggregateCommunitySentiment(samples, "2026-03-18", cfg)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 symbols, got %+v", items)
+	}
+	rb, stock := items[0], items[1]
+	if rb.Symbol != "RB" || stock.Symbol != "600519.SH" {
+		t.Fatalf("expected futures then stock ordering, got %s, %s", rb.Symbol, stock.Symbol)
+	}
+	if stock.SentimentScore != 0 || stock.TopicCount != 4 || stock.AuthorCount != 2 || stock.Extreme != "" {
+		t.Fatalf("expected averaged authors to cancel out, got %+v", stock)
+	}
+	if rb.TopicCount != 6 || rb.BullishWeight != 0 || rb.Extreme != model.CommunitySentimentExtremePanic {
+		t.Fatalf("expected bearish RB panic with zero-trust bull ignored, got %+v", rb)
+	}
+	if rb.SentimentScore > -0.8 || rb.SentimentScore < -1 {
+		t.Fatalf("expected strongly bearish RB score, got %v", rb.SentimentScore)
+	}
+
+	if asset, symbol := communityLinkedSymbol("futures", "rb2405"); asset != "FUTURES" || symbol != "RB" {
+		t.Fatalf("expected futures contract to map to its product, got %s %s", asset, symbol)
+	}
+	if asset, symbol := communityLinkedSymbol("STOCK", "600519"); asset != "STOCK" || symbol != "600519.SH" {
+		t.Fatalf("expected canonical stock key, got %s %s", asset, symbol)
+	}
+	if asset, _ := communityLinkedSymbol("NEWS_ARTICLE", "article_1"); asset != "" {
+		t.Fatalf("expected news links to be ignored, got %s", asset)
+	}
+}
+
+func TestInMemoryRebuildCommunitySentimentReplacesDay(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+
+	count, err := repo.AdminRebuildCommunitySentiment("2026-03-23")
+	if err != nil {
+		t.Fatalf("AdminRebuildCommunitySentiment() error = %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected the seeded stock topic to produce one row, got %d", count)
+	}
+	items, total, err := repo.AdminListCommunitySentiment(model.CommunitySentimentQuery{AssetClass: "STOCK", Symbol: "600519", Page: 1, PageSize: 20})
+	if err != nil || total != 1 {
+		t.Fatalf("expected one 600519.SH row, got %+v total=%d err=%v", items, total, err)
+	}
+	if items[0].Symbol != "600519.SH" || items[0].SentimentScore != 0 || items[0].WatchWeight <= 0 {
+		t.Fatalf("expected neutral WATCH reading, got %+v", items[0])
+	}
+
+	if err := repo.AdminUpdateCommunityTopicStatus("ct_demo_001", "HIDDEN"); err != nil {
+		t.Fatalf("AdminUpdateCommunityTopicStatus() error = %v", err)
+	}
+	if count, err := repo.AdminRebuildCommunitySentiment("2026-03-23"); err != nil || count != 0 {
+		t.Fatalf("expected hidden topic to drop out, got count=%d err=%v", count, err)
+	}
+	if _, total, _ := repo.AdminListCommunitySentiment(model.CommunitySentimentQuery{Page: 1, PageSize: 20}); total != 0 {
+		t.Fatalf("expected rebuilt day to be replaced, got total=%d", total)
+	}
+	if _, err := repo.AdminRebuildCommunitySentiment("2026/03/23"); err == nil {
+		t.Fatal("expected malformed stat_date to be rejected")
+	}
+}
+
+func TestMySQLRebuildCommunitySentimentWritesRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	expectDefaultMembershipOrderPollConfig(mock)
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM discussion_topics t\nJOIN discussion_topic_links l ON l.topic_id = t.id")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"target_type", "target_id", "user_id", "stance", "like_count", "favorite_count", "created_at", "member_level", "vip_expire_at", "violations",
+		}).
+			AddRow("STOCK", "600519", "u_1", "BULLISH", 4, 1, now.AddDate(-1, 0, 0), "VIP1", now.AddDate(0, 1, 0), 0).
+			AddRow("FUTURES", "RB2405", "u_2", "BEARISH", 0, 0, nil, nil, nil, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM community_sentiment_daily WHERE stat_date = ?")).
+		WithArgs("2026-03-18").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO community_sentiment_daily")).
+		WithArgs("FUTURES", "RB", "2026-03-18", 5, 1, 1, 0.0, 0.4, 0.0, -1.0, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO community_sentiment_daily")).
+		WithArgs("STOCK", "600519.SH", "2026-03-18", 5, 1, 1, 2.6513, 0.0, 0.0, 1.0, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	count, err := repo.AdminRebuildCommunitySentiment("2026-03-18")
+	if err != nil {
+		t.Fatalf("AdminRebuildCommunitySentiment() error = %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 rows, got %d", count)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,611 @@
+package repo
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const (
+	communityFeedWindowDays       = 14
+	communityFeedCandidateLimit   = 500
+	communityFeedViewLookbackDays = 30
+	communityBrowseContentType    = "COMMUNITY_TOPIC"
+)
+
+// normalizeCommunityFollowTarget validates a follow target and maps symbols
+// onto the key topics are matched by, see communityLinkedSymbol.
+func normalizeCommunityFollowTarget(targetType string, targetID string) (string, string, error) {
+	targetType = strings.ToUpper(strings.TrimSpace(targetType))
+	targetID = strings.TrimSpace(targetID)
+	switch targetType {
+	case model.CommunityFollowTargetUser:
+		if targetID == "" {
+			return "", "", model.ErrCommunityFollowInvalid
+		}
+		return targetType, targetID, nil
+	case model.CommunityFollowTargetStock, model.CommunityFollowTargetFutures:
+		if _, symbol := communityLinkedSymbol(targetType, targetID); symbol != "" {
+			return targetType, symbol, nil
+		}
+		return "", "", model.ErrCommunityFollowInvalid
+	case model.CommunityFollowTargetTopicType:
+		switch model.CommunityTopicType(strings.ToUpper(targetID)) {
+		case model.CommunityTopicTypeStock, model.CommunityTopicTypeFutures, model.CommunityTopicTypeNews, model.CommunityTopicTypeStrategy:
+			return targetType, strings.ToUpper(targetID), nil
+		}
+		return "", "", model.ErrCommunityFollowInvalid
+	}
+	return "", "", model.ErrCommunityFollowInvalid
+}
+
+// communityFeedSignals holds one viewer's personalization inputs. Symbol maps
+// are keyed by "ASSET_CLASS|SYMBOL".
+type communityFeedSignals struct {
+	FollowedUsers      map[string]struct{}
+	FollowedSymbols    map[string]struct{}
+	FollowedTopicTypes map[string]struct{}
+	SubscribedSymbols  map[string]struct{}
+	ViewedSymbols      map[string]int
+	HiddenUsers        map[string]struct{}
+}
+
+func newCommunityFeedSignals() communityFeedSignals {
+	return communityFeedSignals{
+		FollowedUsers:      map[string]struct{}{},
+		FollowedSymbols:    map[string]struct{}{},
+		FollowedTopicTypes: map[string]struct{}{},
+		SubscribedSymbols:  map[string]struct{}{},
+		ViewedSymbols:      map[string]int{},
+		HiddenUsers:        map[string]struct{}{},
+	}
+}
+
+func (s communityFeedSignals) addFollow(targetType string, targetID string) {
+	switch targetType {
+	case model.CommunityFollowTargetUser:
+		s.FollowedUsers[targetID] = struct{}{}
+	case model.CommunityFollowTargetStock, model.CommunityFollowTargetFutures:
+		s.FollowedSymbols[targetType+"|"+targetID] = struct{}{}
+	case model.CommunityFollowTargetTopicType:
+		s.FollowedTopicTypes[targetID] = struct{}{}
+	}
+}
+
+// addSubscription reads symbols out of an ACTIVE subscription scope such as
+// "600519,000001" or "RB2405 AU"; catch-all scopes like ALL add nothing.
+func (s communityFeedSignals) addSubscription(subscriptionType string, scope string) {
+	assetClass := ""
+	switch strings.ToUpper(strings.TrimSpace(subscriptionType)) {
+	case "STOCK_RECO":
+		assetClass = marketAssetClassStock
+	case "FUTURES_STRATEGY":
+		assetClass = marketAssetClassFutures
+	default:
+		return
+	}
+	for _, token := range strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '|'
+	}) {
+		if strings.EqualFold(token, "ALL") || token == "*" {
+			continue
+		}
+		if _, symbol := communityLinkedSymbol(assetClass, token); symbol != "" {
+			s.SubscribedSymbols[assetClass+"|"+symbol] = struct{}{}
+		}
+	}
+}
+
+func (s communityFeedSignals) addView(targetType string, targetID string, count int) {
+	if assetClass, symbol := communityLinkedSymbol(targetType, targetID); symbol != "" {
+		s.ViewedSymbols[assetClass+"|"+symbol] += count
+	}
+}
+
+// communityHotScore decays engagement with age the way link aggregators do:
+// comments weigh most, and a topic loses about two thirds of its score in a
+// day compared to a fresh one with the same engagement.
+func communityHotScore(item model.CommunityTopicListItem, now time.Time) float64 {
+	engagement := float64(1 + item.LikeCount + 2*item.FavoriteCount + 3*item.CommentCount)
+	ageHours := 0.0
+	if createdAt, err := time.Parse(time.RFC3339, item.CreatedAt); err == nil {
+		ageHours = math.Max(now.Sub(createdAt).Hours(), 0)
+	}
+	return engagement / math.Pow(ageHours+2, 1.5)
+}
+
+// rankCommunityFeed scores candidates for one viewer: the hot score is
+// multiplied by a boost for every personal signal the topic matches, so a
+// followed author's fresh topic beats a stranger's, but a stale followed
+// topic still sinks below a busy new one.
+func rankCommunityFeed(candidates []model.CommunityTopicListItem, signals communityFeedSignals, now time.Time) []model.CommunityFeedItem {
+	items := make([]model.CommunityFeedItem, 0, len(candidates))
+	for _, candidate := range candidates {
+		if _, hidden := signals.HiddenUsers[candidate.UserID]; hidden {
+			continue
+		}
+		boost := 1.0
+		reasons := make([]string, 0, 4)
+		if _, ok := signals.FollowedUsers[candidate.UserID]; ok {
+			boost += 3
+			reasons = append(reasons, "FOLLOWED_USER")
+		}
+		if assetClass, symbol := communityLinkedSymbol(candidate.LinkedTarget.TargetType, candidate.LinkedTarget.TargetID); symbol != "" {
+			key := assetClass + "|" + symbol
+			if _, ok := signals.FollowedSymbols[key]; ok {
+				boost += 2
+				reasons = append(reasons, "FOLLOWED_SYMBOL")
+			}
+			if _, ok := signals.SubscribedSymbols[key]; ok {
+				boost += 1
+				reasons = append(reasons, "SUBSCRIBED_SYMBOL")
+			}
+			if views := signals.ViewedSymbols[key]; views > 0 {
+				boost += 0.2 * float64(minInt(views, 5))
+				reasons = append(reasons, "BROWSED_SYMBOL")
+			}
+		}
+		if _, ok := signals.FollowedTopicTypes[strings.ToUpper(candidate.TopicType)]; ok {
+			boost += 0.5
+			reasons = append(reasons, "FOLLOWED_TOPIC_TYPE")
+		}
+		items = append(items, model.CommunityFeedItem{
+			CommunityTopicListItem: candidate,
+			FeedScore:              roundTo(communityHotScore(candidate, now)*boost, 6),
+			FeedReasons:            reasons,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].FeedScore != items[j].FeedScore {
+			return items[i].FeedScore > items[j].FeedScore
+		}
+		return items[i].CreatedAt > items[j].CreatedAt
+	})
+	return items
+}
+
+func (r *MySQLGrowthRepo) ListCommunityFollows(userID string, targetType string, page int, pageSize int) ([]model.CommunityFollow, int, error) {
+	page, pageSize = normalizeCommunityPage(page, pageSize)
+	where := " WHERE user_id = ?"
+	args := []interface{}{strings.TrimSpace(userID)}
+	if targetType = strings.ToUpper(strings.TrimSpace(targetType)); targetType != "" {
+		where += " AND target_type = ?"
+		args = append(args, targetType)
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_follows"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query("SELECT id, user_id, target_type, target_id, created_at FROM community_follows"+where+" ORDER BY created_at DESC LIMIT ? OFFSET ?",
+		append(args, pageSize, (page-1)*pageSize)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.CommunityFollow, 0)
+	for rows.Next() {
+		item, err := scanCommunityFollow(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// CreateCommunityFollow is idempotent: following the same target twice
+// returns the existing follow.
+func (r *MySQLGrowthRepo) CreateCommunityFollow(userID string, targetType string, targetID string) (model.CommunityFollow, error) {
+	userID = strings.TrimSpace(userID)
+	targetType, targetID, err := normalizeCommunityFollowTarget(targetType, targetID)
+	if err != nil {
+		return model.CommunityFollow{}, err
+	}
+	if targetType == model.CommunityFollowTargetUser {
+		if targetID == userID {
+			return model.CommunityFollow{}, model.ErrCommunityFollowSelf
+		}
+		var exists int
+		if err := r.db.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", targetID).Scan(&exists); err != nil {
+			return model.CommunityFollow{}, err
+		}
+		if exists == 0 {
+			return model.CommunityFollow{}, sql.ErrNoRows
+		}
+		var blocked int
+		if err := r.db.QueryRow(
+			"SELECT COUNT(*) FROM community_user_blocks WHERE user_id = ? AND blocked_user_id = ? AND block_type = ?",
+			targetID, userID, model.CommunityBlockTypeBlock,
+		).Scan(&blocked); err != nil {
+			return model.CommunityFollow{}, err
+		}
+		if blocked > 0 {
+			return model.CommunityFollow{}, model.ErrCommunityFollowBlocked
+		}
+	}
+	if _, err := r.db.Exec(`
+INSERT IGNORE INTO community_follows (id, user_id, target_type, target_id, created_at)
+VALUES (?, ?, ?, ?, ?)`, newID("cf"), userID, targetType, targetID, time.Now()); err != nil {
+		return model.CommunityFollow{}, err
+	}
+	return scanCommunityFollow(r.db.QueryRow(
+		"SELECT id, user_id, target_type, target_id, created_at FROM community_follows WHERE user_id = ? AND target_type = ? AND target_id = ?",
+		userID, targetType, targetID,
+	))
+}
+
+func (r *MySQLGrowthRepo) DeleteCommunityFollow(userID string, id string) error {
+	result, err := r.db.Exec("DELETE FROM community_follows WHERE id = ? AND user_id = ?", strings.TrimSpace(id), strings.TrimSpace(userID))
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrCommunityFollowNotFound
+	}
+	return nil
+}
+
+func (r *MySQLGrowthRepo) ListCommunityBlocks(userID string, page int, pageSize int) ([]model.CommunityUserBlock, int, error) {
+	page, pageSize = normalizeCommunityPage(page, pageSize)
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_user_blocks WHERE user_id = ?", strings.TrimSpace(userID)).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`
+SELECT id, user_id, blocked_user_id, block_type, created_at
+FROM community_user_blocks
+WHERE user_id = ?
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, strings.TrimSpace(userID), pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.CommunityUserBlock, 0)
+	for rows.Next() {
+		item, err := scanCommunityUserBlock(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// CreateCommunityBlock mutes or blocks a user, switching the type in place
+// when one already exists. A BLOCK also drops the blocked user's follow.
+func (r *MySQLGrowthRepo) CreateCommunityBlock(userID string, blockedUserID string, blockType string) (model.CommunityUserBlock, error) {
+	userID = strings.TrimSpace(userID)
+	blockedUserID = strings.TrimSpace(blockedUserID)
+	blockType = strings.ToUpper(strings.TrimSpace(blockType))
+	if blockedUserID == "" || blockedUserID == userID {
+		return model.CommunityUserBlock{}, model.ErrCommunityFollowSelf
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.CommunityUserBlock{}, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`
+INSERT INTO community_user_blocks (id, user_id, blocked_user_id, block_type, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE block_type = VALUES(block_type)`, newID("cub"), userID, blockedUserID, blockType, time.Now()); err != nil {
+		return model.CommunityUserBlock{}, err
+	}
+	if blockType == model.CommunityBlockTypeBlock {
+		if _, err := tx.Exec(
+			"DELETE FROM community_follows WHERE user_id = ? AND target_type = ? AND target_id = ?",
+			blockedUserID, model.CommunityFollowTargetUser, userID,
+		); err != nil {
+			return model.CommunityUserBlock{}, err
+		}
+	}
+	item, err := scanCommunityUserBlock(tx.QueryRow(
+		"SELECT id, user_id, blocked_user_id, block_type, created_at FROM community_user_blocks WHERE user_id = ? AND blocked_user_id = ?",
+		userID, blockedUserID,
+	))
+	if err != nil {
+		return model.CommunityUserBlock{}, err
+	}
+	return item, tx.Commit()
+}
+
+func (r *MySQLGrowthRepo) DeleteCommunityBlock(userID string, id string) error {
+	result, err := r.db.Exec("DELETE FROM community_user_blocks WHERE id = ? AND user_id = ?", strings.TrimSpace(id), strings.TrimSpace(userID))
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrCommunityBlockNotFound
+	}
+	return nil
+}
+
+// RecordCommunityMentions stores mentions of existing users that have not
+// blocked the actor and returns who was newly mentioned, so editing or
+// re-saving content never notifies twice.
+func (r *MySQLGrowthRepo) RecordCommunityMentions(input model.CommunityMentionInput) ([]string, error) {
+	actor := strings.TrimSpace(input.ActorUserID)
+	candidates := make([]string, 0, len(input.MentionedUserIDs))
+	for _, userID := range compactStrings(input.MentionedUserIDs) {
+		if userID != actor {
+			candidates = append(candidates, userID)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(candidates)), ",")
+	args := make([]interface{}, 0, len(candidates)+2)
+	args = append(args, actor, model.CommunityBlockTypeBlock)
+	for _, userID := range candidates {
+		args = append(args, userID)
+	}
+	rows, err := r.db.Query(`
+SELECT u.id
+FROM users u
+WHERE NOT EXISTS (
+	SELECT 1 FROM community_user_blocks b
+	WHERE b.user_id = u.id AND b.blocked_user_id = ? AND b.block_type = ?
+)
+AND u.id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]string, 0, len(candidates))
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		allowed = append(allowed, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mentioned := make([]string, 0, len(allowed))
+	for _, userID := range allowed {
+		result, err := r.db.Exec(`
+INSERT IGNORE INTO community_mentions (id, target_type, target_id, topic_id, mentioned_user_id, actor_user_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			newID("cm"), strings.ToUpper(strings.TrimSpace(input.TargetType)), strings.TrimSpace(input.TargetID),
+			strings.TrimSpace(input.TopicID), userID, actor, now)
+		if err != nil {
+			return nil, err
+		}
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			mentioned = append(mentioned, userID)
+		}
+	}
+	return mentioned, nil
+}
+
+func (r *MySQLGrowthRepo) ListCommunityMentions(userID string, page int, pageSize int) ([]model.CommunityMention, int, error) {
+	page, pageSize = normalizeCommunityPage(page, pageSize)
+	userID = strings.TrimSpace(userID)
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM community_mentions WHERE mentioned_user_id = ?", userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`
+SELECT m.id, m.target_type, m.target_id, m.topic_id, COALESCE(t.title, ''), m.mentioned_user_id, m.actor_user_id, m.created_at
+FROM community_mentions m
+LEFT JOIN discussion_topics t ON t.id = m.topic_id
+WHERE m.mentioned_user_id = ?
+ORDER BY m.created_at DESC
+LIMIT ? OFFSET ?`, userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.CommunityMention, 0)
+	for rows.Next() {
+		var item model.CommunityMention
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.TargetType, &item.TargetID, &item.TopicID, &item.TopicTitle, &item.MentionedUserID, &item.ActorUserID, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// RecordCommunityTopicView adds a topic view to browse_histories; the feed
+// reads the linked symbols of viewed topics back as an interest signal.
+func (r *MySQLGrowthRepo) RecordCommunityTopicView(userID string, topicID string) error {
+	_, err := r.db.Exec(`
+INSERT INTO browse_histories (id, user_id, content_type, content_id, source_page, viewed_at)
+VALUES (?, ?, ?, ?, ?, ?)`, newID("bh"), strings.TrimSpace(userID), communityBrowseContentType, strings.TrimSpace(topicID), "community_topic", time.Now())
+	return err
+}
+
+func (r *MySQLGrowthRepo) ListCommunityFeed(userID string, page int, pageSize int) ([]model.CommunityFeedItem, int, error) {
+	userID = strings.TrimSpace(userID)
+	now := time.Now()
+	signals, err := r.loadCommunityFeedSignals(userID, now)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+SELECT
+	t.id,
+	t.user_id,
+	t.title,
+	t.summary,
+	t.topic_type,
+	t.stance,
+	t.status,
+	t.comment_count,
+	t.like_count,
+	t.favorite_count,
+	t.report_count,
+	t.last_active_at,
+	t.created_at,
+	COALESCE(l.target_type, ''),
+	COALESCE(l.target_id, ''),
+	COALESCE(l.target_snapshot, ''),
+	EXISTS(
+		SELECT 1 FROM discussion_reactions dr
+		WHERE dr.user_id = ? AND dr.target_type = 'TOPIC' AND dr.target_id = t.id AND dr.reaction_type = 'LIKE'
+	) AS liked_by_me,
+	EXISTS(
+		SELECT 1 FROM discussion_reactions dr
+		WHERE dr.user_id = ? AND dr.target_type = 'TOPIC' AND dr.target_id = t.id AND dr.reaction_type = 'FAVORITE'
+	) AS favorited_by_me
+FROM discussion_topics t
+LEFT JOIN discussion_topic_links l ON l.topic_id = t.id
+WHERE t.status = ? AND t.user_id <> ? AND t.last_active_at >= ?
+ORDER BY t.last_active_at DESC
+LIMIT ?`, userID, userID, string(model.CommunityTopicStatusPublished), userID,
+		now.AddDate(0, 0, -communityFeedWindowDays), communityFeedCandidateLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	candidates := make([]model.CommunityTopicListItem, 0)
+	for rows.Next() {
+		item, err := scanCommunityTopicListItem(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		candidates = append(candidates, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	items := rankCommunityFeed(candidates, signals, now)
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunityFeedItem{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *MySQLGrowthRepo) loadCommunityFeedSignals(userID string, now time.Time) (communityFeedSignals, error) {
+	signals := newCommunityFeedSignals()
+
+	rows, err := r.db.Query("SELECT target_type, target_id FROM community_follows WHERE user_id = ?", userID)
+	if err != nil {
+		return signals, err
+	}
+	for rows.Next() {
+		var targetType, targetID string
+		if err := rows.Scan(&targetType, &targetID); err != nil {
+			rows.Close()
+			return signals, err
+		}
+		signals.addFollow(targetType, targetID)
+	}
+	rows.Close()
+
+	rows, err = r.db.Query(`
+SELECT blocked_user_id FROM community_user_blocks WHERE user_id = ?
+UNION
+SELECT user_id FROM community_user_blocks WHERE blocked_user_id = ? AND block_type = ?`, userID, userID, model.CommunityBlockTypeBlock)
+	if err != nil {
+		return signals, err
+	}
+	for rows.Next() {
+		var hidden string
+		if err := rows.Scan(&hidden); err != nil {
+			rows.Close()
+			return signals, err
+		}
+		signals.HiddenUsers[hidden] = struct{}{}
+	}
+	rows.Close()
+
+	rows, err = r.db.Query("SELECT type, scope FROM subscriptions WHERE user_id = ? AND status = 'ACTIVE'", userID)
+	if err != nil {
+		return signals, err
+	}
+	for rows.Next() {
+		var subscriptionType, scope string
+		if err := rows.Scan(&subscriptionType, &scope); err != nil {
+			rows.Close()
+			return signals, err
+		}
+		signals.addSubscription(subscriptionType, scope)
+	}
+	rows.Close()
+
+	rows, err = r.db.Query(`
+SELECT l.target_type, l.target_id, COUNT(*)
+FROM browse_histories b
+JOIN discussion_topic_links l ON l.topic_id = b.content_id
+WHERE b.user_id = ? AND b.content_type = ? AND b.viewed_at >= ?
+GROUP BY l.target_type, l.target_id`, userID, communityBrowseContentType, now.AddDate(0, 0, -communityFeedViewLookbackDays))
+	if err != nil {
+		return signals, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var targetType, targetID string
+		var count int
+		if err := rows.Scan(&targetType, &targetID, &count); err != nil {
+			return signals, err
+		}
+		signals.addView(targetType, targetID, count)
+	}
+	return signals, rows.Err()
+}
+
+// communityNotificationSuppressed reports whether the recipient muted or
+// blocked the actor; a missing block table never suppresses.
+func (r *MySQLGrowthRepo) communityNotificationSuppressed(recipient string, actor string) (bool, error) {
+	if actor == "" || actor == recipient {
+		return false, nil
+	}
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM community_user_blocks WHERE user_id = ? AND blocked_user_id = ?", recipient, actor).Scan(&count)
+	if isTableNotFoundError(err) {
+		return false, nil
+	}
+	return count > 0, err
+}
+
+func normalizeCommunityPage(page int, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+func scanCommunityFollow(scanner interface {
+	Scan(dest ...interface{}) error
+}) (model.CommunityFollow, error) {
+	var item model.CommunityFollow
+	var createdAt time.Time
+	if err := scanner.Scan(&item.ID, &item.UserID, &item.TargetType, &item.TargetID, &createdAt); err != nil {
+		return model.CommunityFollow{}, err
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	return item, nil
+}
+
+func scanCommunityUserBlock(scanner interface {
+	Scan(dest ...interface{}) error
+}) (model.CommunityUserBlock, error) {
+	var item model.CommunityUserBlock
+	var createdAt time.Time
+	if err := scanner.Scan(&item.ID, &item.UserID, &item.BlockedUserID, &item.BlockType, &createdAt); err != nil {
+		return model.CommunityUserBlock{}, err
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	return item, nil
+}
@@ -0,0 +1,284 @@
+package repo
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// The in-memory repo has no user table, so anyone who has authored a topic
+// or comment counts as an existing user for follows and mentions, and the
+// feed ignores subscriptions.
+func (r *InMemoryGrowthRepo) communityKnownUserLocked(userID string) bool {
+	for _, topic := range r.communityTopics {
+		if topic.UserID == userID {
+			return true
+		}
+	}
+	for _, comment := range r.communityComments {
+		if comment.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *InMemoryGrowthRepo) communityBlockLocked(userID string, blockedUserID string) (model.CommunityUserBlock, bool) {
+	for _, item := range r.communityBlocks {
+		if item.UserID == userID && item.BlockedUserID == blockedUserID {
+			return item, true
+		}
+	}
+	return model.CommunityUserBlock{}, false
+}
+
+func (r *InMemoryGrowthRepo) ListCommunityFollows(userID string, targetType string, page int, pageSize int) ([]model.CommunityFollow, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.CommunityFollow, 0)
+	for _, item := range r.communityFollows {
+		if item.UserID != strings.TrimSpace(userID) {
+			continue
+		}
+		if targetType != "" && !strings.EqualFold(item.TargetType, strings.TrimSpace(targetType)) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt > items[j].CreatedAt })
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunityFollow{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) CreateCommunityFollow(userID string, targetType string, targetID string) (model.CommunityFollow, error) {
+	userID = strings.TrimSpace(userID)
+	targetType, targetID, err := normalizeCommunityFollowTarget(targetType, targetID)
+	if err != nil {
+		return model.CommunityFollow{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if targetType == model.CommunityFollowTargetUser {
+		if targetID == userID {
+			return model.CommunityFollow{}, model.ErrCommunityFollowSelf
+		}
+		if !r.communityKnownUserLocked(targetID) {
+			return model.CommunityFollow{}, sql.ErrNoRows
+		}
+		if block, ok := r.communityBlockLocked(targetID, userID); ok && block.BlockType == model.CommunityBlockTypeBlock {
+			return model.CommunityFollow{}, model.ErrCommunityFollowBlocked
+		}
+	}
+	for _, item := range r.communityFollows {
+		if item.UserID == userID && item.TargetType == targetType && item.TargetID == targetID {
+			return item, nil
+		}
+	}
+	item := model.CommunityFollow{
+		ID:         newID("cf"),
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	r.communityFollows[item.ID] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) DeleteCommunityFollow(userID string, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.communityFollows[strings.TrimSpace(id)]
+	if !ok || item.UserID != strings.TrimSpace(userID) {
+		return model.ErrCommunityFollowNotFound
+	}
+	delete(r.communityFollows, item.ID)
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) ListCommunityBlocks(userID string, page int, pageSize int) ([]model.CommunityUserBlock, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.CommunityUserBlock, 0)
+	for _, item := range r.communityBlocks {
+		if item.UserID == strings.TrimSpace(userID) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt > items[j].CreatedAt })
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunityUserBlock{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) CreateCommunityBlock(userID string, blockedUserID string, blockType string) (model.CommunityUserBlock, error) {
+	userID = strings.TrimSpace(userID)
+	blockedUserID = strings.TrimSpace(blockedUserID)
+	blockType = strings.ToUpper(strings.TrimSpace(blockType))
+	if blockedUserID == "" || blockedUserID == userID {
+		return model.CommunityUserBlock{}, model.ErrCommunityFollowSelf
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.communityBlockLocked(userID, blockedUserID)
+	if !ok {
+		item = model.CommunityUserBlock{
+			ID:            newID("cub"),
+			UserID:        userID,
+			BlockedUserID: blockedUserID,
+			CreatedAt:     time.Now().Format(time.RFC3339),
+		}
+	}
+	item.BlockType = blockType
+	r.communityBlocks[item.ID] = item
+	if blockType == model.CommunityBlockTypeBlock {
+		for id, follow := range r.communityFollows {
+			if follow.UserID == blockedUserID && follow.TargetType == model.CommunityFollowTargetUser && follow.TargetID == userID {
+				delete(r.communityFollows, id)
+			}
+		}
+	}
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) DeleteCommunityBlock(userID string, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.communityBlocks[strings.TrimSpace(id)]
+	if !ok || item.UserID != strings.TrimSpace(userID) {
+		return model.ErrCommunityBlockNotFound
+	}
+	delete(r.communityBlocks, item.ID)
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) RecordCommunityMentions(input model.CommunityMentionInput) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	actor := strings.TrimSpace(input.ActorUserID)
+	targetType := strings.ToUpper(strings.TrimSpace(input.TargetType))
+	now := time.Now().Format(time.RFC3339)
+	mentioned := make([]string, 0, len(input.MentionedUserIDs))
+	for _, userID := range compactStrings(input.MentionedUserIDs) {
+		if userID == actor || !r.communityKnownUserLocked(userID) {
+			continue
+		}
+		if block, ok := r.communityBlockLocked(userID, actor); ok && block.BlockType == model.CommunityBlockTypeBlock {
+			continue
+		}
+		duplicate := false
+		for _, existing := range r.communityMentions {
+			if existing.TargetType == targetType && existing.TargetID == input.TargetID && existing.MentionedUserID == userID {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		r.communityMentions = append(r.communityMentions, model.CommunityMention{
+			ID:              newID("cm"),
+			TargetType:      targetType,
+			TargetID:        strings.TrimSpace(input.TargetID),
+			TopicID:         strings.TrimSpace(input.TopicID),
+			MentionedUserID: userID,
+			ActorUserID:     actor,
+			CreatedAt:       now,
+		})
+		mentioned = append(mentioned, userID)
+	}
+	return mentioned, nil
+}
+
+func (r *InMemoryGrowthRepo) ListCommunityMentions(userID string, page int, pageSize int) ([]model.CommunityMention, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.CommunityMention, 0)
+	for _, item := range r.communityMentions {
+		if item.MentionedUserID != strings.TrimSpace(userID) {
+			continue
+		}
+		item.TopicTitle = r.communityTopics[item.TopicID].Title
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt > items[j].CreatedAt })
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunityMention{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) RecordCommunityTopicView(userID string, topicID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	userID = strings.TrimSpace(userID)
+	if r.communityTopicViews[userID] == nil {
+		r.communityTopicViews[userID] = map[string]int{}
+	}
+	r.communityTopicViews[userID][strings.TrimSpace(topicID)]++
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) ListCommunityFeed(userID string, page int, pageSize int) ([]model.CommunityFeedItem, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	userID = strings.TrimSpace(userID)
+	now := time.Now()
+
+	signals := newCommunityFeedSignals()
+	for _, item := range r.communityFollows {
+		if item.UserID == userID {
+			signals.addFollow(item.TargetType, item.TargetID)
+		}
+	}
+	for _, item := range r.communityBlocks {
+		if item.UserID == userID {
+			signals.HiddenUsers[item.BlockedUserID] = struct{}{}
+		}
+		if item.BlockedUserID == userID && item.BlockType == model.CommunityBlockTypeBlock {
+			signals.HiddenUsers[item.UserID] = struct{}{}
+		}
+	}
+	for topicID, count := range r.communityTopicViews[userID] {
+		if topic, ok := r.communityTopics[topicID]; ok {
+			signals.addView(topic.LinkedTarget.TargetType, topic.LinkedTarget.TargetID, count)
+		}
+	}
+
+	since := now.AddDate(0, 0, -communityFeedWindowDays).Format(time.RFC3339)
+	candidates := make([]model.CommunityTopicListItem, 0)
+	for _, topic := range r.communityTopics {
+		if topic.Status != string(model.CommunityTopicStatusPublished) || topic.UserID == userID || topic.LastActiveAt < since {
+			continue
+		}
+		candidates = append(candidates, communityTopicListItemFromDetail(topic, userID, r.communityReacts))
+	}
+
+	items := rankCommunityFeed(candidates, signals, now)
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.CommunityFeedItem{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) communityNotificationSuppressedLocked(recipient string, actor string) bool {
+	if actor == "" || actor == recipient {
+		return false
+	}
+	_, ok := r.communityBlockLocked(recipient, actor)
+	return ok
+}
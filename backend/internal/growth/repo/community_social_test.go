@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestRankCommunityFeedBoostsPersonalSignalsAndDecays(t *testing.T) {
+	now := time.Date(2026, 3, 24, 12, 0, 0, 0, time.UTC)
+	topic := func(id string, userID string, targetType string, targetID string, age time.Duration, comments int) model.CommunityTopicListItem {
+		return model.CommunityTopicListItem{
+			ID:           id,
+			UserID:       userID,
+			TopicType:    targetType,
+			CommentCount: comments,
+			CreatedAt:    now.Add(-age).Format(time.RFC3339),
+			LinkedTarget: model.CommunityTopicLink{TargetType: targetType, TargetID: targetID},
+		}
+	}
+	candidates := []model.CommunityTopicListItem{
+		topic("stranger_fresh", "u_stranger", "STOCK", "000001", time.Hour, 0),
+		topic("followed_user", "u_friend", "STOCK", "000001", time.Hour, 0),
+		topic("followed_contract", "u_stranger", "FUTURES", "RB2410", time.Hour, 0),
+		topic("followed_but_stale", "u_friend", "STOCK", "000001", 72*time.Hour, 0),
+		topic("busy_new", "u_stranger", "STOCK", "000002", 2*time.Hour, 5),
+		topic("muted", "u_muted", "STOCK", "600519", time.Hour, 10),
+	}
+	signals := newCommunityFeedSignals()
+	signals.addFollow(model.CommunityFollowTargetUser, "u_friend")
+	signals.addFollow(model.CommunityFollowTargetFutures, "RB")
+	signals.addSubscription("STOCK_RECO", "ALL")
+	signals.HiddenUsers["u_muted"] = struct{}{}
+
+	items := rankCommunityFeed(candidates, signals, now)
+	if len(items) != 5 {
+		t.Fatalf("expected muted author to be filtered, got %d items", len(items))
+	}
+	order := make([]string, 0, len(items))
+	for _, item := range items {
+		order = append(order, item.ID)
+	}
+	want := []string{"busy_new", "followed_user", "followed_contract", "stranger_fresh", "followed_but_stale"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, order)
+		}
+	}
+	if reasons := items[2].FeedReasons; len(reasons) != 1 || reasons[0] != "FOLLOWED_SYMBOL" {
+		t.Fatalf("expected RB2410 to match the RB product follow, got %+v", items[2])
+	}
+	if len(signals.SubscribedSymbols) != 0 {
+		t.Fatalf("expected catch-all subscription scope to add no symbols, got %+v", signals.SubscribedSymbols)
+	}
+}
+
+func TestInMemoryCommunityBlockFiltersFeedFollowsAndNotifications(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+
+	follow, err := repo.CreateCommunityFollow("u_demo_002", "USER", "u_demo_001")
+	if err != nil {
+		t.Fatalf("CreateCommunityFollow() error = %v", err)
+	}
+	if again, err := repo.CreateCommunityFollow("u_demo_002", "USER", "u_demo_001"); err != nil || again.ID != follow.ID {
+		t.Fatalf("expected idempotent follow, got %+v err=%v", again, err)
+	}
+	if _, err := repo.CreateCommunityFollow("u_demo_002", "USER", "u_nobody"); err == nil {
+		t.Fatal("expected following an unknown user to fail")
+	}
+	topic, err := repo.CreateCommunityTopic(model.CommunityTopicCreateInput{
+		UserID: "u_demo_001", Title: "螺纹钢库存拐点", Content: "库存连续两周去化。",
+		TopicType: "FUTURES", Stance: "BULLISH", TargetType: "FUTURES", TargetID: "RB2410",
+	})
+	if err != nil || topic.Status != string(model.CommunityTopicStatusPublished) {
+		t.Fatalf("CreateCommunityTopic() = %+v, err = %v", topic, err)
+	}
+	if err := repo.RecordCommunityTopicView("u_demo_002", topic.ID); err != nil {
+		t.Fatalf("RecordCommunityTopicView() error = %v", err)
+	}
+	items, total, err := repo.ListCommunityFeed("u_demo_002", 1, 20)
+	if err != nil || total != 1 {
+		t.Fatalf("expected the fresh topic in the feed, got %+v total=%d err=%v", items, total, err)
+	}
+	if reasons := items[0].FeedReasons; len(reasons) != 2 || reasons[0] != "FOLLOWED_USER" || reasons[1] != "BROWSED_SYMBOL" {
+		t.Fatalf("expected followed author and browsed symbol boosts, got %+v", items[0])
+	}
+
+	if _, err := repo.CreateCommunityBlock("u_demo_001", "u_demo_002", model.CommunityBlockTypeBlock); err != nil {
+		t.Fatalf("CreateCommunityBlock() error = %v", err)
+	}
+	if _, total, _ := repo.ListCommunityFollows("u_demo_002", "", 1, 20); total != 0 {
+		t.Fatalf("expected block to drop the blocked user's follow, got total=%d", total)
+	}
+	if _, total, _ := repo.ListCommunityFeed("u_demo_002", 1, 20); total != 0 {
+		t.Fatalf("expected the blocker's topics to leave the blocked user's feed, got total=%d", total)
+	}
+	if _, err := repo.CreateCommunityFollow("u_demo_002", "USER", "u_demo_001"); !errors.Is(err, model.ErrCommunityFollowBlocked) {
+		t.Fatalf("expected ErrCommunityFollowBlocked, got %v", err)
+	}
+	if mentioned, err := repo.RecordCommunityMentions(model.CommunityMentionInput{
+		ActorUserID: "u_demo_002", TargetType: "COMMENT", TargetID: "cc_x", TopicID: "ct_demo_001",
+		MentionedUserIDs: []string{"u_demo_001", "u_demo_002"},
+	}); err != nil || len(mentioned) != 0 {
+		t.Fatalf("expected blocked and self mentions to be dropped, got %v err=%v", mentioned, err)
+	}
+
+	before, _, _ := repo.ListMessages("u_demo_001", 1, 100)
+	if err := repo.CreateCommunityNotification(model.CommunityNotificationInput{
+		UserID: "u_demo_001", Title: "t", Content: "c", MessageType: "COMMUNITY", ActorUserID: "u_demo_002",
+	}); err != nil {
+		t.Fatalf("CreateCommunityNotification() error = %v", err)
+	}
+	after, _, _ := repo.ListMessages("u_demo_001", 1, 100)
+	if len(after) != len(before) {
+		t.Fatalf("expected notification from blocked user to be dropped, got %d -> %d", len(before), len(after))
+	}
+}
+
+func TestMySQLCreateCommunityFollowNormalizesSymbolAndRejectsBlockedUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO community_follows")).
+		WithArgs(sqlmock.AnyArg(), "u_1", "FUTURES", "RB", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM community_follows WHERE user_id = ? AND target_type = ? AND target_id = ?")).
+		WithArgs("u_1", "FUTURES", "RB").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "target_type", "target_id", "created_at"}).
+			AddRow("cf_1", "u_1", "FUTURES", "RB", now))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE id = ?")).
+		WithArgs("u_2").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM community_user_blocks WHERE user_id = ? AND blocked_user_id = ? AND block_type = ?")).
+		WithArgs("u_2", "u_1", "BLOCK").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	follow, err := repo.CreateCommunityFollow("u_1", "futures", "rb2405")
+	if err != nil {
+		t.Fatalf("CreateCommunityFollow() error = %v", err)
+	}
+	if follow.ID != "cf_1" || follow.TargetID != "RB" {
+		t.Fatalf("expected RB product follow, got %+v", follow)
+	}
+	if _, err := repo.CreateCommunityFollow("u_1", "USER", "u_2"); !errors.Is(err, model.ErrCommunityFollowBlocked) {
+		t.Fatalf("expected ErrCommunityFollowBlocked, got %v", err)
+	}
+	if _, err := repo.CreateCommunityFollow("u_1", "TOPIC_TYPE", "GOSSIP"); !errors.Is(err, model.ErrCommunityFollowInvalid) {
+		t.Fatalf("expected ErrCommunityFollowInvalid, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -12,9 +12,10 @@ const (
 	experimentItemsQueryPattern    = `(?s)SELECT\s+experiment_key,\s+variant_key,\s+page_key,.*FROM experiment_events\s+WHERE created_at >= \?\s+GROUP BY experiment_key, variant_key, page_key, COALESCE`
 	experimentPageQueryPattern     = `(?s)SELECT\s+page_key,.*FROM experiment_events\s+WHERE created_at >= \?\s+GROUP BY page_key`
 	experimentTrendQueryPattern    = `(?s)SELECT\s+DATE\(created_at\)\s+AS metric_date,.*FROM experiment_events\s+WHERE created_at >= \?\s+GROUP BY DATE\(created_at\)`
-	experimentPayQueryPattern      = `(?s)SELECT pay_channel, payment_success_count, renewal_success_count, last_event_at\s+FROM\s+\(\s*SELECT.*FROM experiment_events.*event_type IN \('PAYMENT_SUCCESS', 'RENEWAL_SUCCESS'\).*GROUP BY .*?\)\s+pay_summary`
+	experimentPayQueryPattern      = `(?s)SELECT pay_channel, payment_success_count, renewal_success_count, renewal_failed_count, last_event_at\s+FROM\s+\(\s*SELECT.*FROM experiment_events.*event_type IN \('PAYMENT_SUCCESS', 'RENEWAL_SUCCESS', 'RENEWAL_FAILED'\).*GROUP BY .*?\)\s+pay_summary`
 	experimentDeviceQueryPattern   = `(?s)SELECT\s+experiment_key,\s+variant_key,\s+page_key,\s+device_type,.*FROM\s+\(\s*SELECT.*FROM experiment_events.*GROUP BY experiment_key, variant_key, page_key, COALESCE.*\)\s+device_summary`
 	experimentVariantQueryPattern  = `(?s)SELECT\s+DATE\(created_at\)\s+AS metric_date,\s+experiment_key,\s+variant_key,\s+page_key,.*FROM experiment_events\s+WHERE created_at >= \?\s+GROUP BY DATE\(created_at\), experiment_key, variant_key, page_key`
+	experimentCouponQueryPattern   = `(?s)SELECT\s+o\.coupon_code,.*FROM membership_orders o\s+LEFT JOIN experiment_order_attributions a ON a\.order_no = o\.order_no.*GROUP BY o\.coupon_code`
 )
 
 func TestAdminGetExperimentAnalyticsSummaryReturnsBreakdowns(t *testing.T) {
@@ -85,8 +86,9 @@ func TestAdminGetExperimentAnalyticsSummaryReturnsBreakdowns(t *testing.T) {
 			"pay_channel",
 			"payment_success_count",
 			"renewal_success_count",
+			"renewal_failed_count",
 			"last_event_at",
-		}).AddRow("ALIPAY", 1, 1, now))
+		}).AddRow("ALIPAY", 1, 1, 1, now))
 
 	mock.ExpectQuery(experimentDeviceQueryPattern).
 		WithArgs(sqlmock.AnyArg()).
@@ -119,6 +121,19 @@ func TestAdminGetExperimentAnalyticsSummaryReturnsBreakdowns(t *testing.T) {
 			"renewal_success_count",
 		}).AddRow(day, "exp_home", "A", "HOME", "MOBILE", "GUEST", 8, 4, 3, 1, 1))
 
+	mock.ExpectQuery(experimentCouponQueryPattern).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"coupon_code",
+			"experiment_key",
+			"variant_key",
+			"paid_order_count",
+			"original_amount",
+			"discount_amount",
+			"revenue",
+			"last_paid_at",
+		}).AddRow("SPRING20", "exp_home", "A", 2, 198.0, 39.6, 158.4, now))
+
 	summary, err := repo.AdminGetExperimentAnalyticsSummary(7)
 	if err != nil {
 		t.Fatalf("AdminGetExperimentAnalyticsSummary returned error: %v", err)
@@ -138,6 +153,9 @@ func TestAdminGetExperimentAnalyticsSummaryReturnsBreakdowns(t *testing.T) {
 	if summary.DeviceBreakdown[0].PaidPerExposureRate <= 0 {
 		t.Fatalf("expected positive paid per exposure rate, got %.4f", summary.DeviceBreakdown[0].PaidPerExposureRate)
 	}
+	if len(summary.CouponBreakdown) != 1 || summary.CouponBreakdown[0].Revenue != 158.4 {
+		t.Fatalf("unexpected coupon breakdown: %+v", summary.CouponBreakdown)
+	}
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("unmet sql expectations: %v", err)
 	}
@@ -0,0 +1,273 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const exportJobColumns = `id, export_type, format, filters, status, row_count, file_name, file_size, content_sha256, storage_ref, error_message, requested_by, request_ip, download_count, created_at, started_at, finished_at, expires_at, last_downloaded_at`
+
+func (r *MySQLGrowthRepo) AdminCreateExportJob(item model.ExportJob) (model.ExportJob, error) {
+	filters, err := json.Marshal(item.Filters)
+	if err != nil {
+		return model.ExportJob{}, err
+	}
+	item.ID = newID("exp")
+	item.Status = model.ExportJobStatusPending
+	now := time.Now()
+	_, err = r.db.Exec(`
+INSERT INTO export_jobs (id, export_type, format, filters, status, requested_by, request_ip, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.ID, item.ExportType, item.Format, string(filters), item.Status, item.RequestedBy, item.RequestIP, now,
+	)
+	if err != nil {
+		return model.ExportJob{}, err
+	}
+	item.CreatedAt = now.Format(time.RFC3339)
+	return item, nil
+}
+
+func (r *MySQLGrowthRepo) AdminGetExportJob(id string) (model.ExportJob, error) {
+	item, err := scanExportJob(r.db.QueryRow(`SELECT `+exportJobColumns+` FROM export_jobs WHERE id = ?`, strings.TrimSpace(id)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ExportJob{}, model.ErrExportJobNotFound
+	}
+	return item, err
+}
+
+func (r *MySQLGrowthRepo) AdminListExportJobs(requestedBy string, exportType string, status string, page int, pageSize int) ([]model.ExportJob, int, error) {
+	args := []interface{}{}
+	filter := " WHERE 1=1"
+	if requestedBy = strings.TrimSpace(requestedBy); requestedBy != "" {
+		filter += " AND requested_by = ?"
+		args = append(args, requestedBy)
+	}
+	if exportType = strings.ToUpper(strings.TrimSpace(exportType)); exportType != "" {
+		filter += " AND export_type = ?"
+		args = append(args, exportType)
+	}
+	if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+		filter += " AND status = ?"
+		args = append(args, status)
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM export_jobs"+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`SELECT `+exportJobColumns+` FROM export_jobs`+filter+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`, append(args, pageSize, (page-1)*pageSize)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.ExportJob, 0)
+	for rows.Next() {
+		item, err := scanExportJob(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// AdminClaimExportJobs moves up to limit PENDING jobs to RUNNING, oldest
+// first. A RUNNING job that started before staleBefore is assumed to belong
+// to a worker that died and is claimed again. Each claim is a conditional
+// update, so two workers never run the same job.
+func (r *MySQLGrowthRepo) AdminClaimExportJobs(limit int, staleBefore time.Time) ([]model.ExportJob, error) {
+	rows, err := r.db.Query(`
+SELECT id FROM export_jobs
+WHERE status = 'PENDING' OR (status = 'RUNNING' AND started_at < ?)
+ORDER BY created_at ASC
+LIMIT ?`, staleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	items := make([]model.ExportJob, 0, len(ids))
+	for _, id := range ids {
+		result, err := r.db.Exec(`
+UPDATE export_jobs SET status = 'RUNNING', started_at = ?
+WHERE id = ? AND (status = 'PENDING' OR (status = 'RUNNING' AND started_at < ?))`, time.Now(), id, staleBefore)
+		if err != nil {
+			return items, err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			continue
+		}
+		item, err := r.AdminGetExportJob(id)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// AdminFinishExportJob stores the outcome of a RUNNING job: the file for a
+// SUCCEEDED job, the error for a FAILED one.
+func (r *MySQLGrowthRepo) AdminFinishExportJob(item model.ExportJob) error {
+	_, err := r.db.Exec(`
+UPDATE export_jobs
+SET status = ?, row_count = ?, file_name = ?, file_size = ?, content_sha256 = ?, storage_ref = ?, error_message = ?, finished_at = ?, expires_at = ?
+WHERE id = ? AND status = 'RUNNING'`,
+		item.Status, item.RowCount, item.FileName, item.FileSize, item.ContentSHA256, item.StorageRef, truncateByRunes(item.ErrorMessage, 1000),
+		time.Now(), nullableTimeValue(item.ExpiresAt), item.ID,
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) AdminListExpiredExportJobs(before time.Time, limit int) ([]model.ExportJob, error) {
+	rows, err := r.db.Query(`SELECT `+exportJobColumns+` FROM export_jobs WHERE status = 'SUCCEEDED' AND expires_at <= ? ORDER BY expires_at ASC LIMIT ?`, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ExportJob, 0)
+	for rows.Next() {
+		item, err := scanExportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AdminExpireExportJob marks a job EXPIRED once its file has been deleted.
+// storage_ref is cleared but the rest of the row stays as the audit record.
+func (r *MySQLGrowthRepo) AdminExpireExportJob(id string) error {
+	_, err := r.db.Exec(`UPDATE export_jobs SET status = 'EXPIRED', storage_ref = '' WHERE id = ? AND status = 'SUCCEEDED'`, strings.TrimSpace(id))
+	return err
+}
+
+func (r *MySQLGrowthRepo) AdminRecordExportDownload(item model.ExportDownload) error {
+	now := time.Now()
+	if _, err := r.db.Exec(`
+INSERT INTO export_job_downloads (id, job_id, user_id, channel, client_ip, user_agent, downloaded_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		newID("expdl"), item.JobID, item.UserID, item.Channel, item.ClientIP, truncateByRunes(strings.TrimSpace(item.UserAgent), 255), now,
+	); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`UPDATE export_jobs SET download_count = download_count + 1, last_downloaded_at = ? WHERE id = ?`, now, item.JobID)
+	return err
+}
+
+// AdminRecordDeliveredExport records an export.csv response: a DELIVERED
+// job describing the file and its one DIRECT download, written together so
+// the audit view never shows one without the other.
+func (r *MySQLGrowthRepo) AdminRecordDeliveredExport(item model.ExportJob, download model.ExportDownload) (model.ExportJob, error) {
+	filters, err := json.Marshal(item.Filters)
+	if err != nil {
+		return model.ExportJob{}, err
+	}
+	item.ID = newID("exp")
+	item.Status = model.ExportJobStatusDelivered
+	item.DownloadCount = 1
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.ExportJob{}, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`
+INSERT INTO export_jobs (id, export_type, format, filters, status, row_count, file_name, file_size, content_sha256, requested_by, request_ip, download_count, created_at, started_at, finished_at, last_downloaded_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.ID, item.ExportType, item.Format, string(filters), item.Status, item.RowCount, item.FileName, item.FileSize, item.ContentSHA256,
+		item.RequestedBy, item.RequestIP, item.DownloadCount, now, now, now, now,
+	); err != nil {
+		return model.ExportJob{}, err
+	}
+	if _, err := tx.Exec(`
+INSERT INTO export_job_downloads (id, job_id, user_id, channel, client_ip, user_agent, downloaded_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		newID("expdl"), item.ID, download.UserID, download.Channel, download.ClientIP, truncateByRunes(strings.TrimSpace(download.UserAgent), 255), now,
+	); err != nil {
+		return model.ExportJob{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.ExportJob{}, err
+	}
+	item.CreatedAt = now.Format(time.RFC3339)
+	item.StartedAt = item.CreatedAt
+	item.FinishedAt = item.CreatedAt
+	item.LastDownloadedAt = item.CreatedAt
+	return item, nil
+}
+
+func (r *MySQLGrowthRepo) AdminListExportDownloads(jobID string) ([]model.ExportDownload, error) {
+	rows, err := r.db.Query(`
+SELECT id, job_id, user_id, channel, client_ip, user_agent, downloaded_at
+FROM export_job_downloads
+WHERE job_id = ?
+ORDER BY downloaded_at DESC, id DESC`, strings.TrimSpace(jobID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ExportDownload, 0)
+	for rows.Next() {
+		var item model.ExportDownload
+		var downloadedAt time.Time
+		if err := rows.Scan(&item.ID, &item.JobID, &item.UserID, &item.Channel, &item.ClientIP, &item.UserAgent, &downloadedAt); err != nil {
+			return nil, err
+		}
+		item.DownloadedAt = downloadedAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanExportJob(scanner interface{ Scan(dest ...any) error }) (model.ExportJob, error) {
+	var item model.ExportJob
+	var filters string
+	var createdAt time.Time
+	var startedAt, finishedAt, expiresAt, lastDownloadedAt sql.NullTime
+	if err := scanner.Scan(
+		&item.ID, &item.ExportType, &item.Format, &filters, &item.Status, &item.RowCount, &item.FileName, &item.FileSize,
+		&item.ContentSHA256, &item.StorageRef, &item.ErrorMessage, &item.RequestedBy, &item.RequestIP, &item.DownloadCount,
+		&createdAt, &startedAt, &finishedAt, &expiresAt, &lastDownloadedAt,
+	); err != nil {
+		return model.ExportJob{}, err
+	}
+	item.Filters = map[string]string{}
+	if strings.TrimSpace(filters) != "" {
+		if err := json.Unmarshal([]byte(filters), &item.Filters); err != nil {
+			return model.ExportJob{}, err
+		}
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	if startedAt.Valid {
+		item.StartedAt = startedAt.Time.Format(time.RFC3339)
+	}
+	if finishedAt.Valid {
+		item.FinishedAt = finishedAt.Time.Format(time.RFC3339)
+	}
+	if expiresAt.Valid {
+		item.ExpiresAt = expiresAt.Time.Format(time.RFC3339)
+	}
+	if lastDownloadedAt.Valid {
+		item.LastDownloadedAt = lastDownloadedAt.Time.Format(time.RFC3339)
+	}
+	return item, nil
+}
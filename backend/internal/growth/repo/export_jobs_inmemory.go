@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func (r *InMemoryGrowthRepo) AdminCreateExportJob(item model.ExportJob) (model.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item.ID = newID("exp")
+	item.Status = model.ExportJobStatusPending
+	item.CreatedAt = time.Now().Format(time.RFC3339)
+	filters := make(map[string]string, len(item.Filters))
+	for key, value := range item.Filters {
+		filters[key] = value
+	}
+	item.Filters = filters
+	r.exportJobs[item.ID] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetExportJob(id string) (model.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.exportJobs[strings.TrimSpace(id)]
+	if !ok {
+		return model.ExportJob{}, model.ErrExportJobNotFound
+	}
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListExportJobs(requestedBy string, exportType string, status string, page int, pageSize int) ([]model.ExportJob, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	requestedBy = strings.TrimSpace(requestedBy)
+	exportType = strings.ToUpper(strings.TrimSpace(exportType))
+	status = strings.ToUpper(strings.TrimSpace(status))
+	items := make([]model.ExportJob, 0)
+	for _, item := range r.exportJobs {
+		if requestedBy != "" && item.RequestedBy != requestedBy {
+			continue
+		}
+		if exportType != "" && item.ExportType != exportType {
+			continue
+		}
+		if status != "" && item.Status != status {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CreatedAt != items[j].CreatedAt {
+			return items[i].CreatedAt > items[j].CreatedAt
+		}
+		return items[i].ID > items[j].ID
+	})
+	total := len(items)
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminClaimExportJobs(limit int, staleBefore time.Time) ([]model.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	candidates := make([]model.ExportJob, 0)
+	for _, item := range r.exportJobs {
+		if item.Status == model.ExportJobStatusPending {
+			candidates = append(candidates, item)
+			continue
+		}
+		if started, err := time.Parse(time.RFC3339, item.StartedAt); item.Status == model.ExportJobStatusRunning && err == nil && started.Before(staleBefore) {
+			candidates = append(candidates, item)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CreatedAt != candidates[j].CreatedAt {
+			return candidates[i].CreatedAt < candidates[j].CreatedAt
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	now := time.Now().Format(time.RFC3339)
+	for i := range candidates {
+		candidates[i].Status = model.ExportJobStatusRunning
+		candidates[i].StartedAt = now
+		r.exportJobs[candidates[i].ID] = candidates[i]
+	}
+	return candidates, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminFinishExportJob(item model.ExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.exportJobs[item.ID]
+	if !ok || current.Status != model.ExportJobStatusRunning {
+		return nil
+	}
+	current.Status = item.Status
+	current.RowCount = item.RowCount
+	current.FileName = item.FileName
+	current.FileSize = item.FileSize
+	current.ContentSHA256 = item.ContentSHA256
+	current.StorageRef = item.StorageRef
+	current.ErrorMessage = truncateByRunes(item.ErrorMessage, 1000)
+	current.FinishedAt = time.Now().Format(time.RFC3339)
+	current.ExpiresAt = item.ExpiresAt
+	r.exportJobs[item.ID] = current
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListExpiredExportJobs(before time.Time, limit int) ([]model.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.ExportJob, 0)
+	for _, item := range r.exportJobs {
+		if item.Status != model.ExportJobStatusSucceeded {
+			continue
+		}
+		if expiresAt, err := time.Parse(time.RFC3339, item.ExpiresAt); err == nil && !expiresAt.After(before) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ExpiresAt < items[j].ExpiresAt })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminExpireExportJob(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.exportJobs[strings.TrimSpace(id)]
+	if !ok || item.Status != model.ExportJobStatusSucceeded {
+		return nil
+	}
+	item.Status = model.ExportJobStatusExpired
+	item.StorageRef = ""
+	r.exportJobs[item.ID] = item
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRecordExportDownload(item model.ExportDownload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().Format(time.RFC3339)
+	item.ID = newID("expdl")
+	item.UserAgent = truncateByRunes(strings.TrimSpace(item.UserAgent), 255)
+	item.DownloadedAt = now
+	r.exportDownloads = append(r.exportDownloads, item)
+	if job, ok := r.exportJobs[item.JobID]; ok {
+		job.DownloadCount++
+		job.LastDownloadedAt = now
+		r.exportJobs[job.ID] = job
+	}
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRecordDeliveredExport(item model.ExportJob, download model.ExportDownload) (model.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().Format(time.RFC3339)
+	item.ID = newID("exp")
+	item.Status = model.ExportJobStatusDelivered
+	item.DownloadCount = 1
+	item.CreatedAt = now
+	item.StartedAt = now
+	item.FinishedAt = now
+	item.LastDownloadedAt = now
+	filters := make(map[string]string, len(item.Filters))
+	for key, value := range item.Filters {
+		filters[key] = value
+	}
+	item.Filters = filters
+	r.exportJobs[item.ID] = item
+	download.ID = newID("expdl")
+	download.JobID = item.ID
+	download.UserAgent = truncateByRunes(strings.TrimSpace(download.UserAgent), 255)
+	download.DownloadedAt = now
+	r.exportDownloads = append(r.exportDownloads, download)
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListExportDownloads(jobID string) ([]model.ExportDownload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobID = strings.TrimSpace(jobID)
+	items := make([]model.ExportDownload, 0)
+	for i := len(r.exportDownloads) - 1; i >= 0; i-- {
+		if r.exportDownloads[i].JobID == jobID {
+			items = append(items, r.exportDownloads[i])
+		}
+	}
+	return items, nil
+}
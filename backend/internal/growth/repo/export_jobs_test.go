@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestMySQLAdminClaimExportJobsSkipsJobsClaimedElsewhere(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	staleBefore := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	created := staleBefore.Add(-time.Minute)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM export_jobs")).
+		WithArgs(staleBefore, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("exp_1").AddRow("exp_2"))
+	claim := regexp.QuoteMeta("UPDATE export_jobs SET status = 'RUNNING', started_at = ?")
+	mock.ExpectExec(claim).
+		WithArgs(sqlmock.AnyArg(), "exp_1", staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(claim).
+		WithArgs(sqlmock.AnyArg(), "exp_2", staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM export_jobs WHERE id = ?")).
+		WithArgs("exp_2").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "export_type", "format", "filters", "status", "row_count", "file_name", "file_size", "content_sha256", "storage_ref",
+			"error_message", "requested_by", "request_ip", "download_count", "created_at", "started_at", "finished_at", "expires_at", "last_downloaded_at",
+		}).AddRow("exp_2", "USERS", "XLSX", `{"status":"ACTIVE"}`, "RUNNING", 0, "", 0, "", "",
+			"", "admin_001", "10.0.0.1", 0, created, staleBefore, nil, nil, nil))
+
+	repo := &MySQLGrowthRepo{db: db}
+	items, err := repo.AdminClaimExportJobs(5, staleBefore)
+	if err != nil {
+		t.Fatalf("AdminClaimExportJobs() error = %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "exp_2" || items[0].Status != model.ExportJobStatusRunning || items[0].Filters["status"] != "ACTIVE" {
+		t.Fatalf("unexpected claimed jobs %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminRecordDeliveredExportWritesJobAndDownloadTogether(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO export_jobs")).
+		WithArgs(sqlmock.AnyArg(), "LOGIN_LOGS", "CSV", `{"status":"FAILED"}`, "DELIVERED", 3, "auth_login_logs.csv", int64(420), "abc123",
+			"admin_001", "10.0.0.1", 1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO export_job_downloads")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "admin_001", "DIRECT", "10.0.0.1", "curl/8.0", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	item, err := repo.AdminRecordDeliveredExport(model.ExportJob{
+		ExportType:    model.ExportTypeLoginLogs,
+		Format:        model.ExportFormatCSV,
+		Filters:       map[string]string{"status": "FAILED"},
+		RowCount:      3,
+		FileName:      "auth_login_logs.csv",
+		FileSize:      420,
+		ContentSHA256: "abc123",
+		RequestedBy:   "admin_001",
+		RequestIP:     "10.0.0.1",
+	}, model.ExportDownload{UserID: "admin_001", Channel: model.ExportDownloadChannelDirect, ClientIP: "10.0.0.1", UserAgent: "curl/8.0"})
+	if err != nil {
+		t.Fatalf("AdminRecordDeliveredExport() error = %v", err)
+	}
+	if item.ID == "" || item.Status != model.ExportJobStatusDelivered || item.DownloadCount != 1 || item.LastDownloadedAt == "" {
+		t.Fatalf("unexpected delivered job %+v", item)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
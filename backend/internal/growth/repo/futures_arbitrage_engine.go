@@ -0,0 +1,837 @@
+package repo
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const (
+	futuresArbitrageSourceEngine = "ENGINE"
+
+	futuresArbitrageTypeCalendar      = "CALENDAR"
+	futuresArbitrageTypeCrush         = "CRUSH"
+	futuresArbitrageTypeCrack         = "CRACK"
+	futuresArbitrageTypeInterProduct  = "INTER_PRODUCT"
+	futuresArbitrageTypeInterExchange = "INTER_EXCHANGE"
+
+	futuresArbitrageStatusOpen    = "OPEN"
+	futuresArbitrageStatusWatch   = "WATCH"
+	futuresArbitrageStatusExit    = "EXIT"
+	futuresArbitrageStatusStop    = "STOP"
+	futuresArbitrageStatusExpired = "EXPIRED"
+
+	futuresArbitrageDirectionBuySpread  = "BUY_SPREAD"
+	futuresArbitrageDirectionSellSpread = "SELL_SPREAD"
+)
+
+// arbitrageRecoListFilter is the WHERE clause of the arbitrage list
+// endpoints. It hides EXPIRED rows and anything computed before the latest
+// trade date, so a failed expiry pass cannot resurface stale signals. Rows
+// without a trade date predate the engine and stay visible.
+func arbitrageRecoListFilter(typeFilter string) (string, []interface{}) {
+	filter := ` WHERE status <> ?
+  AND (trade_date IS NULL OR trade_date = (SELECT MAX(latest.trade_date) FROM arbitrage_recos latest))`
+	args := []interface{}{futuresArbitrageStatusExpired}
+	if typeFilter != "" {
+		filter += " AND type = ?"
+		args = append(args, typeFilter)
+	}
+	return filter, args
+}
+
+type futuresArbitrageRuntimeConfig struct {
+	Enabled         bool
+	LookbackDays    int
+	MinSamples      int
+	EntryZ          float64
+	ExitZ           float64
+	StopZ           float64
+	CalendarEnabled bool
+	Pairs           []futuresArbitragePairDefinition
+}
+
+type futuresArbitrageLeg struct {
+	ProductKey   string  `json:"product_key"`
+	ExchangeCode string  `json:"exchange_code"`
+	Ratio        float64 `json:"ratio"`
+}
+
+// futuresArbitragePairDefinition describes a cross-product spread as
+// sum(ratio*close) of LegsA minus sum(ratio*close) of LegsB, each leg resolved to
+// its dominant contract on the computation date.
+type futuresArbitragePairDefinition struct {
+	Key   string                `json:"key"`
+	Type  string                `json:"type"`
+	Label string                `json:"label"`
+	LegsA []futuresArbitrageLeg `json:"legs_a"`
+	LegsB []futuresArbitrageLeg `json:"legs_b"`
+}
+
+type futuresArbitrageMapping struct {
+	ProductKey   string
+	ExchangeCode string
+	Dominant     string
+	Secondary    string
+	Near         string
+}
+
+type futuresArbitrageResolvedPair struct {
+	PairKey   string
+	Type      string
+	Label     string
+	ContractA string
+	ContractB string
+	LegsA     []futuresArbitrageResolvedLeg
+	LegsB     []futuresArbitrageResolvedLeg
+}
+
+type futuresArbitrageResolvedLeg struct {
+	InstrumentKey string
+	Ratio         float64
+}
+
+type futuresArbitrageSpreadStats struct {
+	Current    float64
+	Mean       float64
+	Std        float64
+	ZScore     float64
+	Percentile float64
+	HalfLife   float64
+	SampleDays int
+}
+
+type futuresArbitrageSignal struct {
+	Status      string
+	Direction   string
+	EntryPoint  float64
+	ExitPoint   float64
+	StopPoint   float64
+	RiskLevel   string
+	TriggerRule string
+}
+
+func defaultFuturesArbitragePairs() []futuresArbitragePairDefinition {
+	return []futuresArbitragePairDefinition{
+		{
+			Key:   "CRUSH_DCE_SOY",
+			Type:  futuresArbitrageTypeCrush,
+			Label: "大豆压榨利润(豆粕+豆油-豆一)",
+			LegsA: []futuresArbitrageLeg{{ProductKey: "M", ExchangeCode: "DCE", Ratio: 0.785}, {ProductKey: "Y", ExchangeCode: "DCE", Ratio: 0.185}},
+			LegsB: []futuresArbitrageLeg{{ProductKey: "A", ExchangeCode: "DCE", Ratio: 1}},
+		},
+		{
+			Key:   "CRACK_FU_SC",
+			Type:  futuresArbitrageTypeCrack,
+			Label: "燃料油裂解价差(燃料油-原油)",
+			LegsA: []futuresArbitrageLeg{{ProductKey: "FU", ExchangeCode: "SHF", Ratio: 0.1575}},
+			LegsB: []futuresArbitrageLeg{{ProductKey: "SC", ExchangeCode: "INE", Ratio: 1}},
+		},
+		{
+			Key:   "HC_RB_SHF",
+			Type:  futuresArbitrageTypeInterProduct,
+			Label: "卷螺差(热卷-螺纹)",
+			LegsA: []futuresArbitrageLeg{{ProductKey: "HC", ExchangeCode: "SHF", Ratio: 1}},
+			LegsB: []futuresArbitrageLeg{{ProductKey: "RB", ExchangeCode: "SHF", Ratio: 1}},
+		},
+		{
+			Key:   "CU_BC_SHF_INE",
+			Type:  futuresArbitrageTypeInterExchange,
+			Label: "沪铜-国际铜跨市场价差",
+			LegsA: []futuresArbitrageLeg{{ProductKey: "CU", ExchangeCode: "SHF", Ratio: 1}},
+			LegsB: []futuresArbitrageLeg{{ProductKey: "BC", ExchangeCode: "INE", Ratio: 1}},
+		},
+	}
+}
+
+func (r *MySQLGrowthRepo) resolveFuturesArbitrageRuntimeConfig() futuresArbitrageRuntimeConfig {
+	cfg := futuresArbitrageRuntimeConfig{
+		Enabled:         true,
+		LookbackDays:    120,
+		MinSamples:      20,
+		EntryZ:          2,
+		ExitZ:           0.5,
+		StopZ:           3,
+		CalendarEnabled: true,
+		Pairs:           defaultFuturesArbitragePairs(),
+	}
+	items, _, err := r.AdminListSystemConfigs("futures.arbitrage.", 1, 50)
+	if err != nil {
+		return cfg
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "futures.arbitrage.enabled":
+			cfg.Enabled = parseRepoConfigBool(value, cfg.Enabled)
+		case "futures.arbitrage.lookback_days":
+			cfg.LookbackDays = parseRepoConfigInt(value, cfg.LookbackDays)
+		case "futures.arbitrage.min_samples":
+			cfg.MinSamples = parseRepoConfigInt(value, cfg.MinSamples)
+		case "futures.arbitrage.entry_z":
+			cfg.EntryZ = parseRepoConfigFloat(value, cfg.EntryZ)
+		case "futures.arbitrage.exit_z":
+			cfg.ExitZ = parseRepoConfigFloat(value, cfg.ExitZ)
+		case "futures.arbitrage.stop_z":
+			cfg.StopZ = parseRepoConfigFloat(value, cfg.StopZ)
+		case "futures.arbitrage.calendar_enabled":
+			cfg.CalendarEnabled = parseRepoConfigBool(value, cfg.CalendarEnabled)
+		case "futures.arbitrage.pairs":
+			if pairs, ok := parseFuturesArbitragePairDefinitions(value); ok {
+				cfg.Pairs = pairs
+			}
+		}
+	}
+	return normalizeFuturesArbitrageRuntimeConfig(cfg)
+}
+
+func normalizeFuturesArbitrageRuntimeConfig(cfg futuresArbitrageRuntimeConfig) futuresArbitrageRuntimeConfig {
+	if cfg.LookbackDays < 20 {
+		cfg.LookbackDays = 20
+	}
+	if cfg.LookbackDays > 500 {
+		cfg.LookbackDays = 500
+	}
+	if cfg.MinSamples < 10 {
+		cfg.MinSamples = 10
+	}
+	if cfg.MinSamples > cfg.LookbackDays {
+		cfg.MinSamples = cfg.LookbackDays
+	}
+	if cfg.EntryZ <= 0 {
+		cfg.EntryZ = 2
+	}
+	if cfg.ExitZ < 0 || cfg.ExitZ >= cfg.EntryZ {
+		cfg.ExitZ = math.Min(0.5, cfg.EntryZ/2)
+	}
+	if cfg.StopZ <= cfg.EntryZ {
+		cfg.StopZ = cfg.EntryZ + 1
+	}
+	return cfg
+}
+
+func parseRepoConfigFloat(raw string, fallback float64) float64 {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return fallback
+	}
+	return value
+}
+
+func parseFuturesArbitragePairDefinitions(raw string) ([]futuresArbitragePairDefinition, bool) {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return nil, false
+	}
+	var items []futuresArbitragePairDefinition
+	if err := json.Unmarshal([]byte(text), &items); err != nil {
+		return nil, false
+	}
+	result := make([]futuresArbitragePairDefinition, 0, len(items))
+	for _, item := range items {
+		item.Key = strings.ToUpper(strings.TrimSpace(item.Key))
+		item.Type = strings.ToUpper(strings.TrimSpace(item.Type))
+		if item.Key == "" || len(item.LegsA) == 0 || len(item.LegsB) == 0 {
+			continue
+		}
+		if item.Type == "" {
+			item.Type = futuresArbitrageTypeInterProduct
+		}
+		item.LegsA = normalizeFuturesArbitrageLegs(item.LegsA)
+		item.LegsB = normalizeFuturesArbitrageLegs(item.LegsB)
+		if len(item.LegsA) == 0 || len(item.LegsB) == 0 {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, true
+}
+
+func normalizeFuturesArbitrageLegs(legs []futuresArbitrageLeg) []futuresArbitrageLeg {
+	result := make([]futuresArbitrageLeg, 0, len(legs))
+	for _, leg := range legs {
+		leg.ProductKey = strings.ToUpper(strings.TrimSpace(leg.ProductKey))
+		leg.ExchangeCode = strings.ToUpper(strings.TrimSpace(leg.ExchangeCode))
+		if leg.ProductKey == "" || leg.Ratio == 0 {
+			continue
+		}
+		result = append(result, leg)
+	}
+	return result
+}
+
+// buildFuturesArbitrageResolvedPairs turns the day's contract mappings and the
+// configured cross-product definitions into concrete instrument pairs.
+func buildFuturesArbitrageResolvedPairs(mappings []futuresArbitrageMapping, cfg futuresArbitrageRuntimeConfig) ([]futuresArbitrageResolvedPair, []string) {
+	pairs := make([]futuresArbitrageResolvedPair, 0, len(mappings)+len(cfg.Pairs))
+	warnings := make([]string, 0)
+	dominantByProduct := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		dominant := strings.ToUpper(strings.TrimSpace(mapping.Dominant))
+		if dominant == "" {
+			continue
+		}
+		dominantByProduct[mapping.ProductKey+"."+mapping.ExchangeCode] = dominant
+		if _, ok := dominantByProduct[mapping.ProductKey]; !ok {
+			dominantByProduct[mapping.ProductKey] = dominant
+		}
+		if !cfg.CalendarEnabled {
+			continue
+		}
+		deferred := strings.ToUpper(strings.TrimSpace(firstNonEmpty(mapping.Secondary, mapping.Near)))
+		if deferred == "" || deferred == dominant {
+			continue
+		}
+		near, far := orderFuturesArbitrageCalendarLegs(dominant, deferred)
+		pairs = append(pairs, futuresArbitrageResolvedPair{
+			PairKey:   futuresArbitrageTypeCalendar + ":" + normalizeFuturesContextContract(near) + "/" + normalizeFuturesContextContract(far),
+			Type:      futuresArbitrageTypeCalendar,
+			Label:     mapping.ProductKey + " 跨期价差",
+			ContractA: normalizeFuturesContextContract(near),
+			ContractB: normalizeFuturesContextContract(far),
+			LegsA:     []futuresArbitrageResolvedLeg{{InstrumentKey: near, Ratio: 1}},
+			LegsB:     []futuresArbitrageResolvedLeg{{InstrumentKey: far, Ratio: 1}},
+		})
+	}
+	for _, definition := range cfg.Pairs {
+		legsA, missingA := resolveFuturesArbitrageLegs(definition.LegsA, dominantByProduct)
+		legsB, missingB := resolveFuturesArbitrageLegs(definition.LegsB, dominantByProduct)
+		if missing := append(missingA, missingB...); len(missing) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s missing dominant contract for %s", definition.Key, strings.Join(missing, ",")))
+			continue
+		}
+		pairs = append(pairs, futuresArbitrageResolvedPair{
+			PairKey:   definition.Type + ":" + definition.Key,
+			Type:      definition.Type,
+			Label:     firstNonEmpty(definition.Label, definition.Key),
+			ContractA: joinFuturesArbitrageLegContracts(legsA),
+			ContractB: joinFuturesArbitrageLegContracts(legsB),
+			LegsA:     legsA,
+			LegsB:     legsB,
+		})
+	}
+	return pairs, warnings
+}
+
+func resolveFuturesArbitrageLegs(legs []futuresArbitrageLeg, dominantByProduct map[string]string) ([]futuresArbitrageResolvedLeg, []string) {
+	resolved := make([]futuresArbitrageResolvedLeg, 0, len(legs))
+	missing := make([]string, 0)
+	for _, leg := range legs {
+		key := leg.ProductKey
+		if leg.ExchangeCode != "" {
+			key += "." + leg.ExchangeCode
+		}
+		instrumentKey, ok := dominantByProduct[key]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		resolved = append(resolved, futuresArbitrageResolvedLeg{InstrumentKey: instrumentKey, Ratio: leg.Ratio})
+	}
+	return resolved, missing
+}
+
+func orderFuturesArbitrageCalendarLegs(a string, b string) (string, string) {
+	_, expiryA, okA := splitFuturesContractCode(normalizeFuturesContextContract(a))
+	_, expiryB, okB := splitFuturesContractCode(normalizeFuturesContextContract(b))
+	if okA && okB && expiryB < expiryA {
+		return b, a
+	}
+	return a, b
+}
+
+func joinFuturesArbitrageLegContracts(legs []futuresArbitrageResolvedLeg) string {
+	parts := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		parts = append(parts, normalizeFuturesContextContract(leg.InstrumentKey))
+	}
+	return strings.Join(parts, "+")
+}
+
+func futuresArbitrageRecoID(pairKey string) string {
+	sum := sha1.Sum([]byte(pairKey))
+	return "arb_" + hex.EncodeToString(sum[:])[:24]
+}
+
+// buildFuturesArbitrageSpreadSeries aligns the legs on trade dates where every
+// leg has a close and returns the spread series in ascending date order.
+func buildFuturesArbitrageSpreadSeries(pair futuresArbitrageResolvedPair, closes map[string]map[string]float64) ([]string, []float64) {
+	legs := append(append([]futuresArbitrageResolvedLeg{}, pair.LegsA...), pair.LegsB...)
+	if len(legs) == 0 {
+		return nil, nil
+	}
+	dates := make([]string, 0, len(closes[legs[0].InstrumentKey]))
+	for date := range closes[legs[0].InstrumentKey] {
+		complete := true
+		for _, leg := range legs[1:] {
+			if _, ok := closes[leg.InstrumentKey][date]; !ok {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	values := make([]float64, 0, len(dates))
+	for _, date := range dates {
+		spread := 0.0
+		for _, leg := range pair.LegsA {
+			spread += leg.Ratio * closes[leg.InstrumentKey][date]
+		}
+		for _, leg := range pair.LegsB {
+			spread -= leg.Ratio * closes[leg.InstrumentKey][date]
+		}
+		values = append(values, spread)
+	}
+	return dates, values
+}
+
+// computeFuturesArbitrageSpreadStats measures where the latest spread sits in its
+// own history: percentile rank, z-score and the AR(1) mean-reversion half-life.
+func computeFuturesArbitrageSpreadStats(values []float64) futuresArbitrageSpreadStats {
+	stats := futuresArbitrageSpreadStats{SampleDays: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+	stats.Current = values[len(values)-1]
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	stats.Mean = sum / float64(len(values))
+	variance := 0.0
+	below := 0
+	for _, value := range values {
+		variance += (value - stats.Mean) * (value - stats.Mean)
+		if value <= stats.Current {
+			below++
+		}
+	}
+	if len(values) > 1 {
+		stats.Std = math.Sqrt(variance / float64(len(values)-1))
+	}
+	stats.Percentile = float64(below) / float64(len(values))
+	if stats.Std > 0 {
+		stats.ZScore = (stats.Current - stats.Mean) / stats.Std
+	}
+	stats.HalfLife = computeFuturesArbitrageHalfLife(values)
+	return stats
+}
+
+// computeFuturesArbitrageHalfLife fits Δs(t) = a + b*s(t-1) by least squares; a
+// negative b means the spread reverts with half-life -ln(2)/b. Zero means the
+// series shows no mean reversion.
+func computeFuturesArbitrageHalfLife(values []float64) float64 {
+	if len(values) < 3 {
+		return 0
+	}
+	n := float64(len(values) - 1)
+	sumX, sumY, sumXY, sumXX := 0.0, 0.0, 0.0, 0.0
+	for i := 1; i < len(values); i++ {
+		x := values[i-1]
+		y := values[i] - values[i-1]
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	beta := (n*sumXY - sumX*sumY) / denominator
+	if beta >= 0 || beta <= -2 {
+		return 0
+	}
+	return -math.Ln2 / math.Log(1+beta)
+}
+
+func deriveFuturesArbitrageSignal(stats futuresArbitrageSpreadStats, cfg futuresArbitrageRuntimeConfig) futuresArbitrageSignal {
+	signal := futuresArbitrageSignal{Direction: futuresArbitrageDirectionSellSpread}
+	sign := 1.0
+	if stats.ZScore < 0 {
+		signal.Direction = futuresArbitrageDirectionBuySpread
+		sign = -1
+	}
+	absZ := math.Abs(stats.ZScore)
+	switch {
+	case absZ >= cfg.StopZ:
+		signal.Status = futuresArbitrageStatusStop
+	case absZ >= cfg.EntryZ:
+		signal.Status = futuresArbitrageStatusOpen
+	case absZ <= cfg.ExitZ:
+		signal.Status = futuresArbitrageStatusExit
+	default:
+		signal.Status = futuresArbitrageStatusWatch
+	}
+	signal.EntryPoint = roundTo(stats.Mean+sign*cfg.EntryZ*stats.Std, 2)
+	signal.ExitPoint = roundTo(stats.Mean+sign*cfg.ExitZ*stats.Std, 2)
+	signal.StopPoint = roundTo(stats.Mean+sign*cfg.StopZ*stats.Std, 2)
+
+	switch {
+	case signal.Status == futuresArbitrageStatusStop || stats.HalfLife <= 0 || stats.HalfLife > 30:
+		signal.RiskLevel = "HIGH"
+	case stats.HalfLife > 10:
+		signal.RiskLevel = "MEDIUM"
+	default:
+		signal.RiskLevel = "LOW"
+	}
+
+	action := "卖出价差(空A多B)"
+	if signal.Direction == futuresArbitrageDirectionBuySpread {
+		action = "买入价差(多A空B)"
+	}
+	signal.TriggerRule = fmt.Sprintf(
+		"价差偏离均值 %.2f 个标准差；|z|≥%.1f 于 %.2f 附近%s，回归至 %.2f 离场，突破 %.2f 止损",
+		roundTo(stats.ZScore, 2),
+		cfg.EntryZ,
+		signal.EntryPoint,
+		action,
+		signal.ExitPoint,
+		signal.StopPoint,
+	)
+	return signal
+}
+
+func (r *MySQLGrowthRepo) AdminRunFuturesArbitrageEngine(tradeDate string) (model.FuturesArbitrageEngineResult, error) {
+	cfg := r.resolveFuturesArbitrageRuntimeConfig()
+	result := model.FuturesArbitrageEngineResult{Items: make([]model.FuturesArbitrageEngineItem, 0)}
+	if !cfg.Enabled {
+		result.Warnings = append(result.Warnings, "futures arbitrage engine disabled")
+		return result, nil
+	}
+
+	selectedDate, err := r.resolveFuturesArbitrageTradeDate(tradeDate)
+	if err != nil {
+		return result, err
+	}
+	if selectedDate.IsZero() {
+		result.Warnings = append(result.Warnings, "no futures contract mappings available")
+		return result, nil
+	}
+	result.TradeDate = selectedDate.Format("2006-01-02")
+
+	mappings, err := r.loadFuturesArbitrageMappings(selectedDate)
+	if err != nil {
+		return result, err
+	}
+	pairs, warnings := buildFuturesArbitrageResolvedPairs(mappings, cfg)
+	result.PairCount = len(pairs)
+	result.Warnings = append(result.Warnings, warnings...)
+	if len(pairs) == 0 {
+		return result, nil
+	}
+
+	instrumentKeys := make([]string, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		for _, leg := range append(append([]futuresArbitrageResolvedLeg{}, pair.LegsA...), pair.LegsB...) {
+			instrumentKeys = append(instrumentKeys, leg.InstrumentKey)
+		}
+	}
+	// Calendar days are padded so the window still covers LookbackDays sessions
+	// across weekends and exchange holidays.
+	startDate := selectedDate.AddDate(0, 0, -cfg.LookbackDays*2)
+	closes, err := r.loadFuturesArbitrageCloses(compactStrings(instrumentKeys), startDate, selectedDate)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	for _, pair := range pairs {
+		dates, values := buildFuturesArbitrageSpreadSeries(pair, closes)
+		if len(values) > cfg.LookbackDays {
+			dates = dates[len(dates)-cfg.LookbackDays:]
+			values = values[len(values)-cfg.LookbackDays:]
+		}
+		if len(values) < cfg.MinSamples || dates[len(dates)-1] != result.TradeDate {
+			result.Skipped++
+			continue
+		}
+		stats := computeFuturesArbitrageSpreadStats(values)
+		if stats.Std <= 0 {
+			result.Skipped++
+			continue
+		}
+		signal := deriveFuturesArbitrageSignal(stats, cfg)
+		id := futuresArbitrageRecoID(pair.PairKey)
+		if err := r.upsertFuturesArbitrageReco(id, pair, stats, signal, selectedDate, now); err != nil {
+			return result, err
+		}
+		if err := r.upsertFuturesArbitrageSnapshot(pair.PairKey, selectedDate, stats, now); err != nil {
+			return result, err
+		}
+		result.Computed++
+		switch signal.Status {
+		case futuresArbitrageStatusOpen:
+			result.OpenSignals++
+		case futuresArbitrageStatusExit:
+			result.ExitSignals++
+		}
+		result.Items = append(result.Items, model.FuturesArbitrageEngineItem{
+			ID:         id,
+			PairKey:    pair.PairKey,
+			Type:       pair.Type,
+			ContractA:  pair.ContractA,
+			ContractB:  pair.ContractB,
+			Spread:     roundTo(stats.Current, 2),
+			Percentile: roundTo(stats.Percentile, 4),
+			ZScore:     roundTo(stats.ZScore, 4),
+			HalfLife:   roundTo(stats.HalfLife, 2),
+			SampleDays: stats.SampleDays,
+			Direction:  signal.Direction,
+			RiskLevel:  signal.RiskLevel,
+			Status:     signal.Status,
+		})
+	}
+
+	expired, err := r.db.Exec(`
+UPDATE arbitrage_recos
+SET status = ?, updated_at = ?
+WHERE source = ? AND trade_date < ? AND status <> ?`,
+		futuresArbitrageStatusExpired, now, futuresArbitrageSourceEngine, result.TradeDate, futuresArbitrageStatusExpired,
+	)
+	if err != nil {
+		return result, err
+	}
+	if affected, affectedErr := expired.RowsAffected(); affectedErr == nil {
+		result.Expired = int(affected)
+	}
+	return result, nil
+}
+
+func (r *MySQLGrowthRepo) resolveFuturesArbitrageTradeDate(tradeDate string) (time.Time, error) {
+	if text := strings.TrimSpace(tradeDate); text != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", text, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid trade_date: %s", text)
+		}
+		return parsed, nil
+	}
+	var selected sql.NullTime
+	if err := r.db.QueryRow("SELECT MAX(trade_date) FROM futures_contract_mappings").Scan(&selected); err != nil {
+		if isMarketStatusSchemaCompatError(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if !selected.Valid {
+		return time.Time{}, nil
+	}
+	return selected.Time, nil
+}
+
+func (r *MySQLGrowthRepo) loadFuturesArbitrageMappings(tradeDate time.Time) ([]futuresArbitrageMapping, error) {
+	rows, err := r.db.Query(`
+SELECT product_key, exchange_code, dominant_instrument_key, COALESCE(secondary_instrument_key, ''), COALESCE(near_instrument_key, '')
+FROM futures_contract_mappings
+WHERE trade_date = ?
+ORDER BY product_key ASC, exchange_code ASC`, tradeDate.Format("2006-01-02"))
+	if err != nil {
+		if isMarketStatusSchemaCompatError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]futuresArbitrageMapping, 0)
+	for rows.Next() {
+		var item futuresArbitrageMapping
+		if err := rows.Scan(&item.ProductKey, &item.ExchangeCode, &item.Dominant, &item.Secondary, &item.Near); err != nil {
+			return nil, err
+		}
+		item.ProductKey = strings.ToUpper(strings.TrimSpace(item.ProductKey))
+		item.ExchangeCode = strings.ToUpper(strings.TrimSpace(item.ExchangeCode))
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) loadFuturesArbitrageCloses(instrumentKeys []string, startDate time.Time, endDate time.Time) (map[string]map[string]float64, error) {
+	result := make(map[string]map[string]float64, len(instrumentKeys))
+	if len(instrumentKeys) == 0 {
+		return result, nil
+	}
+	placeholders := strings.TrimRight(strings.Repeat("?,", len(instrumentKeys)), ",")
+	args := []any{marketAssetClassFutures, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")}
+	for _, key := range instrumentKeys {
+		args = append(args, key)
+	}
+	rows, err := r.db.Query(`
+SELECT instrument_key, trade_date, COALESCE(settle_price, close_price)
+FROM market_daily_bar_truth
+WHERE asset_class = ? AND trade_date BETWEEN ? AND ? AND instrument_key IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var instrumentKey string
+		var tradeDate time.Time
+		var price float64
+		if err := rows.Scan(&instrumentKey, &tradeDate, &price); err != nil {
+			return nil, err
+		}
+		if price <= 0 {
+			continue
+		}
+		instrumentKey = strings.ToUpper(strings.TrimSpace(instrumentKey))
+		if result[instrumentKey] == nil {
+			result[instrumentKey] = make(map[string]float64)
+		}
+		result[instrumentKey][tradeDate.Format("2006-01-02")] = price
+	}
+	return result, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) upsertFuturesArbitrageReco(id string, pair futuresArbitrageResolvedPair, stats futuresArbitrageSpreadStats, signal futuresArbitrageSignal, tradeDate time.Time, now time.Time) error {
+	_, err := r.db.Exec(`
+INSERT INTO arbitrage_recos
+  (id, type, contract_a, contract_b, spread, percentile, entry_point, exit_point, stop_point, trigger_rule, status,
+   z_score, half_life, spread_mean, spread_std, sample_days, direction, risk_level, trade_date, pair_key, source, updated_at)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  type = VALUES(type),
+  contract_a = VALUES(contract_a),
+  contract_b = VALUES(contract_b),
+  spread = VALUES(spread),
+  percentile = VALUES(percentile),
+  entry_point = VALUES(entry_point),
+  exit_point = VALUES(exit_point),
+  stop_point = VALUES(stop_point),
+  trigger_rule = VALUES(trigger_rule),
+  status = VALUES(status),
+  z_score = VALUES(z_score),
+  half_life = VALUES(half_life),
+  spread_mean = VALUES(spread_mean),
+  spread_std = VALUES(spread_std),
+  sample_days = VALUES(sample_days),
+  direction = VALUES(direction),
+  risk_level = VALUES(risk_level),
+  trade_date = VALUES(trade_date),
+  source = VALUES(source),
+  updated_at = VALUES(updated_at)`,
+		id,
+		pair.Type,
+		truncateByRunes(pair.ContractA, 32),
+		truncateByRunes(pair.ContractB, 32),
+		roundTo(stats.Current, 2),
+		roundTo(stats.Percentile, 4),
+		signal.EntryPoint,
+		signal.ExitPoint,
+		signal.StopPoint,
+		truncateByRunes(pair.Label+"："+signal.TriggerRule, 256),
+		signal.Status,
+		roundTo(stats.ZScore, 4),
+		roundTo(stats.HalfLife, 2),
+		roundTo(stats.Mean, 4),
+		roundTo(stats.Std, 4),
+		stats.SampleDays,
+		signal.Direction,
+		signal.RiskLevel,
+		tradeDate.Format("2006-01-02"),
+		pair.PairKey,
+		futuresArbitrageSourceEngine,
+		now,
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) upsertFuturesArbitrageSnapshot(pairKey string, tradeDate time.Time, stats futuresArbitrageSpreadStats, now time.Time) error {
+	_, err := r.db.Exec(`
+INSERT INTO futures_arbitrage_spread_snapshots
+  (id, pair_key, trade_date, spread, z_score, percentile, spread_mean, spread_std, created_at, updated_at)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  spread = VALUES(spread),
+  z_score = VALUES(z_score),
+  percentile = VALUES(percentile),
+  spread_mean = VALUES(spread_mean),
+  spread_std = VALUES(spread_std),
+  updated_at = VALUES(updated_at)`,
+		newID("fas"),
+		pairKey,
+		tradeDate.Format("2006-01-02"),
+		roundTo(stats.Current, 4),
+		roundTo(stats.ZScore, 4),
+		roundTo(stats.Percentile, 4),
+		roundTo(stats.Mean, 4),
+		roundTo(stats.Std, 4),
+		now,
+		now,
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) loadFuturesArbitrageHistory(pairKey string, limit int) ([]model.ArbitrageSpreadPoint, error) {
+	if strings.TrimSpace(pairKey) == "" || limit <= 0 {
+		return nil, nil
+	}
+	rows, err := r.db.Query(`
+SELECT trade_date, spread, z_score, percentile
+FROM futures_arbitrage_spread_snapshots
+WHERE pair_key = ?
+ORDER BY trade_date DESC
+LIMIT ?`, pairKey, limit)
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ArbitrageSpreadPoint, 0, limit)
+	for rows.Next() {
+		var item model.ArbitrageSpreadPoint
+		var tradeDate time.Time
+		if err := rows.Scan(&tradeDate, &item.Spread, &item.ZScore, &item.Percentile); err != nil {
+			return nil, err
+		}
+		item.TradeDate = tradeDate.Format("2006-01-02")
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	return items, nil
+}
+
+// arbitrageRecoEngineColumns holds the engine-owned columns of arbitrage_recos;
+// they stay NULL on manually curated rows.
+type arbitrageRecoEngineColumns struct {
+	ZScore    sql.NullFloat64
+	HalfLife  sql.NullFloat64
+	Direction sql.NullString
+	RiskLevel sql.NullString
+	TradeDate sql.NullTime
+}
+
+func (c arbitrageRecoEngineColumns) apply(item *model.ArbitrageRecommendation) {
+	item.ZScore = c.ZScore.Float64
+	item.HalfLife = c.HalfLife.Float64
+	item.Direction = c.Direction.String
+	item.RiskLevel = c.RiskLevel.String
+	if c.TradeDate.Valid {
+		item.TradeDate = c.TradeDate.Time.Format("2006-01-02")
+	}
+}
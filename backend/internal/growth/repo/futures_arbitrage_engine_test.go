@@ -0,0 +1,224 @@
+package repo
+
+import (
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestComputeFuturesArbitrageSpreadStatsMeasuresLatestDeviation(t *testing.T) {
+	values := []float64{100, 102, 98, 101, 99, 100, 103, 97, 100, 112}
+	stats := computeFuturesArbitrageSpreadStats(values)
+
+	if stats.SampleDays != len(values) || stats.Current != 112 {
+		t.Fatalf("unexpected sample bookkeeping: %+v", stats)
+	}
+	if math.Abs(stats.Mean-101.2) > 1e-9 {
+		t.Fatalf("expected mean 101.2, got %f", stats.Mean)
+	}
+	if stats.Percentile != 1 {
+		t.Fatalf("expected latest spread at the top of its range, got percentile %f", stats.Percentile)
+	}
+	if stats.ZScore < 2 {
+		t.Fatalf("expected latest spread to be more than two deviations rich, got z=%f", stats.ZScore)
+	}
+}
+
+func TestComputeFuturesArbitrageHalfLifeDetectsMeanReversion(t *testing.T) {
+	reverting := make([]float64, 0, 60)
+	value := 10.0
+	for i := 0; i < 60; i++ {
+		reverting = append(reverting, value)
+		value = value*0.8 + float64((i%3)-1)
+	}
+	if halfLife := computeFuturesArbitrageHalfLife(reverting); halfLife <= 0 || halfLife > 10 {
+		t.Fatalf("expected a short positive half-life for a reverting series, got %f", halfLife)
+	}
+
+	trending := make([]float64, 0, 30)
+	for i := 0; i < 30; i++ {
+		trending = append(trending, float64(i*i))
+	}
+	if halfLife := computeFuturesArbitrageHalfLife(trending); halfLife != 0 {
+		t.Fatalf("expected no half-life for an explosive series, got %f", halfLife)
+	}
+}
+
+func TestDeriveFuturesArbitrageSignalBands(t *testing.T) {
+	cfg := normalizeFuturesArbitrageRuntimeConfig(futuresArbitrageRuntimeConfig{EntryZ: 2, ExitZ: 0.5, StopZ: 3, LookbackDays: 120, MinSamples: 20})
+
+	open := deriveFuturesArbitrageSignal(futuresArbitrageSpreadStats{Mean: 100, Std: 10, ZScore: -2.4, HalfLife: 6}, cfg)
+	if open.Status != futuresArbitrageStatusOpen || open.Direction != futuresArbitrageDirectionBuySpread {
+		t.Fatalf("expected buy-spread entry signal, got %+v", open)
+	}
+	if open.EntryPoint != 80 || open.ExitPoint != 95 || open.StopPoint != 70 || open.RiskLevel != "LOW" {
+		t.Fatalf("unexpected buy-spread levels: %+v", open)
+	}
+
+	exit := deriveFuturesArbitrageSignal(futuresArbitrageSpreadStats{Mean: 100, Std: 10, ZScore: 0.3, HalfLife: 15}, cfg)
+	if exit.Status != futuresArbitrageStatusExit || exit.RiskLevel != "MEDIUM" {
+		t.Fatalf("expected exit signal near the mean, got %+v", exit)
+	}
+
+	stop := deriveFuturesArbitrageSignal(futuresArbitrageSpreadStats{Mean: 100, Std: 10, ZScore: 3.4, HalfLife: 6}, cfg)
+	if stop.Status != futuresArbitrageStatusStop || stop.Direction != futuresArbitrageDirectionSellSpread || stop.RiskLevel != "HIGH" {
+		t.Fatalf("expected stop signal beyond the stop band, got %+v", stop)
+	}
+}
+
+func TestBuildFuturesArbitrageResolvedPairsUsesMappings(t *testing.T) {
+	cfg := normalizeFuturesArbitrageRuntimeConfig(futuresArbitrageRuntimeConfig{
+		CalendarEnabled: true,
+		Pairs:           defaultFuturesArbitragePairs(),
+	})
+	mappings := []futuresArbitrageMapping{
+		{ProductKey: "RB", ExchangeCode: "SHF", Dominant: "RB2610.SHF", Secondary: "RB2605.SHF"},
+		{ProductKey: "HC", ExchangeCode: "SHF", Dominant: "HC2610.SHF"},
+	}
+
+	pairs, warnings := buildFuturesArbitrageResolvedPairs(mappings, cfg)
+
+	byKey := make(map[string]futuresArbitrageResolvedPair, len(pairs))
+	for _, pair := range pairs {
+		byKey[pair.PairKey] = pair
+	}
+	calendar, ok := byKey["CALENDAR:RB2605/RB2610"]
+	if !ok || calendar.ContractA != "RB2605" || calendar.ContractB != "RB2610" {
+		t.Fatalf("expected near/far ordered calendar pair, got %+v", pairs)
+	}
+	interProduct, ok := byKey["INTER_PRODUCT:HC_RB_SHF"]
+	if !ok || interProduct.ContractA != "HC2610" || interProduct.ContractB != "RB2610" {
+		t.Fatalf("expected hot-rolled/rebar pair resolved to dominant contracts, got %+v", pairs)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected pairs without mappings to be reported, got %v", warnings)
+	}
+}
+
+func TestBuildFuturesArbitrageSpreadSeriesAlignsLegs(t *testing.T) {
+	pair := futuresArbitrageResolvedPair{
+		LegsA: []futuresArbitrageResolvedLeg{{InstrumentKey: "M2609.DCE", Ratio: 0.5}, {InstrumentKey: "Y2609.DCE", Ratio: 0.1}},
+		LegsB: []futuresArbitrageResolvedLeg{{InstrumentKey: "A2609.DCE", Ratio: 1}},
+	}
+	closes := map[string]map[string]float64{
+		"M2609.DCE": {"2026-03-02": 3000, "2026-03-03": 3100, "2026-03-04": 3050},
+		"Y2609.DCE": {"2026-03-02": 8000, "2026-03-04": 8200},
+		"A2609.DCE": {"2026-03-02": 2000, "2026-03-03": 2050, "2026-03-04": 2100},
+	}
+
+	dates, values := buildFuturesArbitrageSpreadSeries(pair, closes)
+
+	if len(dates) != 2 || dates[0] != "2026-03-02" || dates[1] != "2026-03-04" {
+		t.Fatalf("expected only fully priced dates, got %v", dates)
+	}
+	if values[0] != 300 || values[1] != 245 {
+		t.Fatalf("unexpected spread values: %v", values)
+	}
+}
+
+func TestMySQLGetFuturesArbitrageDetailLoadsEngineHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := &MySQLGrowthRepo{db: db}
+	tradeDate := time.Date(2026, 3, 30, 0, 0, 0, 0, time.Local)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM arbitrage_recos")).
+		WithArgs("arb_engine").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "type", "contract_a", "contract_b", "spread", "percentile", "entry_point", "exit_point", "stop_point", "trigger_rule", "status",
+			"z_score", "half_life", "direction", "risk_level", "trade_date", "spread_mean", "spread_std", "sample_days", "pair_key",
+		}).AddRow(
+			"arb_engine", "CALENDAR", "RB2605", "RB2610", 120, 0.95, 118, 92, 130, "rule", "OPEN",
+			2.3, 6.5, "SELL_SPREAD", "LOW", tradeDate, 90, 13, 120, "CALENDAR:RB2605/RB2610",
+		))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM futures_arbitrage_spread_snapshots")).
+		WithArgs("CALENDAR:RB2605/RB2610", 120).
+		WillReturnRows(sqlmock.NewRows([]string{"trade_date", "spread", "z_score", "percentile"}).
+			AddRow(tradeDate, 120, 2.3, 0.95).
+			AddRow(tradeDate.AddDate(0, 0, -1), 110, 1.5, 0.9))
+
+	item, err := repo.GetFuturesArbitrageDetail("arb_engine")
+	if err != nil {
+		t.Fatalf("GetFuturesArbitrageDetail() error = %v", err)
+	}
+	if item.ZScore != 2.3 || item.Direction != "SELL_SPREAD" || item.TradeDate != "2026-03-30" || item.SampleDays != 120 {
+		t.Fatalf("unexpected engine columns: %+v", item)
+	}
+	if len(item.History) != 2 || item.History[0].TradeDate != "2026-03-29" {
+		t.Fatalf("expected ascending spread history, got %+v", item.History)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLListFuturesArbitrageHidesExpiredAndStaleRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := &MySQLGrowthRepo{db: db}
+	visible := "WHERE status <> ?\n  AND (trade_date IS NULL OR trade_date = (SELECT MAX(latest.trade_date) FROM arbitrage_recos latest)) AND type = ?"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM arbitrage_recos "+visible)).
+		WithArgs("EXPIRED", "CALENDAR").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM arbitrage_recos "+visible)).
+		WithArgs("EXPIRED", "CALENDAR", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "type", "contract_a", "contract_b", "spread", "percentile", "entry_point", "exit_point", "stop_point", "status",
+			"z_score", "half_life", "direction", "risk_level", "trade_date",
+		}).AddRow(
+			"arb_engine", "CALENDAR", "RB2605", "RB2610", 120, 0.95, 118, 92, 130, "OPEN",
+			2.3, 6.5, "SELL_SPREAD", "LOW", time.Date(2026, 3, 30, 0, 0, 0, 0, time.Local),
+		))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM arbitrage_recos "+visible)).
+		WithArgs("EXPIRED", "CALENDAR").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM arbitrage_recos "+visible)).
+		WithArgs("EXPIRED", "CALENDAR", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "type", "contract_a", "contract_b", "spread", "percentile", "z_score", "half_life", "risk_level", "status",
+		}))
+
+	items, total, err := repo.ListFuturesArbitrage("CALENDAR", 1, 20)
+	if err != nil {
+		t.Fatalf("ListFuturesArbitrage() error = %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].TradeDate != "2026-03-30" {
+		t.Fatalf("unexpected futures arbitrage list: total=%d items=%+v", total, items)
+	}
+	if _, _, err := repo.ListArbitrageOpportunities("CALENDAR", 1, 20); err != nil {
+		t.Fatalf("ListArbitrageOpportunities() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestInMemoryArbitrageListsHideExpiredRows(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	recos, total, err := repo.ListFuturesArbitrage("", 1, 20)
+	if err != nil {
+		t.Fatalf("ListFuturesArbitrage() error = %v", err)
+	}
+	opportunities, _, err := repo.ListArbitrageOpportunities("", 1, 20)
+	if err != nil {
+		t.Fatalf("ListArbitrageOpportunities() error = %v", err)
+	}
+	if total != 1 || len(recos) != 1 || len(opportunities) != 1 {
+		t.Fatalf("expected only the latest pair, got recos=%+v opportunities=%+v", recos, opportunities)
+	}
+	for _, item := range recos {
+		if item.Status == futuresArbitrageStatusExpired || item.TradeDate != "2026-02-24" {
+			t.Fatalf("expected expired and earlier rows to be hidden, got %+v", item)
+		}
+	}
+}
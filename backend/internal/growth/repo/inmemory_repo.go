@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+	"sercherai/backend/internal/platform/realtime"
 )
 
 type InMemoryGrowthRepo struct {
@@ -26,13 +28,44 @@ type InMemoryGrowthRepo struct {
 	stockEventClusters        map[string]model.StockEventCluster
 	futuresInstrumentProfiles map[string]model.FuturesInstrumentProfile
 	reviewTasks               map[string]model.ReviewTask
+	approvalPolicies          map[string]model.ApprovalPolicy
+	approvalRequests          map[string]model.ApprovalRequest
+	approverRoles             map[string][]string
+	reviewSLAPolicies         map[string]model.ReviewSLAPolicy
+	reviewSLAItems            map[string]model.ReviewSLAItem
+	exportJobs                map[string]model.ExportJob
+	exportDownloads           []model.ExportDownload
+	newsEmbargoes             map[string]time.Time
+	newsAttachmentRelinks     map[string]string
+	attachmentDownloaders     map[string][]string
 	communityTopics           map[string]model.CommunityTopicDetail
 	communityComments         map[string]model.CommunityComment
 	communityReports          map[string]model.CommunityReport
 	communityReacts           map[string]struct{}
+	communityWords            map[string]model.CommunitySensitiveWord
+	communityFlags            []model.CommunityModerationFlag
+	communityBans             []model.CommunityUserBan
+	communitySentiment        map[string]model.CommunitySentimentDaily
+	communityFollows          map[string]model.CommunityFollow
+	communityBlocks           map[string]model.CommunityUserBlock
+	communityMentions         []model.CommunityMention
+	communityTopicViews       map[string]map[string]int
+	schedulerPipelines        map[string]model.SchedulerPipeline
+	schedulerPipelineRuns     map[string]model.SchedulerPipelineRun
 	userMessages              map[string][]model.UserMessage
 	adminAuditEvents          map[string]model.AdminAuditEvent
 	workflowMessages          map[string]model.WorkflowMessage
+	membershipOrders          map[string]model.MembershipOrderAdmin
+	paymentCallbackKeys       map[string]struct{}
+	paymentPollLogs           []model.PaymentPollLog
+	membershipCoupons         map[string]model.MembershipCoupon
+	couponRedemptions         []model.MembershipCouponRedemption
+	autoRenewals              map[string]model.MembershipAutoRenewal
+	renewalAttempts           []model.MembershipRenewalAttempt
+	auditLedger               []model.AdminAuditLedgerEntry
+	auditCheckpoints          []model.AdminAuditCheckpoint
+	realtime                  *realtime.Hub
+	messageSeq                int64
 }
 
 func NewInMemoryGrowthRepo() *InMemoryGrowthRepo {
@@ -107,15 +140,39 @@ func NewInMemoryGrowthRepo() *InMemoryGrowthRepo {
 		stockEventClusters:        make(map[string]model.StockEventCluster),
 		futuresInstrumentProfiles: make(map[string]model.FuturesInstrumentProfile),
 		reviewTasks:               make(map[string]model.ReviewTask),
+		approvalPolicies:          make(map[string]model.ApprovalPolicy),
+		reviewSLAPolicies:         make(map[string]model.ReviewSLAPolicy),
+		reviewSLAItems:            make(map[string]model.ReviewSLAItem),
+		exportJobs:                make(map[string]model.ExportJob),
+		exportDownloads:           make([]model.ExportDownload, 0),
+		newsEmbargoes:             make(map[string]time.Time),
+		newsAttachmentRelinks:     make(map[string]string),
+		attachmentDownloaders:     make(map[string][]string),
+		approvalRequests:          make(map[string]model.ApprovalRequest),
 		communityTopics:           make(map[string]model.CommunityTopicDetail),
 		communityComments:         make(map[string]model.CommunityComment),
 		communityReports:          make(map[string]model.CommunityReport),
 		communityReacts:           make(map[string]struct{}),
+		communityWords:            make(map[string]model.CommunitySensitiveWord),
+		communitySentiment:        make(map[string]model.CommunitySentimentDaily),
+		communityFollows:          make(map[string]model.CommunityFollow),
+		communityBlocks:           make(map[string]model.CommunityUserBlock),
+		communityTopicViews:       make(map[string]map[string]int),
+		schedulerPipelines:        make(map[string]model.SchedulerPipeline),
+		schedulerPipelineRuns:     make(map[string]model.SchedulerPipelineRun),
 		userMessages:              make(map[string][]model.UserMessage),
 		adminAuditEvents:          make(map[string]model.AdminAuditEvent),
 		workflowMessages:          make(map[string]model.WorkflowMessage),
+		membershipOrders:          make(map[string]model.MembershipOrderAdmin),
+		paymentCallbackKeys:       make(map[string]struct{}),
+		membershipCoupons:         make(map[string]model.MembershipCoupon),
+		autoRenewals:              make(map[string]model.MembershipAutoRenewal),
+		realtime:                  realtime.NewHub(nil),
 	}
 	repo.seedCommunityData()
+	repo.seedSchedulerPipelines()
+	repo.seedApprovalPolicies()
+	repo.seedReviewSLAPolicies()
 	return repo
 }
 
@@ -313,13 +370,30 @@ func (r *InMemoryGrowthRepo) GetMembershipQuota(userID string) (model.Membership
 }
 
 func (r *InMemoryGrowthRepo) GetAttachmentFileInfo(userID string, attachmentID string) (model.AttachmentFileInfo, error) {
-	return model.AttachmentFileInfo{
+	info := model.AttachmentFileInfo{
 		FileURL:   "https://example.com/file.pdf?sign=demo",
 		ArticleID: "article_demo_001",
-	}, nil
+		FileName:  "file.pdf",
+		MimeType:  "application/pdf",
+	}
+	r.mu.Lock()
+	embargoUntil, embargoed := r.newsEmbargoes[info.ArticleID]
+	r.mu.Unlock()
+	if embargoed && embargoUntil.After(time.Now()) {
+		return model.AttachmentFileInfo{}, sql.ErrNoRows
+	}
+	return info, nil
 }
 
 func (r *InMemoryGrowthRepo) LogAttachmentDownload(userID string, attachmentID string, articleID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.attachmentDownloaders[attachmentID] {
+		if existing == userID {
+			return nil
+		}
+	}
+	r.attachmentDownloaders[attachmentID] = append(r.attachmentDownloaders[attachmentID], userID)
 	return nil
 }
 
@@ -654,26 +728,82 @@ func (r *InMemoryGrowthRepo) ListMembershipProducts(status string, page int, pag
 	return items, len(items), nil
 }
 
-func (r *InMemoryGrowthRepo) CreateMembershipOrder(userID string, productID string, payChannel string) (model.MembershipOrderAdmin, error) {
-	return model.MembershipOrderAdmin{
-		ID:         "mo_demo_002",
-		OrderNo:    "mo_demo_002",
-		UserID:     userID,
-		ProductID:  productID,
-		Amount:     99,
-		PayChannel: payChannel,
-		Status:     "PENDING",
-		CreatedAt:  "2026-02-25T12:00:00+08:00",
-	}, nil
+func (r *InMemoryGrowthRepo) CreateMembershipOrder(userID string, productID string, payChannel string, couponCode string) (model.MembershipOrderAdmin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := newID("mo")
+	order := model.MembershipOrderAdmin{
+		ID:             id,
+		OrderNo:        id,
+		UserID:         userID,
+		ProductID:      productID,
+		Amount:         99,
+		OriginalAmount: 99,
+		PayChannel:     strings.ToUpper(payChannel),
+		OrderSource:    membershipOrderSourceUser,
+		Status:         "PENDING",
+		CreatedAt:      time.Now().Format(time.RFC3339),
+	}
+	if normalizeMembershipCouponCode(couponCode) != "" {
+		coupon, quote, err := r.quoteMembershipCouponLocked(userID, productID, couponCode)
+		if err != nil {
+			return model.MembershipOrderAdmin{}, err
+		}
+		order.Amount = quote.Amount
+		order.DiscountAmount = quote.DiscountAmount
+		order.CouponCode = coupon.Code
+		r.couponRedemptions = append(r.couponRedemptions, model.MembershipCouponRedemption{
+			ID:             newID("cr"),
+			CouponID:       coupon.ID,
+			CouponCode:     coupon.Code,
+			UserID:         userID,
+			OrderID:        id,
+			OrderNo:        id,
+			ProductID:      productID,
+			OriginalAmount: quote.OriginalAmount,
+			DiscountAmount: quote.DiscountAmount,
+			Amount:         quote.Amount,
+			Status:         "RESERVED",
+			CreatedAt:      order.CreatedAt,
+		})
+	}
+	r.membershipOrders[id] = order
+	return order, nil
 }
 
 func (r *InMemoryGrowthRepo) ListMembershipOrders(userID string, status string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error) {
 	items := []model.MembershipOrderAdmin{
 		{ID: "mo_demo_001", OrderNo: "mo_demo_001", UserID: userID, ProductID: "mp_demo_001", Amount: 99, PayChannel: "ALIPAY", Status: "PAID", PaidAt: "2026-02-24T11:00:00+08:00", CreatedAt: "2026-02-24T10:50:00+08:00"},
 	}
+	r.mu.Lock()
+	for _, order := range r.membershipOrders {
+		if order.UserID == userID {
+			items = append(items, order)
+		}
+	}
+	r.mu.Unlock()
+	if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+		filtered := items[:0]
+		for _, item := range items {
+			if item.Status == status {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
 	return items, len(items), nil
 }
 
+func (r *InMemoryGrowthRepo) GetMembershipOrder(orderNo string) (model.MembershipOrderAdmin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.membershipOrders[orderNo]
+	if !ok {
+		return model.MembershipOrderAdmin{}, errors.New("order not found")
+	}
+	return order, nil
+}
+
 func (r *InMemoryGrowthRepo) TrackExperimentEvent(item model.ExperimentEvent) error {
 	return nil
 }
@@ -706,40 +836,108 @@ func (r *InMemoryGrowthRepo) HandlePaymentCallback(channel string, orderNo strin
 	if idempotencyKey == "duplicate" {
 		return errors.New("duplicate callback")
 	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.paymentCallbackKeys[idempotencyKey]; ok {
+		return errors.New("duplicate callback")
+	}
+	r.paymentCallbackKeys[idempotencyKey] = struct{}{}
+	order, ok := r.membershipOrders[orderNo]
+	if !ok || order.Status == "PAID" {
+		return nil
+	}
+	now := time.Now()
+	order.Status = "PAID"
+	order.PayChannel = strings.ToUpper(channel)
+	order.PaidAt = now.Format(time.RFC3339)
+	r.membershipOrders[orderNo] = order
+	r.setCouponRedemptionStatusLocked(order.ID, "REDEEMED", order.PaidAt)
+	r.userMessages[order.UserID] = append(r.userMessages[order.UserID], model.UserMessage{
+		ID:         fmt.Sprintf("msg_vip_%s", order.ID),
+		Title:      "VIP会员开通成功",
+		Content:    fmt.Sprintf("订单%s支付成功，会员等级已更新为VIP1，到期时间：%s。", order.OrderNo, now.AddDate(0, 0, 30).Format("2006-01-02 15:04:05")),
+		Type:       "SYSTEM",
+		ReadStatus: "UNREAD",
+		CreatedAt:  order.PaidAt,
+		Seq:        r.nextMessageSeqLocked(),
+	})
+	r.realtime.Notify(realtime.UserTopic(order.UserID))
 	return nil
 }
 
+// inMemoryArbitrageRecos mirrors what the engine leaves in arbitrage_recos:
+// the latest trade date's pairs plus an earlier pair it has expired.
+var inMemoryArbitrageRecos = []model.ArbitrageRecommendation{
+	{
+		ID:         "arb_000",
+		Type:       "CALENDAR",
+		ContractA:  "RB2401",
+		ContractB:  "RB2405",
+		Spread:     95,
+		Percentile: 0.52,
+		EntryPoint: 120,
+		ExitPoint:  80,
+		StopPoint:  150,
+		Status:     futuresArbitrageStatusExpired,
+		TradeDate:  "2026-02-23",
+	},
+	{
+		ID:         "arb_001",
+		Type:       "CALENDAR",
+		ContractA:  "RB2405",
+		ContractB:  "RB2409",
+		Spread:     110,
+		Percentile: 0.86,
+		EntryPoint: 120,
+		ExitPoint:  80,
+		StopPoint:  150,
+		RiskLevel:  "MEDIUM",
+		Status:     "WATCH",
+		TradeDate:  "2026-02-24",
+	},
+}
+
+// listInMemoryArbitrageRecos applies the same visibility rule as
+// arbitrageRecoListFilter.
+func listInMemoryArbitrageRecos(typeFilter string) []model.ArbitrageRecommendation {
+	latest := ""
+	for _, item := range inMemoryArbitrageRecos {
+		if item.TradeDate > latest {
+			latest = item.TradeDate
+		}
+	}
+	items := make([]model.ArbitrageRecommendation, 0, len(inMemoryArbitrageRecos))
+	for _, item := range inMemoryArbitrageRecos {
+		if item.Status == futuresArbitrageStatusExpired || (item.TradeDate != "" && item.TradeDate != latest) {
+			continue
+		}
+		if typeFilter != "" && item.Type != typeFilter {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func (r *InMemoryGrowthRepo) ListArbitrageOpportunities(typeFilter string, page int, pageSize int) ([]model.ArbitrageOpportunity, int, error) {
-	items := []model.ArbitrageOpportunity{
-		{
-			ID:         "arb_001",
-			Type:       "CALENDAR",
-			ContractA:  "RB2405",
-			ContractB:  "RB2409",
-			Spread:     110,
-			Percentile: 0.86,
-			RiskLevel:  "MEDIUM",
-			Status:     "WATCH",
-		},
+	items := make([]model.ArbitrageOpportunity, 0)
+	for _, item := range listInMemoryArbitrageRecos(typeFilter) {
+		items = append(items, model.ArbitrageOpportunity{
+			ID:         item.ID,
+			Type:       item.Type,
+			ContractA:  item.ContractA,
+			ContractB:  item.ContractB,
+			Spread:     item.Spread,
+			Percentile: item.Percentile,
+			RiskLevel:  item.RiskLevel,
+			Status:     item.Status,
+		})
 	}
 	return items, len(items), nil
 }
 
 func (r *InMemoryGrowthRepo) ListFuturesArbitrage(typeFilter string, page int, pageSize int) ([]model.ArbitrageRecommendation, int, error) {
-	items := []model.ArbitrageRecommendation{
-		{
-			ID:         "arb_001",
-			Type:       "CALENDAR",
-			ContractA:  "RB2405",
-			ContractB:  "RB2409",
-			Spread:     110,
-			Percentile: 0.86,
-			EntryPoint: 120,
-			ExitPoint:  80,
-			StopPoint:  150,
-			Status:     "WATCH",
-		},
-	}
+	items := listInMemoryArbitrageRecos(typeFilter)
 	return items, len(items), nil
 }
 
@@ -759,6 +957,39 @@ func (r *InMemoryGrowthRepo) GetFuturesArbitrageDetail(id string) (model.Arbitra
 	}, nil
 }
 
+func (r *InMemoryGrowthRepo) AdminRunFuturesArbitrageEngine(tradeDate string) (model.FuturesArbitrageEngineResult, error) {
+	if strings.TrimSpace(tradeDate) == "" {
+		tradeDate = time.Now().Format("2006-01-02")
+	}
+	stats := computeFuturesArbitrageSpreadStats([]float64{96, 102, 99, 104, 98, 101, 97, 103, 100, 110})
+	signal := deriveFuturesArbitrageSignal(stats, normalizeFuturesArbitrageRuntimeConfig(futuresArbitrageRuntimeConfig{}))
+	item := model.FuturesArbitrageEngineItem{
+		ID:         "arb_001",
+		PairKey:    "CALENDAR:RB2405/RB2409",
+		Type:       "CALENDAR",
+		ContractA:  "RB2405",
+		ContractB:  "RB2409",
+		Spread:     roundTo(stats.Current, 2),
+		Percentile: roundTo(stats.Percentile, 4),
+		ZScore:     roundTo(stats.ZScore, 4),
+		HalfLife:   roundTo(stats.HalfLife, 2),
+		SampleDays: stats.SampleDays,
+		Direction:  signal.Direction,
+		RiskLevel:  signal.RiskLevel,
+		Status:     signal.Status,
+	}
+	result := model.FuturesArbitrageEngineResult{
+		TradeDate: tradeDate,
+		PairCount: 1,
+		Computed:  1,
+		Items:     []model.FuturesArbitrageEngineItem{item},
+	}
+	if signal.Status == futuresArbitrageStatusOpen {
+		result.OpenSignals = 1
+	}
+	return result, nil
+}
+
 func (r *InMemoryGrowthRepo) CreateFuturesAlert(userID string, contract string, alertType string, threshold float64) (string, error) {
 	return "fa_demo_001", nil
 }
@@ -875,6 +1106,7 @@ func (r *InMemoryGrowthRepo) AdminReviewRiskHit(id string, status string, reason
 func (r *InMemoryGrowthRepo) AdminListWithdrawRequests(page int, pageSize int) ([]model.WithdrawRequestInfo, int, error) {
 	items := []model.WithdrawRequestInfo{
 		{ID: "wd_001", UserID: "u_demo_001", Amount: 50, Status: "PENDING", AppliedAt: "2026-02-24T12:10:00+08:00"},
+		{ID: "wd_002", UserID: "u_demo_002", Amount: 8000, Status: "PENDING", AppliedAt: "2026-02-24T15:30:00+08:00"},
 	}
 	return items, len(items), nil
 }
@@ -909,7 +1141,7 @@ func (r *InMemoryGrowthRepo) AdminCreateNewsArticle(categoryID string, title str
 	return "na_new_001", nil
 }
 
-func (r *InMemoryGrowthRepo) AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string) error {
+func (r *InMemoryGrowthRepo) AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, editorID string) error {
 	return nil
 }
 
@@ -931,6 +1163,14 @@ func (r *InMemoryGrowthRepo) AdminDeleteNewsAttachment(id string) error {
 	return nil
 }
 
+func (r *InMemoryGrowthRepo) AdminGetNewsAttachment(id string) (model.NewsAttachment, error) {
+	return model.NewsAttachment{ID: id, ArticleID: "article_demo_001", FileName: "daily-note.pdf", FileURL: "https://example.com/files/daily-note.pdf", FileSize: 20480, MimeType: "application/pdf", CreatedAt: "2026-02-25T10:00:00+08:00"}, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCountNewsAttachmentsByFileURL(fileURL string) (int, error) {
+	return 0, nil
+}
+
 func (r *InMemoryGrowthRepo) AdminListMarketEvents(eventType string, symbol string, page int, pageSize int) ([]model.MarketEvent, int, error) {
 	items := []model.MarketEvent{
 		{
@@ -1060,6 +1300,11 @@ func (r *InMemoryGrowthRepo) AdminCreateStockEventReview(review model.StockEvent
 	if !ok {
 		return model.StockEventReview{}, sql.ErrNoRows
 	}
+	if review.ReviewStatus != "PENDING" {
+		r.resolveReviewSLALocked(func(item model.ReviewSLAItem) bool {
+			return item.Module == "STOCK_EVENT" && item.TargetID == cluster.ID
+		}, review.Reviewer, time.Now())
+	}
 	now := time.Now().Format(time.RFC3339)
 	review.ReviewedAt = now
 	review.CreatedAt = now
@@ -1543,6 +1788,10 @@ func (r *InMemoryGrowthRepo) AdminRunVIPMembershipLifecycle() (string, error) {
 	return "vip lifecycle skipped (in-memory repo)", nil
 }
 
+func (r *InMemoryGrowthRepo) AdminReleaseInviteCommissions() (string, error) {
+	return "invite commission release skipped (in-memory repo)", nil
+}
+
 func (r *InMemoryGrowthRepo) AdminGetQuantTopStocks(limit int, lookbackDays int) ([]model.StockQuantScore, error) {
 	items := []model.StockQuantScore{
 		{
@@ -1587,6 +1836,21 @@ func (r *InMemoryGrowthRepo) AdminGetQuantTopStocks(limit int, lookbackDays int)
 	return items[:limit], nil
 }
 
+func (r *InMemoryGrowthRepo) AdminGetQuantTopStocksForTradeDate(tradeDate string, limit int, lookbackDays int) ([]model.StockQuantScore, error) {
+	tradeDate = strings.TrimSpace(tradeDate)
+	if _, err := time.Parse("2006-01-02", tradeDate); err != nil {
+		return nil, fmt.Errorf("invalid trade_date: %s", tradeDate)
+	}
+	items, err := r.AdminGetQuantTopStocks(limit, lookbackDays)
+	if err != nil {
+		return nil, err
+	}
+	for index := range items {
+		items[index].TradeDate = tradeDate
+	}
+	return items, nil
+}
+
 func (r *InMemoryGrowthRepo) AdminGetQuantEvaluation(windowDays int, topN int) (model.StockQuantEvaluationSummary, []model.StockQuantEvaluationPoint, []model.StockQuantRiskPerformance, []model.StockQuantRotationPoint, error) {
 	if windowDays <= 0 {
 		windowDays = 60
@@ -1961,7 +2225,7 @@ func (r *InMemoryGrowthRepo) AdminCreateUserMessages(userIDs []string, title str
 	return success, failures, nil
 }
 
-func (r *InMemoryGrowthRepo) AdminUpdateUserStatus(id string, status string) error {
+func (r *InMemoryGrowthRepo) AdminUpdateUserStatus(id string, status string, operator string) error {
 	return nil
 }
 
@@ -1973,7 +2237,7 @@ func (r *InMemoryGrowthRepo) AdminUpdateUserKYCStatus(id string, kycStatus strin
 	return nil
 }
 
-func (r *InMemoryGrowthRepo) AdminResetUserPasswordHash(id string, passwordHash string) error {
+func (r *InMemoryGrowthRepo) AdminResetUserPasswordHash(id string, passwordHash string, operator string) error {
 	return nil
 }
 
@@ -2045,6 +2309,109 @@ func (r *InMemoryGrowthRepo) AdminUpdateMembershipOrderStatus(id string, status
 	return nil
 }
 
+func (r *InMemoryGrowthRepo) AdminRefundMembershipOrder(orderID string, reason string, operator string, channels payment.Resolver) (model.MembershipRefund, error) {
+	channel, err := channels.Channel("ALIPAY")
+	if err != nil {
+		return model.MembershipRefund{}, err
+	}
+	item := model.MembershipRefund{
+		ID:            "mrf_001",
+		OrderID:       orderID,
+		OrderNo:       orderID,
+		UserID:        "u_demo_001",
+		PayChannel:    "ALIPAY",
+		RefundChannel: channel.Name(),
+		OrderAmount:   99,
+		Amount:        99,
+		Status:        "SUCCESS",
+		Reason:        reason,
+		Operator:      operator,
+		CreatedAt:     "2026-02-26T10:00:00+08:00",
+		CompletedAt:   "2026-02-26T10:00:00+08:00",
+	}
+	result, err := channel.Refund(payment.RefundRequest{OrderNo: item.OrderNo, RefundNo: item.ID, OrderAmount: item.OrderAmount, Amount: item.Amount, Reason: reason})
+	if err != nil {
+		return model.MembershipRefund{}, err
+	}
+	item.ChannelRefundNo = result.ChannelRefundNo
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListMembershipRefunds(status string, page int, pageSize int) ([]model.MembershipRefund, int, error) {
+	return []model.MembershipRefund{}, 0, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminPollPendingMembershipOrders(channels payment.Resolver) (string, error) {
+	now := time.Now()
+	r.mu.Lock()
+	pending := make([]model.MembershipOrderAdmin, 0)
+	for _, order := range r.membershipOrders {
+		createdAt, err := time.Parse(time.RFC3339, order.CreatedAt)
+		if order.Status == "PENDING" && err == nil && now.Sub(createdAt) >= 5*time.Minute {
+			pending = append(pending, order)
+		}
+	}
+	r.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, order := range pending {
+		attempt := model.PaymentPollLog{ID: newID("ppl"), OrderID: order.ID, OrderNo: order.OrderNo, PayChannel: order.PayChannel, Result: "PENDING"}
+		if channel, err := channels.Channel(order.PayChannel); err != nil {
+			attempt.Result, attempt.Message = "UNSUPPORTED", err.Error()
+		} else if trade, err := channel.QueryOrder(order.OrderNo); err != nil {
+			attempt.Result, attempt.Message = "ERROR", err.Error()
+		} else {
+			attempt.TradeStatus, attempt.ChannelTxnNo = trade.Status, trade.ChannelTxnNo
+			if trade.Status == payment.TradeStatusFailed {
+				attempt.Result = "FAILED"
+			}
+			if trade.Status == payment.TradeStatusPaid {
+				if err := trade.Matches(order.Amount, payment.CurrencyCNY); err != nil {
+					attempt.Result, attempt.Message = "MISMATCH", err.Error()
+				} else if err := r.HandlePaymentCallback(channel.Name(), order.OrderNo, trade.ChannelTxnNo, trade.IdempotencyKey, "", true); err != nil {
+					attempt.Result, attempt.Message = "ERROR", err.Error()
+				} else {
+					attempt.Result = "PAID"
+				}
+			}
+		}
+		r.mu.Lock()
+		createdAt, _ := time.Parse(time.RFC3339, order.CreatedAt)
+		if current := r.membershipOrders[order.ID]; attempt.TradeStatus != "" && (attempt.Result == "PENDING" || attempt.Result == "FAILED") && current.Status == "PENDING" && now.Sub(createdAt) >= 30*time.Minute {
+			current.Status = "CANCELED"
+			r.membershipOrders[order.ID] = current
+			r.setCouponRedemptionStatusLocked(order.ID, "RELEASED", "")
+			attempt.Result = "CANCELED"
+		}
+		counts[attempt.Result]++
+		attempt.CreatedAt = now.Format(time.RFC3339)
+		r.paymentPollLogs = append(r.paymentPollLogs, attempt)
+		r.mu.Unlock()
+	}
+	return fmt.Sprintf(
+		"polled=%d paid=%d canceled=%d pending=%d errors=%d",
+		len(pending), counts["PAID"], counts["CANCELED"], counts["PENDING"]+counts["FAILED"]+counts["UNSUPPORTED"], counts["ERROR"]+counts["MISMATCH"],
+	), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListPaymentPollLogs(orderNo string, result string, page int, pageSize int) ([]model.PaymentPollLog, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.PaymentPollLog, 0, len(r.paymentPollLogs))
+	for i := len(r.paymentPollLogs) - 1; i >= 0; i-- {
+		item := r.paymentPollLogs[i]
+		if (orderNo == "" || item.OrderNo == orderNo) && (result == "" || strings.EqualFold(item.Result, result)) {
+			items = append(items, item)
+		}
+	}
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.PaymentPollLog{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
 func (r *InMemoryGrowthRepo) AdminGetExperimentAnalyticsSummary(days int) (model.AdminExperimentAnalyticsSummary, error) {
 	overview := model.AdminExperimentAnalyticsOverview{
 		Days:                   days,
@@ -2072,6 +2439,7 @@ func (r *InMemoryGrowthRepo) AdminGetExperimentAnalyticsSummary(days int) (model
 		DeviceBreakdown:     []model.AdminExperimentAnalyticsDeviceItem{},
 		UserStageBreakdown:  []model.AdminExperimentAnalyticsUserStageItem{},
 		VariantDailyTrend:   []model.AdminExperimentAnalyticsVariantTrendPoint{},
+		CouponBreakdown:     []model.AdminExperimentAnalyticsCouponItem{},
 	}, nil
 }
 
@@ -2298,6 +2666,13 @@ func (r *InMemoryGrowthRepo) AdminUpsertSystemConfig(configKey string, configVal
 	return nil
 }
 
+func (r *InMemoryGrowthRepo) AdminRotateConfigSecrets(operator string) (model.SystemConfigSecretRotationResult, error) {
+	return model.SystemConfigSecretRotationResult{
+		Failures:  []string{},
+		RotatedAt: time.Now().Format(time.RFC3339),
+	}, nil
+}
+
 func (r *InMemoryGrowthRepo) AdminListReviewTasks(module string, status string, submitterID string, reviewerID string, page int, pageSize int) ([]model.ReviewTask, int, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -2334,7 +2709,7 @@ func (r *InMemoryGrowthRepo) AdminListReviewTasks(module string, status string,
 	return items, len(items), nil
 }
 
-func (r *InMemoryGrowthRepo) AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string) (string, error) {
+func (r *InMemoryGrowthRepo) AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string, priority string) (string, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -2347,7 +2722,7 @@ func (r *InMemoryGrowthRepo) AdminSubmitReviewTask(module string, targetID strin
 	if err := r.applyModuleTargetStatusLocked(module, targetID, "REVIEWING"); err != nil {
 		return "", err
 	}
-	now := time.Now().Format(time.RFC3339)
+	now := time.Now()
 	item := model.ReviewTask{
 		ID:          newID("rt"),
 		Module:      module,
@@ -2356,9 +2731,10 @@ func (r *InMemoryGrowthRepo) AdminSubmitReviewTask(module string, targetID strin
 		ReviewerID:  strings.TrimSpace(reviewerID),
 		Status:      "PENDING",
 		SubmitNote:  strings.TrimSpace(submitNote),
-		SubmittedAt: now,
+		SubmittedAt: now.Format(time.RFC3339),
 	}
 	r.reviewTasks[item.ID] = item
+	r.openReviewSLALocked(model.ReviewSLASubjectReviewTask, item.ID, module, item.TargetID, priority, item.ReviewerID, now)
 	return item.ID, nil
 }
 
@@ -2372,6 +2748,7 @@ func (r *InMemoryGrowthRepo) AdminAssignReviewTask(reviewID string, reviewerID s
 	}
 	item.ReviewerID = strings.TrimSpace(reviewerID)
 	r.reviewTasks[item.ID] = item
+	r.assignReviewSLALocked(model.ReviewSLASubjectReviewTask, item.ID, item.ReviewerID)
 	return nil
 }
 
@@ -2392,8 +2769,10 @@ func (r *InMemoryGrowthRepo) AdminReviewTaskDecision(reviewID string, status str
 	item.Status = strings.ToUpper(strings.TrimSpace(status))
 	item.ReviewerID = strings.TrimSpace(reviewerID)
 	item.ReviewNote = strings.TrimSpace(reviewNote)
-	item.ReviewedAt = time.Now().Format(time.RFC3339)
+	now := time.Now()
+	item.ReviewedAt = now.Format(time.RFC3339)
 	r.reviewTasks[item.ID] = item
+	r.resolveReviewSLALocked(reviewSLASubjectMatcher(model.ReviewSLASubjectReviewTask, item.ID), item.ReviewerID, now)
 
 	targetStatus := "DRAFT"
 	if item.Status == "APPROVED" {
@@ -2695,6 +3074,12 @@ func (r *InMemoryGrowthRepo) AdminCreateWorkflowMessage(reviewID string, targetI
 		Content:    content,
 		IsRead:     false,
 		CreatedAt:  now,
+		Seq:        r.nextMessageSeqLocked(),
+	}
+	if receiverID == "" {
+		r.realtime.Notify(realtime.AdminBroadcastTopic)
+	} else {
+		r.realtime.Notify(realtime.AdminTopic(receiverID))
 	}
 	return nil
 }
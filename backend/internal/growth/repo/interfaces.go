@@ -1,6 +1,12 @@
 package repo
 
-import "sercherai/backend/internal/growth/model"
+import (
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+	"sercherai/backend/internal/platform/realtime"
+)
 
 type GrowthRepo interface {
 	ListBrowseHistory(userID string, contentType string, page int, pageSize int) ([]model.BrowseHistory, int, error)
@@ -21,6 +27,11 @@ type GrowthRepo interface {
 	CreateSubscription(userID string, subType string, scope string, frequency string) (string, error)
 	UpdateSubscription(userID string, id string, frequency string, status string) error
 	ListMessages(userID string, page int, pageSize int) ([]model.UserMessage, int, error)
+	// Realtime is the hub the repo pings when it writes a message; message
+	// streams subscribe to the same instance.
+	Realtime() *realtime.Hub
+	ListUserMessagesAfter(userID string, afterSeq int64, limit int) ([]model.UserMessage, error)
+	LatestUserMessageSeq(userID string) (int64, error)
 	MarkMessageRead(userID string, id string) error
 	GetUserAccessProfile(userID string) (model.UserAccessProfile, error)
 	GetMembershipQuota(userID string) (model.MembershipQuota, error)
@@ -40,6 +51,16 @@ type GrowthRepo interface {
 	DeleteCommunityReaction(input model.CommunityReactionInput) error
 	CreateCommunityReport(input model.CommunityReportCreateInput) (model.CommunityReport, error)
 	CreateCommunityNotification(input model.CommunityNotificationInput) error
+	ListCommunityFollows(userID string, targetType string, page int, pageSize int) ([]model.CommunityFollow, int, error)
+	CreateCommunityFollow(userID string, targetType string, targetID string) (model.CommunityFollow, error)
+	DeleteCommunityFollow(userID string, id string) error
+	ListCommunityBlocks(userID string, page int, pageSize int) ([]model.CommunityUserBlock, int, error)
+	CreateCommunityBlock(userID string, blockedUserID string, blockType string) (model.CommunityUserBlock, error)
+	DeleteCommunityBlock(userID string, id string) error
+	ListCommunityMentions(userID string, page int, pageSize int) ([]model.CommunityMention, int, error)
+	ListCommunityFeed(userID string, page int, pageSize int) ([]model.CommunityFeedItem, int, error)
+	RecordCommunityMentions(input model.CommunityMentionInput) ([]string, error)
+	RecordCommunityTopicView(userID string, topicID string) error
 	ListStockRecommendations(userID string, tradeDate string, page int, pageSize int) ([]model.StockRecommendation, int, error)
 	GetStockRecommendationDetail(userID string, recoID string) (model.StockRecommendationDetail, error)
 	GetStockRecommendationPerformance(userID string, recoID string) ([]model.RecommendationPerformancePoint, error)
@@ -62,8 +83,14 @@ type GrowthRepo interface {
 	ExecuteQueuedStrategyForecastL3Runs(limit int, operatorUserID string) (int, error)
 	RunStrategyForecastL3QualityBackfill(limit int, operatorUserID string) (int, error)
 	ListMembershipProducts(status string, page int, pageSize int) ([]model.MembershipProduct, int, error)
-	CreateMembershipOrder(userID string, productID string, payChannel string) (model.MembershipOrderAdmin, error)
+	CreateMembershipOrder(userID string, productID string, payChannel string, couponCode string) (model.MembershipOrderAdmin, error)
+	QuoteMembershipCoupon(userID string, productID string, code string) (model.MembershipCouponQuote, error)
+	ListUserMembershipCoupons(userID string) ([]model.MembershipCoupon, error)
+	GetMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error)
+	EnableMembershipAutoRenewal(userID string, productID string, payChannel string, agreementNo string) (model.MembershipAutoRenewal, error)
+	CancelMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error)
 	ListMembershipOrders(userID string, status string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error)
+	GetMembershipOrder(orderNo string) (model.MembershipOrderAdmin, error)
 	TrackExperimentEvent(item model.ExperimentEvent) error
 	BindMembershipOrderExperiment(orderNo string, item model.ExperimentOrderAttribution) error
 	GetRewardWallet(userID string) (model.RewardWallet, error)
@@ -73,6 +100,7 @@ type GrowthRepo interface {
 	ListArbitrageOpportunities(typeFilter string, page int, pageSize int) ([]model.ArbitrageOpportunity, int, error)
 	ListFuturesArbitrage(typeFilter string, page int, pageSize int) ([]model.ArbitrageRecommendation, int, error)
 	GetFuturesArbitrageDetail(id string) (model.ArbitrageRecommendation, error)
+	AdminRunFuturesArbitrageEngine(tradeDate string) (model.FuturesArbitrageEngineResult, error)
 	CreateFuturesAlert(userID string, contract string, alertType string, threshold float64) (string, error)
 	ListFuturesReviews(page int, pageSize int) ([]model.FuturesReview, int, error)
 	ListMarketEvents(eventType string, page int, pageSize int) ([]model.MarketEvent, int, error)
@@ -92,6 +120,7 @@ type GrowthRepo interface {
 	AdminListRiskHits(status string, page int, pageSize int) ([]model.RiskHit, int, error)
 	AdminReviewRiskHit(id string, status string, reason string) error
 	AdminListWithdrawRequests(page int, pageSize int) ([]model.WithdrawRequestInfo, int, error)
+	AdminGetWithdrawRequest(id string) (model.WithdrawRequestInfo, error)
 	AdminReviewWithdrawRequest(id string, status string, reason string) error
 	AdminListNewsCategories(status string, page int, pageSize int) ([]model.NewsCategory, int, error)
 	AdminCreateNewsCategory(name string, slug string, sort int, visibility string, status string) (string, error)
@@ -103,13 +132,41 @@ type GrowthRepo interface {
 	AdminListCommunityComments(query model.CommunityAdminCommentQuery) ([]model.CommunityComment, int, error)
 	AdminUpdateCommunityCommentStatus(id string, status string) error
 	AdminListCommunityReports(query model.CommunityAdminReportQuery) ([]model.CommunityReport, int, error)
-	AdminReviewCommunityReport(id string, status string, reviewNote string) error
+	AdminReviewCommunityReport(id string, status string, reviewerID string, reviewNote string) error
+	AdminListCommunitySensitiveWords(status string, category string, keyword string, page int, pageSize int) ([]model.CommunitySensitiveWord, int, error)
+	AdminCreateCommunitySensitiveWord(word string, category string, operator string) (string, error)
+	AdminUpdateCommunitySensitiveWordStatus(id string, status string) error
+	AdminListCommunityModerationFlags(query model.CommunityModerationFlagQuery) ([]model.CommunityModerationFlag, int, error)
+	AdminListCommunityUserBans(status string, userID string, page int, pageSize int) ([]model.CommunityUserBan, int, error)
+	AdminCreateCommunityUserBan(userID string, days int, reason string, operator string) (string, error)
+	AdminLiftCommunityUserBan(id string, operator string) error
+	AdminListCommunitySentiment(query model.CommunitySentimentQuery) ([]model.CommunitySentimentDaily, int, error)
+	AdminRebuildCommunitySentiment(statDate string) (int, error)
+	AdminRunCommunitySentimentIndex() (string, error)
 	AdminCreateNewsArticle(categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, authorID string) (string, error)
-	AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string) error
+	AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, editorID string) error
 	AdminPublishNewsArticle(id string, status string) error
+	AdminScheduleNewsArticle(id string, publishAt time.Time, embargoUntil time.Time) error
+	AdminCancelNewsArticleSchedule(id string) error
+	AdminRunNewsScheduledPublish() (string, error)
+	AdminListNewsArticleRevisions(articleID string, page int, pageSize int) ([]model.NewsArticleRevision, int, error)
+	AdminGetNewsArticleRevision(articleID string, revisionNo int) (model.NewsArticleRevision, error)
+	AdminDiffNewsArticleRevisions(articleID string, fromRevision int, toRevision int) (model.NewsArticleRevisionDiff, error)
+	AdminRestoreNewsArticleRevision(articleID string, revisionNo int, editorID string) (model.NewsArticleRevision, error)
+	AdminMergeNewsArticleDuplicate(id string, canonicalID string) error
+	AdminUnmergeNewsArticleDuplicate(id string) error
 	AdminCreateNewsAttachment(articleID string, fileName string, fileURL string, fileSize int64, mimeType string) (string, error)
 	AdminListNewsAttachments(articleID string) ([]model.NewsAttachment, error)
 	AdminDeleteNewsAttachment(id string) error
+	AdminGetNewsAttachment(id string) (model.NewsAttachment, error)
+	AdminCountNewsAttachmentsByFileURL(fileURL string) (int, error)
+	AdminListAttachmentDownloads(attachmentID string, userID string) ([]model.AttachmentDownloadRecord, error)
+	AdminListAttachmentDownloaderIDs(attachmentID string) ([]string, error)
+	UpsertNewsAttachmentText(item model.NewsAttachmentText) error
+	AdminListNewsAttachmentsMissingText(limit int) ([]model.NewsAttachment, error)
+	AdminListLegacyNewsAttachments(urlPrefixes []string, limit int) ([]model.NewsAttachment, error)
+	AdminRelinkNewsAttachmentFile(oldURL string, newURL string) (int64, error)
+	SearchNewsAttachmentTexts(userID string, keyword string, limit int) ([]model.NewsAttachmentSearchHit, int, error)
 	AdminListStockRecommendations(status string, page int, pageSize int) ([]model.StockRecommendation, int, error)
 	AdminCreateStockRecommendation(item model.StockRecommendation) (string, error)
 	AdminUpdateStockRecommendationStatus(id string, status string) error
@@ -134,7 +191,9 @@ type GrowthRepo interface {
 	AdminSyncTushareNewsIncremental(batchSize int) (string, error)
 	AdminSyncTushareNewsIncrementalWithOptions(opts model.TushareNewsSyncOptions) (string, []model.NewsSyncRunDetail, error)
 	AdminRunVIPMembershipLifecycle() (string, error)
+	AdminReleaseInviteCommissions() (string, error)
 	AdminGetQuantTopStocks(limit int, lookbackDays int) ([]model.StockQuantScore, error)
+	AdminGetQuantTopStocksForTradeDate(tradeDate string, limit int, lookbackDays int) ([]model.StockQuantScore, error)
 	AdminGetQuantEvaluation(windowDays int, topN int) (model.StockQuantEvaluationSummary, []model.StockQuantEvaluationPoint, []model.StockQuantRiskPerformance, []model.StockQuantRotationPoint, error)
 	AdminGenerateDailyStockRecommendations(tradeDate string) (model.AdminDailyStockRecommendationGenerationResult, error)
 	AdminGetStockSelectionOverview() (model.AdminStockSelectionOverview, error)
@@ -228,21 +287,36 @@ type GrowthRepo interface {
 	AdminListBrowseUserSegments(limit int) ([]model.AdminBrowseUserSegment, error)
 	AdminListUserMessages(userID string, messageType string, readStatus string, page int, pageSize int) ([]model.AdminUserMessage, int, error)
 	AdminCreateUserMessages(userIDs []string, title string, content string, messageType string) (int, []model.AdminMessageSendFailure, error)
-	AdminUpdateUserStatus(id string, status string) error
+	AdminUpdateUserStatus(id string, status string, operator string) error
 	AdminUpdateUserMemberLevel(id string, memberLevel string) error
-	AdminResetUserPasswordHash(id string, passwordHash string) error
+	AdminResetUserPasswordHash(id string, passwordHash string, operator string) error
 	AdminDashboardOverview() (model.AdminDashboardOverview, error)
 	AdminCreateOperationLog(module string, action string, targetType string, targetID string, operatorUserID string, beforeValue string, afterValue string, reason string) error
 	AdminListOperationLogs(module string, action string, operatorUserID string, page int, pageSize int) ([]model.AdminOperationLog, int, error)
 	AdminCreateAuditEvent(item model.AdminAuditEvent) error
 	AdminListAuditEvents(filter model.AdminAuditEventFilter, page int, pageSize int) ([]model.AdminAuditEvent, int, error)
 	AdminGetAuditEventSummary() (model.AdminAuditEventSummary, error)
+	AdminVerifyAuditLedger() (model.AdminAuditLedgerVerification, error)
+	AdminCreateAuditCheckpoint(operator string) (model.AdminAuditCheckpoint, error)
+	AdminListAuditCheckpoints(page int, pageSize int) ([]model.AdminAuditCheckpoint, int, error)
+	AdminExportAuditLedger(startDate string, endDate string, operator string) (model.AdminAuditLedgerExport, error)
 	AdminListMembershipProducts(status string, page int, pageSize int) ([]model.MembershipProduct, int, error)
 	AdminCreateMembershipProduct(name string, price float64, status string, memberLevel string, durationDays int) (string, error)
 	AdminUpdateMembershipProduct(id string, name string, price float64, status string, memberLevel string, durationDays int) error
 	AdminUpdateMembershipProductStatus(id string, status string) error
 	AdminListMembershipOrders(status string, userID string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error)
 	AdminUpdateMembershipOrderStatus(id string, status string) error
+	AdminRefundMembershipOrder(orderID string, reason string, operator string, channels payment.Resolver) (model.MembershipRefund, error)
+	AdminListMembershipRefunds(status string, page int, pageSize int) ([]model.MembershipRefund, int, error)
+	AdminPollPendingMembershipOrders(channels payment.Resolver) (string, error)
+	AdminListPaymentPollLogs(orderNo string, result string, page int, pageSize int) ([]model.PaymentPollLog, int, error)
+	AdminListMembershipCoupons(status string, source string, page int, pageSize int) ([]model.MembershipCoupon, int, error)
+	AdminCreateMembershipCoupon(item model.MembershipCoupon) (string, error)
+	AdminUpdateMembershipCouponStatus(id string, status string) error
+	AdminListMembershipCouponRedemptions(couponID string, status string, page int, pageSize int) ([]model.MembershipCouponRedemption, int, error)
+	AdminRunMembershipAutoRenewals(channels payment.Resolver) (string, error)
+	AdminListMembershipAutoRenewals(status string, userID string, page int, pageSize int) ([]model.MembershipAutoRenewal, int, error)
+	AdminListMembershipRenewalAttempts(renewalID string, result string, page int, pageSize int) ([]model.MembershipRenewalAttempt, int, error)
 	AdminGetExperimentAnalyticsSummary(days int) (model.AdminExperimentAnalyticsSummary, error)
 	AdminListVIPQuotaConfigs(memberLevel string, status string, page int, pageSize int) ([]model.VIPQuotaConfig, int, error)
 	AdminCreateVIPQuotaConfig(item model.VIPQuotaConfig) (string, error)
@@ -277,10 +351,20 @@ type GrowthRepo interface {
 	AdminGetMarketCoverageSummary() (model.MarketCoverageSummary, error)
 	AdminListSystemConfigs(keyword string, page int, pageSize int) ([]model.SystemConfig, int, error)
 	AdminUpsertSystemConfig(configKey string, configValue string, description string, operator string) error
+	AdminRotateConfigSecrets(operator string) (model.SystemConfigSecretRotationResult, error)
 	AdminListReviewTasks(module string, status string, submitterID string, reviewerID string, page int, pageSize int) ([]model.ReviewTask, int, error)
-	AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string) (string, error)
+	AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string, priority string) (string, error)
 	AdminAssignReviewTask(reviewID string, reviewerID string) error
 	AdminReviewTaskDecision(reviewID string, status string, reviewerID string, reviewNote string) error
+	AdminListApprovalPolicies() ([]model.ApprovalPolicy, error)
+	AdminGetApprovalPolicy(module string) (model.ApprovalPolicy, error)
+	AdminSaveApprovalPolicy(item model.ApprovalPolicy, operator string) (model.ApprovalPolicy, error)
+	AdminCreateApprovalRequest(item model.ApprovalRequest) (model.ApprovalRequest, error)
+	AdminListApprovalRequests(module string, status string, submitterID string, page int, pageSize int) ([]model.ApprovalRequest, int, error)
+	AdminGetApprovalRequest(id string) (model.ApprovalRequest, error)
+	AdminDecideApprovalRequest(id string, approverID string, decision string, note string) (model.ApprovalRequest, error)
+	AdminClaimApprovalExecution(id string) (model.ApprovalRequest, error)
+	AdminFinishApprovalExecution(id string, executeError string) error
 	GetSchedulerJobNameByRunID(runID string) (string, error)
 	AdminListSchedulerJobRuns(jobName string, status string, page int, pageSize int) ([]model.SchedulerJobRun, int, error)
 	AdminListNewsSyncRunDetails(runID string, syncType string, source string, symbol string, status string, page int, pageSize int) ([]model.NewsSyncRunDetail, int, error)
@@ -292,11 +376,38 @@ type GrowthRepo interface {
 	AdminUpdateSchedulerJobDefinition(id string, item model.SchedulerJobDefinition, operatorID string) error
 	AdminUpdateSchedulerJobDefinitionStatus(id string, status string, operatorID string) error
 	AdminDeleteSchedulerJobDefinition(id string) error
+	AdminListSchedulerPipelines(status string) ([]model.SchedulerPipeline, error)
+	AdminGetSchedulerPipeline(pipelineKey string) (model.SchedulerPipeline, error)
+	AdminSaveSchedulerPipeline(item model.SchedulerPipeline, operator string) (model.SchedulerPipeline, error)
+	AdminListSchedulerPipelineRuns(pipelineKey string, tradeDate string, status string, page int, pageSize int) ([]model.SchedulerPipelineRun, int, error)
+	AdminGetSchedulerPipelineRun(runID string) (model.SchedulerPipelineRun, error)
+	AdminFindSchedulerPipelineRun(pipelineKey string, tradeDate string) (model.SchedulerPipelineRun, error)
+	AdminClaimSchedulerPipelineRun(pipeline model.SchedulerPipeline, tradeDate string, triggerSource string, operator string, resetJobNames []string) (model.SchedulerPipelineRun, error)
+	AdminUpdateSchedulerPipelineNodeRun(item model.SchedulerPipelineNodeRun) error
+	AdminFinishSchedulerPipelineRun(runID string, status string) error
 	AdminListWorkflowMessages(module string, eventType string, isRead string, receiverID string, page int, pageSize int) ([]model.WorkflowMessage, int, error)
 	AdminCountUnreadWorkflowMessages(module string, eventType string, receiverID string) (int, error)
+	AdminListWorkflowMessagesAfter(receiverID string, afterSeq int64, limit int) ([]model.WorkflowMessage, error)
+	AdminLatestWorkflowMessageSeq(receiverID string) (int64, error)
 	AdminUpdateWorkflowMessageRead(id string, isRead bool) error
 	AdminBulkReadWorkflowMessages(module string, eventType string, receiverID string) (int64, error)
 	AdminCreateWorkflowMessage(reviewID string, targetID string, module string, receiverID string, senderID string, eventType string, title string, content string) error
 	AdminGetWorkflowMetrics(module string, receiverID string) (model.WorkflowMetrics, error)
+	AdminListReviewSLAPolicies() ([]model.ReviewSLAPolicy, error)
+	AdminSaveReviewSLAPolicy(item model.ReviewSLAPolicy, operator string) (model.ReviewSLAPolicy, error)
+	AdminListReviewSLAItems(module string, status string, assigneeID string, page int, pageSize int) ([]model.ReviewSLAItem, int, error)
+	AdminGetReviewSLAMetrics(module string, reviewerID string, days int) (model.ReviewSLAMetrics, error)
+	AdminRunReviewSLAEscalation() (string, error)
+	AdminCreateExportJob(item model.ExportJob) (model.ExportJob, error)
+	AdminGetExportJob(id string) (model.ExportJob, error)
+	AdminListExportJobs(requestedBy string, exportType string, status string, page int, pageSize int) ([]model.ExportJob, int, error)
+	AdminClaimExportJobs(limit int, staleBefore time.Time) ([]model.ExportJob, error)
+	AdminFinishExportJob(item model.ExportJob) error
+	AdminListExpiredExportJobs(before time.Time, limit int) ([]model.ExportJob, error)
+	AdminExpireExportJob(id string) error
+	AdminRecordExportDownload(item model.ExportDownload) error
+	AdminListExportDownloads(jobID string) ([]model.ExportDownload, error)
+	AdminRecordDeliveredExport(item model.ExportJob, download model.ExportDownload) (model.ExportJob, error)
+	AdminListLoginLogs(action string, status string, dateFrom string, dateTo string, page int, pageSize int) ([]model.LoginLog, int, error)
 	AdminGetSchedulerJobMetrics(jobName string) (model.SchedulerJobMetrics, error)
 }
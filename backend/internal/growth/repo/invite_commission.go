@@ -0,0 +1,564 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	inviteCommissionRulesConfigKey = "invite.commission.rules"
+
+	inviteCommissionRewardType     = "CASH"
+	inviteTriggerFirstPaid         = "INVITEE_FIRST_PAID"
+	inviteTriggerRenewalPaid       = "INVITEE_RENEWAL_PAID"
+	inviteCommissionModePercent    = "PERCENT"
+	inviteCommissionModeFixed      = "FIXED"
+	inviteCommissionMaxLevels      = 5
+	inviteCommissionReleaseBatch   = 500
+	inviteCommissionMaxHoldingDays = 365
+)
+
+// inviteCommissionRules is stored as JSON under invite.commission.rules. Level 1
+// is the invitee's direct inviter, level 2 that inviter's own inviter, and so
+// on. Cap bounds a single commission; 0 means uncapped.
+type inviteCommissionRules struct {
+	HoldingDays   int                     `json:"holding_days"`
+	RecurringDays int                     `json:"recurring_days"`
+	Levels        []inviteCommissionLevel `json:"levels"`
+}
+
+type inviteCommissionLevel struct {
+	Level     int                  `json:"level"`
+	FirstPay  inviteCommissionRule `json:"first_pay"`
+	Recurring inviteCommissionRule `json:"recurring"`
+	Cap       float64              `json:"cap"`
+}
+
+type inviteCommissionRule struct {
+	Mode  string  `json:"mode"`
+	Value float64 `json:"value"`
+}
+
+func defaultInviteCommissionRules() inviteCommissionRules {
+	return inviteCommissionRules{
+		HoldingDays:   7,
+		RecurringDays: 365,
+		Levels: []inviteCommissionLevel{
+			{
+				Level:     1,
+				FirstPay:  inviteCommissionRule{Mode: inviteCommissionModePercent, Value: 10},
+				Recurring: inviteCommissionRule{Mode: inviteCommissionModePercent, Value: 5},
+				Cap:       200,
+			},
+			{
+				Level:    2,
+				FirstPay: inviteCommissionRule{Mode: inviteCommissionModePercent, Value: 3},
+				Cap:      50,
+			},
+		},
+	}
+}
+
+func parseInviteCommissionRules(raw string) (inviteCommissionRules, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultInviteCommissionRules(), nil
+	}
+	var rules inviteCommissionRules
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return inviteCommissionRules{}, fmt.Errorf("invalid %s: %w", inviteCommissionRulesConfigKey, err)
+	}
+	if rules.HoldingDays < 0 {
+		rules.HoldingDays = 0
+	}
+	if rules.HoldingDays > inviteCommissionMaxHoldingDays {
+		rules.HoldingDays = inviteCommissionMaxHoldingDays
+	}
+	if rules.RecurringDays < 0 {
+		rules.RecurringDays = 0
+	}
+	levels := make([]inviteCommissionLevel, 0, len(rules.Levels))
+	for _, level := range rules.Levels {
+		if level.Level < 1 || level.Level > inviteCommissionMaxLevels {
+			continue
+		}
+		levels = append(levels, level)
+	}
+	rules.Levels = levels
+	return rules, nil
+}
+
+// levelRule returns the rule of a referral level, or false when the level pays
+// nothing.
+func (rules inviteCommissionRules) levelRule(level int) (inviteCommissionLevel, bool) {
+	for _, item := range rules.Levels {
+		if item.Level == level {
+			return item, true
+		}
+	}
+	return inviteCommissionLevel{}, false
+}
+
+func (rules inviteCommissionRules) maxLevel() int {
+	maxLevel := 0
+	for _, item := range rules.Levels {
+		if item.Level > maxLevel {
+			maxLevel = item.Level
+		}
+	}
+	return maxLevel
+}
+
+// commission computes the payout of one level for an order amount. The result
+// never exceeds the level cap nor the order amount itself.
+func (level inviteCommissionLevel) commission(trigger string, orderAmount float64) float64 {
+	rule := level.FirstPay
+	if trigger == inviteTriggerRenewalPaid {
+		rule = level.Recurring
+	}
+	if orderAmount <= 0 || rule.Value <= 0 {
+		return 0
+	}
+	var amount float64
+	switch strings.ToUpper(strings.TrimSpace(rule.Mode)) {
+	case inviteCommissionModePercent:
+		amount = orderAmount * rule.Value / 100
+	case inviteCommissionModeFixed:
+		amount = rule.Value
+	default:
+		return 0
+	}
+	if level.Cap > 0 && amount > level.Cap {
+		amount = level.Cap
+	}
+	if amount > orderAmount {
+		amount = orderAmount
+	}
+	return math.Round(amount*100) / 100
+}
+
+func loadInviteCommissionRulesTx(tx *sql.Tx) (inviteCommissionRules, error) {
+	var raw sql.NullString
+	err := tx.QueryRow("SELECT config_value FROM system_configs WHERE config_key = ? LIMIT 1", inviteCommissionRulesConfigKey).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultInviteCommissionRules(), nil
+	}
+	if err != nil {
+		return inviteCommissionRules{}, err
+	}
+	return parseInviteCommissionRules(raw.String)
+}
+
+// attributeInvitePaymentTx runs inside the transaction that marks a membership
+// order paid. The invitee's first paid order stamps first_pay_at on the invite
+// record; that order and any renewal within recurring_days then pay
+// commissions up the referral chain. Commissions are held in cash_frozen until
+// available_at, when AdminReleaseInviteCommissions moves them to cash_balance.
+func attributeInvitePaymentTx(tx *sql.Tx, orderID string, userID string, orderAmount float64, now time.Time) error {
+	var inviteRecordID, inviterUserID, riskFlag string
+	var firstPayAt sql.NullTime
+	err := tx.QueryRow(`
+SELECT id, inviter_user_id, first_pay_at, risk_flag
+FROM invite_records
+WHERE invitee_user_id = ?
+LIMIT 1
+FOR UPDATE`, userID).Scan(&inviteRecordID, &inviterUserID, &firstPayAt, &riskFlag)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rules, err := loadInviteCommissionRulesTx(tx)
+	if err != nil {
+		return err
+	}
+	trigger := inviteTriggerFirstPaid
+	if firstPayAt.Valid {
+		trigger = inviteTriggerRenewalPaid
+		if rules.RecurringDays <= 0 || now.After(firstPayAt.Time.AddDate(0, 0, rules.RecurringDays)) {
+			return nil
+		}
+	} else {
+		if _, err := tx.Exec(`
+UPDATE invite_records
+SET first_pay_at = ?, first_pay_order_id = ?, status = 'FIRST_PAID'
+WHERE id = ?`, now, orderID, inviteRecordID); err != nil {
+			return err
+		}
+	}
+	// Flagged invites still count in the funnel but never pay out.
+	if !strings.EqualFold(strings.TrimSpace(riskFlag), "NORMAL") {
+		return nil
+	}
+
+	visited := map[string]bool{userID: true}
+	beneficiary := strings.TrimSpace(inviterUserID)
+	for level := 1; level <= rules.maxLevel() && beneficiary != "" && !visited[beneficiary]; level++ {
+		visited[beneficiary] = true
+		if levelRule, ok := rules.levelRule(level); ok {
+			amount := levelRule.commission(trigger, orderAmount)
+			if amount > 0 {
+				if err := issueInviteCommissionTx(tx, beneficiary, userID, inviteRecordID, orderID, level, trigger, amount, rules.HoldingDays, now); err != nil {
+					return err
+				}
+			}
+		}
+		if level == rules.maxLevel() {
+			break
+		}
+		var upstream sql.NullString
+		err := tx.QueryRow("SELECT inviter_user_id FROM invite_records WHERE invitee_user_id = ? LIMIT 1", beneficiary).Scan(&upstream)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		beneficiary = strings.TrimSpace(upstream.String)
+	}
+	return nil
+}
+
+func issueInviteCommissionTx(tx *sql.Tx, beneficiaryUserID string, inviteeUserID string, inviteRecordID string, orderID string, level int, trigger string, amount float64, holdingDays int, now time.Time) error {
+	var exists int
+	if err := tx.QueryRow(
+		"SELECT COUNT(*) FROM share_reward_records WHERE order_id = ? AND referral_level = ?",
+		orderID,
+		level,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	walletID, err := ensureRewardWalletTx(tx, beneficiaryUserID, now)
+	if err != nil {
+		return err
+	}
+	status := "PENDING"
+	txnType := "COMMISSION_HOLD"
+	balanceColumn := "cash_frozen"
+	availableAt := now.AddDate(0, 0, holdingDays)
+	var issuedAt interface{}
+	if holdingDays <= 0 {
+		status = "ISSUED"
+		txnType = "COMMISSION_IN"
+		balanceColumn = "cash_balance"
+		availableAt = now
+		issuedAt = now
+	}
+
+	recordID := newID("rrd")
+	if _, err := tx.Exec(`
+INSERT INTO share_reward_records (id, inviter_user_id, invitee_user_id, invite_record_id, reward_type, reward_value, trigger_event, status, issued_at, review_reason, created_at, order_id, referral_level, available_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		recordID,
+		beneficiaryUserID,
+		inviteeUserID,
+		inviteRecordID,
+		inviteCommissionRewardType,
+		amount,
+		trigger,
+		status,
+		issuedAt,
+		fmt.Sprintf("%d级邀请佣金 %.2f 元", level, amount),
+		now,
+		orderID,
+		level,
+		availableAt,
+	); err != nil {
+		return err
+	}
+	if err := moveRewardWalletCashTx(tx, walletID, "", balanceColumn, amount, recordID, now); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+INSERT INTO reward_wallet_txns (id, wallet_id, txn_type, amount, status, ref_id, created_at)
+VALUES (?, ?, ?, ?, 'SUCCESS', ?, ?)`,
+		newID("rwt"),
+		walletID,
+		txnType,
+		amount,
+		recordID,
+		now,
+	)
+	return err
+}
+
+func ensureRewardWalletTx(tx *sql.Tx, userID string, now time.Time) (string, error) {
+	if _, err := tx.Exec(`
+INSERT INTO reward_wallets (id, user_id, cash_balance, cash_frozen, coupon_balance, vip_days_balance, updated_at)
+VALUES (?, ?, 0, 0, 0, 0, ?)
+ON DUPLICATE KEY UPDATE updated_at = VALUES(updated_at)`,
+		newID("rwd"),
+		userID,
+		now,
+	); err != nil {
+		return "", err
+	}
+	var walletID string
+	if err := tx.QueryRow("SELECT id FROM reward_wallets WHERE user_id = ? LIMIT 1", userID).Scan(&walletID); err != nil {
+		return "", err
+	}
+	return walletID, nil
+}
+
+// inviteCommissionBalanceColumn reports which wallet column holds the money of
+// a commission in the given status; "" means the money has left the wallet.
+func inviteCommissionBalanceColumn(status string) string {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "PENDING", "FROZEN":
+		return "cash_frozen"
+	case "ISSUED":
+		return "cash_balance"
+	default:
+		return ""
+	}
+}
+
+// moveRewardWalletCashTx shifts amount between two balance columns; "" stands
+// for money outside the wallet. cash_balance never goes below zero: when a
+// clawback takes more than it holds, the rest is booked to cash_debt, and
+// later credits to cash_balance repay that debt first. Every debt change is
+// logged as a COMMISSION_DEBT txn, positive when debt grows.
+func moveRewardWalletCashTx(tx *sql.Tx, walletID string, fromColumn string, toColumn string, amount float64, refID string, now time.Time) error {
+	debit, credit, debtDelta := amount, amount, 0.0
+	if fromColumn == "cash_balance" || toColumn == "cash_balance" {
+		var cashBalance, cashDebt float64
+		if err := tx.QueryRow("SELECT cash_balance, cash_debt FROM reward_wallets WHERE id = ? FOR UPDATE", walletID).Scan(&cashBalance, &cashDebt); err != nil {
+			return err
+		}
+		if fromColumn == "cash_balance" && amount > cashBalance {
+			debit = math.Max(cashBalance, 0)
+			debtDelta = roundTo(amount-debit, 2)
+		}
+		if toColumn == "cash_balance" && cashDebt > 0 {
+			repaid := math.Min(amount, cashDebt)
+			credit = roundTo(amount-repaid, 2)
+			debtDelta = -repaid
+		}
+	}
+
+	assignments := make([]string, 0, 3)
+	args := make([]interface{}, 0, 5)
+	if fromColumn != "" {
+		assignments = append(assignments, fromColumn+" = "+fromColumn+" - ?")
+		args = append(args, debit)
+	}
+	if toColumn != "" {
+		assignments = append(assignments, toColumn+" = "+toColumn+" + ?")
+		args = append(args, credit)
+	}
+	if debtDelta != 0 {
+		assignments = append(assignments, "cash_debt = cash_debt + ?")
+		args = append(args, debtDelta)
+	}
+	args = append(args, now, walletID)
+	if _, err := tx.Exec("UPDATE reward_wallets SET "+strings.Join(assignments, ", ")+", updated_at = ? WHERE id = ?", args...); err != nil {
+		return err
+	}
+	if debtDelta == 0 {
+		return nil
+	}
+	_, err := tx.Exec(`
+INSERT INTO reward_wallet_txns (id, wallet_id, txn_type, amount, status, ref_id, created_at)
+VALUES (?, ?, 'COMMISSION_DEBT', ?, 'SUCCESS', ?, ?)`,
+		newID("rwt"),
+		walletID,
+		debtDelta,
+		refID,
+		now,
+	)
+	return err
+}
+
+// transitionInviteCommissionTx moves a commission to a new status and shifts
+// its amount between the frozen and withdrawable balances to match. The
+// record must already be locked by the caller.
+func transitionInviteCommissionTx(tx *sql.Tx, recordID string, beneficiaryUserID string, amount float64, fromStatus string, toStatus string, txnType string, reason string, now time.Time) error {
+	fromColumn := inviteCommissionBalanceColumn(fromStatus)
+	toColumn := inviteCommissionBalanceColumn(toStatus)
+	if fromColumn != toColumn {
+		walletID, err := ensureRewardWalletTx(tx, beneficiaryUserID, now)
+		if err != nil {
+			return err
+		}
+		if err := moveRewardWalletCashTx(tx, walletID, fromColumn, toColumn, amount, recordID, now); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+INSERT INTO reward_wallet_txns (id, wallet_id, txn_type, amount, status, ref_id, created_at)
+VALUES (?, ?, ?, ?, 'SUCCESS', ?, ?)`,
+			newID("rwt"),
+			walletID,
+			txnType,
+			amount,
+			recordID,
+			now,
+		); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE share_reward_records SET status = ?"
+	args := []interface{}{toStatus}
+	if toStatus == "ISSUED" {
+		query += ", issued_at = ?"
+		args = append(args, now)
+	}
+	if toStatus == "REVERSED" {
+		query += ", reversed_at = ?"
+		args = append(args, now)
+	}
+	if strings.TrimSpace(reason) != "" {
+		query += ", review_reason = ?"
+		args = append(args, reason)
+	}
+	query += " WHERE id = ?"
+	args = append(args, recordID)
+	_, err := tx.Exec(query, args...)
+	return err
+}
+
+// reverseInviteCommissionsTx claws back every commission paid for a refunded
+// order. When the order was the invitee's first payment, first_pay_at moves to
+// the next paid order or is cleared so the funnel no longer counts it.
+func reverseInviteCommissionsTx(tx *sql.Tx, orderID string, now time.Time) (int, error) {
+	type commission struct {
+		id          string
+		beneficiary string
+		amount      float64
+		status      string
+	}
+	rows, err := tx.Query(`
+SELECT id, inviter_user_id, reward_value, status
+FROM share_reward_records
+WHERE order_id = ? AND status IN ('PENDING', 'FROZEN', 'ISSUED')
+FOR UPDATE`, orderID)
+	if err != nil {
+		return 0, err
+	}
+	items := make([]commission, 0)
+	for rows.Next() {
+		var item commission
+		if err := rows.Scan(&item.id, &item.beneficiary, &item.amount, &item.status); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	for _, item := range items {
+		if err := transitionInviteCommissionTx(tx, item.id, item.beneficiary, item.amount, item.status, "REVERSED", "COMMISSION_REVERSAL", "订单退款，佣金已冲回", now); err != nil {
+			return 0, err
+		}
+	}
+
+	var inviteRecordID, inviteeUserID string
+	err = tx.QueryRow(
+		"SELECT id, invitee_user_id FROM invite_records WHERE first_pay_order_id = ? LIMIT 1 FOR UPDATE",
+		orderID,
+	).Scan(&inviteRecordID, &inviteeUserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return len(items), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var nextOrderID string
+	var nextPaidAt sql.NullTime
+	err = tx.QueryRow(`
+SELECT id, paid_at
+FROM membership_orders
+WHERE user_id = ? AND status = 'PAID' AND id <> ?
+ORDER BY paid_at ASC
+LIMIT 1`, inviteeUserID, orderID).Scan(&nextOrderID, &nextPaidAt)
+	switch {
+	case err == nil:
+		firstPayAt := now
+		if nextPaidAt.Valid {
+			firstPayAt = nextPaidAt.Time
+		}
+		_, err = tx.Exec(
+			"UPDATE invite_records SET first_pay_at = ?, first_pay_order_id = ? WHERE id = ?",
+			firstPayAt,
+			nextOrderID,
+			inviteRecordID,
+		)
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.Exec(
+			"UPDATE invite_records SET first_pay_at = NULL, first_pay_order_id = NULL, status = 'REGISTERED' WHERE id = ?",
+			inviteRecordID,
+		)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// AdminReleaseInviteCommissions moves commissions whose holding period has
+// ended from cash_frozen to cash_balance, where they become withdrawable.
+func (r *MySQLGrowthRepo) AdminReleaseInviteCommissions() (string, error) {
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+SELECT id, inviter_user_id, reward_value
+FROM share_reward_records
+WHERE status = 'PENDING' AND order_id IS NOT NULL AND available_at <= ?
+ORDER BY available_at ASC
+LIMIT ?
+FOR UPDATE`, now, inviteCommissionReleaseBatch)
+	if err != nil {
+		return "", err
+	}
+	type commission struct {
+		id          string
+		beneficiary string
+		amount      float64
+	}
+	items := make([]commission, 0)
+	for rows.Next() {
+		var item commission
+		if err := rows.Scan(&item.id, &item.beneficiary, &item.amount); err != nil {
+			rows.Close()
+			return "", err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return "", err
+	}
+	rows.Close()
+
+	total := 0.0
+	for _, item := range items {
+		if err := transitionInviteCommissionTx(tx, item.id, item.beneficiary, item.amount, "PENDING", "ISSUED", "COMMISSION_RELEASE", "", now); err != nil {
+			return "", err
+		}
+		total += item.amount
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("released=%d amount=%.2f", len(items), total), nil
+}
@@ -0,0 +1,221 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestInviteCommissionLevelAppliesModeAndCap(t *testing.T) {
+	level := inviteCommissionLevel{
+		Level:     1,
+		FirstPay:  inviteCommissionRule{Mode: "PERCENT", Value: 10},
+		Recurring: inviteCommissionRule{Mode: "FIXED", Value: 3.5},
+		Cap:       20,
+	}
+	if got := level.commission(inviteTriggerFirstPaid, 99.9); got != 9.99 {
+		t.Fatalf("expected 10%% of 99.9 = 9.99, got %v", got)
+	}
+	if got := level.commission(inviteTriggerFirstPaid, 999); got != 20 {
+		t.Fatalf("expected commission capped at 20, got %v", got)
+	}
+	if got := level.commission(inviteTriggerRenewalPaid, 99); got != 3.5 {
+		t.Fatalf("expected fixed renewal commission 3.5, got %v", got)
+	}
+	if got := level.commission(inviteTriggerRenewalPaid, 2); got != 2 {
+		t.Fatalf("expected fixed commission bounded by order amount, got %v", got)
+	}
+
+	rules, err := parseInviteCommissionRules(`{"holding_days":-3,"levels":[{"level":0},{"level":2,"first_pay":{"mode":"FIXED","value":1}}]}`)
+	if err != nil {
+		t.Fatalf("parseInviteCommissionRules() error = %v", err)
+	}
+	if rules.HoldingDays != 0 || len(rules.Levels) != 1 || rules.maxLevel() != 2 {
+		t.Fatalf("unexpected normalized rules: %+v", rules)
+	}
+	if _, ok := rules.levelRule(1); ok {
+		t.Fatalf("expected level 1 to pay nothing when not configured")
+	}
+}
+
+func TestAttributeInvitePaymentStampsFirstPayAndHoldsCommission(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, inviter_user_id, first_pay_at, risk_flag")).
+		WithArgs("u_invitee").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviter_user_id", "first_pay_at", "risk_flag"}).AddRow("inv_001", "u_inviter", nil, "NORMAL"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT config_value FROM system_configs WHERE config_key = ? LIMIT 1")).
+		WithArgs(inviteCommissionRulesConfigKey).
+		WillReturnRows(sqlmock.NewRows([]string{"config_value"}).AddRow(`{"holding_days":7,"levels":[{"level":1,"first_pay":{"mode":"PERCENT","value":10},"cap":5}]}`))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE invite_records")).
+		WithArgs(now, "ord_001", "inv_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM share_reward_records WHERE order_id = ? AND referral_level = ?")).
+		WithArgs("ord_001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallets")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM reward_wallets WHERE user_id = ? LIMIT 1")).
+		WithArgs("u_inviter").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rwd_001"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO share_reward_records")).
+		WithArgs(sqlmock.AnyArg(), "u_inviter", "u_invitee", "inv_001", "CASH", 5.0, inviteTriggerFirstPaid, "PENDING", nil, sqlmock.AnyArg(), now, "ord_001", 1, now.AddDate(0, 0, 7)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reward_wallets SET cash_frozen = cash_frozen + ?, updated_at = ? WHERE id = ?")).
+		WithArgs(5.0, now, "rwd_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallet_txns")).
+		WithArgs(sqlmock.AnyArg(), "rwd_001", "COMMISSION_HOLD", 5.0, sqlmock.AnyArg(), now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if err := attributeInvitePaymentTx(tx, "ord_001", "u_invitee", 99, now); err != nil {
+		t.Fatalf("attributeInvitePaymentTx() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit tx: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestReverseInviteCommissionsClawsBackIssuedAndClearsFirstPay(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, inviter_user_id, reward_value, status")).
+		WithArgs("ord_001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviter_user_id", "reward_value", "status"}).AddRow("rrd_001", "u_inviter", 5.0, "ISSUED"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallets")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM reward_wallets WHERE user_id = ? LIMIT 1")).
+		WithArgs("u_inviter").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rwd_001"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT cash_balance, cash_debt FROM reward_wallets WHERE id = ? FOR UPDATE")).
+		WithArgs("rwd_001").
+		WillReturnRows(sqlmock.NewRows([]string{"cash_balance", "cash_debt"}).AddRow(12.0, 0.0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reward_wallets SET cash_balance = cash_balance - ?, updated_at = ? WHERE id = ?")).
+		WithArgs(5.0, now, "rwd_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallet_txns")).
+		WithArgs(sqlmock.AnyArg(), "rwd_001", "COMMISSION_REVERSAL", 5.0, "rrd_001", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE share_reward_records SET status = ?, reversed_at = ?, review_reason = ? WHERE id = ?")).
+		WithArgs("REVERSED", now, sqlmock.AnyArg(), "rrd_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, invitee_user_id FROM invite_records WHERE first_pay_order_id = ?")).
+		WithArgs("ord_001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invitee_user_id"}).AddRow("inv_001", "u_invitee"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_orders")).
+		WithArgs("u_invitee", "ord_001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "paid_at"}))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE invite_records SET first_pay_at = NULL, first_pay_order_id = NULL, status = 'REGISTERED' WHERE id = ?")).
+		WithArgs("inv_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	reversed, err := reverseInviteCommissionsTx(tx, "ord_001", now)
+	if err != nil {
+		t.Fatalf("reverseInviteCommissionsTx() error = %v", err)
+	}
+	if reversed != 1 {
+		t.Fatalf("expected 1 reversed commission, got %d", reversed)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit tx: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestTransitionInviteCommissionBooksShortfallAsDebtAndRepaysIt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 3, 10, 0, 0, 0, time.UTC)
+	expectWallet := func(cashBalance float64, cashDebt float64) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallets")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM reward_wallets WHERE user_id = ? LIMIT 1")).
+			WithArgs("u_inviter").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rwd_001"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT cash_balance, cash_debt FROM reward_wallets WHERE id = ? FOR UPDATE")).
+			WithArgs("rwd_001").
+			WillReturnRows(sqlmock.NewRows([]string{"cash_balance", "cash_debt"}).AddRow(cashBalance, cashDebt))
+	}
+	mock.ExpectBegin()
+
+	// The inviter withdrew all but 3 of an issued 5: only 3 is taken back.
+	expectWallet(3, 0)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reward_wallets SET cash_balance = cash_balance - ?, cash_debt = cash_debt + ?, updated_at = ? WHERE id = ?")).
+		WithArgs(3.0, 2.0, now, "rwd_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES (?, ?, 'COMMISSION_DEBT', ?, 'SUCCESS', ?, ?)")).
+		WithArgs(sqlmock.AnyArg(), "rwd_001", 2.0, "rrd_001", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallet_txns")).
+		WithArgs(sqlmock.AnyArg(), "rwd_001", "COMMISSION_REVERSAL", 5.0, "rrd_001", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE share_reward_records SET status = ?, reversed_at = ?, review_reason = ? WHERE id = ?")).
+		WithArgs("REVERSED", now, sqlmock.AnyArg(), "rrd_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The next released commission repays the debt before adding to cash.
+	expectWallet(0, 2)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reward_wallets SET cash_frozen = cash_frozen - ?, cash_balance = cash_balance + ?, cash_debt = cash_debt + ?, updated_at = ? WHERE id = ?")).
+		WithArgs(8.0, 6.0, -2.0, now, "rwd_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES (?, ?, 'COMMISSION_DEBT', ?, 'SUCCESS', ?, ?)")).
+		WithArgs(sqlmock.AnyArg(), "rwd_001", -2.0, "rrd_002", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reward_wallet_txns")).
+		WithArgs(sqlmock.AnyArg(), "rwd_001", "COMMISSION_RELEASE", 8.0, "rrd_002", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE share_reward_records SET status = ?, issued_at = ? WHERE id = ?")).
+		WithArgs("ISSUED", now, "rrd_002").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if err := transitionInviteCommissionTx(tx, "rrd_001", "u_inviter", 5, "ISSUED", "REVERSED", "COMMISSION_REVERSAL", "订单退款，佣金已冲回", now); err != nil {
+		t.Fatalf("reverse commission: %v", err)
+	}
+	if err := transitionInviteCommissionTx(tx, "rrd_002", "u_inviter", 8, "PENDING", "ISSUED", "COMMISSION_RELEASE", "", now); err != nil {
+		t.Fatalf("release commission: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit tx: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -409,7 +409,7 @@ func (r *MySQLGrowthRepo) runStockEnhancementSyncForSource(sourceItem model.Data
 		var err error
 		switch provider {
 		case "TUSHARE":
-			token := strings.TrimSpace(parseDataSourceStringConfig(sourceItem.Config, "token", "api_token", "tushare_token"))
+			token := strings.TrimSpace(r.resolveDataSourceToken(sourceItem))
 			if token == "" {
 				token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 			}
@@ -436,7 +436,7 @@ func (r *MySQLGrowthRepo) runStockEnhancementSyncForSource(sourceItem model.Data
 		var err error
 		switch provider {
 		case "TUSHARE":
-			token := strings.TrimSpace(parseDataSourceStringConfig(sourceItem.Config, "token", "api_token", "tushare_token"))
+			token := strings.TrimSpace(r.resolveDataSourceToken(sourceItem))
 			if token == "" {
 				token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 			}
@@ -1579,7 +1579,7 @@ func (r *MySQLGrowthRepo) fetchMarketDailyBarsForSource(item model.DataSource, a
 	case "MOCK":
 		return buildMockMarketDailyBars(assetClass, sourceKey, instrumentKeys, days), "", nil
 	case "TUSHARE":
-		token := parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token")
+		token := r.resolveDataSourceToken(item)
 		if strings.TrimSpace(token) == "" {
 			token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 		}
@@ -1623,7 +1623,7 @@ func (r *MySQLGrowthRepo) fetchMarketDailyBarsForSourceDateRange(item model.Data
 	}
 	sourceKey = canonicalMarketSourceKey(sourceKey, provider)
 	if assetClass == marketAssetClassStock && provider == "TUSHARE" {
-		token := parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token")
+		token := r.resolveDataSourceToken(item)
 		if strings.TrimSpace(token) == "" {
 			token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 		}
@@ -1644,7 +1644,7 @@ func (r *MySQLGrowthRepo) fetchFuturesInventoryForSource(item model.DataSource,
 	case "MOCK":
 		return buildMockFuturesInventorySnapshots(sourceKey, symbols, days), "", nil
 	case "TUSHARE":
-		token := parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token")
+		token := r.resolveDataSourceToken(item)
 		if strings.TrimSpace(token) == "" {
 			token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 		}
@@ -2972,7 +2972,7 @@ func (r *MySQLGrowthRepo) fetchMarketNewsForSource(item model.DataSource, symbol
 	case "AKSHARE":
 		return fetchMarketNewsFromAkshareBridge(item.Config, sourceKey, symbols, days, limit)
 	case "TUSHARE":
-		token := parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token")
+		token := r.resolveDataSourceToken(item)
 		if strings.TrimSpace(token) == "" {
 			token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 		}
@@ -2989,8 +2989,8 @@ func (r *MySQLGrowthRepo) fetchMarketNewsForSource(item model.DataSource, symbol
 	}
 }
 
-func resolveTushareTokenFromDataSourceConfig(config map[string]interface{}) string {
-	token := parseDataSourceStringConfig(config, "token", "api_token", "tushare_token")
+func (r *MySQLGrowthRepo) resolveTushareTokenFromDataSourceConfig(item model.DataSource) string {
+	token := r.resolveDataSourceToken(item)
 	if strings.TrimSpace(token) == "" {
 		token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 	}
@@ -3008,7 +3008,7 @@ func (r *MySQLGrowthRepo) fetchStockDailyBasicsForSource(item model.DataSource,
 	case "AKSHARE":
 		return fetchStockDailyBasicsFromAkshareBridge(item.Config, sourceKey, symbols, days)
 	case "TUSHARE":
-		items, err := fetchStockDailyBasicsFromTushare(resolveTushareTokenFromDataSourceConfig(item.Config), sourceKey, symbols, days, parseDataSourceTimeoutMS(item.Config))
+		items, err := fetchStockDailyBasicsFromTushare(r.resolveTushareTokenFromDataSourceConfig(item), sourceKey, symbols, days, parseDataSourceTimeoutMS(item.Config))
 		return items, "", err
 	default:
 		return nil, "", fmt.Errorf("unsupported stock daily basic provider: %s", provider)
@@ -3026,7 +3026,7 @@ func (r *MySQLGrowthRepo) fetchStockMoneyflowsForSource(item model.DataSource, s
 	case "AKSHARE":
 		return fetchStockMoneyflowsFromAkshareBridge(item.Config, sourceKey, symbols, days)
 	case "TUSHARE":
-		items, err := fetchStockMoneyflowsFromTushare(resolveTushareTokenFromDataSourceConfig(item.Config), sourceKey, symbols, days, parseDataSourceTimeoutMS(item.Config))
+		items, err := fetchStockMoneyflowsFromTushare(r.resolveTushareTokenFromDataSourceConfig(item), sourceKey, symbols, days, parseDataSourceTimeoutMS(item.Config))
 		return items, "", err
 	default:
 		return nil, "", fmt.Errorf("unsupported stock moneyflow provider: %s", provider)
@@ -3044,7 +3044,7 @@ func (r *MySQLGrowthRepo) fetchStockNewsRawForSource(item model.DataSource, symb
 	case "AKSHARE":
 		return fetchStockNewsRawFromAkshareBridge(item.Config, sourceKey, symbols, days)
 	case "TUSHARE":
-		items, err := fetchStockNewsFromTushare(resolveTushareTokenFromDataSourceConfig(item.Config), sourceKey, symbols, days, parseDataSourceTimeoutMS(item.Config))
+		items, err := fetchStockNewsFromTushare(r.resolveTushareTokenFromDataSourceConfig(item), sourceKey, symbols, days, parseDataSourceTimeoutMS(item.Config))
 		return items, "", err
 	default:
 		return nil, "", fmt.Errorf("unsupported stock news provider: %s", provider)
@@ -3607,10 +3607,14 @@ func (r *MySQLGrowthRepo) syncLegacyStockQuotesFromTruthBars(items []model.Marke
 	return err
 }
 
+// upsertMarketNewsItems stores the items and links each one to its stored
+// near-duplicates. Items in the slice get their ID, DuplicateOf and
+// CanonicalTitle filled in so callers can cluster them afterwards.
 func (r *MySQLGrowthRepo) upsertMarketNewsItems(items []model.MarketNewsItem) (int, error) {
 	now := time.Now()
 	affected := 0
-	for _, item := range items {
+	for i := range items {
+		item := items[i]
 		title := strings.TrimSpace(item.Title)
 		sourceKey := strings.ToUpper(strings.TrimSpace(item.SourceKey))
 		if title == "" || sourceKey == "" {
@@ -3658,6 +3662,9 @@ ON DUPLICATE KEY UPDATE
 		if err != nil {
 			return affected, err
 		}
+		if err := r.linkMarketNewsNearDuplicate(&items[i], sourceKey, externalID, publishedAt); err != nil {
+			return affected, err
+		}
 		affected++
 	}
 	return affected, nil
@@ -3734,10 +3741,15 @@ func (r *MySQLGrowthRepo) upsertDraftStockEventClustersFromMarketNews(items []mo
 	return affected, nil
 }
 
+// buildDraftStockEventClustersFromMarketNews groups items into draft event
+// clusters. Near-duplicate stories, whether linked during sync or found within
+// the batch, are keyed by their canonical title so that the same story from
+// several sources lands in one cluster.
 func buildDraftStockEventClustersFromMarketNews(items []model.MarketNewsItem) []model.StockEventCluster {
 	clusterMap := make(map[string]*model.StockEventCluster)
 	clusterOrder := make([]string, 0, len(items))
-	for _, item := range items {
+	clusterTitles := resolveMarketNewsClusterTitles(items)
+	for idx, item := range items {
 		title := strings.TrimSpace(item.Title)
 		if title == "" {
 			continue
@@ -3751,7 +3763,7 @@ func buildDraftStockEventClustersFromMarketNews(items []model.MarketNewsItem) []
 			primarySymbol = symbols[0]
 		}
 		eventType := inferStockEventTypeFromNews(item)
-		clusterGroupKey := buildDraftStockEventGroupKey(item, primarySymbol, eventType)
+		clusterGroupKey := buildDraftStockEventGroupKey(clusterTitles[idx], primarySymbol, eventType)
 		cluster, exists := clusterMap[clusterGroupKey]
 		if !exists {
 			clusterID := newID("sec")
@@ -3771,7 +3783,7 @@ func buildDraftStockEventClustersFromMarketNews(items []model.MarketNewsItem) []
 				Metadata: map[string]any{
 					"draft_source":        "market_news_sync",
 					"news_type":           coalesceUpper(item.NewsType, "MARKET"),
-					"cluster_title_key":   normalizeStockEventClusterTitle(title),
+					"cluster_title_key":   normalizeStockEventClusterTitle(clusterTitles[idx]),
 					"review_priority":     reviewPriority,
 					"review_reason_codes": reviewReasonCodes,
 				},
@@ -3824,8 +3836,8 @@ func buildDraftStockEventEntities(clusterID string, symbols []string) []model.St
 	return entities
 }
 
-func buildDraftStockEventGroupKey(item model.MarketNewsItem, primarySymbol string, eventType string) string {
-	seed := primarySymbol + "|" + eventType + "|" + normalizeStockEventClusterTitle(item.Title)
+func buildDraftStockEventGroupKey(title string, primarySymbol string, eventType string) string {
+	seed := primarySymbol + "|" + eventType + "|" + normalizeStockEventClusterTitle(title)
 	sum := sha1.Sum([]byte(seed))
 	return "draft:" + hex.EncodeToString(sum[:8])
 }
@@ -3925,7 +3937,7 @@ func (r *MySQLGrowthRepo) ensureHighPriorityStockEventReviewTask(cluster model.S
 			return nil
 		}
 	}
-	_, err = r.AdminSubmitReviewTask("STOCK_EVENT", cluster.ID, "system", "", "高优先级股票事件待审核")
+	_, err = r.AdminSubmitReviewTask("STOCK_EVENT", cluster.ID, "system", "", "高优先级股票事件待审核", model.ReviewSLAPriorityHigh)
 	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "pending review task already exists") {
 		return err
 	}
@@ -223,7 +223,7 @@ func (r *MySQLGrowthRepo) fetchMarketInstrumentFactsForSource(item model.DataSou
 	sourceKey = canonicalMarketSourceKey(sourceKey, provider)
 	switch provider {
 	case "TUSHARE":
-		token := parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token")
+		token := r.resolveDataSourceToken(item)
 		if strings.TrimSpace(token) == "" {
 			token = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 		}
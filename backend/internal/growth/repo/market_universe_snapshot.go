@@ -317,7 +317,7 @@ func (r *MySQLGrowthRepo) fetchMarketUniverseItemsForSource(sourceKey string, as
 			provider = configuredProvider
 		}
 		resolvedSourceKey = canonicalMarketSourceKey(resolvedSourceKey, provider)
-		if configuredToken := strings.TrimSpace(parseDataSourceStringConfig(sourceItem.Config, "token", "api_token", "tushare_token")); configuredToken != "" {
+		if configuredToken := strings.TrimSpace(r.resolveDataSourceToken(sourceItem)); configuredToken != "" {
 			token = configuredToken
 		}
 		if configuredTimeout := parseDataSourceTimeoutMS(sourceItem.Config); configuredTimeout > 0 {
@@ -0,0 +1,811 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+const (
+	membershipOrderSourceUser      = "USER"
+	membershipOrderSourceAutoRenew = "AUTO_RENEW"
+
+	// membershipAutoRenewExperimentKey attributes renewal orders so their
+	// outcomes show up in the experiment analytics pay-channel breakdown.
+	membershipAutoRenewExperimentKey = "membership_auto_renew"
+)
+
+type membershipAutoRenewConfig struct {
+	LeadHours   int
+	RemindHours int
+	RetryHours  []int
+	GraceDays   int
+	BatchSize   int
+}
+
+// membershipAutoRenewalRow carries the raw times the job computes with next
+// to the API view of the renewal.
+type membershipAutoRenewalRow struct {
+	model.MembershipAutoRenewal
+	periodEnd  time.Time
+	graceUntil sql.NullTime
+}
+
+const membershipAutoRenewalColumns = `id, user_id, product_id, pay_channel, agreement_no, status, current_period_end, next_charge_at,
+failed_attempts, grace_until, reminded_at, last_order_no, last_error, canceled_at, created_at, updated_at`
+
+func (r *MySQLGrowthRepo) resolveMembershipAutoRenewConfig() membershipAutoRenewConfig {
+	cfg := membershipAutoRenewConfig{LeadHours: 24, RemindHours: 72, RetryHours: []int{6, 24, 48}, GraceDays: 3, BatchSize: 100}
+	items, _, err := r.AdminListSystemConfigs("membership.auto_renew.", 1, 50)
+	if err != nil {
+		return cfg
+	}
+	for _, item := range items {
+		value := strings.TrimSpace(item.ConfigValue)
+		switch strings.ToLower(strings.TrimSpace(item.ConfigKey)) {
+		case "membership.auto_renew.lead_hours":
+			cfg.LeadHours = parseRepoConfigInt(value, cfg.LeadHours)
+		case "membership.auto_renew.remind_hours":
+			cfg.RemindHours = parseRepoConfigInt(value, cfg.RemindHours)
+		case "membership.auto_renew.retry_hours":
+			cfg.RetryHours = parseMembershipAutoRenewRetryHours(value, cfg.RetryHours)
+		case "membership.auto_renew.grace_days":
+			cfg.GraceDays = parseRepoConfigInt(value, cfg.GraceDays)
+		case "membership.auto_renew.batch_size":
+			cfg.BatchSize = parseRepoConfigInt(value, cfg.BatchSize)
+		}
+	}
+	if cfg.LeadHours < 1 {
+		cfg.LeadHours = 1
+	}
+	if cfg.RemindHours < cfg.LeadHours {
+		cfg.RemindHours = cfg.LeadHours
+	}
+	if cfg.GraceDays < 0 {
+		cfg.GraceDays = 0
+	}
+	if cfg.BatchSize <= 0 || cfg.BatchSize > 500 {
+		cfg.BatchSize = 100
+	}
+	return cfg
+}
+
+// parseMembershipAutoRenewRetryHours reads a comma separated list of hours to
+// wait after each failed charge, e.g. "6,24,48".
+func parseMembershipAutoRenewRetryHours(raw string, fallback []int) []int {
+	hours := make([]int, 0)
+	for _, part := range strings.Split(raw, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value <= 0 {
+			return fallback
+		}
+		hours = append(hours, value)
+	}
+	return hours
+}
+
+func scanMembershipAutoRenewal(scanner interface {
+	Scan(dest ...interface{}) error
+}) (membershipAutoRenewalRow, error) {
+	var item membershipAutoRenewalRow
+	var periodEnd, nextChargeAt, remindedAt, canceledAt sql.NullTime
+	var lastOrderNo, lastError sql.NullString
+	var createdAt, updatedAt time.Time
+	if err := scanner.Scan(
+		&item.ID, &item.UserID, &item.ProductID, &item.PayChannel, &item.AgreementNo, &item.Status, &periodEnd, &nextChargeAt,
+		&item.FailedAttempts, &item.graceUntil, &remindedAt, &lastOrderNo, &lastError, &canceledAt, &createdAt, &updatedAt,
+	); err != nil {
+		return item, err
+	}
+	if periodEnd.Valid {
+		item.periodEnd = periodEnd.Time
+		item.CurrentPeriodEnd = periodEnd.Time.Format(time.RFC3339)
+	}
+	if nextChargeAt.Valid {
+		item.NextChargeAt = nextChargeAt.Time.Format(time.RFC3339)
+	}
+	if item.graceUntil.Valid {
+		item.GraceUntil = item.graceUntil.Time.Format(time.RFC3339)
+	}
+	if remindedAt.Valid {
+		item.RemindedAt = remindedAt.Time.Format(time.RFC3339)
+	}
+	if canceledAt.Valid {
+		item.CanceledAt = canceledAt.Time.Format(time.RFC3339)
+	}
+	item.LastOrderNo = lastOrderNo.String
+	item.LastError = lastError.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return item, nil
+}
+
+func (r *MySQLGrowthRepo) getMembershipAutoRenewal(userID string) (membershipAutoRenewalRow, error) {
+	item, err := scanMembershipAutoRenewal(r.db.QueryRow(
+		"SELECT "+membershipAutoRenewalColumns+" FROM membership_auto_renewals WHERE user_id = ?",
+		userID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return item, model.ErrMembershipAutoRenewNotFound
+	}
+	return item, err
+}
+
+func (r *MySQLGrowthRepo) GetMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error) {
+	item, err := r.getMembershipAutoRenewal(userID)
+	return item.MembershipAutoRenewal, err
+}
+
+// EnableMembershipAutoRenewal opts an active VIP into renewing productID
+// through a signed agreement. A PAST_DUE renewal keeps its dunning state and
+// is simply retried right away with the new agreement, so swapping the
+// payment method recovers it without resetting the grace period.
+func (r *MySQLGrowthRepo) EnableMembershipAutoRenewal(userID string, productID string, payChannel string, agreementNo string) (model.MembershipAutoRenewal, error) {
+	cfg := r.resolveMembershipAutoRenewConfig()
+	now := time.Now()
+	var productStatus string
+	var productLevel sql.NullString
+	err := r.db.QueryRow("SELECT status, member_level FROM membership_products WHERE id = ?", productID).Scan(&productStatus, &productLevel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.MembershipAutoRenewal{}, fmt.Errorf("%w: membership product not found", model.ErrMembershipAutoRenewNotEligible)
+	}
+	if err != nil {
+		return model.MembershipAutoRenewal{}, err
+	}
+	if strings.ToUpper(productStatus) != "ACTIVE" || strings.TrimSpace(productLevel.String) == "" {
+		return model.MembershipAutoRenewal{}, fmt.Errorf("%w: membership product not active", model.ErrMembershipAutoRenewNotEligible)
+	}
+
+	var memberLevel string
+	var expireAt sql.NullTime
+	if err := r.db.QueryRow("SELECT member_level, vip_expire_at FROM users WHERE id = ?", userID).Scan(&memberLevel, &expireAt); err != nil {
+		return model.MembershipAutoRenewal{}, err
+	}
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(memberLevel)), "VIP") || !expireAt.Valid || !expireAt.Time.After(now) {
+		return model.MembershipAutoRenewal{}, fmt.Errorf("%w: auto-renew requires an active VIP membership", model.ErrMembershipAutoRenewNotEligible)
+	}
+	payChannel = strings.ToUpper(strings.TrimSpace(payChannel))
+
+	existing, err := r.getMembershipAutoRenewal(userID)
+	if err != nil && !errors.Is(err, model.ErrMembershipAutoRenewNotFound) {
+		return model.MembershipAutoRenewal{}, err
+	}
+	if err == nil && existing.Status == "PAST_DUE" {
+		if _, err := r.db.Exec(`
+UPDATE membership_auto_renewals
+SET product_id = ?, pay_channel = ?, agreement_no = ?, next_charge_at = ?, updated_at = ?
+WHERE id = ?`, productID, payChannel, agreementNo, now, now, existing.ID); err != nil {
+			return model.MembershipAutoRenewal{}, err
+		}
+		return r.GetMembershipAutoRenewal(userID)
+	}
+
+	nextChargeAt := expireAt.Time.Add(-time.Duration(cfg.LeadHours) * time.Hour)
+	if nextChargeAt.Before(now) {
+		nextChargeAt = now
+	}
+	_, err = r.db.Exec(`
+INSERT INTO membership_auto_renewals
+(id, user_id, product_id, pay_channel, agreement_no, status, current_period_end, next_charge_at, failed_attempts, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, 'ACTIVE', ?, ?, 0, ?, ?)
+ON DUPLICATE KEY UPDATE
+	product_id = VALUES(product_id),
+	pay_channel = VALUES(pay_channel),
+	agreement_no = VALUES(agreement_no),
+	status = 'ACTIVE',
+	current_period_end = VALUES(current_period_end),
+	next_charge_at = VALUES(next_charge_at),
+	failed_attempts = 0,
+	grace_until = NULL,
+	reminded_at = NULL,
+	last_error = NULL,
+	canceled_at = NULL,
+	updated_at = VALUES(updated_at)`,
+		newID("mar"), userID, productID, payChannel, agreementNo, expireAt.Time, nextChargeAt, now, now,
+	)
+	if err != nil {
+		return model.MembershipAutoRenewal{}, err
+	}
+	return r.GetMembershipAutoRenewal(userID)
+}
+
+// CancelMembershipAutoRenewal stops renewing and returns the renewal as it
+// was, so the caller can release the channel agreement. Canceling while
+// PAST_DUE ends the grace period: VIP falls back to the paid period end.
+func (r *MySQLGrowthRepo) CancelMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error) {
+	item, err := r.getMembershipAutoRenewal(userID)
+	if err != nil {
+		return model.MembershipAutoRenewal{}, err
+	}
+	if item.Status != "ACTIVE" && item.Status != "PAST_DUE" {
+		return model.MembershipAutoRenewal{}, model.ErrMembershipAutoRenewNotFound
+	}
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.MembershipAutoRenewal{}, err
+	}
+	result, err := tx.Exec(`
+UPDATE membership_auto_renewals
+SET status = 'CANCELED', next_charge_at = NULL, canceled_at = ?, updated_at = ?
+WHERE id = ? AND status IN ('ACTIVE', 'PAST_DUE')`, now, now, item.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.MembershipAutoRenewal{}, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		_ = tx.Rollback()
+		return model.MembershipAutoRenewal{}, model.ErrMembershipAutoRenewNotFound
+	}
+	if item.Status == "PAST_DUE" {
+		if err := revertMembershipAutoRenewGrace(tx, item, now); err != nil {
+			_ = tx.Rollback()
+			return model.MembershipAutoRenewal{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return model.MembershipAutoRenewal{}, err
+	}
+	return item.MembershipAutoRenewal, nil
+}
+
+// revertMembershipAutoRenewGrace takes back the grace days granted on the
+// first failed charge. A later purchase that pushed vip_expire_at past the
+// grace window is left alone.
+func revertMembershipAutoRenewGrace(execer sqlExecer, item membershipAutoRenewalRow, now time.Time) error {
+	if !item.graceUntil.Valid || item.periodEnd.IsZero() {
+		return nil
+	}
+	_, err := execer.Exec(
+		"UPDATE users SET vip_expire_at = ?, updated_at = ? WHERE id = ? AND vip_expire_at > ? AND vip_expire_at <= ?",
+		item.periodEnd, now, item.UserID, item.periodEnd, item.graceUntil.Time,
+	)
+	return err
+}
+
+// AdminRunMembershipAutoRenewals reminds users of upcoming renewals, then
+// charges every renewal whose next_charge_at has come through its channel
+// agreement. A failed charge starts dunning: VIP is extended to the grace
+// period end and the charge is retried on membership.auto_renew.retry_hours;
+// once retries run out or would fall past the grace period the renewal
+// LAPSES and the grace days are taken back. Every pass is logged to
+// membership_renewal_attempts.
+func (r *MySQLGrowthRepo) AdminRunMembershipAutoRenewals(channels payment.Resolver) (string, error) {
+	cfg := r.resolveMembershipAutoRenewConfig()
+	now := time.Now()
+	reminded, err := r.remindMembershipAutoRenewals(cfg, now)
+	if err != nil {
+		return "", err
+	}
+
+	rows, err := r.db.Query(`
+SELECT `+membershipAutoRenewalColumns+`
+FROM membership_auto_renewals
+WHERE status IN ('ACTIVE', 'PAST_DUE') AND next_charge_at IS NOT NULL AND next_charge_at <= ?
+ORDER BY next_charge_at ASC
+LIMIT ?`, now, cfg.BatchSize)
+	if err != nil {
+		return "", err
+	}
+	due := make([]membershipAutoRenewalRow, 0)
+	for rows.Next() {
+		item, err := scanMembershipAutoRenewal(rows)
+		if err != nil {
+			rows.Close()
+			return "", err
+		}
+		due = append(due, item)
+	}
+	if err := rows.Close(); err != nil {
+		return "", err
+	}
+
+	counts := map[string]int{}
+	for _, item := range due {
+		attempt, err := r.renewMembership(item, channels, cfg, now)
+		if err != nil {
+			return "", err
+		}
+		counts[attempt.Result]++
+	}
+	return fmt.Sprintf(
+		"due=%d renewed=%d pending=%d failed=%d lapsed=%d synced=%d reminded=%d",
+		len(due), counts["PAID"], counts["PENDING"], counts["FAILED"], counts["LAPSED"], counts["SYNCED"], reminded,
+	), nil
+}
+
+func (r *MySQLGrowthRepo) remindMembershipAutoRenewals(cfg membershipAutoRenewConfig, now time.Time) (int, error) {
+	rows, err := r.db.Query(`
+SELECT ar.id, ar.user_id, ar.pay_channel, ar.current_period_end, ar.next_charge_at, p.name, p.price
+FROM membership_auto_renewals ar
+JOIN membership_products p ON p.id = ar.product_id
+WHERE ar.status = 'ACTIVE'
+  AND ar.reminded_at IS NULL
+  AND ar.next_charge_at > ?
+  AND ar.current_period_end <= ?
+ORDER BY ar.current_period_end ASC
+LIMIT ?`, now, now.Add(time.Duration(cfg.RemindHours)*time.Hour), cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	type reminderCandidate struct {
+		ID           string
+		UserID       string
+		PayChannel   string
+		PeriodEnd    time.Time
+		NextChargeAt time.Time
+		ProductName  string
+		Price        float64
+	}
+	candidates := make([]reminderCandidate, 0)
+	for rows.Next() {
+		var item reminderCandidate
+		if err := rows.Scan(&item.ID, &item.UserID, &item.PayChannel, &item.PeriodEnd, &item.NextChargeAt, &item.ProductName, &item.Price); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		candidates = append(candidates, item)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, item := range candidates {
+		result, err := r.db.Exec(
+			"UPDATE membership_auto_renewals SET reminded_at = ?, updated_at = ? WHERE id = ? AND reminded_at IS NULL",
+			now, now, item.ID,
+		)
+		if err != nil {
+			return count, err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			continue
+		}
+		content := fmt.Sprintf(
+			"您的会员将于%s到期，系统将于%s通过%s自动续费「%s」%.2f元。如不需要续费，可在个人中心取消自动续费。",
+			item.PeriodEnd.Format("2006-01-02 15:04:05"),
+			item.NextChargeAt.Format("2006-01-02 15:04:05"),
+			item.PayChannel,
+			item.ProductName,
+			item.Price,
+		)
+		if err := insertSystemMessageTx(r.db, item.UserID, "会员自动续费提醒", content, now); err != nil {
+			return count, err
+		}
+		r.notifyUserMessage(item.UserID)
+		count++
+	}
+	return count, nil
+}
+
+// renewMembership makes one renewal pass for a due renewal. Channel and
+// product problems are recorded as a failed attempt rather than returned, so
+// one broken agreement does not stall the batch; only database errors abort.
+func (r *MySQLGrowthRepo) renewMembership(item membershipAutoRenewalRow, channels payment.Resolver, cfg membershipAutoRenewConfig, now time.Time) (model.MembershipRenewalAttempt, error) {
+	attempt := model.MembershipRenewalAttempt{
+		ID:         newID("mra"),
+		RenewalID:  item.ID,
+		UserID:     item.UserID,
+		PayChannel: item.PayChannel,
+		Attempt:    item.FailedAttempts + 1,
+	}
+
+	// A manual purchase or a late callback for the previous renewal order may
+	// already have paid for the next period.
+	var expireAt sql.NullTime
+	if err := r.db.QueryRow("SELECT vip_expire_at FROM users WHERE id = ?", item.UserID).Scan(&expireAt); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return attempt, err
+	}
+	coveredUntil := item.periodEnd
+	if item.graceUntil.Valid {
+		coveredUntil = item.graceUntil.Time
+	}
+	if expireAt.Valid && expireAt.Time.After(coveredUntil.Add(time.Minute)) {
+		attempt.Result = "SYNCED"
+		attempt.Message = "membership already extended to " + expireAt.Time.Format(time.RFC3339)
+		if err := r.rollMembershipAutoRenewal(item, expireAt.Time, "", cfg, now); err != nil {
+			return attempt, err
+		}
+		return attempt, r.logMembershipRenewalAttempt(attempt)
+	}
+	if item.LastOrderNo != "" {
+		done, err := r.checkPendingMembershipRenewalOrder(item, &attempt, channels, cfg, now)
+		if err != nil {
+			return attempt, err
+		}
+		if done {
+			return attempt, r.logMembershipRenewalAttempt(attempt)
+		}
+	}
+
+	// Check the channel can still charge the agreement before raising an
+	// order, so a channel without withholding support leaves no orphaned
+	// AUTO_RENEW orders behind.
+	channel, err := channels.Channel(item.PayChannel)
+	if err != nil {
+		return attempt, r.failMembershipAutoRenewal(item, &attempt, cfg, now, err.Error())
+	}
+	charger, ok := channel.(payment.AgreementCharger)
+	if !ok {
+		return attempt, r.failMembershipAutoRenewal(item, &attempt, cfg, now, payment.ErrUnsupported.Error())
+	}
+
+	order, err := r.createMembershipOrder(item.UserID, item.ProductID, item.PayChannel, "", membershipOrderSourceAutoRenew)
+	if err != nil {
+		return attempt, r.failMembershipAutoRenewal(item, &attempt, cfg, now, err.Error())
+	}
+	attempt.OrderNo = order.OrderNo
+	if err := r.BindMembershipOrderExperiment(order.OrderNo, model.ExperimentOrderAttribution{
+		ExperimentKey:  membershipAutoRenewExperimentKey,
+		VariantKey:     "default",
+		PageKey:        "auto_renew",
+		TargetKey:      item.ProductID,
+		UserStage:      "VIP",
+		ConversionType: "RENEWAL_SUCCESS",
+		Metadata: map[string]interface{}{
+			"pay_channel":  item.PayChannel,
+			"order_source": membershipOrderSourceAutoRenew,
+			"attempt":      attempt.Attempt,
+		},
+	}); err != nil {
+		return attempt, err
+	}
+
+	trade, err := charger.ChargeAgreement(payment.AgreementCharge{
+		AgreementNo: item.AgreementNo,
+		OrderNo:     order.OrderNo,
+		UserID:      item.UserID,
+		Subject:     "会员自动续费-" + item.ProductID,
+		Amount:      order.Amount,
+	})
+	if err != nil {
+		return attempt, r.failMembershipAutoRenewal(item, &attempt, cfg, now, err.Error())
+	}
+	attempt.TradeStatus = trade.Status
+	attempt.ChannelTxnNo = trade.ChannelTxnNo
+
+	switch trade.Status {
+	case payment.TradeStatusPaid:
+		if err := r.settleMembershipRenewalTrade(item, &attempt, channel, order.OrderNo, trade, cfg, now); err != nil {
+			return attempt, err
+		}
+	case payment.TradeStatusPending:
+		// The channel settles asynchronously; the order poller or callback
+		// grants VIP and the next pass picks that up as SYNCED.
+		if err := r.deferMembershipAutoRenewal(item, &attempt, order.OrderNo, cfg, now); err != nil {
+			return attempt, err
+		}
+	default:
+		return attempt, r.failMembershipAutoRenewal(item, &attempt, cfg, now, "channel trade "+strings.ToLower(trade.Status))
+	}
+	return attempt, r.logMembershipRenewalAttempt(attempt)
+}
+
+// checkPendingMembershipRenewalOrder asks the channel about the previous
+// renewal order while it is still PENDING, so a charge that went through late
+// is settled instead of charged again. A paid order rolls the renewal and an
+// order the channel has not finished with waits for the next pass; only an
+// order the channel reports FAILED is canceled so a new charge can go out.
+// It returns true when the attempt needs no new charge.
+func (r *MySQLGrowthRepo) checkPendingMembershipRenewalOrder(item membershipAutoRenewalRow, attempt *model.MembershipRenewalAttempt, channels payment.Resolver, cfg membershipAutoRenewConfig, now time.Time) (bool, error) {
+	var status string
+	err := r.db.QueryRow("SELECT status FROM membership_orders WHERE order_no = ?", item.LastOrderNo).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if status != "PENDING" {
+		return false, nil
+	}
+
+	attempt.OrderNo = item.LastOrderNo
+	channel, err := channels.Channel(item.PayChannel)
+	if err != nil {
+		attempt.Message = err.Error()
+		return true, r.deferMembershipAutoRenewal(item, attempt, item.LastOrderNo, cfg, now)
+	}
+	trade, err := channel.QueryOrder(item.LastOrderNo)
+	if err != nil {
+		// Not knowing whether the order was paid is no reason to charge
+		// again. The order poller closes it once it expires.
+		attempt.Message = err.Error()
+		return true, r.deferMembershipAutoRenewal(item, attempt, item.LastOrderNo, cfg, now)
+	}
+	attempt.TradeStatus = trade.Status
+	attempt.ChannelTxnNo = trade.ChannelTxnNo
+	switch trade.Status {
+	case payment.TradeStatusPaid:
+		return true, r.settleMembershipRenewalTrade(item, attempt, channel, item.LastOrderNo, trade, cfg, now)
+	case payment.TradeStatusFailed:
+		attempt.TradeStatus, attempt.ChannelTxnNo = "", ""
+		_, err := r.db.Exec(
+			"UPDATE membership_orders SET status = 'CANCELED', updated_at = ? WHERE order_no = ? AND status = 'PENDING'",
+			now, item.LastOrderNo,
+		)
+		return false, err
+	default:
+		return true, r.deferMembershipAutoRenewal(item, attempt, item.LastOrderNo, cfg, now)
+	}
+}
+
+// settleMembershipRenewalTrade grants the period a paid renewal order bought
+// and rolls the renewal, then logs the attempt as PAID.
+func (r *MySQLGrowthRepo) settleMembershipRenewalTrade(item membershipAutoRenewalRow, attempt *model.MembershipRenewalAttempt, channel payment.Channel, orderNo string, trade payment.Trade, cfg membershipAutoRenewConfig, now time.Time) error {
+	// Grace days were a loan while dunning; the renewal period starts
+	// from the paid period end, not from the grace period end.
+	if err := revertMembershipAutoRenewGrace(r.db, item, now); err != nil {
+		return err
+	}
+	idempotencyKey := strings.TrimSpace(trade.IdempotencyKey)
+	if idempotencyKey == "" {
+		idempotencyKey = strings.ToLower(channel.Name()) + ":" + trade.ChannelTxnNo
+	}
+	settleErr := r.HandlePaymentCallback(channel.Name(), orderNo, trade.ChannelTxnNo, idempotencyKey, "", true)
+	if settleErr != nil && !strings.Contains(strings.ToLower(settleErr.Error()), "duplicate") {
+		return settleErr
+	}
+	var newExpireAt sql.NullTime
+	if err := r.db.QueryRow("SELECT vip_expire_at FROM users WHERE id = ?", item.UserID).Scan(&newExpireAt); err != nil {
+		return err
+	}
+	attempt.Result = "PAID"
+	return r.rollMembershipAutoRenewal(item, newExpireAt.Time, orderNo, cfg, now)
+}
+
+// deferMembershipAutoRenewal leaves orderNo open and checks it again after
+// the first retry interval.
+func (r *MySQLGrowthRepo) deferMembershipAutoRenewal(item membershipAutoRenewalRow, attempt *model.MembershipRenewalAttempt, orderNo string, cfg membershipAutoRenewConfig, now time.Time) error {
+	attempt.Result = "PENDING"
+	_, err := r.db.Exec(
+		"UPDATE membership_auto_renewals SET last_order_no = ?, next_charge_at = ?, updated_at = ? WHERE id = ?",
+		orderNo, now.Add(time.Duration(cfg.RetryHours[0])*time.Hour), now, item.ID,
+	)
+	return err
+}
+
+// rollMembershipAutoRenewal starts the next period after a successful renewal.
+func (r *MySQLGrowthRepo) rollMembershipAutoRenewal(item membershipAutoRenewalRow, periodEnd time.Time, orderNo string, cfg membershipAutoRenewConfig, now time.Time) error {
+	nextChargeAt := periodEnd.Add(-time.Duration(cfg.LeadHours) * time.Hour)
+	if nextChargeAt.Before(now) {
+		nextChargeAt = now
+	}
+	_, err := r.db.Exec(`
+UPDATE membership_auto_renewals
+SET status = 'ACTIVE',
+    current_period_end = ?,
+    next_charge_at = ?,
+    failed_attempts = 0,
+    grace_until = NULL,
+    reminded_at = NULL,
+    last_order_no = COALESCE(?, last_order_no),
+    last_error = NULL,
+    updated_at = ?
+WHERE id = ? AND status IN ('ACTIVE', 'PAST_DUE')`,
+		periodEnd, nextChargeAt, nullableString(orderNo), now, item.ID,
+	)
+	return err
+}
+
+// failMembershipAutoRenewal records a failed charge and moves the renewal
+// along the dunning schedule, or lapses it when no retry fits in the grace
+// period.
+func (r *MySQLGrowthRepo) failMembershipAutoRenewal(item membershipAutoRenewalRow, attempt *model.MembershipRenewalAttempt, cfg membershipAutoRenewConfig, now time.Time, reason string) error {
+	attempt.Message = reason
+	graceUntil := item.graceUntil.Time
+	if !item.graceUntil.Valid {
+		base := item.periodEnd
+		if base.Before(now) {
+			base = now
+		}
+		graceUntil = base.AddDate(0, 0, cfg.GraceDays)
+	}
+	failed := item.FailedAttempts + 1
+	var nextChargeAt time.Time
+	lapse := true
+	if failed <= len(cfg.RetryHours) {
+		nextChargeAt = now.Add(time.Duration(cfg.RetryHours[failed-1]) * time.Hour)
+		lapse = !nextChargeAt.Before(graceUntil)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	if attempt.OrderNo != "" {
+		if _, err := tx.Exec(
+			"UPDATE membership_orders SET status = 'FAILED', updated_at = ? WHERE order_no = ? AND status = 'PENDING'",
+			now, attempt.OrderNo,
+		); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := insertExperimentEventTx(tx, model.ExperimentEvent{
+		ExperimentKey: membershipAutoRenewExperimentKey,
+		VariantKey:    "default",
+		EventType:     "RENEWAL_FAILED",
+		PageKey:       "auto_renew",
+		TargetKey:     item.ProductID,
+		UserStage:     "VIP",
+		Metadata: map[string]interface{}{
+			"pay_channel": item.PayChannel,
+			"order_no":    attempt.OrderNo,
+			"attempt":     attempt.Attempt,
+		},
+	}, now); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	var title, content string
+	if lapse {
+		attempt.Result = "LAPSED"
+		if _, err := tx.Exec(`
+UPDATE membership_auto_renewals
+SET status = 'LAPSED', failed_attempts = ?, next_charge_at = NULL, grace_until = NULL,
+    last_order_no = COALESCE(?, last_order_no), last_error = ?, updated_at = ?
+WHERE id = ?`, failed, nullableString(attempt.OrderNo), truncateByRunes(reason, 255), now, item.ID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := revertMembershipAutoRenewGrace(tx, item, now); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		expireAt := item.periodEnd
+		if expireAt.Before(now) {
+			expireAt = now
+		}
+		title = "会员自动续费已停止"
+		content = fmt.Sprintf(
+			"自动续费多次扣款失败（%s），已停止自动续费，会员将于%s到期。您可以在会员中心重新购买或重新开启自动续费。",
+			reason,
+			expireAt.Format("2006-01-02 15:04:05"),
+		)
+	} else {
+		attempt.Result = "FAILED"
+		if _, err := tx.Exec(`
+UPDATE membership_auto_renewals
+SET status = 'PAST_DUE', failed_attempts = ?, next_charge_at = ?, grace_until = ?,
+    last_order_no = COALESCE(?, last_order_no), last_error = ?, updated_at = ?
+WHERE id = ?`, failed, nextChargeAt, graceUntil, nullableString(attempt.OrderNo), truncateByRunes(reason, 255), now, item.ID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(
+			"UPDATE users SET vip_expire_at = ?, updated_at = ? WHERE id = ? AND member_level LIKE 'VIP%' AND vip_expire_at IS NOT NULL AND vip_expire_at < ?",
+			graceUntil, now, item.UserID, graceUntil,
+		); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		title = "会员自动续费扣款失败"
+		content = fmt.Sprintf(
+			"自动续费扣款失败（%s），系统将于%s重试；宽限期内会员权益保留至%s。您也可以在会员中心更换支付方式。",
+			reason,
+			nextChargeAt.Format("2006-01-02 15:04:05"),
+			graceUntil.Format("2006-01-02 15:04:05"),
+		)
+	}
+	if err := insertSystemMessageTx(tx, item.UserID, title, content, now); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	r.notifyUserMessage(item.UserID)
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return r.logMembershipRenewalAttempt(*attempt)
+}
+
+func (r *MySQLGrowthRepo) logMembershipRenewalAttempt(attempt model.MembershipRenewalAttempt) error {
+	_, err := r.db.Exec(`
+INSERT INTO membership_renewal_attempts
+(id, renewal_id, user_id, order_no, pay_channel, attempt, result, trade_status, channel_txn_no, message, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		attempt.ID, attempt.RenewalID, attempt.UserID, nullableString(attempt.OrderNo), attempt.PayChannel, attempt.Attempt,
+		attempt.Result, nullableString(attempt.TradeStatus), nullableString(attempt.ChannelTxnNo),
+		nullableString(truncateByRunes(attempt.Message, 255)), time.Now(),
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) AdminListMembershipAutoRenewals(status string, userID string, page int, pageSize int) ([]model.MembershipAutoRenewal, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 2)
+	args := []interface{}{}
+	if strings.TrimSpace(status) != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(status)))
+	}
+	if strings.TrimSpace(userID) != "" {
+		filters = append(filters, "user_id = ?")
+		args = append(args, strings.TrimSpace(userID))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM membership_auto_renewals"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT `+membershipAutoRenewalColumns+`
+FROM membership_auto_renewals`+where+`
+ORDER BY updated_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.MembershipAutoRenewal, 0)
+	for rows.Next() {
+		item, err := scanMembershipAutoRenewal(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item.MembershipAutoRenewal)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminListMembershipRenewalAttempts(renewalID string, result string, page int, pageSize int) ([]model.MembershipRenewalAttempt, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 2)
+	args := []interface{}{}
+	if strings.TrimSpace(renewalID) != "" {
+		filters = append(filters, "renewal_id = ?")
+		args = append(args, strings.TrimSpace(renewalID))
+	}
+	if strings.TrimSpace(result) != "" {
+		filters = append(filters, "result = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(result)))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM membership_renewal_attempts"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, renewal_id, user_id, order_no, pay_channel, attempt, result, trade_status, channel_txn_no, message, created_at
+FROM membership_renewal_attempts`+where+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.MembershipRenewalAttempt, 0)
+	for rows.Next() {
+		var item model.MembershipRenewalAttempt
+		var orderNo, tradeStatus, channelTxnNo, message sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.RenewalID, &item.UserID, &orderNo, &item.PayChannel, &item.Attempt, &item.Result, &tradeStatus, &channelTxnNo, &message, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		item.OrderNo = orderNo.String
+		item.TradeStatus = tradeStatus.String
+		item.ChannelTxnNo = channelTxnNo.String
+		item.Message = message.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
@@ -0,0 +1,266 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+// The in-memory repo has no VIP state: every user counts as a VIP whose
+// period ends 30 days after opting in, and renewals follow the default
+// membership.auto_renew.* schedule.
+var inMemoryAutoRenewConfig = membershipAutoRenewConfig{LeadHours: 24, RemindHours: 72, RetryHours: []int{6, 24, 48}, GraceDays: 3, BatchSize: 100}
+
+func (r *InMemoryGrowthRepo) GetMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.autoRenewals[userID]
+	if !ok {
+		return model.MembershipAutoRenewal{}, model.ErrMembershipAutoRenewNotFound
+	}
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) EnableMembershipAutoRenewal(userID string, productID string, payChannel string, agreementNo string) (model.MembershipAutoRenewal, error) {
+	if productID != "mp_demo_001" {
+		return model.MembershipAutoRenewal{}, fmt.Errorf("%w: membership product not found", model.ErrMembershipAutoRenewNotEligible)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	item, ok := r.autoRenewals[userID]
+	if !ok {
+		item = model.MembershipAutoRenewal{ID: newID("mar"), UserID: userID, CreatedAt: now.Format(time.RFC3339)}
+	}
+	item.ProductID = productID
+	item.PayChannel = strings.ToUpper(strings.TrimSpace(payChannel))
+	item.AgreementNo = agreementNo
+	item.UpdatedAt = now.Format(time.RFC3339)
+	if item.Status == "PAST_DUE" {
+		item.NextChargeAt = item.UpdatedAt
+	} else {
+		periodEnd := now.AddDate(0, 0, 30)
+		item.Status = "ACTIVE"
+		item.CurrentPeriodEnd = periodEnd.Format(time.RFC3339)
+		item.NextChargeAt = periodEnd.Add(-time.Duration(inMemoryAutoRenewConfig.LeadHours) * time.Hour).Format(time.RFC3339)
+		item.FailedAttempts = 0
+		item.GraceUntil, item.RemindedAt, item.LastError, item.CanceledAt = "", "", "", ""
+	}
+	r.autoRenewals[userID] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) CancelMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.autoRenewals[userID]
+	if !ok || (item.Status != "ACTIVE" && item.Status != "PAST_DUE") {
+		return model.MembershipAutoRenewal{}, model.ErrMembershipAutoRenewNotFound
+	}
+	canceled := item
+	canceled.Status = "CANCELED"
+	canceled.NextChargeAt = ""
+	canceled.CanceledAt = time.Now().Format(time.RFC3339)
+	canceled.UpdatedAt = canceled.CanceledAt
+	r.autoRenewals[userID] = canceled
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRunMembershipAutoRenewals(channels payment.Resolver) (string, error) {
+	now := time.Now()
+	r.mu.Lock()
+	due := make([]model.MembershipAutoRenewal, 0)
+	for _, item := range r.autoRenewals {
+		nextChargeAt, err := time.Parse(time.RFC3339, item.NextChargeAt)
+		if (item.Status == "ACTIVE" || item.Status == "PAST_DUE") && err == nil && !nextChargeAt.After(now) {
+			due = append(due, item)
+		}
+	}
+	r.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, item := range due {
+		attempt := model.MembershipRenewalAttempt{ID: newID("mra"), RenewalID: item.ID, UserID: item.UserID, PayChannel: item.PayChannel, Attempt: item.FailedAttempts + 1}
+		var trade payment.Trade
+		channel, err := channels.Channel(item.PayChannel)
+
+		// Settle or wait on a previous order that is still PENDING rather
+		// than charging again; only a FAILED one is replaced.
+		order, prior := r.pendingMembershipOrderByNo(item.LastOrderNo)
+		if prior {
+			if err == nil {
+				trade, err = channel.QueryOrder(order.OrderNo)
+			}
+			if err == nil && trade.Status == payment.TradeStatusFailed {
+				r.mu.Lock()
+				canceled := r.membershipOrders[order.ID]
+				canceled.Status = "CANCELED"
+				r.membershipOrders[order.ID] = canceled
+				r.mu.Unlock()
+				prior, trade = false, payment.Trade{}
+			} else if err != nil || trade.Status != payment.TradeStatusPaid {
+				attempt.OrderNo = order.OrderNo
+				attempt.TradeStatus, attempt.ChannelTxnNo = trade.Status, trade.ChannelTxnNo
+				if err != nil {
+					attempt.Message = err.Error()
+				}
+				attempt.Result = "PENDING"
+				attempt.CreatedAt = now.Format(time.RFC3339)
+				r.mu.Lock()
+				current := r.autoRenewals[item.UserID]
+				current.NextChargeAt = now.Add(time.Duration(inMemoryAutoRenewConfig.RetryHours[0]) * time.Hour).Format(time.RFC3339)
+				current.UpdatedAt = now.Format(time.RFC3339)
+				r.autoRenewals[item.UserID] = current
+				r.renewalAttempts = append(r.renewalAttempts, attempt)
+				r.mu.Unlock()
+				counts[attempt.Result]++
+				continue
+			}
+		}
+		if !prior {
+			var charger payment.AgreementCharger
+			channel, err = channels.Channel(item.PayChannel)
+			if err == nil {
+				var ok bool
+				if charger, ok = channel.(payment.AgreementCharger); !ok {
+					err = payment.ErrUnsupported
+				}
+			}
+			if err == nil {
+				order, err = r.CreateMembershipOrder(item.UserID, item.ProductID, item.PayChannel, "")
+				if err != nil {
+					return "", err
+				}
+				r.mu.Lock()
+				order.OrderSource = membershipOrderSourceAutoRenew
+				r.membershipOrders[order.ID] = order
+				r.mu.Unlock()
+				trade, err = charger.ChargeAgreement(payment.AgreementCharge{AgreementNo: item.AgreementNo, OrderNo: order.OrderNo, UserID: item.UserID, Amount: order.Amount})
+			}
+		}
+		attempt.OrderNo = order.OrderNo
+		attempt.TradeStatus, attempt.ChannelTxnNo = trade.Status, trade.ChannelTxnNo
+		if err == nil && trade.Status == payment.TradeStatusPaid {
+			if settleErr := r.HandlePaymentCallback(channel.Name(), order.OrderNo, trade.ChannelTxnNo, trade.IdempotencyKey, "", true); settleErr != nil && !strings.Contains(settleErr.Error(), "duplicate") {
+				return "", settleErr
+			}
+		}
+
+		r.mu.Lock()
+		current := r.autoRenewals[item.UserID]
+		current.LastOrderNo = order.OrderNo
+		current.UpdatedAt = now.Format(time.RFC3339)
+		switch {
+		case err == nil && trade.Status == payment.TradeStatusPaid:
+			periodEnd, _ := time.Parse(time.RFC3339, current.CurrentPeriodEnd)
+			if periodEnd.Before(now) {
+				periodEnd = now
+			}
+			periodEnd = periodEnd.AddDate(0, 0, 30)
+			attempt.Result = "PAID"
+			current.Status = "ACTIVE"
+			current.CurrentPeriodEnd = periodEnd.Format(time.RFC3339)
+			current.NextChargeAt = periodEnd.Add(-time.Duration(inMemoryAutoRenewConfig.LeadHours) * time.Hour).Format(time.RFC3339)
+			current.FailedAttempts = 0
+			current.GraceUntil, current.RemindedAt, current.LastError = "", "", ""
+		case err == nil && trade.Status == payment.TradeStatusPending:
+			attempt.Result = "PENDING"
+			current.NextChargeAt = now.Add(time.Duration(inMemoryAutoRenewConfig.RetryHours[0]) * time.Hour).Format(time.RFC3339)
+		default:
+			if err == nil {
+				err = fmt.Errorf("channel trade %s", strings.ToLower(trade.Status))
+			}
+			attempt.Message = err.Error()
+			if order.ID != "" {
+				failedOrder := r.membershipOrders[order.ID]
+				failedOrder.Status = "FAILED"
+				r.membershipOrders[order.ID] = failedOrder
+			}
+
+			if current.GraceUntil == "" {
+				periodEnd, _ := time.Parse(time.RFC3339, current.CurrentPeriodEnd)
+				if periodEnd.Before(now) {
+					periodEnd = now
+				}
+				current.GraceUntil = periodEnd.AddDate(0, 0, inMemoryAutoRenewConfig.GraceDays).Format(time.RFC3339)
+			}
+			graceUntil, _ := time.Parse(time.RFC3339, current.GraceUntil)
+			current.FailedAttempts++
+			current.LastError = attempt.Message
+			var nextChargeAt time.Time
+			if current.FailedAttempts <= len(inMemoryAutoRenewConfig.RetryHours) {
+				nextChargeAt = now.Add(time.Duration(inMemoryAutoRenewConfig.RetryHours[current.FailedAttempts-1]) * time.Hour)
+			}
+			if !nextChargeAt.IsZero() && nextChargeAt.Before(graceUntil) {
+				attempt.Result = "FAILED"
+				current.Status = "PAST_DUE"
+				current.NextChargeAt = nextChargeAt.Format(time.RFC3339)
+			} else {
+				attempt.Result = "LAPSED"
+				current.Status = "LAPSED"
+				current.NextChargeAt, current.GraceUntil = "", ""
+			}
+		}
+		r.autoRenewals[item.UserID] = current
+		attempt.CreatedAt = now.Format(time.RFC3339)
+		r.renewalAttempts = append(r.renewalAttempts, attempt)
+		r.mu.Unlock()
+		counts[attempt.Result]++
+	}
+	return fmt.Sprintf(
+		"due=%d renewed=%d pending=%d failed=%d lapsed=%d synced=%d reminded=%d",
+		len(due), counts["PAID"], counts["PENDING"], counts["FAILED"], counts["LAPSED"], 0, 0,
+	), nil
+}
+
+func (r *InMemoryGrowthRepo) pendingMembershipOrderByNo(orderNo string) (model.MembershipOrderAdmin, bool) {
+	if orderNo == "" {
+		return model.MembershipOrderAdmin{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.membershipOrders {
+		if order.OrderNo == orderNo && order.Status == "PENDING" {
+			return order, true
+		}
+	}
+	return model.MembershipOrderAdmin{}, false
+}
+
+func (r *InMemoryGrowthRepo) AdminListMembershipAutoRenewals(status string, userID string, page int, pageSize int) ([]model.MembershipAutoRenewal, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.MembershipAutoRenewal, 0, len(r.autoRenewals))
+	for _, item := range r.autoRenewals {
+		if (status == "" || strings.EqualFold(item.Status, status)) && (userID == "" || item.UserID == userID) {
+			items = append(items, item)
+		}
+	}
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.MembershipAutoRenewal{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListMembershipRenewalAttempts(renewalID string, result string, page int, pageSize int) ([]model.MembershipRenewalAttempt, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.MembershipRenewalAttempt, 0)
+	for i := len(r.renewalAttempts) - 1; i >= 0; i-- {
+		item := r.renewalAttempts[i]
+		if (renewalID == "" || item.RenewalID == renewalID) && (result == "" || strings.EqualFold(item.Result, result)) {
+			items = append(items, item)
+		}
+	}
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.MembershipRenewalAttempt{}, total, nil
+	}
+	return items[start:end], total, nil
+}
@@ -0,0 +1,278 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/platform/payment"
+)
+
+func membershipAutoRenewalRows(status string, periodEnd time.Time, failedAttempts int, graceUntil interface{}, agreementNo string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "user_id", "product_id", "pay_channel", "agreement_no", "status", "current_period_end", "next_charge_at",
+		"failed_attempts", "grace_until", "reminded_at", "last_order_no", "last_error", "canceled_at", "created_at", "updated_at",
+	}).AddRow(
+		"mar_1", "u_1", "mp_1", "SANDBOX", agreementNo, status, periodEnd, now.Add(-time.Minute),
+		failedAttempts, graceUntil, nil, nil, nil, nil, now, now,
+	)
+}
+
+func expectAutoRenewDueBatch(mock sqlmock.Sqlmock, rows *sqlmock.Rows, vipExpireAt time.Time) {
+	expectAutoRenewDueRenewal(mock, rows, vipExpireAt)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT price, status, member_level FROM membership_products WHERE id = ?")).
+		WithArgs("mp_1").
+		WillReturnRows(sqlmock.NewRows([]string{"price", "status", "member_level"}).AddRow(99.0, "ACTIVE", "VIP1"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_orders")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "u_1", "mp_1", 99.0, 99.0, 0.0, nil, "SANDBOX", "AUTO_RENEW", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO experiment_order_attributions")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func expectAutoRenewDueRenewal(mock sqlmock.Sqlmock, rows *sqlmock.Rows, vipExpireAt time.Time) {
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_auto_renewals ar")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "pay_channel", "current_period_end", "next_charge_at", "name", "price"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_auto_renewals\nWHERE status IN ('ACTIVE', 'PAST_DUE')")).
+		WithArgs(sqlmock.AnyArg(), 100).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vip_expire_at FROM users WHERE id = ?")).
+		WithArgs("u_1").
+		WillReturnRows(sqlmock.NewRows([]string{"vip_expire_at"}).AddRow(vipExpireAt))
+}
+
+func failingSandboxAgreement(t *testing.T) (payment.Resolver, string) {
+	t.Helper()
+	channel := payment.NewSandboxChannel(payment.NewSandboxLedger(), payment.SandboxConfig{Outcome: payment.SandboxOutcomeFailure})
+	agreementNo, err := channel.SignAgreement(payment.AgreementRequest{UserID: "u_1", ProductID: "mp_1"})
+	if err != nil {
+		t.Fatalf("SignAgreement() error = %v", err)
+	}
+	return payment.ResolverFunc(func(name string) (payment.Channel, error) { return channel, nil }), agreementNo
+}
+
+func TestMySQLRunMembershipAutoRenewalsStartsDunningOnFailedCharge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	channels, agreementNo := failingSandboxAgreement(t)
+	periodEnd := time.Now().Add(20 * time.Hour).Truncate(time.Second)
+	graceUntil := periodEnd.AddDate(0, 0, 3)
+	expectAutoRenewDueBatch(mock, membershipAutoRenewalRows("ACTIVE", periodEnd, 0, nil, agreementNo), periodEnd)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_orders SET status = 'FAILED'")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO experiment_events")).
+		WithArgs(sqlmock.AnyArg(), "membership_auto_renew", "default", "RENEWAL_FAILED", "auto_renew", "mp_1", "VIP", "", "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'PAST_DUE'")).
+		WithArgs(1, sqlmock.AnyArg(), graceUntil, sqlmock.AnyArg(), "channel trade failed", sqlmock.AnyArg(), "mar_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET vip_expire_at = ?")).
+		WithArgs(graceUntil, sqlmock.AnyArg(), "u_1", graceUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WithArgs(sqlmock.AnyArg(), "u_1", "会员自动续费扣款失败", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_renewal_attempts")).
+		WithArgs(sqlmock.AnyArg(), "mar_1", "u_1", sqlmock.AnyArg(), "SANDBOX", 1, "FAILED", payment.TradeStatusFailed, sqlmock.AnyArg(), "channel trade failed", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunMembershipAutoRenewals(channels)
+	if err != nil {
+		t.Fatalf("AdminRunMembershipAutoRenewals() error = %v", err)
+	}
+	if summary != "due=1 renewed=0 pending=0 failed=1 lapsed=0 synced=0 reminded=0" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLRunMembershipAutoRenewalsLapsesAfterLastRetry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	channels, agreementNo := failingSandboxAgreement(t)
+	periodEnd := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	graceUntil := periodEnd.AddDate(0, 0, 3)
+	expectAutoRenewDueBatch(mock, membershipAutoRenewalRows("PAST_DUE", periodEnd, 3, graceUntil, agreementNo), graceUntil)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_orders SET status = 'FAILED'")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO experiment_events")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'LAPSED'")).
+		WithArgs(4, sqlmock.AnyArg(), "channel trade failed", sqlmock.AnyArg(), "mar_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET vip_expire_at = ?, updated_at = ? WHERE id = ? AND vip_expire_at > ? AND vip_expire_at <= ?")).
+		WithArgs(periodEnd, sqlmock.AnyArg(), "u_1", periodEnd, graceUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WithArgs(sqlmock.AnyArg(), "u_1", "会员自动续费已停止", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_renewal_attempts")).
+		WithArgs(sqlmock.AnyArg(), "mar_1", "u_1", sqlmock.AnyArg(), "SANDBOX", 4, "LAPSED", payment.TradeStatusFailed, sqlmock.AnyArg(), "channel trade failed", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunMembershipAutoRenewals(channels)
+	if err != nil {
+		t.Fatalf("AdminRunMembershipAutoRenewals() error = %v", err)
+	}
+	if summary != "due=1 renewed=0 pending=0 failed=0 lapsed=1 synced=0 reminded=0" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func expectAutoRenewWithPriorOrder(mock sqlmock.Sqlmock, periodEnd time.Time) {
+	now := time.Now()
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_auto_renewals ar")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "pay_channel", "current_period_end", "next_charge_at", "name", "price"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_auto_renewals\nWHERE status IN ('ACTIVE', 'PAST_DUE')")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "product_id", "pay_channel", "agreement_no", "status", "current_period_end", "next_charge_at",
+			"failed_attempts", "grace_until", "reminded_at", "last_order_no", "last_error", "canceled_at", "created_at", "updated_at",
+		}).AddRow("mar_1", "u_1", "mp_1", "SANDBOX", "AGR_1", "ACTIVE", periodEnd, now.Add(-time.Minute), 0, nil, nil, "MO_PRIOR", nil, nil, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vip_expire_at FROM users WHERE id = ?")).
+		WithArgs("u_1").
+		WillReturnRows(sqlmock.NewRows([]string{"vip_expire_at"}).AddRow(periodEnd))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM membership_orders WHERE order_no = ?")).
+		WithArgs("MO_PRIOR").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("PENDING"))
+}
+
+func TestMySQLRunMembershipAutoRenewalsSettlesPriorOrderPaidLate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	stub := &payment.StubChannel{Trades: map[string]payment.Trade{
+		"MO_PRIOR": {OrderNo: "MO_PRIOR", ChannelTxnNo: "T1", Status: payment.TradeStatusPaid, IdempotencyKey: "sandbox:T1"},
+	}}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return stub, nil })
+	periodEnd := time.Now().Add(20 * time.Hour).Truncate(time.Second)
+	renewedUntil := periodEnd.AddDate(0, 0, 30)
+	expectAutoRenewWithPriorOrder(mock, periodEnd)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_callback_logs")).
+		WithArgs(sqlmock.AnyArg(), "STUB", "MO_PRIOR", "T1", 1, "sandbox:T1", sqlmock.AnyArg()).
+		WillReturnError(errors.New("Error 1062: Duplicate entry 'sandbox:T1' for key 'uk_idempotency_key'"))
+	mock.ExpectRollback()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vip_expire_at FROM users WHERE id = ?")).
+		WithArgs("u_1").
+		WillReturnRows(sqlmock.NewRows([]string{"vip_expire_at"}).AddRow(renewedUntil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_auto_renewals\nSET status = 'ACTIVE'")).
+		WithArgs(renewedUntil, sqlmock.AnyArg(), "MO_PRIOR", sqlmock.AnyArg(), "mar_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_renewal_attempts")).
+		WithArgs(sqlmock.AnyArg(), "mar_1", "u_1", "MO_PRIOR", "SANDBOX", 1, "PAID", payment.TradeStatusPaid, "T1", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunMembershipAutoRenewals(channels)
+	if err != nil {
+		t.Fatalf("AdminRunMembershipAutoRenewals() error = %v", err)
+	}
+	if summary != "due=1 renewed=1 pending=0 failed=0 lapsed=0 synced=0 reminded=0" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLRunMembershipAutoRenewalsWaitsOnUnsettledPriorOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	stub := &payment.StubChannel{Trades: map[string]payment.Trade{
+		"MO_PRIOR": {OrderNo: "MO_PRIOR", ChannelTxnNo: "T1", Status: payment.TradeStatusPending},
+	}}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return stub, nil })
+	periodEnd := time.Now().Add(20 * time.Hour).Truncate(time.Second)
+	expectAutoRenewWithPriorOrder(mock, periodEnd)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_auto_renewals SET last_order_no = ?, next_charge_at = ?")).
+		WithArgs("MO_PRIOR", sqlmock.AnyArg(), sqlmock.AnyArg(), "mar_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_renewal_attempts")).
+		WithArgs(sqlmock.AnyArg(), "mar_1", "u_1", "MO_PRIOR", "SANDBOX", 1, "PENDING", payment.TradeStatusPending, "T1", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunMembershipAutoRenewals(channels)
+	if err != nil {
+		t.Fatalf("AdminRunMembershipAutoRenewals() error = %v", err)
+	}
+	if summary != "due=1 renewed=0 pending=1 failed=0 lapsed=0 synced=0 reminded=0" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLRunMembershipAutoRenewalsRaisesNoOrderOnChannelWithoutAgreements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return &payment.StubChannel{}, nil })
+	periodEnd := time.Now().Add(20 * time.Hour).Truncate(time.Second)
+	graceUntil := periodEnd.AddDate(0, 0, 3)
+	expectAutoRenewDueRenewal(mock, membershipAutoRenewalRows("ACTIVE", periodEnd, 0, nil, "agr_1"), periodEnd)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO experiment_events")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'PAST_DUE'")).
+		WithArgs(1, sqlmock.AnyArg(), graceUntil, sqlmock.AnyArg(), payment.ErrUnsupported.Error(), sqlmock.AnyArg(), "mar_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET vip_expire_at = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_renewal_attempts")).
+		WithArgs(sqlmock.AnyArg(), "mar_1", "u_1", nil, "SANDBOX", 1, "FAILED", nil, nil, payment.ErrUnsupported.Error(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunMembershipAutoRenewals(channels)
+	if err != nil {
+		t.Fatalf("AdminRunMembershipAutoRenewals() error = %v", err)
+	}
+	if summary != "due=1 renewed=0 pending=0 failed=1 lapsed=0 synced=0 reminded=0" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,443 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// membershipCouponMinPayable keeps a discounted order chargeable; gateways
+// reject zero-amount trades.
+const membershipCouponMinPayable = 0.01
+
+// membershipCouponQueryer is satisfied by *sql.DB for quotes and by *sql.Tx
+// when the coupon is reserved together with the order.
+type membershipCouponQueryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+const membershipCouponColumns = `id, code, name, discount_type, discount_value, max_discount, min_amount,
+	valid_from, valid_to, total_limit, per_user_limit, product_ids, member_levels, first_purchase_only,
+	source, owner_user_id, invite_record_id, status, created_by, created_at, updated_at`
+
+func normalizeMembershipCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// membershipCouponDiscount returns the discount a coupon gives on price,
+// rounded to cents. The payable amount never drops below one cent.
+func membershipCouponDiscount(coupon model.MembershipCoupon, price float64) float64 {
+	var discount float64
+	switch strings.ToUpper(coupon.DiscountType) {
+	case "PERCENT":
+		discount = price * coupon.DiscountValue / 100
+		if coupon.MaxDiscount > 0 && discount > coupon.MaxDiscount {
+			discount = coupon.MaxDiscount
+		}
+	case "FIXED":
+		discount = coupon.DiscountValue
+	}
+	discount = math.Round(discount*100) / 100
+	if maxDiscount := math.Round((price-membershipCouponMinPayable)*100) / 100; discount > maxDiscount {
+		discount = maxDiscount
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return discount
+}
+
+// checkMembershipCoupon applies the rules that need no counting: status,
+// validity window, owner, product and member-level scope, minimum amount.
+func checkMembershipCoupon(coupon model.MembershipCoupon, userID string, productID string, memberLevel string, price float64, now time.Time) error {
+	if strings.ToUpper(coupon.Status) != "ACTIVE" {
+		return fmt.Errorf("%w: coupon is %s", model.ErrMembershipCouponNotApplicable, strings.ToLower(coupon.Status))
+	}
+	if coupon.ValidFrom != "" {
+		if from, err := time.Parse(time.RFC3339, coupon.ValidFrom); err == nil && now.Before(from) {
+			return fmt.Errorf("%w: coupon is not valid until %s", model.ErrMembershipCouponNotApplicable, coupon.ValidFrom)
+		}
+	}
+	if coupon.ValidTo != "" {
+		if to, err := time.Parse(time.RFC3339, coupon.ValidTo); err == nil && !now.Before(to) {
+			return fmt.Errorf("%w: coupon expired at %s", model.ErrMembershipCouponNotApplicable, coupon.ValidTo)
+		}
+	}
+	if coupon.OwnerUserID != "" && coupon.OwnerUserID != userID {
+		return fmt.Errorf("%w: coupon belongs to another user", model.ErrMembershipCouponNotApplicable)
+	}
+	if len(coupon.ProductIDs) > 0 && !containsFold(coupon.ProductIDs, productID) {
+		return fmt.Errorf("%w: coupon does not apply to product %s", model.ErrMembershipCouponNotApplicable, productID)
+	}
+	if len(coupon.MemberLevels) > 0 && !containsFold(coupon.MemberLevels, memberLevel) {
+		return fmt.Errorf("%w: coupon does not apply to member level %s", model.ErrMembershipCouponNotApplicable, memberLevel)
+	}
+	if coupon.MinAmount > 0 && price < coupon.MinAmount {
+		return fmt.Errorf("%w: order amount below %.2f", model.ErrMembershipCouponNotApplicable, coupon.MinAmount)
+	}
+	return nil
+}
+
+func containsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitMembershipCouponList(raw string) []string {
+	items := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+func joinMembershipCouponList(items []string, upper bool) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if upper {
+			item = strings.ToUpper(item)
+		}
+		if item != "" && !containsFold(parts, item) {
+			parts = append(parts, item)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// scanMembershipCoupon reads membershipCouponColumns followed by any extra
+// aggregate columns.
+func scanMembershipCoupon(scanner interface {
+	Scan(dest ...interface{}) error
+}, extra ...interface{}) (model.MembershipCoupon, error) {
+	var item model.MembershipCoupon
+	var validFrom, validTo sql.NullTime
+	var productIDs, memberLevels string
+	var ownerUserID, inviteRecordID sql.NullString
+	var createdAt, updatedAt time.Time
+	dest := []interface{}{
+		&item.ID, &item.Code, &item.Name, &item.DiscountType, &item.DiscountValue, &item.MaxDiscount, &item.MinAmount,
+		&validFrom, &validTo, &item.TotalLimit, &item.PerUserLimit, &productIDs, &memberLevels, &item.FirstPurchaseOnly,
+		&item.Source, &ownerUserID, &inviteRecordID, &item.Status, &item.CreatedBy, &createdAt, &updatedAt,
+	}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return item, err
+	}
+	if validFrom.Valid {
+		item.ValidFrom = validFrom.Time.Format(time.RFC3339)
+	}
+	if validTo.Valid {
+		item.ValidTo = validTo.Time.Format(time.RFC3339)
+	}
+	item.ProductIDs = splitMembershipCouponList(productIDs)
+	item.MemberLevels = splitMembershipCouponList(memberLevels)
+	item.OwnerUserID = ownerUserID.String
+	item.InviteRecordID = inviteRecordID.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return item, nil
+}
+
+// quoteMembershipCoupon validates code for userID buying productID and prices
+// the order. Reserved and redeemed uses both count towards the limits, so two
+// concurrent orders cannot both take the last use; pass lock=true inside the
+// order transaction to serialise on the coupon row.
+func quoteMembershipCoupon(q membershipCouponQueryer, userID string, productID string, memberLevel string, price float64, code string, lock bool) (model.MembershipCoupon, model.MembershipCouponQuote, error) {
+	code = normalizeMembershipCouponCode(code)
+	query := "SELECT " + membershipCouponColumns + " FROM membership_coupons WHERE code = ?"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	coupon, err := scanMembershipCoupon(q.QueryRow(query, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return coupon, model.MembershipCouponQuote{}, model.ErrMembershipCouponNotFound
+		}
+		return coupon, model.MembershipCouponQuote{}, err
+	}
+	if err := checkMembershipCoupon(coupon, userID, productID, memberLevel, price, time.Now()); err != nil {
+		return coupon, model.MembershipCouponQuote{}, err
+	}
+
+	var totalUsed, userUsed int
+	if err := q.QueryRow(`
+SELECT COUNT(*), COALESCE(SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END), 0)
+FROM membership_coupon_redemptions
+WHERE coupon_id = ? AND status IN ('RESERVED', 'REDEEMED')`, userID, coupon.ID).Scan(&totalUsed, &userUsed); err != nil {
+		return coupon, model.MembershipCouponQuote{}, err
+	}
+	if coupon.TotalLimit > 0 && totalUsed >= coupon.TotalLimit {
+		return coupon, model.MembershipCouponQuote{}, fmt.Errorf("%w: coupon has been fully redeemed", model.ErrMembershipCouponNotApplicable)
+	}
+	if coupon.PerUserLimit > 0 && userUsed >= coupon.PerUserLimit {
+		return coupon, model.MembershipCouponQuote{}, fmt.Errorf("%w: coupon already used", model.ErrMembershipCouponNotApplicable)
+	}
+	if coupon.FirstPurchaseOnly {
+		var paidOrders int
+		if err := q.QueryRow(
+			"SELECT COUNT(*) FROM membership_orders WHERE user_id = ? AND status IN ('PAID', 'REFUNDED')",
+			userID,
+		).Scan(&paidOrders); err != nil {
+			return coupon, model.MembershipCouponQuote{}, err
+		}
+		if paidOrders > 0 {
+			return coupon, model.MembershipCouponQuote{}, fmt.Errorf("%w: coupon is for first purchase only", model.ErrMembershipCouponNotApplicable)
+		}
+	}
+
+	discount := membershipCouponDiscount(coupon, price)
+	return coupon, model.MembershipCouponQuote{
+		CouponCode:     coupon.Code,
+		ProductID:      productID,
+		OriginalAmount: price,
+		DiscountAmount: discount,
+		Amount:         math.Round((price-discount)*100) / 100,
+	}, nil
+}
+
+func (r *MySQLGrowthRepo) QuoteMembershipCoupon(userID string, productID string, code string) (model.MembershipCouponQuote, error) {
+	var price float64
+	var status string
+	var memberLevel sql.NullString
+	if err := r.db.QueryRow(
+		"SELECT price, status, member_level FROM membership_products WHERE id = ?",
+		productID,
+	).Scan(&price, &status, &memberLevel); err != nil {
+		return model.MembershipCouponQuote{}, err
+	}
+	if strings.ToUpper(status) != "ACTIVE" {
+		return model.MembershipCouponQuote{}, errors.New("membership product not active")
+	}
+	_, quote, err := quoteMembershipCoupon(r.db, userID, productID, memberLevel.String, price, code, false)
+	return quote, err
+}
+
+// releaseMembershipCoupon gives the coupon use back when an order is closed
+// without payment.
+func releaseMembershipCoupon(execer sqlExecer, orderID string, now time.Time) error {
+	_, err := execer.Exec(
+		"UPDATE membership_coupon_redemptions SET status = 'RELEASED', updated_at = ? WHERE order_id = ? AND status = 'RESERVED'",
+		now,
+		orderID,
+	)
+	return err
+}
+
+// redeemMembershipCoupon marks the order's coupon use spent. A RELEASED use is
+// taken back as well, since a late callback still completes a closed order.
+func redeemMembershipCoupon(execer sqlExecer, orderID string, now time.Time) error {
+	_, err := execer.Exec(
+		"UPDATE membership_coupon_redemptions SET status = 'REDEEMED', redeemed_at = ?, updated_at = ? WHERE order_id = ? AND status IN ('RESERVED', 'RELEASED')",
+		now,
+		now,
+		orderID,
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) AdminListMembershipCoupons(status string, source string, page int, pageSize int) ([]model.MembershipCoupon, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 2)
+	args := []interface{}{}
+	if strings.TrimSpace(status) != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(status)))
+	}
+	if strings.TrimSpace(source) != "" {
+		filters = append(filters, "source = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(source)))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM membership_coupons"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT `+membershipCouponColumns+`,
+	(SELECT COUNT(*) FROM membership_coupon_redemptions cr WHERE cr.coupon_id = membership_coupons.id AND cr.status = 'REDEEMED') AS redeemed_count
+FROM membership_coupons`+where+`
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.MembershipCoupon, 0)
+	for rows.Next() {
+		var redeemed int
+		item, err := scanMembershipCoupon(rows, &redeemed)
+		if err != nil {
+			return nil, 0, err
+		}
+		item.RedeemedCount = redeemed
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminCreateMembershipCoupon(item model.MembershipCoupon) (string, error) {
+	code := normalizeMembershipCouponCode(item.Code)
+	if code == "" {
+		code = strings.ToUpper(newID("CP"))
+	}
+	validFrom, err := parseOptionalRFC3339(item.ValidFrom)
+	if err != nil {
+		return "", fmt.Errorf("invalid valid_from: %w", err)
+	}
+	validTo, err := parseOptionalRFC3339(item.ValidTo)
+	if err != nil {
+		return "", fmt.Errorf("invalid valid_to: %w", err)
+	}
+	source := strings.ToUpper(strings.TrimSpace(item.Source))
+	if source == "" {
+		source = "CAMPAIGN"
+	}
+	status := strings.ToUpper(strings.TrimSpace(item.Status))
+	if status == "" {
+		status = "ACTIVE"
+	}
+	id := newID("cp")
+	now := time.Now()
+	_, err = r.db.Exec(`
+INSERT INTO membership_coupons (
+	id, code, name, discount_type, discount_value, max_discount, min_amount, valid_from, valid_to,
+	total_limit, per_user_limit, product_ids, member_levels, first_purchase_only,
+	source, owner_user_id, invite_record_id, status, created_by, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, code, truncateByRunes(strings.TrimSpace(item.Name), 64),
+		strings.ToUpper(strings.TrimSpace(item.DiscountType)), item.DiscountValue, item.MaxDiscount, item.MinAmount,
+		validFrom, validTo, item.TotalLimit, item.PerUserLimit,
+		joinMembershipCouponList(item.ProductIDs, false), joinMembershipCouponList(item.MemberLevels, true),
+		item.FirstPurchaseOnly, source, nullableString(item.OwnerUserID), nullableString(item.InviteRecordID),
+		status, item.CreatedBy, now, now,
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func parseOptionalRFC3339(raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (r *MySQLGrowthRepo) AdminUpdateMembershipCouponStatus(id string, status string) error {
+	result, err := r.db.Exec(
+		"UPDATE membership_coupons SET status = ?, updated_at = ? WHERE id = ?",
+		strings.ToUpper(strings.TrimSpace(status)),
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrMembershipCouponNotFound
+	}
+	return nil
+}
+
+func (r *MySQLGrowthRepo) AdminListMembershipCouponRedemptions(couponID string, status string, page int, pageSize int) ([]model.MembershipCouponRedemption, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 2)
+	args := []interface{}{}
+	if strings.TrimSpace(couponID) != "" {
+		filters = append(filters, "coupon_id = ?")
+		args = append(args, strings.TrimSpace(couponID))
+	}
+	if strings.TrimSpace(status) != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(status)))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM membership_coupon_redemptions"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, coupon_id, coupon_code, user_id, order_id, order_no, product_id,
+	original_amount, discount_amount, amount, status, created_at, redeemed_at
+FROM membership_coupon_redemptions`+where+`
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.MembershipCouponRedemption, 0)
+	for rows.Next() {
+		var item model.MembershipCouponRedemption
+		var createdAt time.Time
+		var redeemedAt sql.NullTime
+		if err := rows.Scan(
+			&item.ID, &item.CouponID, &item.CouponCode, &item.UserID, &item.OrderID, &item.OrderNo, &item.ProductID,
+			&item.OriginalAmount, &item.DiscountAmount, &item.Amount, &item.Status, &createdAt, &redeemedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		if redeemedAt.Valid {
+			item.RedeemedAt = redeemedAt.Time.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// ListUserMembershipCoupons returns the active coupons issued to one user,
+// such as the invitee coupon granted at registration.
+func (r *MySQLGrowthRepo) ListUserMembershipCoupons(userID string) ([]model.MembershipCoupon, error) {
+	rows, err := r.db.Query(`
+SELECT `+membershipCouponColumns+`
+FROM membership_coupons
+WHERE owner_user_id = ? AND status = 'ACTIVE' AND (valid_to IS NULL OR valid_to > ?)
+  AND NOT EXISTS (
+	SELECT 1 FROM membership_coupon_redemptions cr
+	WHERE cr.coupon_id = membership_coupons.id AND cr.user_id = ? AND cr.status IN ('RESERVED', 'REDEEMED')
+  )
+ORDER BY created_at DESC
+LIMIT 50`, userID, time.Now(), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]model.MembershipCoupon, 0)
+	for rows.Next() {
+		item, err := scanMembershipCoupon(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// quoteMembershipCouponLocked mirrors quoteMembershipCoupon for the demo
+// product (mp_demo_001, 99, VIP1). The caller holds r.mu.
+func (r *InMemoryGrowthRepo) quoteMembershipCouponLocked(userID string, productID string, code string) (model.MembershipCoupon, model.MembershipCouponQuote, error) {
+	const price, memberLevel = 99.0, "VIP1"
+	coupon, ok := r.membershipCoupons[normalizeMembershipCouponCode(code)]
+	if !ok {
+		return coupon, model.MembershipCouponQuote{}, model.ErrMembershipCouponNotFound
+	}
+	if err := checkMembershipCoupon(coupon, userID, productID, memberLevel, price, time.Now()); err != nil {
+		return coupon, model.MembershipCouponQuote{}, err
+	}
+	totalUsed, userUsed := 0, 0
+	for _, item := range r.couponRedemptions {
+		if item.CouponID != coupon.ID || (item.Status != "RESERVED" && item.Status != "REDEEMED") {
+			continue
+		}
+		totalUsed++
+		if item.UserID == userID {
+			userUsed++
+		}
+	}
+	if coupon.TotalLimit > 0 && totalUsed >= coupon.TotalLimit {
+		return coupon, model.MembershipCouponQuote{}, fmt.Errorf("%w: coupon has been fully redeemed", model.ErrMembershipCouponNotApplicable)
+	}
+	if coupon.PerUserLimit > 0 && userUsed >= coupon.PerUserLimit {
+		return coupon, model.MembershipCouponQuote{}, fmt.Errorf("%w: coupon already used", model.ErrMembershipCouponNotApplicable)
+	}
+	if coupon.FirstPurchaseOnly {
+		for _, order := range r.membershipOrders {
+			if order.UserID == userID && (order.Status == "PAID" || order.Status == "REFUNDED") {
+				return coupon, model.MembershipCouponQuote{}, fmt.Errorf("%w: coupon is for first purchase only", model.ErrMembershipCouponNotApplicable)
+			}
+		}
+	}
+	discount := membershipCouponDiscount(coupon, price)
+	return coupon, model.MembershipCouponQuote{
+		CouponCode:     coupon.Code,
+		ProductID:      productID,
+		OriginalAmount: price,
+		DiscountAmount: discount,
+		Amount:         math.Round((price-discount)*100) / 100,
+	}, nil
+}
+
+func (r *InMemoryGrowthRepo) setCouponRedemptionStatusLocked(orderID string, status string, redeemedAt string) {
+	for i := range r.couponRedemptions {
+		if r.couponRedemptions[i].OrderID == orderID {
+			r.couponRedemptions[i].Status = status
+			r.couponRedemptions[i].RedeemedAt = redeemedAt
+		}
+	}
+}
+
+func (r *InMemoryGrowthRepo) QuoteMembershipCoupon(userID string, productID string, code string) (model.MembershipCouponQuote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, quote, err := r.quoteMembershipCouponLocked(userID, productID, code)
+	return quote, err
+}
+
+func (r *InMemoryGrowthRepo) ListUserMembershipCoupons(userID string) ([]model.MembershipCoupon, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.MembershipCoupon, 0)
+	for _, coupon := range r.membershipCoupons {
+		if coupon.OwnerUserID == userID && coupon.Status == "ACTIVE" {
+			items = append(items, coupon)
+		}
+	}
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListMembershipCoupons(status string, source string, page int, pageSize int) ([]model.MembershipCoupon, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.MembershipCoupon, 0, len(r.membershipCoupons))
+	for _, coupon := range r.membershipCoupons {
+		if (status == "" || strings.EqualFold(coupon.Status, status)) && (source == "" || strings.EqualFold(coupon.Source, source)) {
+			coupon.RedeemedCount = 0
+			for _, item := range r.couponRedemptions {
+				if item.CouponID == coupon.ID && item.Status == "REDEEMED" {
+					coupon.RedeemedCount++
+				}
+			}
+			items = append(items, coupon)
+		}
+	}
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.MembershipCoupon{}, total, nil
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCreateMembershipCoupon(item model.MembershipCoupon) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item.Code = normalizeMembershipCouponCode(item.Code)
+	if item.Code == "" {
+		item.Code = strings.ToUpper(newID("CP"))
+	}
+	if _, exists := r.membershipCoupons[item.Code]; exists {
+		return "", fmt.Errorf("Error 1062: Duplicate entry '%s' for key 'uk_membership_coupons_code'", item.Code)
+	}
+	item.ID = newID("cp")
+	if item.Source == "" {
+		item.Source = "CAMPAIGN"
+	}
+	if item.Status == "" {
+		item.Status = "ACTIVE"
+	}
+	item.CreatedAt = time.Now().Format(time.RFC3339)
+	item.UpdatedAt = item.CreatedAt
+	r.membershipCoupons[item.Code] = item
+	return item.ID, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminUpdateMembershipCouponStatus(id string, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for code, coupon := range r.membershipCoupons {
+		if coupon.ID == id {
+			coupon.Status = strings.ToUpper(strings.TrimSpace(status))
+			coupon.UpdatedAt = time.Now().Format(time.RFC3339)
+			r.membershipCoupons[code] = coupon
+			return nil
+		}
+	}
+	return model.ErrMembershipCouponNotFound
+}
+
+func (r *InMemoryGrowthRepo) AdminListMembershipCouponRedemptions(couponID string, status string, page int, pageSize int) ([]model.MembershipCouponRedemption, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.MembershipCouponRedemption, 0)
+	for i := len(r.couponRedemptions) - 1; i >= 0; i-- {
+		item := r.couponRedemptions[i]
+		if (couponID == "" || item.CouponID == couponID) && (status == "" || strings.EqualFold(item.Status, status)) {
+			items = append(items, item)
+		}
+	}
+	total := len(items)
+	start, end := paginateBounds(page, pageSize, total)
+	if start >= total {
+		return []model.MembershipCouponRedemption{}, total, nil
+	}
+	return items[start:end], total, nil
+}
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestMembershipCouponDiscount(t *testing.T) {
+	cases := []struct {
+		name   string
+		coupon model.MembershipCoupon
+		price  float64
+		want   float64
+	}{
+		{name: "percent", coupon: model.MembershipCoupon{DiscountType: "PERCENT", DiscountValue: 20}, price: 99, want: 19.8},
+		{name: "percent capped", coupon: model.MembershipCoupon{DiscountType: "PERCENT", DiscountValue: 50, MaxDiscount: 30}, price: 99, want: 30},
+		{name: "fixed", coupon: model.MembershipCoupon{DiscountType: "FIXED", DiscountValue: 10}, price: 99, want: 10},
+		{name: "fixed above price keeps one cent", coupon: model.MembershipCoupon{DiscountType: "FIXED", DiscountValue: 200}, price: 99, want: 98.99},
+	}
+	for _, tc := range cases {
+		if got := membershipCouponDiscount(tc.coupon, tc.price); got != tc.want {
+			t.Fatalf("%s: discount = %.2f, want %.2f", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCheckMembershipCouponRejectsOutOfScope(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	base := model.MembershipCoupon{Status: "ACTIVE", DiscountType: "FIXED", DiscountValue: 10}
+
+	expired := base
+	expired.ValidTo = now.Add(-time.Hour).Format(time.RFC3339)
+	owned := base
+	owned.OwnerUserID = "u_other"
+	scoped := base
+	scoped.MemberLevels = []string{"VIP2"}
+	template := base
+	template.Status = "TEMPLATE"
+
+	for name, coupon := range map[string]model.MembershipCoupon{"expired": expired, "owned": owned, "scoped": scoped, "template": template} {
+		err := checkMembershipCoupon(coupon, "u_1", "mp_1", "VIP1", 99, now)
+		if !errors.Is(err, model.ErrMembershipCouponNotApplicable) {
+			t.Fatalf("%s: expected not applicable, got %v", name, err)
+		}
+	}
+	if err := checkMembershipCoupon(base, "u_1", "mp_1", "VIP1", 99, now); err != nil {
+		t.Fatalf("unrestricted coupon rejected: %v", err)
+	}
+}
+
+func membershipCouponRow(totalLimit int, firstPurchaseOnly bool) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "code", "name", "discount_type", "discount_value", "max_discount", "min_amount",
+		"valid_from", "valid_to", "total_limit", "per_user_limit", "product_ids", "member_levels", "first_purchase_only",
+		"source", "owner_user_id", "invite_record_id", "status", "created_by", "created_at", "updated_at",
+	}).AddRow(
+		"cp_1", "SPRING20", "春季八折", "PERCENT", 20.0, 0.0, 0.0,
+		now.Add(-time.Hour), now.Add(time.Hour), totalLimit, 1, "", "VIP1", firstPurchaseOnly,
+		"CAMPAIGN", nil, nil, "ACTIVE", "admin_1", now, now,
+	)
+}
+
+func TestMySQLCreateMembershipOrderReservesCoupon(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT price, status, member_level FROM membership_products WHERE id = ?")).
+		WithArgs("mp_1").
+		WillReturnRows(sqlmock.NewRows([]string{"price", "status", "member_level"}).AddRow(99.0, "ACTIVE", "VIP1"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_coupons WHERE code = ? FOR UPDATE")).
+		WithArgs("SPRING20").
+		WillReturnRows(membershipCouponRow(100, true))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_coupon_redemptions")).
+		WithArgs("u_1", "cp_1").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "user_total"}).AddRow(3, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM membership_orders WHERE user_id = ? AND status IN ('PAID', 'REFUNDED')")).
+		WithArgs("u_1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_orders")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "u_1", "mp_1", 79.2, 99.0, 19.8, "SPRING20", "YOLKPAY", "USER", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_coupon_redemptions")).
+		WithArgs(sqlmock.AnyArg(), "cp_1", "SPRING20", "u_1", sqlmock.AnyArg(), sqlmock.AnyArg(), "mp_1", 99.0, 19.8, 79.2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	order, err := repo.CreateMembershipOrder("u_1", "mp_1", "yolkpay", " spring20 ")
+	if err != nil {
+		t.Fatalf("CreateMembershipOrder() error = %v", err)
+	}
+	if order.Amount != 79.2 || order.OriginalAmount != 99 || order.DiscountAmount != 19.8 || order.CouponCode != "SPRING20" {
+		t.Fatalf("unexpected order pricing: %+v", order)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLCreateMembershipOrderRejectsExhaustedCoupon(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT price, status, member_level FROM membership_products WHERE id = ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"price", "status", "member_level"}).AddRow(99.0, "ACTIVE", "VIP1"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_coupons WHERE code = ? FOR UPDATE")).
+		WillReturnRows(membershipCouponRow(3, false))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_coupon_redemptions")).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "user_total"}).AddRow(3, 0))
+	mock.ExpectRollback()
+
+	repo := &MySQLGrowthRepo{db: db}
+	_, err = repo.CreateMembershipOrder("u_1", "mp_1", "YOLKPAY", "SPRING20")
+	if !errors.Is(err, model.ErrMembershipCouponNotApplicable) {
+		t.Fatalf("expected not applicable, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
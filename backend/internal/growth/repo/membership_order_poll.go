@@ -0,0 +1,242 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+type membershipOrderPollConfig struct {
+	AfterMinutes int
+	TTLMinutes   int
+	BatchSize    int
+}
+
+type pendingMembershipOrder struct {
+	ID         string
+	OrderNo    string
+	PayChannel string
+	Amount     float64
+	CreatedAt  time.Time
+}
+
+func (r *MySQLGrowthRepo) resolveMembershipOrderPollConfig() membershipOrderPollConfig {
+	cfg := membershipOrderPollConfig{AfterMinutes: 5, TTLMinutes: 30, BatchSize: 100}
+	items, _, err := r.AdminListSystemConfigs("payment.order_poll.", 1, 50)
+	if err != nil {
+		return cfg
+	}
+	for _, item := range items {
+		value := strings.TrimSpace(item.ConfigValue)
+		switch strings.ToLower(strings.TrimSpace(item.ConfigKey)) {
+		case "payment.order_poll.after_minutes":
+			cfg.AfterMinutes = parseRepoConfigInt(value, cfg.AfterMinutes)
+		case "payment.order_poll.ttl_minutes":
+			cfg.TTLMinutes = parseRepoConfigInt(value, cfg.TTLMinutes)
+		case "payment.order_poll.batch_size":
+			cfg.BatchSize = parseRepoConfigInt(value, cfg.BatchSize)
+		}
+	}
+	if cfg.AfterMinutes < 1 {
+		cfg.AfterMinutes = 1
+	}
+	if cfg.TTLMinutes < cfg.AfterMinutes {
+		cfg.TTLMinutes = cfg.AfterMinutes
+	}
+	if cfg.BatchSize <= 0 || cfg.BatchSize > 500 {
+		cfg.BatchSize = 100
+	}
+	return cfg
+}
+
+// AdminPollPendingMembershipOrders asks the pay channel about every order
+// that has been PENDING for payment.order_poll.after_minutes, so a paid order
+// whose callback was lost still grants VIP. Paid trades go through
+// HandlePaymentCallback with the channel's own idempotency key, so the real
+// callback arriving later is a harmless duplicate. Orders still unpaid after
+// payment.order_poll.ttl_minutes are CANCELED, but only when the channel itself
+// reports the trade unpaid: offline channels are settled by staff and a failed
+// query says nothing about whether the buyer paid. Every attempt is logged to
+// payment_order_poll_logs.
+func (r *MySQLGrowthRepo) AdminPollPendingMembershipOrders(channels payment.Resolver) (string, error) {
+	cfg := r.resolveMembershipOrderPollConfig()
+	now := time.Now()
+	rows, err := r.db.Query(`
+SELECT id, order_no, pay_channel, amount, created_at
+FROM membership_orders
+WHERE status = 'PENDING' AND created_at <= ?
+ORDER BY created_at ASC
+LIMIT ?`, now.Add(-time.Duration(cfg.AfterMinutes)*time.Minute), cfg.BatchSize)
+	if err != nil {
+		return "", err
+	}
+	orders := make([]pendingMembershipOrder, 0)
+	for rows.Next() {
+		var item pendingMembershipOrder
+		if err := rows.Scan(&item.ID, &item.OrderNo, &item.PayChannel, &item.Amount, &item.CreatedAt); err != nil {
+			rows.Close()
+			return "", err
+		}
+		orders = append(orders, item)
+	}
+	if err := rows.Close(); err != nil {
+		return "", err
+	}
+
+	expireBefore := now.Add(-time.Duration(cfg.TTLMinutes) * time.Minute)
+	counts := map[string]int{}
+	for _, order := range orders {
+		attempt, err := r.pollMembershipOrder(order, channels, order.CreatedAt.Before(expireBefore))
+		if err != nil {
+			return "", err
+		}
+		counts[attempt.Result]++
+	}
+	return fmt.Sprintf(
+		"polled=%d paid=%d canceled=%d pending=%d errors=%d",
+		len(orders),
+		counts["PAID"],
+		counts["CANCELED"],
+		counts["PENDING"]+counts["FAILED"]+counts["UNSUPPORTED"],
+		counts["ERROR"]+counts["NOT_FOUND"]+counts["MISMATCH"],
+	), nil
+}
+
+// pollMembershipOrder queries one order and logs the attempt. Channel errors
+// are recorded rather than returned so one broken gateway does not stall the
+// batch; only database errors abort. A paid trade whose amount or currency
+// differs from the order is logged as MISMATCH and left for staff.
+func (r *MySQLGrowthRepo) pollMembershipOrder(order pendingMembershipOrder, channels payment.Resolver, expired bool) (model.PaymentPollLog, error) {
+	attempt := model.PaymentPollLog{
+		ID:         newID("ppl"),
+		OrderID:    order.ID,
+		OrderNo:    order.OrderNo,
+		PayChannel: strings.ToUpper(strings.TrimSpace(order.PayChannel)),
+	}
+	channel, err := channels.Channel(attempt.PayChannel)
+	switch {
+	case errors.Is(err, payment.ErrUnsupported):
+		attempt.Result = "UNSUPPORTED"
+	case err != nil:
+		attempt.Result = "ERROR"
+		attempt.Message = err.Error()
+	default:
+		trade, queryErr := channel.QueryOrder(order.OrderNo)
+		switch {
+		case errors.Is(queryErr, payment.ErrTradeNotFound):
+			attempt.Result = "NOT_FOUND"
+			attempt.Message = queryErr.Error()
+		case queryErr != nil:
+			attempt.Result = "ERROR"
+			attempt.Message = queryErr.Error()
+		default:
+			attempt.TradeStatus = trade.Status
+			attempt.ChannelTxnNo = trade.ChannelTxnNo
+			attempt.Result = "PENDING"
+			if trade.Status == payment.TradeStatusFailed {
+				attempt.Result = "FAILED"
+			}
+			if trade.Status == payment.TradeStatusPaid {
+				if err := trade.Matches(order.Amount, payment.CurrencyCNY); err != nil {
+					attempt.Result = "MISMATCH"
+					attempt.Message = err.Error()
+					break
+				}
+				idempotencyKey := strings.TrimSpace(trade.IdempotencyKey)
+				if idempotencyKey == "" {
+					idempotencyKey = strings.ToLower(channel.Name()) + ":" + trade.ChannelTxnNo
+				}
+				settleErr := r.HandlePaymentCallback(channel.Name(), order.OrderNo, trade.ChannelTxnNo, idempotencyKey, "", true)
+				if settleErr != nil && !strings.Contains(strings.ToLower(settleErr.Error()), "duplicate") {
+					attempt.Result = "ERROR"
+					attempt.Message = settleErr.Error()
+				} else {
+					attempt.Result = "PAID"
+				}
+			}
+		}
+	}
+
+	if expired && (attempt.Result == "PENDING" || attempt.Result == "FAILED") {
+		result, err := r.db.Exec(
+			"UPDATE membership_orders SET status = 'CANCELED', updated_at = ? WHERE id = ? AND status = 'PENDING'",
+			time.Now(),
+			order.ID,
+		)
+		if err != nil {
+			return attempt, err
+		}
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			if err := releaseMembershipCoupon(r.db, order.ID, time.Now()); err != nil {
+				return attempt, err
+			}
+			if attempt.Message == "" {
+				attempt.Message = fmt.Sprintf("unpaid after %s, closed", time.Since(order.CreatedAt).Truncate(time.Minute))
+			}
+			attempt.Result = "CANCELED"
+		}
+	}
+
+	_, err = r.db.Exec(`
+INSERT INTO payment_order_poll_logs
+(id, order_id, order_no, pay_channel, result, trade_status, channel_txn_no, message, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		attempt.ID, attempt.OrderID, attempt.OrderNo, attempt.PayChannel, attempt.Result,
+		nullableString(attempt.TradeStatus), nullableString(attempt.ChannelTxnNo),
+		nullableString(truncateByRunes(attempt.Message, 255)), time.Now(),
+	)
+	return attempt, err
+}
+
+func (r *MySQLGrowthRepo) AdminListPaymentPollLogs(orderNo string, result string, page int, pageSize int) ([]model.PaymentPollLog, int, error) {
+	offset := (page - 1) * pageSize
+	filters := make([]string, 0, 2)
+	args := []interface{}{}
+	if strings.TrimSpace(orderNo) != "" {
+		filters = append(filters, "order_no = ?")
+		args = append(args, strings.TrimSpace(orderNo))
+	}
+	if strings.TrimSpace(result) != "" {
+		filters = append(filters, "result = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(result)))
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM payment_order_poll_logs"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, order_id, order_no, pay_channel, result, trade_status, channel_txn_no, message, created_at
+FROM payment_order_poll_logs`+where+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.PaymentPollLog, 0)
+	for rows.Next() {
+		var item model.PaymentPollLog
+		var tradeStatus, channelTxnNo, message sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.OrderID, &item.OrderNo, &item.PayChannel, &item.Result, &tradeStatus, &channelTxnNo, &message, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		item.TradeStatus = tradeStatus.String
+		item.ChannelTxnNo = channelTxnNo.String
+		item.Message = message.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/platform/payment"
+)
+
+func expectDefaultMembershipOrderPollConfig(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM system_configs")).
+		WillReturnError(errors.New("system_configs unavailable"))
+}
+
+func TestMySQLAdminPollPendingMembershipOrdersSettlesPaidTrade(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	stub := &payment.StubChannel{Trades: map[string]payment.Trade{
+		"MO100": {OrderNo: "MO100", ChannelTxnNo: "T100", Status: payment.TradeStatusPaid, Amount: 99, IdempotencyKey: "yolkpay:T100"},
+	}}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return stub, nil })
+
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_orders\nWHERE status = 'PENDING' AND created_at <= ?")).
+		WithArgs(sqlmock.AnyArg(), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_no", "pay_channel", "amount", "created_at"}).
+			AddRow("mo_100", "MO100", "YOLKPAY", 99.0, time.Now().Add(-10*time.Minute)))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_callback_logs")).
+		WithArgs(sqlmock.AnyArg(), "STUB", "MO100", "T100", 1, "yolkpay:T100", sqlmock.AnyArg()).
+		WillReturnError(errors.New("Error 1062: Duplicate entry 'yolkpay:T100' for key 'uk_idempotency_key'"))
+	mock.ExpectRollback()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_order_poll_logs")).
+		WithArgs(sqlmock.AnyArg(), "mo_100", "MO100", "YOLKPAY", "PAID", payment.TradeStatusPaid, "T100", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminPollPendingMembershipOrders(channels)
+	if err != nil {
+		t.Fatalf("AdminPollPendingMembershipOrders() error = %v", err)
+	}
+	if summary != "polled=1 paid=1 canceled=0 pending=0 errors=0" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminPollPendingMembershipOrdersOnlyCancelsWhatTheGatewayReportsUnpaid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	stub := &payment.StubChannel{Trades: map[string]payment.Trade{
+		"MO200": {OrderNo: "MO200", ChannelTxnNo: "T200", Status: payment.TradeStatusPending},
+	}}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) {
+		if name == "YOLKPAY" {
+			return stub, nil
+		}
+		return nil, payment.ErrUnsupported
+	})
+
+	// All three are past the TTL: the gateway reports MO200 unpaid, ALIPAY is
+	// settled offline by staff, and the gateway has no answer for MO202.
+	expired := time.Now().Add(-45 * time.Minute)
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_orders\nWHERE status = 'PENDING' AND created_at <= ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_no", "pay_channel", "amount", "created_at"}).
+			AddRow("mo_200", "MO200", "YOLKPAY", 99.0, expired).
+			AddRow("mo_201", "MO201", "ALIPAY", 99.0, expired).
+			AddRow("mo_202", "MO202", "YOLKPAY", 99.0, expired))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_orders SET status = 'CANCELED'")).
+		WithArgs(sqlmock.AnyArg(), "mo_200").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_coupon_redemptions SET status = 'RELEASED'")).
+		WithArgs(sqlmock.AnyArg(), "mo_200").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_order_poll_logs")).
+		WithArgs(sqlmock.AnyArg(), "mo_200", "MO200", "YOLKPAY", "CANCELED", payment.TradeStatusPending, "T200", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_order_poll_logs")).
+		WithArgs(sqlmock.AnyArg(), "mo_201", "MO201", "ALIPAY", "UNSUPPORTED", nil, nil, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_order_poll_logs")).
+		WithArgs(sqlmock.AnyArg(), "mo_202", "MO202", "YOLKPAY", "NOT_FOUND", nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminPollPendingMembershipOrders(channels)
+	if err != nil {
+		t.Fatalf("AdminPollPendingMembershipOrders() error = %v", err)
+	}
+	if summary != "polled=3 paid=0 canceled=1 pending=1 errors=1" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminPollPendingMembershipOrdersRejectsUnderpaidTrade(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	stub := &payment.StubChannel{Trades: map[string]payment.Trade{
+		"MO300": {OrderNo: "MO300", ChannelTxnNo: "T300", Status: payment.TradeStatusPaid, Amount: 0.01},
+		"MO301": {OrderNo: "MO301", ChannelTxnNo: "T301", Status: payment.TradeStatusPaid, Amount: 99, Currency: "USD"},
+	}}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return stub, nil })
+
+	expectDefaultMembershipOrderPollConfig(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_orders\nWHERE status = 'PENDING' AND created_at <= ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_no", "pay_channel", "amount", "created_at"}).
+			AddRow("mo_300", "MO300", "YOLKPAY", 99.0, time.Now().Add(-45*time.Minute)).
+			AddRow("mo_301", "MO301", "YOLKPAY", 99.0, time.Now().Add(-10*time.Minute)))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_order_poll_logs")).
+		WithArgs(sqlmock.AnyArg(), "mo_300", "MO300", "YOLKPAY", "MISMATCH", payment.TradeStatusPaid, "T300", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO payment_order_poll_logs")).
+		WithArgs(sqlmock.AnyArg(), "mo_301", "MO301", "YOLKPAY", "MISMATCH", payment.TradeStatusPaid, "T301", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminPollPendingMembershipOrders(channels)
+	if err != nil {
+		t.Fatalf("AdminPollPendingMembershipOrders() error = %v", err)
+	}
+	if summary != "polled=2 paid=0 canceled=0 pending=0 errors=2" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
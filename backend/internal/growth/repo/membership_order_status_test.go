@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func expectMembershipOrderForStatusUpdate(mock sqlmock.Sqlmock, status string) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT order_no, user_id, product_id, COALESCE(pay_channel, ''), amount, status FROM membership_orders WHERE id = ? FOR UPDATE")).
+		WithArgs("mo_300").
+		WillReturnRows(sqlmock.NewRows([]string{"order_no", "user_id", "product_id", "pay_channel", "amount", "status"}).
+			AddRow("MO300", "u_300", "mp_001", "alipay", 99.0, status))
+}
+
+func TestMySQLAdminUpdateMembershipOrderStatusSettlesManualPaymentLikeACallback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	expectMembershipOrderForStatusUpdate(mock, "PENDING")
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'PAID', paid_at = ?, pay_channel = ?, updated_at = ?")).
+		WithArgs(sqlmock.AnyArg(), "ALIPAY", sqlmock.AnyArg(), "mo_300").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT member_level, duration_days FROM membership_products WHERE id = ?")).
+		WithArgs("mp_001").
+		WillReturnRows(sqlmock.NewRows([]string{"member_level", "duration_days"}).AddRow("VIP1", 30))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT member_level, vip_expire_at FROM users WHERE id = ? FOR UPDATE")).
+		WithArgs("u_300").
+		WillReturnRows(sqlmock.NewRows([]string{"member_level", "vip_expire_at"}).AddRow("FREE", nil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users\nSET member_level = ?")).
+		WithArgs("VIP1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "u_300").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_orders SET vip_period_start = ?, vip_period_end = ? WHERE id = ?")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "mo_300").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM experiment_order_attributions")).
+		WithArgs("MO300").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM invite_records\nWHERE invitee_user_id = ?")).
+		WithArgs("u_300").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_coupon_redemptions SET status = 'REDEEMED'")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "mo_300").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	if err := repo.AdminUpdateMembershipOrderStatus("mo_300", "PAID"); err != nil {
+		t.Fatalf("AdminUpdateMembershipOrderStatus() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminUpdateMembershipOrderStatusRefusesToCancelPaidOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &MySQLGrowthRepo{db: db}
+
+	for _, status := range []string{"CANCELED", "failed"} {
+		expectMembershipOrderForStatusUpdate(mock, "PAID")
+		mock.ExpectRollback()
+		if err := repo.AdminUpdateMembershipOrderStatus("mo_300", status); !errors.Is(err, model.ErrMembershipOrderPaid) {
+			t.Fatalf("%s: expected ErrMembershipOrderPaid, got %v", status, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,403 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/payment"
+)
+
+// proratedMembershipRefund returns the refundable part of an order whose VIP
+// time covers [start, end). Consumed time is kept; a segment that has not
+// started yet, because it is stacked behind earlier orders, is refunded in
+// full. Orders without a VIP segment are refunded in full and take back no time.
+func proratedMembershipRefund(orderAmount float64, start time.Time, end time.Time, now time.Time) (float64, time.Duration) {
+	total := end.Sub(start)
+	if start.IsZero() || total <= 0 {
+		return math.Round(orderAmount*100) / 100, 0
+	}
+	var unused time.Duration
+	switch {
+	case !now.After(start):
+		unused = total
+	case !now.Before(end):
+		unused = 0
+	default:
+		unused = end.Sub(now).Truncate(time.Second)
+	}
+	return math.Round(orderAmount*unused.Seconds()/total.Seconds()*100) / 100, unused
+}
+
+// AdminRefundMembershipOrder refunds the unused part of a paid order through
+// the channel it was paid with, then takes the VIP time back. The ledger row is
+// written before the gateway call, so a PROCESSING refund left behind by a
+// crash is resumed with the same refund number instead of paying out twice.
+// When the VIP time is already used up the prorated amount is zero; the
+// gateway is not called and the order is only closed as refunded.
+func (r *MySQLGrowthRepo) AdminRefundMembershipOrder(orderID string, reason string, operator string, channels payment.Resolver) (model.MembershipRefund, error) {
+	item, periodEnd, channel, err := r.startMembershipRefund(orderID, reason, operator, channels)
+	if err != nil {
+		return model.MembershipRefund{}, err
+	}
+
+	if item.ChannelRefundNo == "" && item.Amount > 0 {
+		var channelTxnNo sql.NullString
+		if err := r.db.QueryRow(
+			"SELECT channel_txn_no FROM payment_callback_logs WHERE order_no = ? ORDER BY created_at DESC LIMIT 1",
+			item.OrderNo,
+		).Scan(&channelTxnNo); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return model.MembershipRefund{}, err
+		}
+		result, refundErr := channel.Refund(payment.RefundRequest{
+			OrderNo:      item.OrderNo,
+			ChannelTxnNo: channelTxnNo.String,
+			RefundNo:     item.ID,
+			OrderAmount:  item.OrderAmount,
+			Amount:       item.Amount,
+			Reason:       item.Reason,
+		})
+		if refundErr != nil {
+			if _, err := r.db.Exec(
+				"UPDATE membership_refunds SET status = 'FAILED', failure_reason = ?, updated_at = ? WHERE id = ?",
+				truncateByRunes(refundErr.Error(), 255),
+				time.Now(),
+				item.ID,
+			); err != nil {
+				return model.MembershipRefund{}, err
+			}
+			return model.MembershipRefund{}, fmt.Errorf("%w: %v", payment.ErrRefundFailed, refundErr)
+		}
+		item.ChannelRefundNo = strings.TrimSpace(result.ChannelRefundNo)
+		if item.ChannelRefundNo == "" {
+			item.ChannelRefundNo = item.ID
+		}
+		if _, err := r.db.Exec(
+			"UPDATE membership_refunds SET channel_refund_no = ?, updated_at = ? WHERE id = ?",
+			item.ChannelRefundNo,
+			time.Now(),
+			item.ID,
+		); err != nil {
+			return model.MembershipRefund{}, err
+		}
+	}
+
+	if err := r.completeMembershipRefund(&item, periodEnd); err != nil {
+		return model.MembershipRefund{}, err
+	}
+	return item, nil
+}
+
+func (r *MySQLGrowthRepo) startMembershipRefund(orderID string, reason string, operator string, channels payment.Resolver) (model.MembershipRefund, time.Time, payment.Channel, error) {
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.MembershipRefund{}, time.Time{}, nil, err
+	}
+	defer tx.Rollback()
+
+	var orderNo, userID, productID, status string
+	var payChannel sql.NullString
+	var orderAmount float64
+	var paidAt, periodStart, periodEnd sql.NullTime
+	err = tx.QueryRow(`
+SELECT order_no, user_id, product_id, amount, status, pay_channel, paid_at, vip_period_start, vip_period_end
+FROM membership_orders
+WHERE id = ?
+FOR UPDATE`, orderID).Scan(&orderNo, &userID, &productID, &orderAmount, &status, &payChannel, &paidAt, &periodStart, &periodEnd)
+	if err != nil {
+		return model.MembershipRefund{}, time.Time{}, nil, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(status), "PAID") {
+		return model.MembershipRefund{}, time.Time{}, nil, model.ErrMembershipOrderNotRefundable
+	}
+	start, end, err := membershipOrderVIPPeriodTx(tx, productID, paidAt, periodStart, periodEnd)
+	if err != nil {
+		return model.MembershipRefund{}, time.Time{}, nil, err
+	}
+
+	existing, found, err := loadProcessingMembershipRefundTx(tx, orderID)
+	if err != nil {
+		return model.MembershipRefund{}, time.Time{}, nil, err
+	}
+	channelName := strings.ToUpper(strings.TrimSpace(payChannel.String))
+	if found {
+		channelName = existing.RefundChannel
+	}
+	channel, err := channels.Channel(channelName)
+	if err != nil {
+		return model.MembershipRefund{}, time.Time{}, nil, err
+	}
+	if found {
+		return existing, end, channel, tx.Commit()
+	}
+
+	amount, unused := proratedMembershipRefund(orderAmount, start, end, now)
+	item := model.MembershipRefund{
+		ID:            newID("mrf"),
+		OrderID:       orderID,
+		OrderNo:       orderNo,
+		UserID:        userID,
+		PayChannel:    strings.ToUpper(strings.TrimSpace(payChannel.String)),
+		RefundChannel: channel.Name(),
+		OrderAmount:   orderAmount,
+		Amount:        amount,
+		UnusedSeconds: int64(unused / time.Second),
+		Status:        "PROCESSING",
+		Reason:        strings.TrimSpace(reason),
+		Operator:      strings.TrimSpace(operator),
+		CreatedAt:     now.Format(time.RFC3339),
+	}
+	if _, err := tx.Exec(`
+INSERT INTO membership_refunds
+(id, order_id, order_no, user_id, pay_channel, refund_channel, order_amount, amount, unused_seconds, status, reason, operator, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 'PROCESSING', ?, ?, ?, ?)`,
+		item.ID,
+		item.OrderID,
+		item.OrderNo,
+		item.UserID,
+		item.PayChannel,
+		item.RefundChannel,
+		item.OrderAmount,
+		item.Amount,
+		item.UnusedSeconds,
+		item.Reason,
+		item.Operator,
+		now,
+		now,
+	); err != nil {
+		return model.MembershipRefund{}, time.Time{}, nil, err
+	}
+	return item, end, channel, tx.Commit()
+}
+
+// membershipOrderVIPPeriodTx returns the VIP time an order bought. Orders paid
+// before vip_period_* existed fall back to paid_at plus the product duration.
+func membershipOrderVIPPeriodTx(tx *sql.Tx, productID string, paidAt sql.NullTime, periodStart sql.NullTime, periodEnd sql.NullTime) (time.Time, time.Time, error) {
+	if periodStart.Valid && periodEnd.Valid {
+		return periodStart.Time, periodEnd.Time, nil
+	}
+	if !paidAt.Valid {
+		return time.Time{}, time.Time{}, nil
+	}
+	var memberLevel sql.NullString
+	var durationDays sql.NullInt64
+	err := tx.QueryRow("SELECT member_level, duration_days FROM membership_products WHERE id = ?", productID).Scan(&memberLevel, &durationDays)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if strings.TrimSpace(memberLevel.String) == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	days := int(durationDays.Int64)
+	if days <= 0 {
+		days = 30
+	}
+	return paidAt.Time, paidAt.Time.AddDate(0, 0, days), nil
+}
+
+func loadProcessingMembershipRefundTx(tx *sql.Tx, orderID string) (model.MembershipRefund, bool, error) {
+	var item model.MembershipRefund
+	var channelRefundNo sql.NullString
+	var createdAt time.Time
+	err := tx.QueryRow(`
+SELECT id, order_id, order_no, user_id, pay_channel, refund_channel, order_amount, amount, unused_seconds, status, channel_refund_no, reason, operator, created_at
+FROM membership_refunds
+WHERE order_id = ? AND status = 'PROCESSING'
+ORDER BY created_at DESC
+LIMIT 1`, orderID).Scan(
+		&item.ID, &item.OrderID, &item.OrderNo, &item.UserID, &item.PayChannel, &item.RefundChannel,
+		&item.OrderAmount, &item.Amount, &item.UnusedSeconds, &item.Status, &channelRefundNo,
+		&item.Reason, &item.Operator, &createdAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.MembershipRefund{}, false, nil
+	}
+	if err != nil {
+		return model.MembershipRefund{}, false, err
+	}
+	item.ChannelRefundNo = channelRefundNo.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	return item, true, nil
+}
+
+func (r *MySQLGrowthRepo) completeMembershipRefund(item *model.MembershipRefund, periodEnd time.Time) error {
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRow("SELECT status FROM membership_orders WHERE id = ? FOR UPDATE", item.OrderID).Scan(&status); err != nil {
+		return err
+	}
+	if !strings.EqualFold(strings.TrimSpace(status), "PAID") {
+		return model.ErrMembershipOrderNotRefundable
+	}
+	if _, err := tx.Exec("UPDATE membership_orders SET status = 'REFUNDED', updated_at = ? WHERE id = ?", now, item.OrderID); err != nil {
+		return err
+	}
+	unused := time.Duration(item.UnusedSeconds) * time.Second
+	expireAt, err := rollbackMembershipVIPTx(tx, item.UserID, item.OrderID, periodEnd, unused, now)
+	if err != nil {
+		return err
+	}
+	if _, err := reverseInviteCommissionsTx(tx, item.OrderID, now); err != nil {
+		return err
+	}
+	content := fmt.Sprintf("订单%s已退款%.2f元，会员权益已取消。", item.OrderNo, item.Amount)
+	if item.Amount <= 0 {
+		content = fmt.Sprintf("订单%s的会员时长已用完，订单已关闭，无可退金额。", item.OrderNo)
+	} else if !expireAt.IsZero() {
+		content = fmt.Sprintf("订单%s已退款%.2f元，会员到期时间调整为%s。", item.OrderNo, item.Amount, expireAt.Format("2006-01-02 15:04:05"))
+	}
+	if err := insertSystemMessageTx(tx, item.UserID, "会员订单退款成功", content, now); err != nil {
+		return err
+	}
+	r.notifyUserMessage(item.UserID)
+	if _, err := tx.Exec(
+		"UPDATE membership_refunds SET status = 'SUCCESS', completed_at = ?, updated_at = ? WHERE id = ?",
+		now,
+		now,
+		item.ID,
+	); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	item.Status = "SUCCESS"
+	item.CompletedAt = now.Format(time.RFC3339)
+	return nil
+}
+
+// rollbackMembershipVIPTx takes the unused VIP time of a refunded order back
+// from the user. Orders stacked after it move earlier by the same amount, and
+// member_level falls back to the newest order still covering now, or FREE when
+// nothing is left. It returns the new expiry, zero when VIP ended.
+func rollbackMembershipVIPTx(tx *sql.Tx, userID string, orderID string, periodEnd time.Time, unused time.Duration, now time.Time) (time.Time, error) {
+	var memberLevel string
+	var expireAt sql.NullTime
+	if err := tx.QueryRow("SELECT member_level, vip_expire_at FROM users WHERE id = ? FOR UPDATE", userID).Scan(&memberLevel, &expireAt); err != nil {
+		return time.Time{}, err
+	}
+	if unused <= 0 || !expireAt.Valid {
+		if expireAt.Valid {
+			return expireAt.Time, nil
+		}
+		return time.Time{}, nil
+	}
+
+	shiftSeconds := int64(unused / time.Second)
+	if _, err := tx.Exec(`
+UPDATE membership_orders
+SET vip_period_start = DATE_SUB(vip_period_start, INTERVAL ? SECOND),
+    vip_period_end = DATE_SUB(vip_period_end, INTERVAL ? SECOND)
+WHERE user_id = ? AND status = 'PAID' AND id <> ? AND vip_period_start >= ?`,
+		shiftSeconds,
+		shiftSeconds,
+		userID,
+		orderID,
+		periodEnd,
+	); err != nil {
+		return time.Time{}, err
+	}
+
+	newExpireAt := expireAt.Time.Add(-unused)
+	if !newExpireAt.After(now) {
+		_, err := tx.Exec(`
+UPDATE users
+SET member_level = 'FREE',
+    vip_started_at = NULL,
+    vip_expire_at = NULL,
+    vip_remind_3d_at = NULL,
+    vip_remind_1d_at = NULL,
+    updated_at = ?
+WHERE id = ?`, now, userID)
+		return time.Time{}, err
+	}
+
+	nextLevel := strings.ToUpper(strings.TrimSpace(memberLevel))
+	var remainingLevel sql.NullString
+	err := tx.QueryRow(`
+SELECT p.member_level
+FROM membership_orders o
+JOIN membership_products p ON p.id = o.product_id
+WHERE o.user_id = ? AND o.status = 'PAID' AND o.id <> ? AND o.vip_period_end > ?
+ORDER BY o.paid_at DESC
+LIMIT 1`, userID, orderID, now).Scan(&remainingLevel)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, err
+	}
+	if level := strings.ToUpper(strings.TrimSpace(remainingLevel.String)); level != "" {
+		nextLevel = level
+	}
+	_, err = tx.Exec(`
+UPDATE users
+SET member_level = ?,
+    vip_expire_at = ?,
+    vip_remind_3d_at = NULL,
+    vip_remind_1d_at = NULL,
+    updated_at = ?
+WHERE id = ?`, nextLevel, newExpireAt, now, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return newExpireAt, nil
+}
+
+func (r *MySQLGrowthRepo) AdminListMembershipRefunds(status string, page int, pageSize int) ([]model.MembershipRefund, int, error) {
+	offset := (page - 1) * pageSize
+	args := []interface{}{}
+	filter := ""
+	if strings.TrimSpace(status) != "" {
+		filter = " WHERE status = ?"
+		args = append(args, strings.ToUpper(strings.TrimSpace(status)))
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM membership_refunds"+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Query(`
+SELECT id, order_id, order_no, user_id, pay_channel, refund_channel, order_amount, amount, unused_seconds, status, channel_refund_no, failure_reason, reason, operator, created_at, completed_at
+FROM membership_refunds`+filter+`
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.MembershipRefund, 0)
+	for rows.Next() {
+		var item model.MembershipRefund
+		var channelRefundNo, failureReason sql.NullString
+		var createdAt time.Time
+		var completedAt sql.NullTime
+		if err := rows.Scan(
+			&item.ID, &item.OrderID, &item.OrderNo, &item.UserID, &item.PayChannel, &item.RefundChannel,
+			&item.OrderAmount, &item.Amount, &item.UnusedSeconds, &item.Status, &channelRefundNo, &failureReason,
+			&item.Reason, &item.Operator, &createdAt, &completedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		item.ChannelRefundNo = channelRefundNo.String
+		item.FailureReason = failureReason.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		if completedAt.Valid {
+			item.CompletedAt = completedAt.Time.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
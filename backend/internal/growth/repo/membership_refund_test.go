@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/platform/payment"
+)
+
+func TestProratedMembershipRefund(t *testing.T) {
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, 0, -10)
+	end := now.AddDate(0, 0, 20)
+
+	amount, unused := proratedMembershipRefund(300, start, end, now)
+	if amount != 200 || unused != 20*24*time.Hour {
+		t.Fatalf("expected 2/3 refunded for a third-used order, got amount=%v unused=%v", amount, unused)
+	}
+	amount, unused = proratedMembershipRefund(300, now.AddDate(0, 0, 5), now.AddDate(0, 0, 35), now)
+	if amount != 300 || unused != 30*24*time.Hour {
+		t.Fatalf("expected stacked future segment refunded in full, got amount=%v unused=%v", amount, unused)
+	}
+	amount, unused = proratedMembershipRefund(300, start.AddDate(0, 0, -30), start, now)
+	if amount != 0 || unused != 0 {
+		t.Fatalf("expected consumed segment to refund nothing, got amount=%v unused=%v", amount, unused)
+	}
+	amount, unused = proratedMembershipRefund(49.9, time.Time{}, time.Time{}, now)
+	if amount != 49.9 || unused != 0 {
+		t.Fatalf("expected order without VIP segment refunded in full, got amount=%v unused=%v", amount, unused)
+	}
+}
+
+func TestMySQLAdminRefundMembershipOrderRollsBackStackedVIP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -10)
+	periodEnd := now.AddDate(0, 0, 20)
+	stub := &payment.StubChannel{}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) {
+		if name != "YOLKPAY" {
+			t.Fatalf("expected order pay channel YOLKPAY, got %q", name)
+		}
+		return stub, nil
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT order_no, user_id, product_id, amount, status, pay_channel, paid_at, vip_period_start, vip_period_end")).
+		WithArgs("mo_002").
+		WillReturnRows(sqlmock.NewRows([]string{"order_no", "user_id", "product_id", "amount", "status", "pay_channel", "paid_at", "vip_period_start", "vip_period_end"}).
+			AddRow("MO002", "u_001", "mp_vip2", 300.0, "PAID", "YOLKPAY", periodStart, periodStart, periodEnd))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_refunds")).
+		WithArgs("mo_002").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_refunds")).
+		WithArgs(sqlmock.AnyArg(), "mo_002", "MO002", "u_001", "YOLKPAY", "STUB", 300.0, 200.0, sqlmock.AnyArg(), "duplicate purchase", "admin_001", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT channel_txn_no FROM payment_callback_logs")).
+		WithArgs("MO002").
+		WillReturnRows(sqlmock.NewRows([]string{"channel_txn_no"}).AddRow("T20260320"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_refunds SET channel_refund_no = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM membership_orders WHERE id = ? FOR UPDATE")).
+		WithArgs("mo_002").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("PAID"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_orders SET status = 'REFUNDED'")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT member_level, vip_expire_at FROM users WHERE id = ? FOR UPDATE")).
+		WithArgs("u_001").
+		WillReturnRows(sqlmock.NewRows([]string{"member_level", "vip_expire_at"}).AddRow("VIP2", now.AddDate(0, 0, 50)))
+	mock.ExpectExec(regexp.QuoteMeta("SET vip_period_start = DATE_SUB(vip_period_start, INTERVAL ? SECOND)")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "u_001", "mo_002", periodEnd).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.member_level")).
+		WithArgs("u_001", "mo_002", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"member_level"}).AddRow("VIP1"))
+	mock.ExpectExec(regexp.QuoteMeta("SET member_level = ?,")).
+		WithArgs("VIP1", sqlmock.AnyArg(), sqlmock.AnyArg(), "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, inviter_user_id, reward_value, status")).
+		WithArgs("mo_002").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviter_user_id", "reward_value", "status"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, invitee_user_id FROM invite_records WHERE first_pay_order_id = ?")).
+		WithArgs("mo_002").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invitee_user_id"}))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_refunds SET status = 'SUCCESS'")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	item, err := repo.AdminRefundMembershipOrder("mo_002", "duplicate purchase", "admin_001", channels)
+	if err != nil {
+		t.Fatalf("AdminRefundMembershipOrder() error = %v", err)
+	}
+	if item.Status != "SUCCESS" || item.Amount != 200 {
+		t.Fatalf("unexpected refund: %+v", item)
+	}
+	if len(stub.Requests) != 1 || stub.Requests[0].Amount != 200 || stub.Requests[0].ChannelTxnNo != "T20260320" || stub.Requests[0].RefundNo != item.ID {
+		t.Fatalf("unexpected channel refund requests: %+v", stub.Requests)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminRefundMembershipOrderKeepsOrderPaidWhenChannelFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	stub := &payment.StubChannel{Err: errors.New("insufficient merchant balance")}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return stub, nil })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT order_no, user_id, product_id, amount, status, pay_channel, paid_at, vip_period_start, vip_period_end")).
+		WithArgs("mo_003").
+		WillReturnRows(sqlmock.NewRows([]string{"order_no", "user_id", "product_id", "amount", "status", "pay_channel", "paid_at", "vip_period_start", "vip_period_end"}).
+			AddRow("MO003", "u_001", "mp_vip1", 99.0, "PAID", "YOLKPAY", now, now, now.AddDate(0, 0, 30)))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_refunds")).
+		WithArgs("mo_003").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_refunds")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT channel_txn_no FROM payment_callback_logs")).
+		WithArgs("MO003").
+		WillReturnRows(sqlmock.NewRows([]string{"channel_txn_no"}))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_refunds SET status = 'FAILED'")).
+		WithArgs("insufficient merchant balance", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	_, err = repo.AdminRefundMembershipOrder("mo_003", "customer request", "admin_001", channels)
+	if !errors.Is(err, payment.ErrRefundFailed) {
+		t.Fatalf("expected ErrRefundFailed, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminRefundMembershipOrderSkipsChannelForConsumedOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -40)
+	periodEnd := now.AddDate(0, 0, -10)
+	stub := &payment.StubChannel{}
+	channels := payment.ResolverFunc(func(name string) (payment.Channel, error) { return stub, nil })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT order_no, user_id, product_id, amount, status, pay_channel, paid_at, vip_period_start, vip_period_end")).
+		WithArgs("mo_004").
+		WillReturnRows(sqlmock.NewRows([]string{"order_no", "user_id", "product_id", "amount", "status", "pay_channel", "paid_at", "vip_period_start", "vip_period_end"}).
+			AddRow("MO004", "u_001", "mp_vip1", 99.0, "PAID", "YOLKPAY", periodStart, periodStart, periodEnd))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM membership_refunds")).
+		WithArgs("mo_004").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO membership_refunds")).
+		WithArgs(sqlmock.AnyArg(), "mo_004", "MO004", "u_001", "YOLKPAY", "STUB", 99.0, 0.0, int64(0), "customer request", "admin_001", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM membership_orders WHERE id = ? FOR UPDATE")).
+		WithArgs("mo_004").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("PAID"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_orders SET status = 'REFUNDED'")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT member_level, vip_expire_at FROM users WHERE id = ? FOR UPDATE")).
+		WithArgs("u_001").
+		WillReturnRows(sqlmock.NewRows([]string{"member_level", "vip_expire_at"}).AddRow("FREE", nil))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, inviter_user_id, reward_value, status")).
+		WithArgs("mo_004").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviter_user_id", "reward_value", "status"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, invitee_user_id FROM invite_records WHERE first_pay_order_id = ?")).
+		WithArgs("mo_004").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invitee_user_id"}))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE membership_refunds SET status = 'SUCCESS'")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	item, err := repo.AdminRefundMembershipOrder("mo_004", "customer request", "admin_001", channels)
+	if err != nil {
+		t.Fatalf("AdminRefundMembershipOrder() error = %v", err)
+	}
+	if item.Status != "SUCCESS" || item.Amount != 0 || item.ChannelRefundNo != "" {
+		t.Fatalf("unexpected refund: %+v", item)
+	}
+	if len(stub.Requests) != 0 {
+		t.Fatalf("expected no gateway refund for a consumed order, got %+v", stub.Requests)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
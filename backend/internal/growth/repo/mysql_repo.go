@@ -26,6 +26,9 @@ import (
 
 	"sercherai/backend/internal/growth/model"
 	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/secrets"
+	"sercherai/backend/internal/platform/realtime"
+	"sercherai/backend/internal/platform/session"
 )
 
 type MySQLGrowthRepo struct {
@@ -33,6 +36,11 @@ type MySQLGrowthRepo struct {
 	redis          *redis.Client
 	strategyEngine *strategyEngineClient
 	strategyGraph  *strategyGraphClient
+	auditSigner    *auditLedgerSigner
+	configSecrets  *secrets.Keyring
+	sessions       *session.Store
+	sensitiveWords communitySensitiveWordCache
+	realtime       *realtime.Hub
 }
 
 var repoIDSequence atomic.Uint64
@@ -43,6 +51,10 @@ func NewMySQLGrowthRepo(db *sql.DB, redisClient *redis.Client, cfg config.Config
 		redis:          redisClient,
 		strategyEngine: newStrategyEngineClient(cfg),
 		strategyGraph:  newStrategyGraphClient(cfg),
+		auditSigner:    newAuditLedgerSigner(auditSigningSecret(cfg)),
+		configSecrets:  loadConfigSecretKeyring(cfg),
+		sessions:       session.NewStore(db, redisClient),
+		realtime:       realtime.NewHub(redisClient),
 	}
 }
 
@@ -349,7 +361,7 @@ func (r *MySQLGrowthRepo) ListRewardRecords(userID string, page int, pageSize in
 	}
 
 	rows, err := r.db.Query(`
-SELECT id, reward_type, reward_value, trigger_event, status, issued_at
+SELECT id, reward_type, reward_value, trigger_event, status, issued_at, COALESCE(order_id, ''), COALESCE(referral_level, 0), available_at
 FROM share_reward_records
 WHERE inviter_user_id = ?
 ORDER BY created_at DESC
@@ -362,13 +374,16 @@ LIMIT ? OFFSET ?`, userID, pageSize, offset)
 	items := make([]model.RewardRecord, 0)
 	for rows.Next() {
 		var item model.RewardRecord
-		var issuedAt sql.NullTime
-		if err := rows.Scan(&item.ID, &item.RewardType, &item.RewardValue, &item.TriggerEvent, &item.Status, &issuedAt); err != nil {
+		var issuedAt, availableAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.RewardType, &item.RewardValue, &item.TriggerEvent, &item.Status, &issuedAt, &item.OrderID, &item.ReferralLevel, &availableAt); err != nil {
 			return nil, 0, err
 		}
 		if issuedAt.Valid {
 			item.IssuedAt = issuedAt.Time.Format(time.RFC3339)
 		}
+		if availableAt.Valid {
+			item.AvailableAt = availableAt.Time.Format(time.RFC3339)
+		}
 		items = append(items, item)
 	}
 	return items, total, nil
@@ -674,17 +689,20 @@ func (r *MySQLGrowthRepo) GetAttachmentFileInfo(userID string, attachmentID stri
 		return model.AttachmentFileInfo{}, err
 	}
 	query := `
-SELECT a.file_url, a.article_id
+SELECT a.file_url, a.article_id, a.file_name, a.mime_type
 FROM news_attachments a
 JOIN news_articles n ON a.article_id = n.id
-WHERE a.id = ? AND n.status = 'PUBLISHED'`
+WHERE a.id = ? AND n.status = 'PUBLISHED'
+  AND (n.embargo_until IS NULL OR n.embargo_until <= NOW())`
 	if !isVIP {
 		query += " AND n.visibility = 'PUBLIC'"
 	}
 	var info model.AttachmentFileInfo
-	if err := r.db.QueryRow(query, attachmentID).Scan(&info.FileURL, &info.ArticleID); err != nil {
+	var mimeType sql.NullString
+	if err := r.db.QueryRow(query, attachmentID).Scan(&info.FileURL, &info.ArticleID, &info.FileName, &mimeType); err != nil {
 		return model.AttachmentFileInfo{}, err
 	}
+	info.MimeType = mimeType.String
 	return info, nil
 }
 
@@ -719,20 +737,35 @@ ORDER BY sort ASC, created_at DESC`)
 	return items, nil
 }
 
-func (r *MySQLGrowthRepo) ListNewsArticles(userID string, categoryID string, keyword string, page int, pageSize int) ([]model.NewsArticle, int, error) {
-	offset := (page - 1) * pageSize
-
+// newsArticleListConditions is the user news list filter on the table
+// aliased as alias.
+func newsArticleListConditions(alias string, categoryID string, keyword string) (string, []interface{}) {
 	args := []interface{}{}
-	filter := " WHERE status = 'PUBLISHED'"
+	conditions := alias + ".status = 'PUBLISHED' AND (" + alias + ".embargo_until IS NULL OR " + alias + ".embargo_until <= NOW())"
 	if categoryID != "" {
-		filter += " AND category_id = ?"
+		conditions += " AND " + alias + ".category_id = ?"
 		args = append(args, categoryID)
 	}
 	if keyword != "" {
-		filter += " AND (title LIKE ? OR summary LIKE ?)"
+		conditions += " AND (" + alias + ".title LIKE ? OR " + alias + ".summary LIKE ?)"
 		kw := "%" + keyword + "%"
 		args = append(args, kw, kw)
 	}
+	return conditions, args
+}
+
+// ListNewsArticles hides a near-duplicate only when its canonical is listed
+// under the same filters, so a story whose canonical is embargoed, in
+// another category or missing the keyword still shows up once.
+func (r *MySQLGrowthRepo) ListNewsArticles(userID string, categoryID string, keyword string, page int, pageSize int) ([]model.NewsArticle, int, error) {
+	offset := (page - 1) * pageSize
+
+	conditions, args := newsArticleListConditions("news_articles", categoryID, keyword)
+	canonicalConditions, canonicalArgs := newsArticleListConditions("canon", categoryID, keyword)
+	filter := " WHERE " + conditions + `
+  AND (news_articles.duplicate_of IS NULL OR NOT EXISTS (
+    SELECT 1 FROM news_articles canon WHERE canon.id = news_articles.duplicate_of AND ` + canonicalConditions + `))`
+	args = append(args, canonicalArgs...)
 
 	var total int
 	if err := r.db.QueryRow("SELECT COUNT(*) FROM news_articles"+filter, args...).Scan(&total); err != nil {
@@ -740,7 +773,8 @@ func (r *MySQLGrowthRepo) ListNewsArticles(userID string, categoryID string, key
 	}
 
 	query := `
-SELECT id, category_id, title, summary, cover_url, visibility, status, published_at, author_id
+SELECT id, category_id, title, summary, cover_url, visibility, status, published_at, author_id,
+  (SELECT COUNT(*) FROM news_articles alt WHERE alt.duplicate_of = news_articles.id AND alt.status = 'PUBLISHED') AS alternate_source_count
 FROM news_articles` + filter + `
 ORDER BY published_at DESC, created_at DESC
 LIMIT ? OFFSET ?`
@@ -756,7 +790,7 @@ LIMIT ? OFFSET ?`
 		var item model.NewsArticle
 		var summary, coverURL, authorID sql.NullString
 		var publishedAt sql.NullTime
-		if err := rows.Scan(&item.ID, &item.CategoryID, &item.Title, &summary, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID); err != nil {
+		if err := rows.Scan(&item.ID, &item.CategoryID, &item.Title, &summary, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID, &item.AlternateSourceCount); err != nil {
 			return nil, 0, err
 		}
 		if summary.Valid {
@@ -782,22 +816,29 @@ func (r *MySQLGrowthRepo) GetNewsArticleDetail(userID string, articleID string)
 		return model.NewsArticle{}, err
 	}
 	query := `
-SELECT id, category_id, title, summary, content, cover_url, visibility, status, published_at, author_id
+SELECT id, category_id, title, summary, content, cover_url, visibility, status, published_at, author_id, duplicate_of
 FROM news_articles
-WHERE id = ? AND status = 'PUBLISHED'`
+WHERE id = ? AND status = 'PUBLISHED' AND (embargo_until IS NULL OR embargo_until <= NOW())`
 	args := []interface{}{articleID}
 	if !isVIP {
 		query += " AND visibility = 'PUBLIC'"
 	}
 	var item model.NewsArticle
-	var summary, content, coverURL, authorID sql.NullString
+	var summary, content, coverURL, authorID, duplicateOf sql.NullString
 	var publishedAt sql.NullTime
 	err = r.db.QueryRow(query, args...).Scan(
-		&item.ID, &item.CategoryID, &item.Title, &summary, &content, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID,
+		&item.ID, &item.CategoryID, &item.Title, &summary, &content, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID, &duplicateOf,
 	)
 	if err != nil {
 		return model.NewsArticle{}, err
 	}
+	item.DuplicateOf = duplicateOf.String
+	alternates, err := r.listNewsArticleAlternateSources(item.ID, item.DuplicateOf, !isVIP, true)
+	if err != nil {
+		return model.NewsArticle{}, err
+	}
+	item.AlternateSources = alternates
+	item.AlternateSourceCount = len(alternates)
 	if summary.Valid {
 		item.Summary = summary.String
 	}
@@ -827,7 +868,7 @@ func (r *MySQLGrowthRepo) ListNewsAttachments(userID string, articleID string) (
 		return nil, err
 	}
 
-	query := "SELECT COUNT(*) FROM news_articles WHERE id = ? AND status = 'PUBLISHED'"
+	query := "SELECT COUNT(*) FROM news_articles WHERE id = ? AND status = 'PUBLISHED' AND (embargo_until IS NULL OR embargo_until <= NOW())"
 	args := []interface{}{articleID}
 	if !isVIP {
 		query += " AND visibility = 'PUBLIC'"
@@ -870,10 +911,10 @@ ORDER BY created_at DESC`, articleID)
 func (r *MySQLGrowthRepo) GetRewardWallet(userID string) (model.RewardWallet, error) {
 	var item model.RewardWallet
 	err := r.db.QueryRow(`
-SELECT cash_balance, cash_frozen, coupon_balance, vip_days_balance
+SELECT cash_balance, cash_frozen, cash_debt, coupon_balance, vip_days_balance
 FROM reward_wallets
 WHERE user_id = ?`, userID).Scan(
-		&item.CashBalance, &item.CashFrozen, &item.CouponBalance, &item.VIPDaysBalance,
+		&item.CashBalance, &item.CashFrozen, &item.CashDebt, &item.CouponBalance, &item.VIPDaysBalance,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -958,92 +999,15 @@ VALUES (?, ?, ?, ?, ?, ?, 'RECEIVED', ?)`,
 	}
 
 	var orderID, userID, productID, status string
+	var orderAmount float64
 	orderErr := tx.QueryRow(`
-SELECT id, user_id, product_id, status
+SELECT id, user_id, product_id, amount, status
 FROM membership_orders
 WHERE order_no = ? OR id = ?
-LIMIT 1`, orderNo, orderNo).Scan(&orderID, &userID, &productID, &status)
+LIMIT 1`, orderNo, orderNo).Scan(&orderID, &userID, &productID, &orderAmount, &status)
 	if orderErr == nil {
 		if strings.ToUpper(status) != "PAID" {
-			if _, err := tx.Exec(`
-UPDATE membership_orders
-SET status = 'PAID', paid_at = ?, pay_channel = ?, updated_at = ?
-WHERE id = ?`, now, strings.ToUpper(channel), now, orderID); err != nil {
-				_ = tx.Rollback()
-				return err
-			}
-			var memberLevel sql.NullString
-			var durationDays sql.NullInt64
-			if err := tx.QueryRow(
-				"SELECT member_level, duration_days FROM membership_products WHERE id = ?",
-				productID,
-			).Scan(&memberLevel, &durationDays); err == nil {
-				level := strings.ToUpper(strings.TrimSpace(memberLevel.String))
-				days := int(durationDays.Int64)
-				if level != "" && days <= 0 {
-					days = 30
-				}
-				if level != "" {
-					var currentLevel string
-					var currentExpireAt sql.NullTime
-					if err := tx.QueryRow(
-						"SELECT member_level, vip_expire_at FROM users WHERE id = ? FOR UPDATE",
-						userID,
-					).Scan(&currentLevel, &currentExpireAt); err != nil {
-						_ = tx.Rollback()
-						return err
-					}
-					baseTime := now
-					if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(currentLevel)), "VIP") &&
-						currentExpireAt.Valid &&
-						currentExpireAt.Time.After(now) {
-						baseTime = currentExpireAt.Time
-					}
-					newExpireAt := baseTime
-					if days > 0 {
-						newExpireAt = baseTime.AddDate(0, 0, days)
-					}
-					startedAt := now
-					if _, err := tx.Exec(`
-UPDATE users
-SET member_level = ?,
-    vip_started_at = ?,
-    vip_expire_at = ?,
-    vip_remind_3d_at = NULL,
-    vip_remind_1d_at = NULL,
-    updated_at = ?
-WHERE id = ?`,
-						level,
-						startedAt,
-						newExpireAt,
-						now,
-						userID,
-					); err != nil {
-						_ = tx.Rollback()
-						return err
-					}
-					title := "VIP会员开通成功"
-					content := fmt.Sprintf(
-						"订单%s支付成功，会员等级已更新为%s，到期时间：%s。",
-						strings.TrimSpace(orderNo),
-						level,
-						newExpireAt.Format("2006-01-02 15:04:05"),
-					)
-					if _, err := tx.Exec(`
-INSERT INTO messages (id, user_id, title, content, type, read_status, created_at)
-VALUES (?, ?, ?, ?, 'SYSTEM', 'UNREAD', ?)`,
-						newID("msg"),
-						userID,
-						title,
-						content,
-						now,
-					); err != nil {
-						_ = tx.Rollback()
-						return err
-					}
-				}
-			}
-			if err := createExperimentSuccessEventTx(tx, strings.TrimSpace(orderNo), now); err != nil {
+			if err := r.markMembershipOrderPaidTx(tx, orderID, orderNo, userID, productID, channel, orderAmount, now); err != nil {
 				_ = tx.Rollback()
 				return err
 			}
@@ -1077,20 +1041,15 @@ VALUES (?, ?, ?, ?, 'SYSTEM', 'UNREAD', ?)`,
 
 func (r *MySQLGrowthRepo) ListArbitrageOpportunities(typeFilter string, page int, pageSize int) ([]model.ArbitrageOpportunity, int, error) {
 	offset := (page - 1) * pageSize
-	args := []interface{}{}
-	filter := ""
-	if typeFilter != "" {
-		filter = " WHERE type = ?"
-		args = append(args, typeFilter)
-	}
+	filter, args := arbitrageRecoListFilter(typeFilter)
 	var total int
 	if err := r.db.QueryRow("SELECT COUNT(*) FROM arbitrage_recos"+filter, args...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 	query := `
-SELECT id, type, contract_a, contract_b, spread, percentile, status
+SELECT id, type, contract_a, contract_b, spread, percentile, z_score, half_life, risk_level, status
 FROM arbitrage_recos` + filter + `
-ORDER BY id DESC
+ORDER BY COALESCE(trade_date, '1970-01-01') DESC, ABS(COALESCE(z_score, 0)) DESC, id DESC
 LIMIT ? OFFSET ?`
 	args = append(args, pageSize, offset)
 	rows, err := r.db.Query(query, args...)
@@ -1101,8 +1060,9 @@ LIMIT ? OFFSET ?`
 	items := make([]model.ArbitrageOpportunity, 0)
 	for rows.Next() {
 		var item model.ArbitrageOpportunity
-		var spread, percentile sql.NullFloat64
-		if err := rows.Scan(&item.ID, &item.Type, &item.ContractA, &item.ContractB, &spread, &percentile, &item.Status); err != nil {
+		var spread, percentile, zScore, halfLife sql.NullFloat64
+		var riskLevel sql.NullString
+		if err := rows.Scan(&item.ID, &item.Type, &item.ContractA, &item.ContractB, &spread, &percentile, &zScore, &halfLife, &riskLevel, &item.Status); err != nil {
 			return nil, 0, err
 		}
 		if spread.Valid {
@@ -1111,7 +1071,13 @@ LIMIT ? OFFSET ?`
 		if percentile.Valid {
 			item.Percentile = percentile.Float64
 		}
-		item.RiskLevel = "MEDIUM"
+		if zScore.Valid {
+			item.ZScore = zScore.Float64
+		}
+		if halfLife.Valid {
+			item.HalfLife = halfLife.Float64
+		}
+		item.RiskLevel = firstNonEmpty(riskLevel.String, "MEDIUM")
 		items = append(items, item)
 	}
 	return items, total, nil
@@ -1119,20 +1085,16 @@ LIMIT ? OFFSET ?`
 
 func (r *MySQLGrowthRepo) ListFuturesArbitrage(typeFilter string, page int, pageSize int) ([]model.ArbitrageRecommendation, int, error) {
 	offset := (page - 1) * pageSize
-	args := []interface{}{}
-	filter := ""
-	if typeFilter != "" {
-		filter = " WHERE type = ?"
-		args = append(args, typeFilter)
-	}
+	filter, args := arbitrageRecoListFilter(typeFilter)
 	var total int
 	if err := r.db.QueryRow("SELECT COUNT(*) FROM arbitrage_recos"+filter, args...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 	query := `
-SELECT id, type, contract_a, contract_b, spread, percentile, entry_point, exit_point, stop_point, status
+SELECT id, type, contract_a, contract_b, spread, percentile, entry_point, exit_point, stop_point, status,
+  z_score, half_life, direction, risk_level, trade_date
 FROM arbitrage_recos` + filter + `
-ORDER BY id DESC
+ORDER BY COALESCE(trade_date, '1970-01-01') DESC, ABS(COALESCE(z_score, 0)) DESC, id DESC
 LIMIT ? OFFSET ?`
 	args = append(args, pageSize, offset)
 	rows, err := r.db.Query(query, args...)
@@ -1144,9 +1106,14 @@ LIMIT ? OFFSET ?`
 	for rows.Next() {
 		var item model.ArbitrageRecommendation
 		var spread, percentile, entryPoint, exitPoint, stopPoint sql.NullFloat64
-		if err := rows.Scan(&item.ID, &item.Type, &item.ContractA, &item.ContractB, &spread, &percentile, &entryPoint, &exitPoint, &stopPoint, &item.Status); err != nil {
+		var engine arbitrageRecoEngineColumns
+		if err := rows.Scan(
+			&item.ID, &item.Type, &item.ContractA, &item.ContractB, &spread, &percentile, &entryPoint, &exitPoint, &stopPoint, &item.Status,
+			&engine.ZScore, &engine.HalfLife, &engine.Direction, &engine.RiskLevel, &engine.TradeDate,
+		); err != nil {
 			return nil, 0, err
 		}
+		engine.apply(&item)
 		if spread.Valid {
 			item.Spread = spread.Float64
 		}
@@ -1169,19 +1136,33 @@ LIMIT ? OFFSET ?`
 
 func (r *MySQLGrowthRepo) GetFuturesArbitrageDetail(id string) (model.ArbitrageRecommendation, error) {
 	var item model.ArbitrageRecommendation
-	var spread, percentile, entryPoint, exitPoint, stopPoint sql.NullFloat64
-	var triggerRule sql.NullString
+	var spread, percentile, entryPoint, exitPoint, stopPoint, spreadMean, spreadStd sql.NullFloat64
+	var triggerRule, pairKey sql.NullString
+	var sampleDays sql.NullInt64
+	var engine arbitrageRecoEngineColumns
 	err := r.db.QueryRow(`
-SELECT id, type, contract_a, contract_b, spread, percentile, entry_point, exit_point, stop_point, trigger_rule, status
+SELECT id, type, contract_a, contract_b, spread, percentile, entry_point, exit_point, stop_point, trigger_rule, status,
+  z_score, half_life, direction, risk_level, trade_date, spread_mean, spread_std, sample_days, pair_key
 FROM arbitrage_recos
 WHERE id = ?
 LIMIT 1`, id).Scan(
 		&item.ID, &item.Type, &item.ContractA, &item.ContractB, &spread, &percentile,
 		&entryPoint, &exitPoint, &stopPoint, &triggerRule, &item.Status,
+		&engine.ZScore, &engine.HalfLife, &engine.Direction, &engine.RiskLevel, &engine.TradeDate,
+		&spreadMean, &spreadStd, &sampleDays, &pairKey,
 	)
 	if err != nil {
 		return model.ArbitrageRecommendation{}, err
 	}
+	engine.apply(&item)
+	item.SpreadMean = spreadMean.Float64
+	item.SpreadStd = spreadStd.Float64
+	item.SampleDays = int(sampleDays.Int64)
+	history, err := r.loadFuturesArbitrageHistory(pairKey.String, 120)
+	if err != nil {
+		return model.ArbitrageRecommendation{}, err
+	}
+	item.History = history
 	if spread.Valid {
 		item.Spread = spread.Float64
 	}
@@ -1490,7 +1471,7 @@ func (r *MySQLGrowthRepo) AdminListRewardRecords(status string, page int, pageSi
 		return nil, 0, err
 	}
 	query := `
-SELECT id, inviter_user_id, invitee_user_id, reward_type, reward_value, status
+SELECT id, inviter_user_id, invitee_user_id, reward_type, reward_value, trigger_event, status, COALESCE(order_id, ''), COALESCE(referral_level, 0), available_at
 FROM share_reward_records` + filter + `
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?`
@@ -1503,17 +1484,53 @@ LIMIT ? OFFSET ?`
 	items := make([]model.RewardRecord, 0)
 	for rows.Next() {
 		var item model.RewardRecord
-		if err := rows.Scan(&item.ID, &item.InviterUser, &item.InviteeUser, &item.RewardType, &item.RewardValue, &item.Status); err != nil {
+		var availableAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.InviterUser, &item.InviteeUser, &item.RewardType, &item.RewardValue, &item.TriggerEvent, &item.Status, &item.OrderID, &item.ReferralLevel, &availableAt); err != nil {
 			return nil, 0, err
 		}
+		if availableAt.Valid {
+			item.AvailableAt = availableAt.Time.Format(time.RFC3339)
+		}
 		items = append(items, item)
 	}
 	return items, total, nil
 }
 
 func (r *MySQLGrowthRepo) AdminReviewRewardRecord(id string, status string, reason string) error {
-	_, err := r.db.Exec("UPDATE share_reward_records SET status = ? WHERE id = ?", status, id)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var beneficiary, currentStatus string
+	var amount float64
+	var orderID sql.NullString
+	err = tx.QueryRow(
+		"SELECT inviter_user_id, reward_value, status, order_id FROM share_reward_records WHERE id = ? FOR UPDATE",
+		id,
+	).Scan(&beneficiary, &amount, &currentStatus, &orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Payment commissions carry money in the reward wallet, so their review
+	// also moves the amount between the frozen and withdrawable balances.
+	if strings.TrimSpace(orderID.String) == "" {
+		if _, err := tx.Exec("UPDATE share_reward_records SET status = ? WHERE id = ?", status, id); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	if strings.EqualFold(currentStatus, "REVERSED") {
+		return errors.New("commission already reversed")
+	}
+	if err := transitionInviteCommissionTx(tx, id, beneficiary, amount, currentStatus, status, "COMMISSION_REVIEW", reason, time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *MySQLGrowthRepo) AdminListReconciliation(page int, pageSize int) ([]model.ReconciliationRecord, int, error) {
@@ -1728,7 +1745,9 @@ func (r *MySQLGrowthRepo) AdminListNewsArticles(status string, categoryID string
 	}
 	query := `
 SELECT na.id, na.category_id, na.title, na.summary, na.cover_url, na.visibility, na.status, na.published_at, na.author_id,
-  (SELECT COUNT(*) FROM news_attachments att WHERE att.article_id = na.id) AS attachment_count
+  (SELECT COUNT(*) FROM news_attachments att WHERE att.article_id = na.id) AS attachment_count,
+  na.scheduled_publish_at, na.embargo_until, na.duplicate_of,
+  (SELECT COUNT(*) FROM news_articles alt WHERE alt.duplicate_of = na.id) AS alternate_source_count
 FROM news_articles na` + filter + `
 ORDER BY na.created_at DESC
 LIMIT ? OFFSET ?`
@@ -1741,11 +1760,18 @@ LIMIT ? OFFSET ?`
 	items := make([]model.NewsArticle, 0)
 	for rows.Next() {
 		var item model.NewsArticle
-		var summary, coverURL, authorID sql.NullString
-		var publishedAt sql.NullTime
-		if err := rows.Scan(&item.ID, &item.CategoryID, &item.Title, &summary, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID, &item.AttachmentCount); err != nil {
+		var summary, coverURL, authorID, duplicateOf sql.NullString
+		var publishedAt, scheduledPublishAt, embargoUntil sql.NullTime
+		if err := rows.Scan(&item.ID, &item.CategoryID, &item.Title, &summary, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID, &item.AttachmentCount, &scheduledPublishAt, &embargoUntil, &duplicateOf, &item.AlternateSourceCount); err != nil {
 			return nil, 0, err
 		}
+		item.DuplicateOf = duplicateOf.String
+		if scheduledPublishAt.Valid {
+			item.ScheduledPublishAt = scheduledPublishAt.Time.Format(time.RFC3339)
+		}
+		if embargoUntil.Valid {
+			item.EmbargoUntil = embargoUntil.Time.Format(time.RFC3339)
+		}
 		if summary.Valid {
 			item.Summary = summary.String
 		}
@@ -1766,18 +1792,33 @@ LIMIT ? OFFSET ?`
 func (r *MySQLGrowthRepo) AdminGetNewsArticleDetail(id string) (model.NewsArticle, error) {
 	query := `
 SELECT na.id, na.category_id, na.title, na.summary, na.content, na.cover_url, na.visibility, na.status, na.published_at, na.author_id,
-  (SELECT COUNT(*) FROM news_attachments att WHERE att.article_id = na.id) AS attachment_count
+  (SELECT COUNT(*) FROM news_attachments att WHERE att.article_id = na.id) AS attachment_count,
+  na.scheduled_publish_at, na.embargo_until, na.duplicate_of
 FROM news_articles na
 WHERE na.id = ?`
 	var item model.NewsArticle
-	var summary, content, coverURL, authorID sql.NullString
-	var publishedAt sql.NullTime
+	var summary, content, coverURL, authorID, duplicateOf sql.NullString
+	var publishedAt, scheduledPublishAt, embargoUntil sql.NullTime
 	err := r.db.QueryRow(query, id).Scan(
 		&item.ID, &item.CategoryID, &item.Title, &summary, &content, &coverURL, &item.Visibility, &item.Status, &publishedAt, &authorID, &item.AttachmentCount,
+		&scheduledPublishAt, &embargoUntil, &duplicateOf,
 	)
 	if err != nil {
 		return model.NewsArticle{}, err
 	}
+	item.DuplicateOf = duplicateOf.String
+	alternates, err := r.listNewsArticleAlternateSources(item.ID, item.DuplicateOf, false, false)
+	if err != nil {
+		return model.NewsArticle{}, err
+	}
+	item.AlternateSources = alternates
+	item.AlternateSourceCount = len(alternates)
+	if scheduledPublishAt.Valid {
+		item.ScheduledPublishAt = scheduledPublishAt.Time.Format(time.RFC3339)
+	}
+	if embargoUntil.Valid {
+		item.EmbargoUntil = embargoUntil.Time.Format(time.RFC3339)
+	}
 	if summary.Valid {
 		item.Summary = summary.String
 	}
@@ -1798,29 +1839,67 @@ WHERE na.id = ?`
 
 func (r *MySQLGrowthRepo) AdminCreateNewsArticle(categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, authorID string) (string, error) {
 	id := newID("na")
-	_, err := r.db.Exec(`
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(`
 INSERT INTO news_articles (id, category_id, title, summary, content, cover_url, visibility, status, published_at, author_id, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		id, categoryID, title, summary, content, nullableString(coverURL), visibility, status, time.Now(), authorID, time.Now(), time.Now(),
-	)
-	if err != nil {
+		id, categoryID, title, summary, content, nullableString(coverURL), visibility, status, now, authorID, now, now,
+	); err != nil {
+		_ = tx.Rollback()
+		return "", err
+	}
+	if err := insertNewsArticleRevisionTx(tx, id, model.NewsArticleRevisionCreate, 0, authorID, now); err != nil {
+		_ = tx.Rollback()
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
 		return "", err
 	}
 	return id, nil
 }
 
-func (r *MySQLGrowthRepo) AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string) error {
-	_, err := r.db.Exec(`
+// AdminUpdateNewsArticle overwrites the article and records the result as a
+// new revision. A scheduled article keeps its publish time only while the
+// status stays SCHEDULED.
+func (r *MySQLGrowthRepo) AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, editorID string) error {
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	var currentStatus string
+	if err := tx.QueryRow("SELECT status FROM news_articles WHERE id = ? FOR UPDATE", id).Scan(&currentStatus); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if status == model.NewsArticleStatusScheduled && currentStatus != model.NewsArticleStatusScheduled {
+		_ = tx.Rollback()
+		return model.ErrNewsArticleStatusConflict
+	}
+	if _, err := tx.Exec(`
 UPDATE news_articles
-SET category_id = ?, title = ?, summary = ?, content = ?, cover_url = ?, visibility = ?, status = ?, updated_at = ?
-WHERE id = ?`, categoryID, title, summary, content, nullableString(coverURL), visibility, status, time.Now(), id)
-	return err
+SET category_id = ?, title = ?, summary = ?, content = ?, cover_url = ?, visibility = ?, status = ?,
+    scheduled_publish_at = CASE WHEN ? = 'SCHEDULED' THEN scheduled_publish_at ELSE NULL END,
+    updated_at = ?
+WHERE id = ?`, categoryID, title, summary, content, nullableString(coverURL), visibility, status, status, now, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := insertNewsArticleRevisionTx(tx, id, model.NewsArticleRevisionUpdate, 0, editorID, now); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *MySQLGrowthRepo) AdminPublishNewsArticle(id string, status string) error {
 	result, err := r.db.Exec(`
 UPDATE news_articles
-SET status = ?, published_at = ?, updated_at = ?
+SET status = ?, published_at = ?, scheduled_publish_at = NULL, updated_at = ?
 WHERE id = ?`, status, time.Now(), time.Now(), id)
 	if err != nil {
 		return err
@@ -1875,6 +1954,30 @@ ORDER BY created_at DESC`, articleID)
 	return items, nil
 }
 
+func (r *MySQLGrowthRepo) AdminGetNewsAttachment(id string) (model.NewsAttachment, error) {
+	var item model.NewsAttachment
+	var mimeType sql.NullString
+	var createdAt time.Time
+	err := r.db.QueryRow(`
+SELECT id, article_id, file_name, file_url, file_size, mime_type, created_at
+FROM news_attachments
+WHERE id = ?`, id).Scan(&item.ID, &item.ArticleID, &item.FileName, &item.FileURL, &item.FileSize, &mimeType, &createdAt)
+	if err != nil {
+		return model.NewsAttachment{}, err
+	}
+	item.MimeType = mimeType.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	return item, nil
+}
+
+// AdminCountNewsAttachmentsByFileURL counts attachments sharing a stored
+// object; uploads are content-addressed, so identical files share one.
+func (r *MySQLGrowthRepo) AdminCountNewsAttachmentsByFileURL(fileURL string) (int, error) {
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM news_attachments WHERE file_url = ?", fileURL).Scan(&total)
+	return total, err
+}
+
 func (r *MySQLGrowthRepo) AdminDeleteNewsAttachment(id string) error {
 	result, err := r.db.Exec("DELETE FROM news_attachments WHERE id = ?", id)
 	if err != nil {
@@ -2127,6 +2230,12 @@ ON DUPLICATE KEY UPDATE
 			r.markDocFastSyncFailed(err.Error())
 			return "", err
 		}
+		if _, err := linkNewsNearDuplicates(tx, newsArticleDuplicateTable, articleID, newsSimHash(title, content), fromUnixSecondOrNow(item.PublishUnix)); err != nil {
+			rollbacked = true
+			_ = tx.Rollback()
+			r.markDocFastSyncFailed(err.Error())
+			return "", err
+		}
 		syncedArticles++
 
 		attachments := parseDocFastDownloadAttachments(item.DownloadURL)
@@ -2889,7 +2998,7 @@ WHERE slug IN (?, ?, ?, ?, ?)`,
 func (r *MySQLGrowthRepo) resolveTushareTokenForNewsSync() (string, error) {
 	item, err := r.getDataSourceBySourceKey("TUSHARE")
 	if err == nil {
-		if token := parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token"); strings.TrimSpace(token) != "" {
+		if token := r.resolveDataSourceToken(item); strings.TrimSpace(token) != "" {
 			return strings.TrimSpace(token), nil
 		}
 	}
@@ -2963,6 +3072,9 @@ ON DUPLICATE KEY UPDATE
 		if err != nil {
 			return syncedArticles, syncedAttachments, err
 		}
+		if _, err := linkNewsNearDuplicates(r.db, newsArticleDuplicateTable, id, newsSimHash(title, content), publishedAt); err != nil {
+			return syncedArticles, syncedAttachments, err
+		}
 		syncedArticles++
 
 		attachmentURL := strings.TrimSpace(item.AttachmentURL)
@@ -4516,6 +4628,8 @@ SELECT na.id, na.title, na.summary, nc.name AS source, na.visibility, na.publish
 FROM news_articles na
 LEFT JOIN news_categories nc ON nc.id = na.category_id
 WHERE na.status = 'PUBLISHED'
+  AND (na.embargo_until IS NULL OR na.embargo_until <= NOW())
+  AND na.duplicate_of IS NULL
   AND na.published_at >= ?
   AND (` + strings.Join(conditions, " OR ") + `)
 ORDER BY na.published_at DESC
@@ -5435,37 +5549,84 @@ LIMIT ? OFFSET ?`
 	return items, total, nil
 }
 
-func (r *MySQLGrowthRepo) CreateMembershipOrder(userID string, productID string, payChannel string) (model.MembershipOrderAdmin, error) {
+// CreateMembershipOrder opens a pending order for productID. With a coupon
+// code the coupon is locked, validated and reserved in the same transaction,
+// and the order keeps the original price, the discount and the code.
+func (r *MySQLGrowthRepo) CreateMembershipOrder(userID string, productID string, payChannel string, couponCode string) (model.MembershipOrderAdmin, error) {
+	return r.createMembershipOrder(userID, productID, payChannel, couponCode, membershipOrderSourceUser)
+}
+
+// createMembershipOrder opens a PENDING order. source tells buyer-initiated
+// orders (USER) from ones the auto-renew job raises (AUTO_RENEW).
+func (r *MySQLGrowthRepo) createMembershipOrder(userID string, productID string, payChannel string, couponCode string, source string) (model.MembershipOrderAdmin, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.MembershipOrderAdmin{}, err
+	}
 	var price float64
 	var status string
-	err := r.db.QueryRow("SELECT price, status FROM membership_products WHERE id = ?", productID).Scan(&price, &status)
+	var memberLevel sql.NullString
+	err = tx.QueryRow("SELECT price, status, member_level FROM membership_products WHERE id = ?", productID).Scan(&price, &status, &memberLevel)
 	if err != nil {
+		_ = tx.Rollback()
 		return model.MembershipOrderAdmin{}, err
 	}
 	if strings.ToUpper(status) != "ACTIVE" {
+		_ = tx.Rollback()
 		return model.MembershipOrderAdmin{}, errors.New("membership product not active")
 	}
 	id := newID("mo")
 	orderNo := id
 	now := time.Now()
-	_, err = r.db.Exec(`
-INSERT INTO membership_orders (id, order_no, user_id, product_id, amount, pay_channel, status, paid_at, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, 'PENDING', NULL, ?, ?)`,
-		id, orderNo, userID, productID, price, strings.ToUpper(payChannel), now, now,
+	order := model.MembershipOrderAdmin{
+		ID:             id,
+		OrderNo:        orderNo,
+		UserID:         userID,
+		ProductID:      productID,
+		Amount:         price,
+		OriginalAmount: price,
+		PayChannel:     strings.ToUpper(payChannel),
+		OrderSource:    source,
+		Status:         "PENDING",
+		CreatedAt:      now.Format(time.RFC3339),
+	}
+	var coupon model.MembershipCoupon
+	if normalizeMembershipCouponCode(couponCode) != "" {
+		var quote model.MembershipCouponQuote
+		coupon, quote, err = quoteMembershipCoupon(tx, userID, productID, memberLevel.String, price, couponCode, true)
+		if err != nil {
+			_ = tx.Rollback()
+			return model.MembershipOrderAdmin{}, err
+		}
+		order.Amount = quote.Amount
+		order.DiscountAmount = quote.DiscountAmount
+		order.CouponCode = coupon.Code
+	}
+	_, err = tx.Exec(`
+INSERT INTO membership_orders (id, order_no, user_id, product_id, amount, original_amount, discount_amount, coupon_code, pay_channel, order_source, status, paid_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'PENDING', NULL, ?, ?)`,
+		id, orderNo, userID, productID, order.Amount, order.OriginalAmount, order.DiscountAmount, nullableString(order.CouponCode), order.PayChannel, order.OrderSource, now, now,
 	)
 	if err != nil {
+		_ = tx.Rollback()
 		return model.MembershipOrderAdmin{}, err
 	}
-	return model.MembershipOrderAdmin{
-		ID:         id,
-		OrderNo:    orderNo,
-		UserID:     userID,
-		ProductID:  productID,
-		Amount:     price,
-		PayChannel: strings.ToUpper(payChannel),
-		Status:     "PENDING",
-		CreatedAt:  now.Format(time.RFC3339),
-	}, nil
+	if order.CouponCode != "" {
+		if _, err := tx.Exec(`
+INSERT INTO membership_coupon_redemptions
+(id, coupon_id, coupon_code, user_id, order_id, order_no, product_id, original_amount, discount_amount, amount, status, created_at, redeemed_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'RESERVED', ?, NULL, ?)`,
+			newID("cr"), coupon.ID, coupon.Code, userID, id, orderNo, productID,
+			order.OriginalAmount, order.DiscountAmount, order.Amount, now, now,
+		); err != nil {
+			_ = tx.Rollback()
+			return model.MembershipOrderAdmin{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return model.MembershipOrderAdmin{}, err
+	}
+	return order, nil
 }
 
 func (r *MySQLGrowthRepo) ListMembershipOrders(userID string, status string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error) {
@@ -5481,7 +5642,7 @@ func (r *MySQLGrowthRepo) ListMembershipOrders(userID string, status string, pag
 		return nil, 0, err
 	}
 	query := `
-SELECT id, order_no, user_id, product_id, amount, pay_channel, status, paid_at, created_at
+SELECT id, order_no, user_id, product_id, amount, original_amount, discount_amount, coupon_code, pay_channel, order_source, status, paid_at, created_at
 FROM membership_orders` + filter + `
 ORDER BY created_at DESC, id DESC
 LIMIT ? OFFSET ?`
@@ -5495,13 +5656,19 @@ LIMIT ? OFFSET ?`
 	for rows.Next() {
 		var item model.MembershipOrderAdmin
 		var paidAt, createdAt sql.NullTime
-		var orderNo, payChannel sql.NullString
-		if err := rows.Scan(&item.ID, &orderNo, &item.UserID, &item.ProductID, &item.Amount, &payChannel, &item.Status, &paidAt, &createdAt); err != nil {
+		var orderNo, payChannel, couponCode sql.NullString
+		var originalAmount sql.NullFloat64
+		if err := rows.Scan(&item.ID, &orderNo, &item.UserID, &item.ProductID, &item.Amount, &originalAmount, &item.DiscountAmount, &couponCode, &payChannel, &item.OrderSource, &item.Status, &paidAt, &createdAt); err != nil {
 			return nil, 0, err
 		}
 		if orderNo.Valid {
 			item.OrderNo = orderNo.String
 		}
+		item.OriginalAmount = item.Amount
+		if originalAmount.Valid {
+			item.OriginalAmount = originalAmount.Float64
+		}
+		item.CouponCode = couponCode.String
 		if payChannel.Valid {
 			item.PayChannel = payChannel.String
 		}
@@ -5516,6 +5683,27 @@ LIMIT ? OFFSET ?`
 	return items, total, nil
 }
 
+// GetMembershipOrder looks an order up the way HandlePaymentCallback does, by
+// order_no or id.
+func (r *MySQLGrowthRepo) GetMembershipOrder(orderNo string) (model.MembershipOrderAdmin, error) {
+	var item model.MembershipOrderAdmin
+	var storedOrderNo, payChannel sql.NullString
+	err := r.db.QueryRow(`
+SELECT id, order_no, user_id, product_id, amount, pay_channel, status
+FROM membership_orders
+WHERE order_no = ? OR id = ?
+LIMIT 1`, orderNo, orderNo).Scan(&item.ID, &storedOrderNo, &item.UserID, &item.ProductID, &item.Amount, &payChannel, &item.Status)
+	if err == sql.ErrNoRows {
+		return model.MembershipOrderAdmin{}, errors.New("order not found")
+	}
+	if err != nil {
+		return model.MembershipOrderAdmin{}, err
+	}
+	item.OrderNo = storedOrderNo.String
+	item.PayChannel = payChannel.String
+	return item, nil
+}
+
 func (r *MySQLGrowthRepo) TrackExperimentEvent(item model.ExperimentEvent) error {
 	metadataJSON, err := marshalExperimentMetadata(item.Metadata)
 	if err != nil {
@@ -6017,6 +6205,22 @@ func (r *MySQLGrowthRepo) AdminUpdateStockRecommendationStatus(id string, status
 }
 
 func (r *MySQLGrowthRepo) AdminGetQuantTopStocks(limit int, lookbackDays int) ([]model.StockQuantScore, error) {
+	return r.rankQuantTopStocks(time.Now(), limit, lookbackDays)
+}
+
+// AdminGetQuantTopStocksForTradeDate ranks the market as it stood at the
+// close of tradeDate: quotes, daily basics, money flows and news published
+// after that day are left out, so a pipeline rerun for an older trade date
+// scores the same inputs the original run saw.
+func (r *MySQLGrowthRepo) AdminGetQuantTopStocksForTradeDate(tradeDate string, limit int, lookbackDays int) ([]model.StockQuantScore, error) {
+	asOf, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(tradeDate), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade_date: %s", tradeDate)
+	}
+	return r.rankQuantTopStocks(asOf, limit, lookbackDays)
+}
+
+func (r *MySQLGrowthRepo) rankQuantTopStocks(asOf time.Time, limit int, lookbackDays int) ([]model.StockQuantScore, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -6033,8 +6237,8 @@ func (r *MySQLGrowthRepo) AdminGetQuantTopStocks(limit int, lookbackDays int) ([
 		lookbackDays = 365
 	}
 
-	sinceDate := time.Now().AddDate(0, 0, -(lookbackDays + 40))
-	quotesBySymbol, err := r.loadStockQuotesBySymbol(sinceDate)
+	sinceDate := asOf.AddDate(0, 0, -(lookbackDays + 40))
+	quotesBySymbol, err := r.loadStockQuotesBySymbol(sinceDate, asOf)
 	if err != nil {
 		return nil, err
 	}
@@ -6049,15 +6253,16 @@ func (r *MySQLGrowthRepo) AdminGetQuantTopStocks(limit int, lookbackDays int) ([
 	if len(items) == 0 {
 		return []model.StockQuantScore{}, nil
 	}
-	dailyBasicMap, err := r.loadLatestStockDailyBasics(sinceDate)
+	dailyBasicMap, err := r.loadLatestStockDailyBasics(sinceDate, asOf)
 	if err != nil {
 		return nil, err
 	}
-	moneyflowMap, err := r.loadLatestStockMoneyflows(sinceDate)
+	moneyflowMap, err := r.loadLatestStockMoneyflows(sinceDate, asOf)
 	if err != nil {
 		return nil, err
 	}
-	newsSignalMap, err := r.loadStockNewsSignals(time.Now().AddDate(0, 0, -14))
+	newsUntil := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location()).AddDate(0, 0, 1)
+	newsSignalMap, err := r.loadStockNewsSignals(newsUntil.AddDate(0, 0, -15), newsUntil)
 	if err != nil {
 		return nil, err
 	}
@@ -6263,7 +6468,7 @@ ORDER BY trade_date ASC, rank_no ASC`, sinceDate.Format("2006-01-02"), topN)
 	}
 
 	quoteSince := sinceDate.AddDate(0, 0, -20)
-	quotesBySymbol, err := r.loadStockQuotesBySymbol(quoteSince)
+	quotesBySymbol, err := r.loadStockQuotesBySymbol(quoteSince, time.Now())
 	if err != nil {
 		return model.StockQuantEvaluationSummary{}, nil, nil, nil, err
 	}
@@ -6981,6 +7186,7 @@ VALUES (?, ?, ?, ?, ?, 'UNREAD', ?)`)
 			failures = append(failures, model.AdminMessageSendFailure{UserID: userID, Reason: err.Error()})
 			continue
 		}
+		r.notifyUserMessage(userID)
 		sent++
 	}
 
@@ -7186,8 +7392,16 @@ LEFT JOIN invite_records ir ON ir.invitee_user_id = u.id` + filter
 	return result, nil
 }
 
-func (r *MySQLGrowthRepo) AdminUpdateUserStatus(id string, status string) error {
+// AdminUpdateUserStatus revokes the user's sessions on anything but ACTIVE;
+// operator is recorded as the sessions' revoked_by.
+func (r *MySQLGrowthRepo) AdminUpdateUserStatus(id string, status string, operator string) error {
 	_, err := r.db.Exec("UPDATE users SET status = ?, updated_at = ? WHERE id = ?", status, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(status) != "ACTIVE" && r.sessions != nil {
+		_, err = r.sessions.RevokeAllForUser(id, sessionRevokedBy(operator), "status_"+strings.ToLower(status))
+	}
 	return err
 }
 
@@ -7197,7 +7411,7 @@ func (r *MySQLGrowthRepo) AdminUpdateUserMemberLevel(id string, memberLevel stri
 }
 
 
-func (r *MySQLGrowthRepo) AdminResetUserPasswordHash(id string, passwordHash string) error {
+func (r *MySQLGrowthRepo) AdminResetUserPasswordHash(id string, passwordHash string, operator string) error {
 	result, err := r.db.Exec("UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?", passwordHash, time.Now(), id)
 	if err != nil {
 		return err
@@ -7209,7 +7423,19 @@ func (r *MySQLGrowthRepo) AdminResetUserPasswordHash(id string, passwordHash str
 	if affected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	if r.sessions != nil {
+		_, err = r.sessions.RevokeAllForUser(id, sessionRevokedBy(operator), "password_reset")
+	}
+	return err
+}
+
+// sessionRevokedBy falls back to "admin" for callers without an operator,
+// such as scripts, so revoked_by is never blank.
+func sessionRevokedBy(operator string) string {
+	if operator = strings.TrimSpace(operator); operator != "" {
+		return operator
+	}
+	return "admin"
 }
 
 func (r *MySQLGrowthRepo) AdminDashboardOverview() (model.AdminDashboardOverview, error) {
@@ -7255,12 +7481,24 @@ WHERE status = 'PAID' AND paid_at >= ? AND paid_at < ?`, startOfDay, endOfDay).S
 }
 
 func (r *MySQLGrowthRepo) AdminCreateOperationLog(module string, action string, targetType string, targetID string, operatorUserID string, beforeValue string, afterValue string, reason string) error {
-	_, err := r.db.Exec(`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id := newID("aol")
+	if _, err := tx.Exec(`
 INSERT INTO admin_operation_logs (id, module, action, target_type, target_id, operator_user_id, before_value, after_value, reason, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		newID("aol"), strings.ToUpper(module), strings.ToUpper(action), strings.ToUpper(targetType), targetID, operatorUserID, beforeValue, afterValue, reason, time.Now(),
-	)
-	return err
+		id, strings.ToUpper(module), strings.ToUpper(action), strings.ToUpper(targetType), targetID, operatorUserID, beforeValue, afterValue, reason, time.Now(),
+	); err != nil {
+		return err
+	}
+	if err := appendAuditLedgerEntry(tx, auditLedgerSourceOperationLog, id, auditLedgerActionCreate); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *MySQLGrowthRepo) AdminListOperationLogs(module string, action string, operatorUserID string, page int, pageSize int) ([]model.AdminOperationLog, int, error) {
@@ -7476,7 +7714,7 @@ func (r *MySQLGrowthRepo) AdminListMembershipOrders(status string, userID string
 		return nil, 0, err
 	}
 	query := `
-SELECT id, order_no, user_id, product_id, amount, pay_channel, status, paid_at, created_at
+SELECT id, order_no, user_id, product_id, amount, original_amount, discount_amount, coupon_code, pay_channel, order_source, status, paid_at, created_at
 FROM membership_orders` + filter + `
 ORDER BY created_at DESC, id DESC
 LIMIT ? OFFSET ?`
@@ -7490,13 +7728,19 @@ LIMIT ? OFFSET ?`
 	for rows.Next() {
 		var item model.MembershipOrderAdmin
 		var paidAt, createdAt sql.NullTime
-		var orderNo, payChannel sql.NullString
-		if err := rows.Scan(&item.ID, &orderNo, &item.UserID, &item.ProductID, &item.Amount, &payChannel, &item.Status, &paidAt, &createdAt); err != nil {
+		var orderNo, payChannel, couponCode sql.NullString
+		var originalAmount sql.NullFloat64
+		if err := rows.Scan(&item.ID, &orderNo, &item.UserID, &item.ProductID, &item.Amount, &originalAmount, &item.DiscountAmount, &couponCode, &payChannel, &item.OrderSource, &item.Status, &paidAt, &createdAt); err != nil {
 			return nil, 0, err
 		}
 		if orderNo.Valid {
 			item.OrderNo = orderNo.String
 		}
+		item.OriginalAmount = item.Amount
+		if originalAmount.Valid {
+			item.OriginalAmount = originalAmount.Float64
+		}
+		item.CouponCode = couponCode.String
 		if payChannel.Valid {
 			item.PayChannel = payChannel.String
 		}
@@ -7511,23 +7755,140 @@ LIMIT ? OFFSET ?`
 	return items, total, nil
 }
 
+// markMembershipOrderPaidTx settles an order: it flips the order to PAID, grants
+// or extends the VIP period, and books the success event, invite commissions
+// and coupon redemption. Gateway callbacks and manual admin settlement share it
+// so a paid order always carries the same side effects.
+func (r *MySQLGrowthRepo) markMembershipOrderPaidTx(tx *sql.Tx, orderID string, orderNo string, userID string, productID string, channel string, orderAmount float64, now time.Time) error {
+	if _, err := tx.Exec(`
+UPDATE membership_orders
+SET status = 'PAID', paid_at = ?, pay_channel = ?, updated_at = ?
+WHERE id = ?`, now, strings.ToUpper(channel), now, orderID); err != nil {
+		return err
+	}
+	var memberLevel sql.NullString
+	var durationDays sql.NullInt64
+	if err := tx.QueryRow(
+		"SELECT member_level, duration_days FROM membership_products WHERE id = ?",
+		productID,
+	).Scan(&memberLevel, &durationDays); err == nil {
+		level := strings.ToUpper(strings.TrimSpace(memberLevel.String))
+		days := int(durationDays.Int64)
+		if level != "" && days <= 0 {
+			days = 30
+		}
+		if level != "" {
+			var currentLevel string
+			var currentExpireAt sql.NullTime
+			if err := tx.QueryRow(
+				"SELECT member_level, vip_expire_at FROM users WHERE id = ? FOR UPDATE",
+				userID,
+			).Scan(&currentLevel, &currentExpireAt); err != nil {
+				return err
+			}
+			baseTime := now
+			if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(currentLevel)), "VIP") &&
+				currentExpireAt.Valid &&
+				currentExpireAt.Time.After(now) {
+				baseTime = currentExpireAt.Time
+			}
+			newExpireAt := baseTime
+			if days > 0 {
+				newExpireAt = baseTime.AddDate(0, 0, days)
+			}
+			startedAt := now
+			if _, err := tx.Exec(`
+UPDATE users
+SET member_level = ?,
+    vip_started_at = ?,
+    vip_expire_at = ?,
+    vip_remind_3d_at = NULL,
+    vip_remind_1d_at = NULL,
+    updated_at = ?
+WHERE id = ?`,
+				level,
+				startedAt,
+				newExpireAt,
+				now,
+				userID,
+			); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(
+				"UPDATE membership_orders SET vip_period_start = ?, vip_period_end = ? WHERE id = ?",
+				baseTime,
+				newExpireAt,
+				orderID,
+			); err != nil {
+				return err
+			}
+			title := "VIP会员开通成功"
+			content := fmt.Sprintf(
+				"订单%s支付成功，会员等级已更新为%s，到期时间：%s。",
+				strings.TrimSpace(orderNo),
+				level,
+				newExpireAt.Format("2006-01-02 15:04:05"),
+			)
+			if _, err := tx.Exec(`
+INSERT INTO messages (id, user_id, title, content, type, read_status, created_at)
+VALUES (?, ?, ?, ?, 'SYSTEM', 'UNREAD', ?)`,
+				newID("msg"),
+				userID,
+				title,
+				content,
+				now,
+			); err != nil {
+				return err
+			}
+			r.notifyUserMessage(userID)
+		}
+	}
+	if err := createExperimentSuccessEventTx(tx, strings.TrimSpace(orderNo), now); err != nil {
+		return err
+	}
+	if err := attributeInvitePaymentTx(tx, orderID, userID, orderAmount, now); err != nil {
+		return err
+	}
+	return redeemMembershipCoupon(tx, orderID, now)
+}
+
+// AdminUpdateMembershipOrderStatus lets staff settle or close an order by hand.
+// Marking an order PAID goes through the same settlement as a gateway callback;
+// a paid order only leaves PAID through the refund flow, which also takes back
+// the VIP time, the invite commissions and the coupon.
 func (r *MySQLGrowthRepo) AdminUpdateMembershipOrderStatus(id string, status string) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
-	var orderNo, previousStatus string
-	if err := tx.QueryRow("SELECT order_no, status FROM membership_orders WHERE id = ? FOR UPDATE", id).Scan(&orderNo, &previousStatus); err != nil {
+	var orderNo, userID, productID, payChannel, previousStatus string
+	var orderAmount float64
+	if err := tx.QueryRow("SELECT order_no, user_id, product_id, COALESCE(pay_channel, ''), amount, status FROM membership_orders WHERE id = ? FOR UPDATE", id).Scan(&orderNo, &userID, &productID, &payChannel, &orderAmount, &previousStatus); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
+	normalized := strings.ToUpper(strings.TrimSpace(status))
+	wasPaid := strings.ToUpper(strings.TrimSpace(previousStatus)) == "PAID"
+	if wasPaid && normalized != "PAID" {
+		_ = tx.Rollback()
+		return model.ErrMembershipOrderPaid
+	}
 	now := time.Now()
+	if normalized == "PAID" {
+		if !wasPaid {
+			if err := r.markMembershipOrderPaidTx(tx, id, orderNo, userID, productID, payChannel, orderAmount, now); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
+	}
 	if _, err := tx.Exec("UPDATE membership_orders SET status = ?, updated_at = ? WHERE id = ?", status, now, id); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
-	if strings.ToUpper(strings.TrimSpace(status)) == "PAID" && strings.ToUpper(strings.TrimSpace(previousStatus)) != "PAID" {
-		if err := createExperimentSuccessEventTx(tx, strings.TrimSpace(orderNo), now); err != nil {
+	if normalized == "CANCELED" || normalized == "FAILED" {
+		if err := releaseMembershipCoupon(tx, id, now); err != nil {
 			_ = tx.Rollback()
 			return err
 		}
@@ -7704,6 +8065,7 @@ func (r *MySQLGrowthRepo) AdminGetExperimentAnalyticsSummary(days int) (model.Ad
 		DeviceBreakdown:     make([]model.AdminExperimentAnalyticsDeviceItem, 0),
 		UserStageBreakdown:  make([]model.AdminExperimentAnalyticsUserStageItem, 0),
 		VariantDailyTrend:   make([]model.AdminExperimentAnalyticsVariantTrendPoint, 0),
+		CouponBreakdown:     make([]model.AdminExperimentAnalyticsCouponItem, 0),
 	}
 	var lastEventAt sql.NullTime
 	if err := r.db.QueryRow(`
@@ -7902,16 +8264,17 @@ ORDER BY metric_date ASC`, since)
 	}
 
 	payRows, err := r.db.Query(`
-SELECT pay_channel, payment_success_count, renewal_success_count, last_event_at
+SELECT pay_channel, payment_success_count, renewal_success_count, renewal_failed_count, last_event_at
 FROM (
 	SELECT
 		COALESCE(NULLIF(JSON_UNQUOTE(JSON_EXTRACT(metadata_json, '$.pay_channel')), ''), 'UNKNOWN') AS pay_channel,
 		COALESCE(SUM(CASE WHEN event_type = 'PAYMENT_SUCCESS' THEN 1 ELSE 0 END), 0) AS payment_success_count,
 		COALESCE(SUM(CASE WHEN event_type = 'RENEWAL_SUCCESS' THEN 1 ELSE 0 END), 0) AS renewal_success_count,
+		COALESCE(SUM(CASE WHEN event_type = 'RENEWAL_FAILED' THEN 1 ELSE 0 END), 0) AS renewal_failed_count,
 		MAX(created_at) AS last_event_at
 	FROM experiment_events
 	WHERE created_at >= ?
-	  AND event_type IN ('PAYMENT_SUCCESS', 'RENEWAL_SUCCESS')
+	  AND event_type IN ('PAYMENT_SUCCESS', 'RENEWAL_SUCCESS', 'RENEWAL_FAILED')
 	GROUP BY COALESCE(NULLIF(JSON_UNQUOTE(JSON_EXTRACT(metadata_json, '$.pay_channel')), ''), 'UNKNOWN')
 ) pay_summary
 ORDER BY (payment_success_count + renewal_success_count) DESC, last_event_at DESC`, since)
@@ -7927,6 +8290,7 @@ ORDER BY (payment_success_count + renewal_success_count) DESC, last_event_at DES
 			&item.PayChannel,
 			&item.PaymentSuccessCount,
 			&item.RenewalSuccessCount,
+			&item.RenewalFailedCount,
 			&itemLastEventAt,
 		); err != nil {
 			return summary, err
@@ -8064,6 +8428,51 @@ LIMIT 360`, since)
 		return summary, err
 	}
 
+	couponRows, err := r.db.Query(`
+SELECT
+	o.coupon_code,
+	COALESCE(a.experiment_key, '') AS experiment_key,
+	COALESCE(a.variant_key, '') AS variant_key,
+	COUNT(*) AS paid_order_count,
+	COALESCE(SUM(COALESCE(o.original_amount, o.amount)), 0) AS original_amount,
+	COALESCE(SUM(o.discount_amount), 0) AS discount_amount,
+	COALESCE(SUM(o.amount), 0) AS revenue,
+	MAX(o.paid_at) AS last_paid_at
+FROM membership_orders o
+LEFT JOIN experiment_order_attributions a ON a.order_no = o.order_no
+WHERE o.status = 'PAID' AND o.paid_at >= ? AND o.coupon_code IS NOT NULL AND o.coupon_code <> ''
+GROUP BY o.coupon_code, COALESCE(a.experiment_key, ''), COALESCE(a.variant_key, '')
+ORDER BY revenue DESC, paid_order_count DESC
+LIMIT 100`, since)
+	if err != nil {
+		return summary, err
+	}
+	defer couponRows.Close()
+
+	for couponRows.Next() {
+		var item model.AdminExperimentAnalyticsCouponItem
+		var lastPaidAt sql.NullTime
+		if err := couponRows.Scan(
+			&item.CouponCode,
+			&item.ExperimentKey,
+			&item.VariantKey,
+			&item.PaidOrderCount,
+			&item.OriginalAmount,
+			&item.DiscountAmount,
+			&item.Revenue,
+			&lastPaidAt,
+		); err != nil {
+			return summary, err
+		}
+		if lastPaidAt.Valid {
+			item.LastPaidAt = lastPaidAt.Time.Format(time.RFC3339)
+		}
+		summary.CouponBreakdown = append(summary.CouponBreakdown, item)
+	}
+	if err := couponRows.Err(); err != nil {
+		return summary, err
+	}
+
 	return summary, nil
 }
 
@@ -8332,11 +8741,15 @@ func (r *MySQLGrowthRepo) AdminCreateDataSource(item model.DataSource) (string,
 		return "", errors.New("data source already exists")
 	}
 
+	sealedConfig, err := r.sealDataSourceConfig(configKey, item.Config)
+	if err != nil {
+		return "", err
+	}
 	payloadBytes, err := json.Marshal(map[string]interface{}{
 		"name":        item.Name,
 		"source_type": strings.ToUpper(strings.TrimSpace(item.SourceType)),
 		"status":      strings.ToUpper(strings.TrimSpace(item.Status)),
-		"config":      item.Config,
+		"config":      sealedConfig,
 	})
 	if err != nil {
 		return "", err
@@ -8407,7 +8820,10 @@ func (r *MySQLGrowthRepo) AdminUpdateDataSource(sourceKey string, item model.Dat
 	if status == "" {
 		status = "ACTIVE"
 	}
-	mergedConfig := mergeDataSourceConfigMap(existingPayload.Config, item.Config)
+	mergedConfig, err := r.sealDataSourceConfig(configKey, mergeDataSourceConfigMap(existingPayload.Config, item.Config))
+	if err != nil {
+		return err
+	}
 	payloadBytes, err := json.Marshal(map[string]interface{}{
 		"name":        name,
 		"source_type": sourceType,
@@ -8520,7 +8936,7 @@ func (r *MySQLGrowthRepo) AdminCheckDataSourceHealth(sourceKey string) (model.Da
 			} else {
 				tushareToken := ""
 				if isTushare {
-					tushareToken = parseDataSourceStringConfig(item.Config, "token", "api_token", "tushare_token")
+					tushareToken = r.resolveDataSourceToken(item)
 					if strings.TrimSpace(tushareToken) == "" {
 						tushareToken = strings.TrimSpace(os.Getenv("TUSHARE_TOKEN"))
 					}
@@ -9391,7 +9807,11 @@ func (r *MySQLGrowthRepo) AdminUpsertSystemConfig(configKey string, configValue
 	if operator == "" {
 		operator = "admin_unknown"
 	}
-	_, err := r.db.Exec(`
+	configValue, err := r.sealSystemConfigValue(configKey, configValue)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(`
 INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
 VALUES (?, ?, ?, ?, ?, ?)
 ON DUPLICATE KEY UPDATE
@@ -9477,7 +9897,7 @@ LIMIT ? OFFSET ?`
 	return items, total, nil
 }
 
-func (r *MySQLGrowthRepo) AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string) (string, error) {
+func (r *MySQLGrowthRepo) AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string, priority string) (string, error) {
 	module = strings.ToUpper(strings.TrimSpace(module))
 	if module == "" {
 		return "", errors.New("module is required")
@@ -9515,6 +9935,7 @@ VALUES (?, ?, ?, ?, ?, 'PENDING', ?, ?, ?, ?)`,
 		"内容已提交审核",
 		"模块 "+module+" 的目标 "+targetID+" 已提交审核",
 	)
+	_ = r.openReviewSLA(model.ReviewSLASubjectReviewTask, id, module, targetID, priority, reviewerID, now)
 	return id, nil
 }
 
@@ -9536,6 +9957,7 @@ func (r *MySQLGrowthRepo) AdminAssignReviewTask(reviewID string, reviewerID stri
 		"审核任务已分配",
 		"你有新的审核任务 "+reviewID,
 	)
+	_ = r.assignReviewSLA(model.ReviewSLASubjectReviewTask, reviewID, reviewerID)
 	return nil
 }
 
@@ -9609,6 +10031,7 @@ WHERE id = ?`,
 			"review_status": status,
 		},
 	})
+	_ = r.resolveReviewSLA(model.ReviewSLASubjectReviewTask, reviewID, reviewerID, time.Now())
 	return nil
 }
 
@@ -10216,12 +10639,15 @@ func (r *MySQLGrowthRepo) AdminCreateWorkflowMessage(reviewID string, targetID s
 	if senderID != "" {
 		senderVal = senderID
 	}
-	_, err := r.db.Exec(`
+	if _, err := r.db.Exec(`
 INSERT INTO workflow_messages (id, review_id, target_id, module, receiver_id, sender_id, event_type, title, content, is_read, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?)`,
 		newID("wm"), reviewVal, targetID, module, receiverVal, senderVal, eventType, title, content, time.Now(),
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	r.notifyWorkflowMessage(receiverID)
+	return nil
 }
 
 func (r *MySQLGrowthRepo) AdminGetWorkflowMetrics(module string, receiverID string) (model.WorkflowMetrics, error) {
@@ -10878,6 +11304,10 @@ WHERE member_level LIKE 'VIP%%'
   AND vip_expire_at > ?
   AND vip_expire_at <= ?
   AND %s IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM membership_auto_renewals ar
+    WHERE ar.user_id = users.id AND ar.status IN ('ACTIVE', 'PAST_DUE')
+  )
 ORDER BY vip_expire_at ASC
 LIMIT 500`, remindColumn)
 
@@ -10938,6 +11368,7 @@ LIMIT 500`, remindColumn)
 		if err := insertSystemMessageTx(tx, item.UserID, title, content, now); err != nil {
 			return count, err
 		}
+		r.notifyUserMessage(item.UserID)
 		count++
 	}
 	return count, nil
@@ -11009,12 +11440,13 @@ WHERE id = ? AND member_level LIKE 'VIP%'`,
 		if err := insertSystemMessageTx(tx, item.UserID, title, content, now); err != nil {
 			return count, err
 		}
+		r.notifyUserMessage(item.UserID)
 		count++
 	}
 	return count, nil
 }
 
-func insertSystemMessageTx(tx *sql.Tx, userID string, title string, content string, now time.Time) error {
+func insertSystemMessageTx(tx sqlExecer, userID string, title string, content string, now time.Time) error {
 	_, err := tx.Exec(`
 INSERT INTO messages (id, user_id, title, content, type, read_status, created_at)
 VALUES (?, ?, ?, ?, 'SYSTEM', 'UNREAD', ?)`,
@@ -12577,17 +13009,17 @@ ON DUPLICATE KEY UPDATE
 	return affected, nil
 }
 
-func (r *MySQLGrowthRepo) loadLatestStockDailyBasics(sinceDate time.Time) (map[string]stockDailyBasicPoint, error) {
+func (r *MySQLGrowthRepo) loadLatestStockDailyBasics(sinceDate time.Time, untilDate time.Time) (map[string]stockDailyBasicPoint, error) {
 	rows, err := r.db.Query(`
 SELECT t.symbol, t.trade_date, t.turnover_rate, t.volume_ratio, t.pe_ttm, t.pb, t.total_mv, t.circ_mv, t.source_key
 FROM stock_daily_basic t
 INNER JOIN (
   SELECT symbol, MAX(trade_date) AS latest_trade_date
   FROM stock_daily_basic
-  WHERE trade_date >= ?
+  WHERE trade_date >= ? AND trade_date <= ?
   GROUP BY symbol
 ) latest
-ON latest.symbol = t.symbol AND latest.latest_trade_date = t.trade_date`, sinceDate.Format("2006-01-02"))
+ON latest.symbol = t.symbol AND latest.latest_trade_date = t.trade_date`, sinceDate.Format("2006-01-02"), untilDate.Format("2006-01-02"))
 	if err != nil {
 		if isTableNotFoundError(err) {
 			return map[string]stockDailyBasicPoint{}, nil
@@ -12638,17 +13070,17 @@ ON latest.symbol = t.symbol AND latest.latest_trade_date = t.trade_date`, sinceD
 	return result, nil
 }
 
-func (r *MySQLGrowthRepo) loadLatestStockMoneyflows(sinceDate time.Time) (map[string]stockMoneyflowPoint, error) {
+func (r *MySQLGrowthRepo) loadLatestStockMoneyflows(sinceDate time.Time, untilDate time.Time) (map[string]stockMoneyflowPoint, error) {
 	rows, err := r.db.Query(`
 SELECT t.symbol, t.trade_date, t.net_mf_amount, t.buy_lg_amount, t.sell_lg_amount, t.buy_elg_amount, t.sell_elg_amount, t.source_key
 FROM stock_moneyflow_daily t
 INNER JOIN (
   SELECT symbol, MAX(trade_date) AS latest_trade_date
   FROM stock_moneyflow_daily
-  WHERE trade_date >= ?
+  WHERE trade_date >= ? AND trade_date <= ?
   GROUP BY symbol
 ) latest
-ON latest.symbol = t.symbol AND latest.latest_trade_date = t.trade_date`, sinceDate.Format("2006-01-02"))
+ON latest.symbol = t.symbol AND latest.latest_trade_date = t.trade_date`, sinceDate.Format("2006-01-02"), untilDate.Format("2006-01-02"))
 	if err != nil {
 		if isTableNotFoundError(err) {
 			return map[string]stockMoneyflowPoint{}, nil
@@ -12696,14 +13128,14 @@ ON latest.symbol = t.symbol AND latest.latest_trade_date = t.trade_date`, sinceD
 	return result, nil
 }
 
-func (r *MySQLGrowthRepo) loadStockNewsSignals(sinceTime time.Time) (map[string]stockNewsSignal, error) {
+func (r *MySQLGrowthRepo) loadStockNewsSignals(sinceTime time.Time, untilTime time.Time) (map[string]stockNewsSignal, error) {
 	rows, err := r.db.Query(`
 SELECT symbol,
        COUNT(*) AS heat,
        SUM(CASE WHEN sentiment = 'POSITIVE' THEN 1 ELSE 0 END) AS positive_cnt
 FROM stock_news_raw
-WHERE published_at >= ?
-GROUP BY symbol`, sinceTime)
+WHERE published_at >= ? AND published_at < ?
+GROUP BY symbol`, sinceTime, untilTime)
 	if err != nil {
 		if isTableNotFoundError(err) {
 			return map[string]stockNewsSignal{}, nil
@@ -12811,12 +13243,12 @@ ON DUPLICATE KEY UPDATE
 	return nil
 }
 
-func (r *MySQLGrowthRepo) loadStockQuotesBySymbol(sinceDate time.Time) (map[string][]stockQuoteCandle, error) {
+func (r *MySQLGrowthRepo) loadStockQuotesBySymbol(sinceDate time.Time, untilDate time.Time) (map[string][]stockQuoteCandle, error) {
 	rows, err := r.db.Query(`
 SELECT symbol, trade_date, open_price, high_price, low_price, close_price, prev_close_price, volume, turnover
 FROM stock_market_quotes
-WHERE trade_date >= ?
-ORDER BY symbol ASC, trade_date ASC`, sinceDate.Format("2006-01-02"))
+WHERE trade_date >= ? AND trade_date <= ?
+ORDER BY symbol ASC, trade_date ASC`, sinceDate.Format("2006-01-02"), untilDate.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,507 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const newsScheduledPublishBatchSize = 50
+
+// newsArticleDiffMaxCells caps the line diff table; larger contents fall back
+// to "everything removed, everything added" instead of a full LCS.
+const newsArticleDiffMaxCells = 4000000
+
+// insertNewsArticleRevisionTx snapshots the article as it stands inside tx.
+// Callers lock or create the article row first so revision numbers stay
+// sequential.
+func insertNewsArticleRevisionTx(tx *sql.Tx, articleID string, action string, restoredFrom int, editorID string, now time.Time) error {
+	var revisionNo int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(revision_no), 0) + 1 FROM news_article_revisions WHERE article_id = ?", articleID).Scan(&revisionNo); err != nil {
+		return err
+	}
+	editorID = strings.TrimSpace(editorID)
+	if editorID == "" {
+		editorID = "admin_unknown"
+	}
+	var restoredFromValue interface{}
+	if restoredFrom > 0 {
+		restoredFromValue = restoredFrom
+	}
+	_, err := tx.Exec(`
+INSERT INTO news_article_revisions (id, article_id, revision_no, action, restored_from, category_id, title, summary, content, cover_url, visibility, status, editor_id, created_at)
+SELECT ?, id, ?, ?, ?, category_id, title, summary, content, cover_url, visibility, status, ?, ?
+FROM news_articles
+WHERE id = ?`,
+		newID("nar"), revisionNo, action, restoredFromValue, editorID, now, articleID,
+	)
+	return err
+}
+
+// AdminScheduleNewsArticle holds an unpublished article until publishAt, when
+// the news_scheduled_publish job publishes it. A non-zero embargoUntil is kept
+// on the article so it stays hidden from users until then.
+func (r *MySQLGrowthRepo) AdminScheduleNewsArticle(id string, publishAt time.Time, embargoUntil time.Time) error {
+	var embargoValue interface{}
+	if !embargoUntil.IsZero() {
+		embargoValue = embargoUntil
+	}
+	result, err := r.db.Exec(`
+UPDATE news_articles
+SET status = 'SCHEDULED', scheduled_publish_at = ?, embargo_until = ?, updated_at = ?
+WHERE id = ? AND status <> 'PUBLISHED'`, publishAt, embargoValue, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return r.newsArticleStatusUpdateResult(id, result)
+}
+
+// AdminCancelNewsArticleSchedule moves a scheduled article back to DRAFT.
+func (r *MySQLGrowthRepo) AdminCancelNewsArticleSchedule(id string) error {
+	result, err := r.db.Exec(`
+UPDATE news_articles
+SET status = 'DRAFT', scheduled_publish_at = NULL, updated_at = ?
+WHERE id = ? AND status = 'SCHEDULED'`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return r.newsArticleStatusUpdateResult(id, result)
+}
+
+func (r *MySQLGrowthRepo) newsArticleStatusUpdateResult(id string, result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	var exists int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM news_articles WHERE id = ?", id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return sql.ErrNoRows
+	}
+	return model.ErrNewsArticleStatusConflict
+}
+
+// AdminRunNewsScheduledPublish publishes scheduled articles that are due and
+// then messages the NEWS subscribers of each article's category. The
+// conditional update lets only one replica publish a given article, and
+// marks it for notification. Embargoed articles are notified once their
+// embargo lifts. Subscribers who could not be messaged are retried on the
+// next pass, and the pass reports them as an error.
+func (r *MySQLGrowthRepo) AdminRunNewsScheduledPublish() (string, error) {
+	now := time.Now()
+	rows, err := r.db.Query(`
+SELECT id
+FROM news_articles
+WHERE status = 'SCHEDULED' AND scheduled_publish_at <= ?
+ORDER BY scheduled_publish_at ASC
+LIMIT ?`, now, newsScheduledPublishBatchSize)
+	if err != nil {
+		return "", err
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return "", err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	published := 0
+	for _, id := range ids {
+		result, err := r.db.Exec(`
+UPDATE news_articles
+SET status = 'PUBLISHED', published_at = scheduled_publish_at, scheduled_publish_at = NULL, subscriber_notify_status = 'PENDING', updated_at = ?
+WHERE id = ? AND status = 'SCHEDULED'`, now, id)
+		if err != nil {
+			return fmt.Sprintf("published=%d notified=0", published), err
+		}
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			published++
+		}
+	}
+
+	notified, err := r.notifyPendingNewsArticles(now)
+	return fmt.Sprintf("published=%d notified=%d", published, notified), err
+}
+
+// notifyPendingNewsArticles messages subscribers about published articles
+// still waiting for it whose embargo has lifted. An article is marked DONE
+// only once every subscriber has been messaged.
+func (r *MySQLGrowthRepo) notifyPendingNewsArticles(now time.Time) (int, error) {
+	rows, err := r.db.Query(`
+SELECT id, category_id, title, summary, visibility
+FROM news_articles
+WHERE status = 'PUBLISHED' AND subscriber_notify_status = 'PENDING'
+  AND (embargo_until IS NULL OR embargo_until <= ?)
+ORDER BY published_at ASC
+LIMIT ?`, now, newsScheduledPublishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	items := make([]model.NewsArticle, 0)
+	for rows.Next() {
+		var item model.NewsArticle
+		var summary sql.NullString
+		if err := rows.Scan(&item.ID, &item.CategoryID, &item.Title, &summary, &item.Visibility); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		item.Summary = summary.String
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	failures := make([]string, 0)
+	for _, item := range items {
+		count, failed, err := r.notifyNewsCategorySubscribers(item, now)
+		notified += count
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %d subscribers: %v", item.ID, failed, err))
+			continue
+		}
+		if _, err := r.db.Exec(
+			"UPDATE news_articles SET subscriber_notify_status = 'DONE' WHERE id = ? AND subscriber_notify_status = 'PENDING'",
+			item.ID,
+		); err != nil {
+			return notified, err
+		}
+	}
+	if len(failures) > 0 {
+		return notified, fmt.Errorf("notify subscribers: %s", strings.Join(failures, "; "))
+	}
+	return notified, nil
+}
+
+// notifyNewsCategorySubscribers messages users with an ACTIVE NEWS
+// subscription whose scope is the category id, its slug, ALL or empty. VIP
+// articles only reach subscribers who are VIP right now. Each message is
+// recorded in news_article_notifications, so a retry skips subscribers who
+// already have one. A failure for one subscriber does not stop the rest; it
+// returns how many were messaged, how many failed and the first error.
+func (r *MySQLGrowthRepo) notifyNewsCategorySubscribers(article model.NewsArticle, now time.Time) (int, int, error) {
+	rows, err := r.db.Query(`
+SELECT DISTINCT s.user_id
+FROM subscriptions s
+WHERE s.type = 'NEWS'
+  AND s.status = 'ACTIVE'
+  AND (s.scope IS NULL OR s.scope IN ('', 'ALL', ?) OR s.scope = (SELECT nc.slug FROM news_categories nc WHERE nc.id = ?))`,
+		article.CategoryID, article.CategoryID,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	userIDs := make([]string, 0)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	title := truncateByRunes("资讯更新："+article.Title, 128)
+	content := strings.TrimSpace(article.Summary)
+	if content == "" {
+		content = article.Title
+	}
+	count := 0
+	failed := 0
+	var firstErr error
+	for _, userID := range userIDs {
+		sent, err := r.notifyNewsSubscriber(article, userID, title, content, now)
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if sent {
+			r.notifyUserMessage(userID)
+			count++
+		}
+	}
+	return count, failed, firstErr
+}
+
+// notifyNewsSubscriber messages one subscriber about article unless an
+// earlier pass already did. It reports whether a message was sent.
+func (r *MySQLGrowthRepo) notifyNewsSubscriber(article model.NewsArticle, userID string, title string, content string, now time.Time) (bool, error) {
+	if strings.EqualFold(article.Visibility, "VIP") {
+		isVIP, err := r.isVIPUser(userID)
+		if err != nil || !isVIP {
+			return false, err
+		}
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	messageID := newID("msg")
+	result, err := tx.Exec(
+		"INSERT IGNORE INTO news_article_notifications (article_id, user_id, message_id, created_at) VALUES (?, ?, ?, ?)",
+		article.ID, userID, messageID, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	if _, err := tx.Exec(`
+INSERT INTO messages (id, user_id, title, content, type, read_status, created_at)
+VALUES (?, ?, ?, ?, 'NEWS', 'UNREAD', ?)`, messageID, userID, title, content, now); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *MySQLGrowthRepo) AdminListNewsArticleRevisions(articleID string, page int, pageSize int) ([]model.NewsArticleRevision, int, error) {
+	offset := (page - 1) * pageSize
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM news_article_revisions WHERE article_id = ?", articleID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`
+SELECT id, article_id, revision_no, action, restored_from, category_id, title, summary, '', cover_url, visibility, status, editor_id, created_at
+FROM news_article_revisions
+WHERE article_id = ?
+ORDER BY revision_no DESC
+LIMIT ? OFFSET ?`, articleID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.NewsArticleRevision, 0)
+	for rows.Next() {
+		item, err := scanNewsArticleRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminGetNewsArticleRevision(articleID string, revisionNo int) (model.NewsArticleRevision, error) {
+	item, err := scanNewsArticleRevision(r.db.QueryRow(`
+SELECT id, article_id, revision_no, action, restored_from, category_id, title, summary, content, cover_url, visibility, status, editor_id, created_at
+FROM news_article_revisions
+WHERE article_id = ? AND revision_no = ?`, articleID, revisionNo))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NewsArticleRevision{}, model.ErrNewsArticleRevisionNotFound
+	}
+	return item, err
+}
+
+// AdminDiffNewsArticleRevisions compares fromRevision to toRevision. A
+// fromRevision of 0 means the revision before toRevision; revision 1 is
+// compared against an empty article.
+func (r *MySQLGrowthRepo) AdminDiffNewsArticleRevisions(articleID string, fromRevision int, toRevision int) (model.NewsArticleRevisionDiff, error) {
+	to, err := r.AdminGetNewsArticleRevision(articleID, toRevision)
+	if err != nil {
+		return model.NewsArticleRevisionDiff{}, err
+	}
+	if fromRevision <= 0 {
+		fromRevision = toRevision - 1
+	}
+	from := model.NewsArticleRevision{ArticleID: articleID}
+	if fromRevision > 0 {
+		from, err = r.AdminGetNewsArticleRevision(articleID, fromRevision)
+		if err != nil {
+			return model.NewsArticleRevisionDiff{}, err
+		}
+	}
+	return diffNewsArticleRevisions(from, to), nil
+}
+
+// AdminRestoreNewsArticleRevision copies an old revision's content back onto
+// the article and records that as a new RESTORE revision. The article status
+// is left alone so restoring never publishes or unpublishes.
+func (r *MySQLGrowthRepo) AdminRestoreNewsArticleRevision(articleID string, revisionNo int, editorID string) (model.NewsArticleRevision, error) {
+	source, err := r.AdminGetNewsArticleRevision(articleID, revisionNo)
+	if err != nil {
+		return model.NewsArticleRevision{}, err
+	}
+	now := time.Now()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.NewsArticleRevision{}, err
+	}
+	var locked string
+	if err := tx.QueryRow("SELECT id FROM news_articles WHERE id = ? FOR UPDATE", articleID).Scan(&locked); err != nil {
+		_ = tx.Rollback()
+		return model.NewsArticleRevision{}, err
+	}
+	if _, err := tx.Exec(`
+UPDATE news_articles
+SET category_id = ?, title = ?, summary = ?, content = ?, cover_url = ?, visibility = ?, updated_at = ?
+WHERE id = ?`, source.CategoryID, source.Title, source.Summary, source.Content, nullableString(source.CoverURL), source.Visibility, now, articleID); err != nil {
+		_ = tx.Rollback()
+		return model.NewsArticleRevision{}, err
+	}
+	if err := insertNewsArticleRevisionTx(tx, articleID, model.NewsArticleRevisionRestore, revisionNo, editorID, now); err != nil {
+		_ = tx.Rollback()
+		return model.NewsArticleRevision{}, err
+	}
+	var latest int
+	if err := tx.QueryRow("SELECT MAX(revision_no) FROM news_article_revisions WHERE article_id = ?", articleID).Scan(&latest); err != nil {
+		_ = tx.Rollback()
+		return model.NewsArticleRevision{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.NewsArticleRevision{}, err
+	}
+	return r.AdminGetNewsArticleRevision(articleID, latest)
+}
+
+func scanNewsArticleRevision(scanner interface{ Scan(dest ...any) error }) (model.NewsArticleRevision, error) {
+	var item model.NewsArticleRevision
+	var restoredFrom sql.NullInt64
+	var summary, content, coverURL sql.NullString
+	var createdAt time.Time
+	if err := scanner.Scan(
+		&item.ID, &item.ArticleID, &item.RevisionNo, &item.Action, &restoredFrom, &item.CategoryID, &item.Title, &summary, &content,
+		&coverURL, &item.Visibility, &item.Status, &item.EditorID, &createdAt,
+	); err != nil {
+		return model.NewsArticleRevision{}, err
+	}
+	item.RestoredFrom = int(restoredFrom.Int64)
+	item.Summary = summary.String
+	item.Content = content.String
+	item.CoverURL = coverURL.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	return item, nil
+}
+
+func diffNewsArticleRevisions(from model.NewsArticleRevision, to model.NewsArticleRevision) model.NewsArticleRevisionDiff {
+	diff := model.NewsArticleRevisionDiff{
+		ArticleID:    to.ArticleID,
+		FromRevision: from.RevisionNo,
+		ToRevision:   to.RevisionNo,
+		FromEditorID: from.EditorID,
+		ToEditorID:   to.EditorID,
+		FromAt:       from.CreatedAt,
+		ToAt:         to.CreatedAt,
+		Fields:       make([]model.NewsArticleRevisionFieldDiff, 0),
+	}
+	scalar := []struct {
+		field  string
+		before string
+		after  string
+	}{
+		{"category_id", from.CategoryID, to.CategoryID},
+		{"title", from.Title, to.Title},
+		{"summary", from.Summary, to.Summary},
+		{"cover_url", from.CoverURL, to.CoverURL},
+		{"visibility", from.Visibility, to.Visibility},
+		{"status", from.Status, to.Status},
+	}
+	for _, item := range scalar {
+		if item.before != item.after {
+			diff.Fields = append(diff.Fields, model.NewsArticleRevisionFieldDiff{Field: item.field, Before: item.before, After: item.after})
+		}
+	}
+	if from.Content != to.Content {
+		diff.Fields = append(diff.Fields, model.NewsArticleRevisionFieldDiff{Field: "content", Lines: diffNewsArticleLines(from.Content, to.Content)})
+	}
+	return diff
+}
+
+// diffNewsArticleLines is a longest-common-subsequence line diff. The shared
+// head and tail are trimmed first so typical small edits stay cheap.
+func diffNewsArticleLines(before string, after string) []model.NewsArticleRevisionDiffLine {
+	a := splitNewsArticleLines(before)
+	b := splitNewsArticleLines(after)
+	head := 0
+	for head < len(a) && head < len(b) && a[head] == b[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(a)-head && tail < len(b)-head && a[len(a)-1-tail] == b[len(b)-1-tail] {
+		tail++
+	}
+	result := make([]model.NewsArticleRevisionDiffLine, 0, len(a)+len(b))
+	for _, line := range a[:head] {
+		result = append(result, model.NewsArticleRevisionDiffLine{Op: "=", Text: line})
+	}
+	midA := a[head : len(a)-tail]
+	midB := b[head : len(b)-tail]
+	if (len(midA)+1)*(len(midB)+1) > newsArticleDiffMaxCells {
+		for _, line := range midA {
+			result = append(result, model.NewsArticleRevisionDiffLine{Op: "-", Text: line})
+		}
+		for _, line := range midB {
+			result = append(result, model.NewsArticleRevisionDiffLine{Op: "+", Text: line})
+		}
+	} else {
+		// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				result = append(result, model.NewsArticleRevisionDiffLine{Op: "=", Text: midA[i]})
+				i++
+				j++
+			case j < len(midB) && (i == len(midA) || lcs[i][j+1] > lcs[i+1][j]):
+				result = append(result, model.NewsArticleRevisionDiffLine{Op: "+", Text: midB[j]})
+				j++
+			default:
+				result = append(result, model.NewsArticleRevisionDiffLine{Op: "-", Text: midA[i]})
+				i++
+			}
+		}
+	}
+	for _, line := range a[len(a)-tail:] {
+		result = append(result, model.NewsArticleRevisionDiffLine{Op: "=", Text: line})
+	}
+	return result
+}
+
+func splitNewsArticleLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func (r *InMemoryGrowthRepo) AdminScheduleNewsArticle(id string, publishAt time.Time, embargoUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if embargoUntil.IsZero() {
+		delete(r.newsEmbargoes, id)
+	} else {
+		r.newsEmbargoes[id] = embargoUntil
+	}
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminCancelNewsArticleSchedule(id string) error {
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRunNewsScheduledPublish() (string, error) {
+	return "published=0 notified=0", nil
+}
+
+func (r *InMemoryGrowthRepo) sampleNewsArticleRevisions(articleID string) []model.NewsArticleRevision {
+	return []model.NewsArticleRevision{
+		{
+			ID: "nar_002", ArticleID: articleID, RevisionNo: 2, Action: model.NewsArticleRevisionUpdate, CategoryID: "nc_001",
+			Title: "A股盘前观察", Summary: "示例摘要", Content: "示例正文\n补充：关注北向资金", Visibility: "PUBLIC", Status: "PUBLISHED",
+			EditorID: "admin_001", CreatedAt: "2026-02-25T08:40:00+08:00",
+		},
+		{
+			ID: "nar_001", ArticleID: articleID, RevisionNo: 1, Action: model.NewsArticleRevisionCreate, CategoryID: "nc_001",
+			Title: "A股盘前速览", Summary: "示例摘要", Content: "示例正文", Visibility: "PUBLIC", Status: "DRAFT",
+			EditorID: "admin_001", CreatedAt: "2026-02-25T08:00:00+08:00",
+		},
+	}
+}
+
+func (r *InMemoryGrowthRepo) AdminListNewsArticleRevisions(articleID string, page int, pageSize int) ([]model.NewsArticleRevision, int, error) {
+	items := r.sampleNewsArticleRevisions(articleID)
+	for i := range items {
+		items[i].Content = ""
+	}
+	return items, len(items), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetNewsArticleRevision(articleID string, revisionNo int) (model.NewsArticleRevision, error) {
+	for _, item := range r.sampleNewsArticleRevisions(articleID) {
+		if item.RevisionNo == revisionNo {
+			return item, nil
+		}
+	}
+	return model.NewsArticleRevision{}, model.ErrNewsArticleRevisionNotFound
+}
+
+func (r *InMemoryGrowthRepo) AdminDiffNewsArticleRevisions(articleID string, fromRevision int, toRevision int) (model.NewsArticleRevisionDiff, error) {
+	to, err := r.AdminGetNewsArticleRevision(articleID, toRevision)
+	if err != nil {
+		return model.NewsArticleRevisionDiff{}, err
+	}
+	if fromRevision <= 0 {
+		fromRevision = toRevision - 1
+	}
+	from := model.NewsArticleRevision{ArticleID: articleID}
+	if fromRevision > 0 {
+		from, err = r.AdminGetNewsArticleRevision(articleID, fromRevision)
+		if err != nil {
+			return model.NewsArticleRevisionDiff{}, err
+		}
+	}
+	return diffNewsArticleRevisions(from, to), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRestoreNewsArticleRevision(articleID string, revisionNo int, editorID string) (model.NewsArticleRevision, error) {
+	source, err := r.AdminGetNewsArticleRevision(articleID, revisionNo)
+	if err != nil {
+		return model.NewsArticleRevision{}, err
+	}
+	latest := r.sampleNewsArticleRevisions(articleID)[0]
+	source.ID = newID("nar")
+	source.RevisionNo = latest.RevisionNo + 1
+	source.Action = model.NewsArticleRevisionRestore
+	source.RestoredFrom = revisionNo
+	source.Status = latest.Status
+	source.EditorID = editorID
+	source.CreatedAt = time.Now().Format(time.RFC3339)
+	return source, nil
+}
@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestMySQLAdminUpdateNewsArticleRecordsRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM news_articles WHERE id = ? FOR UPDATE")).
+		WithArgs("na_1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("SCHEDULED"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_articles")).
+		WithArgs("nc_1", "新标题", "摘要", "正文", nil, "PUBLIC", "SCHEDULED", "SCHEDULED", sqlmock.AnyArg(), "na_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(revision_no), 0) + 1 FROM news_article_revisions WHERE article_id = ?")).
+		WithArgs("na_1").
+		WillReturnRows(sqlmock.NewRows([]string{"next"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO news_article_revisions")).
+		WithArgs(sqlmock.AnyArg(), 3, "UPDATE", nil, "admin_7", sqlmock.AnyArg(), "na_1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	if err := repo.AdminUpdateNewsArticle("na_1", "nc_1", "新标题", "摘要", "正文", "", "PUBLIC", "SCHEDULED", "admin_7"); err != nil {
+		t.Fatalf("AdminUpdateNewsArticle() error = %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM news_articles WHERE id = ? FOR UPDATE")).
+		WithArgs("na_2").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("DRAFT"))
+	mock.ExpectRollback()
+	if err := repo.AdminUpdateNewsArticle("na_2", "nc_1", "标题", "", "正文", "", "PUBLIC", "SCHEDULED", "admin_7"); !errors.Is(err, model.ErrNewsArticleStatusConflict) {
+		t.Fatalf("expected ErrNewsArticleStatusConflict for an unscheduled article, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func expectNewsSubscriberNotified(mock sqlmock.Sqlmock, articleID string, userID string, title string, content string) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO news_article_notifications")).
+		WithArgs(articleID, userID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WithArgs(sqlmock.AnyArg(), userID, title, content, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+}
+
+func TestMySQLAdminRunNewsScheduledPublishNotifiesCategorySubscribers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE status = 'SCHEDULED' AND scheduled_publish_at <= ?")).
+		WithArgs(sqlmock.AnyArg(), newsScheduledPublishBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("na_1").AddRow("na_2"))
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'PUBLISHED', published_at = scheduled_publish_at")).
+		WithArgs(sqlmock.AnyArg(), "na_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'PUBLISHED', published_at = scheduled_publish_at")).
+		WithArgs(sqlmock.AnyArg(), "na_2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// Embargoed articles are left PENDING by the query itself.
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE status = 'PUBLISHED' AND subscriber_notify_status = 'PENDING'\n  AND (embargo_until IS NULL OR embargo_until <= ?)")).
+		WithArgs(sqlmock.AnyArg(), newsScheduledPublishBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "title", "summary", "visibility"}).
+			AddRow("na_1", "nc_1", "盘前速递", "早盘要点", "PUBLIC"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM subscriptions s")).
+		WithArgs("nc_1", "nc_1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("u_1").AddRow("u_2"))
+	expectNewsSubscriberNotified(mock, "na_1", "u_1", "资讯更新：盘前速递", "早盘要点")
+	expectNewsSubscriberNotified(mock, "na_1", "u_2", "资讯更新：盘前速递", "早盘要点")
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_articles SET subscriber_notify_status = 'DONE'")).
+		WithArgs("na_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunNewsScheduledPublish()
+	if err != nil {
+		t.Fatalf("AdminRunNewsScheduledPublish() error = %v", err)
+	}
+	if summary != "published=1 notified=2" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminRunNewsScheduledPublishKeepsNotifyingAfterAFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE status = 'SCHEDULED' AND scheduled_publish_at <= ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta("subscriber_notify_status = 'PENDING'")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "title", "summary", "visibility"}).
+			AddRow("na_1", "nc_1", "解禁快讯", nil, "PUBLIC"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM subscriptions s")).
+		WithArgs("nc_1", "nc_1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("u_1").AddRow("u_2").AddRow("u_3"))
+	// u_1 was messaged by an earlier pass.
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO news_article_notifications")).
+		WithArgs("na_1", "u_1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO news_article_notifications")).
+		WithArgs("na_1", "u_2", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO messages")).
+		WithArgs(sqlmock.AnyArg(), "u_2", "资讯更新：解禁快讯", "解禁快讯", sqlmock.AnyArg()).
+		WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectRollback()
+	expectNewsSubscriberNotified(mock, "na_1", "u_3", "资讯更新：解禁快讯", "解禁快讯")
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunNewsScheduledPublish()
+	if err == nil || summary != "published=0 notified=1" {
+		t.Fatalf("expected the failed subscriber to be reported, got summary=%q err=%v", summary, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMySQLGetAttachmentFileInfoHidesEmbargoedArticles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT member_level, kyc_status, vip_expire_at FROM users WHERE id = ?")).
+		WithArgs("u_1").
+		WillReturnRows(sqlmock.NewRows([]string{"member_level", "kyc_status", "vip_expire_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE a.id = ? AND n.status = 'PUBLISHED'\n  AND (n.embargo_until IS NULL OR n.embargo_until <= NOW()) AND n.visibility = 'PUBLIC'")).
+		WithArgs("att_1").
+		WillReturnRows(sqlmock.NewRows([]string{"file_url", "article_id", "file_name", "mime_type"}))
+
+	repo := &MySQLGrowthRepo{db: db}
+	if _, err := repo.GetAttachmentFileInfo("u_1", "att_1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected an embargoed attachment to be not found, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestInMemoryGetAttachmentFileInfoHidesEmbargoedArticles(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	info, err := repo.GetAttachmentFileInfo("u_1", "att_001")
+	if err != nil {
+		t.Fatalf("GetAttachmentFileInfo() error = %v", err)
+	}
+	if err := repo.AdminScheduleNewsArticle(info.ArticleID, time.Now(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("AdminScheduleNewsArticle() error = %v", err)
+	}
+	if _, err := repo.GetAttachmentFileInfo("u_1", "att_001"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected an embargoed attachment to be not found, got %v", err)
+	}
+	if err := repo.AdminScheduleNewsArticle(info.ArticleID, time.Now(), time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("AdminScheduleNewsArticle() error = %v", err)
+	}
+	if _, err := repo.GetAttachmentFileInfo("u_1", "att_001"); err != nil {
+		t.Fatalf("expected the attachment once the embargo lifted, got %v", err)
+	}
+}
+
+func TestDiffNewsArticleRevisionsReportsChangedFieldsAndLines(t *testing.T) {
+	from := model.NewsArticleRevision{
+		ArticleID: "na_1", RevisionNo: 1, Title: "旧标题", Visibility: "PUBLIC", Status: "DRAFT",
+		Content: "第一段\n第二段\n第三段", EditorID: "admin_1", CreatedAt: time.Date(2026, 3, 30, 8, 0, 0, 0, time.UTC).Format(time.RFC3339),
+	}
+	to := from
+	to.RevisionNo = 2
+	to.Title = "新标题"
+	to.Content = "第一段\n第二段（修订）\n第三段\n第四段"
+	to.EditorID = "admin_2"
+
+	diff := diffNewsArticleRevisions(from, to)
+	if diff.FromRevision != 1 || diff.ToRevision != 2 || diff.ToEditorID != "admin_2" {
+		t.Fatalf("unexpected diff header %+v", diff)
+	}
+	if len(diff.Fields) != 2 || diff.Fields[0].Field != "title" || diff.Fields[0].Before != "旧标题" || diff.Fields[1].Field != "content" {
+		t.Fatalf("expected title and content changes, got %+v", diff.Fields)
+	}
+	got := ""
+	for _, line := range diff.Fields[1].Lines {
+		got += line.Op + line.Text + "|"
+	}
+	if want := "=第一段|-第二段|+第二段（修订）|=第三段|+第四段|"; got != want {
+		t.Fatalf("unexpected line diff %q, want %q", got, want)
+	}
+}
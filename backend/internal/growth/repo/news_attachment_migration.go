@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// AdminListLegacyNewsAttachments returns attachments whose file_url starts
+// with one of urlPrefixes, oldest first. It is used to find files uploaded
+// to the public /uploads directory before attachments moved to the store.
+func (r *MySQLGrowthRepo) AdminListLegacyNewsAttachments(urlPrefixes []string, limit int) ([]model.NewsAttachment, error) {
+	if len(urlPrefixes) == 0 {
+		return []model.NewsAttachment{}, nil
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	conds := make([]string, 0, len(urlPrefixes))
+	args := make([]interface{}, 0, len(urlPrefixes)+1)
+	for _, prefix := range urlPrefixes {
+		conds = append(conds, "file_url LIKE ?")
+		args = append(args, prefix+"%")
+	}
+	args = append(args, limit)
+	rows, err := r.db.Query(`
+SELECT id, article_id, file_name, file_url, file_size, mime_type, created_at
+FROM news_attachments
+WHERE `+strings.Join(conds, " OR ")+`
+ORDER BY created_at ASC, id ASC
+LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]model.NewsAttachment, 0)
+	for rows.Next() {
+		var item model.NewsAttachment
+		var mimeType sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.ArticleID, &item.FileName, &item.FileURL, &item.FileSize, &mimeType, &createdAt); err != nil {
+			return nil, err
+		}
+		item.MimeType = mimeType.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AdminRelinkNewsAttachmentFile points every attachment row using oldURL at
+// newURL and carries the extracted text over, so search keeps matching.
+// When the new file already has text (a deduplicated object), the old text
+// row is dropped instead.
+func (r *MySQLGrowthRepo) AdminRelinkNewsAttachmentFile(oldURL string, newURL string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE news_attachments SET file_url = ? WHERE file_url = ?", newURL, oldURL)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(
+		"UPDATE IGNORE news_attachment_texts SET url_hash = ?, file_url = ? WHERE url_hash = ?",
+		newsAttachmentURLHash(newURL), newURL, newsAttachmentURLHash(oldURL),
+	); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM news_attachment_texts WHERE url_hash = ?", newsAttachmentURLHash(oldURL)); err != nil {
+		return 0, err
+	}
+	return moved, tx.Commit()
+}
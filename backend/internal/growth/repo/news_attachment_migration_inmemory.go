@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"strings"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// inMemoryLegacyNewsAttachment is the demo attachment uploaded before
+// attachments moved to the store.
+var inMemoryLegacyNewsAttachment = model.NewsAttachment{
+	ID:        "att_legacy_001",
+	ArticleID: "article_demo_001",
+	FileName:  "weekly-review.pdf",
+	FileURL:   "/uploads/news/20260120/1768872000000000000_a1b2c3d4.pdf",
+	FileSize:  16,
+	MimeType:  "application/pdf",
+	CreatedAt: "2026-01-20T09:20:00+08:00",
+}
+
+func (r *InMemoryGrowthRepo) AdminListLegacyNewsAttachments(urlPrefixes []string, limit int) ([]model.NewsAttachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item := inMemoryLegacyNewsAttachment
+	if relinked, ok := r.newsAttachmentRelinks[item.FileURL]; ok {
+		item.FileURL = relinked
+	}
+	for _, prefix := range urlPrefixes {
+		if prefix != "" && strings.HasPrefix(item.FileURL, prefix) {
+			return []model.NewsAttachment{item}, nil
+		}
+	}
+	return []model.NewsAttachment{}, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRelinkNewsAttachmentFile(oldURL string, newURL string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if oldURL != inMemoryLegacyNewsAttachment.FileURL {
+		return 0, nil
+	}
+	if _, ok := r.newsAttachmentRelinks[oldURL]; ok {
+		return 0, nil
+	}
+	r.newsAttachmentRelinks[oldURL] = newURL
+	return 1, nil
+}
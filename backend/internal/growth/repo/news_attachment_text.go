@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/textextract"
+)
+
+const (
+	newsAttachmentTextSnippetBefore = 60
+	newsAttachmentTextSnippetLength = 180
+)
+
+func newsAttachmentURLHash(fileURL string) string {
+	sum := sha256.Sum256([]byte(fileURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *MySQLGrowthRepo) UpsertNewsAttachmentText(item model.NewsAttachmentText) error {
+	offsets, err := json.Marshal(item.PageOffsets)
+	if err != nil {
+		return err
+	}
+	errorMessage := item.ErrorMessage
+	if utf8.RuneCountInString(errorMessage) > 500 {
+		errorMessage = string([]rune(errorMessage)[:500])
+	}
+	_, err = r.db.Exec(`
+INSERT INTO news_attachment_texts (url_hash, file_url, format, status, page_count, page_offsets, content, error_message, extracted_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  format = VALUES(format),
+  status = VALUES(status),
+  page_count = VALUES(page_count),
+  page_offsets = VALUES(page_offsets),
+  content = VALUES(content),
+  error_message = VALUES(error_message),
+  extracted_at = VALUES(extracted_at)`,
+		newsAttachmentURLHash(item.FileURL), item.FileURL, item.Format, item.Status, item.PageCount, string(offsets),
+		nullableString(item.Content), nullableString(errorMessage), time.Now(),
+	)
+	return err
+}
+
+// AdminListNewsAttachmentsMissingText returns attachments whose file has
+// never been through extraction, newest first. Failed extractions have a
+// row and are only retried on request.
+func (r *MySQLGrowthRepo) AdminListNewsAttachmentsMissingText(limit int) ([]model.NewsAttachment, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.Query(`
+SELECT a.id, a.article_id, a.file_name, a.file_url, a.file_size, a.mime_type, a.created_at
+FROM news_attachments a
+LEFT JOIN news_attachment_texts t ON t.url_hash = SHA2(a.file_url, 256)
+WHERE t.url_hash IS NULL
+ORDER BY a.created_at DESC
+LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]model.NewsAttachment, 0)
+	for rows.Next() {
+		var item model.NewsAttachment
+		var mimeType sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.ArticleID, &item.FileName, &item.FileURL, &item.FileSize, &mimeType, &createdAt); err != nil {
+			return nil, err
+		}
+		item.MimeType = mimeType.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// SearchNewsAttachmentTexts matches keyword against extracted attachment
+// text under the same rules as attachment downloads: published, past
+// embargo, and VIP-only articles for active VIP members only.
+func (r *MySQLGrowthRepo) SearchNewsAttachmentTexts(userID string, keyword string, limit int) ([]model.NewsAttachmentSearchHit, int, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []model.NewsAttachmentSearchHit{}, 0, nil
+	}
+	if limit <= 0 {
+		limit = 6
+	}
+	isVIP, err := r.isVIPUser(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := `
+FROM news_attachment_texts t
+JOIN news_attachments a ON t.url_hash = SHA2(a.file_url, 256)
+JOIN news_articles n ON a.article_id = n.id
+WHERE t.status = 'EXTRACTED' AND t.content LIKE ?
+  AND n.status = 'PUBLISHED' AND (n.embargo_until IS NULL OR n.embargo_until <= NOW())`
+	if !isVIP {
+		filter += " AND n.visibility = 'PUBLIC'"
+	}
+	like := "%" + keyword + "%"
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*)"+filter, like).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+SELECT a.id, a.article_id, n.title, n.visibility, a.file_name, a.mime_type, t.page_count, t.page_offsets,
+  LOCATE(?, t.content) AS match_pos,
+  SUBSTRING(t.content, GREATEST(LOCATE(?, t.content) - ?, 1), ?) AS snippet`+filter+`
+ORDER BY n.published_at DESC, a.created_at DESC
+LIMIT ?`, keyword, keyword, newsAttachmentTextSnippetBefore, newsAttachmentTextSnippetLength, like, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]model.NewsAttachmentSearchHit, 0)
+	for rows.Next() {
+		var item model.NewsAttachmentSearchHit
+		var mimeType, pageOffsets, snippet sql.NullString
+		var matchPos int
+		if err := rows.Scan(&item.AttachmentID, &item.ArticleID, &item.ArticleTitle, &item.Visibility, &item.FileName, &mimeType, &item.PageCount, &pageOffsets, &matchPos, &snippet); err != nil {
+			return nil, 0, err
+		}
+		item.MimeType = mimeType.String
+		var offsets []int
+		_ = json.Unmarshal([]byte(pageOffsets.String), &offsets)
+		item.Page = textextract.PageAt(offsets, matchPos-1)
+		item.Snippet = formatNewsAttachmentSnippet(snippet.String, matchPos > newsAttachmentTextSnippetBefore+1)
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// formatNewsAttachmentSnippet flattens line breaks and marks cut ends.
+func formatNewsAttachmentSnippet(snippet string, truncatedStart bool) string {
+	truncatedEnd := utf8.RuneCountInString(snippet) >= newsAttachmentTextSnippetLength
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	if truncatedStart {
+		snippet = "…" + snippet
+	}
+	if truncatedEnd {
+		snippet += "…"
+	}
+	return snippet
+}
@@ -0,0 +1,17 @@
+package repo
+
+import "sercherai/backend/internal/growth/model"
+
+func (r *InMemoryGrowthRepo) UpsertNewsAttachmentText(item model.NewsAttachmentText) error {
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListNewsAttachmentsMissingText(limit int) ([]model.NewsAttachment, error) {
+	return []model.NewsAttachment{}, nil
+}
+
+func (r *InMemoryGrowthRepo) SearchNewsAttachmentTexts(userID string, keyword string, limit int) ([]model.NewsAttachmentSearchHit, int, error) {
+	return []model.NewsAttachmentSearchHit{
+		{AttachmentID: "att_001", ArticleID: "article_demo_001", ArticleTitle: "晨会纪要", Visibility: "PUBLIC", FileName: "daily-note.pdf", MimeType: "application/pdf", PageCount: 3, Page: 2, Snippet: "…" + keyword + "…"},
+	}, 1, nil
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMySQLSearchNewsAttachmentTextsLimitsFreeUsersToPublicArticles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT member_level, kyc_status, vip_expire_at FROM users WHERE id = ?")).
+		WithArgs("u_free").
+		WillReturnRows(sqlmock.NewRows([]string{"member_level", "kyc_status", "vip_expire_at"}).AddRow("FREE", "APPROVED", nil))
+	mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM news_attachment_texts t[\s\S]+n\.visibility = 'PUBLIC'`).
+		WithArgs("%估值%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`LOCATE\(\?, t\.content\)[\s\S]+n\.visibility = 'PUBLIC'[\s\S]+LIMIT \?`).
+		WithArgs("估值", "估值", newsAttachmentTextSnippetBefore, newsAttachmentTextSnippetLength, "%估值%", 6).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "title", "visibility", "file_name", "mime_type", "page_count", "page_offsets", "match_pos", "snippet"}).
+			AddRow("att_001", "na_001", "白酒深度报告", "PUBLIC", "report.pdf", "application/pdf", 3, "[0,400,900]", 512, "行业\n估值   处于低位"))
+
+	repo := &MySQLGrowthRepo{db: db}
+	items, total, err := repo.SearchNewsAttachmentTexts("u_free", "估值", 6)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if total != 1 || len(items) != 1 {
+		t.Fatalf("expected one hit, got %d %+v", total, items)
+	}
+	if items[0].Page != 2 || items[0].Snippet != "…行业 估值 处于低位" {
+		t.Fatalf("expected page 2 with a flattened snippet, got %+v", items[0])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMySQLAdminRelinkNewsAttachmentFileMovesRowsAndText(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	oldURL := "/uploads/news/20260120/a.pdf"
+	newURL := "storage://LOCAL/news/attachments/ab/abcdef.pdf"
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_attachments SET file_url = ? WHERE file_url = ?")).
+		WithArgs(newURL, oldURL).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE IGNORE news_attachment_texts SET url_hash = ?, file_url = ? WHERE url_hash = ?")).
+		WithArgs(newsAttachmentURLHash(newURL), newURL, newsAttachmentURLHash(oldURL)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM news_attachment_texts WHERE url_hash = ?")).
+		WithArgs(newsAttachmentURLHash(oldURL)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := &MySQLGrowthRepo{db: db}
+	moved, err := repo.AdminRelinkNewsAttachmentFile(oldURL, newURL)
+	if err != nil || moved != 2 {
+		t.Fatalf("AdminRelinkNewsAttachmentFile() = %d, %v", moved, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
@@ -0,0 +1,412 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const (
+	// Two stories are near-duplicates when their 64-bit SimHash fingerprints
+	// differ in at most this many bits and they were published within the
+	// window of each other.
+	newsNearDuplicateMaxDistance = 6
+	newsNearDuplicateWindow      = 48 * time.Hour
+	newsNearDuplicateCandidates  = 2000
+
+	newsSimHashShingleSize = 2
+	newsSimHashBodyRunes   = 2000
+)
+
+// newsDuplicateTable describes a table holding synced news with id,
+// published_at, simhash and duplicate_of columns. manual names the flag
+// column of links made by hand, when the table has one.
+type newsDuplicateTable struct {
+	name   string
+	filter string
+	manual string
+}
+
+var (
+	newsArticleDuplicateTable = newsDuplicateTable{name: "news_articles", filter: " AND status = 'PUBLISHED'", manual: "duplicate_manual"}
+	marketNewsDuplicateTable  = newsDuplicateTable{name: "market_news_items"}
+)
+
+// newsDuplicateQueryer is satisfied by both *sql.DB and *sql.Tx.
+type newsDuplicateQueryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// newsSimHash fingerprints a story from character bigrams of its normalized
+// title and body. Bigrams rather than longer shingles keep short Chinese
+// headlines stable under small rewrites.
+func newsSimHash(title string, body string) uint64 {
+	var weights [64]int
+	addNewsSimHashShingles(&weights, normalizeStockEventClusterTitle(title))
+	addNewsSimHashShingles(&weights, normalizeStockEventClusterTitle(truncateByRunes(body, newsSimHashBodyRunes)))
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+func addNewsSimHashShingles(weights *[64]int, text string) {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return
+	}
+	size := newsSimHashShingleSize
+	if len(runes) < size {
+		size = len(runes)
+	}
+	for i := 0; i+size <= len(runes); i++ {
+		hasher := fnv.New64a()
+		_, _ = hasher.Write([]byte(string(runes[i : i+size])))
+		sum := hasher.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+}
+
+func newsSimHashDistance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func isNewsNearDuplicate(a uint64, aAt time.Time, b uint64, bAt time.Time) bool {
+	if a == 0 || b == 0 || newsSimHashDistance(a, b) > newsNearDuplicateMaxDistance {
+		return false
+	}
+	if aAt.IsZero() || bAt.IsZero() {
+		return true
+	}
+	gap := aAt.Sub(bAt)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= newsNearDuplicateWindow
+}
+
+func formatNewsSimHash(value uint64) string {
+	return fmt.Sprintf("%016x", value)
+}
+
+func parseNewsSimHash(value string) (uint64, bool) {
+	parsed, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return parsed, true
+}
+
+type newsDuplicateCandidate struct {
+	id          string
+	canonicalID string
+	publishedAt time.Time
+}
+
+// linkNewsNearDuplicates stores the fingerprint of a freshly upserted row and
+// merges it with every stored near-duplicate group it matches. The earliest
+// published canonical of the matched groups (or the row itself) wins; the
+// other canonicals and their members are re-pointed at it. It returns the
+// canonical id of the row's group, which is the row itself when nothing
+// matched. A row merged by hand keeps its link and is not re-linked.
+func linkNewsNearDuplicates(q newsDuplicateQueryer, table newsDuplicateTable, id string, fingerprint uint64, publishedAt time.Time) (string, error) {
+	reset := "duplicate_of = NULL"
+	if table.manual != "" {
+		reset = "duplicate_of = CASE WHEN " + table.manual + " = 1 THEN duplicate_of ELSE NULL END"
+	}
+	if _, err := q.Exec("UPDATE "+table.name+" SET simhash = ?, "+reset+" WHERE id = ?", formatNewsSimHash(fingerprint), id); err != nil {
+		return "", err
+	}
+	if table.manual != "" {
+		canonicalID, err := manualNewsDuplicateCanonical(q, table, id)
+		if err != nil || canonicalID != "" {
+			return canonicalID, err
+		}
+	}
+	if fingerprint == 0 || publishedAt.IsZero() {
+		return id, nil
+	}
+
+	rows, err := q.Query(`
+SELECT id, simhash, duplicate_of, published_at
+FROM `+table.name+`
+WHERE id <> ? AND simhash IS NOT NULL AND published_at BETWEEN ? AND ?`+table.filter+`
+ORDER BY published_at ASC
+LIMIT ?`, id, publishedAt.Add(-newsNearDuplicateWindow), publishedAt.Add(newsNearDuplicateWindow), newsNearDuplicateCandidates)
+	if err != nil {
+		return "", err
+	}
+	published := map[string]time.Time{id: publishedAt}
+	groups := make([]string, 0)
+	seen := map[string]bool{id: true}
+	for rows.Next() {
+		var candidate newsDuplicateCandidate
+		var simhash, duplicateOf sql.NullString
+		if err := rows.Scan(&candidate.id, &simhash, &duplicateOf, &candidate.publishedAt); err != nil {
+			rows.Close()
+			return "", err
+		}
+		value, ok := parseNewsSimHash(simhash.String)
+		if !ok || newsSimHashDistance(fingerprint, value) > newsNearDuplicateMaxDistance {
+			continue
+		}
+		candidate.canonicalID = candidate.id
+		if duplicateOf.Valid && strings.TrimSpace(duplicateOf.String) != "" {
+			candidate.canonicalID = strings.TrimSpace(duplicateOf.String)
+		} else {
+			published[candidate.id] = candidate.publishedAt
+		}
+		if !seen[candidate.canonicalID] {
+			seen[candidate.canonicalID] = true
+			groups = append(groups, candidate.canonicalID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return "", err
+	}
+	rows.Close()
+	if len(groups) == 0 {
+		return id, nil
+	}
+
+	if err := loadNewsDuplicatePublishedAt(q, table, groups, published); err != nil {
+		return "", err
+	}
+	members := append([]string{id}, groups...)
+	sort.SliceStable(members, func(i, j int) bool {
+		// A canonical that no longer exists never wins; its members follow
+		// the surviving canonical.
+		left, leftOK := published[members[i]]
+		right, rightOK := published[members[j]]
+		if leftOK != rightOK {
+			return leftOK
+		}
+		if !left.Equal(right) {
+			return left.Before(right)
+		}
+		return members[i] < members[j]
+	})
+	canonicalID := members[0]
+	losers := members[1:]
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(losers)), ",")
+	args := make([]interface{}, 0, 1+len(losers)*2)
+	args = append(args, canonicalID)
+	for _, loser := range losers {
+		args = append(args, loser)
+	}
+	for _, loser := range losers {
+		args = append(args, loser)
+	}
+	if _, err := q.Exec("UPDATE "+table.name+" SET duplicate_of = ? WHERE id IN ("+placeholders+") OR duplicate_of IN ("+placeholders+")", args...); err != nil {
+		return "", err
+	}
+	if _, err := q.Exec("UPDATE "+table.name+" SET duplicate_of = NULL WHERE id = ?", canonicalID); err != nil {
+		return "", err
+	}
+	return canonicalID, nil
+}
+
+// manualNewsDuplicateCanonical returns the canonical a row was merged into
+// by hand, or "" when its link, if any, is automatic.
+func manualNewsDuplicateCanonical(q newsDuplicateQueryer, table newsDuplicateTable, id string) (string, error) {
+	rows, err := q.Query("SELECT duplicate_of FROM "+table.name+" WHERE id = ? AND "+table.manual+" = 1 AND duplicate_of IS NOT NULL", id)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var canonicalID string
+	if rows.Next() {
+		if err := rows.Scan(&canonicalID); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(canonicalID), rows.Err()
+}
+
+// loadNewsDuplicatePublishedAt fills in the publish time of matched group
+// canonicals that fell outside the candidate window.
+func loadNewsDuplicatePublishedAt(q newsDuplicateQueryer, table newsDuplicateTable, ids []string, published map[string]time.Time) error {
+	missing := make([]interface{}, 0)
+	for _, id := range ids {
+		if _, ok := published[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	rows, err := q.Query("SELECT id, published_at FROM "+table.name+" WHERE id IN ("+strings.TrimSuffix(strings.Repeat("?,", len(missing)), ",")+")", missing...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&id, &publishedAt); err != nil {
+			return err
+		}
+		published[id] = publishedAt.Time
+	}
+	return rows.Err()
+}
+
+// linkMarketNewsNearDuplicate links a stored market news item and, when it is
+// a duplicate, records the canonical id and title on the item so the event
+// clustering that follows the sync groups it with the canonical story.
+func (r *MySQLGrowthRepo) linkMarketNewsNearDuplicate(item *model.MarketNewsItem, sourceKey string, externalID string, publishedAt time.Time) error {
+	var id string
+	if err := r.db.QueryRow("SELECT id FROM market_news_items WHERE source_key = ? AND external_id = ?", sourceKey, externalID).Scan(&id); err != nil {
+		return err
+	}
+	item.ID = id
+	canonicalID, err := linkNewsNearDuplicates(r.db, marketNewsDuplicateTable, id, newsSimHash(item.Title, marketNewsSimHashBody(*item)), publishedAt)
+	if err != nil {
+		return err
+	}
+	item.DuplicateOf = ""
+	item.CanonicalTitle = ""
+	if canonicalID == id {
+		return nil
+	}
+	var title string
+	if err := r.db.QueryRow("SELECT title FROM market_news_items WHERE id = ?", canonicalID).Scan(&title); err != nil {
+		return err
+	}
+	item.DuplicateOf = canonicalID
+	item.CanonicalTitle = title
+	return nil
+}
+
+func marketNewsSimHashBody(item model.MarketNewsItem) string {
+	if body := strings.TrimSpace(item.Content); body != "" {
+		return body
+	}
+	return strings.TrimSpace(item.Summary)
+}
+
+// resolveMarketNewsClusterTitles returns the title each item should be
+// clustered under: the stored canonical title when the sync linked it, else
+// the title of the first near-duplicate earlier in the same batch, else its
+// own title.
+func resolveMarketNewsClusterTitles(items []model.MarketNewsItem) []string {
+	titles := make([]string, len(items))
+	fingerprints := make([]uint64, len(items))
+	publishedAt := make([]time.Time, len(items))
+	for i, item := range items {
+		titles[i] = strings.TrimSpace(item.Title)
+		if canonical := strings.TrimSpace(item.CanonicalTitle); canonical != "" {
+			titles[i] = canonical
+			continue
+		}
+		if titles[i] == "" {
+			continue
+		}
+		fingerprints[i] = newsSimHash(item.Title, marketNewsSimHashBody(item))
+		publishedAt[i], _ = parseFlexibleDateTime(item.PublishedAt)
+		for j := 0; j < i; j++ {
+			if isNewsNearDuplicate(fingerprints[i], publishedAt[i], fingerprints[j], publishedAt[j]) {
+				titles[i] = titles[j]
+				break
+			}
+		}
+	}
+	return titles
+}
+
+func (r *MySQLGrowthRepo) listNewsArticleAlternateSources(articleID string, duplicateOf string, publicOnly bool, publishedOnly bool) ([]model.NewsArticleAlternateSource, error) {
+	canonicalID := articleID
+	if strings.TrimSpace(duplicateOf) != "" {
+		canonicalID = strings.TrimSpace(duplicateOf)
+	}
+	query := `
+SELECT id, category_id, title, published_at, duplicate_of
+FROM news_articles
+WHERE (id = ? OR duplicate_of = ?) AND id <> ?`
+	if publishedOnly {
+		query += " AND status = 'PUBLISHED' AND (embargo_until IS NULL OR embargo_until <= NOW())"
+	}
+	if publicOnly {
+		query += " AND visibility = 'PUBLIC'"
+	}
+	query += " ORDER BY published_at ASC, id ASC"
+	rows, err := r.db.Query(query, canonicalID, canonicalID, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.NewsArticleAlternateSource, 0)
+	for rows.Next() {
+		var item model.NewsArticleAlternateSource
+		var publishedAt sql.NullTime
+		var itemDuplicateOf sql.NullString
+		if err := rows.Scan(&item.ID, &item.CategoryID, &item.Title, &publishedAt, &itemDuplicateOf); err != nil {
+			return nil, err
+		}
+		if publishedAt.Valid {
+			item.PublishedAt = publishedAt.Time.Format(time.RFC3339)
+		}
+		item.Canonical = !itemDuplicateOf.Valid || itemDuplicateOf.String == ""
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AdminMergeNewsArticleDuplicate links an article, together with any
+// articles already pointing at it, into the group of canonicalID. The links
+// are flagged as manual so a later sync of the article keeps them.
+func (r *MySQLGrowthRepo) AdminMergeNewsArticleDuplicate(id string, canonicalID string) error {
+	var duplicateOf sql.NullString
+	if err := r.db.QueryRow("SELECT duplicate_of FROM news_articles WHERE id = ?", canonicalID).Scan(&duplicateOf); err != nil {
+		return err
+	}
+	if duplicateOf.Valid && duplicateOf.String != "" {
+		canonicalID = duplicateOf.String
+	}
+	if canonicalID == id {
+		return fmt.Errorf("%w: article is already the canonical of this group", model.ErrNewsArticleMergeConflict)
+	}
+	result, err := r.db.Exec("UPDATE news_articles SET duplicate_of = ?, duplicate_manual = 1, updated_at = ? WHERE id = ? OR duplicate_of = ?", canonicalID, time.Now(), id, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AdminUnmergeNewsArticleDuplicate detaches a duplicate from its group so it
+// is listed on its own again. A later sync of the same article may link it
+// again if it is still a near-duplicate.
+func (r *MySQLGrowthRepo) AdminUnmergeNewsArticleDuplicate(id string) error {
+	var duplicateOf sql.NullString
+	if err := r.db.QueryRow("SELECT duplicate_of FROM news_articles WHERE id = ?", id).Scan(&duplicateOf); err != nil {
+		return err
+	}
+	if !duplicateOf.Valid || duplicateOf.String == "" {
+		return fmt.Errorf("%w: article is not a duplicate", model.ErrNewsArticleMergeConflict)
+	}
+	_, err := r.db.Exec("UPDATE news_articles SET duplicate_of = NULL, duplicate_manual = 0, updated_at = ? WHERE id = ?", time.Now(), id)
+	return err
+}
@@ -0,0 +1,9 @@
+package repo
+
+func (r *InMemoryGrowthRepo) AdminMergeNewsArticleDuplicate(id string, canonicalID string) error {
+	return nil
+}
+
+func (r *InMemoryGrowthRepo) AdminUnmergeNewsArticleDuplicate(id string) error {
+	return nil
+}
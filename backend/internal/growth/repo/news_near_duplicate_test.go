@@ -0,0 +1,202 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const nearDuplicateTestBody = "贵州茅台发布2026年一季度报告，公司实现营业总收入同比增长12%，归母净利润同比增长15%，高于市场一致预期。直销渠道收入占比继续提升，系列酒增速较快，公司维持全年经营目标不变。"
+
+func TestNewsSimHashSeparatesNearDuplicatesFromDifferentStories(t *testing.T) {
+	base := newsSimHash("贵州茅台一季度净利润同比增长15%超市场预期", nearDuplicateTestBody)
+	rewrite := newsSimHash("贵州茅台一季度净利润同比增长15%，超市场预期！", nearDuplicateTestBody+"（来源：公司公告）")
+	other := newsSimHash("宁德时代发布新一代钠离子电池", "宁德时代今日在发布会上推出新一代钠离子电池产品，能量密度提升，预计年内量产并配套多家整车厂。")
+
+	if distance := newsSimHashDistance(base, rewrite); distance > newsNearDuplicateMaxDistance {
+		t.Fatalf("expected rewrite to be a near-duplicate, distance=%d", distance)
+	}
+	if distance := newsSimHashDistance(base, other); distance <= newsNearDuplicateMaxDistance {
+		t.Fatalf("expected different stories to be far apart, distance=%d", distance)
+	}
+	if parsed, ok := parseNewsSimHash(formatNewsSimHash(base)); !ok || parsed != base {
+		t.Fatalf("expected fingerprint to round-trip, got %x ok=%v", parsed, ok)
+	}
+}
+
+func TestBuildDraftStockEventClustersFromMarketNewsMergesNearDuplicateSources(t *testing.T) {
+	clusters := buildDraftStockEventClustersFromMarketNews([]model.MarketNewsItem{
+		{
+			SourceKey:     "TUSHARE",
+			ExternalID:    "dup_001",
+			NewsType:      "announcement",
+			Title:         "贵州茅台一季度净利润同比增长15%超市场预期",
+			Content:       nearDuplicateTestBody,
+			PrimarySymbol: "600519.SH",
+			PublishedAt:   "2026-03-23T09:00:00+08:00",
+		},
+		{
+			SourceKey:     "AKSHARE",
+			ExternalID:    "dup_002",
+			NewsType:      "announcement",
+			Title:         "贵州茅台一季度净利润同比增长15%，超市场预期！",
+			Content:       nearDuplicateTestBody + "（来源：公司公告）",
+			PrimarySymbol: "600519.SH",
+			PublishedAt:   "2026-03-23T09:20:00+08:00",
+		},
+		{
+			SourceKey:     "AKSHARE",
+			ExternalID:    "dup_003",
+			NewsType:      "announcement",
+			Title:         "贵州茅台一季度净利润增长15%超预期",
+			Content:       nearDuplicateTestBody,
+			PrimarySymbol: "600519.SH",
+			PublishedAt:   "2026-03-27T09:00:00+08:00",
+		},
+	})
+
+	if len(clusters) != 2 {
+		t.Fatalf("expected near-duplicates to share a cluster and the rewrite outside the window to stay apart, got %d clusters", len(clusters))
+	}
+	if clusters[0].NewsCount != 2 || clusters[0].Source != "MULTI_SOURCE" {
+		t.Fatalf("expected both sources in the first cluster, got %+v", clusters[0])
+	}
+}
+
+func TestBuildDraftStockEventClustersFromMarketNewsUsesStoredCanonicalTitle(t *testing.T) {
+	clusters := buildDraftStockEventClustersFromMarketNews([]model.MarketNewsItem{
+		{
+			SourceKey:     "AKSHARE",
+			ExternalID:    "dup_010",
+			NewsType:      "market",
+			Title:         "机器人板块午后拉升",
+			PrimarySymbol: "300024.SZ",
+			PublishedAt:   "2026-03-23T13:30:00+08:00",
+		},
+	})
+	linked := buildDraftStockEventClustersFromMarketNews([]model.MarketNewsItem{
+		{
+			SourceKey:      "TUSHARE",
+			ExternalID:     "dup_011",
+			NewsType:       "market",
+			Title:          "午后机器人板块快速拉升",
+			PrimarySymbol:  "300024.SZ",
+			PublishedAt:    "2026-03-23T13:35:00+08:00",
+			DuplicateOf:    "mni_canonical",
+			CanonicalTitle: "机器人板块午后拉升",
+		},
+	})
+
+	if len(clusters) != 1 || len(linked) != 1 {
+		t.Fatalf("expected one cluster per batch, got %d and %d", len(clusters), len(linked))
+	}
+	if clusters[0].ClusterKey != linked[0].ClusterKey {
+		t.Fatalf("expected linked item to reuse the canonical cluster key, got %s and %s", clusters[0].ClusterKey, linked[0].ClusterKey)
+	}
+}
+
+func TestLinkNewsNearDuplicatesPicksEarliestCanonical(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	publishedAt := time.Date(2026, 3, 23, 9, 30, 0, 0, time.UTC)
+	fingerprint := newsSimHash("贵州茅台一季度净利润同比增长15%超市场预期", nearDuplicateTestBody)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_articles SET simhash = ?, duplicate_of = CASE WHEN duplicate_manual = 1 THEN duplicate_of ELSE NULL END WHERE id = ?")).
+		WithArgs(formatNewsSimHash(fingerprint), "na_new").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT duplicate_of FROM news_articles WHERE id = ? AND duplicate_manual = 1")).
+		WithArgs("na_new").
+		WillReturnRows(sqlmock.NewRows([]string{"duplicate_of"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, simhash, duplicate_of, published_at\nFROM news_articles")).
+		WithArgs("na_new", publishedAt.Add(-newsNearDuplicateWindow), publishedAt.Add(newsNearDuplicateWindow), newsNearDuplicateCandidates).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "simhash", "duplicate_of", "published_at"}).
+			AddRow("na_member", formatNewsSimHash(fingerprint^1), "na_old", publishedAt.Add(-time.Hour)).
+			AddRow("na_other", formatNewsSimHash(^fingerprint), nil, publishedAt.Add(-2*time.Hour)))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, published_at FROM news_articles WHERE id IN (?)")).
+		WithArgs("na_old").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "published_at"}).AddRow("na_old", publishedAt.Add(-3*time.Hour)))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_articles SET duplicate_of = ? WHERE id IN (?) OR duplicate_of IN (?)")).
+		WithArgs("na_old", "na_new", "na_new").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_articles SET duplicate_of = NULL WHERE id = ?")).
+		WithArgs("na_old").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	canonicalID, err := linkNewsNearDuplicates(db, newsArticleDuplicateTable, "na_new", fingerprint, publishedAt)
+	if err != nil {
+		t.Fatalf("link near duplicates: %v", err)
+	}
+	if canonicalID != "na_old" {
+		t.Fatalf("expected earliest group canonical to win, got %s", canonicalID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestLinkNewsNearDuplicatesKeepsManualMerge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	publishedAt := time.Date(2026, 3, 23, 9, 30, 0, 0, time.UTC)
+	fingerprint := newsSimHash("贵州茅台一季度净利润同比增长15%超市场预期", nearDuplicateTestBody)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE news_articles SET simhash = ?, duplicate_of = CASE WHEN duplicate_manual = 1 THEN duplicate_of ELSE NULL END WHERE id = ?")).
+		WithArgs(formatNewsSimHash(fingerprint), "na_merged").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT duplicate_of FROM news_articles WHERE id = ? AND duplicate_manual = 1")).
+		WithArgs("na_merged").
+		WillReturnRows(sqlmock.NewRows([]string{"duplicate_of"}).AddRow("na_chosen"))
+
+	canonicalID, err := linkNewsNearDuplicates(db, newsArticleDuplicateTable, "na_merged", fingerprint, publishedAt)
+	if err != nil {
+		t.Fatalf("link near duplicates: %v", err)
+	}
+	if canonicalID != "na_chosen" {
+		t.Fatalf("expected the manual merge to survive the sync, got %s", canonicalID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMySQLListNewsArticlesHidesDuplicatesOnlyBehindAListedCanonical(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM news_articles WHERE news_articles.status = 'PUBLISHED'")+`.*`+
+		regexp.QuoteMeta("NOT EXISTS (\n    SELECT 1 FROM news_articles canon WHERE canon.id = news_articles.duplicate_of AND canon.status = 'PUBLISHED'")+`.*`+
+		regexp.QuoteMeta("AND canon.category_id = ? AND (canon.title LIKE ? OR canon.summary LIKE ?)")).
+		WithArgs("nc_1", "%茅台%", "%茅台%", "nc_1", "%茅台%", "%茅台%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM news_articles WHERE news_articles.status = 'PUBLISHED'")).
+		WithArgs("nc_1", "%茅台%", "%茅台%", "nc_1", "%茅台%", "%茅台%", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "title", "summary", "cover_url", "visibility", "status", "published_at", "author_id", "alternate_source_count"}).
+			AddRow("na_dup", "nc_1", "茅台一季报", nil, nil, "PUBLIC", "PUBLISHED", time.Now(), nil, 0))
+
+	repo := &MySQLGrowthRepo{db: db}
+	items, total, err := repo.ListNewsArticles("u_1", "nc_1", "茅台", 1, 20)
+	if err != nil {
+		t.Fatalf("ListNewsArticles() error = %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != "na_dup" {
+		t.Fatalf("expected the duplicate to be listed, got total=%d items=%+v", total, items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/realtime"
+)
+
+func (r *MySQLGrowthRepo) Realtime() *realtime.Hub {
+	return r.realtime
+}
+
+// notifyUserMessage wakes the user's message streams. Callers inside a
+// transaction may ping before commit; streams re-read shortly after a ping,
+// so the row is picked up once it is visible.
+func (r *MySQLGrowthRepo) notifyUserMessage(userID string) {
+	r.realtime.Notify(realtime.UserTopic(userID))
+}
+
+func (r *MySQLGrowthRepo) notifyWorkflowMessage(receiverID string) {
+	if strings.TrimSpace(receiverID) == "" {
+		r.realtime.Notify(realtime.AdminBroadcastTopic)
+		return
+	}
+	r.realtime.Notify(realtime.AdminTopic(receiverID))
+}
+
+// ListUserMessagesAfter returns the user's messages inserted after the
+// afterSeq cursor, in insert order. seq is an auto-increment column, so
+// messages written in the same second or on different replicas still have
+// a strict order.
+func (r *MySQLGrowthRepo) ListUserMessagesAfter(userID string, afterSeq int64, limit int) ([]model.UserMessage, error) {
+	rows, err := r.db.Query(`
+SELECT seq, id, title, content, type, read_status, created_at
+FROM messages
+WHERE user_id = ? AND seq > ?
+ORDER BY seq ASC
+LIMIT ?`, strings.TrimSpace(userID), afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.UserMessage, 0)
+	for rows.Next() {
+		var item model.UserMessage
+		var content sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&item.Seq, &item.ID, &item.Title, &content, &item.Type, &item.ReadStatus, &createdAt); err != nil {
+			return nil, err
+		}
+		item.Content = content.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// LatestUserMessageSeq is where a stream without Last-Event-ID starts.
+func (r *MySQLGrowthRepo) LatestUserMessageSeq(userID string) (int64, error) {
+	var seq int64
+	err := r.db.QueryRow("SELECT COALESCE(MAX(seq), 0) FROM messages WHERE user_id = ?", strings.TrimSpace(userID)).Scan(&seq)
+	return seq, err
+}
+
+// AdminListWorkflowMessagesAfter is the workflow counterpart of
+// ListUserMessagesAfter; messages without a receiver go to every admin.
+func (r *MySQLGrowthRepo) AdminListWorkflowMessagesAfter(receiverID string, afterSeq int64, limit int) ([]model.WorkflowMessage, error) {
+	rows, err := r.db.Query(`
+SELECT seq, id, review_id, target_id, module, receiver_id, sender_id, event_type, title, content, is_read, created_at, read_at
+FROM workflow_messages
+WHERE (receiver_id = ? OR receiver_id IS NULL OR receiver_id = '')
+  AND seq > ?
+ORDER BY seq ASC
+LIMIT ?`, strings.TrimSpace(receiverID), afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.WorkflowMessage, 0)
+	for rows.Next() {
+		var item model.WorkflowMessage
+		var reviewID, receiver, sender sql.NullString
+		var createdAt time.Time
+		var readAt sql.NullTime
+		if err := rows.Scan(&item.Seq, &item.ID, &reviewID, &item.TargetID, &item.Module, &receiver, &sender, &item.EventType, &item.Title, &item.Content, &item.IsRead, &createdAt, &readAt); err != nil {
+			return nil, err
+		}
+		item.ReviewID = reviewID.String
+		item.ReceiverID = receiver.String
+		item.SenderID = sender.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		if readAt.Valid {
+			item.ReadAt = readAt.Time.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminLatestWorkflowMessageSeq(receiverID string) (int64, error) {
+	var seq int64
+	err := r.db.QueryRow(`
+SELECT COALESCE(MAX(seq), 0)
+FROM workflow_messages
+WHERE receiver_id = ? OR receiver_id IS NULL OR receiver_id = ''`, strings.TrimSpace(receiverID)).Scan(&seq)
+	return seq, err
+}
@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"sort"
+	"strings"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/realtime"
+)
+
+func (r *InMemoryGrowthRepo) Realtime() *realtime.Hub {
+	return r.realtime
+}
+
+// nextMessageSeqLocked hands out the insert sequence of user and workflow
+// messages, like the seq auto-increment columns in MySQL.
+func (r *InMemoryGrowthRepo) nextMessageSeqLocked() int64 {
+	r.messageSeq++
+	return r.messageSeq
+}
+
+func (r *InMemoryGrowthRepo) ListUserMessagesAfter(userID string, afterSeq int64, limit int) ([]model.UserMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.UserMessage, 0)
+	for _, item := range r.userMessages[strings.TrimSpace(userID)] {
+		if item.Seq > afterSeq {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Seq < items[j].Seq })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) LatestUserMessageSeq(userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var seq int64
+	for _, item := range r.userMessages[strings.TrimSpace(userID)] {
+		if item.Seq > seq {
+			seq = item.Seq
+		}
+	}
+	return seq, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListWorkflowMessagesAfter(receiverID string, afterSeq int64, limit int) ([]model.WorkflowMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	receiverID = strings.TrimSpace(receiverID)
+	items := make([]model.WorkflowMessage, 0)
+	for _, item := range r.workflowMessages {
+		if item.ReceiverID != "" && item.ReceiverID != receiverID {
+			continue
+		}
+		if item.Seq > afterSeq {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Seq < items[j].Seq })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminLatestWorkflowMessageSeq(receiverID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	receiverID = strings.TrimSpace(receiverID)
+	var seq int64
+	for _, item := range r.workflowMessages {
+		if (item.ReceiverID == "" || item.ReceiverID == receiverID) && item.Seq > seq {
+			seq = item.Seq
+		}
+	}
+	return seq, nil
+}
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMySQLListUserMessagesAfterPagesOnSeq(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE user_id = ? AND seq > ?\nORDER BY seq ASC")).
+		WithArgs("u_001", int64(41), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "id", "title", "content", "type", "read_status", "created_at"}).
+			AddRow(42, "msg_b", "late", "committed late", "SYSTEM", "UNREAD", createdAt).
+			AddRow(43, "msg_a", "next", nil, "COMMUNITY", "UNREAD", createdAt))
+
+	repo := &MySQLGrowthRepo{db: db}
+	items, err := repo.ListUserMessagesAfter(" u_001 ", 41, 100)
+	if err != nil {
+		t.Fatalf("ListUserMessagesAfter() error = %v", err)
+	}
+	if len(items) != 2 || items[0].Seq != 42 || items[0].ID != "msg_b" || items[1].Seq != 43 || items[1].Content != "" {
+		t.Fatalf("unexpected messages %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
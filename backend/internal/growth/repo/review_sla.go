@@ -0,0 +1,389 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const reviewSLAPolicyColumns = `module, status, high_minutes, normal_minutes, low_minutes, business_hours_only, business_start, business_end, workdays, timezone, backup_reviewer_id, backup_role, updated_by, updated_at`
+
+const reviewSLAItemColumns = `id, subject_type, subject_id, module, target_id, priority, assignee_id, status, started_at, due_at, breached_at, breached_assignee_id, escalated_to, escalated_at, resolved_by, resolved_at`
+
+// reviewSLAEscalationBatch bounds one escalation run; the rest are picked
+// up by the next tick.
+const reviewSLAEscalationBatch = 500
+
+func (r *MySQLGrowthRepo) AdminListReviewSLAPolicies() ([]model.ReviewSLAPolicy, error) {
+	rows, err := r.db.Query(`SELECT ` + reviewSLAPolicyColumns + ` FROM review_sla_policies ORDER BY module ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ReviewSLAPolicy, 0)
+	for rows.Next() {
+		item, err := scanReviewSLAPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminSaveReviewSLAPolicy(item model.ReviewSLAPolicy, operator string) (model.ReviewSLAPolicy, error) {
+	_, err := r.db.Exec(`
+INSERT INTO review_sla_policies (`+reviewSLAPolicyColumns+`)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE status = VALUES(status), high_minutes = VALUES(high_minutes), normal_minutes = VALUES(normal_minutes), low_minutes = VALUES(low_minutes),
+	business_hours_only = VALUES(business_hours_only), business_start = VALUES(business_start), business_end = VALUES(business_end), workdays = VALUES(workdays),
+	timezone = VALUES(timezone), backup_reviewer_id = VALUES(backup_reviewer_id), backup_role = VALUES(backup_role), updated_by = VALUES(updated_by), updated_at = VALUES(updated_at)`,
+		item.Module, item.Status, item.HighMinutes, item.NormalMinutes, item.LowMinutes, item.BusinessHoursOnly, item.BusinessStart, item.BusinessEnd,
+		formatReviewSLAWorkdays(item.Workdays), item.Timezone, item.BackupReviewerID, item.BackupRole, strings.TrimSpace(operator), time.Now(),
+	)
+	if err != nil {
+		return model.ReviewSLAPolicy{}, err
+	}
+	return r.getReviewSLAPolicy(item.Module)
+}
+
+func (r *MySQLGrowthRepo) getReviewSLAPolicy(module string) (model.ReviewSLAPolicy, error) {
+	item, err := scanReviewSLAPolicy(r.db.QueryRow(`SELECT `+reviewSLAPolicyColumns+` FROM review_sla_policies WHERE module = ?`, strings.ToUpper(strings.TrimSpace(module))))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ReviewSLAPolicy{}, model.ErrReviewSLAPolicyNotFound
+	}
+	return item, err
+}
+
+func (r *MySQLGrowthRepo) AdminListReviewSLAItems(module string, status string, assigneeID string, page int, pageSize int) ([]model.ReviewSLAItem, int, error) {
+	filter, args := buildReviewSLAItemFilter(module, status, assigneeID)
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM review_sla_items"+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`SELECT `+reviewSLAItemColumns+` FROM review_sla_items`+filter+` ORDER BY due_at ASC, id ASC LIMIT ? OFFSET ?`, append(args, pageSize, (page-1)*pageSize)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.ReviewSLAItem, 0)
+	for rows.Next() {
+		item, err := scanReviewSLAItem(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// AdminGetReviewSLAMetrics reports compliance over items started in the last
+// days days, plus every item still open regardless of age.
+func (r *MySQLGrowthRepo) AdminGetReviewSLAMetrics(module string, reviewerID string, days int) (model.ReviewSLAMetrics, error) {
+	args := []interface{}{time.Now().AddDate(0, 0, -days)}
+	filter := " WHERE (status IN ('OPEN', 'BREACHED') OR started_at >= ?)"
+	if module = strings.ToUpper(strings.TrimSpace(module)); module != "" {
+		filter += " AND module = ?"
+		args = append(args, module)
+	}
+	if reviewerID = strings.TrimSpace(reviewerID); reviewerID != "" {
+		filter += ` AND (CASE
+	WHEN status IN ('OPEN', 'BREACHED') THEN assignee_id
+	WHEN status = 'MISSED' AND breached_assignee_id <> '' THEN breached_assignee_id
+	WHEN resolved_by <> '' THEN resolved_by
+	ELSE assignee_id END) = ?`
+		args = append(args, reviewerID)
+	}
+	rows, err := r.db.Query(`SELECT `+reviewSLAItemColumns+` FROM review_sla_items`+filter, args...)
+	if err != nil {
+		return model.ReviewSLAMetrics{}, err
+	}
+	defer rows.Close()
+	items := make([]model.ReviewSLAItem, 0)
+	for rows.Next() {
+		item, err := scanReviewSLAItem(rows)
+		if err != nil {
+			return model.ReviewSLAMetrics{}, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ReviewSLAMetrics{}, err
+	}
+	return model.BuildReviewSLAMetrics(items), nil
+}
+
+// AdminRunReviewSLAEscalation marks overdue items BREACHED and escalates
+// them. A policy's backup reviewer takes over a breached review task; with
+// only a backup role set, every holder of the role is told instead. The
+// original assignee is told either way.
+func (r *MySQLGrowthRepo) AdminRunReviewSLAEscalation() (string, error) {
+	now := time.Now()
+	rows, err := r.db.Query(`SELECT `+reviewSLAItemColumns+` FROM review_sla_items WHERE status = 'OPEN' AND due_at <= ? ORDER BY due_at ASC LIMIT ?`, now, reviewSLAEscalationBatch)
+	if err != nil {
+		return "", err
+	}
+	items := make([]model.ReviewSLAItem, 0)
+	for rows.Next() {
+		item, err := scanReviewSLAItem(rows)
+		if err != nil {
+			rows.Close()
+			return "", err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	policies := map[string]model.ReviewSLAPolicy{}
+	if list, err := r.AdminListReviewSLAPolicies(); err == nil {
+		for _, policy := range list {
+			policies[policy.Module] = policy
+		}
+	}
+
+	breached, escalated := 0, 0
+	for _, item := range items {
+		res, err := r.db.Exec("UPDATE review_sla_items SET status = 'BREACHED', breached_at = ?, breached_assignee_id = assignee_id WHERE id = ? AND status = 'OPEN'", now, item.ID)
+		if err != nil {
+			return fmt.Sprintf("breached=%d escalated=%d", breached, escalated), err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			continue
+		}
+		breached++
+
+		escalatedTo, receivers := r.reviewSLAEscalationTarget(policies[item.Module], item)
+		if escalatedTo != "" {
+			assigneeID := item.AssigneeID
+			if !strings.HasPrefix(escalatedTo, "ROLE:") && item.SubjectType == model.ReviewSLASubjectReviewTask {
+				if _, err := r.db.Exec("UPDATE review_tasks SET reviewer_id = ?, updated_at = ? WHERE id = ? AND status = 'PENDING'", escalatedTo, now, item.SubjectID); err != nil {
+					return fmt.Sprintf("breached=%d escalated=%d", breached, escalated), err
+				}
+				assigneeID = escalatedTo
+			}
+			if _, err := r.db.Exec("UPDATE review_sla_items SET escalated_to = ?, escalated_at = ?, assignee_id = ? WHERE id = ?", escalatedTo, now, assigneeID, item.ID); err != nil {
+				return fmt.Sprintf("breached=%d escalated=%d", breached, escalated), err
+			}
+			escalated++
+		}
+		for _, message := range buildReviewSLAMessages(item, escalatedTo, receivers) {
+			_ = r.AdminCreateWorkflowMessage(message.ReviewID, message.TargetID, message.Module, message.ReceiverID, "system", message.EventType, message.Title, message.Content)
+		}
+	}
+	return fmt.Sprintf("breached=%d escalated=%d", breached, escalated), nil
+}
+
+// reviewSLAEscalationTarget picks who a breached item goes to. It returns
+// the backup reviewer's ID, or "ROLE:<key>" with the role's holders.
+func (r *MySQLGrowthRepo) reviewSLAEscalationTarget(policy model.ReviewSLAPolicy, item model.ReviewSLAItem) (string, []string) {
+	if policy.Status != model.ApprovalPolicyStatusActive {
+		return "", nil
+	}
+	if policy.BackupReviewerID != "" && policy.BackupReviewerID != item.AssigneeID {
+		return policy.BackupReviewerID, []string{policy.BackupReviewerID}
+	}
+	if policy.BackupRole == "" {
+		return "", nil
+	}
+	rows, err := r.db.Query(`
+SELECT DISTINCT ur.user_id
+FROM rbac_user_roles ur
+JOIN rbac_roles r ON r.id = ur.role_id
+WHERE r.role_key = ? AND r.status = 'ACTIVE'
+ORDER BY ur.user_id ASC`, policy.BackupRole)
+	if err != nil {
+		return "ROLE:" + policy.BackupRole, nil
+	}
+	defer rows.Close()
+	receivers := make([]string, 0)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err == nil {
+			receivers = append(receivers, userID)
+		}
+	}
+	return "ROLE:" + policy.BackupRole, receivers
+}
+
+// openReviewSLA starts the SLA clock of a new work item when its module has
+// an active policy. Modules without one are not tracked.
+func (r *MySQLGrowthRepo) openReviewSLA(subjectType string, subjectID string, module string, targetID string, priority string, assigneeID string, startedAt time.Time) error {
+	policy, err := r.getReviewSLAPolicy(module)
+	if errors.Is(err, model.ErrReviewSLAPolicyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if policy.Status != model.ApprovalPolicyStatusActive {
+		return nil
+	}
+	priority = model.NormalizeReviewSLAPriority(priority)
+	_, err = r.db.Exec(`
+INSERT INTO review_sla_items (id, subject_type, subject_id, module, target_id, priority, assignee_id, status, started_at, due_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, 'OPEN', ?, ?)
+ON DUPLICATE KEY UPDATE id = id`,
+		newID("sla"), subjectType, subjectID, policy.Module, strings.TrimSpace(targetID), priority, strings.TrimSpace(assigneeID), startedAt, policy.DueAt(startedAt, priority),
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) assignReviewSLA(subjectType string, subjectID string, assigneeID string) error {
+	_, err := r.db.Exec(
+		"UPDATE review_sla_items SET assignee_id = ? WHERE subject_type = ? AND subject_id = ? AND status IN ('OPEN', 'BREACHED')",
+		strings.TrimSpace(assigneeID), subjectType, subjectID,
+	)
+	return err
+}
+
+// resolveReviewSLA stops the clock of one work item. Items resolved after
+// their due time, or already marked BREACHED, count as MISSED against whoever
+// held them at breach time; an item resolved late before the escalation job
+// saw it was still held by its current assignee.
+func (r *MySQLGrowthRepo) resolveReviewSLA(subjectType string, subjectID string, resolvedBy string, resolvedAt time.Time) error {
+	return r.closeReviewSLAItems("subject_type = ? AND subject_id = ?", []interface{}{subjectType, subjectID}, resolvedBy, resolvedAt)
+}
+
+// resolveReviewSLAByTarget stops the clock of every open item on a target,
+// e.g. when a stock event is reviewed directly rather than through its task.
+func (r *MySQLGrowthRepo) resolveReviewSLAByTarget(module string, targetID string, resolvedBy string, resolvedAt time.Time) error {
+	return r.closeReviewSLAItems("module = ? AND target_id = ?", []interface{}{strings.ToUpper(strings.TrimSpace(module)), strings.TrimSpace(targetID)}, resolvedBy, resolvedAt)
+}
+
+func (r *MySQLGrowthRepo) closeReviewSLAItems(where string, args []interface{}, resolvedBy string, resolvedAt time.Time) error {
+	_, err := r.db.Exec(`
+UPDATE review_sla_items
+SET breached_assignee_id = CASE WHEN status = 'OPEN' AND due_at < ? THEN assignee_id ELSE breached_assignee_id END,
+	status = CASE WHEN status = 'BREACHED' OR due_at < ? THEN 'MISSED' ELSE 'MET' END, resolved_by = ?, resolved_at = ?
+WHERE `+where+` AND status IN ('OPEN', 'BREACHED')`,
+		append([]interface{}{resolvedAt, resolvedAt, strings.TrimSpace(resolvedBy), resolvedAt}, args...)...,
+	)
+	return err
+}
+
+func buildReviewSLAItemFilter(module string, status string, assigneeID string) (string, []interface{}) {
+	args := []interface{}{}
+	filter := " WHERE 1=1"
+	if module = strings.ToUpper(strings.TrimSpace(module)); module != "" {
+		filter += " AND module = ?"
+		args = append(args, module)
+	}
+	if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+		if strings.Contains(status, ",") {
+			filter += " AND FIND_IN_SET(status, ?) > 0"
+		} else {
+			filter += " AND status = ?"
+		}
+		args = append(args, status)
+	}
+	if assigneeID = strings.TrimSpace(assigneeID); assigneeID != "" {
+		filter += " AND assignee_id = ?"
+		args = append(args, assigneeID)
+	}
+	return filter, args
+}
+
+type reviewSLAMessage struct {
+	ReviewID   string
+	TargetID   string
+	Module     string
+	ReceiverID string
+	EventType  string
+	Title      string
+	Content    string
+}
+
+// buildReviewSLAMessages tells the escalation receivers, and the original
+// assignee when they are not one of them, that an item breached its SLA.
+func buildReviewSLAMessages(item model.ReviewSLAItem, escalatedTo string, receivers []string) []reviewSLAMessage {
+	reviewID := ""
+	if item.SubjectType == model.ReviewSLASubjectReviewTask {
+		reviewID = item.SubjectID
+	}
+	content := fmt.Sprintf("%s %s（%s优先级）已超过SLA截止时间 %s", item.Module, item.TargetID, item.Priority, item.DueAt)
+	messages := make([]reviewSLAMessage, 0, len(receivers)+1)
+	notified := map[string]bool{}
+	for _, receiverID := range receivers {
+		if receiverID == "" || notified[receiverID] {
+			continue
+		}
+		notified[receiverID] = true
+		messages = append(messages, reviewSLAMessage{
+			ReviewID: reviewID, TargetID: item.TargetID, Module: item.Module, ReceiverID: receiverID,
+			EventType: "SLA_ESCALATED", Title: "审核超时已升级", Content: content + "，已升级至 " + escalatedTo,
+		})
+	}
+	if item.AssigneeID != "" && !notified[item.AssigneeID] {
+		messages = append(messages, reviewSLAMessage{
+			ReviewID: reviewID, TargetID: item.TargetID, Module: item.Module, ReceiverID: item.AssigneeID,
+			EventType: "SLA_BREACHED", Title: "审核任务已超时", Content: content,
+		})
+	}
+	return messages
+}
+
+func formatReviewSLAWorkdays(days []int) string {
+	parts := make([]string, 0, len(days))
+	for _, day := range days {
+		parts = append(parts, strconv.Itoa(day))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseReviewSLAWorkdays(raw string) []int {
+	days := make([]int, 0, 7)
+	for _, part := range strings.Split(raw, ",") {
+		if day, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func scanReviewSLAPolicy(scanner interface{ Scan(dest ...any) error }) (model.ReviewSLAPolicy, error) {
+	var item model.ReviewSLAPolicy
+	var workdays string
+	var updatedAt time.Time
+	if err := scanner.Scan(
+		&item.Module, &item.Status, &item.HighMinutes, &item.NormalMinutes, &item.LowMinutes, &item.BusinessHoursOnly,
+		&item.BusinessStart, &item.BusinessEnd, &workdays, &item.Timezone, &item.BackupReviewerID, &item.BackupRole,
+		&item.UpdatedBy, &updatedAt,
+	); err != nil {
+		return model.ReviewSLAPolicy{}, err
+	}
+	item.Workdays = parseReviewSLAWorkdays(workdays)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return item, nil
+}
+
+func scanReviewSLAItem(scanner interface{ Scan(dest ...any) error }) (model.ReviewSLAItem, error) {
+	var item model.ReviewSLAItem
+	var startedAt, dueAt time.Time
+	var breachedAt, escalatedAt, resolvedAt sql.NullTime
+	if err := scanner.Scan(
+		&item.ID, &item.SubjectType, &item.SubjectID, &item.Module, &item.TargetID, &item.Priority, &item.AssigneeID, &item.Status,
+		&startedAt, &dueAt, &breachedAt, &item.BreachedAssigneeID, &item.EscalatedTo, &escalatedAt, &item.ResolvedBy, &resolvedAt,
+	); err != nil {
+		return model.ReviewSLAItem{}, err
+	}
+	item.StartedAt = startedAt.Format(time.RFC3339)
+	item.DueAt = dueAt.Format(time.RFC3339)
+	if breachedAt.Valid {
+		item.BreachedAt = breachedAt.Time.Format(time.RFC3339)
+	}
+	if escalatedAt.Valid {
+		item.EscalatedAt = escalatedAt.Time.Format(time.RFC3339)
+	}
+	if resolvedAt.Valid {
+		item.ResolvedAt = resolvedAt.Time.Format(time.RFC3339)
+	}
+	return item, nil
+}
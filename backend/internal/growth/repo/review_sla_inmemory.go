@@ -0,0 +1,240 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// seedReviewSLAPolicies mirrors the policies seeded by the 20260330_20
+// migration.
+func (r *InMemoryGrowthRepo) seedReviewSLAPolicies() {
+	now := time.Now().Format(time.RFC3339)
+	marketHours := []int{1, 2, 3, 4, 5}
+	everyDay := []int{1, 2, 3, 4, 5, 6, 7}
+	seed := []model.ReviewSLAPolicy{
+		{Module: "STOCK_EVENT", HighMinutes: 60, NormalMinutes: 240, LowMinutes: 1440, BusinessHoursOnly: true, BusinessStart: "09:00", BusinessEnd: "17:30", Workdays: marketHours, BackupRole: "SUPER_ADMIN"},
+		{Module: "STOCK", HighMinutes: 120, NormalMinutes: 480, LowMinutes: 1440, BusinessHoursOnly: true, BusinessStart: "09:00", BusinessEnd: "17:30", Workdays: marketHours, BackupRole: "SUPER_ADMIN"},
+		{Module: "FUTURES", HighMinutes: 120, NormalMinutes: 480, LowMinutes: 1440, BusinessHoursOnly: true, BusinessStart: "09:00", BusinessEnd: "17:30", Workdays: marketHours, BackupRole: "SUPER_ADMIN"},
+		{Module: "NEWS", HighMinutes: 60, NormalMinutes: 240, LowMinutes: 1440, BusinessStart: "09:00", BusinessEnd: "18:00", Workdays: everyDay, BackupRole: "OPS_ADMIN"},
+		{Module: model.ReviewSLAModuleCommunity, HighMinutes: 120, NormalMinutes: 720, LowMinutes: 2880, BusinessStart: "09:00", BusinessEnd: "18:00", Workdays: everyDay, BackupRole: "OPS_ADMIN"},
+	}
+	for _, item := range seed {
+		item.Status = model.ApprovalPolicyStatusActive
+		item.Timezone = "Asia/Shanghai"
+		item.UpdatedBy = "system"
+		item.UpdatedAt = now
+		r.reviewSLAPolicies[item.Module] = item
+	}
+}
+
+func (r *InMemoryGrowthRepo) AdminListReviewSLAPolicies() ([]model.ReviewSLAPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]model.ReviewSLAPolicy, 0, len(r.reviewSLAPolicies))
+	for _, item := range r.reviewSLAPolicies {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Module < items[j].Module })
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminSaveReviewSLAPolicy(item model.ReviewSLAPolicy, operator string) (model.ReviewSLAPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item.UpdatedBy = strings.TrimSpace(operator)
+	item.UpdatedAt = time.Now().Format(time.RFC3339)
+	r.reviewSLAPolicies[item.Module] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListReviewSLAItems(module string, status string, assigneeID string, page int, pageSize int) ([]model.ReviewSLAItem, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	module = strings.ToUpper(strings.TrimSpace(module))
+	statuses := map[string]bool{}
+	for _, value := range strings.Split(strings.ToUpper(strings.TrimSpace(status)), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			statuses[value] = true
+		}
+	}
+	assigneeID = strings.TrimSpace(assigneeID)
+	items := make([]model.ReviewSLAItem, 0)
+	for _, item := range r.reviewSLAItems {
+		if module != "" && item.Module != module {
+			continue
+		}
+		if len(statuses) > 0 && !statuses[item.Status] {
+			continue
+		}
+		if assigneeID != "" && item.AssigneeID != assigneeID {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].DueAt != items[j].DueAt {
+			return items[i].DueAt < items[j].DueAt
+		}
+		return items[i].ID < items[j].ID
+	})
+	total := len(items)
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetReviewSLAMetrics(module string, reviewerID string, days int) (model.ReviewSLAMetrics, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	module = strings.ToUpper(strings.TrimSpace(module))
+	reviewerID = strings.TrimSpace(reviewerID)
+	since := time.Now().AddDate(0, 0, -days)
+	items := make([]model.ReviewSLAItem, 0, len(r.reviewSLAItems))
+	for _, item := range r.reviewSLAItems {
+		open := item.Status == model.ReviewSLAStatusOpen || item.Status == model.ReviewSLAStatusBreached
+		if started, err := time.Parse(time.RFC3339, item.StartedAt); !open && (err != nil || started.Before(since)) {
+			continue
+		}
+		if module != "" && item.Module != module {
+			continue
+		}
+		if reviewerID != "" && item.ReviewerID() != reviewerID {
+			continue
+		}
+		items = append(items, item)
+	}
+	return model.BuildReviewSLAMetrics(items), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminRunReviewSLAEscalation() (string, error) {
+	r.mu.Lock()
+	now := time.Now()
+	messages := make([]reviewSLAMessage, 0)
+	breached, escalated := 0, 0
+	for id, item := range r.reviewSLAItems {
+		due, err := time.Parse(time.RFC3339, item.DueAt)
+		if item.Status != model.ReviewSLAStatusOpen || err != nil || due.After(now) {
+			continue
+		}
+		item.Status = model.ReviewSLAStatusBreached
+		item.BreachedAt = now.Format(time.RFC3339)
+		item.BreachedAssigneeID = item.AssigneeID
+		breached++
+
+		escalatedTo, receivers := r.reviewSLAEscalationTargetLocked(r.reviewSLAPolicies[item.Module], item)
+		messages = append(messages, buildReviewSLAMessages(item, escalatedTo, receivers)...)
+		if escalatedTo != "" {
+			if !strings.HasPrefix(escalatedTo, "ROLE:") && item.SubjectType == model.ReviewSLASubjectReviewTask {
+				if task, ok := r.reviewTasks[item.SubjectID]; ok && task.Status == "PENDING" {
+					task.ReviewerID = escalatedTo
+					r.reviewTasks[task.ID] = task
+				}
+				item.AssigneeID = escalatedTo
+			}
+			item.EscalatedTo = escalatedTo
+			item.EscalatedAt = now.Format(time.RFC3339)
+			escalated++
+		}
+		r.reviewSLAItems[id] = item
+	}
+	r.mu.Unlock()
+
+	for _, message := range messages {
+		_ = r.AdminCreateWorkflowMessage(message.ReviewID, message.TargetID, message.Module, message.ReceiverID, "system", message.EventType, message.Title, message.Content)
+	}
+	return fmt.Sprintf("breached=%d escalated=%d", breached, escalated), nil
+}
+
+func (r *InMemoryGrowthRepo) reviewSLAEscalationTargetLocked(policy model.ReviewSLAPolicy, item model.ReviewSLAItem) (string, []string) {
+	if policy.Status != model.ApprovalPolicyStatusActive {
+		return "", nil
+	}
+	if policy.BackupReviewerID != "" && policy.BackupReviewerID != item.AssigneeID {
+		return policy.BackupReviewerID, []string{policy.BackupReviewerID}
+	}
+	if policy.BackupRole == "" {
+		return "", nil
+	}
+	receivers := make([]string, 0)
+	for userID, roles := range r.approverRoles {
+		for _, role := range roles {
+			if role == policy.BackupRole {
+				receivers = append(receivers, userID)
+				break
+			}
+		}
+	}
+	sort.Strings(receivers)
+	return "ROLE:" + policy.BackupRole, receivers
+}
+
+func (r *InMemoryGrowthRepo) openReviewSLALocked(subjectType string, subjectID string, module string, targetID string, priority string, assigneeID string, startedAt time.Time) {
+	policy, ok := r.reviewSLAPolicies[strings.ToUpper(strings.TrimSpace(module))]
+	if !ok || policy.Status != model.ApprovalPolicyStatusActive {
+		return
+	}
+	for _, item := range r.reviewSLAItems {
+		if item.SubjectType == subjectType && item.SubjectID == subjectID {
+			return
+		}
+	}
+	priority = model.NormalizeReviewSLAPriority(priority)
+	item := model.ReviewSLAItem{
+		ID:          newID("sla"),
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Module:      policy.Module,
+		TargetID:    strings.TrimSpace(targetID),
+		Priority:    priority,
+		AssigneeID:  strings.TrimSpace(assigneeID),
+		Status:      model.ReviewSLAStatusOpen,
+		StartedAt:   startedAt.Format(time.RFC3339),
+		DueAt:       policy.DueAt(startedAt, priority).Format(time.RFC3339),
+	}
+	r.reviewSLAItems[item.ID] = item
+}
+
+func (r *InMemoryGrowthRepo) assignReviewSLALocked(subjectType string, subjectID string, assigneeID string) {
+	for id, item := range r.reviewSLAItems {
+		if item.SubjectType == subjectType && item.SubjectID == subjectID && (item.Status == model.ReviewSLAStatusOpen || item.Status == model.ReviewSLAStatusBreached) {
+			item.AssigneeID = strings.TrimSpace(assigneeID)
+			r.reviewSLAItems[id] = item
+		}
+	}
+}
+
+// resolveReviewSLALocked stops the clock of every open item accepted by
+// match; see MySQLGrowthRepo.resolveReviewSLA.
+func (r *InMemoryGrowthRepo) resolveReviewSLALocked(match func(model.ReviewSLAItem) bool, resolvedBy string, resolvedAt time.Time) {
+	for id, item := range r.reviewSLAItems {
+		if item.Status != model.ReviewSLAStatusOpen && item.Status != model.ReviewSLAStatusBreached {
+			continue
+		}
+		if !match(item) {
+			continue
+		}
+		if item.Status == model.ReviewSLAStatusOpen && item.ResolvedStatus(resolvedAt) == model.ReviewSLAStatusMissed {
+			item.BreachedAssigneeID = item.AssigneeID
+		}
+		item.Status = item.ResolvedStatus(resolvedAt)
+		item.ResolvedBy = strings.TrimSpace(resolvedBy)
+		item.ResolvedAt = resolvedAt.Format(time.RFC3339)
+		r.reviewSLAItems[id] = item
+	}
+}
+
+func reviewSLASubjectMatcher(subjectType string, subjectID string) func(model.ReviewSLAItem) bool {
+	return func(item model.ReviewSLAItem) bool {
+		return item.SubjectType == subjectType && item.SubjectID == subjectID
+	}
+}
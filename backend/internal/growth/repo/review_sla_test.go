@@ -0,0 +1,236 @@
+package repo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func TestReviewSLAPolicyDueAtPausesOutsideBusinessHours(t *testing.T) {
+	policy, err := model.ReviewSLAPolicy{
+		Module:            "STOCK_EVENT",
+		HighMinutes:       60,
+		NormalMinutes:     240,
+		LowMinutes:        1440,
+		BusinessHoursOnly: true,
+		BusinessStart:     "09:00",
+		BusinessEnd:       "17:30",
+	}.Normalized()
+	if err != nil {
+		t.Fatalf("Normalized() error = %v", err)
+	}
+	cst := time.FixedZone("CST", 8*60*60)
+	cases := []struct {
+		name  string
+		start time.Time
+		want  time.Time
+	}{
+		{"within hours", time.Date(2026, 3, 30, 10, 0, 0, 0, cst), time.Date(2026, 3, 30, 11, 0, 0, 0, cst)},
+		{"overnight waits for the open", time.Date(2026, 3, 31, 2, 15, 0, 0, cst), time.Date(2026, 3, 31, 10, 0, 0, 0, cst)},
+		{"friday close carries over the weekend", time.Date(2026, 3, 27, 17, 0, 0, 0, cst), time.Date(2026, 3, 30, 9, 30, 0, 0, cst)},
+		{"saturday", time.Date(2026, 3, 28, 12, 0, 0, 0, cst), time.Date(2026, 3, 30, 10, 0, 0, 0, cst)},
+	}
+	for _, tc := range cases {
+		if got := policy.DueAt(tc.start, "HIGH"); !got.Equal(tc.want) {
+			t.Fatalf("%s: DueAt() = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	policy.BusinessHoursOnly = false
+	start := time.Date(2026, 3, 28, 12, 0, 0, 0, cst)
+	if got := policy.DueAt(start, "LOW"); !got.Equal(start.Add(24 * time.Hour)) {
+		t.Fatalf("expected calendar due time without business hours, got %s", got)
+	}
+	if _, err := (model.ReviewSLAPolicy{Module: "NEWS", HighMinutes: 1, NormalMinutes: 1, LowMinutes: 1, BusinessStart: "18:00", BusinessEnd: "09:00"}).Normalized(); err == nil {
+		t.Fatal("expected inverted business hours to be rejected")
+	}
+	if _, err := (model.ReviewSLAPolicy{Module: "NEWS", HighMinutes: 1, NormalMinutes: 1, LowMinutes: 1, Timezone: "Mars/Olympus"}).Normalized(); !errors.Is(err, model.ErrReviewSLAPolicyInvalid) {
+		t.Fatalf("expected unknown timezone to be rejected, got %v", err)
+	}
+}
+
+func TestInMemoryReviewSLABreachEscalatesAndScoresReviewers(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	if _, err := repo.AdminSaveReviewSLAPolicy(model.ReviewSLAPolicy{
+		Module: "STOCK", Status: "ACTIVE", HighMinutes: 30, NormalMinutes: 60, LowMinutes: 120,
+		BusinessStart: "09:00", BusinessEnd: "18:00", Workdays: []int{1, 2, 3, 4, 5}, BackupReviewerID: "admin_009",
+	}, "admin_001"); err != nil {
+		t.Fatalf("AdminSaveReviewSLAPolicy() error = %v", err)
+	}
+
+	taskID, err := repo.AdminSubmitReviewTask("STOCK", "sr_sla_1", "admin_003", "admin_002", "", "HIGH")
+	if err != nil {
+		t.Fatalf("AdminSubmitReviewTask() error = %v", err)
+	}
+	lateReport, err := repo.CreateCommunityReport(model.CommunityReportCreateInput{ReporterUserID: "u_1", TargetType: "TOPIC", TargetID: "ct_sla_1", Reason: "spam"})
+	if err != nil {
+		t.Fatalf("CreateCommunityReport() error = %v", err)
+	}
+	onTimeReport, err := repo.CreateCommunityReport(model.CommunityReportCreateInput{ReporterUserID: "u_2", TargetType: "TOPIC", TargetID: "ct_sla_2", Reason: "abuse"})
+	if err != nil {
+		t.Fatalf("CreateCommunityReport() error = %v", err)
+	}
+
+	overdue := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	for id, item := range repo.reviewSLAItems {
+		if item.SubjectID == taskID || item.SubjectID == lateReport.ID {
+			item.DueAt = overdue
+			repo.reviewSLAItems[id] = item
+		}
+	}
+
+	summary, err := repo.AdminRunReviewSLAEscalation()
+	if err != nil {
+		t.Fatalf("AdminRunReviewSLAEscalation() error = %v", err)
+	}
+	if summary != "breached=2 escalated=2" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if summary, _ := repo.AdminRunReviewSLAEscalation(); summary != "breached=0 escalated=0" {
+		t.Fatalf("expected breached items to be escalated once, got %q", summary)
+	}
+	if task := repo.reviewTasks[taskID]; task.ReviewerID != "admin_009" {
+		t.Fatalf("expected task to move to the backup reviewer, got %q", task.ReviewerID)
+	}
+	escalatedTo := map[string]string{}
+	for _, message := range repo.workflowMessages {
+		escalatedTo[message.ReceiverID+"|"+message.TargetID] = message.EventType
+	}
+	if escalatedTo["admin_009|sr_sla_1"] != "SLA_ESCALATED" || escalatedTo["admin_002|sr_sla_1"] != "SLA_BREACHED" {
+		t.Fatalf("expected backup reviewer and original reviewer to be told, got %v", escalatedTo)
+	}
+	if escalatedTo["admin_002|ct_sla_1"] != "SLA_ESCALATED" {
+		t.Fatalf("expected OPS_ADMIN holders to be told about the report, got %v", escalatedTo)
+	}
+
+	if err := repo.AdminReviewTaskDecision(taskID, "APPROVED", "admin_009", ""); err != nil {
+		t.Fatalf("AdminReviewTaskDecision() error = %v", err)
+	}
+	if err := repo.AdminReviewCommunityReport(lateReport.ID, "RESOLVED", "admin_002", ""); err != nil {
+		t.Fatalf("AdminReviewCommunityReport() error = %v", err)
+	}
+	if err := repo.AdminReviewCommunityReport(onTimeReport.ID, "REJECTED", "admin_002", ""); err != nil {
+		t.Fatalf("AdminReviewCommunityReport() error = %v", err)
+	}
+
+	metrics, err := repo.AdminGetReviewSLAMetrics("", "", 30)
+	if err != nil {
+		t.Fatalf("AdminGetReviewSLAMetrics() error = %v", err)
+	}
+	if metrics.Resolved != 3 || metrics.Met != 1 || metrics.Missed != 2 || metrics.Open != 0 {
+		t.Fatalf("unexpected totals %+v", metrics)
+	}
+	stats := map[string]model.ReviewerSLAStat{}
+	for _, stat := range metrics.Reviewers {
+		stats[stat.ReviewerID] = stat
+	}
+	if stats["admin_002"].Resolved != 3 || stats["admin_002"].Missed != 2 {
+		t.Fatalf("unexpected admin_002 stats %+v", stats["admin_002"])
+	}
+	if _, ok := stats["admin_009"]; ok {
+		t.Fatalf("expected the backup reviewer not to be charged for the escalated miss, got %+v", stats["admin_009"])
+	}
+}
+
+func TestInMemoryReviewSLAMissAfterEscalationIsChargedToBreachedAssignee(t *testing.T) {
+	repo := NewInMemoryGrowthRepo()
+	if _, err := repo.AdminSaveReviewSLAPolicy(model.ReviewSLAPolicy{
+		Module: "NEWS", Status: "ACTIVE", HighMinutes: 30, NormalMinutes: 60, LowMinutes: 120,
+		BusinessStart: "09:00", BusinessEnd: "18:00", Workdays: []int{1, 2, 3, 4, 5, 6, 7}, BackupReviewerID: "admin_009",
+	}, "admin_001"); err != nil {
+		t.Fatalf("AdminSaveReviewSLAPolicy() error = %v", err)
+	}
+	escalatedTask, err := repo.AdminSubmitReviewTask("NEWS", "na_sla_1", "admin_003", "admin_002", "", "HIGH")
+	if err != nil {
+		t.Fatalf("AdminSubmitReviewTask() error = %v", err)
+	}
+	lateTask, err := repo.AdminSubmitReviewTask("NEWS", "na_sla_2", "admin_003", "admin_004", "", "HIGH")
+	if err != nil {
+		t.Fatalf("AdminSubmitReviewTask() error = %v", err)
+	}
+	setOverdue := func(taskID string) {
+		for id, item := range repo.reviewSLAItems {
+			if item.SubjectID == taskID {
+				item.DueAt = time.Now().Add(-time.Minute).Format(time.RFC3339)
+				repo.reviewSLAItems[id] = item
+			}
+		}
+	}
+
+	// The first task breaches and moves to the backup, who resolves it. The
+	// second is resolved late by its own reviewer before the job sees it.
+	setOverdue(escalatedTask)
+	if _, err := repo.AdminRunReviewSLAEscalation(); err != nil {
+		t.Fatalf("AdminRunReviewSLAEscalation() error = %v", err)
+	}
+	if err := repo.AdminReviewTaskDecision(escalatedTask, "APPROVED", "admin_009", ""); err != nil {
+		t.Fatalf("AdminReviewTaskDecision() error = %v", err)
+	}
+	setOverdue(lateTask)
+	if err := repo.AdminReviewTaskDecision(lateTask, "APPROVED", "admin_004", ""); err != nil {
+		t.Fatalf("AdminReviewTaskDecision() error = %v", err)
+	}
+
+	for reviewerID, wantMissed := range map[string]int{"admin_002": 1, "admin_004": 1, "admin_009": 0} {
+		metrics, err := repo.AdminGetReviewSLAMetrics("NEWS", reviewerID, 30)
+		if err != nil {
+			t.Fatalf("AdminGetReviewSLAMetrics(%s) error = %v", reviewerID, err)
+		}
+		if metrics.Missed != wantMissed {
+			t.Fatalf("%s: expected %d missed, got %+v", reviewerID, wantMissed, metrics)
+		}
+	}
+}
+
+func TestMySQLAdminRunReviewSLAEscalationReassignsToBackupReviewer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_sla_items WHERE status = 'OPEN' AND due_at <= ?")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subject_type", "subject_id", "module", "target_id", "priority", "assignee_id", "status",
+			"started_at", "due_at", "breached_at", "breached_assignee_id", "escalated_to", "escalated_at", "resolved_by", "resolved_at",
+		}).AddRow("sla_1", "REVIEW_TASK", "rt_9", "STOCK_EVENT", "sec_9", "HIGH", "admin_002", "OPEN",
+			started, started.Add(time.Hour), nil, "", "", nil, "", nil))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_sla_policies ORDER BY module ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"module", "status", "high_minutes", "normal_minutes", "low_minutes", "business_hours_only", "business_start", "business_end",
+			"workdays", "timezone", "backup_reviewer_id", "backup_role", "updated_by", "updated_at",
+		}).AddRow("STOCK_EVENT", "ACTIVE", 60, 240, 1440, true, "09:00", "17:30", "1,2,3,4,5", "Asia/Shanghai", "admin_009", "SUPER_ADMIN", "system", started))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE review_sla_items SET status = 'BREACHED', breached_at = ?, breached_assignee_id = assignee_id WHERE id = ? AND status = 'OPEN'")).
+		WithArgs(sqlmock.AnyArg(), "sla_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE review_tasks SET reviewer_id = ?, updated_at = ? WHERE id = ? AND status = 'PENDING'")).
+		WithArgs("admin_009", sqlmock.AnyArg(), "rt_9").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE review_sla_items SET escalated_to = ?, escalated_at = ?, assignee_id = ? WHERE id = ?")).
+		WithArgs("admin_009", sqlmock.AnyArg(), "admin_009", "sla_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO workflow_messages")).
+		WithArgs(sqlmock.AnyArg(), "rt_9", "sec_9", "STOCK_EVENT", "admin_009", "system", "SLA_ESCALATED", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO workflow_messages")).
+		WithArgs(sqlmock.AnyArg(), "rt_9", "sec_9", "STOCK_EVENT", "admin_002", "system", "SLA_BREACHED", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := &MySQLGrowthRepo{db: db}
+	summary, err := repo.AdminRunReviewSLAEscalation()
+	if err != nil {
+		t.Fatalf("AdminRunReviewSLAEscalation() error = %v", err)
+	}
+	if summary != "breached=1 escalated=1" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
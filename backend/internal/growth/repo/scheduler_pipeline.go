@@ -0,0 +1,279 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// schedulerPipelineRunStaleAfter lets a new claim take over a run left
+// RUNNING by a crashed process.
+const schedulerPipelineRunStaleAfter = 6 * time.Hour
+
+const schedulerPipelineRunColumns = `id, pipeline_key, DATE_FORMAT(trade_date, '%Y-%m-%d'), status, trigger_source, operator_id, started_at, finished_at`
+
+func (r *MySQLGrowthRepo) AdminListSchedulerPipelines(status string) ([]model.SchedulerPipeline, error) {
+	query := `SELECT id, pipeline_key, display_name, status, CAST(nodes_json AS CHAR), updated_by, created_at, updated_at FROM scheduler_pipelines`
+	args := []interface{}{}
+	if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	rows, err := r.db.Query(query+" ORDER BY pipeline_key ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.SchedulerPipeline, 0)
+	for rows.Next() {
+		item, err := scanSchedulerPipeline(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminGetSchedulerPipeline(pipelineKey string) (model.SchedulerPipeline, error) {
+	item, err := scanSchedulerPipeline(r.db.QueryRow(`
+SELECT id, pipeline_key, display_name, status, CAST(nodes_json AS CHAR), updated_by, created_at, updated_at
+FROM scheduler_pipelines
+WHERE pipeline_key = ?`, strings.ToLower(strings.TrimSpace(pipelineKey))))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SchedulerPipeline{}, model.ErrSchedulerPipelineNotFound
+	}
+	return item, err
+}
+
+// AdminSaveSchedulerPipeline upserts by pipeline_key. Runs already created
+// keep the graph they started with.
+func (r *MySQLGrowthRepo) AdminSaveSchedulerPipeline(item model.SchedulerPipeline, operator string) (model.SchedulerPipeline, error) {
+	nodesJSON, err := json.Marshal(item.Nodes)
+	if err != nil {
+		return model.SchedulerPipeline{}, err
+	}
+	now := time.Now()
+	displayName := item.DisplayName
+	if displayName == "" {
+		displayName = item.PipelineKey
+	}
+	_, err = r.db.Exec(`
+INSERT INTO scheduler_pipelines (id, pipeline_key, display_name, status, nodes_json, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE display_name = VALUES(display_name), status = VALUES(status), nodes_json = VALUES(nodes_json), updated_by = VALUES(updated_by), updated_at = VALUES(updated_at)`,
+		newID("sp"), item.PipelineKey, displayName, item.Status, string(nodesJSON), strings.TrimSpace(operator), now, now,
+	)
+	if err != nil {
+		return model.SchedulerPipeline{}, err
+	}
+	return r.AdminGetSchedulerPipeline(item.PipelineKey)
+}
+
+func (r *MySQLGrowthRepo) AdminListSchedulerPipelineRuns(pipelineKey string, tradeDate string, status string, page int, pageSize int) ([]model.SchedulerPipelineRun, int, error) {
+	filter := " WHERE 1=1"
+	args := []interface{}{}
+	if pipelineKey = strings.ToLower(strings.TrimSpace(pipelineKey)); pipelineKey != "" {
+		filter += " AND pipeline_key = ?"
+		args = append(args, pipelineKey)
+	}
+	if tradeDate = strings.TrimSpace(tradeDate); tradeDate != "" {
+		filter += " AND trade_date = ?"
+		args = append(args, tradeDate)
+	}
+	if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+		filter += " AND status = ?"
+		args = append(args, status)
+	}
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM scheduler_pipeline_runs"+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, pageSize, (page-1)*pageSize)
+	rows, err := r.db.Query(`
+SELECT `+schedulerPipelineRunColumns+`
+FROM scheduler_pipeline_runs`+filter+`
+ORDER BY trade_date DESC, started_at DESC
+LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]model.SchedulerPipelineRun, 0)
+	for rows.Next() {
+		item, err := scanSchedulerPipelineRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *MySQLGrowthRepo) AdminGetSchedulerPipelineRun(runID string) (model.SchedulerPipelineRun, error) {
+	return r.loadSchedulerPipelineRun(`SELECT `+schedulerPipelineRunColumns+` FROM scheduler_pipeline_runs WHERE id = ?`, strings.TrimSpace(runID))
+}
+
+func (r *MySQLGrowthRepo) AdminFindSchedulerPipelineRun(pipelineKey string, tradeDate string) (model.SchedulerPipelineRun, error) {
+	return r.loadSchedulerPipelineRun(`SELECT `+schedulerPipelineRunColumns+` FROM scheduler_pipeline_runs WHERE pipeline_key = ? AND trade_date = ?`,
+		strings.ToLower(strings.TrimSpace(pipelineKey)), strings.TrimSpace(tradeDate))
+}
+
+func (r *MySQLGrowthRepo) loadSchedulerPipelineRun(query string, args ...interface{}) (model.SchedulerPipelineRun, error) {
+	item, err := scanSchedulerPipelineRun(r.db.QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SchedulerPipelineRun{}, model.ErrSchedulerPipelineRunNotFound
+	}
+	if err != nil {
+		return model.SchedulerPipelineRun{}, err
+	}
+	rows, err := r.db.Query(`
+SELECT id, run_id, seq, job_name, CAST(depends_on_json AS CHAR), status, attempt, job_run_id, blocked_by, result_summary, error_message, started_at, finished_at
+FROM scheduler_pipeline_node_runs
+WHERE run_id = ?
+ORDER BY seq ASC`, item.ID)
+	if err != nil {
+		return model.SchedulerPipelineRun{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var node model.SchedulerPipelineNodeRun
+		var dependsOn string
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(&node.ID, &node.RunID, &node.Seq, &node.JobName, &dependsOn, &node.Status, &node.Attempt, &node.JobRunID, &node.BlockedBy, &node.ResultSummary, &node.ErrorMessage, &startedAt, &finishedAt); err != nil {
+			return model.SchedulerPipelineRun{}, err
+		}
+		_ = json.Unmarshal([]byte(dependsOn), &node.DependsOn)
+		node.StartedAt = formatNullTime(startedAt)
+		node.FinishedAt = formatNullTime(finishedAt)
+		item.Nodes = append(item.Nodes, node)
+	}
+	return item, rows.Err()
+}
+
+// AdminClaimSchedulerPipelineRun marks the pipeline's run for tradeDate
+// RUNNING, creating it with one PENDING node per pipeline node on first use.
+// resetJobNames go back to PENDING so they run again. A run that is already
+// RUNNING, and not stale, returns ErrSchedulerPipelineRunBusy.
+func (r *MySQLGrowthRepo) AdminClaimSchedulerPipelineRun(pipeline model.SchedulerPipeline, tradeDate string, triggerSource string, operator string, resetJobNames []string) (model.SchedulerPipelineRun, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.SchedulerPipelineRun{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	triggerSource = strings.ToUpper(strings.TrimSpace(triggerSource))
+	operator = truncateByRunes(strings.TrimSpace(operator), 32)
+	runID := newID("spr")
+	res, err := tx.Exec(`
+INSERT IGNORE INTO scheduler_pipeline_runs (id, pipeline_key, trade_date, status, trigger_source, operator_id, started_at, finished_at, created_at)
+VALUES (?, ?, ?, 'RUNNING', ?, ?, ?, NULL, ?)`,
+		runID, pipeline.PipelineKey, tradeDate, triggerSource, operator, now, now,
+	)
+	if err != nil {
+		return model.SchedulerPipelineRun{}, err
+	}
+	if inserted, _ := res.RowsAffected(); inserted == 1 {
+		for i, node := range pipeline.Nodes {
+			dependsOn, _ := json.Marshal(append([]string{}, node.DependsOn...))
+			if _, err := tx.Exec(`
+INSERT INTO scheduler_pipeline_node_runs (id, run_id, seq, job_name, depends_on_json, status, attempt, job_run_id, blocked_by, result_summary, error_message, started_at, finished_at, updated_at)
+VALUES (?, ?, ?, ?, ?, 'PENDING', 0, '', '', '', '', NULL, NULL, ?)`,
+				newID("spn"), runID, i+1, node.JobName, string(dependsOn), now,
+			); err != nil {
+				return model.SchedulerPipelineRun{}, err
+			}
+		}
+	} else {
+		var status string
+		var startedAt time.Time
+		if err := tx.QueryRow(`
+SELECT id, status, started_at FROM scheduler_pipeline_runs WHERE pipeline_key = ? AND trade_date = ? FOR UPDATE`,
+			pipeline.PipelineKey, tradeDate,
+		).Scan(&runID, &status, &startedAt); err != nil {
+			return model.SchedulerPipelineRun{}, err
+		}
+		if status == model.SchedulerPipelineRunRunning && now.Sub(startedAt) < schedulerPipelineRunStaleAfter {
+			return model.SchedulerPipelineRun{}, model.ErrSchedulerPipelineRunBusy
+		}
+		if _, err := tx.Exec(`
+UPDATE scheduler_pipeline_runs
+SET status = 'RUNNING', trigger_source = ?, operator_id = ?, started_at = ?, finished_at = NULL
+WHERE id = ?`, triggerSource, operator, now, runID); err != nil {
+			return model.SchedulerPipelineRun{}, err
+		}
+		if len(resetJobNames) > 0 {
+			placeholders := strings.TrimSuffix(strings.Repeat("?,", len(resetJobNames)), ",")
+			args := []interface{}{now, runID}
+			for _, name := range resetJobNames {
+				args = append(args, name)
+			}
+			if _, err := tx.Exec(`
+UPDATE scheduler_pipeline_node_runs
+SET status = 'PENDING', job_run_id = '', blocked_by = '', result_summary = '', error_message = '', started_at = NULL, finished_at = NULL, updated_at = ?
+WHERE run_id = ? AND job_name IN (`+placeholders+`)`, args...); err != nil {
+				return model.SchedulerPipelineRun{}, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return model.SchedulerPipelineRun{}, err
+	}
+	return r.AdminGetSchedulerPipelineRun(runID)
+}
+
+func (r *MySQLGrowthRepo) AdminUpdateSchedulerPipelineNodeRun(item model.SchedulerPipelineNodeRun) error {
+	_, err := r.db.Exec(`
+UPDATE scheduler_pipeline_node_runs
+SET status = ?, attempt = ?, job_run_id = ?, blocked_by = ?, result_summary = ?, error_message = ?, started_at = ?, finished_at = ?, updated_at = ?
+WHERE id = ?`,
+		item.Status,
+		item.Attempt,
+		item.JobRunID,
+		item.BlockedBy,
+		truncateByRunes(normalizeUTF8Text(item.ResultSummary), 512),
+		truncateByRunes(normalizeUTF8Text(item.ErrorMessage), 512),
+		nullableTimeValue(item.StartedAt),
+		nullableTimeValue(item.FinishedAt),
+		time.Now(),
+		item.ID,
+	)
+	return err
+}
+
+func (r *MySQLGrowthRepo) AdminFinishSchedulerPipelineRun(runID string, status string) error {
+	_, err := r.db.Exec(`UPDATE scheduler_pipeline_runs SET status = ?, finished_at = ? WHERE id = ?`, status, time.Now(), strings.TrimSpace(runID))
+	return err
+}
+
+func scanSchedulerPipeline(scanner interface{ Scan(dest ...any) error }) (model.SchedulerPipeline, error) {
+	var item model.SchedulerPipeline
+	var nodesJSON string
+	var createdAt, updatedAt time.Time
+	if err := scanner.Scan(&item.ID, &item.PipelineKey, &item.DisplayName, &item.Status, &nodesJSON, &item.UpdatedBy, &createdAt, &updatedAt); err != nil {
+		return model.SchedulerPipeline{}, err
+	}
+	if err := json.Unmarshal([]byte(nodesJSON), &item.Nodes); err != nil {
+		return model.SchedulerPipeline{}, err
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return item, nil
+}
+
+func scanSchedulerPipelineRun(scanner interface{ Scan(dest ...any) error }) (model.SchedulerPipelineRun, error) {
+	var item model.SchedulerPipelineRun
+	var startedAt time.Time
+	var finishedAt sql.NullTime
+	if err := scanner.Scan(&item.ID, &item.PipelineKey, &item.TradeDate, &item.Status, &item.TriggerSource, &item.OperatorID, &startedAt, &finishedAt); err != nil {
+		return model.SchedulerPipelineRun{}, err
+	}
+	item.StartedAt = startedAt.Format(time.RFC3339)
+	item.FinishedAt = formatNullTime(finishedAt)
+	return item, nil
+}
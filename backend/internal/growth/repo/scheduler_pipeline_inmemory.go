@@ -0,0 +1,226 @@
+package repo
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+// seedSchedulerPipelines mirrors the daily_stock_market pipeline seeded by
+// the 20260330_14 migration.
+func (r *InMemoryGrowthRepo) seedSchedulerPipelines() {
+	now := time.Now().Format(time.RFC3339)
+	r.schedulerPipelines["daily_stock_market"] = model.SchedulerPipeline{
+		ID:          "sp_daily_stock_market",
+		PipelineKey: "daily_stock_market",
+		DisplayName: "每日股票行情与推荐流水线",
+		Status:      model.SchedulerPipelineStatusActive,
+		Nodes: []model.SchedulerPipelineNode{
+			{JobName: "stock_master_sync"},
+			{JobName: "stock_quotes_sync", DependsOn: []string{"stock_master_sync"}},
+			{JobName: "stock_daily_basic_sync", DependsOn: []string{"stock_quotes_sync"}},
+			{JobName: "stock_moneyflow_sync", DependsOn: []string{"stock_quotes_sync"}},
+			{JobName: "stock_truth_rebuild", DependsOn: []string{"stock_daily_basic_sync", "stock_moneyflow_sync"}},
+			{JobName: "stock_quant_rank", DependsOn: []string{"stock_truth_rebuild"}},
+			{JobName: "daily_stock_recommendation", DependsOn: []string{"stock_quant_rank"}},
+			{JobName: "stock_selection_run", DependsOn: []string{"daily_stock_recommendation"}},
+			{JobName: "stock_selection_publish_review", DependsOn: []string{"stock_selection_run"}},
+		},
+		UpdatedBy: "system",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (r *InMemoryGrowthRepo) AdminListSchedulerPipelines(status string) ([]model.SchedulerPipeline, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status = strings.ToUpper(strings.TrimSpace(status))
+	items := make([]model.SchedulerPipeline, 0, len(r.schedulerPipelines))
+	for _, item := range r.schedulerPipelines {
+		if status != "" && item.Status != status {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].PipelineKey < items[j].PipelineKey })
+	return items, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetSchedulerPipeline(pipelineKey string) (model.SchedulerPipeline, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.schedulerPipelines[strings.ToLower(strings.TrimSpace(pipelineKey))]
+	if !ok {
+		return model.SchedulerPipeline{}, model.ErrSchedulerPipelineNotFound
+	}
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminSaveSchedulerPipeline(item model.SchedulerPipeline, operator string) (model.SchedulerPipeline, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().Format(time.RFC3339)
+	if existing, ok := r.schedulerPipelines[item.PipelineKey]; ok {
+		item.ID = existing.ID
+		item.CreatedAt = existing.CreatedAt
+	} else {
+		item.ID = newID("sp")
+		item.CreatedAt = now
+	}
+	if item.DisplayName == "" {
+		item.DisplayName = item.PipelineKey
+	}
+	item.UpdatedBy = strings.TrimSpace(operator)
+	item.UpdatedAt = now
+	r.schedulerPipelines[item.PipelineKey] = item
+	return item, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminListSchedulerPipelineRuns(pipelineKey string, tradeDate string, status string, page int, pageSize int) ([]model.SchedulerPipelineRun, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pipelineKey = strings.ToLower(strings.TrimSpace(pipelineKey))
+	tradeDate = strings.TrimSpace(tradeDate)
+	status = strings.ToUpper(strings.TrimSpace(status))
+	items := make([]model.SchedulerPipelineRun, 0)
+	for _, item := range r.schedulerPipelineRuns {
+		if pipelineKey != "" && item.PipelineKey != pipelineKey {
+			continue
+		}
+		if tradeDate != "" && item.TradeDate != tradeDate {
+			continue
+		}
+		if status != "" && item.Status != status {
+			continue
+		}
+		item.Nodes = nil
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TradeDate != items[j].TradeDate {
+			return items[i].TradeDate > items[j].TradeDate
+		}
+		return items[i].StartedAt > items[j].StartedAt
+	})
+	total := len(items)
+	start := (page - 1) * pageSize
+	if start >= total {
+		return []model.SchedulerPipelineRun{}, total, nil
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return items[start:end], total, nil
+}
+
+func (r *InMemoryGrowthRepo) AdminGetSchedulerPipelineRun(runID string) (model.SchedulerPipelineRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.schedulerPipelineRuns[strings.TrimSpace(runID)]
+	if !ok {
+		return model.SchedulerPipelineRun{}, model.ErrSchedulerPipelineRunNotFound
+	}
+	return cloneSchedulerPipelineRun(item), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminFindSchedulerPipelineRun(pipelineKey string, tradeDate string) (model.SchedulerPipelineRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.findSchedulerPipelineRunLocked(strings.ToLower(strings.TrimSpace(pipelineKey)), strings.TrimSpace(tradeDate))
+	if !ok {
+		return model.SchedulerPipelineRun{}, model.ErrSchedulerPipelineRunNotFound
+	}
+	return cloneSchedulerPipelineRun(item), nil
+}
+
+func (r *InMemoryGrowthRepo) findSchedulerPipelineRunLocked(pipelineKey string, tradeDate string) (model.SchedulerPipelineRun, bool) {
+	for _, item := range r.schedulerPipelineRuns {
+		if item.PipelineKey == pipelineKey && item.TradeDate == tradeDate {
+			return item, true
+		}
+	}
+	return model.SchedulerPipelineRun{}, false
+}
+
+func (r *InMemoryGrowthRepo) AdminClaimSchedulerPipelineRun(pipeline model.SchedulerPipeline, tradeDate string, triggerSource string, operator string, resetJobNames []string) (model.SchedulerPipelineRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	item, ok := r.findSchedulerPipelineRunLocked(pipeline.PipelineKey, tradeDate)
+	if !ok {
+		item = model.SchedulerPipelineRun{ID: newID("spr"), PipelineKey: pipeline.PipelineKey, TradeDate: tradeDate}
+		for i, node := range pipeline.Nodes {
+			item.Nodes = append(item.Nodes, model.SchedulerPipelineNodeRun{
+				ID:        newID("spn"),
+				RunID:     item.ID,
+				Seq:       i + 1,
+				JobName:   node.JobName,
+				DependsOn: append([]string{}, node.DependsOn...),
+				Status:    model.SchedulerPipelineNodePending,
+			})
+		}
+	} else {
+		startedAt, _ := time.Parse(time.RFC3339, item.StartedAt)
+		if item.Status == model.SchedulerPipelineRunRunning && now.Sub(startedAt) < schedulerPipelineRunStaleAfter {
+			return model.SchedulerPipelineRun{}, model.ErrSchedulerPipelineRunBusy
+		}
+		item = cloneSchedulerPipelineRun(item)
+		reset := map[string]struct{}{}
+		for _, name := range resetJobNames {
+			reset[name] = struct{}{}
+		}
+		for i, node := range item.Nodes {
+			if _, ok := reset[node.JobName]; ok {
+				item.Nodes[i] = model.SchedulerPipelineNodeRun{
+					ID: node.ID, RunID: node.RunID, Seq: node.Seq, JobName: node.JobName, DependsOn: node.DependsOn,
+					Status: model.SchedulerPipelineNodePending, Attempt: node.Attempt,
+				}
+			}
+		}
+	}
+	item.Status = model.SchedulerPipelineRunRunning
+	item.TriggerSource = strings.ToUpper(strings.TrimSpace(triggerSource))
+	item.OperatorID = strings.TrimSpace(operator)
+	item.StartedAt = now.Format(time.RFC3339)
+	item.FinishedAt = ""
+	r.schedulerPipelineRuns[item.ID] = item
+	return cloneSchedulerPipelineRun(item), nil
+}
+
+func (r *InMemoryGrowthRepo) AdminUpdateSchedulerPipelineNodeRun(item model.SchedulerPipelineNodeRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.schedulerPipelineRuns[item.RunID]
+	if !ok {
+		return model.ErrSchedulerPipelineRunNotFound
+	}
+	for i, node := range run.Nodes {
+		if node.ID == item.ID {
+			run.Nodes[i] = item
+			return nil
+		}
+	}
+	return model.ErrSchedulerPipelineRunNotFound
+}
+
+func (r *InMemoryGrowthRepo) AdminFinishSchedulerPipelineRun(runID string, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.schedulerPipelineRuns[strings.TrimSpace(runID)]
+	if !ok {
+		return model.ErrSchedulerPipelineRunNotFound
+	}
+	run.Status = status
+	run.FinishedAt = time.Now().Format(time.RFC3339)
+	r.schedulerPipelineRuns[run.ID] = run
+	return nil
+}
+
+func cloneSchedulerPipelineRun(item model.SchedulerPipelineRun) model.SchedulerPipelineRun {
+	item.Nodes = append([]model.SchedulerPipelineNodeRun(nil), item.Nodes...)
+	return item
+}
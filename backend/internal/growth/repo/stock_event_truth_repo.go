@@ -248,6 +248,9 @@ INSERT INTO stock_event_reviews (
 	if err = tx.Commit(); err != nil {
 		return model.StockEventReview{}, err
 	}
+	if review.ReviewStatus != "PENDING" {
+		_ = r.resolveReviewSLAByTarget("STOCK_EVENT", review.ClusterID, review.Reviewer, now)
+	}
 
 	review.ReviewedAt = now.Format(time.RFC3339)
 	review.CreatedAt = now.Format(time.RFC3339)
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMySQLAdminGetQuantTopStocksForTradeDateBoundsQuotesByTradeDate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &MySQLGrowthRepo{db: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM stock_market_quotes\nWHERE trade_date >= ? AND trade_date <= ?")).
+		WithArgs("2025-10-18", "2026-03-02").
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "trade_date", "open_price", "high_price", "low_price", "close_price", "prev_close_price", "volume", "turnover"}))
+
+	items, err := repo.AdminGetQuantTopStocksForTradeDate("2026-03-02", 10, 95)
+	if err != nil {
+		t.Fatalf("AdminGetQuantTopStocksForTradeDate() error = %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("expected no scores without quotes, got %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+
+	if _, err := repo.AdminGetQuantTopStocksForTradeDate("20260302", 10, 95); err == nil {
+		t.Fatal("expected an invalid trade date to be rejected")
+	}
+}
@@ -123,6 +123,10 @@ func (r *MySQLGrowthRepo) BuildStrategyEngineStockSelectionContext(input model.S
 	if err != nil {
 		return model.StrategyEngineStockSelectionContextResponse{}, err
 	}
+	crowdSentiment, err := r.loadStrategyCrowdSentiment(marketAssetClassStock, candidateSymbols, selectedTradeDate)
+	if err != nil {
+		return model.StrategyEngineStockSelectionContextResponse{}, err
+	}
 
 	warnings := make([]string, 0)
 	if listingCoverageWarning != "" {
@@ -201,6 +205,7 @@ func (r *MySQLGrowthRepo) BuildStrategyEngineStockSelectionContext(input model.S
 			Sector:           candidate.Sector,
 			ThemeTags:        append([]string(nil), candidate.ThemeTags...),
 			RiskFlags:        buildStrategyStockRiskFlags(candidate),
+			CrowdSentiment:   crowdSentimentPointer(crowdSentiment, candidate.Symbol),
 		})
 		if len(filteredInclude) > 0 && len(seeds) >= requestedLimit {
 			break
@@ -365,6 +370,14 @@ func (r *MySQLGrowthRepo) buildStrategyEngineFuturesStrategyContextResponse(requ
 	if err != nil {
 		return model.StrategyEngineFuturesStrategyContextResponse{}, err
 	}
+	productKeys := make([]string, 0, len(candidates))
+	for _, item := range candidates {
+		productKeys = append(productKeys, deriveMarketProductKey(marketAssetClassFutures, item.Contract, ""))
+	}
+	crowdSentiment, err := r.loadStrategyCrowdSentiment(marketAssetClassFutures, compactStrings(productKeys), selectedTradeDate)
+	if err != nil {
+		return model.StrategyEngineFuturesStrategyContextResponse{}, err
+	}
 	curveMetricsMap, err := r.loadStrategyFuturesCurveMetrics(selectedTradeDate, candidates, mockFallback)
 	if err != nil {
 		return model.StrategyEngineFuturesStrategyContextResponse{}, err
@@ -398,6 +411,7 @@ func (r *MySQLGrowthRepo) buildStrategyEngineFuturesStrategyContextResponse(requ
 			warnings = appendUniqueText(warnings, fmt.Sprintf("%s 历史样本不足 14 个交易日，已跳过", candidate.Contract))
 			continue
 		}
+		seed.CrowdSentiment = crowdSentimentPointer(crowdSentiment, deriveMarketProductKey(marketAssetClassFutures, candidate.Contract, ""))
 		priceSources[candidate.PriceSource] = struct{}{}
 		seeds = append(seeds, seed)
 		if len(includeContracts) == 0 && len(seeds) >= requestedLimit {
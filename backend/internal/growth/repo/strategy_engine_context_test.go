@@ -28,6 +28,7 @@ const strategyFuturesContextMappingQueryPattern = `SELECT m\.dominant_instrument
 const strategyFuturesContextHistoryQueryPattern = `SELECT instrument_key, trade_date, close_price, prev_close_price, settle_price, prev_settle_price, volume, turnover, open_interest\s+FROM market_daily_bar_truth`
 const strategyFuturesContextSupplementQueryPattern = `SELECT instrument_key, trade_date, source_key, close_price, settle_price, prev_settle_price, turnover, open_interest\s+FROM market_daily_bars`
 const strategyFuturesContextNewsQueryPattern = `SELECT primary_symbol, symbols_json, title\s+FROM market_news_items`
+const strategyContextCrowdSentimentQueryPattern = `SELECT symbol, stat_date, window_days, topic_count, author_count, sentiment_score, extreme\s+FROM community_sentiment_daily`
 const strategyFuturesContextTermStructureQueryPattern = `SELECT instrument_key, close_price\s+FROM market_daily_bar_truth`
 const strategyFuturesContextInventoryQueryPattern = `SELECT symbol, trade_date, warehouse, area, brand, place, grade, receipt_volume, previous_volume, change_volume\s+FROM futures_inventory_snapshots`
 const strategyFuturesContextSpreadQueryPattern = `SELECT contract_a, contract_b, percentile, status\s+FROM arbitrage_recos`
@@ -154,6 +155,11 @@ func TestBuildStrategyEngineStockSelectionContextUsesTruthBarsAndNews(t *testing
 			AddRow(sql.NullString{String: "600519.SH", Valid: true}, sql.NullString{String: "", Valid: false}, "公司增长签约").
 			AddRow(sql.NullString{String: "600519.SH", Valid: true}, sql.NullString{String: "", Valid: false}, "公司风险提示").
 			AddRow(sql.NullString{String: "", Valid: false}, sql.NullString{String: `["300750.SZ"]`, Valid: true}, "宁德时代中标创新高"))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WithArgs(marketAssetClassStock, "2026-03-15", "2026-03-18", "600519.SH", "300750.SZ").
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}).
+			AddRow("600519.SH", selectedTradeDate, 5, 8, 6, 0.72, "EUPHORIA").
+			AddRow("600519.SH", selectedTradeDate.AddDate(0, 0, -1), 5, 7, 5, 0.55, ""))
 
 	ctx, err := repo.BuildStrategyEngineStockSelectionContext(model.StrategyEngineStockSelectionContextRequest{
 		TradeDate:   "2026-03-19",
@@ -211,6 +217,12 @@ func TestBuildStrategyEngineStockSelectionContextUsesTruthBarsAndNews(t *testing
 	if ctx.Seeds[1].Symbol != "300750.SZ" || ctx.Seeds[1].NewsHeat != 1 {
 		t.Fatalf("unexpected second seed: %+v", ctx.Seeds[1])
 	}
+	if got := ctx.Seeds[0].CrowdSentiment; got == nil || got.Score != 0.72 || got.Extreme != "EUPHORIA" || got.StatDate != "2026-03-18" {
+		t.Fatalf("expected latest crowd sentiment on first seed, got %+v", got)
+	}
+	if ctx.Seeds[1].CrowdSentiment != nil {
+		t.Fatalf("expected undiscussed symbol to carry no crowd sentiment, got %+v", ctx.Seeds[1].CrowdSentiment)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("unmet expectations: %v", err)
@@ -251,6 +263,8 @@ func TestBuildStrategyEngineStockSelectionContextFallsBackToNeutralNewsSignals(t
 	mock.ExpectQuery(strategyStockContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 
 	ctx, err := repo.BuildStrategyEngineStockSelectionContext(model.StrategyEngineStockSelectionContextRequest{
 		TradeDate:   "2026-03-19",
@@ -329,6 +343,8 @@ func TestBuildStrategyEngineStockSelectionContextAutoModeAppliesUniverseFilters(
 	mock.ExpectQuery(strategyStockContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 
 	ctx, err := repo.BuildStrategyEngineStockSelectionContext(model.StrategyEngineStockSelectionContextRequest{
 		TradeDate:      "2026-03-19",
@@ -441,6 +457,8 @@ func TestBuildStrategyEngineStockSelectionContextAutoModeSkipsListingDaysWhenTru
 	mock.ExpectQuery(strategyStockContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 
 	ctx, err := repo.BuildStrategyEngineStockSelectionContext(model.StrategyEngineStockSelectionContextRequest{
 		TradeDate:      "2026-03-21",
@@ -520,6 +538,10 @@ func TestBuildStrategyEngineFuturesStrategyContextUsesTruthBarsAndNews(t *testin
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}).
 			AddRow(sql.NullString{String: "IF2606", Valid: true}, sql.NullString{String: "", Valid: false}, "股指期货突破创新高").
 			AddRow(sql.NullString{String: "AU2606.SHF", Valid: true}, sql.NullString{String: "", Valid: false}, "黄金波动风险提示"))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WithArgs(marketAssetClassFutures, "2026-03-15", "2026-03-18", "IF", "AU").
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}).
+			AddRow("AU", selectedTradeDate.AddDate(0, 0, -1), 5, 3, 3, -0.4, ""))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-18", "MOCK", "IF%", "AU%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -575,6 +597,12 @@ func TestBuildStrategyEngineFuturesStrategyContextUsesTruthBarsAndNews(t *testin
 	if ctx.Seeds[1].Contract != "AU2606" || ctx.Seeds[1].NewsBias >= 0 {
 		t.Fatalf("unexpected second futures seed: %+v", ctx.Seeds[1])
 	}
+	if ctx.Seeds[0].CrowdSentiment != nil {
+		t.Fatalf("expected IF seed to carry no crowd sentiment, got %+v", ctx.Seeds[0].CrowdSentiment)
+	}
+	if got := ctx.Seeds[1].CrowdSentiment; got == nil || got.Score != -0.4 || got.StatDate != "2026-03-17" {
+		t.Fatalf("expected AU product sentiment on AU2606 seed, got %+v", got)
+	}
 	if ctx.Seeds[0].InventoryFocusArea != "华东" || ctx.Seeds[0].InventoryFocusWarehouse != "中储1号" || ctx.Seeds[0].InventoryFocusBrand != "央企品牌A" {
 		t.Fatalf("expected IF inventory focus dimensions, got %+v", ctx.Seeds[0])
 	}
@@ -646,6 +674,8 @@ func TestBuildStrategyEngineFuturesStrategyContextFallsBackToNeutralNewsSignals(
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-18", "MOCK", "IF%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -746,6 +776,8 @@ func TestBuildStrategyEngineFuturesStrategyContextPrefersRealTruthBeforeMock(t *
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2024-06-18", "MOCK", "IF%", "AU%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -814,6 +846,8 @@ func TestBuildStrategyEngineFuturesStrategyContextUsesMappedDominantContractsWhe
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-18", "MOCK", "IF%", "AU%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -874,6 +908,8 @@ func TestBuildStrategyEngineFuturesStrategyContextExplicitContractsFallBackToMoc
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-19", "IF%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -943,6 +979,8 @@ func TestBuildStrategyEngineFuturesStrategyContextAllowsMockFallbackOnShortHisto
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-18", "MOCK", "IF%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -968,6 +1006,8 @@ func TestBuildStrategyEngineFuturesStrategyContextAllowsMockFallbackOnShortHisto
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-19", "IF%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -1031,6 +1071,8 @@ func TestBuildStrategyEngineFuturesStrategyContextEnrichesFactorsFromRawBars(t *
 	mock.ExpectQuery(strategyFuturesContextNewsQueryPattern).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"primary_symbol", "symbols_json", "title"}))
+	mock.ExpectQuery(strategyContextCrowdSentimentQueryPattern).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "stat_date", "window_days", "topic_count", "author_count", "sentiment_score", "extreme"}))
 	mock.ExpectQuery(strategyFuturesContextTermStructureQueryPattern).
 		WithArgs(marketAssetClassFutures, "2026-03-18", "MOCK", "IF%").
 		WillReturnRows(buildStrategyFuturesTermStructureRows(
@@ -0,0 +1,200 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/config"
+	"sercherai/backend/internal/platform/secrets"
+)
+
+// loadConfigSecretKeyring never returns nil: a keyring that failed to load
+// rejects every seal/open, so misconfiguration surfaces as write errors
+// instead of silently storing credentials in plaintext.
+func loadConfigSecretKeyring(cfg config.Config) *secrets.Keyring {
+	keyring, _ := secrets.Load(cfg)
+	return keyring
+}
+
+func (r *MySQLGrowthRepo) sealSystemConfigValue(configKey string, configValue string) (string, error) {
+	if !secrets.IsSensitiveConfigKey(configKey) {
+		return configValue, nil
+	}
+	sealed, err := r.configSecrets.Seal(configKey, configValue)
+	if err != nil {
+		return "", fmt.Errorf("seal %s: %w", configKey, err)
+	}
+	return sealed, nil
+}
+
+// dataSourceSecretName is the name a data source credential field is sealed
+// under, so a token only opens for the source and field it was written to.
+func dataSourceSecretName(configKey string, field string) string {
+	return strings.TrimSpace(configKey) + "." + field
+}
+
+// sealDataSourceConfig seals credential fields (token, api_token, secret...)
+// inside a data source config map. Values echoed back from a listing are
+// already sealed and pass through untouched.
+func (r *MySQLGrowthRepo) sealDataSourceConfig(configKey string, value map[string]interface{}) (map[string]interface{}, error) {
+	sealed := cloneDataSourceConfigMap(value)
+	for key, raw := range sealed {
+		text, ok := raw.(string)
+		if !ok || !secrets.IsSensitiveConfigKey(key) {
+			continue
+		}
+		next, err := r.configSecrets.Seal(dataSourceSecretName(configKey, key), text)
+		if err != nil {
+			return nil, fmt.Errorf("seal data source %s: %w", key, err)
+		}
+		sealed[key] = next
+	}
+	return sealed, nil
+}
+
+// resolveDataSourceToken is the only place data source tokens are decrypted;
+// an unreadable token behaves like a missing one so fetchers fail closed.
+func (r *MySQLGrowthRepo) resolveDataSourceToken(item model.DataSource) string {
+	for _, field := range []string{"token", "api_token", "tushare_token"} {
+		value := parseDataSourceStringConfig(item.Config, field)
+		if value == "" {
+			continue
+		}
+		token, err := r.configSecrets.Open(dataSourceSecretName("data_source."+item.SourceKey, field), value)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func (r *MySQLGrowthRepo) AdminRotateConfigSecrets(operator string) (model.SystemConfigSecretRotationResult, error) {
+	result := model.SystemConfigSecretRotationResult{
+		PrimaryKeyID: r.configSecrets.PrimaryKeyID(),
+		Failures:     make([]string, 0),
+	}
+	if !r.configSecrets.Enabled() {
+		return result, secrets.ErrKeyringUnavailable
+	}
+	if strings.TrimSpace(operator) == "" {
+		operator = "system"
+	}
+
+	rows, err := r.db.Query(`
+SELECT config_key, config_value
+FROM system_configs
+ORDER BY config_key ASC`)
+	if err != nil {
+		return result, err
+	}
+	type rotatedConfig struct {
+		key      string
+		previous string
+		next     string
+	}
+	updates := make([]rotatedConfig, 0)
+	for rows.Next() {
+		var configKey, configValue string
+		if err := rows.Scan(&configKey, &configValue); err != nil {
+			rows.Close()
+			return result, err
+		}
+		var (
+			next    string
+			changed bool
+			sealed  int
+			err     error
+		)
+		switch {
+		case strings.HasPrefix(configKey, "data_source."):
+			next, changed, sealed, err = r.rotateDataSourceConfigValue(configKey, configValue)
+		case secrets.IsSensitiveConfigKey(configKey):
+			if !secrets.IsSealed(configValue) && configValue != "" {
+				sealed = 1
+			}
+			next, changed, err = r.configSecrets.Rotate(configKey, configValue)
+		default:
+			continue
+		}
+		result.Scanned++
+		if err != nil {
+			result.Failed++
+			result.Failures = append(result.Failures, fmt.Sprintf("%s: %v", configKey, err))
+			continue
+		}
+		if !changed {
+			result.Unchanged++
+			continue
+		}
+		if sealed > 0 {
+			result.Sealed++
+		} else {
+			result.Rotated++
+		}
+		updates = append(updates, rotatedConfig{key: configKey, previous: configValue, next: next})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return result, err
+	}
+	rows.Close()
+
+	now := time.Now()
+	for _, item := range updates {
+		// Compare-and-swap so a concurrent admin edit is never overwritten with a
+		// re-sealed copy of the old value.
+		if _, err := r.db.Exec(`
+UPDATE system_configs
+SET config_value = ?, updated_by = ?, updated_at = ?
+WHERE config_key = ? AND config_value = ?`,
+			item.next, operator, now, item.key, item.previous,
+		); err != nil {
+			return result, err
+		}
+	}
+	result.RotatedAt = now.Format(time.RFC3339)
+	return result, nil
+}
+
+func (r *MySQLGrowthRepo) rotateDataSourceConfigValue(configKey string, configValue string) (string, bool, int, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(configValue), &payload); err != nil {
+		return configValue, false, 0, nil
+	}
+	configMap, ok := payload["config"].(map[string]interface{})
+	if !ok {
+		return configValue, false, 0, nil
+	}
+	changed := false
+	sealed := 0
+	for key, raw := range configMap {
+		text, ok := raw.(string)
+		if !ok || text == "" || !secrets.IsSensitiveConfigKey(key) {
+			continue
+		}
+		next, rotated, err := r.configSecrets.Rotate(dataSourceSecretName(configKey, key), text)
+		if err != nil {
+			return configValue, false, 0, fmt.Errorf("%s: %w", key, err)
+		}
+		if !rotated {
+			continue
+		}
+		if !secrets.IsSealed(text) {
+			sealed++
+		}
+		configMap[key] = next
+		changed = true
+	}
+	if !changed {
+		return configValue, false, 0, nil
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return configValue, false, 0, err
+	}
+	return string(body), true, sealed, nil
+}
@@ -0,0 +1,189 @@
+package repo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"sercherai/backend/internal/growth/model"
+	"sercherai/backend/internal/platform/secrets"
+)
+
+const (
+	testConfigMasterKeyOld = "k2025:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	testConfigMasterKeyNew = "k2026:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+type sealedConfigArg struct {
+	keyring   *secrets.Keyring
+	name      string
+	keyID     string
+	plaintext string
+}
+
+func (a sealedConfigArg) Match(value driver.Value) bool {
+	text, ok := value.(string)
+	if !ok || secrets.SealedKeyID(text) != a.keyID {
+		return false
+	}
+	opened, err := a.keyring.Open(a.name, text)
+	return err == nil && opened == a.plaintext
+}
+
+func mustTestKeyring(t *testing.T, spec string) *secrets.Keyring {
+	t.Helper()
+	keyring, err := secrets.ParseKeyring(spec)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	return keyring
+}
+
+func TestMySQLAdminUpsertSystemConfigSealsSensitiveValues(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	keyring := mustTestKeyring(t, testConfigMasterKeyNew)
+	repo := &MySQLGrowthRepo{db: db, configSecrets: keyring}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO system_configs")).
+		WithArgs(sqlmock.AnyArg(), "payment.channel.yolkpay.key", sealedConfigArg{keyring: keyring, name: "payment.channel.yolkpay.key", keyID: "k2026", plaintext: "merchant-secret"}, "", "admin_001", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO system_configs")).
+		WithArgs(sqlmock.AnyArg(), "oss.qiniu.domain", "cdn.example.com", "", "admin_001", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.AdminUpsertSystemConfig("payment.channel.yolkpay.key", "merchant-secret", "", "admin_001"); err != nil {
+		t.Fatalf("AdminUpsertSystemConfig(sensitive) error = %v", err)
+	}
+	if err := repo.AdminUpsertSystemConfig("oss.qiniu.domain", "cdn.example.com", "", "admin_001"); err != nil {
+		t.Fatalf("AdminUpsertSystemConfig(plain) error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestSealDataSourceConfigOnlyTouchesCredentialFields(t *testing.T) {
+	keyring := mustTestKeyring(t, testConfigMasterKeyNew)
+	repo := &MySQLGrowthRepo{configSecrets: keyring}
+
+	sealed, err := repo.sealDataSourceConfig("data_source.tushare", map[string]interface{}{
+		"provider":    "TUSHARE",
+		"endpoint":    "https://api.tushare.pro",
+		"token":       "ts-token",
+		"retry_times": float64(1),
+	})
+	if err != nil {
+		t.Fatalf("sealDataSourceConfig() error = %v", err)
+	}
+	if sealed["provider"] != "TUSHARE" || sealed["endpoint"] != "https://api.tushare.pro" || sealed["retry_times"] != float64(1) {
+		t.Fatalf("expected non-credential fields untouched, got %+v", sealed)
+	}
+	token, _ := sealed["token"].(string)
+	if !secrets.IsSealed(token) {
+		t.Fatalf("expected token to be sealed, got %q", token)
+	}
+
+	resealed, err := repo.sealDataSourceConfig("data_source.tushare", sealed)
+	if err != nil || resealed["token"] != token {
+		t.Fatalf("expected sealed token to pass through unchanged, got %v (%v)", resealed["token"], err)
+	}
+	source := model.DataSource{SourceKey: "tushare", Config: sealed}
+	if got := repo.resolveTushareTokenFromDataSourceConfig(source); got != "ts-token" {
+		t.Fatalf("expected token to be opened at point of use, got %q", got)
+	}
+	if got := (&MySQLGrowthRepo{}).resolveDataSourceToken(source); got != "" {
+		t.Fatalf("expected unreadable token to behave as missing, got %q", got)
+	}
+	if got := repo.resolveDataSourceToken(model.DataSource{SourceKey: "other", Config: sealed}); got != "" {
+		t.Fatalf("expected a token copied to another source not to open, got %q", got)
+	}
+
+	// A copied token is not trusted as sealed: it is sealed again as an opaque
+	// value for the new source instead of passing through.
+	copied, err := repo.sealDataSourceConfig("data_source.other", sealed)
+	if err != nil || copied["token"] == token {
+		t.Fatalf("expected a copied token to be re-sealed, got %v (%v)", copied["token"], err)
+	}
+}
+
+func TestMySQLAdminRotateConfigSecretsReSealsUnderPrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	oldKeyring := mustTestKeyring(t, testConfigMasterKeyOld)
+	keyring := mustTestKeyring(t, testConfigMasterKeyNew+","+testConfigMasterKeyOld)
+	repo := &MySQLGrowthRepo{db: db, configSecrets: keyring}
+
+	oldSecret, _ := oldKeyring.Seal("oss.qiniu.secret_key", "qiniu-secret")
+	currentKey, _ := keyring.Seal("payment.signing_secret", "already-current")
+	oldToken, _ := oldKeyring.Seal("data_source.tushare.token", "ts-token")
+	dataSourceValue := `{"name":"Tushare","config":{"provider":"TUSHARE","token":"` + oldToken + `"}}`
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM system_configs")).
+		WillReturnRows(sqlmock.NewRows([]string{"config_key", "config_value"}).
+			AddRow("data_source.tushare", dataSourceValue).
+			AddRow("oss.qiniu.domain", "cdn.example.com").
+			AddRow("oss.qiniu.secret_key", oldSecret).
+			AddRow("payment.channel.yolkpay.key", "legacy-plaintext").
+			AddRow("payment.signing_secret", currentKey))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE system_configs")).
+		WithArgs(sqlmock.AnyArg(), "admin_001", sqlmock.AnyArg(), "data_source.tushare", dataSourceValue).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE system_configs")).
+		WithArgs(sealedConfigArg{keyring: keyring, name: "oss.qiniu.secret_key", keyID: "k2026", plaintext: "qiniu-secret"}, "admin_001", sqlmock.AnyArg(), "oss.qiniu.secret_key", oldSecret).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE system_configs")).
+		WithArgs(sealedConfigArg{keyring: keyring, name: "payment.channel.yolkpay.key", keyID: "k2026", plaintext: "legacy-plaintext"}, "admin_001", sqlmock.AnyArg(), "payment.channel.yolkpay.key", "legacy-plaintext").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := repo.AdminRotateConfigSecrets("admin_001")
+	if err != nil {
+		t.Fatalf("AdminRotateConfigSecrets() error = %v", err)
+	}
+	if result.PrimaryKeyID != "k2026" || result.Scanned != 4 || result.Rotated != 2 || result.Sealed != 1 || result.Unchanged != 1 || result.Failed != 0 {
+		t.Fatalf("unexpected rotation result: %+v", result)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestRotateDataSourceConfigValueKeepsPayloadShape(t *testing.T) {
+	oldKeyring := mustTestKeyring(t, testConfigMasterKeyOld)
+	keyring := mustTestKeyring(t, testConfigMasterKeyNew+","+testConfigMasterKeyOld)
+	repo := &MySQLGrowthRepo{configSecrets: keyring}
+	oldToken, _ := oldKeyring.Seal("data_source.tushare.token", "ts-token")
+
+	next, changed, sealed, err := repo.rotateDataSourceConfigValue("data_source.tushare", `{"name":"Tushare","status":"ACTIVE","config":{"provider":"TUSHARE","token":"`+oldToken+`","api_token":"plain"}}`)
+	if err != nil || !changed || sealed != 1 {
+		t.Fatalf("rotateDataSourceConfigValue() = changed %v sealed %d err %v", changed, sealed, err)
+	}
+	var payload struct {
+		Name   string                 `json:"name"`
+		Status string                 `json:"status"`
+		Config map[string]interface{} `json:"config"`
+	}
+	if err := json.Unmarshal([]byte(next), &payload); err != nil {
+		t.Fatalf("rotated payload is not json: %v", err)
+	}
+	if payload.Name != "Tushare" || payload.Status != "ACTIVE" || payload.Config["provider"] != "TUSHARE" {
+		t.Fatalf("expected payload metadata preserved, got %+v", payload)
+	}
+	for _, key := range []string{"token", "api_token"} {
+		value, _ := payload.Config[key].(string)
+		if !strings.HasPrefix(value, "enc:v2:k2026:") {
+			t.Fatalf("expected %s sealed under primary key, got %q", key, value)
+		}
+	}
+}
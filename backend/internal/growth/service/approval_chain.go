@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func (s *growthService) AdminListApprovalPolicies() ([]model.ApprovalPolicy, error) {
+	return s.repo.AdminListApprovalPolicies()
+}
+
+func (s *growthService) AdminGetApprovalPolicy(module string) (model.ApprovalPolicy, error) {
+	return s.repo.AdminGetApprovalPolicy(module)
+}
+
+func (s *growthService) AdminSaveApprovalPolicy(item model.ApprovalPolicy, operator string) (model.ApprovalPolicy, error) {
+	normalized, err := item.Normalized()
+	if err != nil {
+		return model.ApprovalPolicy{}, err
+	}
+	return s.repo.AdminSaveApprovalPolicy(normalized, operator)
+}
+
+// AdminMatchApprovalPolicy returns the module's policy when an action of
+// this amount has to go through it. A module without a policy is unguarded.
+func (s *growthService) AdminMatchApprovalPolicy(module string, amount float64) (model.ApprovalPolicy, bool, error) {
+	policy, err := s.repo.AdminGetApprovalPolicy(module)
+	if errors.Is(err, model.ErrApprovalPolicyNotFound) {
+		return model.ApprovalPolicy{}, false, nil
+	}
+	if err != nil {
+		return model.ApprovalPolicy{}, false, err
+	}
+	return policy, policy.Applies(amount), nil
+}
+
+func (s *growthService) AdminCreateApprovalRequest(item model.ApprovalRequest) (model.ApprovalRequest, error) {
+	return s.repo.AdminCreateApprovalRequest(item)
+}
+
+func (s *growthService) AdminListApprovalRequests(module string, status string, submitterID string, page int, pageSize int) ([]model.ApprovalRequest, int, error) {
+	return s.repo.AdminListApprovalRequests(module, status, submitterID, page, pageSize)
+}
+
+func (s *growthService) AdminGetApprovalRequest(id string) (model.ApprovalRequest, error) {
+	return s.repo.AdminGetApprovalRequest(id)
+}
+
+func (s *growthService) AdminDecideApprovalRequest(id string, approverID string, decision string, note string) (model.ApprovalRequest, error) {
+	return s.repo.AdminDecideApprovalRequest(id, approverID, decision, note)
+}
+
+func (s *growthService) AdminClaimApprovalExecution(id string) (model.ApprovalRequest, error) {
+	return s.repo.AdminClaimApprovalExecution(id)
+}
+
+func (s *growthService) AdminFinishApprovalExecution(id string, executeError string) error {
+	return s.repo.AdminFinishApprovalExecution(id, executeError)
+}
+
+func (s *growthService) AdminGetWithdrawRequest(id string) (model.WithdrawRequestInfo, error) {
+	return s.repo.AdminGetWithdrawRequest(id)
+}
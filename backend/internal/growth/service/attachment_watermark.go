@@ -0,0 +1,11 @@
+package service
+
+import "sercherai/backend/internal/growth/model"
+
+func (s *growthService) AdminListAttachmentDownloads(attachmentID string, userID string) ([]model.AttachmentDownloadRecord, error) {
+	return s.repo.AdminListAttachmentDownloads(attachmentID, userID)
+}
+
+func (s *growthService) AdminListAttachmentDownloaderIDs(attachmentID string) ([]string, error) {
+	return s.repo.AdminListAttachmentDownloaderIDs(attachmentID)
+}
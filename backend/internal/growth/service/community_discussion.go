@@ -12,11 +12,31 @@ func (s *growthService) ListCommunityTopics(userID string, query model.Community
 }
 
 func (s *growthService) GetCommunityTopic(userID string, topicID string) (model.CommunityTopicDetail, error) {
-	return s.repo.GetCommunityTopic(userID, topicID)
+	topic, err := s.repo.GetCommunityTopic(userID, topicID)
+	if err != nil {
+		return model.CommunityTopicDetail{}, err
+	}
+	if strings.TrimSpace(userID) != "" {
+		_ = s.repo.RecordCommunityTopicView(userID, topic.ID)
+	}
+	return topic, nil
 }
 
 func (s *growthService) CreateCommunityTopic(input model.CommunityTopicCreateInput) (model.CommunityTopicDetail, error) {
-	return s.repo.CreateCommunityTopic(input)
+	topic, err := s.repo.CreateCommunityTopic(input)
+	if err != nil {
+		return model.CommunityTopicDetail{}, err
+	}
+	if topic.Status == string(model.CommunityTopicStatusPublished) {
+		s.notifyCommunityMentions(model.CommunityMentionInput{
+			ActorUserID:      input.UserID,
+			TargetType:       "TOPIC",
+			TargetID:         topic.ID,
+			TopicID:          topic.ID,
+			MentionedUserIDs: parseCommunityMentions(input.Title, input.Content, input.ReasonText, input.RiskText),
+		}, topic.Title)
+	}
+	return topic, nil
 }
 
 func (s *growthService) ListCommunityComments(userID string, topicID string, query model.CommunityCommentListQuery) ([]model.CommunityComment, int, error) {
@@ -45,6 +65,7 @@ func (s *growthService) CreateCommunityComment(input model.CommunityCommentCreat
 			Title:       title,
 			Content:     content,
 			MessageType: "COMMUNITY",
+			ActorUserID: input.UserID,
 		})
 	}
 	if strings.TrimSpace(input.ReplyToUserID) != "" && input.ReplyToUserID != input.UserID && input.ReplyToUserID != topic.UserID {
@@ -55,8 +76,18 @@ func (s *growthService) CreateCommunityComment(input model.CommunityCommentCreat
 			Title:       title,
 			Content:     content,
 			MessageType: "COMMUNITY",
+			ActorUserID: input.UserID,
 		})
 	}
+	if comment.Status == string(model.CommunityCommentStatusPublished) {
+		s.notifyCommunityMentions(model.CommunityMentionInput{
+			ActorUserID:      input.UserID,
+			TargetType:       "COMMENT",
+			TargetID:         comment.ID,
+			TopicID:          topic.ID,
+			MentionedUserIDs: parseCommunityMentions(input.Content),
+		}, topic.Title, topic.UserID, input.ReplyToUserID)
+	}
 	return comment, nil
 }
 
@@ -92,6 +123,46 @@ func (s *growthService) AdminListCommunityReports(query model.CommunityAdminRepo
 	return s.repo.AdminListCommunityReports(query)
 }
 
-func (s *growthService) AdminReviewCommunityReport(id string, status string, reviewNote string) error {
-	return s.repo.AdminReviewCommunityReport(id, status, reviewNote)
+func (s *growthService) AdminReviewCommunityReport(id string, status string, reviewerID string, reviewNote string) error {
+	return s.repo.AdminReviewCommunityReport(id, status, reviewerID, reviewNote)
+}
+
+func (s *growthService) AdminListCommunitySensitiveWords(status string, category string, keyword string, page int, pageSize int) ([]model.CommunitySensitiveWord, int, error) {
+	return s.repo.AdminListCommunitySensitiveWords(status, category, keyword, page, pageSize)
+}
+
+func (s *growthService) AdminCreateCommunitySensitiveWord(word string, category string, operator string) (string, error) {
+	return s.repo.AdminCreateCommunitySensitiveWord(word, category, operator)
+}
+
+func (s *growthService) AdminUpdateCommunitySensitiveWordStatus(id string, status string) error {
+	return s.repo.AdminUpdateCommunitySensitiveWordStatus(id, status)
+}
+
+func (s *growthService) AdminListCommunityModerationFlags(query model.CommunityModerationFlagQuery) ([]model.CommunityModerationFlag, int, error) {
+	return s.repo.AdminListCommunityModerationFlags(query)
+}
+
+func (s *growthService) AdminListCommunityUserBans(status string, userID string, page int, pageSize int) ([]model.CommunityUserBan, int, error) {
+	return s.repo.AdminListCommunityUserBans(status, userID, page, pageSize)
+}
+
+func (s *growthService) AdminCreateCommunityUserBan(userID string, days int, reason string, operator string) (string, error) {
+	return s.repo.AdminCreateCommunityUserBan(userID, days, reason, operator)
+}
+
+func (s *growthService) AdminLiftCommunityUserBan(id string, operator string) error {
+	return s.repo.AdminLiftCommunityUserBan(id, operator)
+}
+
+func (s *growthService) AdminListCommunitySentiment(query model.CommunitySentimentQuery) ([]model.CommunitySentimentDaily, int, error) {
+	return s.repo.AdminListCommunitySentiment(query)
+}
+
+func (s *growthService) AdminRebuildCommunitySentiment(statDate string) (int, error) {
+	return s.repo.AdminRebuildCommunitySentiment(statDate)
+}
+
+func (s *growthService) AdminRunCommunitySentimentIndex() (string, error) {
+	return s.repo.AdminRunCommunitySentimentIndex()
 }
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+const maxCommunityMentionsPerPost = 10
+
+// communityMentionPattern matches @user_id handles; the leading guard keeps
+// e-mail addresses such as a@b.com from being read as mentions.
+var communityMentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@])@([A-Za-z0-9_]{2,32})`)
+
+// parseCommunityMentions returns the distinct user ids mentioned in text, in
+// order of appearance, capped so one post cannot page half the community.
+func parseCommunityMentions(texts ...string) []string {
+	seen := make(map[string]struct{})
+	mentions := make([]string, 0)
+	for _, text := range texts {
+		for _, match := range communityMentionPattern.FindAllStringSubmatch(text, -1) {
+			userID := match[1]
+			if _, ok := seen[userID]; ok {
+				continue
+			}
+			seen[userID] = struct{}{}
+			mentions = append(mentions, userID)
+			if len(mentions) == maxCommunityMentionsPerPost {
+				return mentions
+			}
+		}
+	}
+	return mentions
+}
+
+// notifyCommunityMentions records mentions in published content and sends
+// one notification per newly mentioned user, skipping users in skip who were
+// already notified about the same post as topic owner or reply target.
+func (s *growthService) notifyCommunityMentions(input model.CommunityMentionInput, topicTitle string, skip ...string) {
+	if len(input.MentionedUserIDs) == 0 {
+		return
+	}
+	mentioned, err := s.repo.RecordCommunityMentions(input)
+	if err != nil {
+		return
+	}
+	where := "讨论"
+	if input.TargetType == "COMMENT" {
+		where = "评论"
+	}
+	for _, userID := range mentioned {
+		if containsString(skip, userID) {
+			continue
+		}
+		_ = s.repo.CreateCommunityNotification(model.CommunityNotificationInput{
+			UserID:      userID,
+			Title:       "有人在社区提到了你",
+			Content:     fmt.Sprintf("你在《%s》的%s中被提到。", topicTitle, where),
+			MessageType: "COMMUNITY",
+			ActorUserID: input.ActorUserID,
+		})
+	}
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if strings.TrimSpace(item) != "" && item == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *growthService) ListCommunityFollows(userID string, targetType string, page int, pageSize int) ([]model.CommunityFollow, int, error) {
+	return s.repo.ListCommunityFollows(userID, targetType, page, pageSize)
+}
+
+func (s *growthService) CreateCommunityFollow(userID string, targetType string, targetID string) (model.CommunityFollow, error) {
+	return s.repo.CreateCommunityFollow(userID, targetType, targetID)
+}
+
+func (s *growthService) DeleteCommunityFollow(userID string, id string) error {
+	return s.repo.DeleteCommunityFollow(userID, id)
+}
+
+func (s *growthService) ListCommunityBlocks(userID string, page int, pageSize int) ([]model.CommunityUserBlock, int, error) {
+	return s.repo.ListCommunityBlocks(userID, page, pageSize)
+}
+
+func (s *growthService) CreateCommunityBlock(userID string, blockedUserID string, blockType string) (model.CommunityUserBlock, error) {
+	return s.repo.CreateCommunityBlock(userID, blockedUserID, blockType)
+}
+
+func (s *growthService) DeleteCommunityBlock(userID string, id string) error {
+	return s.repo.DeleteCommunityBlock(userID, id)
+}
+
+func (s *growthService) ListCommunityMentions(userID string, page int, pageSize int) ([]model.CommunityMention, int, error) {
+	return s.repo.ListCommunityMentions(userID, page, pageSize)
+}
+
+func (s *growthService) ListCommunityFeed(userID string, page int, pageSize int) ([]model.CommunityFeedItem, int, error) {
+	return s.repo.ListCommunityFeed(userID, page, pageSize)
+}
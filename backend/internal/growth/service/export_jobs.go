@@ -0,0 +1,51 @@
+package service
+
+import (
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func (s *growthService) AdminCreateExportJob(item model.ExportJob) (model.ExportJob, error) {
+	return s.repo.AdminCreateExportJob(item)
+}
+
+func (s *growthService) AdminGetExportJob(id string) (model.ExportJob, error) {
+	return s.repo.AdminGetExportJob(id)
+}
+
+func (s *growthService) AdminListExportJobs(requestedBy string, exportType string, status string, page int, pageSize int) ([]model.ExportJob, int, error) {
+	return s.repo.AdminListExportJobs(requestedBy, exportType, status, page, pageSize)
+}
+
+func (s *growthService) AdminClaimExportJobs(limit int, staleBefore time.Time) ([]model.ExportJob, error) {
+	return s.repo.AdminClaimExportJobs(limit, staleBefore)
+}
+
+func (s *growthService) AdminFinishExportJob(item model.ExportJob) error {
+	return s.repo.AdminFinishExportJob(item)
+}
+
+func (s *growthService) AdminListExpiredExportJobs(before time.Time, limit int) ([]model.ExportJob, error) {
+	return s.repo.AdminListExpiredExportJobs(before, limit)
+}
+
+func (s *growthService) AdminExpireExportJob(id string) error {
+	return s.repo.AdminExpireExportJob(id)
+}
+
+func (s *growthService) AdminRecordExportDownload(item model.ExportDownload) error {
+	return s.repo.AdminRecordExportDownload(item)
+}
+
+func (s *growthService) AdminListExportDownloads(jobID string) ([]model.ExportDownload, error) {
+	return s.repo.AdminListExportDownloads(jobID)
+}
+
+func (s *growthService) AdminRecordDeliveredExport(item model.ExportJob, download model.ExportDownload) (model.ExportJob, error) {
+	return s.repo.AdminRecordDeliveredExport(item, download)
+}
+
+func (s *growthService) AdminListLoginLogs(action string, status string, dateFrom string, dateTo string, page int, pageSize int) ([]model.LoginLog, int, error) {
+	return s.repo.AdminListLoginLogs(action, status, dateFrom, dateTo, page, pageSize)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"time"
+
+	"sercherai/backend/internal/growth/model"
+)
+
+func (s *growthService) AdminScheduleNewsArticle(id string, publishAt time.Time, embargoUntil time.Time) error {
+	return s.repo.AdminScheduleNewsArticle(id, publishAt, embargoUntil)
+}
+
+func (s *growthService) AdminCancelNewsArticleSchedule(id string) error {
+	return s.repo.AdminCancelNewsArticleSchedule(id)
+}
+
+func (s *growthService) AdminRunNewsScheduledPublish() (string, error) {
+	return s.repo.AdminRunNewsScheduledPublish()
+}
+
+func (s *growthService) AdminListNewsArticleRevisions(articleID string, page int, pageSize int) ([]model.NewsArticleRevision, int, error) {
+	return s.repo.AdminListNewsArticleRevisions(articleID, page, pageSize)
+}
+
+func (s *growthService) AdminGetNewsArticleRevision(articleID string, revisionNo int) (model.NewsArticleRevision, error) {
+	return s.repo.AdminGetNewsArticleRevision(articleID, revisionNo)
+}
+
+func (s *growthService) AdminDiffNewsArticleRevisions(articleID string, fromRevision int, toRevision int) (model.NewsArticleRevisionDiff, error) {
+	return s.repo.AdminDiffNewsArticleRevisions(articleID, fromRevision, toRevision)
+}
+
+func (s *growthService) AdminRestoreNewsArticleRevision(articleID string, revisionNo int, editorID string) (model.NewsArticleRevision, error) {
+	return s.repo.AdminRestoreNewsArticleRevision(articleID, revisionNo, editorID)
+}
@@ -0,0 +1,23 @@
+package service
+
+import "sercherai/backend/internal/growth/model"
+
+func (s *growthService) UpsertNewsAttachmentText(item model.NewsAttachmentText) error {
+	return s.repo.UpsertNewsAttachmentText(item)
+}
+
+func (s *growthService) AdminListNewsAttachmentsMissingText(limit int) ([]model.NewsAttachment, error) {
+	return s.repo.AdminListNewsAttachmentsMissingText(limit)
+}
+
+func (s *growthService) SearchNewsAttachmentTexts(userID string, keyword string, limit int) ([]model.NewsAttachmentSearchHit, int, error) {
+	return s.repo.SearchNewsAttachmentTexts(userID, keyword, limit)
+}
+
+func (s *growthService) AdminListLegacyNewsAttachments(urlPrefixes []string, limit int) ([]model.NewsAttachment, error) {
+	return s.repo.AdminListLegacyNewsAttachments(urlPrefixes, limit)
+}
+
+func (s *growthService) AdminRelinkNewsAttachmentFile(oldURL string, newURL string) (int64, error) {
+	return s.repo.AdminRelinkNewsAttachmentFile(oldURL, newURL)
+}
@@ -0,0 +1,9 @@
+package service
+
+func (s *growthService) AdminMergeNewsArticleDuplicate(id string, canonicalID string) error {
+	return s.repo.AdminMergeNewsArticleDuplicate(id, canonicalID)
+}
+
+func (s *growthService) AdminUnmergeNewsArticleDuplicate(id string) error {
+	return s.repo.AdminUnmergeNewsArticleDuplicate(id)
+}
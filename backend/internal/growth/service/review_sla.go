@@ -0,0 +1,30 @@
+package service
+
+import "sercherai/backend/internal/growth/model"
+
+func (s *growthService) AdminListReviewSLAPolicies() ([]model.ReviewSLAPolicy, error) {
+	return s.repo.AdminListReviewSLAPolicies()
+}
+
+func (s *growthService) AdminSaveReviewSLAPolicy(item model.ReviewSLAPolicy, operator string) (model.ReviewSLAPolicy, error) {
+	normalized, err := item.Normalized()
+	if err != nil {
+		return model.ReviewSLAPolicy{}, err
+	}
+	return s.repo.AdminSaveReviewSLAPolicy(normalized, operator)
+}
+
+func (s *growthService) AdminListReviewSLAItems(module string, status string, assigneeID string, page int, pageSize int) ([]model.ReviewSLAItem, int, error) {
+	return s.repo.AdminListReviewSLAItems(module, status, assigneeID, page, pageSize)
+}
+
+func (s *growthService) AdminGetReviewSLAMetrics(module string, reviewerID string, days int) (model.ReviewSLAMetrics, error) {
+	if days <= 0 {
+		days = 30
+	}
+	return s.repo.AdminGetReviewSLAMetrics(module, reviewerID, days)
+}
+
+func (s *growthService) AdminRunReviewSLAEscalation() (string, error) {
+	return s.repo.AdminRunReviewSLAEscalation()
+}
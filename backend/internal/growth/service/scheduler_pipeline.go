@@ -0,0 +1,45 @@
+package service
+
+import "sercherai/backend/internal/growth/model"
+
+func (s *growthService) AdminListSchedulerPipelines(status string) ([]model.SchedulerPipeline, error) {
+	return s.repo.AdminListSchedulerPipelines(status)
+}
+
+func (s *growthService) AdminGetSchedulerPipeline(pipelineKey string) (model.SchedulerPipeline, error) {
+	return s.repo.AdminGetSchedulerPipeline(pipelineKey)
+}
+
+// AdminSaveSchedulerPipeline validates the dependency graph and stores the
+// nodes in topological order, which is the order runs execute them in.
+func (s *growthService) AdminSaveSchedulerPipeline(item model.SchedulerPipeline, operator string) (model.SchedulerPipeline, error) {
+	normalized, err := item.Normalized()
+	if err != nil {
+		return model.SchedulerPipeline{}, err
+	}
+	return s.repo.AdminSaveSchedulerPipeline(normalized, operator)
+}
+
+func (s *growthService) AdminListSchedulerPipelineRuns(pipelineKey string, tradeDate string, status string, page int, pageSize int) ([]model.SchedulerPipelineRun, int, error) {
+	return s.repo.AdminListSchedulerPipelineRuns(pipelineKey, tradeDate, status, page, pageSize)
+}
+
+func (s *growthService) AdminGetSchedulerPipelineRun(runID string) (model.SchedulerPipelineRun, error) {
+	return s.repo.AdminGetSchedulerPipelineRun(runID)
+}
+
+func (s *growthService) AdminFindSchedulerPipelineRun(pipelineKey string, tradeDate string) (model.SchedulerPipelineRun, error) {
+	return s.repo.AdminFindSchedulerPipelineRun(pipelineKey, tradeDate)
+}
+
+func (s *growthService) AdminClaimSchedulerPipelineRun(pipeline model.SchedulerPipeline, tradeDate string, triggerSource string, operator string, resetJobNames []string) (model.SchedulerPipelineRun, error) {
+	return s.repo.AdminClaimSchedulerPipelineRun(pipeline, tradeDate, triggerSource, operator, resetJobNames)
+}
+
+func (s *growthService) AdminUpdateSchedulerPipelineNodeRun(item model.SchedulerPipelineNodeRun) error {
+	return s.repo.AdminUpdateSchedulerPipelineNodeRun(item)
+}
+
+func (s *growthService) AdminFinishSchedulerPipelineRun(runID string, status string) error {
+	return s.repo.AdminFinishSchedulerPipelineRun(runID, status)
+}
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"time"
+
 	"sercherai/backend/internal/growth/model"
 	"sercherai/backend/internal/growth/repo"
+	"sercherai/backend/internal/platform/payment"
+	"sercherai/backend/internal/platform/pii"
+	"sercherai/backend/internal/platform/realtime"
 )
 
 type GrowthService interface {
@@ -21,6 +26,9 @@ type GrowthService interface {
 	CreateSubscription(userID string, subType string, scope string, frequency string) (string, error)
 	UpdateSubscription(userID string, id string, frequency string, status string) error
 	ListMessages(userID string, page int, pageSize int) ([]model.UserMessage, int, error)
+	Realtime() *realtime.Hub
+	ListUserMessagesAfter(userID string, afterSeq int64, limit int) ([]model.UserMessage, error)
+	LatestUserMessageSeq(userID string) (int64, error)
 	MarkMessageRead(userID string, id string) error
 	GetUserAccessProfile(userID string) (model.UserAccessProfile, error)
 	GetMembershipQuota(userID string) (model.MembershipQuota, error)
@@ -37,6 +45,14 @@ type GrowthService interface {
 	ListMyCommunityComments(userID string, page int, pageSize int) ([]model.CommunityComment, int, error)
 	CreateCommunityComment(input model.CommunityCommentCreateInput) (model.CommunityComment, error)
 	CreateCommunityReaction(input model.CommunityReactionInput) error
+	ListCommunityFollows(userID string, targetType string, page int, pageSize int) ([]model.CommunityFollow, int, error)
+	CreateCommunityFollow(userID string, targetType string, targetID string) (model.CommunityFollow, error)
+	DeleteCommunityFollow(userID string, id string) error
+	ListCommunityBlocks(userID string, page int, pageSize int) ([]model.CommunityUserBlock, int, error)
+	CreateCommunityBlock(userID string, blockedUserID string, blockType string) (model.CommunityUserBlock, error)
+	DeleteCommunityBlock(userID string, id string) error
+	ListCommunityMentions(userID string, page int, pageSize int) ([]model.CommunityMention, int, error)
+	ListCommunityFeed(userID string, page int, pageSize int) ([]model.CommunityFeedItem, int, error)
 	DeleteCommunityReaction(input model.CommunityReactionInput) error
 	CreateCommunityReport(input model.CommunityReportCreateInput) (model.CommunityReport, error)
 	ListStockRecommendations(userID string, tradeDate string, page int, pageSize int) ([]model.StockRecommendation, int, error)
@@ -61,8 +77,14 @@ type GrowthService interface {
 	ExecuteQueuedStrategyForecastL3Runs(limit int, operatorUserID string) (int, error)
 	RunStrategyForecastL3QualityBackfill(limit int, operatorUserID string) (int, error)
 	ListMembershipProducts(status string, page int, pageSize int) ([]model.MembershipProduct, int, error)
-	CreateMembershipOrder(userID string, productID string, payChannel string) (model.MembershipOrderAdmin, error)
+	CreateMembershipOrder(userID string, productID string, payChannel string, couponCode string) (model.MembershipOrderAdmin, error)
+	QuoteMembershipCoupon(userID string, productID string, code string) (model.MembershipCouponQuote, error)
+	ListUserMembershipCoupons(userID string) ([]model.MembershipCoupon, error)
+	GetMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error)
+	EnableMembershipAutoRenewal(userID string, productID string, payChannel string, agreementNo string) (model.MembershipAutoRenewal, error)
+	CancelMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error)
 	ListMembershipOrders(userID string, status string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error)
+	GetMembershipOrder(orderNo string) (model.MembershipOrderAdmin, error)
 	TrackExperimentEvent(item model.ExperimentEvent) error
 	BindMembershipOrderExperiment(orderNo string, item model.ExperimentOrderAttribution) error
 	GetRewardWallet(userID string) (model.RewardWallet, error)
@@ -72,6 +94,7 @@ type GrowthService interface {
 	ListArbitrageOpportunities(typeFilter string, page int, pageSize int) ([]model.ArbitrageOpportunity, int, error)
 	ListFuturesArbitrage(typeFilter string, page int, pageSize int) ([]model.ArbitrageRecommendation, int, error)
 	GetFuturesArbitrageDetail(id string) (model.ArbitrageRecommendation, error)
+	AdminRunFuturesArbitrageEngine(tradeDate string) (model.FuturesArbitrageEngineResult, error)
 	CreateFuturesAlert(userID string, contract string, alertType string, threshold float64) (string, error)
 	ListFuturesReviews(page int, pageSize int) ([]model.FuturesReview, int, error)
 	ListMarketEvents(eventType string, page int, pageSize int) ([]model.MarketEvent, int, error)
@@ -90,6 +113,7 @@ type GrowthService interface {
 	AdminListRiskHits(status string, page int, pageSize int) ([]model.RiskHit, int, error)
 	AdminReviewRiskHit(id string, status string, reason string) error
 	AdminListWithdrawRequests(page int, pageSize int) ([]model.WithdrawRequestInfo, int, error)
+	AdminGetWithdrawRequest(id string) (model.WithdrawRequestInfo, error)
 	AdminReviewWithdrawRequest(id string, status string, reason string) error
 	AdminListNewsCategories(status string, page int, pageSize int) ([]model.NewsCategory, int, error)
 	AdminCreateNewsCategory(name string, slug string, sort int, visibility string, status string) (string, error)
@@ -101,13 +125,41 @@ type GrowthService interface {
 	AdminListCommunityComments(query model.CommunityAdminCommentQuery) ([]model.CommunityComment, int, error)
 	AdminUpdateCommunityCommentStatus(id string, status string) error
 	AdminListCommunityReports(query model.CommunityAdminReportQuery) ([]model.CommunityReport, int, error)
-	AdminReviewCommunityReport(id string, status string, reviewNote string) error
+	AdminReviewCommunityReport(id string, status string, reviewerID string, reviewNote string) error
+	AdminListCommunitySensitiveWords(status string, category string, keyword string, page int, pageSize int) ([]model.CommunitySensitiveWord, int, error)
+	AdminCreateCommunitySensitiveWord(word string, category string, operator string) (string, error)
+	AdminUpdateCommunitySensitiveWordStatus(id string, status string) error
+	AdminListCommunityModerationFlags(query model.CommunityModerationFlagQuery) ([]model.CommunityModerationFlag, int, error)
+	AdminListCommunityUserBans(status string, userID string, page int, pageSize int) ([]model.CommunityUserBan, int, error)
+	AdminCreateCommunityUserBan(userID string, days int, reason string, operator string) (string, error)
+	AdminLiftCommunityUserBan(id string, operator string) error
+	AdminListCommunitySentiment(query model.CommunitySentimentQuery) ([]model.CommunitySentimentDaily, int, error)
+	AdminRebuildCommunitySentiment(statDate string) (int, error)
+	AdminRunCommunitySentimentIndex() (string, error)
 	AdminCreateNewsArticle(categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, authorID string) (string, error)
-	AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string) error
+	AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, editorID string) error
 	AdminPublishNewsArticle(id string, status string) error
+	AdminScheduleNewsArticle(id string, publishAt time.Time, embargoUntil time.Time) error
+	AdminCancelNewsArticleSchedule(id string) error
+	AdminRunNewsScheduledPublish() (string, error)
+	AdminListNewsArticleRevisions(articleID string, page int, pageSize int) ([]model.NewsArticleRevision, int, error)
+	AdminGetNewsArticleRevision(articleID string, revisionNo int) (model.NewsArticleRevision, error)
+	AdminDiffNewsArticleRevisions(articleID string, fromRevision int, toRevision int) (model.NewsArticleRevisionDiff, error)
+	AdminRestoreNewsArticleRevision(articleID string, revisionNo int, editorID string) (model.NewsArticleRevision, error)
+	AdminMergeNewsArticleDuplicate(id string, canonicalID string) error
+	AdminUnmergeNewsArticleDuplicate(id string) error
 	AdminCreateNewsAttachment(articleID string, fileName string, fileURL string, fileSize int64, mimeType string) (string, error)
 	AdminListNewsAttachments(articleID string) ([]model.NewsAttachment, error)
 	AdminDeleteNewsAttachment(id string) error
+	AdminGetNewsAttachment(id string) (model.NewsAttachment, error)
+	AdminCountNewsAttachmentsByFileURL(fileURL string) (int, error)
+	AdminListAttachmentDownloads(attachmentID string, userID string) ([]model.AttachmentDownloadRecord, error)
+	AdminListAttachmentDownloaderIDs(attachmentID string) ([]string, error)
+	UpsertNewsAttachmentText(item model.NewsAttachmentText) error
+	AdminListNewsAttachmentsMissingText(limit int) ([]model.NewsAttachment, error)
+	AdminListLegacyNewsAttachments(urlPrefixes []string, limit int) ([]model.NewsAttachment, error)
+	AdminRelinkNewsAttachmentFile(oldURL string, newURL string) (int64, error)
+	SearchNewsAttachmentTexts(userID string, keyword string, limit int) ([]model.NewsAttachmentSearchHit, int, error)
 	AdminListStockRecommendations(status string, page int, pageSize int) ([]model.StockRecommendation, int, error)
 	AdminCreateStockRecommendation(item model.StockRecommendation) (string, error)
 	AdminUpdateStockRecommendationStatus(id string, status string) error
@@ -137,7 +189,9 @@ type GrowthService interface {
 	AdminSyncTushareNewsIncremental(batchSize int) (string, error)
 	AdminSyncTushareNewsIncrementalWithOptions(opts model.TushareNewsSyncOptions) (string, []model.NewsSyncRunDetail, error)
 	AdminRunVIPMembershipLifecycle() (string, error)
+	AdminReleaseInviteCommissions() (string, error)
 	AdminGetQuantTopStocks(limit int, lookbackDays int) ([]model.StockQuantScore, error)
+	AdminGetQuantTopStocksForTradeDate(tradeDate string, limit int, lookbackDays int) ([]model.StockQuantScore, error)
 	AdminGetQuantEvaluation(windowDays int, topN int) (model.StockQuantEvaluationSummary, []model.StockQuantEvaluationPoint, []model.StockQuantRiskPerformance, []model.StockQuantRotationPoint, error)
 	AdminGenerateDailyStockRecommendations(tradeDate string) (model.AdminDailyStockRecommendationGenerationResult, error)
 	AdminGetStockSelectionOverview() (model.AdminStockSelectionOverview, error)
@@ -228,21 +282,36 @@ type GrowthService interface {
 	AdminListBrowseUserSegments(limit int) ([]model.AdminBrowseUserSegment, error)
 	AdminListUserMessages(userID string, messageType string, readStatus string, page int, pageSize int) ([]model.AdminUserMessage, int, error)
 	AdminCreateUserMessages(userIDs []string, title string, content string, messageType string) (int, []model.AdminMessageSendFailure, error)
-	AdminUpdateUserStatus(id string, status string) error
+	AdminUpdateUserStatus(id string, status string, operator string) error
 	AdminUpdateUserMemberLevel(id string, memberLevel string) error
-	AdminResetUserPasswordHash(id string, passwordHash string) error
+	AdminResetUserPasswordHash(id string, passwordHash string, operator string) error
 	AdminDashboardOverview() (model.AdminDashboardOverview, error)
 	AdminCreateOperationLog(module string, action string, targetType string, targetID string, operatorUserID string, beforeValue string, afterValue string, reason string) error
 	AdminListOperationLogs(module string, action string, operatorUserID string, page int, pageSize int) ([]model.AdminOperationLog, int, error)
 	AdminCreateAuditEvent(item model.AdminAuditEvent) error
 	AdminListAuditEvents(filter model.AdminAuditEventFilter, page int, pageSize int) ([]model.AdminAuditEvent, int, error)
 	AdminGetAuditEventSummary() (model.AdminAuditEventSummary, error)
+	AdminVerifyAuditLedger() (model.AdminAuditLedgerVerification, error)
+	AdminCreateAuditCheckpoint(operator string) (model.AdminAuditCheckpoint, error)
+	AdminListAuditCheckpoints(page int, pageSize int) ([]model.AdminAuditCheckpoint, int, error)
+	AdminExportAuditLedger(startDate string, endDate string, operator string) (model.AdminAuditLedgerExport, error)
 	AdminListMembershipProducts(status string, page int, pageSize int) ([]model.MembershipProduct, int, error)
 	AdminCreateMembershipProduct(name string, price float64, status string, memberLevel string, durationDays int) (string, error)
 	AdminUpdateMembershipProduct(id string, name string, price float64, status string, memberLevel string, durationDays int) error
 	AdminUpdateMembershipProductStatus(id string, status string) error
 	AdminListMembershipOrders(status string, userID string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error)
 	AdminUpdateMembershipOrderStatus(id string, status string) error
+	AdminRefundMembershipOrder(orderID string, reason string, operator string, channels payment.Resolver) (model.MembershipRefund, error)
+	AdminListMembershipRefunds(status string, page int, pageSize int) ([]model.MembershipRefund, int, error)
+	AdminPollPendingMembershipOrders(channels payment.Resolver) (string, error)
+	AdminListPaymentPollLogs(orderNo string, result string, page int, pageSize int) ([]model.PaymentPollLog, int, error)
+	AdminListMembershipCoupons(status string, source string, page int, pageSize int) ([]model.MembershipCoupon, int, error)
+	AdminCreateMembershipCoupon(item model.MembershipCoupon) (string, error)
+	AdminUpdateMembershipCouponStatus(id string, status string) error
+	AdminListMembershipCouponRedemptions(couponID string, status string, page int, pageSize int) ([]model.MembershipCouponRedemption, int, error)
+	AdminRunMembershipAutoRenewals(channels payment.Resolver) (string, error)
+	AdminListMembershipAutoRenewals(status string, userID string, page int, pageSize int) ([]model.MembershipAutoRenewal, int, error)
+	AdminListMembershipRenewalAttempts(renewalID string, result string, page int, pageSize int) ([]model.MembershipRenewalAttempt, int, error)
 	AdminGetExperimentAnalyticsSummary(days int) (model.AdminExperimentAnalyticsSummary, error)
 	AdminListVIPQuotaConfigs(memberLevel string, status string, page int, pageSize int) ([]model.VIPQuotaConfig, int, error)
 	AdminCreateVIPQuotaConfig(item model.VIPQuotaConfig) (string, error)
@@ -271,10 +340,21 @@ type GrowthService interface {
 	AdminGetMarketCoverageSummary() (model.MarketCoverageSummary, error)
 	AdminListSystemConfigs(keyword string, page int, pageSize int) ([]model.SystemConfig, int, error)
 	AdminUpsertSystemConfig(configKey string, configValue string, description string, operator string) error
+	AdminRotateConfigSecrets(operator string) (model.SystemConfigSecretRotationResult, error)
 	AdminListReviewTasks(module string, status string, submitterID string, reviewerID string, page int, pageSize int) ([]model.ReviewTask, int, error)
-	AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string) (string, error)
+	AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string, priority string) (string, error)
 	AdminAssignReviewTask(reviewID string, reviewerID string) error
 	AdminReviewTaskDecision(reviewID string, status string, reviewerID string, reviewNote string) error
+	AdminListApprovalPolicies() ([]model.ApprovalPolicy, error)
+	AdminGetApprovalPolicy(module string) (model.ApprovalPolicy, error)
+	AdminSaveApprovalPolicy(item model.ApprovalPolicy, operator string) (model.ApprovalPolicy, error)
+	AdminCreateApprovalRequest(item model.ApprovalRequest) (model.ApprovalRequest, error)
+	AdminListApprovalRequests(module string, status string, submitterID string, page int, pageSize int) ([]model.ApprovalRequest, int, error)
+	AdminGetApprovalRequest(id string) (model.ApprovalRequest, error)
+	AdminDecideApprovalRequest(id string, approverID string, decision string, note string) (model.ApprovalRequest, error)
+	AdminClaimApprovalExecution(id string) (model.ApprovalRequest, error)
+	AdminFinishApprovalExecution(id string, executeError string) error
+	AdminMatchApprovalPolicy(module string, amount float64) (model.ApprovalPolicy, bool, error)
 	GetSchedulerJobNameByRunID(runID string) (string, error)
 	AdminListSchedulerJobRuns(jobName string, status string, page int, pageSize int) ([]model.SchedulerJobRun, int, error)
 	AdminListNewsSyncRunDetails(runID string, syncType string, source string, symbol string, status string, page int, pageSize int) ([]model.NewsSyncRunDetail, int, error)
@@ -286,12 +366,39 @@ type GrowthService interface {
 	AdminUpdateSchedulerJobDefinition(id string, item model.SchedulerJobDefinition, operatorID string) error
 	AdminUpdateSchedulerJobDefinitionStatus(id string, status string, operatorID string) error
 	AdminDeleteSchedulerJobDefinition(id string) error
+	AdminListSchedulerPipelines(status string) ([]model.SchedulerPipeline, error)
+	AdminGetSchedulerPipeline(pipelineKey string) (model.SchedulerPipeline, error)
+	AdminSaveSchedulerPipeline(item model.SchedulerPipeline, operator string) (model.SchedulerPipeline, error)
+	AdminListSchedulerPipelineRuns(pipelineKey string, tradeDate string, status string, page int, pageSize int) ([]model.SchedulerPipelineRun, int, error)
+	AdminGetSchedulerPipelineRun(runID string) (model.SchedulerPipelineRun, error)
+	AdminFindSchedulerPipelineRun(pipelineKey string, tradeDate string) (model.SchedulerPipelineRun, error)
+	AdminClaimSchedulerPipelineRun(pipeline model.SchedulerPipeline, tradeDate string, triggerSource string, operator string, resetJobNames []string) (model.SchedulerPipelineRun, error)
+	AdminUpdateSchedulerPipelineNodeRun(item model.SchedulerPipelineNodeRun) error
+	AdminFinishSchedulerPipelineRun(runID string, status string) error
 	AdminListWorkflowMessages(module string, eventType string, isRead string, receiverID string, page int, pageSize int) ([]model.WorkflowMessage, int, error)
 	AdminCountUnreadWorkflowMessages(module string, eventType string, receiverID string) (int, error)
+	AdminListWorkflowMessagesAfter(receiverID string, afterSeq int64, limit int) ([]model.WorkflowMessage, error)
+	AdminLatestWorkflowMessageSeq(receiverID string) (int64, error)
 	AdminUpdateWorkflowMessageRead(id string, isRead bool) error
 	AdminBulkReadWorkflowMessages(module string, eventType string, receiverID string) (int64, error)
 	AdminCreateWorkflowMessage(reviewID string, targetID string, module string, receiverID string, senderID string, eventType string, title string, content string) error
 	AdminGetWorkflowMetrics(module string, receiverID string) (model.WorkflowMetrics, error)
+	AdminListReviewSLAPolicies() ([]model.ReviewSLAPolicy, error)
+	AdminSaveReviewSLAPolicy(item model.ReviewSLAPolicy, operator string) (model.ReviewSLAPolicy, error)
+	AdminListReviewSLAItems(module string, status string, assigneeID string, page int, pageSize int) ([]model.ReviewSLAItem, int, error)
+	AdminGetReviewSLAMetrics(module string, reviewerID string, days int) (model.ReviewSLAMetrics, error)
+	AdminRunReviewSLAEscalation() (string, error)
+	AdminCreateExportJob(item model.ExportJob) (model.ExportJob, error)
+	AdminGetExportJob(id string) (model.ExportJob, error)
+	AdminListExportJobs(requestedBy string, exportType string, status string, page int, pageSize int) ([]model.ExportJob, int, error)
+	AdminClaimExportJobs(limit int, staleBefore time.Time) ([]model.ExportJob, error)
+	AdminFinishExportJob(item model.ExportJob) error
+	AdminListExpiredExportJobs(before time.Time, limit int) ([]model.ExportJob, error)
+	AdminExpireExportJob(id string) error
+	AdminRecordExportDownload(item model.ExportDownload) error
+	AdminListExportDownloads(jobID string) ([]model.ExportDownload, error)
+	AdminRecordDeliveredExport(item model.ExportJob, download model.ExportDownload) (model.ExportJob, error)
+	AdminListLoginLogs(action string, status string, dateFrom string, dateTo string, page int, pageSize int) ([]model.LoginLog, int, error)
 	AdminGetSchedulerJobMetrics(jobName string) (model.SchedulerJobMetrics, error)
 }
 
@@ -323,7 +430,22 @@ func (s *growthService) BuildStrategyEngineFuturesStrategyContext(input model.St
 	return s.repo.BuildStrategyEngineFuturesStrategyContext(input)
 }
 
+// AdminCreateAuditEvent stores item with personal data in its text fields
+// masked; audit payloads never keep a full phone, email or IP.
 func (s *growthService) AdminCreateAuditEvent(item model.AdminAuditEvent) error {
+	item.Title = pii.MaskText(item.Title)
+	item.Summary = pii.MaskText(item.Summary)
+	item.Detail = pii.MaskText(item.Detail)
+	if len(item.Metadata) > 0 {
+		metadata := make(map[string]any, len(item.Metadata))
+		for key, value := range item.Metadata {
+			if text, ok := value.(string); ok {
+				value = pii.MaskText(text)
+			}
+			metadata[key] = value
+		}
+		item.Metadata = metadata
+	}
 	return s.repo.AdminCreateAuditEvent(item)
 }
 
@@ -335,6 +457,22 @@ func (s *growthService) AdminGetAuditEventSummary() (model.AdminAuditEventSummar
 	return s.repo.AdminGetAuditEventSummary()
 }
 
+func (s *growthService) AdminVerifyAuditLedger() (model.AdminAuditLedgerVerification, error) {
+	return s.repo.AdminVerifyAuditLedger()
+}
+
+func (s *growthService) AdminCreateAuditCheckpoint(operator string) (model.AdminAuditCheckpoint, error) {
+	return s.repo.AdminCreateAuditCheckpoint(operator)
+}
+
+func (s *growthService) AdminListAuditCheckpoints(page int, pageSize int) ([]model.AdminAuditCheckpoint, int, error) {
+	return s.repo.AdminListAuditCheckpoints(page, pageSize)
+}
+
+func (s *growthService) AdminExportAuditLedger(startDate string, endDate string, operator string) (model.AdminAuditLedgerExport, error) {
+	return s.repo.AdminExportAuditLedger(startDate, endDate, operator)
+}
+
 func (s *growthService) ListRechargeRecords(userID string, status string, page int, pageSize int) ([]model.RechargeRecord, int, error) {
 	return s.repo.ListRechargeRecords(userID, status, page, pageSize)
 }
@@ -384,6 +522,18 @@ func (s *growthService) ListMessages(userID string, page int, pageSize int) ([]m
 	return s.repo.ListMessages(userID, page, pageSize)
 }
 
+func (s *growthService) Realtime() *realtime.Hub {
+	return s.repo.Realtime()
+}
+
+func (s *growthService) ListUserMessagesAfter(userID string, afterSeq int64, limit int) ([]model.UserMessage, error) {
+	return s.repo.ListUserMessagesAfter(userID, afterSeq, limit)
+}
+
+func (s *growthService) LatestUserMessageSeq(userID string) (int64, error) {
+	return s.repo.LatestUserMessageSeq(userID)
+}
+
 func (s *growthService) MarkMessageRead(userID string, id string) error {
 	return s.repo.MarkMessageRead(userID, id)
 }
@@ -460,14 +610,38 @@ func (s *growthService) ListMembershipProducts(status string, page int, pageSize
 	return s.repo.ListMembershipProducts(status, page, pageSize)
 }
 
-func (s *growthService) CreateMembershipOrder(userID string, productID string, payChannel string) (model.MembershipOrderAdmin, error) {
-	return s.repo.CreateMembershipOrder(userID, productID, payChannel)
+func (s *growthService) CreateMembershipOrder(userID string, productID string, payChannel string, couponCode string) (model.MembershipOrderAdmin, error) {
+	return s.repo.CreateMembershipOrder(userID, productID, payChannel, couponCode)
+}
+
+func (s *growthService) QuoteMembershipCoupon(userID string, productID string, code string) (model.MembershipCouponQuote, error) {
+	return s.repo.QuoteMembershipCoupon(userID, productID, code)
+}
+
+func (s *growthService) ListUserMembershipCoupons(userID string) ([]model.MembershipCoupon, error) {
+	return s.repo.ListUserMembershipCoupons(userID)
+}
+
+func (s *growthService) GetMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error) {
+	return s.repo.GetMembershipAutoRenewal(userID)
+}
+
+func (s *growthService) EnableMembershipAutoRenewal(userID string, productID string, payChannel string, agreementNo string) (model.MembershipAutoRenewal, error) {
+	return s.repo.EnableMembershipAutoRenewal(userID, productID, payChannel, agreementNo)
+}
+
+func (s *growthService) CancelMembershipAutoRenewal(userID string) (model.MembershipAutoRenewal, error) {
+	return s.repo.CancelMembershipAutoRenewal(userID)
 }
 
 func (s *growthService) ListMembershipOrders(userID string, status string, page int, pageSize int) ([]model.MembershipOrderAdmin, int, error) {
 	return s.repo.ListMembershipOrders(userID, status, page, pageSize)
 }
 
+func (s *growthService) GetMembershipOrder(orderNo string) (model.MembershipOrderAdmin, error) {
+	return s.repo.GetMembershipOrder(orderNo)
+}
+
 func (s *growthService) TrackExperimentEvent(item model.ExperimentEvent) error {
 	return s.repo.TrackExperimentEvent(item)
 }
@@ -504,6 +678,10 @@ func (s *growthService) GetFuturesArbitrageDetail(id string) (model.ArbitrageRec
 	return s.repo.GetFuturesArbitrageDetail(id)
 }
 
+func (s *growthService) AdminRunFuturesArbitrageEngine(tradeDate string) (model.FuturesArbitrageEngineResult, error) {
+	return s.repo.AdminRunFuturesArbitrageEngine(tradeDate)
+}
+
 func (s *growthService) CreateFuturesAlert(userID string, contract string, alertType string, threshold float64) (string, error) {
 	return s.repo.CreateFuturesAlert(userID, contract, alertType, threshold)
 }
@@ -604,8 +782,8 @@ func (s *growthService) AdminCreateNewsArticle(categoryID string, title string,
 	return s.repo.AdminCreateNewsArticle(categoryID, title, summary, content, coverURL, visibility, status, authorID)
 }
 
-func (s *growthService) AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string) error {
-	return s.repo.AdminUpdateNewsArticle(id, categoryID, title, summary, content, coverURL, visibility, status)
+func (s *growthService) AdminUpdateNewsArticle(id string, categoryID string, title string, summary string, content string, coverURL string, visibility string, status string, editorID string) error {
+	return s.repo.AdminUpdateNewsArticle(id, categoryID, title, summary, content, coverURL, visibility, status, editorID)
 }
 
 func (s *growthService) AdminPublishNewsArticle(id string, status string) error {
@@ -624,6 +802,14 @@ func (s *growthService) AdminDeleteNewsAttachment(id string) error {
 	return s.repo.AdminDeleteNewsAttachment(id)
 }
 
+func (s *growthService) AdminGetNewsAttachment(id string) (model.NewsAttachment, error) {
+	return s.repo.AdminGetNewsAttachment(id)
+}
+
+func (s *growthService) AdminCountNewsAttachmentsByFileURL(fileURL string) (int, error) {
+	return s.repo.AdminCountNewsAttachmentsByFileURL(fileURL)
+}
+
 func (s *growthService) AdminListStockRecommendations(status string, page int, pageSize int) ([]model.StockRecommendation, int, error) {
 	return s.repo.AdminListStockRecommendations(status, page, pageSize)
 }
@@ -700,10 +886,18 @@ func (s *growthService) AdminRunVIPMembershipLifecycle() (string, error) {
 	return s.repo.AdminRunVIPMembershipLifecycle()
 }
 
+func (s *growthService) AdminReleaseInviteCommissions() (string, error) {
+	return s.repo.AdminReleaseInviteCommissions()
+}
+
 func (s *growthService) AdminGetQuantTopStocks(limit int, lookbackDays int) ([]model.StockQuantScore, error) {
 	return s.repo.AdminGetQuantTopStocks(limit, lookbackDays)
 }
 
+func (s *growthService) AdminGetQuantTopStocksForTradeDate(tradeDate string, limit int, lookbackDays int) ([]model.StockQuantScore, error) {
+	return s.repo.AdminGetQuantTopStocksForTradeDate(tradeDate, limit, lookbackDays)
+}
+
 func (s *growthService) AdminGetQuantEvaluation(windowDays int, topN int) (model.StockQuantEvaluationSummary, []model.StockQuantEvaluationPoint, []model.StockQuantRiskPerformance, []model.StockQuantRotationPoint, error) {
 	return s.repo.AdminGetQuantEvaluation(windowDays, topN)
 }
@@ -820,8 +1014,8 @@ func (s *growthService) AdminCreateUserMessages(userIDs []string, title string,
 	return s.repo.AdminCreateUserMessages(userIDs, title, content, messageType)
 }
 
-func (s *growthService) AdminUpdateUserStatus(id string, status string) error {
-	return s.repo.AdminUpdateUserStatus(id, status)
+func (s *growthService) AdminUpdateUserStatus(id string, status string, operator string) error {
+	return s.repo.AdminUpdateUserStatus(id, status, operator)
 }
 
 func (s *growthService) AdminUpdateUserMemberLevel(id string, memberLevel string) error {
@@ -829,16 +1023,18 @@ func (s *growthService) AdminUpdateUserMemberLevel(id string, memberLevel string
 }
 
 
-func (s *growthService) AdminResetUserPasswordHash(id string, passwordHash string) error {
-	return s.repo.AdminResetUserPasswordHash(id, passwordHash)
+func (s *growthService) AdminResetUserPasswordHash(id string, passwordHash string, operator string) error {
+	return s.repo.AdminResetUserPasswordHash(id, passwordHash, operator)
 }
 
 func (s *growthService) AdminDashboardOverview() (model.AdminDashboardOverview, error) {
 	return s.repo.AdminDashboardOverview()
 }
 
+// AdminCreateOperationLog masks personal data in the logged values the same
+// way as AdminCreateAuditEvent.
 func (s *growthService) AdminCreateOperationLog(module string, action string, targetType string, targetID string, operatorUserID string, beforeValue string, afterValue string, reason string) error {
-	return s.repo.AdminCreateOperationLog(module, action, targetType, targetID, operatorUserID, beforeValue, afterValue, reason)
+	return s.repo.AdminCreateOperationLog(module, action, targetType, targetID, operatorUserID, pii.MaskText(beforeValue), pii.MaskText(afterValue), pii.MaskText(reason))
 }
 
 func (s *growthService) AdminListOperationLogs(module string, action string, operatorUserID string, page int, pageSize int) ([]model.AdminOperationLog, int, error) {
@@ -869,6 +1065,50 @@ func (s *growthService) AdminUpdateMembershipOrderStatus(id string, status strin
 	return s.repo.AdminUpdateMembershipOrderStatus(id, status)
 }
 
+func (s *growthService) AdminRefundMembershipOrder(orderID string, reason string, operator string, channels payment.Resolver) (model.MembershipRefund, error) {
+	return s.repo.AdminRefundMembershipOrder(orderID, reason, operator, channels)
+}
+
+func (s *growthService) AdminListMembershipRefunds(status string, page int, pageSize int) ([]model.MembershipRefund, int, error) {
+	return s.repo.AdminListMembershipRefunds(status, page, pageSize)
+}
+
+func (s *growthService) AdminPollPendingMembershipOrders(channels payment.Resolver) (string, error) {
+	return s.repo.AdminPollPendingMembershipOrders(channels)
+}
+
+func (s *growthService) AdminListPaymentPollLogs(orderNo string, result string, page int, pageSize int) ([]model.PaymentPollLog, int, error) {
+	return s.repo.AdminListPaymentPollLogs(orderNo, result, page, pageSize)
+}
+
+func (s *growthService) AdminListMembershipCoupons(status string, source string, page int, pageSize int) ([]model.MembershipCoupon, int, error) {
+	return s.repo.AdminListMembershipCoupons(status, source, page, pageSize)
+}
+
+func (s *growthService) AdminCreateMembershipCoupon(item model.MembershipCoupon) (string, error) {
+	return s.repo.AdminCreateMembershipCoupon(item)
+}
+
+func (s *growthService) AdminUpdateMembershipCouponStatus(id string, status string) error {
+	return s.repo.AdminUpdateMembershipCouponStatus(id, status)
+}
+
+func (s *growthService) AdminListMembershipCouponRedemptions(couponID string, status string, page int, pageSize int) ([]model.MembershipCouponRedemption, int, error) {
+	return s.repo.AdminListMembershipCouponRedemptions(couponID, status, page, pageSize)
+}
+
+func (s *growthService) AdminRunMembershipAutoRenewals(channels payment.Resolver) (string, error) {
+	return s.repo.AdminRunMembershipAutoRenewals(channels)
+}
+
+func (s *growthService) AdminListMembershipAutoRenewals(status string, userID string, page int, pageSize int) ([]model.MembershipAutoRenewal, int, error) {
+	return s.repo.AdminListMembershipAutoRenewals(status, userID, page, pageSize)
+}
+
+func (s *growthService) AdminListMembershipRenewalAttempts(renewalID string, result string, page int, pageSize int) ([]model.MembershipRenewalAttempt, int, error) {
+	return s.repo.AdminListMembershipRenewalAttempts(renewalID, result, page, pageSize)
+}
+
 func (s *growthService) AdminGetExperimentAnalyticsSummary(days int) (model.AdminExperimentAnalyticsSummary, error) {
 	return s.repo.AdminGetExperimentAnalyticsSummary(days)
 }
@@ -929,12 +1169,16 @@ func (s *growthService) AdminUpsertSystemConfig(configKey string, configValue st
 	return s.repo.AdminUpsertSystemConfig(configKey, configValue, description, operator)
 }
 
+func (s *growthService) AdminRotateConfigSecrets(operator string) (model.SystemConfigSecretRotationResult, error) {
+	return s.repo.AdminRotateConfigSecrets(operator)
+}
+
 func (s *growthService) AdminListReviewTasks(module string, status string, submitterID string, reviewerID string, page int, pageSize int) ([]model.ReviewTask, int, error) {
 	return s.repo.AdminListReviewTasks(module, status, submitterID, reviewerID, page, pageSize)
 }
 
-func (s *growthService) AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string) (string, error) {
-	return s.repo.AdminSubmitReviewTask(module, targetID, submitterID, reviewerID, submitNote)
+func (s *growthService) AdminSubmitReviewTask(module string, targetID string, submitterID string, reviewerID string, submitNote string, priority string) (string, error) {
+	return s.repo.AdminSubmitReviewTask(module, targetID, submitterID, reviewerID, submitNote, priority)
 }
 
 func (s *growthService) AdminAssignReviewTask(reviewID string, reviewerID string) error {
@@ -997,6 +1241,14 @@ func (s *growthService) AdminCountUnreadWorkflowMessages(module string, eventTyp
 	return s.repo.AdminCountUnreadWorkflowMessages(module, eventType, receiverID)
 }
 
+func (s *growthService) AdminListWorkflowMessagesAfter(receiverID string, afterSeq int64, limit int) ([]model.WorkflowMessage, error) {
+	return s.repo.AdminListWorkflowMessagesAfter(receiverID, afterSeq, limit)
+}
+
+func (s *growthService) AdminLatestWorkflowMessageSeq(receiverID string) (int64, error) {
+	return s.repo.AdminLatestWorkflowMessageSeq(receiverID)
+}
+
 func (s *growthService) AdminUpdateWorkflowMessageRead(id string, isRead bool) error {
 	return s.repo.AdminUpdateWorkflowMessageRead(id, isRead)
 }
@@ -16,6 +16,12 @@ type Claims struct {
 }
 
 func SignToken(secret string, userID string, role string, tokenType string, ttl time.Duration) (string, error) {
+	return SignSessionToken(secret, userID, role, tokenType, fmt.Sprintf("%d", time.Now().UnixNano()), ttl)
+}
+
+// SignSessionToken signs a token whose jti is the server-side session id, so
+// the access and refresh tokens of one login can be revoked together.
+func SignSessionToken(secret string, userID string, role string, tokenType string, sessionID string, ttl time.Duration) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		UserID:    userID,
@@ -23,7 +29,7 @@ func SignToken(secret string, userID string, role string, tokenType string, ttl
 		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   userID,
-			ID:        fmt.Sprintf("%d", now.UnixNano()),
+			ID:        sessionID,
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
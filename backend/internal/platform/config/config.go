@@ -32,8 +32,14 @@ type Config struct {
 	AttachmentSigningSecret    string
 	AttachmentSigningTTLSecond int
 	AttachmentUploadDir        string
+	AttachmentStorageDir       string
 	AttachmentUploadMaxMB      int
+	AttachmentWatermarkEnabled bool
+	ExportSigningSecret        string
 	PaymentSigningSecret       string
+	AuditSigningSecret         string
+	ConfigMasterKeys           string
+	ConfigMasterKeyFile        string
 	StrategyEngineBaseURL      string
 	StrategyEngineTimeoutMS    int
 	StrategyEnginePollMS       int
@@ -70,8 +76,14 @@ func Load() Config {
 		AttachmentSigningSecret:    getEnv("ATTACHMENT_SIGNING_SECRET", ""),
 		AttachmentSigningTTLSecond: getEnvInt("ATTACHMENT_SIGNING_TTL_SECONDS", 300),
 		AttachmentUploadDir:        getEnv("ATTACHMENT_UPLOAD_DIR", "./uploads"),
+		AttachmentStorageDir:       getEnv("ATTACHMENT_STORAGE_DIR", "./storage/attachments"),
 		AttachmentUploadMaxMB:      getEnvInt("ATTACHMENT_UPLOAD_MAX_MB", 20),
+		AttachmentWatermarkEnabled: getEnvBool("ATTACHMENT_WATERMARK_ENABLED", true),
+		ExportSigningSecret:        getEnv("EXPORT_SIGNING_SECRET", ""),
 		PaymentSigningSecret:       getEnv("PAYMENT_SIGNING_SECRET", ""),
+		AuditSigningSecret:         getEnv("AUDIT_SIGNING_SECRET", ""),
+		ConfigMasterKeys:           getEnv("CONFIG_MASTER_KEYS", ""),
+		ConfigMasterKeyFile:        getEnv("CONFIG_MASTER_KEY_FILE", ""),
 		StrategyEngineBaseURL:      getEnv("STRATEGY_ENGINE_BASE_URL", ""),
 		StrategyEngineTimeoutMS:    getEnvInt("STRATEGY_ENGINE_TIMEOUT_MS", 15000),
 		StrategyEnginePollMS:       getEnvInt("STRATEGY_ENGINE_POLL_MS", 250),
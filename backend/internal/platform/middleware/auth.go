@@ -9,9 +9,16 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"sercherai/backend/internal/platform/auth"
+	"sercherai/backend/internal/platform/session"
+	"sercherai/backend/internal/platform/streamticket"
 )
 
-func AuthRequired(secret string) gin.HandlerFunc {
+// AuthRequired verifies the access token and, when a session store is wired,
+// that its jti still names an active session so revoked logins stop working
+// before the token expires. Access tokens signed before the session registry
+// have no session to check; they are accepted until they expire so the
+// rollout does not log everyone out, and their refresh moves them onto one.
+func AuthRequired(secret string, sessions *session.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
 		if header == "" {
@@ -37,9 +44,107 @@ func AuthRequired(secret string) gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 40105, "message": "invalid token type", "data": struct{}{}})
 			return
 		}
+		sessionID := claims.ID
+		if !session.IsID(sessionID) {
+			sessionID = ""
+		}
+		if sessions != nil && sessionID != "" {
+			active, err := sessions.Check(sessionID, claims.UserID, c.ClientIP())
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"code": 50301, "message": "session store unavailable", "data": struct{}{}})
+				return
+			}
+			if !active {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 40106, "message": "session revoked", "data": struct{}{}})
+				return
+			}
+		}
 
 		c.Set("user_id", claims.UserID)
+		c.Set("session_id", sessionID)
 		c.Set("role", claims.Role)
+		setSessionCheck(c, sessions, sessionID, claims.UserID)
+		c.Next()
+	}
+}
+
+// StreamAuthRequired authenticates a stream request. Clients that can set
+// headers use the Authorization header as everywhere else; the browser
+// EventSource passes a single-use ?ticket= from POST /auth/stream-ticket
+// instead, so no access token ends up in URLs or access logs.
+func StreamAuthRequired(secret string, sessions *session.Store, tickets *streamticket.Store) gin.HandlerFunc {
+	headerAuth := AuthRequired(secret, sessions)
+	return func(c *gin.Context) {
+		raw := strings.TrimSpace(c.Query("ticket"))
+		if raw == "" || c.GetHeader("Authorization") != "" {
+			headerAuth(c)
+			return
+		}
+		ticket, ok, err := tickets.Redeem(c.Request.Context(), raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"code": 50301, "message": "stream ticket store unavailable", "data": struct{}{}})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 40108, "message": "invalid stream ticket", "data": struct{}{}})
+			return
+		}
+		// A ticket without a session was issued to a pre-registry access
+		// token, which AuthRequired accepts until it expires.
+		if sessions != nil && ticket.SessionID != "" {
+			active, err := sessions.Check(ticket.SessionID, ticket.UserID, c.ClientIP())
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"code": 50301, "message": "session store unavailable", "data": struct{}{}})
+				return
+			}
+			if !active {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 40106, "message": "session revoked", "data": struct{}{}})
+				return
+			}
+		}
+
+		c.Set("user_id", ticket.UserID)
+		c.Set("session_id", ticket.SessionID)
+		c.Set("role", ticket.Role)
+		setSessionCheck(c, sessions, ticket.SessionID, ticket.UserID)
+		c.Next()
+	}
+}
+
+// setSessionCheck stores a func() (bool, error) under "session_check" that
+// re-runs the session check for this request. Long-lived responses such as
+// message streams call it again while they are open, so revoking the session
+// also cuts off a stream that was opened before the revoke.
+func setSessionCheck(c *gin.Context, sessions *session.Store, sessionID string, userID string) {
+	if sessions == nil || sessionID == "" {
+		return
+	}
+	ip := c.ClientIP()
+	c.Set("session_check", func() (bool, error) {
+		return sessions.Check(sessionID, userID, ip)
+	})
+}
+
+// StepUpRequired guards sensitive writes behind a recent second-factor check
+// on the current session. Without a session store (in-memory mode) it is a
+// no-op, like the other DB-backed guards.
+func StepUpRequired(sessions *session.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if sessions == nil {
+			c.Next()
+			return
+		}
+		sessionIDValue, _ := c.Get("session_id")
+		sessionID, _ := sessionIDValue.(string)
+		verified, err := sessions.MFAVerifiedWithin(sessionID, session.StepUpWindow)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"code": 50001, "message": err.Error(), "data": struct{}{}})
+			return
+		}
+		if !verified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": 40307, "message": "2fa verification required", "data": struct{}{}})
+			return
+		}
 		c.Next()
 	}
 }
@@ -67,7 +172,14 @@ func RoleRequired(roles ...string) gin.HandlerFunc {
 
 func PermissionRequired(db *sql.DB, permissionCodes ...string) gin.HandlerFunc {
 	requiredCodes := normalizePermissionCodes(permissionCodes)
+	return PermissionRequiredFor(db, func(*gin.Context) []string { return requiredCodes })
+}
+
+// PermissionRequiredFor is PermissionRequired for routes whose permission
+// depends on the request, such as a path parameter naming the dataset.
+func PermissionRequiredFor(db *sql.DB, resolve func(c *gin.Context) []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		requiredCodes := normalizePermissionCodes(resolve(c))
 		if len(requiredCodes) == 0 || db == nil {
 			c.Next()
 			return
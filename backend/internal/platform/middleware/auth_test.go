@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"sercherai/backend/internal/platform/auth"
+	"sercherai/backend/internal/platform/session"
+)
+
+func TestAuthRequiredAcceptsPreSessionTokensUntilTheyExpire(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	router := gin.New()
+	router.GET("/me", AuthRequired("jwt-secret", session.NewStore(db, nil)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "session_id": c.GetString("session_id")})
+	})
+	call := func(token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var payload map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		return rec.Code, payload
+	}
+
+	// Tokens signed before the session registry carry a timestamp jti, or
+	// none at all; neither names a session, so no lookup is made.
+	for _, jti := range []string{"1743300000000000000", ""} {
+		token, err := auth.SignSessionToken("jwt-secret", "u_legacy", "USER", "ACCESS", jti, time.Hour)
+		if err != nil {
+			t.Fatalf("SignSessionToken() error = %v", err)
+		}
+		if status, payload := call(token); status != http.StatusOK || payload["user_id"] != "u_legacy" || payload["session_id"] != "" {
+			t.Fatalf("jti %q: expected legacy token to be accepted without a session, got %d %+v", jti, status, payload)
+		}
+	}
+
+	expired, err := auth.SignSessionToken("jwt-secret", "u_legacy", "USER", "ACCESS", "1743300000000000000", -time.Minute)
+	if err != nil {
+		t.Fatalf("SignSessionToken() error = %v", err)
+	}
+	if status, payload := call(expired); status != http.StatusUnauthorized || payload["code"] != float64(40103) {
+		t.Fatalf("expected expired legacy token to be rejected, got %d %+v", status, payload)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, status, expires_at FROM auth_sessions WHERE id = ?")).
+		WithArgs("sess_revoked").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status", "expires_at"}).AddRow("u_legacy", session.StatusRevoked, time.Now().Add(time.Hour)))
+	revoked, err := auth.SignSessionToken("jwt-secret", "u_legacy", "USER", "ACCESS", "sess_revoked", time.Hour)
+	if err != nil {
+		t.Fatalf("SignSessionToken() error = %v", err)
+	}
+	if status, payload := call(revoked); status != http.StatusUnauthorized || payload["code"] != float64(40106) {
+		t.Fatalf("expected revoked session token to be rejected, got %d %+v", status, payload)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
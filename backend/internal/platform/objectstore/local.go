@@ -0,0 +1,113 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalStore keeps objects under Root on local disk. Root must not be
+// served statically; the backend streams objects after checking access.
+type LocalStore struct {
+	Root string
+}
+
+func NewLocalStore(root string) *LocalStore {
+	return &LocalStore{Root: root}
+}
+
+func (s *LocalStore) Provider() string {
+	return ProviderLocal
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, payload []byte, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(payload); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	info, err := s.Head(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	target, _ := s.path(key)
+	file, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+	return file, info, nil
+}
+
+func (s *LocalStore) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	stat, err := os.Stat(target)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, ErrNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	if stat.IsDir() {
+		return ObjectInfo{}, ErrNotFound
+	}
+	return ObjectInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(target)),
+	}, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "", ErrPresignUnsupported
+}
@@ -0,0 +1,104 @@
+// Package objectstore stores uploaded files behind one interface so handlers
+// do not care whether bytes land on local disk, Qiniu or an S3-compatible
+// bucket. Objects are private: readers go through a presigned URL or, for
+// stores that cannot presign, through the backend.
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderLocal = "LOCAL"
+	ProviderQiniu = "QINIU"
+	ProviderS3    = "S3"
+
+	refScheme = "storage://"
+)
+
+var (
+	ErrNotFound           = errors.New("object not found")
+	ErrPresignUnsupported = errors.New("object store cannot presign urls")
+	ErrInvalidKey         = errors.New("invalid object key")
+)
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+}
+
+type Store interface {
+	Provider() string
+	Put(ctx context.Context, key string, payload []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Head(ctx context.Context, key string) (ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a time-limited download URL, or
+	// ErrPresignUnsupported when the caller has to stream the object itself.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// ContentKey builds a content-addressed key under prefix, so identical
+// uploads map to one object. It returns the key and the hex SHA-256.
+func ContentKey(prefix string, payload []byte, ext string) (string, string) {
+	sum := sha256.Sum256(payload)
+	digest := hex.EncodeToString(sum[:])
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return JoinKey(prefix, digest[:2], digest+ext), digest
+}
+
+func JoinKey(parts ...string) string {
+	normalized := make([]string, 0, len(parts))
+	for _, part := range parts {
+		p := strings.Trim(strings.TrimSpace(part), "/")
+		if p == "" {
+			continue
+		}
+		normalized = append(normalized, p)
+	}
+	return strings.Join(normalized, "/")
+}
+
+// FormatRef renders the reference stored in place of a public URL, e.g.
+// storage://S3/news/attachments/ab/ab12....pdf.
+func FormatRef(provider string, key string) string {
+	return refScheme + strings.ToUpper(strings.TrimSpace(provider)) + "/" + strings.TrimLeft(key, "/")
+}
+
+// ParseRef reverses FormatRef. ok is false for anything that is not a
+// storage reference, such as a plain http URL from a news sync.
+func ParseRef(value string) (provider string, key string, ok bool) {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, refScheme) {
+		return "", "", false
+	}
+	rest := strings.TrimPrefix(trimmed, refScheme)
+	idx := strings.Index(rest, "/")
+	if idx <= 0 || idx == len(rest)-1 {
+		return "", "", false
+	}
+	return strings.ToUpper(rest[:idx]), rest[idx+1:], true
+}
+
+func validKey(key string) bool {
+	if strings.TrimSpace(key) == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QiniuStore talks to Qiniu Kodo. Domain is the bucket's bound download
+// domain; private buckets are read through PresignGet.
+type QiniuStore struct {
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Domain    string
+	Region    string
+	UseHTTPS  bool
+	Client    *http.Client
+	// RSHost overrides the management API host, mainly for tests.
+	RSHost string
+}
+
+// qiniuNoSuchFile is the status Qiniu's management API returns for a
+// missing key.
+const qiniuNoSuchFile = 612
+
+func (s *QiniuStore) Provider() string {
+	return ProviderQiniu
+}
+
+func (s *QiniuStore) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
+
+func (s *QiniuStore) scheme() string {
+	if s.UseHTTPS {
+		return "https://"
+	}
+	return "http://"
+}
+
+func (s *QiniuStore) configured() error {
+	if strings.TrimSpace(s.AccessKey) == "" || strings.TrimSpace(s.SecretKey) == "" ||
+		strings.TrimSpace(s.Bucket) == "" || strings.TrimSpace(s.Domain) == "" {
+		return errors.New("oss qiniu config incomplete, require access_key/secret_key/bucket/domain")
+	}
+	return nil
+}
+
+func (s *QiniuStore) UploadHost() string {
+	regionHost := map[string]string{
+		"z0":  "up-z0.qiniup.com",
+		"z1":  "up-z1.qiniup.com",
+		"z2":  "up-z2.qiniup.com",
+		"na0": "up-na0.qiniup.com",
+		"as0": "up-as0.qiniup.com",
+	}
+	host, ok := regionHost[strings.ToLower(strings.TrimSpace(s.Region))]
+	if !ok || host == "" {
+		host = "up-z0.qiniup.com"
+	}
+	return s.scheme() + host
+}
+
+// PublicURL is the plain download URL, only readable on public buckets.
+func (s *QiniuStore) PublicURL(key string) string {
+	domain := strings.TrimSpace(s.Domain)
+	if domain == "" {
+		return key
+	}
+	lower := strings.ToLower(domain)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return strings.TrimRight(domain, "/") + "/" + strings.TrimLeft(key, "/")
+	}
+	return s.scheme() + strings.TrimRight(domain, "/") + "/" + strings.TrimLeft(key, "/")
+}
+
+// UploadToken signs a one-hour upload policy scoped to bucket:key.
+func (s *QiniuStore) UploadToken(key string) (string, error) {
+	ak := strings.TrimSpace(s.AccessKey)
+	sk := strings.TrimSpace(s.SecretKey)
+	bk := strings.TrimSpace(s.Bucket)
+	key = strings.TrimSpace(key)
+	if ak == "" || sk == "" || bk == "" || key == "" {
+		return "", errors.New("qiniu credential or object key missing")
+	}
+	policy := map[string]interface{}{
+		"scope":    bk + ":" + key,
+		"deadline": time.Now().Add(1 * time.Hour).Unix(),
+	}
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	encodedPolicy := base64.URLEncoding.EncodeToString(policyJSON)
+	return ak + ":" + s.sign([]byte(encodedPolicy)) + ":" + encodedPolicy, nil
+}
+
+func (s *QiniuStore) sign(data []byte) string {
+	mac := hmac.New(sha1.New, []byte(strings.TrimSpace(s.SecretKey)))
+	_, _ = mac.Write(data)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *QiniuStore) Put(ctx context.Context, key string, payload []byte, contentType string) error {
+	if err := s.configured(); err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return errors.New("empty upload payload")
+	}
+	token, err := s.UploadToken(key)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("token", token); err != nil {
+		return err
+	}
+	if err := writer.WriteField("key", key); err != nil {
+		return err
+	}
+	part, err := writer.CreateFormFile("file", path.Base(key))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(payload); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.UploadHost(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if strings.TrimSpace(contentType) != "" {
+		req.Header.Set("X-Qiniu-MimeType", contentType)
+	}
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 16*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(respBytes))
+		if msg == "" {
+			msg = resp.Status
+		}
+		return fmt.Errorf("qiniu upload failed: %s", msg)
+	}
+	if len(respBytes) > 0 {
+		var result map[string]interface{}
+		if json.Unmarshal(respBytes, &result) == nil {
+			if errText, ok := result["error"].(string); ok && strings.TrimSpace(errText) != "" {
+				return fmt.Errorf("qiniu upload failed: %s", strings.TrimSpace(errText))
+			}
+		}
+	}
+	return nil
+}
+
+// PresignGet builds a private download URL: the deadline is appended as e=
+// and the whole URL is signed with the secret key.
+func (s *QiniuStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if err := s.configured(); err != nil {
+		return "", err
+	}
+	escaped := (&url.URL{Path: strings.TrimLeft(key, "/")}).EscapedPath()
+	raw := s.PublicURL(escaped)
+	separator := "?"
+	if strings.Contains(raw, "?") {
+		separator = "&"
+	}
+	raw += separator + "e=" + strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return raw + "&token=" + strings.TrimSpace(s.AccessKey) + ":" + s.sign([]byte(raw)), nil
+}
+
+func (s *QiniuStore) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	signed, err := s.PresignGet(ctx, key, 5*time.Minute)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signed, nil)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("qiniu download failed: %s", resp.Status)
+	}
+	return resp.Body, ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+	}, nil
+}
+
+func (s *QiniuStore) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	var stat struct {
+		Size     int64  `json:"fsize"`
+		Hash     string `json:"hash"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := s.manage(ctx, "stat", key, &stat); err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: stat.Size, ContentType: stat.MimeType, ETag: stat.Hash}, nil
+}
+
+func (s *QiniuStore) Delete(ctx context.Context, key string) error {
+	err := s.manage(ctx, "delete", key, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// manage calls a management API (stat, delete) on bucket:key, signed with
+// the QBox scheme over path and empty body.
+func (s *QiniuStore) manage(ctx context.Context, op string, key string, out interface{}) error {
+	if err := s.configured(); err != nil {
+		return err
+	}
+	entry := base64.URLEncoding.EncodeToString([]byte(strings.TrimSpace(s.Bucket) + ":" + key))
+	requestPath := "/" + op + "/" + entry
+	host := strings.TrimRight(strings.TrimSpace(s.RSHost), "/")
+	if host == "" {
+		host = s.scheme() + "rs.qiniuapi.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+requestPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "QBox "+strings.TrimSpace(s.AccessKey)+":"+s.sign([]byte(requestPath+"\n")))
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 16*1024))
+	if resp.StatusCode == qiniuNoSuchFile || resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(respBytes))
+		if msg == "" {
+			msg = resp.Status
+		}
+		return fmt.Errorf("qiniu %s failed: %s", op, msg)
+	}
+	if out != nil && len(respBytes) > 0 {
+		return json.Unmarshal(respBytes, out)
+	}
+	return nil
+}
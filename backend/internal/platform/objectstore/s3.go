@@ -0,0 +1,333 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Store talks to any S3-compatible service (AWS S3, MinIO, Ceph RGW...)
+// with hand-rolled Signature V4, so no SDK is needed. MinIO and most
+// self-hosted services want PathStyle.
+type S3Store struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	Client    *http.Client
+
+	now func() time.Time
+}
+
+const (
+	s3Algorithm        = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3MaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+func (s *S3Store) Provider() string {
+	return ProviderS3
+}
+
+func (s *S3Store) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
+
+func (s *S3Store) clock() time.Time {
+	if s.now != nil {
+		return s.now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *S3Store) region() string {
+	if region := strings.TrimSpace(s.Region); region != "" {
+		return region
+	}
+	return "us-east-1"
+}
+
+func (s *S3Store) configured() error {
+	if strings.TrimSpace(s.Endpoint) == "" || strings.TrimSpace(s.Bucket) == "" ||
+		strings.TrimSpace(s.AccessKey) == "" || strings.TrimSpace(s.SecretKey) == "" {
+		return errors.New("oss s3 config incomplete, require endpoint/bucket/access_key/secret_key")
+	}
+	return nil
+}
+
+// objectURL addresses key either as endpoint/bucket/key (path style) or
+// bucket.endpoint/key (virtual-hosted style).
+func (s *S3Store) objectURL(key string) (*url.URL, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	endpoint := strings.TrimSpace(s.Endpoint)
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	base, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	bucket := strings.TrimSpace(s.Bucket)
+	objectPath := "/" + key
+	if s.PathStyle {
+		objectPath = "/" + bucket + objectPath
+	} else {
+		base.Host = bucket + "." + base.Host
+	}
+	return &url.URL{Scheme: base.Scheme, Host: base.Host, Path: strings.TrimRight(base.Path, "/") + objectPath}, nil
+}
+
+// PublicURL is the unsigned object URL, only readable when the bucket
+// policy allows anonymous reads on the key.
+func (s *S3Store) PublicURL(key string) string {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return key
+	}
+	return objectURL.String()
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, payload []byte, contentType string) error {
+	headers := map[string]string{}
+	if strings.TrimSpace(contentType) != "" {
+		headers["content-type"] = contentType
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, payload, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return s.checkStatus(resp, "put")
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if err := s.checkStatus(resp, "get"); err != nil {
+		resp.Body.Close()
+		return nil, ObjectInfo{}, err
+	}
+	return resp.Body, s.objectInfo(key, resp), nil
+}
+
+func (s *S3Store) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer resp.Body.Close()
+	if err := s.checkStatus(resp, "head"); err != nil {
+		return ObjectInfo{}, err
+	}
+	return s.objectInfo(key, resp), nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := s.checkStatus(resp, "delete"); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// PresignGet signs a GET in the query string (X-Amz-* parameters) with only
+// the host header signed, valid for ttl (at most 7 days).
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if err := s.configured(); err != nil {
+		return "", err
+	}
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	if ttl > s3MaxPresignExpiry {
+		ttl = s3MaxPresignExpiry
+	}
+	now := s.clock()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+	query := map[string]string{
+		"X-Amz-Algorithm":     s3Algorithm,
+		"X-Amz-Credential":    strings.TrimSpace(s.AccessKey) + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.FormatInt(int64(ttl/time.Second), 10),
+		"X-Amz-SignedHeaders": "host",
+	}
+	canonicalQuery := s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		s3EscapePath(objectURL.Path),
+		canonicalQuery,
+		"host:" + objectURL.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+	return objectURL.Scheme + "://" + objectURL.Host + s3EscapePath(objectURL.Path) + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+func (s *S3Store) do(ctx context.Context, method string, key string, payload []byte, headers map[string]string) (*http.Response, error) {
+	if err := s.configured(); err != nil {
+		return nil, err
+	}
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.Scheme+"://"+objectURL.Host+s3EscapePath(objectURL.Path), body)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := sha256.Sum256(payload)
+	now := s.clock()
+	amzDate := now.Format("20060102T150405Z")
+	signed := map[string]string{
+		"host":                 objectURL.Host,
+		"x-amz-content-sha256": hex.EncodeToString(payloadHash[:]),
+		"x-amz-date":           amzDate,
+	}
+	for name, value := range headers {
+		signed[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+		if name != "host" {
+			req.Header.Set(name, signed[name])
+		}
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3EscapePath(objectURL.Path),
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		signed["x-amz-content-sha256"],
+	}, "\n")
+	scope := s.scope(now)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, strings.TrimSpace(s.AccessKey), scope, signedHeaders, s.signature(now, amzDate, scope, canonicalRequest)))
+	if payload != nil {
+		req.ContentLength = int64(len(payload))
+	}
+	return s.client().Do(req)
+}
+
+func (s *S3Store) checkStatus(resp *http.Response, op string) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := ""
+		if resp.Body != nil {
+			respBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+			msg = strings.TrimSpace(string(respBytes))
+		}
+		if msg == "" {
+			msg = resp.Status
+		}
+		return fmt.Errorf("s3 %s failed: %s", op, msg)
+	}
+	return nil
+}
+
+func (s *S3Store) objectInfo(key string, resp *http.Response) ObjectInfo {
+	return ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+	}
+}
+
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region() + "/s3/aws4_request"
+}
+
+func (s *S3Store) signature(now time.Time, amzDate string, scope string, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+	key := s3HMAC([]byte("AWS4"+strings.TrimSpace(s.SecretKey)), now.Format("20060102"))
+	key = s3HMAC(key, s.region())
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3CanonicalQuery(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, s3Escape(key, true)+"="+s3Escape(values[key], true))
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3EscapePath(value string) string {
+	return s3Escape(value, false)
+}
+
+// s3Escape percent-encodes everything but the RFC 3986 unreserved
+// characters, keeping "/" when encoding a path.
+func s3Escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9'),
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			builder.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return builder.String()
+}
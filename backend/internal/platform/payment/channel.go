@@ -0,0 +1,231 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupported       = errors.New("payment channel does not support this operation")
+	ErrRefundUnsupported = errors.New("payment channel does not support refunds")
+	ErrRefundFailed      = errors.New("channel refund failed")
+	ErrInvalidSignature  = errors.New("invalid payment callback signature")
+	ErrTradeNotFound     = errors.New("payment trade not found")
+	ErrAgreementInactive = errors.New("payment agreement is not active")
+	ErrAmountMismatch    = errors.New("payment callback amount does not match the order")
+	ErrCurrencyMismatch  = errors.New("payment callback currency does not match the order")
+)
+
+// CurrencyCNY is the only currency orders are priced in. Callbacks from
+// gateways that do not report a currency are taken to be in it.
+const CurrencyCNY = "CNY"
+
+const (
+	TradeStatusPending  = "PENDING"
+	TradeStatusPaid     = "PAID"
+	TradeStatusFailed   = "FAILED"
+	TradeStatusRefunded = "REFUNDED"
+)
+
+type PaymentRequest struct {
+	OrderNo  string
+	UserID   string
+	Subject  string
+	Amount   float64
+	ClientIP string
+}
+
+// PaymentAction is what the client needs to finish paying: a page to open,
+// a QR code to scan or an app scheme to launch, depending on the channel.
+type PaymentAction struct {
+	Channel   string      `json:"channel"`
+	PayURL    string      `json:"pay_url"`
+	QRCode    string      `json:"qrcode"`
+	URLScheme string      `json:"urlscheme"`
+	TradeNo   string      `json:"trade_no"`
+	Raw       interface{} `json:"raw"`
+}
+
+// Trade is the channel's view of one order, as returned by QueryOrder and
+// listed by DownloadStatement. IdempotencyKey matches the key the channel's
+// callback for the same payment carries, so settling a trade found by polling
+// and a late callback for it never grants twice.
+type Trade struct {
+	OrderNo        string
+	ChannelTxnNo   string
+	Status         string
+	Amount         float64
+	Currency       string
+	PaidAt         time.Time
+	IdempotencyKey string
+	Raw            string
+}
+
+// Matches applies the callback amount and currency check to a trade found by
+// polling, which settles an order just like a callback does.
+func (t Trade) Matches(amount float64, currency string) error {
+	return Callback{OrderNo: t.OrderNo, Amount: t.Amount, Currency: t.Currency}.Matches(amount, currency)
+}
+
+// Callback is a verified asynchronous notification. Only TradeStatusPaid
+// callbacks settle an order; the rest are acknowledged and dropped.
+type Callback struct {
+	OrderNo        string
+	ChannelTxnNo   string
+	Status         string
+	Amount         float64
+	Currency       string
+	IdempotencyKey string
+	Sign           string
+}
+
+// Matches reports whether the callback paid exactly amount in currency. A
+// valid signature only proves the gateway sent the callback, not that the
+// buyer paid what the order costs, so callers check this before settling.
+func (c Callback) Matches(amount float64, currency string) error {
+	if math.Round(c.Amount*100) != math.Round(amount*100) {
+		return fmt.Errorf("%w: order %s expects %.2f, callback paid %.2f", ErrAmountMismatch, c.OrderNo, amount, c.Amount)
+	}
+	if !strings.EqualFold(callbackCurrency(c.Currency), callbackCurrency(currency)) {
+		return fmt.Errorf("%w: order %s expects %s, callback paid %s", ErrCurrencyMismatch, c.OrderNo, callbackCurrency(currency), callbackCurrency(c.Currency))
+	}
+	return nil
+}
+
+func callbackCurrency(value string) string {
+	if value = strings.ToUpper(strings.TrimSpace(value)); value != "" {
+		return value
+	}
+	return CurrencyCNY
+}
+
+type RefundRequest struct {
+	OrderNo      string
+	ChannelTxnNo string
+	RefundNo     string
+	OrderAmount  float64
+	Amount       float64
+	Reason       string
+}
+
+type RefundResult struct {
+	ChannelRefundNo string
+	Raw             string
+}
+
+// Channel is a payment gateway as seen by the order flows. Refund must be
+// idempotent on RefundNo so a retried refund never pays out twice, and
+// ParseCallback must reject params whose signature does not verify.
+type Channel interface {
+	Name() string
+	CreatePayment(req PaymentRequest) (PaymentAction, error)
+	QueryOrder(orderNo string) (Trade, error)
+	ParseCallback(params map[string]string) (Callback, error)
+	Refund(req RefundRequest) (RefundResult, error)
+	DownloadStatement(day time.Time) ([]Trade, error)
+}
+
+// AgreementRequest asks the channel to sign a withholding agreement that lets
+// later renewals be charged without the buyer present.
+type AgreementRequest struct {
+	UserID    string
+	ProductID string
+	Subject   string
+}
+
+// AgreementCharge debits a signed agreement for one renewal order.
+type AgreementCharge struct {
+	AgreementNo string
+	OrderNo     string
+	UserID      string
+	Subject     string
+	Amount      float64
+}
+
+// AgreementCharger is implemented by channels that support auto-renewal.
+// ChargeAgreement returns the trade as the channel sees it right after the
+// debit, which may still be TradeStatusPending for asynchronous gateways.
+type AgreementCharger interface {
+	SignAgreement(req AgreementRequest) (string, error)
+	ChargeAgreement(req AgreementCharge) (Trade, error)
+	CancelAgreement(agreementNo string) error
+}
+
+// Resolver picks the channel an order was paid through. Handlers build it from
+// the payment.* system configs, tests hand in a StubChannel.
+type Resolver interface {
+	Channel(name string) (Channel, error)
+}
+
+type ResolverFunc func(name string) (Channel, error)
+
+func (f ResolverFunc) Channel(name string) (Channel, error) {
+	return f(name)
+}
+
+// refundOnly fills in the gateway operations for channels that can only
+// refund.
+type refundOnly struct{}
+
+func (refundOnly) CreatePayment(req PaymentRequest) (PaymentAction, error) {
+	return PaymentAction{}, ErrUnsupported
+}
+
+func (refundOnly) QueryOrder(orderNo string) (Trade, error) {
+	return Trade{}, ErrUnsupported
+}
+
+func (refundOnly) ParseCallback(params map[string]string) (Callback, error) {
+	return Callback{}, ErrUnsupported
+}
+
+func (refundOnly) DownloadStatement(day time.Time) ([]Trade, error) {
+	return nil, ErrUnsupported
+}
+
+// OfflineChannel records refunds that finance settles outside any gateway,
+// e.g. for ALIPAY/WECHAT/CARD orders that have no API integration.
+type OfflineChannel struct {
+	refundOnly
+}
+
+func (OfflineChannel) Name() string {
+	return "OFFLINE"
+}
+
+func (OfflineChannel) Refund(req RefundRequest) (RefundResult, error) {
+	return RefundResult{ChannelRefundNo: "OFFLINE-" + strings.TrimSpace(req.RefundNo)}, nil
+}
+
+// StubChannel is a local channel that refunds in memory and answers
+// QueryOrder from Trades. It keeps every refund request so tests can assert
+// what reached the gateway.
+type StubChannel struct {
+	refundOnly
+	Err      error
+	Requests []RefundRequest
+	Trades   map[string]Trade
+}
+
+func (s *StubChannel) Name() string {
+	return "STUB"
+}
+
+func (s *StubChannel) QueryOrder(orderNo string) (Trade, error) {
+	trade, ok := s.Trades[orderNo]
+	if !ok {
+		return Trade{}, fmt.Errorf("%w: %s", ErrTradeNotFound, orderNo)
+	}
+	return trade, nil
+}
+
+func (s *StubChannel) Refund(req RefundRequest) (RefundResult, error) {
+	s.Requests = append(s.Requests, req)
+	if s.Err != nil {
+		return RefundResult{}, s.Err
+	}
+	return RefundResult{ChannelRefundNo: fmt.Sprintf("STUB-%s-%d", req.RefundNo, time.Now().UnixNano())}, nil
+}
@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+)
+
+func signedYolkPayCallback(key string, money string) map[string]string {
+	params := map[string]string{
+		"pid":          "1001",
+		"out_trade_no": "mo_test_001",
+		"trade_no":     "YP0001",
+		"money":        money,
+		"trade_status": "TRADE_SUCCESS",
+		"sign_type":    "MD5",
+	}
+	params["sign"] = YolkPaySign(params, key)
+	return params
+}
+
+func TestYolkPayParseCallbackVerifiesSignature(t *testing.T) {
+	channel := NewYolkPayChannel(YolkPayConfig{PID: "1001", Key: "merchant-key"})
+
+	callback, err := channel.ParseCallback(signedYolkPayCallback("merchant-key", "99.00"))
+	if err != nil {
+		t.Fatalf("ParseCallback() error = %v", err)
+	}
+	if callback.Status != TradeStatusPaid || callback.Amount != 99 || callback.Currency != CurrencyCNY {
+		t.Fatalf("unexpected callback %+v", callback)
+	}
+
+	forged := signedYolkPayCallback("other-key", "99.00")
+	if _, err := channel.ParseCallback(forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for a foreign key, got %v", err)
+	}
+
+	tampered := signedYolkPayCallback("merchant-key", "99.00")
+	tampered["money"] = "0.01"
+	if _, err := channel.ParseCallback(tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for a tampered amount, got %v", err)
+	}
+}
+
+func TestSandboxParseCallbackOnlyAcceptsItsOwnLedger(t *testing.T) {
+	var delivered map[string]string
+	ledger := NewSandboxLedger()
+	channel := NewSandboxChannel(ledger, SandboxConfig{Notify: func(params map[string]string) { delivered = params }})
+	if _, err := channel.CreatePayment(PaymentRequest{OrderNo: "mo_test_002", Amount: 49.9}); err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	if delivered == nil {
+		t.Fatal("expected the sandbox to deliver a callback")
+	}
+
+	callback, err := channel.ParseCallback(delivered)
+	if err != nil {
+		t.Fatalf("ParseCallback() error = %v", err)
+	}
+	if err := callback.Matches(49.9, CurrencyCNY); err != nil {
+		t.Fatalf("expected the sandbox callback to match its order, got %v", err)
+	}
+
+	other := NewSandboxChannel(NewSandboxLedger(), SandboxConfig{})
+	if _, err := other.ParseCallback(delivered); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature from another ledger, got %v", err)
+	}
+}
+
+func TestCallbackMatchesRejectsAmountAndCurrencyMismatch(t *testing.T) {
+	callback := Callback{OrderNo: "mo_test_003", Amount: 0.01, Currency: "cny"}
+	if err := callback.Matches(99, CurrencyCNY); !errors.Is(err, ErrAmountMismatch) {
+		t.Fatalf("expected ErrAmountMismatch, got %v", err)
+	}
+
+	callback.Amount = 99.0000001
+	if err := callback.Matches(99, CurrencyCNY); err != nil {
+		t.Fatalf("expected amounts equal to the cent to match, got %v", err)
+	}
+
+	callback.Currency = "USD"
+	if err := callback.Matches(99, CurrencyCNY); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+
+	callback.Currency = ""
+	if err := callback.Matches(99, CurrencyCNY); err != nil {
+		t.Fatalf("expected a callback without currency to be taken as CNY, got %v", err)
+	}
+}
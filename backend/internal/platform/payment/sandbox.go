@@ -0,0 +1,285 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SandboxOutcomeSuccess = "SUCCESS"
+	SandboxOutcomeFailure = "FAILURE"
+	SandboxOutcomeDelayed = "DELAYED"
+)
+
+const sandboxDefaultDelay = 5 * time.Second
+
+// SandboxConfig decides how the simulated buyer behaves. Notify receives the
+// signed callback params the way a gateway would POST them to notify_url.
+type SandboxConfig struct {
+	Outcome string
+	Delay   time.Duration
+	Notify  func(params map[string]string)
+}
+
+// SandboxLedger keeps sandbox trades for the life of the process. Its key is
+// random per process, so only callbacks the sandbox itself produced verify.
+type SandboxLedger struct {
+	mu      sync.Mutex
+	key     []byte
+	seq     int
+	trades  map[string]*sandboxTrade
+	refunds map[string]RefundResult
+	// agreements maps agreement numbers to whether they are still signed.
+	agreements map[string]bool
+}
+
+type sandboxTrade struct {
+	Trade
+	Refunded float64
+}
+
+func NewSandboxLedger() *SandboxLedger {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		key = []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	}
+	return &SandboxLedger{key: key, trades: map[string]*sandboxTrade{}, refunds: map[string]RefundResult{}, agreements: map[string]bool{}}
+}
+
+// SandboxChannel simulates a gateway end to end so the order → callback →
+// VIP grant flow runs without real money. It must never be offered in
+// production.
+type SandboxChannel struct {
+	ledger *SandboxLedger
+	cfg    SandboxConfig
+}
+
+func NewSandboxChannel(ledger *SandboxLedger, cfg SandboxConfig) *SandboxChannel {
+	return &SandboxChannel{ledger: ledger, cfg: cfg}
+}
+
+func (s *SandboxChannel) Name() string {
+	return "SANDBOX"
+}
+
+// CreatePayment opens a pending trade and plays out the configured outcome:
+// SUCCESS and FAILURE notify before returning, DELAYED notifies success after
+// Delay on a timer.
+func (s *SandboxChannel) CreatePayment(req PaymentRequest) (PaymentAction, error) {
+	orderNo := strings.TrimSpace(req.OrderNo)
+	if orderNo == "" || req.Amount <= 0 {
+		return PaymentAction{}, errors.New("sandbox payment requires order_no and a positive amount")
+	}
+	outcome := strings.ToUpper(strings.TrimSpace(s.cfg.Outcome))
+	if outcome == "" {
+		outcome = SandboxOutcomeSuccess
+	}
+	if outcome != SandboxOutcomeSuccess && outcome != SandboxOutcomeFailure && outcome != SandboxOutcomeDelayed {
+		return PaymentAction{}, fmt.Errorf("unknown sandbox outcome %q", s.cfg.Outcome)
+	}
+	delay := s.cfg.Delay
+	if delay <= 0 {
+		delay = sandboxDefaultDelay
+	}
+
+	s.ledger.mu.Lock()
+	if existing, ok := s.ledger.trades[orderNo]; ok && existing.Status != TradeStatusFailed {
+		s.ledger.mu.Unlock()
+		return PaymentAction{}, fmt.Errorf("sandbox trade for %s already %s", orderNo, strings.ToLower(existing.Status))
+	}
+	s.ledger.seq++
+	tradeNo := fmt.Sprintf("SBX%s%04d", time.Now().Format("20060102150405"), s.ledger.seq)
+	s.ledger.trades[orderNo] = &sandboxTrade{Trade: Trade{
+		OrderNo:      orderNo,
+		ChannelTxnNo: tradeNo,
+		Status:       TradeStatusPending,
+		Amount:       req.Amount,
+	}}
+	s.ledger.mu.Unlock()
+
+	switch outcome {
+	case SandboxOutcomeSuccess:
+		s.settle(orderNo, TradeStatusPaid)
+	case SandboxOutcomeFailure:
+		s.settle(orderNo, TradeStatusFailed)
+	case SandboxOutcomeDelayed:
+		time.AfterFunc(delay, func() { s.settle(orderNo, TradeStatusPaid) })
+	}
+	raw := map[string]interface{}{"outcome": outcome}
+	if outcome == SandboxOutcomeDelayed {
+		raw["delay_seconds"] = delay.Seconds()
+	}
+	return PaymentAction{Channel: s.Name(), TradeNo: tradeNo, Raw: raw}, nil
+}
+
+func (s *SandboxChannel) QueryOrder(orderNo string) (Trade, error) {
+	s.ledger.mu.Lock()
+	defer s.ledger.mu.Unlock()
+	trade, ok := s.ledger.trades[strings.TrimSpace(orderNo)]
+	if !ok {
+		return Trade{}, fmt.Errorf("%w: %s", ErrTradeNotFound, orderNo)
+	}
+	item := trade.Trade
+	item.IdempotencyKey = sandboxIdempotencyKey(item.ChannelTxnNo, item.Status)
+	return item, nil
+}
+
+func (s *SandboxChannel) ParseCallback(params map[string]string) (Callback, error) {
+	sign := strings.TrimSpace(params["sign"])
+	if sign == "" || !hmac.Equal([]byte(strings.ToLower(sign)), []byte(s.ledger.sign(params))) {
+		return Callback{}, ErrInvalidSignature
+	}
+	tradeNo := strings.TrimSpace(params["trade_no"])
+	status := strings.ToUpper(strings.TrimSpace(params["trade_status"]))
+	amount, _ := strconv.ParseFloat(strings.TrimSpace(params["money"]), 64)
+	return Callback{
+		OrderNo:        strings.TrimSpace(params["out_trade_no"]),
+		ChannelTxnNo:   tradeNo,
+		Status:         status,
+		Amount:         amount,
+		Currency:       callbackCurrency(params["currency"]),
+		IdempotencyKey: sandboxIdempotencyKey(tradeNo, status),
+		Sign:           sign,
+	}, nil
+}
+
+// Refund accepts partial refunds up to the paid amount and is idempotent on
+// RefundNo.
+func (s *SandboxChannel) Refund(req RefundRequest) (RefundResult, error) {
+	s.ledger.mu.Lock()
+	defer s.ledger.mu.Unlock()
+	refundNo := strings.TrimSpace(req.RefundNo)
+	if result, ok := s.ledger.refunds[refundNo]; ok {
+		return result, nil
+	}
+	trade, ok := s.ledger.trades[strings.TrimSpace(req.OrderNo)]
+	if !ok {
+		return RefundResult{}, fmt.Errorf("%w: %s", ErrTradeNotFound, req.OrderNo)
+	}
+	if trade.Status != TradeStatusPaid {
+		return RefundResult{}, fmt.Errorf("sandbox trade %s is %s", trade.ChannelTxnNo, strings.ToLower(trade.Status))
+	}
+	if req.Amount <= 0 || req.Amount > trade.Amount-trade.Refunded+0.005 {
+		return RefundResult{}, fmt.Errorf("sandbox refund %.2f exceeds refundable %.2f", req.Amount, trade.Amount-trade.Refunded)
+	}
+	trade.Refunded += req.Amount
+	if trade.Amount-trade.Refunded < 0.005 {
+		trade.Status = TradeStatusRefunded
+	}
+	result := RefundResult{ChannelRefundNo: "SBXR-" + refundNo}
+	s.ledger.refunds[refundNo] = result
+	return result, nil
+}
+
+// DownloadStatement lists trades paid on day, including ones refunded since.
+func (s *SandboxChannel) DownloadStatement(day time.Time) ([]Trade, error) {
+	target := day.Format("2006-01-02")
+	s.ledger.mu.Lock()
+	items := make([]Trade, 0)
+	for _, trade := range s.ledger.trades {
+		if !trade.PaidAt.IsZero() && trade.PaidAt.Format("2006-01-02") == target {
+			items = append(items, trade.Trade)
+		}
+	}
+	s.ledger.mu.Unlock()
+	sort.Slice(items, func(i, j int) bool { return items[i].PaidAt.Before(items[j].PaidAt) })
+	return items, nil
+}
+
+// SignAgreement always succeeds; the sandbox buyer consents to everything.
+func (s *SandboxChannel) SignAgreement(req AgreementRequest) (string, error) {
+	if strings.TrimSpace(req.UserID) == "" {
+		return "", errors.New("sandbox agreement requires user_id")
+	}
+	s.ledger.mu.Lock()
+	defer s.ledger.mu.Unlock()
+	s.ledger.seq++
+	agreementNo := fmt.Sprintf("SBXA%s%04d", time.Now().Format("20060102150405"), s.ledger.seq)
+	s.ledger.agreements[agreementNo] = true
+	return agreementNo, nil
+}
+
+// ChargeAgreement debits a signed agreement with the configured outcome, the
+// same way CreatePayment plays it out for a buyer at the cashier.
+func (s *SandboxChannel) ChargeAgreement(req AgreementCharge) (Trade, error) {
+	s.ledger.mu.Lock()
+	active := s.ledger.agreements[strings.TrimSpace(req.AgreementNo)]
+	s.ledger.mu.Unlock()
+	if !active {
+		return Trade{}, fmt.Errorf("%w: %s", ErrAgreementInactive, req.AgreementNo)
+	}
+	if _, err := s.CreatePayment(PaymentRequest{OrderNo: req.OrderNo, UserID: req.UserID, Subject: req.Subject, Amount: req.Amount}); err != nil {
+		return Trade{}, err
+	}
+	return s.QueryOrder(req.OrderNo)
+}
+
+func (s *SandboxChannel) CancelAgreement(agreementNo string) error {
+	s.ledger.mu.Lock()
+	defer s.ledger.mu.Unlock()
+	agreementNo = strings.TrimSpace(agreementNo)
+	if _, ok := s.ledger.agreements[agreementNo]; !ok {
+		return fmt.Errorf("%w: %s", ErrAgreementInactive, agreementNo)
+	}
+	s.ledger.agreements[agreementNo] = false
+	return nil
+}
+
+// settle moves a pending trade to status and delivers the signed callback.
+func (s *SandboxChannel) settle(orderNo string, status string) {
+	s.ledger.mu.Lock()
+	trade, ok := s.ledger.trades[orderNo]
+	if !ok || trade.Status != TradeStatusPending {
+		s.ledger.mu.Unlock()
+		return
+	}
+	trade.Status = status
+	if status == TradeStatusPaid {
+		trade.PaidAt = time.Now()
+	}
+	params := map[string]string{
+		"out_trade_no": trade.OrderNo,
+		"trade_no":     trade.ChannelTxnNo,
+		"money":        fmt.Sprintf("%.2f", trade.Amount),
+		"currency":     CurrencyCNY,
+		"trade_status": status,
+		"timestamp":    strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	params["sign"] = s.ledger.sign(params)
+	s.ledger.mu.Unlock()
+
+	if s.cfg.Notify != nil {
+		s.cfg.Notify(params)
+	}
+}
+
+func sandboxIdempotencyKey(tradeNo string, status string) string {
+	return "sandbox:" + tradeNo + ":" + status
+}
+
+func (l *SandboxLedger) sign(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key == "sign" || strings.TrimSpace(params[key]) == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+strings.TrimSpace(params[key]))
+	}
+	mac := hmac.New(sha256.New, l.key)
+	_, _ = mac.Write([]byte(strings.Join(parts, "&")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
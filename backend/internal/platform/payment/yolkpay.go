@@ -0,0 +1,394 @@
+package payment
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const yolkPayStatementPageSize = 50
+const yolkPayStatementMaxPages = 20
+
+type YolkPayConfig struct {
+	PID       string
+	Key       string
+	Gateway   string
+	MAPIPath  string
+	NotifyURL string
+	ReturnURL string
+	PayType   string
+	Device    string
+}
+
+type YolkPayChannel struct {
+	cfg    YolkPayConfig
+	client *http.Client
+}
+
+func NewYolkPayChannel(cfg YolkPayConfig) *YolkPayChannel {
+	return &YolkPayChannel{cfg: cfg, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (y *YolkPayChannel) Name() string {
+	return "YOLKPAY"
+}
+
+// CreatePayment places the order through the merchant API (mapi.php) and
+// returns whichever of pay_url/qrcode/urlscheme the gateway handed back.
+func (y *YolkPayChannel) CreatePayment(req PaymentRequest) (PaymentAction, error) {
+	if err := y.requireCredentials(); err != nil {
+		return PaymentAction{}, err
+	}
+	clientIP := strings.TrimSpace(req.ClientIP)
+	if clientIP == "" || clientIP == "::1" || clientIP == "0:0:0:0:0:0:0:1" {
+		clientIP = "127.0.0.1"
+	}
+	params := map[string]string{
+		"pid":          strings.TrimSpace(y.cfg.PID),
+		"type":         strings.TrimSpace(y.cfg.PayType),
+		"out_trade_no": strings.TrimSpace(req.OrderNo),
+		"notify_url":   strings.TrimSpace(y.cfg.NotifyURL),
+		"return_url":   strings.TrimSpace(y.cfg.ReturnURL),
+		"name":         strings.TrimSpace(req.Subject),
+		"money":        fmt.Sprintf("%.2f", req.Amount),
+		"clientip":     clientIP,
+		"device":       strings.TrimSpace(y.cfg.Device),
+		"param":        strings.TrimSpace(req.UserID),
+	}
+	params["sign"] = YolkPaySign(params, y.cfg.Key)
+	params["sign_type"] = "MD5"
+
+	form := url.Values{}
+	for key, value := range params {
+		form.Set(key, value)
+	}
+	mapiPath := strings.TrimSpace(y.cfg.MAPIPath)
+	if mapiPath == "" {
+		mapiPath = "/mapi.php"
+	}
+	if !strings.HasPrefix(mapiPath, "/") {
+		mapiPath = "/" + mapiPath
+	}
+	payload, err := y.post(yolkPayBaseURL(y.cfg.Gateway)+mapiPath, form)
+	if err != nil {
+		return PaymentAction{}, err
+	}
+	if code := yolkPayCode(payload["code"]); code != 1 {
+		msg := yolkPayString(payload["msg"])
+		if msg == "" {
+			msg = "下单失败"
+		}
+		return PaymentAction{}, fmt.Errorf("蛋黄支付下单失败: %s", msg)
+	}
+	return PaymentAction{
+		Channel:   y.Name(),
+		PayURL:    yolkPayString(payload["payurl"]),
+		QRCode:    yolkPayString(payload["qrcode"]),
+		URLScheme: yolkPayString(payload["urlscheme"]),
+		TradeNo:   yolkPayString(payload["trade_no"]),
+		Raw:       payload,
+	}, nil
+}
+
+// QueryOrder looks the order up through api.php?act=order. The gateway
+// reports status 1 once the buyer has paid.
+func (y *YolkPayChannel) QueryOrder(orderNo string) (Trade, error) {
+	if err := y.requireCredentials(); err != nil {
+		return Trade{}, err
+	}
+	values := y.apiValues("order")
+	values.Set("out_trade_no", strings.TrimSpace(orderNo))
+	payload, err := y.get(values)
+	if err != nil {
+		return Trade{}, err
+	}
+	if yolkPayCode(payload["code"]) != 1 {
+		msg := yolkPayString(payload["msg"])
+		if msg == "" {
+			msg = strings.TrimSpace(orderNo)
+		}
+		return Trade{}, fmt.Errorf("%w: %s", ErrTradeNotFound, msg)
+	}
+	return yolkPayTrade(payload), nil
+}
+
+// ParseCallback verifies the MD5 signature of a notify request. Anything
+// other than TRADE_SUCCESS is reported as pending.
+func (y *YolkPayChannel) ParseCallback(params map[string]string) (Callback, error) {
+	if strings.TrimSpace(y.cfg.Key) == "" {
+		return Callback{}, errors.New("payment.channel.yolkpay.key 未配置")
+	}
+	sign := strings.TrimSpace(params["sign"])
+	if sign == "" || !strings.EqualFold(sign, YolkPaySign(params, y.cfg.Key)) {
+		return Callback{}, ErrInvalidSignature
+	}
+	orderNo := strings.TrimSpace(params["out_trade_no"])
+	if orderNo == "" {
+		return Callback{}, errors.New("蛋黄支付回调缺少 out_trade_no")
+	}
+	tradeNo := strings.TrimSpace(params["trade_no"])
+	status := TradeStatusPending
+	if strings.EqualFold(strings.TrimSpace(params["trade_status"]), "TRADE_SUCCESS") {
+		status = TradeStatusPaid
+	}
+	amount, _ := strconv.ParseFloat(strings.TrimSpace(params["money"]), 64)
+	idempotencyKey := "yolkpay:" + orderNo
+	if tradeNo != "" {
+		idempotencyKey = "yolkpay:" + tradeNo
+	}
+	return Callback{
+		OrderNo:        orderNo,
+		ChannelTxnNo:   tradeNo,
+		Status:         status,
+		Amount:         amount,
+		Currency:       callbackCurrency(params["currency"]),
+		IdempotencyKey: idempotencyKey,
+		Sign:           sign,
+	}, nil
+}
+
+// Refund calls the merchant refund API (api.php?act=refund). The gateway
+// authenticates it with the merchant key itself rather than a signature.
+func (y *YolkPayChannel) Refund(req RefundRequest) (RefundResult, error) {
+	if err := y.requireCredentials(); err != nil {
+		return RefundResult{}, err
+	}
+	form := url.Values{}
+	form.Set("pid", strings.TrimSpace(y.cfg.PID))
+	form.Set("key", strings.TrimSpace(y.cfg.Key))
+	form.Set("out_trade_no", strings.TrimSpace(req.OrderNo))
+	if txnNo := strings.TrimSpace(req.ChannelTxnNo); txnNo != "" {
+		form.Set("trade_no", txnNo)
+	}
+	form.Set("money", fmt.Sprintf("%.2f", req.Amount))
+
+	httpReq, err := http.NewRequest(http.MethodPost, yolkPayBaseURL(y.cfg.Gateway)+"/api.php?act=refund", strings.NewReader(form.Encode()))
+	if err != nil {
+		return RefundResult{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := y.client.Do(httpReq)
+	if err != nil {
+		return RefundResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 128*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return RefundResult{}, fmt.Errorf("蛋黄支付退款网关返回异常: %s", resp.Status)
+	}
+	var payload struct {
+		Code interface{} `json:"code"`
+		Msg  string      `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return RefundResult{}, fmt.Errorf("蛋黄支付退款返回解析失败: %w", err)
+	}
+	if yolkPayCode(payload.Code) != 1 {
+		msg := strings.TrimSpace(payload.Msg)
+		if msg == "" {
+			msg = "退款失败"
+		}
+		return RefundResult{}, fmt.Errorf("蛋黄支付退款失败: %s", msg)
+	}
+	return RefundResult{ChannelRefundNo: strings.TrimSpace(req.RefundNo), Raw: string(body)}, nil
+}
+
+// DownloadStatement pages through api.php?act=orders and keeps the orders
+// paid on day. The gateway has no date filter, so at most
+// yolkPayStatementMaxPages pages are scanned.
+func (y *YolkPayChannel) DownloadStatement(day time.Time) ([]Trade, error) {
+	if err := y.requireCredentials(); err != nil {
+		return nil, err
+	}
+	target := day.Format("2006-01-02")
+	items := make([]Trade, 0)
+	for page := 1; page <= yolkPayStatementMaxPages; page++ {
+		values := y.apiValues("orders")
+		values.Set("limit", strconv.Itoa(yolkPayStatementPageSize))
+		values.Set("page", strconv.Itoa(page))
+		payload, err := y.get(values)
+		if err != nil {
+			return nil, err
+		}
+		if yolkPayCode(payload["code"]) != 1 {
+			return nil, fmt.Errorf("蛋黄支付对账单下载失败: %s", yolkPayString(payload["msg"]))
+		}
+		rows, _ := payload["data"].([]interface{})
+		for _, row := range rows {
+			record, ok := row.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			trade := yolkPayTrade(record)
+			if trade.Status == TradeStatusPaid && trade.PaidAt.Format("2006-01-02") == target {
+				items = append(items, trade)
+			}
+		}
+		if len(rows) < yolkPayStatementPageSize {
+			break
+		}
+	}
+	return items, nil
+}
+
+// QueryMerchant fetches the merchant profile (api.php?act=query); it is the
+// cheapest call that proves pid and key are valid.
+func (y *YolkPayChannel) QueryMerchant() (map[string]interface{}, error) {
+	if err := y.requireCredentials(); err != nil {
+		return nil, err
+	}
+	return y.get(y.apiValues("query"))
+}
+
+func (y *YolkPayChannel) requireCredentials() error {
+	if strings.TrimSpace(y.cfg.PID) == "" || strings.TrimSpace(y.cfg.Key) == "" {
+		return errors.New("payment.channel.yolkpay.pid 或 key 未配置")
+	}
+	return nil
+}
+
+func (y *YolkPayChannel) apiValues(act string) url.Values {
+	values := url.Values{}
+	values.Set("act", act)
+	values.Set("pid", strings.TrimSpace(y.cfg.PID))
+	values.Set("key", strings.TrimSpace(y.cfg.Key))
+	return values
+}
+
+func (y *YolkPayChannel) get(values url.Values) (map[string]interface{}, error) {
+	httpReq, err := http.NewRequest(http.MethodGet, yolkPayBaseURL(y.cfg.Gateway)+"/api.php?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return y.do(httpReq)
+}
+
+func (y *YolkPayChannel) post(endpoint string, form url.Values) (map[string]interface{}, error) {
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return y.do(httpReq)
+}
+
+func (y *YolkPayChannel) do(httpReq *http.Request) (map[string]interface{}, error) {
+	resp, err := y.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = resp.Status
+		}
+		return nil, fmt.Errorf("蛋黄支付网关返回异常: %s", msg)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("蛋黄支付返回解析失败: %w", err)
+	}
+	return payload, nil
+}
+
+// YolkPaySign is the gateway's MD5 signature: non-empty params except
+// sign/sign_type, sorted by key, joined as k=v&..., then the merchant key.
+func YolkPaySign(params map[string]string, merchantKey string) string {
+	filtered := make([]string, 0, len(params))
+	values := make(map[string]string, len(params))
+	for rawKey, rawValue := range params {
+		key := strings.TrimSpace(rawKey)
+		if key == "" {
+			continue
+		}
+		lowerKey := strings.ToLower(key)
+		if lowerKey == "sign" || lowerKey == "sign_type" {
+			continue
+		}
+		value := strings.TrimSpace(rawValue)
+		if value == "" {
+			continue
+		}
+		filtered = append(filtered, key)
+		values[key] = value
+	}
+	sort.Strings(filtered)
+	parts := make([]string, 0, len(filtered))
+	for _, key := range filtered {
+		parts = append(parts, key+"="+values[key])
+	}
+	hash := md5.Sum([]byte(strings.Join(parts, "&") + strings.TrimSpace(merchantKey)))
+	return strings.ToLower(hex.EncodeToString(hash[:]))
+}
+
+func yolkPayTrade(record map[string]interface{}) Trade {
+	trade := Trade{
+		OrderNo:      yolkPayString(record["out_trade_no"]),
+		ChannelTxnNo: yolkPayString(record["trade_no"]),
+		Status:       TradeStatusPending,
+	}
+	trade.IdempotencyKey = "yolkpay:" + trade.OrderNo
+	if trade.ChannelTxnNo != "" {
+		trade.IdempotencyKey = "yolkpay:" + trade.ChannelTxnNo
+	}
+	trade.Amount, _ = strconv.ParseFloat(yolkPayString(record["money"]), 64)
+	trade.Currency = callbackCurrency(yolkPayString(record["currency"]))
+	if yolkPayCode(record["status"]) == 1 {
+		trade.Status = TradeStatusPaid
+		if paidAt, err := time.ParseInLocation("2006-01-02 15:04:05", yolkPayString(record["endtime"]), time.Local); err == nil {
+			trade.PaidAt = paidAt
+		}
+	}
+	if raw, err := json.Marshal(record); err == nil {
+		trade.Raw = string(raw)
+	}
+	return trade
+}
+
+func yolkPayCode(raw interface{}) int {
+	switch v := raw.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	}
+	return 0
+}
+
+func yolkPayString(raw interface{}) string {
+	if raw == nil {
+		return ""
+	}
+	if text, ok := raw.(string); ok {
+		return strings.TrimSpace(text)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", raw))
+}
+
+func yolkPayBaseURL(gateway string) string {
+	base := strings.TrimSpace(gateway)
+	if base == "" {
+		base = "https://www.yolkpay.net"
+	}
+	lower := strings.ToLower(base)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		base = "https://" + base
+	}
+	return strings.TrimRight(base, "/")
+}
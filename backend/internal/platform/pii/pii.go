@@ -0,0 +1,95 @@
+// Package pii masks personal data — phone numbers, email addresses and IP
+// addresses — before it reaches admin responses, exports and audit payloads.
+// Every mask is idempotent, so masking an already masked value is safe.
+package pii
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	ipv4Pattern  = regexp.MustCompile(`\b(\d{1,3})\.(\d{1,3})\.\d{1,3}\.\d{1,3}\b`)
+	digitsRun    = regexp.MustCompile(`[0-9]+`)
+)
+
+// MaskPhone keeps the first three and last four digits of a phone number:
+// 13800138000 becomes 138****8000. Shorter values are masked entirely.
+func MaskPhone(value string) string {
+	value = strings.TrimSpace(value)
+	runes := []rune(value)
+	if len(runes) == 0 {
+		return ""
+	}
+	if len(runes) < 7 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:3]) + "****" + string(runes[len(runes)-4:])
+}
+
+// MaskEmail keeps the first character of the local part and the domain:
+// zhangsan@example.com becomes z***@example.com.
+func MaskEmail(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	at := strings.LastIndex(value, "@")
+	if at <= 0 {
+		return "***"
+	}
+	local := []rune(value[:at])
+	return string(local[0]) + "***" + value[at:]
+}
+
+// MaskIP keeps the network half of an address: 203.0.113.45 becomes
+// 203.0.*.* and 2001:db8::1 becomes 2001:db8:*. A port is dropped, and a
+// value that is not an address is masked entirely.
+func MaskIP(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		if strings.Contains(value, "*") {
+			return value
+		}
+		return "***"
+	}
+	if v4 := ip.To4(); v4 != nil {
+		parts := strings.Split(v4.String(), ".")
+		return parts[0] + "." + parts[1] + ".*.*"
+	}
+	groups := strings.Split(ip.String(), ":")
+	kept := make([]string, 0, 2)
+	for _, group := range groups {
+		if group == "" || len(kept) == 2 {
+			break
+		}
+		kept = append(kept, group)
+	}
+	return strings.Join(append(kept, "*"), ":")
+}
+
+// MaskText masks email addresses, IPv4 addresses and mainland mobile
+// numbers found anywhere in free text, such as operation log values and
+// audit event summaries.
+func MaskText(value string) string {
+	if value == "" {
+		return value
+	}
+	value = emailPattern.ReplaceAllStringFunc(value, MaskEmail)
+	value = ipv4Pattern.ReplaceAllString(value, "$1.$2.*.*")
+	return digitsRun.ReplaceAllStringFunc(value, func(digits string) string {
+		if len(digits) == 11 && digits[0] == '1' && digits[1] >= '3' {
+			return MaskPhone(digits)
+		}
+		return digits
+	})
+}
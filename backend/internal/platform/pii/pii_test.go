@@ -0,0 +1,43 @@
+package pii
+
+import "testing"
+
+func TestMaskFields(t *testing.T) {
+	cases := []struct {
+		name string
+		mask func(string) string
+		in   string
+		want string
+	}{
+		{"phone", MaskPhone, "13800138000", "138****8000"},
+		{"phone with country code", MaskPhone, "+8613800138000", "+86****8000"},
+		{"short phone", MaskPhone, "12345", "*****"},
+		{"empty phone", MaskPhone, "", ""},
+		{"email", MaskEmail, "zhangsan@example.com", "z***@example.com"},
+		{"not an email", MaskEmail, "zhangsan", "***"},
+		{"ipv4", MaskIP, "203.0.113.45", "203.0.*.*"},
+		{"ipv4 with port", MaskIP, "203.0.113.45:52100", "203.0.*.*"},
+		{"ipv6", MaskIP, "2001:db8::1", "2001:db8:*"},
+		{"not an ip", MaskIP, "unknown", "***"},
+	}
+	for _, tc := range cases {
+		got := tc.mask(tc.in)
+		if got != tc.want {
+			t.Fatalf("%s: mask(%q) = %q, want %q", tc.name, tc.in, got, tc.want)
+		}
+		if again := tc.mask(got); again != got {
+			t.Fatalf("%s: masking is not idempotent: %q then %q", tc.name, got, again)
+		}
+	}
+}
+
+func TestMaskTextLeavesOtherNumbersAlone(t *testing.T) {
+	in := "user u_1001 phone=13800138000 email=li.si@example.com ip=10.2.3.4 order 20260330123 amount 199"
+	want := "user u_1001 phone=138****8000 email=l***@example.com ip=10.2.*.* order 20260330123 amount 199"
+	if got := MaskText(in); got != want {
+		t.Fatalf("MaskText() = %q, want %q", got, want)
+	}
+	if got := MaskText(want); got != want {
+		t.Fatalf("MaskText() is not idempotent: %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const channelPrefix = "realtime:"
+
+// Hub fans out "something new for this topic" pings to live streams. Pings
+// carry no payload: streams re-read rows after their cursor from the
+// database, so a ping published before a transaction commits, or one that is
+// lost, only delays delivery until the stream's next poll.
+//
+// With Redis the pings travel over pub/sub and reach streams on every
+// replica. Without Redis they only reach streams subscribed to this Hub, and
+// streams fall back to polling at a shorter interval. The repo that writes
+// messages owns the Hub and handlers subscribe to that same instance.
+type Hub struct {
+	redis *redis.Client
+	local *localBus
+}
+
+// NewHub accepts a nil client, which selects the in-process fanout.
+func NewHub(redisClient *redis.Client) *Hub {
+	return &Hub{redis: redisClient, local: &localBus{subs: map[string]map[chan struct{}]struct{}{}}}
+}
+
+// Distributed reports whether pings reach other replicas.
+func (h *Hub) Distributed() bool {
+	return h != nil && h.redis != nil
+}
+
+func UserTopic(userID string) string {
+	return "user:" + strings.TrimSpace(userID)
+}
+
+// AdminTopic addresses one admin; AdminBroadcastTopic reaches every admin
+// stream, for workflow messages without a receiver.
+func AdminTopic(adminID string) string {
+	return "admin:" + strings.TrimSpace(adminID)
+}
+
+const AdminBroadcastTopic = "admin:*"
+
+// Notify pings every stream subscribed to topic. Errors are logged and
+// swallowed; the write that triggered the ping has already succeeded.
+func (h *Hub) Notify(topic string) {
+	if h == nil || strings.TrimSpace(topic) == "" {
+		return
+	}
+	if h.redis != nil {
+		if err := h.redis.Publish(context.Background(), channelPrefix+topic, "1").Err(); err != nil {
+			log.Printf("realtime publish %s failed: %v", topic, err)
+		}
+		return
+	}
+	h.local.notify(topic)
+}
+
+// Subscribe returns a channel that receives a value whenever one of topics
+// is notified. Bursts coalesce into a single pending ping. The subscription
+// ends when ctx is done.
+func (h *Hub) Subscribe(ctx context.Context, topics ...string) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	if h == nil {
+		return out
+	}
+	if h.redis == nil {
+		h.local.subscribe(ctx, topics, out)
+		return out
+	}
+	channels := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		channels = append(channels, channelPrefix+topic)
+	}
+	pubsub := h.redis.Subscribe(ctx, channels...)
+	go func() {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+				signal(out)
+			}
+		}
+	}()
+	return out
+}
+
+func signal(out chan struct{}) {
+	select {
+	case out <- struct{}{}:
+	default:
+	}
+}
+
+type localBus struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func (b *localBus) notify(topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for out := range b.subs[topic] {
+		signal(out)
+	}
+}
+
+func (b *localBus) subscribe(ctx context.Context, topics []string, out chan struct{}) {
+	b.mu.Lock()
+	for _, topic := range topics {
+		if b.subs[topic] == nil {
+			b.subs[topic] = map[chan struct{}]struct{}{}
+		}
+		b.subs[topic][out] = struct{}{}
+	}
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for _, topic := range topics {
+			delete(b.subs[topic], out)
+			if len(b.subs[topic]) == 0 {
+				delete(b.subs, topic)
+			}
+		}
+	}()
+}
@@ -0,0 +1,95 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func waitPing(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("expected a ping")
+	}
+}
+
+func expectNoPing(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatal("expected no ping")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestLocalHubPingsSubscribedTopicsOnly(t *testing.T) {
+	hub := NewHub(nil)
+	if hub.Distributed() {
+		t.Fatal("expected a hub without redis to be local")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wake := hub.Subscribe(ctx, AdminTopic("admin_001"), AdminBroadcastTopic)
+
+	hub.Notify(AdminTopic("admin_002"))
+	expectNoPing(t, wake)
+	hub.Notify(AdminBroadcastTopic)
+	waitPing(t, wake)
+	hub.Notify(AdminTopic(" admin_001 "))
+	waitPing(t, wake)
+}
+
+func TestLocalHubCoalescesBursts(t *testing.T) {
+	hub := NewHub(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wake := hub.Subscribe(ctx, UserTopic("u_001"))
+
+	for i := 0; i < 5; i++ {
+		hub.Notify(UserTopic("u_001"))
+	}
+	waitPing(t, wake)
+	expectNoPing(t, wake)
+}
+
+func TestLocalHubsDoNotShareSubscribers(t *testing.T) {
+	writer, reader := NewHub(nil), NewHub(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wake := reader.Subscribe(ctx, UserTopic("u_001"))
+
+	writer.Notify(UserTopic("u_001"))
+	expectNoPing(t, wake)
+}
+
+func TestLocalHubDropsSubscriptionWhenContextEnds(t *testing.T) {
+	hub := NewHub(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	hub.Subscribe(ctx, UserTopic("u_001"))
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		hub.local.mu.Lock()
+		remaining := len(hub.local.subs)
+		hub.local.mu.Unlock()
+		if remaining == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the subscription to be removed, %d topics left", remaining)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNilHubIsANoOp(t *testing.T) {
+	var hub *Hub
+	hub.Notify(UserTopic("u_001"))
+	if hub.Distributed() {
+		t.Fatal("expected a nil hub to be local")
+	}
+	expectNoPing(t, hub.Subscribe(context.Background(), UserTopic("u_001")))
+}
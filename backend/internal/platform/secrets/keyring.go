@@ -0,0 +1,305 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"sercherai/backend/internal/platform/config"
+)
+
+// Sealed values look like enc:v2:<key_id>:<wrapped_dek>:<ciphertext>. Each value
+// gets its own random data key (DEK) which is encrypted by a master key, so a
+// master key rotation only needs the stored values re-sealed, never a re-key of
+// every consumer. v2 binds the name the value is stored under (the config key)
+// into the AEAD additional data, so a sealed value copied to another key does
+// not open there. v1 values predate that binding; they still open and are
+// upgraded by Rotate.
+const (
+	sealedPrefix       = "enc:v2:"
+	legacySealedPrefix = "enc:v1:"
+)
+
+const dataKeySize = 32
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+var ErrKeyringUnavailable = errors.New("config master key is not configured")
+
+type Keyring struct {
+	primaryID string
+	keys      map[string][]byte
+	loadErr   error
+}
+
+// Load builds the keyring from CONFIG_MASTER_KEYS, falling back to the file at
+// CONFIG_MASTER_KEY_FILE. A keyring that failed to load refuses to seal or open
+// anything, so a broken key never degrades into plaintext writes. In production
+// a missing master key counts as a failed load.
+func Load(cfg config.Config) (*Keyring, error) {
+	spec := strings.TrimSpace(cfg.ConfigMasterKeys)
+	if spec == "" && strings.TrimSpace(cfg.ConfigMasterKeyFile) != "" {
+		body, err := os.ReadFile(strings.TrimSpace(cfg.ConfigMasterKeyFile))
+		if err != nil {
+			err = fmt.Errorf("read config master key file: %w", err)
+			return &Keyring{loadErr: err}, err
+		}
+		spec = string(body)
+	}
+	keyring, err := ParseKeyring(spec)
+	if err != nil {
+		return &Keyring{loadErr: err}, err
+	}
+	if keyring.primaryID == "" && strings.EqualFold(strings.TrimSpace(cfg.AppEnv), "production") {
+		return &Keyring{loadErr: ErrKeyringUnavailable}, ErrKeyringUnavailable
+	}
+	return keyring, nil
+}
+
+// ParseKeyring accepts "id:key" entries separated by commas, semicolons or
+// newlines. The first entry is the primary key used for new values; the rest
+// stay available for opening values sealed before a rotation. Keys are 32
+// bytes encoded as base64 or hex.
+func ParseKeyring(spec string) (*Keyring, error) {
+	keyring := &Keyring{keys: make(map[string][]byte)}
+	entries := strings.FieldsFunc(spec, func(ch rune) bool {
+		return ch == ',' || ch == ';' || ch == '\n' || ch == '\r'
+	})
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		keyID, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			keyID, encoded = "default", entry
+		}
+		keyID = strings.TrimSpace(keyID)
+		if !keyIDPattern.MatchString(keyID) {
+			return nil, fmt.Errorf("invalid config master key id %q", keyID)
+		}
+		if _, exists := keyring.keys[keyID]; exists {
+			return nil, fmt.Errorf("duplicate config master key id %q", keyID)
+		}
+		key, err := decodeMasterKey(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("config master key %q: %w", keyID, err)
+		}
+		keyring.keys[keyID] = key
+		if keyring.primaryID == "" {
+			keyring.primaryID = keyID
+		}
+	}
+	return keyring, nil
+}
+
+func decodeMasterKey(encoded string) ([]byte, error) {
+	if len(encoded) == hex.EncodedLen(dataKeySize) {
+		if key, err := hex.DecodeString(encoded); err == nil {
+			return key, nil
+		}
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := encoding.DecodeString(encoded); err == nil {
+			if len(key) != dataKeySize {
+				return nil, fmt.Errorf("expected %d bytes, got %d", dataKeySize, len(key))
+			}
+			return key, nil
+		}
+	}
+	return nil, errors.New("key must be base64 or hex encoded")
+}
+
+// Enabled reports whether new values will be sealed. Without a master key the
+// keyring passes plaintext through so local setups keep working.
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.loadErr == nil && k.primaryID != ""
+}
+
+func (k *Keyring) PrimaryKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.primaryID
+}
+
+func IsSealed(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, sealedPrefix) || strings.HasPrefix(value, legacySealedPrefix)
+}
+
+// sealedParts splits a sealed value into key id, wrapped key and ciphertext,
+// reporting whether it uses the legacy v1 envelope.
+func sealedParts(value string) ([]string, bool, bool) {
+	value = strings.TrimSpace(value)
+	legacy := strings.HasPrefix(value, legacySealedPrefix)
+	if !legacy && !strings.HasPrefix(value, sealedPrefix) {
+		return nil, false, false
+	}
+	parts := strings.Split(value[len(sealedPrefix):], ":")
+	if len(parts) != 3 {
+		return nil, legacy, false
+	}
+	return parts, legacy, true
+}
+
+// SealedKeyID returns the master key id a sealed value was wrapped with.
+func SealedKeyID(value string) string {
+	parts, _, ok := sealedParts(value)
+	if !ok {
+		return ""
+	}
+	return parts[0]
+}
+
+func sealedAAD(keyID string, name string, legacy bool) []byte {
+	if legacy {
+		return []byte(keyID)
+	}
+	return []byte(keyID + "|" + strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Seal encrypts plaintext under the primary key, bound to name. Empty values
+// are returned unchanged, and so are values already sealed for name, so
+// callers can seal merged payloads blindly. Anything else that merely looks
+// sealed is treated as plaintext: only what Open accepts passes through.
+func (k *Keyring) Seal(name string, plaintext string) (string, error) {
+	if plaintext == "" {
+		return plaintext, nil
+	}
+	if k != nil && k.loadErr != nil {
+		return "", k.loadErr
+	}
+	if IsSealed(plaintext) {
+		if opened, err := k.Open(name, plaintext); err == nil {
+			if _, legacy, _ := sealedParts(plaintext); !legacy {
+				return plaintext, nil
+			}
+			plaintext = opened
+		}
+	}
+	if !k.Enabled() {
+		return plaintext, nil
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	aad := sealedAAD(k.primaryID, name, false)
+	wrappedKey, err := gcmSeal(k.keys[k.primaryID], dataKey, aad)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := gcmSeal(dataKey, []byte(plaintext), aad)
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + k.primaryID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value sealed for name. Legacy plaintext is returned as-is so
+// values written before encryption was enabled keep working until they are
+// rotated.
+func (k *Keyring) Open(name string, value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	if k != nil && k.loadErr != nil {
+		return "", k.loadErr
+	}
+	if k == nil || len(k.keys) == 0 {
+		return "", ErrKeyringUnavailable
+	}
+	parts, legacy, ok := sealedParts(value)
+	if !ok {
+		return "", errors.New("malformed sealed value")
+	}
+	keyID := parts[0]
+	masterKey, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("config master key %q is not loaded", keyID)
+	}
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed sealed value")
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed sealed value")
+	}
+	aad := sealedAAD(keyID, name, legacy)
+	dataKey, err := gcmOpen(masterKey, wrappedKey, aad)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key: %w", err)
+	}
+	plaintext, err := gcmOpen(dataKey, ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rotate re-seals a value under the primary key with a fresh data key. Legacy
+// plaintext gets sealed for the first time and v1 values are moved to the
+// name-bound envelope; v2 values already on the primary key are left alone.
+// The bool reports whether the stored value must be replaced.
+func (k *Keyring) Rotate(name string, value string) (string, bool, error) {
+	if value == "" || !k.Enabled() {
+		return value, false, nil
+	}
+	if parts, legacy, ok := sealedParts(value); ok && !legacy && parts[0] == k.primaryID {
+		if _, err := k.Open(name, value); err != nil {
+			return value, false, err
+		}
+		return value, false, nil
+	}
+	plaintext, err := k.Open(name, value)
+	if err != nil {
+		return value, false, err
+	}
+	sealed, err := k.Seal(name, plaintext)
+	if err != nil {
+		return value, false, err
+	}
+	return sealed, true, nil
+}
+
+func gcmSeal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func gcmOpen(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,269 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sercherai/backend/internal/platform/config"
+)
+
+func testMasterKey(fill byte) []byte {
+	key := make([]byte, dataKeySize)
+	for i := range key {
+		key[i] = fill
+	}
+	return key
+}
+
+func TestParseKeyringAcceptsHexBase64AndComments(t *testing.T) {
+	spec := strings.Join([]string{
+		"# rotated 2026-03",
+		"k2:" + hex.EncodeToString(testMasterKey(2)),
+		"k1:" + base64.StdEncoding.EncodeToString(testMasterKey(1)),
+	}, "\n")
+	keyring, err := ParseKeyring(spec)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	if !keyring.Enabled() || keyring.PrimaryKeyID() != "k2" || len(keyring.keys) != 2 {
+		t.Fatalf("unexpected keyring primary=%q keys=%d", keyring.PrimaryKeyID(), len(keyring.keys))
+	}
+
+	bare, err := ParseKeyring(base64.RawURLEncoding.EncodeToString(testMasterKey(3)))
+	if err != nil || bare.PrimaryKeyID() != "default" {
+		t.Fatalf("expected a bare key to get the default id, got %q, %v", bare.PrimaryKeyID(), err)
+	}
+
+	empty, err := ParseKeyring("  ")
+	if err != nil || empty.Enabled() {
+		t.Fatalf("expected an empty spec to disable sealing, got enabled=%v err=%v", empty.Enabled(), err)
+	}
+}
+
+func TestParseKeyringRejectsBadEntries(t *testing.T) {
+	valid := hex.EncodeToString(testMasterKey(1))
+	cases := map[string]string{
+		"duplicate id":  "k1:" + valid + ",k1:" + valid,
+		"bad id":        "bad id!:" + valid,
+		"short key":     "k1:" + base64.StdEncoding.EncodeToString([]byte("too-short")),
+		"not encoded":   "k1:%%%",
+		"empty key id":  ":" + valid,
+		"long key id":   strings.Repeat("k", 33) + ":" + valid,
+		"short hex key": "k1:" + hex.EncodeToString(testMasterKey(1)[:16]) + "zz",
+	}
+	for name, spec := range cases {
+		if _, err := ParseKeyring(spec); err == nil {
+			t.Errorf("%s: expected ParseKeyring(%q) to fail", name, spec)
+		}
+	}
+}
+
+// sealLegacyForTest writes a v1 value the way keyrings did before sealed values
+// were bound to their config key.
+func sealLegacyForTest(t *testing.T, k *Keyring, plaintext string) string {
+	t.Helper()
+	dataKey := testMasterKey(7)
+	wrappedKey, err := gcmSeal(k.keys[k.primaryID], dataKey, []byte(k.primaryID))
+	if err != nil {
+		t.Fatalf("gcmSeal() error = %v", err)
+	}
+	ciphertext, err := gcmSeal(dataKey, []byte(plaintext), []byte(k.primaryID))
+	if err != nil {
+		t.Fatalf("gcmSeal() error = %v", err)
+	}
+	return legacySealedPrefix + k.primaryID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext)
+}
+
+func TestSealOpenRoundTripAndRotation(t *testing.T) {
+	const name = "data_source.tushare.token"
+	oldKeyring, err := ParseKeyring("k1:" + hex.EncodeToString(testMasterKey(1)))
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	sealed, err := oldKeyring.Seal(name, "tushare-token")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !strings.HasPrefix(sealed, sealedPrefix) || SealedKeyID(sealed) != "k1" || strings.Contains(sealed, "tushare-token") {
+		t.Fatalf("unexpected sealed value %q", sealed)
+	}
+	again, _ := oldKeyring.Seal(name, "tushare-token")
+	if again == sealed {
+		t.Fatal("expected every seal to use a fresh data key and nonce")
+	}
+	if resealed, _ := oldKeyring.Seal(name, sealed); resealed != sealed {
+		t.Fatal("expected a value already sealed for the name to be returned unchanged")
+	}
+	if plaintext, err := oldKeyring.Open(name, sealed); err != nil || plaintext != "tushare-token" {
+		t.Fatalf("Open() = %q, %v", plaintext, err)
+	}
+
+	rotated, err := ParseKeyring("k2:" + hex.EncodeToString(testMasterKey(2)) + ",k1:" + hex.EncodeToString(testMasterKey(1)))
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	if plaintext, err := rotated.Open(name, sealed); err != nil || plaintext != "tushare-token" {
+		t.Fatalf("expected the retired key to still open old values, got %q, %v", plaintext, err)
+	}
+	moved, changed, err := rotated.Rotate(name, sealed)
+	if err != nil || !changed || SealedKeyID(moved) != "k2" {
+		t.Fatalf("Rotate() = %q, %v, %v", moved, changed, err)
+	}
+	if _, changed, _ := rotated.Rotate(name, moved); changed {
+		t.Fatal("expected a value on the primary key to be left alone")
+	}
+	legacy, changed, err := rotated.Rotate(name, "legacy-plaintext")
+	if err != nil || !changed || SealedKeyID(legacy) != "k2" {
+		t.Fatalf("expected legacy plaintext to be sealed on rotation, got %q, %v, %v", legacy, changed, err)
+	}
+
+	newOnly, _ := ParseKeyring("k2:" + hex.EncodeToString(testMasterKey(2)))
+	if _, err := newOnly.Open(name, sealed); err == nil {
+		t.Fatal("expected a value sealed under an unloaded key to fail to open")
+	}
+	wrongKey, _ := ParseKeyring("k1:" + hex.EncodeToString(testMasterKey(9)))
+	if _, err := wrongKey.Open(name, sealed); err == nil {
+		t.Fatal("expected the wrong master key to fail to unwrap")
+	}
+	if _, err := oldKeyring.Open(name, sealedPrefix+"k1:garbage"); err == nil {
+		t.Fatal("expected a malformed sealed value to be rejected")
+	}
+}
+
+func TestSealedValuesAreBoundToTheirName(t *testing.T) {
+	keyring, _ := ParseKeyring("k1:" + hex.EncodeToString(testMasterKey(1)))
+	sealed, err := keyring.Seal("payment.channel.yolkpay.key", "merchant-secret")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if _, err := keyring.Open("oss.qiniu.secret_key", sealed); err == nil {
+		t.Fatal("expected a value copied to another config key not to open")
+	}
+	if plaintext, err := keyring.Open(" PAYMENT.channel.yolkpay.key ", sealed); err != nil || plaintext != "merchant-secret" {
+		t.Fatalf("expected the name to match case-insensitively, got %q, %v", plaintext, err)
+	}
+
+	// A value that only looks sealed, or was sealed for another key, is
+	// treated as plaintext and sealed for this key rather than stored as-is.
+	for _, supplied := range []string{sealed, sealedPrefix + "k1:not:sealed"} {
+		stored, err := keyring.Seal("oss.qiniu.secret_key", supplied)
+		if err != nil {
+			t.Fatalf("Seal(%q) error = %v", supplied, err)
+		}
+		if stored == supplied {
+			t.Fatalf("expected %q to be sealed instead of passed through", supplied)
+		}
+		if opened, err := keyring.Open("oss.qiniu.secret_key", stored); err != nil || opened != supplied {
+			t.Fatalf("expected the supplied text to round trip, got %q, %v", opened, err)
+		}
+	}
+}
+
+func TestLegacyValuesOpenAndMoveToTheBoundEnvelope(t *testing.T) {
+	const name = "payment.signing_secret"
+	keyring, _ := ParseKeyring("k1:" + hex.EncodeToString(testMasterKey(1)))
+	legacy := sealLegacyForTest(t, keyring, "signing-secret")
+	if !IsSealed(legacy) || SealedKeyID(legacy) != "k1" {
+		t.Fatalf("expected a v1 value to count as sealed, got %q", legacy)
+	}
+	if plaintext, err := keyring.Open(name, legacy); err != nil || plaintext != "signing-secret" {
+		t.Fatalf("Open(v1) = %q, %v", plaintext, err)
+	}
+
+	upgraded, changed, err := keyring.Rotate(name, legacy)
+	if err != nil || !changed || !strings.HasPrefix(upgraded, sealedPrefix) {
+		t.Fatalf("expected Rotate to move a v1 value on the primary key, got %q, %v, %v", upgraded, changed, err)
+	}
+	if _, err := keyring.Open("other.secret", upgraded); err == nil {
+		t.Fatal("expected the upgraded value to be bound to its name")
+	}
+
+	resealed, err := keyring.Seal(name, legacy)
+	if err != nil || !strings.HasPrefix(resealed, sealedPrefix) {
+		t.Fatalf("expected Seal to upgrade an echoed v1 value, got %q, %v", resealed, err)
+	}
+	if plaintext, _ := keyring.Open(name, resealed); plaintext != "signing-secret" {
+		t.Fatalf("expected the upgraded value to keep its plaintext, got %q", plaintext)
+	}
+}
+
+func TestKeyringWithoutMasterKeyPassesPlaintextThrough(t *testing.T) {
+	keyring, err := Load(config.Config{AppEnv: "dev"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if keyring.Enabled() {
+		t.Fatal("expected sealing to be disabled without a master key")
+	}
+	if value, err := keyring.Seal("k", "plain"); err != nil || value != "plain" {
+		t.Fatalf("Seal() = %q, %v", value, err)
+	}
+	if value, err := keyring.Open("k", "plain"); err != nil || value != "plain" {
+		t.Fatalf("Open() = %q, %v", value, err)
+	}
+	if _, err := keyring.Open("k", sealedPrefix+"k1:a:b"); !errors.Is(err, ErrKeyringUnavailable) {
+		t.Fatalf("expected ErrKeyringUnavailable for a sealed value, got %v", err)
+	}
+	if value, changed, err := keyring.Rotate("k", "plain"); err != nil || changed || value != "plain" {
+		t.Fatalf("Rotate() = %q, %v, %v", value, changed, err)
+	}
+}
+
+func TestProductionWithoutMasterKeyFailsClosed(t *testing.T) {
+	keyring, err := Load(config.Config{AppEnv: "production"})
+	if !errors.Is(err, ErrKeyringUnavailable) {
+		t.Fatalf("expected ErrKeyringUnavailable in production without a key, got %v", err)
+	}
+	if _, err := keyring.Seal("payment.signing_secret", "secret"); !errors.Is(err, ErrKeyringUnavailable) {
+		t.Fatalf("expected production to refuse plaintext writes, got %v", err)
+	}
+	if value, err := keyring.Open("payment.signing_secret", "legacy-plaintext"); err != nil || value != "legacy-plaintext" {
+		t.Fatalf("expected legacy plaintext to stay readable, got %q, %v", value, err)
+	}
+
+	configured, err := Load(config.Config{AppEnv: "production", ConfigMasterKeys: "k1:" + hex.EncodeToString(testMasterKey(1))})
+	if err != nil || !configured.Enabled() {
+		t.Fatalf("expected a configured production keyring to load, got %v", err)
+	}
+}
+
+func TestLoadFailureRefusesToSealOrOpen(t *testing.T) {
+	keyring, err := Load(config.Config{ConfigMasterKeyFile: filepath.Join(t.TempDir(), "missing.key")})
+	if err == nil {
+		t.Fatal("expected a missing key file to fail to load")
+	}
+	if keyring == nil || keyring.Enabled() {
+		t.Fatal("expected a non-nil, disabled keyring after a failed load")
+	}
+	if _, err := keyring.Seal("k", "secret"); err == nil {
+		t.Fatal("expected a failed keyring to refuse plaintext writes")
+	}
+	if _, err := keyring.Open("k", sealedPrefix+"k1:a:b"); err == nil {
+		t.Fatal("expected a failed keyring to refuse to open values")
+	}
+
+	if _, err := Load(config.Config{ConfigMasterKeys: "k1:short"}); err == nil {
+		t.Fatal("expected an invalid CONFIG_MASTER_KEYS to fail to load")
+	}
+}
+
+func TestSealedKeyIDIgnoresPlaintextAndMalformedValues(t *testing.T) {
+	cases := map[string]string{
+		"enc:v2:k2:wrapped:cipher": "k2",
+		"enc:v1:k1:wrapped:cipher": "k1",
+		"plain":                    "",
+		"enc:v1:k1:only-two":       "",
+		"":                         "",
+	}
+	for value, want := range cases {
+		if got := SealedKeyID(value); got != want {
+			t.Errorf("SealedKeyID(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package secrets
+
+import "strings"
+
+// IsSensitiveConfigKey decides which system config keys, and which fields inside
+// data source config JSON, hold credentials that must be masked and sealed.
+func IsSensitiveConfigKey(rawKey string) bool {
+	key := strings.ToLower(strings.TrimSpace(rawKey))
+	if key == "" {
+		return false
+	}
+	// Selector keys name a source, routing policy or forecast engine
+	// (growth.forecast_l3.default_engine_key); they are read as plain
+	// identifiers and must not be sealed.
+	if strings.Contains(key, "source_key") || strings.Contains(key, "policy_key") || strings.Contains(key, "engine_key") {
+		return false
+	}
+	if strings.Contains(key, "secret") ||
+		strings.Contains(key, "token") ||
+		strings.Contains(key, "password") ||
+		strings.Contains(key, "private_key") ||
+		strings.Contains(key, "access_key") ||
+		strings.Contains(key, "api_v3_key") {
+		return true
+	}
+	return strings.HasSuffix(key, ".key") || strings.HasSuffix(key, "_key")
+}
@@ -0,0 +1,25 @@
+package secrets
+
+import "testing"
+
+func TestIsSensitiveConfigKey(t *testing.T) {
+	cases := map[string]bool{
+		"tushare.token":             true,
+		"payment.wechat.api_v3_key": true,
+		"SMTP_PASSWORD":             true,
+		"oss.access_key":            true,
+		"alipay.private_key":        true,
+		"sms.app_key":               true,
+		"openai.key":                true,
+		"market.source_key":         false,
+		"risk.policy_key":           false,
+		"strategy.engine_key":       false,
+		"site.title":                false,
+		"  ":                        false,
+	}
+	for key, want := range cases {
+		if got := IsSensitiveConfigKey(key); got != want {
+			t.Errorf("IsSensitiveConfigKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
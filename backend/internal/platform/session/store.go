@@ -0,0 +1,377 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	StatusActive  = "ACTIVE"
+	StatusRevoked = "REVOKED"
+	StatusExpired = "EXPIRED"
+
+	cacheKeyPrefix   = "auth:session:"
+	seenKeyPrefix    = "auth:session:seen:"
+	revokedCacheMark = "!revoked"
+
+	// last_seen_at only needs minute precision, so each session writes to
+	// MySQL at most once per touchInterval no matter how chatty the client is.
+	touchInterval = time.Minute
+	// A revoked or unknown jti is cached for this long so replayed tokens do
+	// not fall through to MySQL on every request.
+	negativeCacheTTL = 10 * time.Minute
+	// Active entries are kept short so a revoke that could not reach Redis is
+	// still picked up from MySQL within a few minutes.
+	activeCacheTTL = 5 * time.Minute
+
+	// StepUpWindow is how long a second-factor check on a session keeps
+	// unlocking sensitive operations.
+	StepUpWindow    = 15 * time.Minute
+	maxDeviceLength = 255
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type Session struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	Role         string `json:"role"`
+	Status       string `json:"status"`
+	Device       string `json:"device"`
+	IP           string `json:"ip"`
+	CreatedAt    string `json:"created_at"`
+	LastSeenAt   string `json:"last_seen_at"`
+	ExpiresAt    string `json:"expires_at"`
+	RevokedAt    string `json:"revoked_at,omitempty"`
+	RevokedBy    string `json:"revoked_by,omitempty"`
+	RevokeReason string `json:"revoke_reason,omitempty"`
+	Current      bool   `json:"current"`
+}
+
+// Store is the server-side registry of login sessions. A session id is the jti
+// shared by the access and refresh tokens of one login, so revoking it cuts off
+// both tokens. MySQL is the source of truth; Redis only caches the status for
+// the per-request check in AuthRequired.
+type Store struct {
+	db    *sql.DB
+	redis *redis.Client
+	now   func() time.Time
+}
+
+// NewStore returns nil without a database, which callers treat as "session
+// checks disabled" (the in-memory development mode has no users table either).
+func NewStore(db *sql.DB, redisClient *redis.Client) *Store {
+	if db == nil {
+		return nil
+	}
+	return &Store{db: db, redis: redisClient, now: time.Now}
+}
+
+// IsID reports whether a token jti names a session. Tokens signed before
+// the session registry carry a timestamp jti instead.
+func IsID(id string) bool {
+	return strings.HasPrefix(strings.TrimSpace(id), "sess_")
+}
+
+func NewID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("sess_%d", time.Now().UnixNano())
+	}
+	return "sess_" + hex.EncodeToString(buf)
+}
+
+func (s *Store) Create(item Session, expiresAt time.Time) error {
+	now := s.now()
+	_, err := s.db.Exec(`
+INSERT INTO auth_sessions (id, user_id, role, status, device, ip, created_at, last_seen_at, expires_at)
+VALUES (?, ?, ?, 'ACTIVE', ?, ?, ?, ?, ?)`,
+		item.ID, item.UserID, item.Role, truncate(item.Device, maxDeviceLength), item.IP, now, now, expiresAt,
+	)
+	if err != nil {
+		return err
+	}
+	s.cacheActive(item.ID, item.UserID, expiresAt)
+	return nil
+}
+
+// Extend pushes the session expiry forward after a refresh token rotation. It
+// fails with ErrSessionNotFound when the session is no longer active, which is
+// how a revoked session also stops its refresh chain.
+func (s *Store) Extend(id string, userID string, expiresAt time.Time, ip string) error {
+	now := s.now()
+	result, err := s.db.Exec(`
+UPDATE auth_sessions
+SET expires_at = ?, last_seen_at = ?, ip = ?
+WHERE id = ? AND user_id = ? AND status = 'ACTIVE' AND expires_at > ?`,
+		expiresAt, now, ip, id, userID, now,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSessionNotFound
+	}
+	s.cacheActive(id, userID, expiresAt)
+	return nil
+}
+
+// Check reports whether the jti belongs to an active session of userID and
+// records activity for it. Redis answers the hot path; a cache miss or Redis
+// outage falls back to MySQL and an error is only returned when MySQL fails too.
+func (s *Store) Check(id string, userID string, ip string) (bool, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || strings.TrimSpace(userID) == "" {
+		return false, nil
+	}
+	if cached, ok := s.cachedOwner(id); ok {
+		if cached != userID {
+			return false, nil
+		}
+		s.touch(id, ip)
+		return true, nil
+	}
+
+	var ownerID, status string
+	var expiresAt time.Time
+	err := s.db.QueryRow("SELECT user_id, status, expires_at FROM auth_sessions WHERE id = ?", id).Scan(&ownerID, &status, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.cacheRevoked(id)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if status != StatusActive || !expiresAt.After(s.now()) {
+		s.cacheRevoked(id)
+		return false, nil
+	}
+	s.cacheActive(id, ownerID, expiresAt)
+	if ownerID != userID {
+		return false, nil
+	}
+	s.touch(id, ip)
+	return true, nil
+}
+
+func (s *Store) ListByUser(userID string, includeInactive bool) ([]Session, error) {
+	query := `
+SELECT id, user_id, role, status, device, ip, created_at, last_seen_at, expires_at, revoked_at, revoked_by, revoke_reason
+FROM auth_sessions
+WHERE user_id = ?`
+	args := []interface{}{userID}
+	if !includeInactive {
+		query += " AND status = 'ACTIVE' AND expires_at > ?"
+		args = append(args, s.now())
+	}
+	query += " ORDER BY last_seen_at DESC LIMIT 100"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := s.now()
+	items := make([]Session, 0)
+	for rows.Next() {
+		var item Session
+		var createdAt, lastSeenAt, expiresAt time.Time
+		var revokedAt sql.NullTime
+		var revokedBy, revokeReason sql.NullString
+		if err := rows.Scan(&item.ID, &item.UserID, &item.Role, &item.Status, &item.Device, &item.IP, &createdAt, &lastSeenAt, &expiresAt, &revokedAt, &revokedBy, &revokeReason); err != nil {
+			return nil, err
+		}
+		if item.Status == StatusActive && !expiresAt.After(now) {
+			item.Status = StatusExpired
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.LastSeenAt = lastSeenAt.Format(time.RFC3339)
+		item.ExpiresAt = expiresAt.Format(time.RFC3339)
+		if revokedAt.Valid {
+			item.RevokedAt = revokedAt.Time.Format(time.RFC3339)
+		}
+		item.RevokedBy = revokedBy.String
+		item.RevokeReason = revokeReason.String
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Revoke ends one session of userID together with its refresh tokens. Scoping
+// by user keeps a user from revoking someone else's session by guessing ids.
+func (s *Store) Revoke(userID string, id string, revokedBy string, reason string) error {
+	now := s.now()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+UPDATE auth_sessions
+SET status = 'REVOKED', revoked_at = ?, revoked_by = ?, revoke_reason = ?
+WHERE id = ? AND user_id = ? AND status = 'ACTIVE'`,
+		now, revokedBy, reason, id, userID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSessionNotFound
+	}
+	if _, err := tx.Exec("UPDATE refresh_tokens SET revoked = 1, revoked_at = ? WHERE session_id = ? AND revoked = 0", now, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.cacheRevoked(id)
+	return nil
+}
+
+// RevokeAllForUser ends every active session of userID and all of its refresh
+// tokens, including legacy ones issued before sessions existed. It is used for
+// logout-all, account disable and password reset.
+func (s *Store) RevokeAllForUser(userID string, revokedBy string, reason string) (int, error) {
+	now := s.now()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM auth_sessions WHERE user_id = ? AND status = 'ACTIVE' FOR UPDATE", userID)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	if len(ids) > 0 {
+		if _, err := tx.Exec(`
+UPDATE auth_sessions
+SET status = 'REVOKED', revoked_at = ?, revoked_by = ?, revoke_reason = ?
+WHERE user_id = ? AND status = 'ACTIVE'`,
+			now, revokedBy, reason, userID,
+		); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec("UPDATE refresh_tokens SET revoked = 1, revoked_at = ? WHERE user_id = ? AND revoked = 0", now, userID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.cacheRevoked(id)
+	}
+	return len(ids), nil
+}
+
+// MarkMFAVerified records a successful second-factor check on the session,
+// which opens the StepUpWindow for sensitive operations.
+func (s *Store) MarkMFAVerified(id string) (time.Time, error) {
+	now := s.now()
+	_, err := s.db.Exec("UPDATE auth_sessions SET mfa_verified_at = ? WHERE id = ? AND status = 'ACTIVE'", now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(StepUpWindow), nil
+}
+
+func (s *Store) MFAVerifiedWithin(id string, window time.Duration) (bool, error) {
+	var verifiedAt sql.NullTime
+	err := s.db.QueryRow("SELECT mfa_verified_at FROM auth_sessions WHERE id = ? AND status = 'ACTIVE'", id).Scan(&verifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return verifiedAt.Valid && verifiedAt.Time.After(s.now().Add(-window)), nil
+}
+
+func (s *Store) touch(id string, ip string) {
+	now := s.now()
+	if s.redis != nil {
+		claimed, err := s.redis.SetNX(context.Background(), seenKeyPrefix+id, "1", touchInterval).Result()
+		if err == nil && !claimed {
+			return
+		}
+	}
+	_, _ = s.db.Exec(`
+UPDATE auth_sessions
+SET last_seen_at = ?, ip = ?
+WHERE id = ? AND last_seen_at < ?`,
+		now, ip, id, now.Add(-touchInterval),
+	)
+}
+
+func (s *Store) cachedOwner(id string) (string, bool) {
+	if s.redis == nil {
+		return "", false
+	}
+	value, err := s.redis.Get(context.Background(), cacheKeyPrefix+id).Result()
+	if err != nil {
+		return "", false
+	}
+	if value == revokedCacheMark {
+		return "", true
+	}
+	return value, true
+}
+
+func (s *Store) cacheActive(id string, userID string, expiresAt time.Time) {
+	ttl := expiresAt.Sub(s.now())
+	if s.redis == nil || ttl <= 0 {
+		return
+	}
+	if ttl > activeCacheTTL {
+		ttl = activeCacheTTL
+	}
+	_ = s.redis.Set(context.Background(), cacheKeyPrefix+id, userID, ttl).Err()
+}
+
+func (s *Store) cacheRevoked(id string) {
+	if s.redis == nil {
+		return
+	}
+	_ = s.redis.Set(context.Background(), cacheKeyPrefix+id, revokedCacheMark, negativeCacheTTL).Err()
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
@@ -0,0 +1,187 @@
+package session
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newStoreForTest(t *testing.T) (*Store, sqlmock.Sqlmock, time.Time) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	now := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	store := NewStore(db, nil)
+	store.now = func() time.Time { return now }
+	return store, mock, now
+}
+
+func TestNewStoreWithoutDatabaseDisablesSessions(t *testing.T) {
+	if store := NewStore(nil, nil); store != nil {
+		t.Fatalf("expected nil store without a database, got %+v", store)
+	}
+	if id := NewID(); !strings.HasPrefix(id, "sess_") || len(id) != len("sess_")+32 {
+		t.Fatalf("unexpected session id %q", id)
+	}
+}
+
+func TestCheckAcceptsOnlyTheOwnerOfAnActiveSession(t *testing.T) {
+	store, mock, now := newStoreForTest(t)
+	sessionRow := func(status string, expiresAt time.Time) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id", "status", "expires_at"}).AddRow("u_001", status, expiresAt)
+	}
+	query := regexp.QuoteMeta("SELECT user_id, status, expires_at FROM auth_sessions WHERE id = ?")
+
+	mock.ExpectQuery(query).WithArgs("sess_001").WillReturnRows(sessionRow(StatusActive, now.Add(time.Hour)))
+	mock.ExpectExec(regexp.QuoteMeta("SET last_seen_at = ?, ip = ?")).
+		WithArgs(now, "203.0.113.7", "sess_001", now.Add(-touchInterval)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if ok, err := store.Check("sess_001", "u_001", "203.0.113.7"); err != nil || !ok {
+		t.Fatalf("expected the owner to pass, got ok=%v err=%v", ok, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_001").WillReturnRows(sessionRow(StatusActive, now.Add(time.Hour)))
+	if ok, err := store.Check("sess_001", "u_other", "203.0.113.7"); err != nil || ok {
+		t.Fatalf("expected another user to be refused, got ok=%v err=%v", ok, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_002").WillReturnRows(sessionRow(StatusRevoked, now.Add(time.Hour)))
+	if ok, _ := store.Check("sess_002", "u_001", ""); ok {
+		t.Fatal("expected a revoked session to be refused")
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_003").WillReturnRows(sessionRow(StatusActive, now))
+	if ok, _ := store.Check("sess_003", "u_001", ""); ok {
+		t.Fatal("expected an expired session to be refused")
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_404").WillReturnRows(sqlmock.NewRows([]string{"user_id", "status", "expires_at"}))
+	if ok, err := store.Check("sess_404", "u_001", ""); err != nil || ok {
+		t.Fatalf("expected an unknown session to be refused, got ok=%v err=%v", ok, err)
+	}
+
+	if ok, _ := store.Check(" ", "u_001", ""); ok {
+		t.Fatal("expected a blank session id to be refused without a query")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestRevokeIsScopedToTheUser(t *testing.T) {
+	store, mock, now := newStoreForTest(t)
+	revoke := regexp.QuoteMeta("SET status = 'REVOKED', revoked_at = ?, revoked_by = ?, revoke_reason = ?")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(revoke).
+		WithArgs(now, "u_other", "logout", "sess_001", "u_other").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	if err := store.Revoke("u_other", "sess_001", "u_other", "logout"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for someone else's session, got %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(revoke).
+		WithArgs(now, "u_001", "logout", "sess_001", "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked = 1, revoked_at = ? WHERE session_id = ? AND revoked = 0")).
+		WithArgs(now, "sess_001").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	if err := store.Revoke("u_001", "sess_001", "u_001", "logout"); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestRevokeAllForUserRecordsWhoRevoked(t *testing.T) {
+	store, mock, now := newStoreForTest(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM auth_sessions WHERE user_id = ? AND status = 'ACTIVE' FOR UPDATE")).
+		WithArgs("u_001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("sess_a").AddRow("sess_b"))
+	mock.ExpectExec(regexp.QuoteMeta("SET status = 'REVOKED', revoked_at = ?, revoked_by = ?, revoke_reason = ?")).
+		WithArgs(now, "admin_007", "status_disabled", "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked = 1, revoked_at = ? WHERE user_id = ? AND revoked = 0")).
+		WithArgs(now, "u_001").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	revoked, err := store.RevokeAllForUser("u_001", "admin_007", "status_disabled")
+	if err != nil || revoked != 2 {
+		t.Fatalf("RevokeAllForUser() = %d, %v", revoked, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestListByUserMarksLapsedSessionsExpired(t *testing.T) {
+	store, mock, now := newStoreForTest(t)
+	columns := []string{"id", "user_id", "role", "status", "device", "ip", "created_at", "last_seen_at", "expires_at", "revoked_at", "revoked_by", "revoke_reason"}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth_sessions\nWHERE user_id = ? ORDER BY last_seen_at DESC LIMIT 100")).
+		WithArgs("u_001").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("sess_a", "u_001", "USER", StatusActive, "iPhone", "203.0.113.7", now.Add(-time.Hour), now, now.Add(time.Hour), nil, nil, nil).
+			AddRow("sess_b", "u_001", "USER", StatusActive, "Chrome", "203.0.113.8", now.Add(-48*time.Hour), now.Add(-24*time.Hour), now.Add(-time.Minute), nil, nil, nil).
+			AddRow("sess_c", "u_001", "USER", StatusRevoked, "Chrome", "203.0.113.9", now.Add(-72*time.Hour), now.Add(-72*time.Hour), now.Add(time.Hour), now.Add(-2*time.Hour), "admin_007", "status_disabled"))
+
+	items, err := store.ListByUser("u_001", true)
+	if err != nil {
+		t.Fatalf("ListByUser() error = %v", err)
+	}
+	if len(items) != 3 || items[0].Status != StatusActive || items[1].Status != StatusExpired || items[2].Status != StatusRevoked {
+		t.Fatalf("unexpected statuses: %+v", items)
+	}
+	if items[2].RevokedBy != "admin_007" || items[2].RevokeReason != "status_disabled" || items[2].RevokedAt == "" {
+		t.Fatalf("expected revoke details on the revoked session, got %+v", items[2])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
+
+func TestMFAVerifiedWithinHonoursTheWindow(t *testing.T) {
+	store, mock, now := newStoreForTest(t)
+	query := regexp.QuoteMeta("SELECT mfa_verified_at FROM auth_sessions WHERE id = ? AND status = 'ACTIVE'")
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_sessions SET mfa_verified_at = ?")).
+		WithArgs(now, "sess_001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	until, err := store.MarkMFAVerified("sess_001")
+	if err != nil || !until.Equal(now.Add(StepUpWindow)) {
+		t.Fatalf("MarkMFAVerified() = %v, %v", until, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_001").
+		WillReturnRows(sqlmock.NewRows([]string{"mfa_verified_at"}).AddRow(now.Add(-StepUpWindow + time.Minute)))
+	if ok, err := store.MFAVerifiedWithin("sess_001", StepUpWindow); err != nil || !ok {
+		t.Fatalf("expected a recent check to count, got ok=%v err=%v", ok, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_001").
+		WillReturnRows(sqlmock.NewRows([]string{"mfa_verified_at"}).AddRow(now.Add(-StepUpWindow - time.Minute)))
+	if ok, _ := store.MFAVerifiedWithin("sess_001", StepUpWindow); ok {
+		t.Fatal("expected a check outside the window not to count")
+	}
+
+	mock.ExpectQuery(query).WithArgs("sess_002").
+		WillReturnRows(sqlmock.NewRows([]string{"mfa_verified_at"}).AddRow(nil))
+	if ok, _ := store.MFAVerifiedWithin("sess_002", StepUpWindow); ok {
+		t.Fatal("expected a session without a check not to count")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sql expectations: %v", err)
+	}
+}
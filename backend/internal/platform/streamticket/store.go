@@ -0,0 +1,125 @@
+// Package streamticket issues short-lived, single-use tickets that let
+// clients which cannot set headers, such as the browser EventSource, open a
+// stream without putting their access token in the URL.
+package streamticket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// TTL is how long a ticket can wait before it is redeemed.
+	TTL       = 30 * time.Second
+	keyPrefix = "auth:stream_ticket:"
+)
+
+// Ticket is what a redeemed ticket stands for: the session that asked for
+// it.
+type Ticket struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	Role      string `json:"role"`
+}
+
+// Store keeps issued tickets in Redis so any replica can redeem them.
+// Without Redis tickets live in this process and must be redeemed on the
+// replica that issued them.
+type Store struct {
+	redis *redis.Client
+	now   func() time.Time
+
+	mu    sync.Mutex
+	local map[string]localTicket
+}
+
+type localTicket struct {
+	ticket    Ticket
+	expiresAt time.Time
+}
+
+// NewStore accepts a nil client, which selects the in-process store.
+func NewStore(redisClient *redis.Client) *Store {
+	return &Store{redis: redisClient, now: time.Now, local: map[string]localTicket{}}
+}
+
+// Issue returns a new ticket for item, valid for TTL.
+func (s *Store) Issue(ctx context.Context, item Ticket) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := "st_" + hex.EncodeToString(buf)
+	if s.redis != nil {
+		payload, err := json.Marshal(item)
+		if err != nil {
+			return "", err
+		}
+		if err := s.redis.Set(ctx, keyPrefix+raw, payload, TTL).Err(); err != nil {
+			return "", err
+		}
+		return raw, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for key, entry := range s.local {
+		if !entry.expiresAt.After(now) {
+			delete(s.local, key)
+		}
+	}
+	s.local[raw] = localTicket{ticket: item, expiresAt: now.Add(TTL)}
+	return raw, nil
+}
+
+// Redeem consumes raw. It reports false for unknown, expired and already
+// redeemed tickets.
+func (s *Store) Redeem(ctx context.Context, raw string) (Ticket, bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Ticket{}, false, nil
+	}
+	if s.redis != nil {
+		// GET and DEL in one MULTI, so two redeems cannot both read it;
+		// GETDEL would need Redis 6.2.
+		var get *redis.StringCmd
+		if _, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			get = pipe.Get(ctx, keyPrefix+raw)
+			pipe.Del(ctx, keyPrefix+raw)
+			return nil
+		}); err != nil && !errors.Is(err, redis.Nil) {
+			return Ticket{}, false, err
+		}
+		payload, err := get.Bytes()
+		if errors.Is(err, redis.Nil) {
+			return Ticket{}, false, nil
+		}
+		if err != nil {
+			return Ticket{}, false, err
+		}
+		var item Ticket
+		if err := json.Unmarshal(payload, &item); err != nil {
+			return Ticket{}, false, nil
+		}
+		return item, true, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.local[raw]
+	if !ok {
+		return Ticket{}, false, nil
+	}
+	delete(s.local, raw)
+	if !entry.expiresAt.After(s.now()) {
+		return Ticket{}, false, nil
+	}
+	return entry.ticket, true, nil
+}
@@ -0,0 +1,40 @@
+package streamticket
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTicketIsRedeemedOnce(t *testing.T) {
+	store := NewStore(nil)
+	raw, err := store.Issue(context.Background(), Ticket{UserID: "u_001", SessionID: "sess_001", Role: "USER"})
+	if err != nil || !strings.HasPrefix(raw, "st_") {
+		t.Fatalf("Issue() = %q, %v", raw, err)
+	}
+	ticket, ok, err := store.Redeem(context.Background(), raw)
+	if err != nil || !ok || ticket.UserID != "u_001" || ticket.SessionID != "sess_001" || ticket.Role != "USER" {
+		t.Fatalf("Redeem() = %+v, %v, %v", ticket, ok, err)
+	}
+	if _, ok, _ := store.Redeem(context.Background(), raw); ok {
+		t.Fatal("expected a redeemed ticket to be rejected")
+	}
+	if _, ok, _ := store.Redeem(context.Background(), "st_unknown"); ok {
+		t.Fatal("expected an unknown ticket to be rejected")
+	}
+}
+
+func TestTicketExpires(t *testing.T) {
+	now := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	store := NewStore(nil)
+	store.now = func() time.Time { return now }
+	raw, err := store.Issue(context.Background(), Ticket{UserID: "u_001"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	now = now.Add(TTL)
+	if _, ok, _ := store.Redeem(context.Background(), raw); ok {
+		t.Fatal("expected an expired ticket to be rejected")
+	}
+}
@@ -0,0 +1,33 @@
+// Package tabular writes row-oriented exports as CSV or XLSX. Both writers
+// stream: rows go straight to the underlying io.Writer, so an export never
+// holds more than the page of records it is currently writing.
+package tabular
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type Writer interface {
+	WriteRow(cells []string) error
+	// Close flushes buffered output and finishes the file. It does not
+	// close the underlying io.Writer.
+	Close() error
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(cells []string) error {
+	return c.w.Write(cells)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
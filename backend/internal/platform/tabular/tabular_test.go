@@ -0,0 +1,83 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func TestXLSXWriterProducesReadableWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewXLSXWriter(&buf, "users/2026")
+	if err != nil {
+		t.Fatalf("NewXLSXWriter() error = %v", err)
+	}
+	rows := [][]string{
+		{"id", "email", "note"},
+		{"u_1", "a&b@example.com", "line1\nline2"},
+		{"u_2", "", "<tag>\x01"},
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open xlsx zip: %v", err)
+	}
+	parts := map[string][]byte{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		parts[file.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Fatalf("missing part %s", name)
+		}
+	}
+	if !bytes.Contains(parts["xl/workbook.xml"], []byte(`name="users_2026"`)) {
+		t.Fatalf("expected sanitized sheet name, got %s", parts["xl/workbook.xml"])
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Ref   string `xml:"r,attr"`
+			Cells []struct {
+				Ref  string `xml:"r,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("parse sheet xml: %v", err)
+	}
+	if len(sheet.Rows) != 3 || sheet.Rows[2].Ref != "3" || sheet.Rows[1].Cells[2].Ref != "C2" {
+		t.Fatalf("unexpected sheet layout %+v", sheet.Rows)
+	}
+	if got := sheet.Rows[1].Cells[1].Text; got != "a&b@example.com" {
+		t.Fatalf("expected escaped text to round-trip, got %q", got)
+	}
+	if got := sheet.Rows[2].Cells[2].Text; got != "<tag>" {
+		t.Fatalf("expected control characters to be dropped, got %q", got)
+	}
+}
+
+func TestXLSXColumnNames(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, want := range cases {
+		if got := xlsxColumn(index); got != want {
+			t.Fatalf("xlsxColumn(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
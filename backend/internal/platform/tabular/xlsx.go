@@ -0,0 +1,151 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XLSX output is a single-sheet SpreadsheetML package. Every cell is an
+// inline string so no shared-string table has to be kept in memory, and the
+// sheet part is the last zip entry so rows can be appended until Close.
+
+// xlsxMaxRows is Excel's row limit per sheet.
+const xlsxMaxRows = 1048576
+
+var ErrTooManyRows = errors.New("xlsx sheet row limit reached")
+
+var xlsxStaticParts = []struct {
+	name string
+	body string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+	err   error
+}
+
+// NewXLSXWriter starts a workbook with one sheet named sheetName.
+func NewXLSXWriter(w io.Writer, sheetName string) (Writer, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		if err := writeZipPart(zw, part.name, part.body); err != nil {
+			return nil, err
+		}
+	}
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` +
+		escapeXML(xlsxSheetName(sheetName)) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writeZipPart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+	part, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(part)
+	if _, err := sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{zw: zw, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) WriteRow(cells []string) error {
+	if x.err != nil {
+		return x.err
+	}
+	if x.rows >= xlsxMaxRows {
+		return ErrTooManyRows
+	}
+	x.rows++
+	row := strconv.Itoa(x.rows)
+	var b strings.Builder
+	b.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		b.WriteString(`<c r="` + xlsxColumn(i) + row + `" t="inlineStr"><is><t xml:space="preserve">`)
+		b.WriteString(escapeXML(cell))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, x.err = x.sheet.WriteString(b.String())
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+	if _, err := x.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+func writeZipPart(zw *zip.Writer, name string, body string) error {
+	part, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, body)
+	return err
+}
+
+// xlsxColumn converts a zero-based column index to letters: 0 -> A,
+// 26 -> AA.
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName trims a sheet name to Excel's rules: at most 31 characters
+// and none of : \ / ? * [ ].
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// escapeXML escapes text for an XML element and drops control characters
+// XML 1.0 cannot carry at all.
+func escapeXML(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, value)
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
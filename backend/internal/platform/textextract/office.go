@@ -0,0 +1,307 @@
+package textextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DOCX and XLSX are zip packages of XML parts. DOCX pages follow the
+// explicit and last-rendered page breaks Word records in document.xml;
+// each XLSX worksheet becomes one page headed by its sheet name.
+
+const xlsxMaxRows = 100000
+
+func extractOffice(payload []byte) (Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(payload), int64(len(payload)))
+	if err != nil {
+		return Document{}, err
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+	switch {
+	case parts["word/document.xml"] != nil:
+		return extractDOCX(parts)
+	case parts["xl/workbook.xml"] != nil:
+		return extractXLSX(parts)
+	default:
+		return Document{}, ErrUnsupported
+	}
+}
+
+func openPart(parts map[string]*zip.File, name string) (*xml.Decoder, func(), error) {
+	file := parts[name]
+	if file == nil {
+		return nil, nil, errors.New("textextract: missing part " + name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxDecodedBytes))
+	decoder.Strict = false
+	return decoder, func() { reader.Close() }, nil
+}
+
+func attrValue(start xml.StartElement, local string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func extractDOCX(parts map[string]*zip.File) (Document, error) {
+	decoder, closePart, err := openPart(parts, "word/document.xml")
+	if err != nil {
+		return Document{}, err
+	}
+	defer closePart()
+
+	b := newPageBuilder()
+	b.startPage()
+	inText := false
+	for !b.full {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Document{}, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.write("\t")
+			case "br":
+				if attrValue(t, "type") == "page" {
+					if !b.pageEmpty() {
+						b.startPage()
+					}
+				} else {
+					b.write("\n")
+				}
+			case "lastRenderedPageBreak":
+				if !b.pageEmpty() {
+					b.startPage()
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.write("\n")
+			case "tc":
+				b.write("\t")
+			}
+		case xml.CharData:
+			if inText {
+				b.write(string(t))
+			}
+		}
+	}
+	return b.document(FormatDOCX), nil
+}
+
+type xlsxSheet struct {
+	name string
+	part string
+}
+
+func extractXLSX(parts map[string]*zip.File) (Document, error) {
+	shared, err := xlsxSharedStrings(parts)
+	if err != nil {
+		return Document{}, err
+	}
+	sheets, err := xlsxSheets(parts)
+	if err != nil {
+		return Document{}, err
+	}
+	b := newPageBuilder()
+	for _, sheet := range sheets {
+		b.startPage()
+		b.write(sheet.name + "\n")
+		if err := xlsxSheetText(b, parts, sheet.part, shared); err != nil {
+			return Document{}, err
+		}
+		if b.full {
+			break
+		}
+	}
+	return b.document(FormatXLSX), nil
+}
+
+func xlsxSharedStrings(parts map[string]*zip.File) ([]string, error) {
+	if parts["xl/sharedStrings.xml"] == nil {
+		return nil, nil
+	}
+	decoder, closePart, err := openPart(parts, "xl/sharedStrings.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer closePart()
+
+	var values []string
+	var current strings.Builder
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				// Phonetic hints repeat the text; skip them.
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "si":
+				values = append(values, current.String())
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+}
+
+// xlsxSheets lists worksheets in workbook order, resolving each sheet's
+// relationship ID to its part name.
+func xlsxSheets(parts map[string]*zip.File) ([]xlsxSheet, error) {
+	targets := map[string]string{}
+	if parts["xl/_rels/workbook.xml.rels"] != nil {
+		decoder, closePart, err := openPart(parts, "xl/_rels/workbook.xml.rels")
+		if err != nil {
+			return nil, err
+		}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				break
+			}
+			if start, ok := token.(xml.StartElement); ok && start.Name.Local == "Relationship" {
+				target := attrValue(start, "Target")
+				if strings.HasPrefix(target, "/") {
+					target = strings.TrimPrefix(target, "/")
+				} else {
+					target = path.Join("xl", target)
+				}
+				targets[attrValue(start, "Id")] = target
+			}
+		}
+		closePart()
+	}
+
+	decoder, closePart, err := openPart(parts, "xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer closePart()
+	var sheets []xlsxSheet
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "sheet" {
+			continue
+		}
+		part := targets[attrValue(start, "id")]
+		if part == "" {
+			part = "xl/worksheets/sheet" + strconv.Itoa(len(sheets)+1) + ".xml"
+		}
+		if parts[part] != nil {
+			sheets = append(sheets, xlsxSheet{name: attrValue(start, "name"), part: part})
+		}
+	}
+	return sheets, nil
+}
+
+// xlsxSheetText writes one row per line with tab-separated cell values.
+func xlsxSheetText(b *pageBuilder, parts map[string]*zip.File, part string, shared []string) error {
+	decoder, closePart, err := openPart(parts, part)
+	if err != nil {
+		return err
+	}
+	defer closePart()
+
+	rows := 0
+	cellType := ""
+	inValue := false
+	var value strings.Builder
+	for !b.full && rows < xlsxMaxRows {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "c":
+				cellType = attrValue(t, "t")
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				text := value.String()
+				if cellType == "s" {
+					if index, err := strconv.Atoi(strings.TrimSpace(text)); err == nil && index >= 0 && index < len(shared) {
+						text = shared[index]
+					} else {
+						text = ""
+					}
+				}
+				if text != "" {
+					b.write(text + "\t")
+				}
+			case "row":
+				b.write("\n")
+				rows++
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,605 @@
+package textextract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// PDFs are read without the xref table: every "N G obj" is located by a
+// scan and object streams are expanded, which also copes with files whose
+// xref offsets are broken. Pages are walked from the catalog and text is
+// taken from the Tj/TJ/'/" operators, mapped through each font's ToUnicode
+// CMap. Encrypted files and scanned images yield no text.
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfRootRef      = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+)
+
+const (
+	pdfMaxPages      = 2000
+	pdfMaxFormDepth  = 4
+	pdfTJSpaceGap    = -200
+	pdfMaxRangeCodes = 1 << 16
+)
+
+var errPDFEncrypted = errors.New("textextract: encrypted pdf")
+
+type pdfObject struct {
+	value  interface{}
+	stream []byte
+	dict   map[string]interface{}
+}
+
+type pdfFile struct {
+	objects map[int]*pdfObject
+	decoded map[int][]byte
+	fonts   map[int]*pdfFont
+	budget  int
+}
+
+type pdfFont struct {
+	cmap     *pdfCMap
+	twoByte  bool
+	utf16Enc bool
+}
+
+func extractPDF(payload []byte) (Document, error) {
+	tail := payload
+	if len(tail) > 4096 {
+		tail = tail[len(tail)-4096:]
+	}
+	if bytes.Contains(tail, []byte("/Encrypt")) {
+		return Document{}, errPDFEncrypted
+	}
+	file := &pdfFile{
+		objects: scanPDFObjects(payload),
+		decoded: map[int][]byte{},
+		fonts:   map[int]*pdfFont{},
+		budget:  maxDecodedBytes,
+	}
+	file.expandObjectStreams()
+
+	b := newPageBuilder()
+	for _, page := range file.pages(payload) {
+		b.startPage()
+		resources := file.dictOf(page["Resources"])
+		for _, content := range file.contentStreams(page["Contents"]) {
+			file.runContent(b, content, resources, 0)
+			b.write("\n")
+		}
+		if b.full {
+			break
+		}
+	}
+	return b.document(FormatPDF), nil
+}
+
+func scanPDFObjects(payload []byte) map[int]*pdfObject {
+	objects := map[int]*pdfObject{}
+	for _, loc := range pdfObjectHeader.FindAllSubmatchIndex(payload, -1) {
+		num := atoiBytes(payload[loc[2]:loc[3]])
+		body := payload[loc[1]:]
+		lexer := newPDFLexer(body)
+		value, ok := lexer.next()
+		if !ok {
+			continue
+		}
+		if op, isOp := value.(pdfOp); isOp && op == "R" {
+			continue
+		}
+		obj := &pdfObject{value: value}
+		if dict, isDict := value.(map[string]interface{}); isDict {
+			obj.dict = dict
+			lexer.skipSpace()
+			if bytes.HasPrefix(body[lexer.pos:], []byte("stream")) {
+				obj.stream = readPDFStreamData(body[lexer.pos+len("stream"):], dict)
+			}
+		}
+		objects[num] = obj
+	}
+	return objects
+}
+
+func readPDFStreamData(data []byte, dict map[string]interface{}) []byte {
+	if bytes.HasPrefix(data, []byte("\r\n")) {
+		data = data[2:]
+	} else if len(data) > 0 && (data[0] == '\n' || data[0] == '\r') {
+		data = data[1:]
+	}
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && int(length) <= len(data) {
+		rest := bytes.TrimLeft(data[int(length):], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return data[:int(length)]
+		}
+	}
+	end := bytes.Index(data, []byte("endstream"))
+	if end < 0 {
+		return data
+	}
+	return bytes.TrimRight(data[:end], "\r\n")
+}
+
+// expandObjectStreams adds objects packed in /ObjStm streams that are not
+// also defined directly.
+func (f *pdfFile) expandObjectStreams() {
+	nums := make([]int, 0)
+	for num, obj := range f.objects {
+		if name, _ := obj.dict["Type"].(pdfName); name == "ObjStm" {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		obj := f.objects[num]
+		data := f.decodeStream(obj)
+		count, _ := obj.dict["N"].(float64)
+		first, _ := obj.dict["First"].(float64)
+		if data == nil || int(first) > len(data) {
+			continue
+		}
+		header := newPDFLexer(data[:int(first)])
+		type entry struct{ num, offset int }
+		entries := make([]entry, 0, int(count))
+		for i := 0; i < int(count); i++ {
+			n, ok1 := header.next()
+			o, ok2 := header.next()
+			nf, isNum := n.(float64)
+			of, isOff := o.(float64)
+			if !ok1 || !ok2 || !isNum || !isOff {
+				break
+			}
+			entries = append(entries, entry{num: int(nf), offset: int(first) + int(of)})
+		}
+		for _, e := range entries {
+			if _, exists := f.objects[e.num]; exists || e.offset >= len(data) {
+				continue
+			}
+			value, ok := newPDFLexer(data[e.offset:]).next()
+			if !ok {
+				continue
+			}
+			dict, _ := value.(map[string]interface{})
+			f.objects[e.num] = &pdfObject{value: value, dict: dict}
+		}
+	}
+}
+
+func (f *pdfFile) resolve(value interface{}) interface{} {
+	for i := 0; i < 8; i++ {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		obj := f.objects[int(ref)]
+		if obj == nil {
+			return nil
+		}
+		value = obj.value
+	}
+	return nil
+}
+
+func (f *pdfFile) dictOf(value interface{}) map[string]interface{} {
+	dict, _ := f.resolve(value).(map[string]interface{})
+	return dict
+}
+
+// streamOf decodes the stream a reference points to, caching by object.
+func (f *pdfFile) streamOf(value interface{}) []byte {
+	ref, ok := value.(pdfRef)
+	if !ok {
+		return nil
+	}
+	if data, cached := f.decoded[int(ref)]; cached {
+		return data
+	}
+	obj := f.objects[int(ref)]
+	if obj == nil || obj.stream == nil {
+		return nil
+	}
+	data := f.decodeStream(obj)
+	f.decoded[int(ref)] = data
+	return data
+}
+
+// decodeStream applies FlateDecode filters; any other filter (images,
+// LZW, encryption) yields nil.
+func (f *pdfFile) decodeStream(obj *pdfObject) []byte {
+	if obj.stream == nil {
+		return nil
+	}
+	var filters []interface{}
+	switch value := f.resolve(obj.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{value}
+	case []interface{}:
+		filters = value
+	}
+	data := obj.stream
+	for _, filter := range filters {
+		name, _ := f.resolve(filter).(pdfName)
+		if name != "FlateDecode" && name != "Fl" {
+			return nil
+		}
+		if f.budget <= 0 {
+			return nil
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil
+		}
+		// Truncated streams are common; keep whatever inflated cleanly.
+		inflated, _ := io.ReadAll(io.LimitReader(reader, int64(f.budget)))
+		reader.Close()
+		f.budget -= len(inflated)
+		data = inflated
+	}
+	return data
+}
+
+// pages returns page dictionaries in document order, with inherited
+// /Resources filled in. Without a usable page tree it falls back to every
+// /Type /Page object in object-number order.
+func (f *pdfFile) pages(payload []byte) []map[string]interface{} {
+	var pages []map[string]interface{}
+	if matches := pdfRootRef.FindAllSubmatch(payload, -1); len(matches) > 0 {
+		root := f.dictOf(pdfRef(atoiBytes(matches[len(matches)-1][1])))
+		visited := map[int]bool{}
+		var walk func(node interface{}, resources interface{})
+		walk = func(node interface{}, resources interface{}) {
+			if ref, ok := node.(pdfRef); ok {
+				if visited[int(ref)] {
+					return
+				}
+				visited[int(ref)] = true
+			}
+			dict := f.dictOf(node)
+			if dict == nil || len(pages) >= pdfMaxPages {
+				return
+			}
+			if own, ok := dict["Resources"]; ok {
+				resources = own
+			}
+			if kind, _ := dict["Type"].(pdfName); kind == "Page" {
+				page := make(map[string]interface{}, len(dict)+1)
+				for key, value := range dict {
+					page[key] = value
+				}
+				page["Resources"] = resources
+				pages = append(pages, page)
+				return
+			}
+			kids, _ := f.resolve(dict["Kids"]).([]interface{})
+			for _, kid := range kids {
+				walk(kid, resources)
+			}
+		}
+		if root != nil {
+			walk(root["Pages"], nil)
+		}
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+	nums := make([]int, 0)
+	for num, obj := range f.objects {
+		if kind, _ := obj.dict["Type"].(pdfName); kind == "Page" {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if len(pages) >= pdfMaxPages {
+			break
+		}
+		pages = append(pages, f.objects[num].dict)
+	}
+	return pages
+}
+
+func (f *pdfFile) contentStreams(contents interface{}) [][]byte {
+	var refs []interface{}
+	switch value := contents.(type) {
+	case pdfRef:
+		if list, ok := f.resolve(value).([]interface{}); ok {
+			refs = list
+		} else {
+			refs = []interface{}{value}
+		}
+	case []interface{}:
+		refs = value
+	}
+	streams := make([][]byte, 0, len(refs))
+	for _, ref := range refs {
+		if data := f.streamOf(ref); data != nil {
+			streams = append(streams, data)
+		}
+	}
+	return streams
+}
+
+// runContent interprets the text operators of one content stream.
+func (f *pdfFile) runContent(b *pageBuilder, content []byte, resources map[string]interface{}, depth int) {
+	fonts := f.dictOf(resources["Font"])
+	var font *pdfFont
+	lastY, haveY := 0.0, false
+	var operands []interface{}
+	lexer := newPDFLexer(content)
+	for {
+		value, ok := lexer.next()
+		if !ok {
+			return
+		}
+		op, isOp := value.(pdfOp)
+		if !isOp {
+			operands = append(operands, value)
+			continue
+		}
+		switch op {
+		case "BT":
+			haveY = false
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					font = f.font(fonts[string(name)])
+				}
+			}
+		case "Tj":
+			if len(operands) > 0 {
+				b.write(font.decode(operands[len(operands)-1]))
+			}
+		case "'", "\"":
+			b.write("\n")
+			if len(operands) > 0 {
+				b.write(font.decode(operands[len(operands)-1]))
+			}
+		case "TJ":
+			if len(operands) == 0 {
+				break
+			}
+			items, _ := operands[len(operands)-1].([]interface{})
+			for _, item := range items {
+				if gap, isNum := item.(float64); isNum {
+					if gap < pdfTJSpaceGap {
+						b.write(" ")
+					}
+					continue
+				}
+				b.write(font.decode(item))
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				// Horizontal moves add nothing: CJK text is often laid
+				// out run by run and must stay contiguous for search.
+				if ty, _ := operands[len(operands)-1].(float64); ty != 0 {
+					b.write("\n")
+				}
+			}
+		case "T*":
+			b.write("\n")
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := operands[len(operands)-1].(float64)
+				if haveY && y != lastY {
+					b.write("\n")
+				}
+				lastY, haveY = y, true
+			}
+		case "ID":
+			// Inline image data runs to the next whitespace-delimited EI.
+			if end := bytes.Index(content[lexer.pos:], []byte("EI")); end >= 0 {
+				lexer.pos += end + 2
+			} else {
+				lexer.pos = len(content)
+			}
+		case "Do":
+			if depth >= pdfMaxFormDepth || len(operands) == 0 {
+				break
+			}
+			name, _ := operands[len(operands)-1].(pdfName)
+			ref := f.dictOf(resources["XObject"])[string(name)]
+			form, _ := f.resolve(ref).(map[string]interface{})
+			if subtype, _ := form["Subtype"].(pdfName); subtype != "Form" {
+				break
+			}
+			formResources := f.dictOf(form["Resources"])
+			if formResources == nil {
+				formResources = resources
+			}
+			if data := f.streamOf(ref); data != nil {
+				f.runContent(b, data, formResources, depth+1)
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+func (f *pdfFile) font(ref interface{}) *pdfFont {
+	num, isRef := ref.(pdfRef)
+	if isRef {
+		if font, cached := f.fonts[int(num)]; cached {
+			return font
+		}
+	}
+	dict := f.dictOf(ref)
+	font := &pdfFont{}
+	if dict != nil {
+		subtype, _ := dict["Subtype"].(pdfName)
+		font.twoByte = subtype == "Type0"
+		if encoding, ok := f.resolve(dict["Encoding"]).(pdfName); ok {
+			upper := strings.ToUpper(string(encoding))
+			font.utf16Enc = strings.Contains(upper, "UCS2") || strings.Contains(upper, "UTF16")
+		}
+		if data := f.streamOf(dict["ToUnicode"]); data != nil {
+			font.cmap = parseCMap(data)
+		}
+	}
+	if isRef {
+		f.fonts[int(num)] = font
+	}
+	return font
+}
+
+// decode maps a shown string to text. Without a CMap, simple fonts are
+// read as Latin-1 and Type0 fonts only decode under a UCS-2 encoding.
+func (font *pdfFont) decode(value interface{}) string {
+	raw, ok := value.(pdfString)
+	if !ok {
+		return ""
+	}
+	if font == nil {
+		font = &pdfFont{}
+	}
+	if font.cmap != nil {
+		return font.cmap.decode(raw, font.twoByte)
+	}
+	if font.twoByte {
+		if font.utf16Enc {
+			return decodeUTF16BE(raw)
+		}
+		return ""
+	}
+	out := make([]rune, 0, len(raw))
+	for _, c := range raw {
+		out = append(out, rune(c))
+	}
+	return string(out)
+}
+
+type pdfCMap struct {
+	codeLen int
+	codes   map[uint32]string
+}
+
+func parseCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{codes: map[uint32]string{}}
+	lexer := newPDFLexer(data)
+	var pending []interface{}
+	mode := ""
+	for {
+		value, ok := lexer.next()
+		if !ok {
+			break
+		}
+		op, isOp := value.(pdfOp)
+		if !isOp {
+			if mode != "" {
+				pending = append(pending, value)
+			}
+			continue
+		}
+		switch op {
+		case "beginbfchar", "beginbfrange":
+			mode = string(op)
+			pending = pending[:0]
+		case "endbfchar":
+			for i := 0; i+1 < len(pending); i += 2 {
+				src, ok1 := pending[i].(pdfString)
+				dst, ok2 := pending[i+1].(pdfString)
+				if ok1 && ok2 {
+					cmap.add(src, codeValue(src), decodeUTF16BE(dst))
+				}
+			}
+			mode = ""
+		case "endbfrange":
+			for i := 0; i+2 < len(pending); i += 3 {
+				lo, ok1 := pending[i].(pdfString)
+				hi, ok2 := pending[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := codeValue(lo), codeValue(hi)
+				if end < start || end-start > pdfMaxRangeCodes {
+					continue
+				}
+				switch dst := pending[i+2].(type) {
+				case pdfString:
+					base := []rune(decodeUTF16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for code := start; code <= end; code++ {
+						mapped := append([]rune{}, base...)
+						mapped[len(mapped)-1] += rune(code - start)
+						cmap.add(lo, code, string(mapped))
+					}
+				case []interface{}:
+					for j, item := range dst {
+						if text, ok := item.(pdfString); ok && start+uint32(j) <= end {
+							cmap.add(lo, start+uint32(j), decodeUTF16BE(text))
+						}
+					}
+				}
+			}
+			mode = ""
+		}
+	}
+	if len(cmap.codes) == 0 {
+		return nil
+	}
+	return cmap
+}
+
+func (c *pdfCMap) add(src pdfString, code uint32, text string) {
+	if c.codeLen == 0 && len(src) > 0 && len(src) <= 4 {
+		c.codeLen = len(src)
+	}
+	c.codes[code] = text
+}
+
+func (c *pdfCMap) decode(raw []byte, twoByte bool) string {
+	width := c.codeLen
+	if width == 0 {
+		width = 1
+		if twoByte {
+			width = 2
+		}
+	}
+	var out strings.Builder
+	for i := 0; i+width <= len(raw); i += width {
+		code := codeValue(raw[i : i+width])
+		if text, ok := c.codes[code]; ok {
+			out.WriteString(text)
+		} else if width == 1 && code >= 0x20 && code < 0x7F {
+			out.WriteByte(byte(code))
+		}
+	}
+	return out.String()
+}
+
+func codeValue(raw []byte) uint32 {
+	var value uint32
+	for _, c := range raw {
+		value = value<<8 | uint32(c)
+	}
+	return value
+}
+
+func decodeUTF16BE(raw []byte) string {
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func atoiBytes(raw []byte) int {
+	value := 0
+	for _, c := range raw {
+		if c < '0' || c > '9' {
+			break
+		}
+		value = value*10 + int(c-'0')
+		if value > 1<<30 {
+			return 0
+		}
+	}
+	return value
+}
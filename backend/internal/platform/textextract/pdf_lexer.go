@@ -0,0 +1,318 @@
+package textextract
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// A small PDF object lexer, enough for object dictionaries, content
+// streams and CMaps. Values are:
+//   pdfName, pdfString, float64, bool, nil, []interface{},
+//   map[string]interface{}, pdfRef and pdfOp (a bare keyword).
+
+type (
+	pdfName   string
+	pdfString []byte
+	pdfOp     string
+	pdfRef    int
+)
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func newPDFLexer(data []byte) *pdfLexer {
+	return &pdfLexer{data: data}
+}
+
+func isPDFWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// next returns the next value, or ok=false at end of input. Closing
+// delimiters come back as pdfOp("]") or pdfOp(">>").
+func (l *pdfLexer) next() (interface{}, bool) {
+	return l.nextDepth(0)
+}
+
+func (l *pdfLexer) nextDepth(depth int) (interface{}, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	if depth > 64 {
+		l.pos = len(l.data)
+		return nil, false
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.readName(), true
+	case c == '(':
+		return l.readLiteralString(), true
+	case c == '<' && l.peek(1) == '<':
+		l.pos += 2
+		return l.readDict(depth), true
+	case c == '<':
+		return l.readHexString(), true
+	case c == '>' && l.peek(1) == '>':
+		l.pos += 2
+		return pdfOp(">>"), true
+	case c == '[':
+		l.pos++
+		return l.readArray(depth), true
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		return pdfOp(string(c)), true
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.readNumber(), true
+	}
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	switch word {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	return pdfOp(word), true
+}
+
+func (l *pdfLexer) peek(offset int) byte {
+	if l.pos+offset < len(l.data) {
+		return l.data[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *pdfLexer) readName() pdfName {
+	l.pos++
+	var out []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) || isPDFDelimiter(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		out = append(out, c)
+		l.pos++
+	}
+	return pdfName(out)
+}
+
+func (l *pdfLexer) readNumber() interface{} {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || c == '.' {
+			l.pos++
+			continue
+		}
+		break
+	}
+	value, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+	if err != nil {
+		return float64(0)
+	}
+	return value
+}
+
+func (l *pdfLexer) readLiteralString() pdfString {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(out)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return pdfString(out)
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					value := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(value)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return pdfString(out)
+}
+
+func (l *pdfLexer) readHexString() pdfString {
+	l.pos++
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		end = len(l.data) - l.pos
+	}
+	raw := l.data[l.pos : l.pos+end]
+	l.pos += end + 1
+	return decodeHex(raw)
+}
+
+func decodeHex(raw []byte) pdfString {
+	out := make([]byte, 0, len(raw)/2+1)
+	var current byte
+	half := false
+	for _, c := range raw {
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+		if half {
+			out = append(out, current<<4|v)
+			half = false
+		} else {
+			current = v
+			half = true
+		}
+	}
+	if half {
+		out = append(out, current<<4)
+	}
+	return pdfString(out)
+}
+
+func (l *pdfLexer) readArray(depth int) []interface{} {
+	var items []interface{}
+	for {
+		value, ok := l.nextDepth(depth + 1)
+		if !ok {
+			return items
+		}
+		if op, isOp := value.(pdfOp); isOp {
+			if op == "]" {
+				return items
+			}
+			if op == "R" {
+				items = foldRef(items)
+				continue
+			}
+		}
+		items = append(items, value)
+	}
+}
+
+func (l *pdfLexer) readDict(depth int) map[string]interface{} {
+	var items []interface{}
+	for {
+		value, ok := l.nextDepth(depth + 1)
+		if !ok {
+			break
+		}
+		if op, isOp := value.(pdfOp); isOp {
+			if op == ">>" {
+				break
+			}
+			if op == "R" {
+				items = foldRef(items)
+				continue
+			}
+		}
+		items = append(items, value)
+	}
+	dict := make(map[string]interface{}, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		if key, ok := items[i].(pdfName); ok {
+			dict[string(key)] = items[i+1]
+		}
+	}
+	return dict
+}
+
+// foldRef turns a trailing "num gen" pair into a reference.
+func foldRef(items []interface{}) []interface{} {
+	if len(items) < 2 {
+		return items
+	}
+	num, okNum := items[len(items)-2].(float64)
+	_, okGen := items[len(items)-1].(float64)
+	if !okNum || !okGen {
+		return items
+	}
+	return append(items[:len(items)-2], pdfRef(int(num)))
+}
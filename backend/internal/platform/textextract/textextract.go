@@ -0,0 +1,176 @@
+// Package textextract pulls searchable plain text out of news attachments:
+// PDF, DOCX, XLSX and plain text, without cgo or external tools.
+//
+// Documents are split into pages (PDF pages, DOCX rendered page breaks,
+// XLSX sheets) and PageOffsets records the rune offset where each page
+// starts, so a match position maps back to a page number.
+package textextract
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	FormatPDF  = "PDF"
+	FormatDOCX = "DOCX"
+	FormatXLSX = "XLSX"
+	FormatText = "TEXT"
+
+	// MaxTextRunes caps stored text per document; anything past it is
+	// dropped.
+	MaxTextRunes = 1 << 20
+	// maxDecodedBytes caps every decompressed stream or archive member.
+	maxDecodedBytes = 64 << 20
+)
+
+var ErrUnsupported = errors.New("textextract: unsupported format")
+
+type Document struct {
+	Format      string
+	Text        string
+	PageOffsets []int
+}
+
+// PageCount is the number of pages, at least one.
+func (d Document) PageCount() int {
+	if len(d.PageOffsets) == 0 {
+		return 1
+	}
+	return len(d.PageOffsets)
+}
+
+// PageAt returns the 1-based page holding the rune at offset.
+func PageAt(pageOffsets []int, offset int) int {
+	page := 1
+	for i, start := range pageOffsets {
+		if offset >= start {
+			page = i + 1
+		}
+	}
+	return page
+}
+
+// Extract detects the format from the payload, falling back to the file
+// name and MIME type for plain text.
+func Extract(payload []byte, fileName string, mimeType string) (Document, error) {
+	switch {
+	case bytes.HasPrefix(payload, []byte("%PDF-")):
+		return extractPDF(payload)
+	case bytes.HasPrefix(payload, []byte("PK\x03\x04")):
+		return extractOffice(payload)
+	case isPlainText(payload, fileName, mimeType):
+		b := newPageBuilder()
+		b.startPage()
+		b.write(strings.TrimPrefix(string(payload), "\ufeff"))
+		return b.document(FormatText), nil
+	default:
+		return Document{}, ErrUnsupported
+	}
+}
+
+func isPlainText(payload []byte, fileName string, mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	textual := strings.HasPrefix(mimeType, "text/")
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".txt", ".csv", ".md", ".tsv", ".json", ".log":
+		textual = true
+	}
+	return textual && utf8.Valid(payload)
+}
+
+// pageBuilder accumulates text and page starts, counting runes so offsets
+// line up with character positions in the database.
+type pageBuilder struct {
+	text    strings.Builder
+	runes   int
+	offsets []int
+	full    bool
+}
+
+func newPageBuilder() *pageBuilder {
+	return &pageBuilder{}
+}
+
+// startPage opens a new page. Empty pages are kept so page numbers match
+// the source document.
+func (b *pageBuilder) startPage() {
+	if b.runes > 0 && !strings.HasSuffix(b.text.String(), "\n") {
+		b.write("\n")
+	}
+	b.offsets = append(b.offsets, b.runes)
+}
+
+// pageEmpty reports whether nothing has been written since the last page
+// start.
+func (b *pageBuilder) pageEmpty() bool {
+	n := len(b.offsets)
+	return n == 0 || b.offsets[n-1] == b.runes
+}
+
+func (b *pageBuilder) write(s string) {
+	if b.full || s == "" {
+		return
+	}
+	for _, r := range s {
+		if r == utf8.RuneError || (r < 0x20 && r != '\n' && r != '\t') {
+			continue
+		}
+		if b.runes >= MaxTextRunes {
+			b.full = true
+			return
+		}
+		b.text.WriteRune(r)
+		b.runes++
+	}
+}
+
+// document normalises whitespace page by page and recomputes the offsets.
+func (b *pageBuilder) document(format string) Document {
+	text := []rune(b.text.String())
+	offsets := b.offsets
+	if len(offsets) == 0 {
+		offsets = []int{0}
+	}
+	var out strings.Builder
+	normalized := make([]int, 0, len(offsets))
+	count := 0
+	for i, start := range offsets {
+		end := len(text)
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		page := normalizeWhitespace(string(text[start:end]))
+		if i > 0 {
+			out.WriteString("\n")
+			count++
+		}
+		normalized = append(normalized, count)
+		out.WriteString(page)
+		count += utf8.RuneCountInString(page)
+	}
+	return Document{Format: format, Text: out.String(), PageOffsets: normalized}
+}
+
+// normalizeWhitespace collapses runs of spaces and blank lines.
+func normalizeWhitespace(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(kept) > 0 {
+				kept = append(kept, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
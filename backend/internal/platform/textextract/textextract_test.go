@@ -0,0 +1,135 @@
+package textextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func deflate(data string) string {
+	var out bytes.Buffer
+	writer := zlib.NewWriter(&out)
+	_, _ = writer.Write([]byte(data))
+	_ = writer.Close()
+	return out.String()
+}
+
+// testPDF builds a two-page PDF: page one uses a simple font, page two a
+// Type0 font whose ToUnicode CMap maps 2-byte codes to Chinese text.
+func testPDF() []byte {
+	page1 := "BT /F1 12 Tf 72 720 Td (Quarterly revenue) Tj 0 -14 Td [(grew)-300(12%)] TJ ET"
+	page2 := "BT /F2 12 Tf 72 720 Td <00010002> Tj ET"
+	cmap := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"1 beginbfchar <0001> <8D35> endbfchar\n" +
+		"1 beginbfrange <0002> <0002> <5DDE> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 7 0 R /F2 8 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [6 0 R] >>",
+		fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(deflate(page1)), deflate(page1)),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(page2), page2),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H /ToUnicode 9 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(cmap), cmap),
+	}
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.5\n")
+	for i, body := range objects {
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	out.WriteString("trailer\n<< /Size 10 /Root 1 0 R >>\n%%EOF\n")
+	return out.Bytes()
+}
+
+func TestExtractPDFPagesAndToUnicode(t *testing.T) {
+	doc, err := Extract(testPDF(), "report.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if doc.Format != FormatPDF || len(doc.PageOffsets) != 2 {
+		t.Fatalf("expected two PDF pages, got %+v", doc)
+	}
+	if !strings.Contains(doc.Text, "Quarterly revenue\ngrew 12%") {
+		t.Fatalf("expected page one text, got %q", doc.Text)
+	}
+	index := strings.Index(doc.Text, "贵州")
+	if index < 0 {
+		t.Fatalf("expected CMap-mapped Chinese text, got %q", doc.Text)
+	}
+	offset := len([]rune(doc.Text[:index]))
+	if page := PageAt(doc.PageOffsets, offset); page != 2 {
+		t.Fatalf("expected match on page 2, got %d (offsets %v)", page, doc.PageOffsets)
+	}
+}
+
+func zipPackage(files map[string]string) []byte {
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for name, body := range files {
+		part, _ := writer.Create(name)
+		_, _ = part.Write([]byte(body))
+	}
+	_ = writer.Close()
+	return out.Bytes()
+}
+
+func TestExtractDOCXSplitsOnPageBreaks(t *testing.T) {
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Industry outlook</w:t></w:r></w:p>
+<w:p><w:r><w:br w:type="page"/><w:t xml:space="preserve">Risk </w:t></w:r><w:r><w:t>factors</w:t></w:r></w:p>
+<w:p><w:r><w:lastRenderedPageBreak/><w:t>Appendix</w:t></w:r></w:p>
+</w:body></w:document>`
+	doc, err := Extract(zipPackage(map[string]string{"word/document.xml": document}), "memo.docx", "")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if doc.Format != FormatDOCX || len(doc.PageOffsets) != 3 {
+		t.Fatalf("expected three DOCX pages, got %+v", doc)
+	}
+	if doc.Text != "Industry outlook\nRisk factors\nAppendix" {
+		t.Fatalf("unexpected text %q", doc.Text)
+	}
+	if page := PageAt(doc.PageOffsets, strings.Index(doc.Text, "Appendix")); page != 3 {
+		t.Fatalf("expected Appendix on page 3, got %d", page)
+	}
+}
+
+func TestExtractXLSXOnePagePerSheet(t *testing.T) {
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			`<sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Detail" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Target="worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":     `<sst><si><t>Ticker</t></si><si><r><t>600</t></r><r><t>519.SH</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData><row><c><v>1.25</v></c><c t="inlineStr"><is><t>target</t></is></c></row></sheetData></worksheet>`,
+	}
+	doc, err := Extract(zipPackage(files), "model.xlsx", "")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if doc.Format != FormatXLSX || doc.PageCount() != 2 {
+		t.Fatalf("expected two sheets, got %+v", doc)
+	}
+	if !strings.Contains(doc.Text, "Ticker 600519.SH") || !strings.Contains(doc.Text, "Detail\n1.25 target") {
+		t.Fatalf("unexpected text %q", doc.Text)
+	}
+}
+
+func TestExtractPlainTextAndUnsupported(t *testing.T) {
+	doc, err := Extract([]byte("\ufeffline one\n\n\n\nline   two"), "notes.txt", "")
+	if err != nil || doc.Text != "line one\n\nline two" || doc.PageCount() != 1 {
+		t.Fatalf("unexpected plain text result %+v %v", doc, err)
+	}
+	if _, err := Extract([]byte{0x89, 'P', 'N', 'G'}, "chart.png", "image/png"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package watermark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// Images get the label tiled across the picture in translucent grey using
+// a built-in 5x7 bitmap font, so no font files are needed. PNGs also carry
+// the token in a tEXt chunk and in the blue-channel LSBs of opaque pixels;
+// JPEGs carry it in a COM segment, since re-encoding destroys LSBs.
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+
+const (
+	imageStampAlpha  = 0.28
+	imageJPEGQuality = 92
+	lsbMaxTokenBytes = 512
+)
+
+func stampPNG(payload []byte, label string, token string) ([]byte, error) {
+	src, err := png.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	canvas := stampImage(src, label)
+	embedPixelToken(canvas, token)
+	var out bytes.Buffer
+	if err := png.Encode(&out, canvas); err != nil {
+		return nil, err
+	}
+	return insertPNGText(out.Bytes(), "Comment", token), nil
+}
+
+func stampJPEG(payload []byte, label string, token string) ([]byte, error) {
+	src, err := jpeg.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	canvas := stampImage(src, label)
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, canvas, &jpeg.Options{Quality: imageJPEGQuality}); err != nil {
+		return nil, err
+	}
+	return insertJPEGComment(out.Bytes(), token), nil
+}
+
+// stampImage tiles label over a copy of src, offsetting every other row so
+// cropping a corner doesn't remove it.
+func stampImage(src image.Image, label string) *image.NRGBA {
+	bounds := src.Bounds()
+	canvas := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), src, bounds.Min, draw.Src)
+
+	label = strings.ToUpper(label)
+	width, height := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+	scale := minInt(width, height) / 320
+	if scale < 1 {
+		scale = 1
+	}
+	textWidth := len(label) * (glyphWidth + 1) * scale
+	textHeight := glyphHeight * scale
+	stepX := textWidth + 12*textHeight
+	stepY := 8 * textHeight
+	for row, y := 0, textHeight; y < height; row, y = row+1, y+stepY {
+		offset := 0
+		if row%2 == 1 {
+			offset = stepX / 2
+		}
+		for x := textHeight - offset; x < width; x += stepX {
+			drawLabel(canvas, label, x, y, scale)
+		}
+	}
+	return canvas
+}
+
+func drawLabel(canvas *image.NRGBA, label string, x int, y int, scale int) {
+	for i := 0; i < len(label); i++ {
+		glyph := glyphFor(label[i])
+		gx := x + i*(glyphWidth+1)*scale
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]&(1<<uint(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						blendPixel(canvas, gx+col*scale+dx, y+row*scale+dy)
+					}
+				}
+			}
+		}
+	}
+}
+
+func blendPixel(canvas *image.NRGBA, x int, y int) {
+	if !(image.Point{X: x, Y: y}.In(canvas.Bounds())) {
+		return
+	}
+	offset := canvas.PixOffset(x, y)
+	for i := 0; i < 3; i++ {
+		value := float64(canvas.Pix[offset+i])
+		canvas.Pix[offset+i] = uint8(value*(1-imageStampAlpha) + 128*imageStampAlpha)
+	}
+}
+
+// embedPixelToken writes a 16-bit length and the token bytes into the blue
+// LSB of opaque pixels, in raster order. Translucent pixels are skipped
+// because their colour does not survive premultiplication on decode.
+func embedPixelToken(canvas *image.NRGBA, token string) {
+	if len(token) > lsbMaxTokenBytes {
+		return
+	}
+	message := make([]byte, 2+len(token))
+	binary.BigEndian.PutUint16(message, uint16(len(token)))
+	copy(message[2:], token)
+	bit := 0
+	total := len(message) * 8
+	for offset := 0; offset+3 < len(canvas.Pix) && bit < total; offset += 4 {
+		if canvas.Pix[offset+3] != 0xFF {
+			continue
+		}
+		value := (message[bit/8] >> uint(7-bit%8)) & 1
+		canvas.Pix[offset+2] = canvas.Pix[offset+2]&^1 | value
+		bit++
+	}
+}
+
+func extractPNGPixels(payload []byte) (string, bool) {
+	img, err := png.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return "", false
+	}
+	bounds := img.Bounds()
+	var bits []byte
+	read := func(n int) []byte {
+		out := make([]byte, 0, n)
+		var current byte
+		count := 0
+		for len(bits) > 0 && len(out) < n {
+			current = current<<1 | bits[0]
+			bits = bits[1:]
+			count++
+			if count == 8 {
+				out = append(out, current)
+				current, count = 0, 0
+			}
+		}
+		return out
+	}
+	limit := (2 + lsbMaxTokenBytes) * 8
+	for y := bounds.Min.Y; y < bounds.Max.Y && len(bits) < limit; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X && len(bits) < limit; x++ {
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if pixel.A != 0xFF {
+				continue
+			}
+			bits = append(bits, pixel.B&1)
+		}
+	}
+	header := read(2)
+	if len(header) < 2 {
+		return "", false
+	}
+	length := int(binary.BigEndian.Uint16(header))
+	if length == 0 || length > lsbMaxTokenBytes {
+		return "", false
+	}
+	token := tokenPattern.Find(read(length))
+	if token == nil {
+		return "", false
+	}
+	return string(token), true
+}
+
+// insertPNGText adds a tEXt chunk right after IHDR.
+func insertPNGText(encoded []byte, keyword string, text string) []byte {
+	ihdrEnd := len(pngSignature) + 8 + 13 + 4
+	if len(encoded) < ihdrEnd {
+		return encoded
+	}
+	data := append([]byte(keyword+"\x00"), text...)
+	chunk := make([]byte, 0, len(data)+12)
+	chunk = binary.BigEndian.AppendUint32(chunk, uint32(len(data)))
+	chunk = append(chunk, "tEXt"...)
+	chunk = append(chunk, data...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	out := make([]byte, 0, len(encoded)+len(chunk))
+	out = append(out, encoded[:ihdrEnd]...)
+	out = append(out, chunk...)
+	return append(out, encoded[ihdrEnd:]...)
+}
+
+// insertJPEGComment adds a COM segment right after SOI.
+func insertJPEGComment(encoded []byte, text string) []byte {
+	if len(encoded) < 2 || len(text)+2 > 0xFFFF {
+		return encoded
+	}
+	segment := []byte{0xFF, 0xFE}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(text)+2))
+	segment = append(segment, text...)
+	out := make([]byte, 0, len(encoded)+len(segment))
+	out = append(out, encoded[:2]...)
+	out = append(out, segment...)
+	return append(out, encoded[2:]...)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,319 @@
+package watermark
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PDFs are stamped with an incremental update appended after the original
+// bytes: a Stamp annotation with its own appearance stream is added to every
+// page object that can be located, so the page contents and resources are
+// never rewritten. Pages stored inside object streams and encrypted files
+// only get the invisible comment marker.
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfStartXref    = regexp.MustCompile(`startxref\s+(\d+)`)
+	pdfRootRef      = regexp.MustCompile(`/Root\s+(\d+\s+\d+\s+R)`)
+	pdfInfoRef      = regexp.MustCompile(`/Info\s+(\d+\s+\d+\s+R)`)
+	pdfSize         = regexp.MustCompile(`/Size\s+(\d+)`)
+	pdfPageType     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfMediaBox     = regexp.MustCompile(`/MediaBox\s*\[\s*(-?[\d.]+)\s+(-?[\d.]+)\s+(-?[\d.]+)\s+(-?[\d.]+)\s*\]`)
+)
+
+const (
+	pdfStampFontSize = 7.0
+	pdfStampHeight   = 12.0
+	pdfStampMargin   = 24.0
+	pdfDefaultWidth  = 612.0
+)
+
+type pdfObject struct {
+	num  int
+	gen  int
+	body string
+}
+
+func stampPDF(payload []byte, label string, token string) []byte {
+	out := bytes.NewBuffer(make([]byte, 0, len(payload)+4096))
+	out.Write(payload)
+	if !bytes.HasSuffix(payload, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	out.WriteString("%" + token + "\n")
+
+	tail := payload
+	if len(tail) > 4096 {
+		tail = tail[len(tail)-4096:]
+	}
+	prev := lastSubmatch(pdfStartXref, payload)
+	root := lastSubmatch(pdfRootRef, tail)
+	if prev == "" || root == "" || bytes.Contains(tail, []byte("/Encrypt")) {
+		return out.Bytes()
+	}
+	pages := pdfPageObjects(payload)
+	if len(pages) == 0 {
+		return out.Bytes()
+	}
+
+	nextNum := pdfNextObjectNumber(payload, tail)
+	fontNum, apNum := nextNum, nextNum+1
+	nextNum += 2
+	type pageStamp struct {
+		page     pdfObject
+		dict     string
+		annotNum int
+	}
+	stamps := make([]pageStamp, 0, len(pages))
+	for _, page := range pages {
+		dict, ok := pdfAppendAnnot(page.body, fmt.Sprintf("%d 0 R", nextNum))
+		if !ok {
+			continue
+		}
+		stamps = append(stamps, pageStamp{page: page, dict: dict, annotNum: nextNum})
+		nextNum++
+	}
+	if len(stamps) == 0 {
+		return out.Bytes()
+	}
+
+	offsets := map[int]int{}
+	gens := map[int]int{}
+	writeObject := func(num int, gen int, body string) {
+		offsets[num] = out.Len()
+		gens[num] = gen
+		fmt.Fprintf(out, "%d %d obj\n%s\nendobj\n", num, gen, body)
+	}
+	width := float64(len(label))*pdfStampFontSize*0.55 + 8
+	writeObject(fontNum, 0, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	content := fmt.Sprintf("q 0.45 g BT /Helv %.0f Tf 4 3.5 Td (%s) Tj ET Q", pdfStampFontSize, pdfEscapeString(label))
+	writeObject(apNum, 0, fmt.Sprintf(
+		"<< /Type /XObject /Subtype /Form /BBox [0 0 %.2f %.2f] /Resources << /Font << /Helv %d 0 R >> >> /Length %d >>\nstream\n%s\nendstream",
+		width, pdfStampHeight, fontNum, len(content), content,
+	))
+	for _, stamp := range stamps {
+		x0, y0, x1 := 0.0, 0.0, pdfDefaultWidth
+		if box := pdfMediaBox.FindStringSubmatch(stamp.page.body); box != nil {
+			x0, _ = strconv.ParseFloat(box[1], 64)
+			y0, _ = strconv.ParseFloat(box[2], 64)
+			x1, _ = strconv.ParseFloat(box[3], 64)
+		}
+		x := x0 + pdfStampMargin
+		if x+width > x1 {
+			x = x0
+		}
+		y := y0 + pdfStampMargin/2
+		writeObject(stamp.annotNum, 0, fmt.Sprintf(
+			"<< /Type /Annot /Subtype /Stamp /Rect [%.2f %.2f %.2f %.2f] /F 132 /NM (%s) /AP << /N %d 0 R >> >>",
+			x, y, x+width, y+pdfStampHeight, token, apNum,
+		))
+		writeObject(stamp.page.num, stamp.page.gen, stamp.dict)
+	}
+
+	nums := make([]int, 0, len(offsets))
+	for num := range offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	xrefOffset := out.Len()
+	out.WriteString("xref\n")
+	for _, num := range nums {
+		fmt.Fprintf(out, "%d 1\n%010d %05d n\r\n", num, offsets[num], gens[num])
+	}
+	trailer := fmt.Sprintf("/Size %d /Root %s /Prev %s", nextNum, root, prev)
+	if info := lastSubmatch(pdfInfoRef, tail); info != "" {
+		trailer += " /Info " + info
+	}
+	fmt.Fprintf(out, "trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xrefOffset)
+	return out.Bytes()
+}
+
+func lastSubmatch(pattern *regexp.Regexp, data []byte) string {
+	matches := pattern.FindAllSubmatch(data, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return string(matches[len(matches)-1][1])
+}
+
+// pdfPageObjects returns the latest revision of every uncompressed page
+// object, in object number order.
+func pdfPageObjects(payload []byte) []pdfObject {
+	latest := map[int]pdfObject{}
+	for _, loc := range pdfObjectHeader.FindAllSubmatchIndex(payload, -1) {
+		if loc[0] > 0 && !isPDFDelimiterOrSpace(payload[loc[0]-1]) {
+			continue
+		}
+		num, _ := strconv.Atoi(string(payload[loc[2]:loc[3]]))
+		gen, _ := strconv.Atoi(string(payload[loc[4]:loc[5]]))
+		rest := payload[loc[1]:]
+		end := bytes.Index(rest, []byte("endobj"))
+		if end < 0 {
+			continue
+		}
+		body := strings.TrimSpace(string(rest[:end]))
+		if !strings.HasPrefix(body, "<<") || !strings.HasSuffix(body, ">>") || strings.Contains(body, "stream") {
+			delete(latest, num)
+			continue
+		}
+		if !pdfPageType.MatchString(body) {
+			delete(latest, num)
+			continue
+		}
+		latest[num] = pdfObject{num: num, gen: gen, body: body}
+	}
+	pages := make([]pdfObject, 0, len(latest))
+	for _, page := range latest {
+		pages = append(pages, page)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].num < pages[j].num })
+	return pages
+}
+
+func pdfNextObjectNumber(payload []byte, tail []byte) int {
+	next := 0
+	if size := lastSubmatch(pdfSize, tail); size != "" {
+		next, _ = strconv.Atoi(size)
+	}
+	for _, match := range pdfObjectHeader.FindAllSubmatch(payload, -1) {
+		if num, err := strconv.Atoi(string(match[1])); err == nil && num+1 > next {
+			next = num + 1
+		}
+	}
+	return next
+}
+
+// pdfAppendAnnot adds ref to the page's /Annots. Pages whose /Annots is an
+// indirect array are left alone, since that array may be shared.
+func pdfAppendAnnot(dict string, ref string) (string, bool) {
+	valueStart := pdfFindTopLevelKey(dict, "/Annots")
+	if valueStart < 0 {
+		closing := strings.LastIndex(dict, ">>")
+		return strings.TrimRight(dict[:closing], " \t\r\n") + " /Annots [" + ref + "] >>", true
+	}
+	i := valueStart
+	for i < len(dict) && isPDFSpace(dict[i]) {
+		i++
+	}
+	if i >= len(dict) || dict[i] != '[' {
+		return "", false
+	}
+	end := pdfMatchingClose(dict, i)
+	if end < 0 {
+		return "", false
+	}
+	return dict[:end] + " " + ref + dict[end:], true
+}
+
+// pdfFindTopLevelKey returns the offset just past key when it is a key of
+// the outermost dictionary, or -1.
+func pdfFindTopLevelKey(dict string, key string) int {
+	depth := 0
+	for i := 0; i < len(dict); i++ {
+		switch c := dict[i]; c {
+		case '(':
+			i = pdfSkipString(dict, i)
+		case '%':
+			for i < len(dict) && dict[i] != '\n' && dict[i] != '\r' {
+				i++
+			}
+		case '<':
+			if i+1 < len(dict) && dict[i+1] == '<' {
+				depth++
+				i++
+				continue
+			}
+			for i < len(dict) && dict[i] != '>' {
+				i++
+			}
+		case '>':
+			if i+1 < len(dict) && dict[i+1] == '>' {
+				depth--
+				i++
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			end := i + 1
+			for end < len(dict) && !isPDFDelimiterOrSpace(dict[end]) {
+				end++
+			}
+			if depth == 1 && dict[i:end] == key {
+				return end
+			}
+			i = end - 1
+		}
+	}
+	return -1
+}
+
+// pdfMatchingClose returns the offset of the "]" closing the array opened
+// at start.
+func pdfMatchingClose(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			i = pdfSkipString(s, i)
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// pdfSkipString returns the offset of the ")" closing the literal string
+// opened at start, honouring escapes and balanced parentheses.
+func pdfSkipString(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+func pdfEscapeString(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r < 0x20 || r > 0x7E:
+			// Helvetica with WinAnsi only covers ASCII reliably here.
+			builder.WriteByte('?')
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiterOrSpace(c byte) bool {
+	return isPDFSpace(c) || strings.IndexByte("()<>[]{}/%", c) >= 0
+}
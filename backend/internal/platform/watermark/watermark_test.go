@@ -0,0 +1,146 @@
+package watermark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testMark() Mark {
+	return Mark{AttachmentID: "att_001", UserID: "u_1001", StampedAt: time.Unix(1760000000, 0)}
+}
+
+func TestSignParseRoundTripRejectsForgery(t *testing.T) {
+	token := Sign("secret", testMark())
+	mark, err := Parse("secret", token)
+	if err != nil || mark.AttachmentID != "att_001" || mark.UserID != "u_1001" || mark.StampedAt.Unix() != 1760000000 {
+		t.Fatalf("unexpected parse result %+v %v", mark, err)
+	}
+	if _, err := Parse("other-secret", token); !errors.Is(err, ErrInvalidMarker) {
+		t.Fatalf("expected a marker signed with another secret to be rejected, got %v", err)
+	}
+}
+
+// minimalPDF builds a two-page classic-xref PDF; the second page already
+// has an inline /Annots array.
+func minimalPDF() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Annots [ ] /Contents 5 0 R >>",
+		"<< /Length 0 >>\nstream\n\nendstream",
+	}
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f\r\n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n\r\n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+func TestStampPDFAddsAnnotationToEveryPage(t *testing.T) {
+	source := minimalPDF()
+	token := Sign("secret", testMark())
+	stamped, err := Stamp(source, "u_1001 ****5678 2025-10-09 08:53", token)
+	if err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	if !bytes.HasPrefix(stamped, source) {
+		t.Fatalf("expected an incremental update that keeps the original bytes")
+	}
+	update := string(stamped[len(source):])
+	if !strings.Contains(update, "3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Contents 5 0 R /Annots [8 0 R] >>") {
+		t.Fatalf("expected page 3 to gain an /Annots array, got:\n%s", update)
+	}
+	if !strings.Contains(update, "/Annots [  9 0 R]") {
+		t.Fatalf("expected page 4's inline /Annots to be extended, got:\n%s", update)
+	}
+	if !strings.Contains(update, "/Prev ") || !strings.Contains(update, "/Size 10") {
+		t.Fatalf("expected a chained trailer, got:\n%s", update)
+	}
+	extracted, err := Extract(stamped)
+	if err != nil || extracted != token {
+		t.Fatalf("expected to extract %s, got %s %v", token, extracted, err)
+	}
+}
+
+func TestStampPNGSurvivesTextChunkRemoval(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 200, 120))
+	for y := 0; y < 120; y++ {
+		for x := 0; x < 200; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	_ = png.Encode(&encoded, src)
+	token := Sign("secret", testMark())
+	stamped, err := Stamp(encoded.Bytes(), "u_1001 ****5678", token)
+	if err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	if got, err := Extract(stamped); err != nil || got != token {
+		t.Fatalf("expected tEXt marker, got %s %v", got, err)
+	}
+
+	// Re-encoding drops the tEXt chunk; the pixel marker must remain.
+	decoded, err := png.Decode(bytes.NewReader(stamped))
+	if err != nil {
+		t.Fatalf("decode stamped png: %v", err)
+	}
+	var reencoded bytes.Buffer
+	_ = png.Encode(&reencoded, decoded)
+	if bytes.Contains(reencoded.Bytes(), []byte("SCWM1.")) {
+		t.Fatalf("expected re-encoding to drop the text chunk")
+	}
+	if got, err := Extract(reencoded.Bytes()); err != nil || got != token {
+		t.Fatalf("expected pixel marker after re-encode, got %s %v", got, err)
+	}
+	stampedPixels := 0
+	for y := 0; y < 120; y++ {
+		for x := 0; x < 200; x++ {
+			before := src.RGBAAt(x, y)
+			after := color.RGBAModel.Convert(decoded.At(x, y)).(color.RGBA)
+			if before.R != after.R {
+				stampedPixels++
+			}
+		}
+	}
+	if stampedPixels == 0 {
+		t.Fatalf("expected the visible stamp to change pixels")
+	}
+}
+
+func TestStampJPEGCarriesComment(t *testing.T) {
+	var encoded bytes.Buffer
+	_ = jpeg.Encode(&encoded, image.NewGray(image.Rect(0, 0, 64, 64)), nil)
+	token := Sign("secret", testMark())
+	stamped, err := Stamp(encoded.Bytes(), "u_1001", token)
+	if err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(stamped)); err != nil {
+		t.Fatalf("stamped jpeg no longer decodes: %v", err)
+	}
+	if got, err := Extract(stamped); err != nil || got != token {
+		t.Fatalf("expected COM marker, got %s %v", got, err)
+	}
+	if _, err := Stamp([]byte("plain text"), "u_1001", token); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+}
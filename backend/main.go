@@ -18,7 +18,7 @@ func main() {
 	if uploadDir == "" {
 		uploadDir = "./uploads"
 	}
-	r.Static("/uploads", uploadDir)
+	router.RegisterPublicUploads(r, uploadDir)
 	r.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
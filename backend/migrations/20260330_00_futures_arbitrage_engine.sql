@@ -0,0 +1,233 @@
+SET @has_arbitrage_recos_z_score := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'z_score'
+);
+SET @sql_arbitrage_recos_z_score := IF(
+  @has_arbitrage_recos_z_score = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN z_score decimal(10,4) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_z_score FROM @sql_arbitrage_recos_z_score;
+EXECUTE stmt_arbitrage_recos_z_score;
+DEALLOCATE PREPARE stmt_arbitrage_recos_z_score;
+
+SET @has_arbitrage_recos_half_life := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'half_life'
+);
+SET @sql_arbitrage_recos_half_life := IF(
+  @has_arbitrage_recos_half_life = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN half_life decimal(10,2) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_half_life FROM @sql_arbitrage_recos_half_life;
+EXECUTE stmt_arbitrage_recos_half_life;
+DEALLOCATE PREPARE stmt_arbitrage_recos_half_life;
+
+SET @has_arbitrage_recos_spread_mean := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'spread_mean'
+);
+SET @sql_arbitrage_recos_spread_mean := IF(
+  @has_arbitrage_recos_spread_mean = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN spread_mean decimal(18,4) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_spread_mean FROM @sql_arbitrage_recos_spread_mean;
+EXECUTE stmt_arbitrage_recos_spread_mean;
+DEALLOCATE PREPARE stmt_arbitrage_recos_spread_mean;
+
+SET @has_arbitrage_recos_spread_std := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'spread_std'
+);
+SET @sql_arbitrage_recos_spread_std := IF(
+  @has_arbitrage_recos_spread_std = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN spread_std decimal(18,4) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_spread_std FROM @sql_arbitrage_recos_spread_std;
+EXECUTE stmt_arbitrage_recos_spread_std;
+DEALLOCATE PREPARE stmt_arbitrage_recos_spread_std;
+
+SET @has_arbitrage_recos_sample_days := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'sample_days'
+);
+SET @sql_arbitrage_recos_sample_days := IF(
+  @has_arbitrage_recos_sample_days = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN sample_days int NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_sample_days FROM @sql_arbitrage_recos_sample_days;
+EXECUTE stmt_arbitrage_recos_sample_days;
+DEALLOCATE PREPARE stmt_arbitrage_recos_sample_days;
+
+SET @has_arbitrage_recos_direction := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'direction'
+);
+SET @sql_arbitrage_recos_direction := IF(
+  @has_arbitrage_recos_direction = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN direction varchar(16) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_direction FROM @sql_arbitrage_recos_direction;
+EXECUTE stmt_arbitrage_recos_direction;
+DEALLOCATE PREPARE stmt_arbitrage_recos_direction;
+
+SET @has_arbitrage_recos_risk_level := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'risk_level'
+);
+SET @sql_arbitrage_recos_risk_level := IF(
+  @has_arbitrage_recos_risk_level = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN risk_level varchar(16) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_risk_level FROM @sql_arbitrage_recos_risk_level;
+EXECUTE stmt_arbitrage_recos_risk_level;
+DEALLOCATE PREPARE stmt_arbitrage_recos_risk_level;
+
+SET @has_arbitrage_recos_trade_date := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'trade_date'
+);
+SET @sql_arbitrage_recos_trade_date := IF(
+  @has_arbitrage_recos_trade_date = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN trade_date date NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_trade_date FROM @sql_arbitrage_recos_trade_date;
+EXECUTE stmt_arbitrage_recos_trade_date;
+DEALLOCATE PREPARE stmt_arbitrage_recos_trade_date;
+
+SET @has_arbitrage_recos_pair_key := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'pair_key'
+);
+SET @sql_arbitrage_recos_pair_key := IF(
+  @has_arbitrage_recos_pair_key = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN pair_key varchar(128) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_pair_key FROM @sql_arbitrage_recos_pair_key;
+EXECUTE stmt_arbitrage_recos_pair_key;
+DEALLOCATE PREPARE stmt_arbitrage_recos_pair_key;
+
+SET @has_arbitrage_recos_source := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'source'
+);
+SET @sql_arbitrage_recos_source := IF(
+  @has_arbitrage_recos_source = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN source varchar(16) NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_source FROM @sql_arbitrage_recos_source;
+EXECUTE stmt_arbitrage_recos_source;
+DEALLOCATE PREPARE stmt_arbitrage_recos_source;
+
+SET @has_arbitrage_recos_updated_at := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND COLUMN_NAME = 'updated_at'
+);
+SET @sql_arbitrage_recos_updated_at := IF(
+  @has_arbitrage_recos_updated_at = 0,
+  'ALTER TABLE arbitrage_recos ADD COLUMN updated_at datetime NULL',
+  'SELECT 1'
+);
+PREPARE stmt_arbitrage_recos_updated_at FROM @sql_arbitrage_recos_updated_at;
+EXECUTE stmt_arbitrage_recos_updated_at;
+DEALLOCATE PREPARE stmt_arbitrage_recos_updated_at;
+
+SET @has_idx_arbitrage_recos_trade_date := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'arbitrage_recos'
+    AND INDEX_NAME = 'idx_arbitrage_recos_trade_date'
+);
+SET @sql_idx_arbitrage_recos_trade_date := IF(
+  @has_idx_arbitrage_recos_trade_date = 0,
+  'ALTER TABLE arbitrage_recos ADD INDEX idx_arbitrage_recos_trade_date (source, trade_date, status)',
+  'SELECT 1'
+);
+PREPARE stmt_idx_arbitrage_recos_trade_date FROM @sql_idx_arbitrage_recos_trade_date;
+EXECUTE stmt_idx_arbitrage_recos_trade_date;
+DEALLOCATE PREPARE stmt_idx_arbitrage_recos_trade_date;
+
+CREATE TABLE IF NOT EXISTS futures_arbitrage_spread_snapshots (
+  id varchar(64) NOT NULL,
+  pair_key varchar(128) NOT NULL,
+  trade_date date NOT NULL,
+  spread decimal(18,4) NOT NULL,
+  z_score decimal(10,4) NOT NULL DEFAULT 0,
+  percentile decimal(6,4) NOT NULL DEFAULT 0,
+  spread_mean decimal(18,4) NOT NULL DEFAULT 0,
+  spread_std decimal(18,4) NOT NULL DEFAULT 0,
+  created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_futures_arbitrage_spread_snapshots_pair_date (pair_key, trade_date),
+  KEY idx_futures_arbitrage_spread_snapshots_trade_date (trade_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_futures_arbitrage_compute', 'futures_arbitrage_compute', '期货套利价差计算', 'FUTURES', '0 40 17 * * *', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_futures_arbitrage_enabled', 'futures.arbitrage.enabled', 'true', '期货套利价差计算开关', 'system', NOW()),
+  ('cfg_futures_arbitrage_lookback_days', 'futures.arbitrage.lookback_days', '120', '价差分布回看交易日数', 'system', NOW()),
+  ('cfg_futures_arbitrage_min_samples', 'futures.arbitrage.min_samples', '20', '计算价差统计所需最少样本数', 'system', NOW()),
+  ('cfg_futures_arbitrage_entry_z', 'futures.arbitrage.entry_z', '2.0', '入场z-score阈值', 'system', NOW()),
+  ('cfg_futures_arbitrage_exit_z', 'futures.arbitrage.exit_z', '0.5', '离场z-score阈值', 'system', NOW()),
+  ('cfg_futures_arbitrage_stop_z', 'futures.arbitrage.stop_z', '3.0', '止损z-score阈值', 'system', NOW()),
+  ('cfg_futures_arbitrage_calendar_enabled', 'futures.arbitrage.calendar_enabled', 'true', '是否计算主力/次主力跨期价差', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,77 @@
+-- Hash-chained ledger over admin_operation_logs and admin_audit_events.
+-- payload_json is stored as text so the hashed bytes survive round-trips unchanged.
+CREATE TABLE IF NOT EXISTS admin_audit_ledger (
+  seq bigint NOT NULL,
+  source_type varchar(32) NOT NULL,
+  source_id varchar(64) NOT NULL,
+  action varchar(16) NOT NULL,
+  payload_json longtext NOT NULL,
+  payload_hash char(64) NOT NULL,
+  prev_hash char(64) NOT NULL,
+  entry_hash char(64) NOT NULL,
+  created_at datetime NOT NULL,
+  PRIMARY KEY (seq),
+  KEY idx_admin_audit_ledger_source (source_type, source_id, seq),
+  KEY idx_admin_audit_ledger_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS admin_audit_ledger_head (
+  id varchar(16) NOT NULL,
+  last_seq bigint NOT NULL DEFAULT 0,
+  last_hash char(64) NOT NULL,
+  updated_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO admin_audit_ledger_head (id, last_seq, last_hash, updated_at)
+VALUES ('HEAD', 0, REPEAT('0', 64), NOW());
+
+CREATE TABLE IF NOT EXISTS admin_audit_checkpoints (
+  id varchar(32) NOT NULL,
+  seq bigint NOT NULL,
+  entry_hash char(64) NOT NULL,
+  entry_count bigint NOT NULL DEFAULT 0,
+  algorithm varchar(16) NOT NULL,
+  key_id varchar(32) NOT NULL,
+  public_key varchar(128) NOT NULL,
+  signature varchar(256) NOT NULL,
+  created_by varchar(64) NOT NULL,
+  created_at datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_admin_audit_checkpoints_seq (seq)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO rbac_permissions (code, name, module, action, description, status, created_at, updated_at)
+VALUES
+  ('audit.edit', 'Audit Edit', 'AUDIT', 'EDIT', 'create signed audit ledger checkpoints', 'ACTIVE', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  name = VALUES(name),
+  module = VALUES(module),
+  action = VALUES(action),
+  description = VALUES(description),
+  status = VALUES(status),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO rbac_role_permissions (role_id, permission_code, created_at)
+SELECT 'role_super_admin', p.code, NOW()
+FROM rbac_permissions p
+WHERE p.code IN ('audit.edit')
+ON DUPLICATE KEY UPDATE created_at = VALUES(created_at);
+
+INSERT INTO rbac_role_permissions (role_id, permission_code, created_at)
+SELECT 'role_ops_admin', p.code, NOW()
+FROM rbac_permissions p
+WHERE p.code IN ('audit.edit')
+ON DUPLICATE KEY UPDATE created_at = VALUES(created_at);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_audit_ledger_checkpoint', 'audit_ledger_checkpoint', '审计链签名检查点', 'SYSTEM', 'EVERY_60_MINUTES', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
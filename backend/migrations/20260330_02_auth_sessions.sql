@@ -0,0 +1,34 @@
+-- Server-side login sessions keyed by the jti shared by a login's access and refresh tokens
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+  id            varchar(64) PRIMARY KEY,
+  user_id       varchar(32) NOT NULL,
+  role          varchar(32) NOT NULL,
+  status        varchar(16) NOT NULL DEFAULT 'ACTIVE',
+  device        varchar(255) NOT NULL DEFAULT '',
+  ip            varchar(64) NOT NULL DEFAULT '',
+  created_at    datetime NOT NULL,
+  last_seen_at  datetime NOT NULL,
+  expires_at    datetime NOT NULL,
+  revoked_at    datetime NULL,
+  revoked_by    varchar(64) NULL,
+  revoke_reason varchar(64) NULL,
+  INDEX idx_auth_sessions_user_status (user_id, status),
+  INDEX idx_auth_sessions_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @has_refresh_tokens_session_id := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'refresh_tokens'
+    AND COLUMN_NAME = 'session_id'
+);
+SET @sql_refresh_tokens_session_id := IF(
+  @has_refresh_tokens_session_id = 0,
+  'ALTER TABLE refresh_tokens ADD COLUMN session_id varchar(64) NULL AFTER user_id, ADD INDEX idx_refresh_tokens_session_id (session_id)',
+  'SELECT 1'
+);
+PREPARE stmt_refresh_tokens_session_id FROM @sql_refresh_tokens_session_id;
+EXECUTE stmt_refresh_tokens_session_id;
+DEALLOCATE PREPARE stmt_refresh_tokens_session_id;
@@ -0,0 +1,67 @@
+-- TOTP two-factor authentication for accounts holding RBAC roles
+
+CREATE TABLE IF NOT EXISTS auth_user_mfa (
+  user_id        varchar(32) PRIMARY KEY,
+  secret         varchar(512) NOT NULL,
+  status         varchar(16) NOT NULL DEFAULT 'PENDING',
+  last_used_step bigint NOT NULL DEFAULT 0,
+  enabled_at     datetime NULL,
+  created_at     datetime NOT NULL,
+  updated_at     datetime NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS auth_mfa_recovery_codes (
+  id         varchar(64) PRIMARY KEY,
+  user_id    varchar(32) NOT NULL,
+  code_hash  varchar(64) NOT NULL,
+  used_at    datetime NULL,
+  created_at datetime NOT NULL,
+  INDEX idx_auth_mfa_recovery_codes_user (user_id, code_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS auth_mfa_challenges (
+  id             varchar(64) PRIMARY KEY,
+  user_id        varchar(32) NOT NULL,
+  token_hash     varchar(64) NOT NULL UNIQUE,
+  challenge_type varchar(16) NOT NULL,
+  expires_in     int NOT NULL,
+  attempts       int NOT NULL DEFAULT 0,
+  ip             varchar(64) NOT NULL DEFAULT '',
+  consumed_at    datetime NULL,
+  expires_at     datetime NOT NULL,
+  created_at     datetime NOT NULL,
+  INDEX idx_auth_mfa_challenges_user (user_id, consumed_at),
+  INDEX idx_auth_mfa_challenges_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @has_rbac_roles_require_2fa := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'rbac_roles'
+    AND COLUMN_NAME = 'require_2fa'
+);
+SET @sql_rbac_roles_require_2fa := IF(
+  @has_rbac_roles_require_2fa = 0,
+  'ALTER TABLE rbac_roles ADD COLUMN require_2fa tinyint(1) NOT NULL DEFAULT 0 AFTER built_in',
+  'SELECT 1'
+);
+PREPARE stmt_rbac_roles_require_2fa FROM @sql_rbac_roles_require_2fa;
+EXECUTE stmt_rbac_roles_require_2fa;
+DEALLOCATE PREPARE stmt_rbac_roles_require_2fa;
+
+SET @has_auth_sessions_mfa_verified_at := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'auth_sessions'
+    AND COLUMN_NAME = 'mfa_verified_at'
+);
+SET @sql_auth_sessions_mfa_verified_at := IF(
+  @has_auth_sessions_mfa_verified_at = 0,
+  'ALTER TABLE auth_sessions ADD COLUMN mfa_verified_at datetime NULL AFTER last_seen_at',
+  'SELECT 1'
+);
+PREPARE stmt_auth_sessions_mfa_verified_at FROM @sql_auth_sessions_mfa_verified_at;
+EXECUTE stmt_auth_sessions_mfa_verified_at;
+DEALLOCATE PREPARE stmt_auth_sessions_mfa_verified_at;
@@ -0,0 +1,76 @@
+-- Payment-time invite attribution and tiered referral commissions
+
+SET @has_invite_records_first_pay_order_id := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'invite_records'
+    AND COLUMN_NAME = 'first_pay_order_id'
+);
+SET @sql_invite_records_first_pay_order_id := IF(
+  @has_invite_records_first_pay_order_id = 0,
+  'ALTER TABLE invite_records ADD COLUMN first_pay_order_id varchar(32) NULL AFTER first_pay_at, ADD INDEX idx_invite_records_first_pay_order (first_pay_order_id)',
+  'SELECT 1'
+);
+PREPARE stmt_invite_records_first_pay_order_id FROM @sql_invite_records_first_pay_order_id;
+EXECUTE stmt_invite_records_first_pay_order_id;
+DEALLOCATE PREPARE stmt_invite_records_first_pay_order_id;
+
+SET @has_share_reward_records_order_id := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'share_reward_records'
+    AND COLUMN_NAME = 'order_id'
+);
+SET @sql_share_reward_records_order_id := IF(
+  @has_share_reward_records_order_id = 0,
+  'ALTER TABLE share_reward_records ADD COLUMN order_id varchar(32) NULL AFTER invite_record_id, ADD COLUMN referral_level int NULL AFTER order_id, ADD UNIQUE KEY uk_share_reward_order_level (order_id, referral_level)',
+  'SELECT 1'
+);
+PREPARE stmt_share_reward_records_order_id FROM @sql_share_reward_records_order_id;
+EXECUTE stmt_share_reward_records_order_id;
+DEALLOCATE PREPARE stmt_share_reward_records_order_id;
+
+SET @has_share_reward_records_available_at := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'share_reward_records'
+    AND COLUMN_NAME = 'available_at'
+);
+SET @sql_share_reward_records_available_at := IF(
+  @has_share_reward_records_available_at = 0,
+  'ALTER TABLE share_reward_records ADD COLUMN available_at datetime NULL AFTER issued_at, ADD COLUMN reversed_at datetime NULL AFTER available_at, ADD INDEX idx_share_reward_status_available (status, available_at)',
+  'SELECT 1'
+);
+PREPARE stmt_share_reward_records_available_at FROM @sql_share_reward_records_available_at;
+EXECUTE stmt_share_reward_records_available_at;
+DEALLOCATE PREPARE stmt_share_reward_records_available_at;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+SELECT
+  'cfg_invite_commission_rules',
+  'invite.commission.rules',
+  '{"holding_days":7,"recurring_days":365,"levels":[{"level":1,"first_pay":{"mode":"PERCENT","value":10},"recurring":{"mode":"PERCENT","value":5},"cap":200},{"level":2,"first_pay":{"mode":"PERCENT","value":3},"recurring":{"mode":"PERCENT","value":0},"cap":50}]}',
+  '邀请付费佣金规则(分级比例/固定金额、封顶、冻结期)',
+  'system',
+  NOW()
+FROM DUAL
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM system_configs
+  WHERE config_key = 'invite.commission.rules'
+);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_invite_commission_release', 'invite_commission_release', '邀请佣金解冻', 'SYSTEM', 'EVERY_60_MINUTES', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,39 @@
+-- Membership refunds with prorated VIP rollback
+
+CREATE TABLE IF NOT EXISTS membership_refunds (
+  id                varchar(32) PRIMARY KEY,
+  order_id          varchar(32) NOT NULL,
+  order_no          varchar(64) NOT NULL,
+  user_id           varchar(32) NOT NULL,
+  pay_channel       varchar(32) NOT NULL DEFAULT '',
+  refund_channel    varchar(32) NOT NULL,
+  order_amount      decimal(10,2) NOT NULL,
+  amount            decimal(10,2) NOT NULL,
+  unused_seconds    bigint NOT NULL DEFAULT 0,
+  status            varchar(16) NOT NULL,
+  channel_refund_no varchar(128) NULL,
+  failure_reason    varchar(255) NULL,
+  reason            varchar(255) NOT NULL DEFAULT '',
+  operator          varchar(64) NOT NULL DEFAULT '',
+  created_at        datetime NOT NULL,
+  updated_at        datetime NOT NULL,
+  completed_at      datetime NULL,
+  INDEX idx_membership_refunds_order (order_id, status),
+  INDEX idx_membership_refunds_status_created (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @has_membership_orders_vip_period_start := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND COLUMN_NAME = 'vip_period_start'
+);
+SET @sql_membership_orders_vip_period_start := IF(
+  @has_membership_orders_vip_period_start = 0,
+  'ALTER TABLE membership_orders ADD COLUMN vip_period_start datetime NULL AFTER paid_at, ADD COLUMN vip_period_end datetime NULL AFTER vip_period_start, ADD INDEX idx_membership_orders_user_period (user_id, status, vip_period_start)',
+  'SELECT 1'
+);
+PREPARE stmt_membership_orders_vip_period_start FROM @sql_membership_orders_vip_period_start;
+EXECUTE stmt_membership_orders_vip_period_start;
+DEALLOCATE PREPARE stmt_membership_orders_vip_period_start;
@@ -0,0 +1,8 @@
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_payment_sandbox_outcome', 'payment.channel.sandbox.outcome', 'SUCCESS', '沙箱支付模拟结果(SUCCESS/FAILURE/DELAYED)，生产环境不可用', 'system', NOW()),
+  ('cfg_payment_sandbox_delay_seconds', 'payment.channel.sandbox.delay_seconds', '5', '沙箱支付延迟回调秒数(DELAYED)', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,55 @@
+-- Active status polling and TTL expiry for pending membership orders
+
+CREATE TABLE IF NOT EXISTS payment_order_poll_logs (
+  id             varchar(32) PRIMARY KEY,
+  order_id       varchar(32) NOT NULL,
+  order_no       varchar(64) NOT NULL,
+  pay_channel    varchar(32) NOT NULL DEFAULT '',
+  result         varchar(16) NOT NULL,
+  trade_status   varchar(16) NULL,
+  channel_txn_no varchar(64) NULL,
+  message        varchar(255) NULL,
+  created_at     datetime NOT NULL,
+  INDEX idx_payment_order_poll_logs_order (order_no, created_at),
+  INDEX idx_payment_order_poll_logs_result (result, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @has_mo_status_created_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND INDEX_NAME = 'idx_membership_orders_status_created'
+);
+SET @sql_mo_status_created_idx := IF(
+  @has_mo_status_created_idx = 0,
+  'ALTER TABLE membership_orders ADD INDEX idx_membership_orders_status_created (status, created_at)',
+  'SELECT 1'
+);
+PREPARE stmt_mo_status_created_idx FROM @sql_mo_status_created_idx;
+EXECUTE stmt_mo_status_created_idx;
+DEALLOCATE PREPARE stmt_mo_status_created_idx;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_payment_order_poll_enabled', 'payment.order_poll.enabled', 'true', '待支付订单主动查单开关', 'system', NOW()),
+  ('cfg_payment_order_poll_interval', 'payment.order_poll.interval_minutes', '5', '待支付订单查单间隔(分钟)', 'system', NOW()),
+  ('cfg_payment_order_poll_after', 'payment.order_poll.after_minutes', '5', '订单创建多少分钟后开始查单', 'system', NOW()),
+  ('cfg_payment_order_poll_ttl', 'payment.order_poll.ttl_minutes', '30', '未支付订单超时关闭(分钟)', 'system', NOW()),
+  ('cfg_payment_order_poll_batch', 'payment.order_poll.batch_size', '100', '每轮查单订单数', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_membership_order_poll', 'membership_order_poll', '待支付订单查单与超时关闭', 'SYSTEM', 'EVERY_5_MINUTES', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
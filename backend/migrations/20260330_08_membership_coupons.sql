@@ -0,0 +1,123 @@
+-- Coupons, promo codes and introductory pricing for membership products
+
+CREATE TABLE IF NOT EXISTS membership_coupons (
+  id                  varchar(32) PRIMARY KEY,
+  code                varchar(32) NOT NULL,
+  name                varchar(64) NOT NULL DEFAULT '',
+  discount_type       varchar(16) NOT NULL,
+  discount_value      decimal(10,2) NOT NULL,
+  max_discount        decimal(10,2) NOT NULL DEFAULT 0,
+  min_amount          decimal(10,2) NOT NULL DEFAULT 0,
+  valid_from          datetime NULL,
+  valid_to            datetime NULL,
+  total_limit         int NOT NULL DEFAULT 0,
+  per_user_limit      int NOT NULL DEFAULT 1,
+  product_ids         varchar(512) NOT NULL DEFAULT '',
+  member_levels       varchar(128) NOT NULL DEFAULT '',
+  first_purchase_only tinyint(1) NOT NULL DEFAULT 0,
+  source              varchar(16) NOT NULL DEFAULT 'CAMPAIGN',
+  owner_user_id       varchar(32) NULL,
+  invite_record_id    varchar(32) NULL,
+  status              varchar(16) NOT NULL DEFAULT 'ACTIVE',
+  created_by          varchar(32) NOT NULL DEFAULT '',
+  created_at          datetime NOT NULL,
+  updated_at          datetime NOT NULL,
+  UNIQUE KEY uk_membership_coupons_code (code),
+  INDEX idx_membership_coupons_owner (owner_user_id, status),
+  INDEX idx_membership_coupons_source_status (source, status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS membership_coupon_redemptions (
+  id              varchar(32) PRIMARY KEY,
+  coupon_id       varchar(32) NOT NULL,
+  coupon_code     varchar(32) NOT NULL,
+  user_id         varchar(32) NOT NULL,
+  order_id        varchar(32) NOT NULL,
+  order_no        varchar(64) NOT NULL,
+  product_id      varchar(32) NOT NULL,
+  original_amount decimal(10,2) NOT NULL,
+  discount_amount decimal(10,2) NOT NULL,
+  amount          decimal(10,2) NOT NULL,
+  status          varchar(16) NOT NULL DEFAULT 'RESERVED',
+  created_at      datetime NOT NULL,
+  redeemed_at     datetime NULL,
+  updated_at      datetime NOT NULL,
+  UNIQUE KEY uk_membership_coupon_redemptions_order (order_id),
+  INDEX idx_membership_coupon_redemptions_coupon (coupon_id, status),
+  INDEX idx_membership_coupon_redemptions_user (coupon_id, user_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @has_mo_original_amount := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND COLUMN_NAME = 'original_amount'
+);
+SET @sql_mo_original_amount := IF(
+  @has_mo_original_amount = 0,
+  'ALTER TABLE membership_orders ADD COLUMN original_amount decimal(10,2) NULL AFTER amount',
+  'SELECT 1'
+);
+PREPARE stmt_mo_original_amount FROM @sql_mo_original_amount;
+EXECUTE stmt_mo_original_amount;
+DEALLOCATE PREPARE stmt_mo_original_amount;
+
+SET @has_mo_discount_amount := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND COLUMN_NAME = 'discount_amount'
+);
+SET @sql_mo_discount_amount := IF(
+  @has_mo_discount_amount = 0,
+  'ALTER TABLE membership_orders ADD COLUMN discount_amount decimal(10,2) NOT NULL DEFAULT 0 AFTER original_amount',
+  'SELECT 1'
+);
+PREPARE stmt_mo_discount_amount FROM @sql_mo_discount_amount;
+EXECUTE stmt_mo_discount_amount;
+DEALLOCATE PREPARE stmt_mo_discount_amount;
+
+SET @has_mo_coupon_code := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND COLUMN_NAME = 'coupon_code'
+);
+SET @sql_mo_coupon_code := IF(
+  @has_mo_coupon_code = 0,
+  'ALTER TABLE membership_orders ADD COLUMN coupon_code varchar(32) NULL AFTER discount_amount',
+  'SELECT 1'
+);
+PREPARE stmt_mo_coupon_code FROM @sql_mo_coupon_code;
+EXECUTE stmt_mo_coupon_code;
+DEALLOCATE PREPARE stmt_mo_coupon_code;
+
+UPDATE membership_orders SET original_amount = amount WHERE original_amount IS NULL;
+
+SET @has_mo_coupon_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND INDEX_NAME = 'idx_membership_orders_coupon_paid'
+);
+SET @sql_mo_coupon_idx := IF(
+  @has_mo_coupon_idx = 0,
+  'ALTER TABLE membership_orders ADD INDEX idx_membership_orders_coupon_paid (coupon_code, status, paid_at)',
+  'SELECT 1'
+);
+PREPARE stmt_mo_coupon_idx FROM @sql_mo_coupon_idx;
+EXECUTE stmt_mo_coupon_idx;
+DEALLOCATE PREPARE stmt_mo_coupon_idx;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_coupon_invitee_template', 'coupon.invitee.template_code', '', '邀请注册发放给被邀请人的优惠券模板码(留空不发放)', 'system', NOW()),
+  ('cfg_coupon_invitee_valid_days', 'coupon.invitee.valid_days', '30', '被邀请人优惠券有效天数', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
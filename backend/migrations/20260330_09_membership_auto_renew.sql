@@ -0,0 +1,80 @@
+-- Auto-renewing membership plans with renewal reminders and dunning
+
+CREATE TABLE IF NOT EXISTS membership_auto_renewals (
+  id                 varchar(32) PRIMARY KEY,
+  user_id            varchar(32) NOT NULL,
+  product_id         varchar(32) NOT NULL,
+  pay_channel        varchar(32) NOT NULL,
+  agreement_no       varchar(64) NOT NULL,
+  status             varchar(16) NOT NULL DEFAULT 'ACTIVE',
+  current_period_end datetime NULL,
+  next_charge_at     datetime NULL,
+  failed_attempts    int NOT NULL DEFAULT 0,
+  grace_until        datetime NULL,
+  reminded_at        datetime NULL,
+  last_order_no      varchar(64) NULL,
+  last_error         varchar(255) NULL,
+  canceled_at        datetime NULL,
+  created_at         datetime NOT NULL,
+  updated_at         datetime NOT NULL,
+  UNIQUE KEY uk_membership_auto_renewals_user (user_id),
+  INDEX idx_membership_auto_renewals_due (status, next_charge_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS membership_renewal_attempts (
+  id             varchar(32) PRIMARY KEY,
+  renewal_id     varchar(32) NOT NULL,
+  user_id        varchar(32) NOT NULL,
+  order_no       varchar(64) NULL,
+  pay_channel    varchar(32) NOT NULL DEFAULT '',
+  attempt        int NOT NULL DEFAULT 1,
+  result         varchar(16) NOT NULL,
+  trade_status   varchar(16) NULL,
+  channel_txn_no varchar(64) NULL,
+  message        varchar(255) NULL,
+  created_at     datetime NOT NULL,
+  INDEX idx_membership_renewal_attempts_renewal (renewal_id, created_at),
+  INDEX idx_membership_renewal_attempts_result (result, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @has_mo_order_source := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'membership_orders'
+    AND COLUMN_NAME = 'order_source'
+);
+SET @sql_mo_order_source := IF(
+  @has_mo_order_source = 0,
+  'ALTER TABLE membership_orders ADD COLUMN order_source varchar(16) NOT NULL DEFAULT ''USER'' AFTER pay_channel',
+  'SELECT 1'
+);
+PREPARE stmt_mo_order_source FROM @sql_mo_order_source;
+EXECUTE stmt_mo_order_source;
+DEALLOCATE PREPARE stmt_mo_order_source;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_membership_auto_renew_enabled', 'membership.auto_renew.enabled', 'true', '会员自动续费开关', 'system', NOW()),
+  ('cfg_membership_auto_renew_interval', 'membership.auto_renew.interval_minutes', '30', '自动续费任务间隔(分钟)', 'system', NOW()),
+  ('cfg_membership_auto_renew_lead', 'membership.auto_renew.lead_hours', '24', '到期前多少小时发起续费扣款', 'system', NOW()),
+  ('cfg_membership_auto_renew_remind', 'membership.auto_renew.remind_hours', '72', '到期前多少小时发送续费提醒', 'system', NOW()),
+  ('cfg_membership_auto_renew_retry', 'membership.auto_renew.retry_hours', '6,24,48', '扣款失败后的重试间隔(小时,逗号分隔)', 'system', NOW()),
+  ('cfg_membership_auto_renew_grace', 'membership.auto_renew.grace_days', '3', '扣款失败后的会员宽限期(天)', 'system', NOW()),
+  ('cfg_membership_auto_renew_batch', 'membership.auto_renew.batch_size', '100', '每轮处理的续费数', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_membership_auto_renew', 'membership_auto_renew', '会员自动续费与催缴', 'SYSTEM', 'EVERY_30_MINUTES', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
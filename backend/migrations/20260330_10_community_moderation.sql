@@ -0,0 +1,70 @@
+-- Pre-publish moderation for community topics and comments
+
+CREATE TABLE IF NOT EXISTS community_sensitive_words (
+  id         varchar(32) PRIMARY KEY,
+  word       varchar(64) NOT NULL,
+  category   varchar(32) NOT NULL DEFAULT 'GENERAL',
+  status     varchar(16) NOT NULL DEFAULT 'ACTIVE',
+  created_by varchar(32) NULL,
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL,
+  UNIQUE KEY uk_community_sensitive_words_word (word),
+  INDEX idx_community_sensitive_words_status (status, category)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS community_moderation_flags (
+  id          varchar(32) PRIMARY KEY,
+  user_id     varchar(32) NOT NULL,
+  target_type varchar(16) NOT NULL,
+  target_id   varchar(64) NOT NULL,
+  reasons     json NOT NULL,
+  trust_score int NOT NULL DEFAULT 0,
+  status      varchar(16) NOT NULL DEFAULT 'PENDING',
+  created_at  datetime NOT NULL,
+  reviewed_at datetime NULL,
+  INDEX idx_community_moderation_flags_target (target_type, target_id),
+  INDEX idx_community_moderation_flags_user (user_id, status, created_at),
+  INDEX idx_community_moderation_flags_status (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS community_user_bans (
+  id              varchar(32) PRIMARY KEY,
+  user_id         varchar(32) NOT NULL,
+  reason          varchar(255) NOT NULL,
+  violation_count int NOT NULL DEFAULT 0,
+  status          varchar(16) NOT NULL DEFAULT 'ACTIVE',
+  expires_at      datetime NOT NULL,
+  created_by      varchar(32) NOT NULL,
+  lifted_by       varchar(32) NULL,
+  lifted_at       datetime NULL,
+  created_at      datetime NOT NULL,
+  INDEX idx_community_user_bans_user (user_id, status, expires_at),
+  INDEX idx_community_user_bans_status (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO community_sensitive_words (id, word, category, status, created_by, created_at, updated_at)
+VALUES
+  ('csw_seed_001', '荐股', 'STOCK_TIP', 'ACTIVE', 'system', NOW(), NOW()),
+  ('csw_seed_002', '带单', 'STOCK_TIP', 'ACTIVE', 'system', NOW(), NOW()),
+  ('csw_seed_003', '内幕消息', 'STOCK_TIP', 'ACTIVE', 'system', NOW(), NOW()),
+  ('csw_seed_004', '稳赚不赔', 'STOCK_TIP', 'ACTIVE', 'system', NOW(), NOW()),
+  ('csw_seed_005', '保本保收益', 'STOCK_TIP', 'ACTIVE', 'system', NOW(), NOW()),
+  ('csw_seed_006', '拉你进群', 'STOCK_TIP', 'ACTIVE', 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  updated_at = updated_at;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_community_moderation_enabled', 'community.moderation.enabled', 'true', '社区发帖前置审核开关', 'system', NOW()),
+  ('cfg_community_moderation_topics_hour', 'community.moderation.topics_per_hour', '5', '每用户每小时最多发帖数', 'system', NOW()),
+  ('cfg_community_moderation_comments_min', 'community.moderation.comments_per_minute', '3', '每用户每分钟最多评论数', 'system', NOW()),
+  ('cfg_community_moderation_comments_hour', 'community.moderation.comments_per_hour', '60', '每用户每小时最多评论数', 'system', NOW()),
+  ('cfg_community_moderation_low_trust', 'community.moderation.low_trust_score', '20', '信任分低于该值的内容全部进入人工审核', 'system', NOW()),
+  ('cfg_community_moderation_link_trust', 'community.moderation.link_trust_score', '60', '信任分低于该值时外链进入人工审核', 'system', NOW()),
+  ('cfg_community_moderation_ban_threshold', 'community.moderation.ban_threshold', '3', '统计窗口内违规达到该次数自动禁言', 'system', NOW()),
+  ('cfg_community_moderation_ban_window', 'community.moderation.ban_window_days', '30', '违规次数统计窗口(天)', 'system', NOW()),
+  ('cfg_community_moderation_ban_days', 'community.moderation.ban_days', '7', '自动禁言时长(天)', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,42 @@
+-- Daily crowd sentiment per stock symbol / futures product from community topics
+
+CREATE TABLE IF NOT EXISTS community_sentiment_daily (
+  asset_class     varchar(16) NOT NULL,
+  symbol          varchar(32) NOT NULL,
+  stat_date       date NOT NULL,
+  window_days     int NOT NULL,
+  topic_count     int NOT NULL DEFAULT 0,
+  author_count    int NOT NULL DEFAULT 0,
+  bullish_weight  decimal(12,4) NOT NULL DEFAULT 0,
+  bearish_weight  decimal(12,4) NOT NULL DEFAULT 0,
+  watch_weight    decimal(12,4) NOT NULL DEFAULT 0,
+  sentiment_score decimal(6,4) NOT NULL DEFAULT 0,
+  extreme         varchar(16) NOT NULL DEFAULT '',
+  updated_at      datetime NOT NULL,
+  PRIMARY KEY (asset_class, symbol, stat_date),
+  INDEX idx_community_sentiment_daily_date (stat_date, asset_class)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_community_sentiment_enabled', 'community.sentiment.enabled', 'true', '社区情绪指数任务开关', 'system', NOW()),
+  ('cfg_community_sentiment_interval', 'community.sentiment.interval_minutes', '60', '社区情绪指数任务间隔(分钟)', 'system', NOW()),
+  ('cfg_community_sentiment_window', 'community.sentiment.window_days', '5', '情绪指数统计窗口(天)', 'system', NOW()),
+  ('cfg_community_sentiment_extreme', 'community.sentiment.extreme_score', '0.6', '情绪分绝对值达到该值标记为极端(反向信号)', 'system', NOW()),
+  ('cfg_community_sentiment_min_topics', 'community.sentiment.min_topics', '5', '标记极端情绪所需的最少话题数', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_community_sentiment_index', 'community_sentiment_index', '社区情绪指数', 'SYSTEM', 'EVERY_60_MINUTES', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,36 @@
+-- Community follows, mute/block lists and @mentions for the personalized feed
+
+CREATE TABLE IF NOT EXISTS community_follows (
+  id          varchar(64) NOT NULL,
+  user_id     varchar(32) NOT NULL,
+  target_type varchar(16) NOT NULL,
+  target_id   varchar(64) NOT NULL,
+  created_at  datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_community_follows_target (user_id, target_type, target_id),
+  INDEX idx_community_follows_followed (target_type, target_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS community_user_blocks (
+  id              varchar(64) NOT NULL,
+  user_id         varchar(32) NOT NULL,
+  blocked_user_id varchar(32) NOT NULL,
+  block_type      varchar(16) NOT NULL,
+  created_at      datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_community_user_blocks_pair (user_id, blocked_user_id),
+  INDEX idx_community_user_blocks_blocked (blocked_user_id, block_type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS community_mentions (
+  id                varchar(64) NOT NULL,
+  target_type       varchar(16) NOT NULL,
+  target_id         varchar(64) NOT NULL,
+  topic_id          varchar(64) NOT NULL,
+  mentioned_user_id varchar(32) NOT NULL,
+  actor_user_id     varchar(32) NOT NULL,
+  created_at        datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_community_mentions_target (target_type, target_id, mentioned_user_id),
+  INDEX idx_community_mentions_user (mentioned_user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
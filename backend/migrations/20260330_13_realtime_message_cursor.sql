@@ -0,0 +1,33 @@
+-- Cursor indexes for the real-time message streams (created_at, id after a Last-Event-ID)
+
+SET @has_messages_user_created_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'messages'
+    AND INDEX_NAME = 'idx_messages_user_created'
+);
+SET @sql_messages_user_created_idx := IF(
+  @has_messages_user_created_idx = 0,
+  'ALTER TABLE messages ADD INDEX idx_messages_user_created (user_id, created_at, id)',
+  'SELECT 1'
+);
+PREPARE stmt_messages_user_created_idx FROM @sql_messages_user_created_idx;
+EXECUTE stmt_messages_user_created_idx;
+DEALLOCATE PREPARE stmt_messages_user_created_idx;
+
+SET @has_workflow_messages_created_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'workflow_messages'
+    AND INDEX_NAME = 'idx_workflow_messages_created'
+);
+SET @sql_workflow_messages_created_idx := IF(
+  @has_workflow_messages_created_idx = 0,
+  'ALTER TABLE workflow_messages ADD INDEX idx_workflow_messages_created (created_at, id)',
+  'SELECT 1'
+);
+PREPARE stmt_workflow_messages_created_idx FROM @sql_workflow_messages_created_idx;
+EXECUTE stmt_workflow_messages_created_idx;
+DEALLOCATE PREPARE stmt_workflow_messages_created_idx;
@@ -0,0 +1,71 @@
+-- Dependency-aware scheduler pipelines: definitions, per-trade-date runs and node runs
+
+CREATE TABLE IF NOT EXISTS scheduler_pipelines (
+  id           varchar(64) NOT NULL,
+  pipeline_key varchar(64) NOT NULL,
+  display_name varchar(128) NOT NULL,
+  status       varchar(16) NOT NULL,
+  nodes_json   json NOT NULL,
+  updated_by   varchar(32) NOT NULL,
+  created_at   datetime NOT NULL,
+  updated_at   datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_scheduler_pipelines_key (pipeline_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS scheduler_pipeline_runs (
+  id             varchar(64) NOT NULL,
+  pipeline_key   varchar(64) NOT NULL,
+  trade_date     date NOT NULL,
+  status         varchar(16) NOT NULL,
+  trigger_source varchar(32) NOT NULL,
+  operator_id    varchar(32) NOT NULL DEFAULT '',
+  started_at     datetime NOT NULL,
+  finished_at    datetime NULL,
+  created_at     datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_scheduler_pipeline_runs_date (pipeline_key, trade_date),
+  INDEX idx_scheduler_pipeline_runs_status (status, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS scheduler_pipeline_node_runs (
+  id              varchar(64) NOT NULL,
+  run_id          varchar(64) NOT NULL,
+  seq             int NOT NULL,
+  job_name        varchar(64) NOT NULL,
+  depends_on_json json NOT NULL,
+  status          varchar(16) NOT NULL,
+  attempt         int NOT NULL DEFAULT 0,
+  job_run_id      varchar(64) NOT NULL DEFAULT '',
+  blocked_by      varchar(64) NOT NULL DEFAULT '',
+  result_summary  varchar(512) NOT NULL DEFAULT '',
+  error_message   varchar(512) NOT NULL DEFAULT '',
+  started_at      datetime NULL,
+  finished_at     datetime NULL,
+  updated_at      datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_scheduler_pipeline_node_runs_job (run_id, job_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO scheduler_pipelines (id, pipeline_key, display_name, status, nodes_json, updated_by, created_at, updated_at)
+VALUES (
+  'sp_daily_stock_market',
+  'daily_stock_market',
+  '每日股票行情与推荐流水线',
+  'ACTIVE',
+  JSON_ARRAY(
+    JSON_OBJECT('job_name', 'stock_master_sync', 'depends_on', JSON_ARRAY()),
+    JSON_OBJECT('job_name', 'stock_quotes_sync', 'depends_on', JSON_ARRAY('stock_master_sync')),
+    JSON_OBJECT('job_name', 'stock_daily_basic_sync', 'depends_on', JSON_ARRAY('stock_quotes_sync')),
+    JSON_OBJECT('job_name', 'stock_moneyflow_sync', 'depends_on', JSON_ARRAY('stock_quotes_sync')),
+    JSON_OBJECT('job_name', 'stock_truth_rebuild', 'depends_on', JSON_ARRAY('stock_daily_basic_sync', 'stock_moneyflow_sync')),
+    JSON_OBJECT('job_name', 'stock_quant_rank', 'depends_on', JSON_ARRAY('stock_truth_rebuild')),
+    JSON_OBJECT('job_name', 'daily_stock_recommendation', 'depends_on', JSON_ARRAY('stock_quant_rank')),
+    JSON_OBJECT('job_name', 'stock_selection_run', 'depends_on', JSON_ARRAY('daily_stock_recommendation')),
+    JSON_OBJECT('job_name', 'stock_selection_publish_review', 'depends_on', JSON_ARRAY('stock_selection_run'))
+  ),
+  'system',
+  NOW(),
+  NOW()
+)
+ON DUPLICATE KEY UPDATE id = id;
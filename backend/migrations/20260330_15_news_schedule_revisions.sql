@@ -0,0 +1,74 @@
+-- Scheduled publishing, embargo and revision history for news articles
+
+SET @has_news_scheduled_publish_at := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND COLUMN_NAME = 'scheduled_publish_at'
+);
+SET @sql_news_scheduled_publish_at := IF(
+  @has_news_scheduled_publish_at = 0,
+  'ALTER TABLE news_articles ADD COLUMN scheduled_publish_at datetime NULL AFTER published_at',
+  'SELECT 1'
+);
+PREPARE stmt_news_scheduled_publish_at FROM @sql_news_scheduled_publish_at;
+EXECUTE stmt_news_scheduled_publish_at;
+DEALLOCATE PREPARE stmt_news_scheduled_publish_at;
+
+SET @has_news_embargo_until := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND COLUMN_NAME = 'embargo_until'
+);
+SET @sql_news_embargo_until := IF(
+  @has_news_embargo_until = 0,
+  'ALTER TABLE news_articles ADD COLUMN embargo_until datetime NULL AFTER scheduled_publish_at',
+  'SELECT 1'
+);
+PREPARE stmt_news_embargo_until FROM @sql_news_embargo_until;
+EXECUTE stmt_news_embargo_until;
+DEALLOCATE PREPARE stmt_news_embargo_until;
+
+SET @has_news_scheduled_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND INDEX_NAME = 'idx_news_articles_status_scheduled'
+);
+SET @sql_news_scheduled_idx := IF(
+  @has_news_scheduled_idx = 0,
+  'ALTER TABLE news_articles ADD INDEX idx_news_articles_status_scheduled (status, scheduled_publish_at)',
+  'SELECT 1'
+);
+PREPARE stmt_news_scheduled_idx FROM @sql_news_scheduled_idx;
+EXECUTE stmt_news_scheduled_idx;
+DEALLOCATE PREPARE stmt_news_scheduled_idx;
+
+CREATE TABLE IF NOT EXISTS news_article_revisions (
+  id            varchar(32) PRIMARY KEY,
+  article_id    varchar(32) NOT NULL,
+  revision_no   int NOT NULL,
+  action        varchar(16) NOT NULL,
+  restored_from int NULL,
+  category_id   varchar(32) NOT NULL,
+  title         varchar(256) NOT NULL,
+  summary       varchar(512),
+  content       mediumtext NOT NULL,
+  cover_url     varchar(512),
+  visibility    varchar(16) NOT NULL,
+  status        varchar(16) NOT NULL,
+  editor_id     varchar(32) NOT NULL,
+  created_at    datetime NOT NULL,
+  UNIQUE KEY uk_news_article_revision (article_id, revision_no),
+  FOREIGN KEY (article_id) REFERENCES news_articles(id)
+);
+
+-- Existing articles start their history with their current content as revision 1.
+INSERT INTO news_article_revisions (id, article_id, revision_no, action, category_id, title, summary, content, cover_url, visibility, status, editor_id, created_at)
+SELECT CONCAT('nar_init_', SUBSTRING(na.id, 1, 23)), na.id, 1, 'CREATE', na.category_id, na.title, na.summary, na.content, na.cover_url, na.visibility, na.status, na.author_id, na.updated_at
+FROM news_articles na
+WHERE NOT EXISTS (SELECT 1 FROM news_article_revisions nar WHERE nar.article_id = na.id);
@@ -0,0 +1,115 @@
+-- SimHash fingerprints and near-duplicate links for synced news.
+-- duplicate_of points at the canonical row of a near-duplicate group; rows
+-- synced before this migration get a fingerprint on their next upsert.
+
+SET @has_news_simhash := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND COLUMN_NAME = 'simhash'
+);
+SET @sql_news_simhash := IF(
+  @has_news_simhash = 0,
+  'ALTER TABLE news_articles ADD COLUMN simhash char(16) NULL AFTER embargo_until',
+  'SELECT 1'
+);
+PREPARE stmt_news_simhash FROM @sql_news_simhash;
+EXECUTE stmt_news_simhash;
+DEALLOCATE PREPARE stmt_news_simhash;
+
+SET @has_news_duplicate_of := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND COLUMN_NAME = 'duplicate_of'
+);
+SET @sql_news_duplicate_of := IF(
+  @has_news_duplicate_of = 0,
+  'ALTER TABLE news_articles ADD COLUMN duplicate_of varchar(32) NULL AFTER simhash',
+  'SELECT 1'
+);
+PREPARE stmt_news_duplicate_of FROM @sql_news_duplicate_of;
+EXECUTE stmt_news_duplicate_of;
+DEALLOCATE PREPARE stmt_news_duplicate_of;
+
+SET @has_news_duplicate_of_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND INDEX_NAME = 'idx_news_articles_duplicate_of'
+);
+SET @sql_news_duplicate_of_idx := IF(
+  @has_news_duplicate_of_idx = 0,
+  'ALTER TABLE news_articles ADD INDEX idx_news_articles_duplicate_of (duplicate_of)',
+  'SELECT 1'
+);
+PREPARE stmt_news_duplicate_of_idx FROM @sql_news_duplicate_of_idx;
+EXECUTE stmt_news_duplicate_of_idx;
+DEALLOCATE PREPARE stmt_news_duplicate_of_idx;
+
+SET @has_news_published_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND INDEX_NAME = 'idx_news_articles_published'
+);
+SET @sql_news_published_idx := IF(
+  @has_news_published_idx = 0,
+  'ALTER TABLE news_articles ADD INDEX idx_news_articles_published (published_at)',
+  'SELECT 1'
+);
+PREPARE stmt_news_published_idx FROM @sql_news_published_idx;
+EXECUTE stmt_news_published_idx;
+DEALLOCATE PREPARE stmt_news_published_idx;
+
+SET @has_market_news_simhash := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'market_news_items'
+    AND COLUMN_NAME = 'simhash'
+);
+SET @sql_market_news_simhash := IF(
+  @has_market_news_simhash = 0,
+  'ALTER TABLE market_news_items ADD COLUMN simhash char(16) NULL AFTER metadata_json',
+  'SELECT 1'
+);
+PREPARE stmt_market_news_simhash FROM @sql_market_news_simhash;
+EXECUTE stmt_market_news_simhash;
+DEALLOCATE PREPARE stmt_market_news_simhash;
+
+SET @has_market_news_duplicate_of := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'market_news_items'
+    AND COLUMN_NAME = 'duplicate_of'
+);
+SET @sql_market_news_duplicate_of := IF(
+  @has_market_news_duplicate_of = 0,
+  'ALTER TABLE market_news_items ADD COLUMN duplicate_of varchar(32) NULL AFTER simhash',
+  'SELECT 1'
+);
+PREPARE stmt_market_news_duplicate_of FROM @sql_market_news_duplicate_of;
+EXECUTE stmt_market_news_duplicate_of;
+DEALLOCATE PREPARE stmt_market_news_duplicate_of;
+
+SET @has_market_news_duplicate_of_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'market_news_items'
+    AND INDEX_NAME = 'idx_market_news_duplicate_of'
+);
+SET @sql_market_news_duplicate_of_idx := IF(
+  @has_market_news_duplicate_of_idx = 0,
+  'ALTER TABLE market_news_items ADD INDEX idx_market_news_duplicate_of (duplicate_of)',
+  'SELECT 1'
+);
+PREPARE stmt_market_news_duplicate_of_idx FROM @sql_market_news_duplicate_of_idx;
+EXECUTE stmt_market_news_duplicate_of_idx;
+DEALLOCATE PREPARE stmt_market_news_duplicate_of_idx;
@@ -0,0 +1,13 @@
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_oss_s3_endpoint', 'oss.s3.endpoint', '', 'S3兼容存储Endpoint（如 http://minio:9000）', 'system', NOW()),
+  ('cfg_oss_s3_region', 'oss.s3.region', 'us-east-1', 'S3兼容存储区域', 'system', NOW()),
+  ('cfg_oss_s3_bucket', 'oss.s3.bucket', '', 'S3兼容存储Bucket', 'system', NOW()),
+  ('cfg_oss_s3_access_key', 'oss.s3.access_key', '', 'S3兼容存储AccessKey', 'system', NOW()),
+  ('cfg_oss_s3_secret_key', 'oss.s3.secret_key', '', 'S3兼容存储SecretKey', 'system', NOW()),
+  ('cfg_oss_s3_path_style', 'oss.s3.path_style', 'true', 'S3兼容存储是否使用路径风格访问（MinIO需开启）', 'system', NOW()),
+  ('cfg_oss_s3_path_prefix', 'oss.s3.path_prefix', 'uploads/', 'S3兼容存储对象前缀', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,17 @@
+-- Extracted plain text of news attachments for in-document search.
+-- Rows are keyed by SHA-256 of file_url so an upload can be extracted before
+-- its attachment row exists and deduplicated objects share one row.
+-- page_offsets is a JSON array of character offsets where each page starts.
+
+CREATE TABLE IF NOT EXISTS news_attachment_texts (
+  url_hash      char(64) PRIMARY KEY,
+  file_url      varchar(512) NOT NULL,
+  format        varchar(16) NOT NULL DEFAULT '',
+  status        varchar(16) NOT NULL,
+  page_count    int NOT NULL DEFAULT 0,
+  page_offsets  text NULL,
+  content       mediumtext NULL,
+  error_message varchar(512) NULL,
+  extracted_at  datetime NOT NULL,
+  INDEX idx_news_attachment_texts_status (status, extracted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,92 @@
+-- Maker-checker approval chains for sensitive admin actions: per-module
+-- policies, guarded action requests and per-step approver decisions
+
+CREATE TABLE IF NOT EXISTS approval_policies (
+  id           varchar(64) NOT NULL,
+  module       varchar(32) NOT NULL,
+  display_name varchar(128) NOT NULL,
+  status       varchar(16) NOT NULL,
+  min_amount   decimal(18,2) NOT NULL DEFAULT 0,
+  steps_json   json NOT NULL,
+  updated_by   varchar(32) NOT NULL,
+  created_at   datetime NOT NULL,
+  updated_at   datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_approval_policies_module (module)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS approval_requests (
+  id            varchar(64) NOT NULL,
+  policy_id     varchar(64) NOT NULL,
+  module        varchar(32) NOT NULL,
+  action        varchar(64) NOT NULL,
+  target_id     varchar(64) NOT NULL,
+  amount        decimal(18,2) NOT NULL DEFAULT 0,
+  summary       varchar(512) NOT NULL DEFAULT '',
+  payload       text NOT NULL,
+  submitter_id  varchar(32) NOT NULL,
+  status        varchar(16) NOT NULL,
+  current_step  int NOT NULL DEFAULT 1,
+  steps_json    json NOT NULL,
+  execute_error varchar(512) NOT NULL DEFAULT '',
+  created_at    datetime NOT NULL,
+  updated_at    datetime NOT NULL,
+  completed_at  datetime NULL,
+  executed_at   datetime NULL,
+  PRIMARY KEY (id),
+  INDEX idx_approval_requests_target (action, target_id, status),
+  INDEX idx_approval_requests_status (module, status, created_at),
+  INDEX idx_approval_requests_submitter (submitter_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS approval_decisions (
+  id          varchar(64) NOT NULL,
+  request_id  varchar(64) NOT NULL,
+  step        int NOT NULL,
+  approver_id varchar(32) NOT NULL,
+  decision    varchar(16) NOT NULL,
+  note        varchar(512) NOT NULL DEFAULT '',
+  created_at  datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_approval_decisions_approver (request_id, approver_id),
+  INDEX idx_approval_decisions_step (request_id, step)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Policies ship DISABLED so upgrading does not start parking actions; enable
+-- them once the approver roles are staffed.
+INSERT INTO approval_policies (id, module, display_name, status, min_amount, steps_json, updated_by, created_at, updated_at)
+VALUES
+  ('ap_recommendation_publish', 'RECOMMENDATION_PUBLISH', '推荐发布双人复核', 'DISABLED', 0,
+    JSON_ARRAY(JSON_OBJECT('name', '运营复核', 'required_approvals', 1, 'approver_roles', JSON_ARRAY('OPS_ADMIN', 'SUPER_ADMIN'))),
+    'system', NOW(), NOW()),
+  ('ap_withdraw', 'WITHDRAW', '大额提现审批', 'DISABLED', 5000,
+    JSON_ARRAY(
+      JSON_OBJECT('name', '运营复核', 'required_approvals', 1, 'approver_roles', JSON_ARRAY('OPS_ADMIN')),
+      JSON_OBJECT('name', '财务终审', 'required_approvals', 1, 'approver_roles', JSON_ARRAY('SUPER_ADMIN'))
+    ),
+    'system', NOW(), NOW()),
+  ('ap_payment_config', 'PAYMENT_CONFIG', '支付配置变更审批', 'DISABLED', 0,
+    JSON_ARRAY(JSON_OBJECT('name', '超级管理员复核', 'required_approvals', 1, 'approver_roles', JSON_ARRAY('SUPER_ADMIN'))),
+    'system', NOW(), NOW()),
+  ('ap_rbac', 'RBAC', '角色权限变更审批', 'DISABLED', 0,
+    JSON_ARRAY(JSON_OBJECT('name', '超级管理员复核', 'required_approvals', 1, 'approver_roles', JSON_ARRAY('SUPER_ADMIN'))),
+    'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  updated_at = updated_at;
+
+INSERT INTO rbac_permissions (code, name, module, action, description, status, created_at, updated_at)
+VALUES
+  ('approval_policy.edit', 'Approval Policy Edit', 'REVIEW', 'EDIT', 'edit maker-checker approval policies', 'ACTIVE', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  name = VALUES(name),
+  module = VALUES(module),
+  action = VALUES(action),
+  description = VALUES(description),
+  status = VALUES(status),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO rbac_role_permissions (role_id, permission_code, created_at)
+SELECT 'role_super_admin', p.code, NOW()
+FROM rbac_permissions p
+WHERE p.code IN ('approval_policy.edit')
+ON DUPLICATE KEY UPDATE created_at = VALUES(created_at);
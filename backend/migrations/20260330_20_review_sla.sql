@@ -0,0 +1,78 @@
+-- SLA due times, breach detection and escalation for review tasks and
+-- community reports
+
+CREATE TABLE IF NOT EXISTS review_sla_policies (
+  module              varchar(32) NOT NULL,
+  status              varchar(16) NOT NULL,
+  high_minutes        int NOT NULL,
+  normal_minutes      int NOT NULL,
+  low_minutes         int NOT NULL,
+  business_hours_only tinyint(1) NOT NULL DEFAULT 0,
+  business_start      varchar(5) NOT NULL DEFAULT '09:00',
+  business_end        varchar(5) NOT NULL DEFAULT '18:00',
+  workdays            varchar(16) NOT NULL DEFAULT '1,2,3,4,5',
+  timezone            varchar(64) NOT NULL DEFAULT 'Asia/Shanghai',
+  backup_reviewer_id  varchar(32) NOT NULL DEFAULT '',
+  backup_role         varchar(64) NOT NULL DEFAULT '',
+  updated_by          varchar(32) NOT NULL,
+  updated_at          datetime NOT NULL,
+  PRIMARY KEY (module)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS review_sla_items (
+  id           varchar(64) NOT NULL,
+  subject_type varchar(32) NOT NULL,
+  subject_id   varchar(64) NOT NULL,
+  module       varchar(32) NOT NULL,
+  target_id    varchar(64) NOT NULL,
+  priority     varchar(16) NOT NULL,
+  assignee_id  varchar(32) NOT NULL DEFAULT '',
+  status       varchar(16) NOT NULL,
+  started_at   datetime NOT NULL,
+  due_at       datetime NOT NULL,
+  breached_at  datetime NULL,
+  escalated_to varchar(64) NOT NULL DEFAULT '',
+  escalated_at datetime NULL,
+  resolved_by  varchar(32) NOT NULL DEFAULT '',
+  resolved_at  datetime NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_review_sla_items_subject (subject_type, subject_id),
+  INDEX idx_review_sla_items_due (status, due_at),
+  INDEX idx_review_sla_items_target (module, target_id, status),
+  INDEX idx_review_sla_items_assignee (assignee_id, status),
+  INDEX idx_review_sla_items_resolved (resolved_by, resolved_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- HIGH stock events must be picked up within the first trading hour; the
+-- clock is paused outside 09:00-17:30 on weekdays.
+INSERT INTO review_sla_policies
+  (module, status, high_minutes, normal_minutes, low_minutes, business_hours_only, business_start, business_end, workdays, timezone, backup_reviewer_id, backup_role, updated_by, updated_at)
+VALUES
+  ('STOCK_EVENT', 'ACTIVE', 60, 240, 1440, 1, '09:00', '17:30', '1,2,3,4,5', 'Asia/Shanghai', '', 'SUPER_ADMIN', 'system', NOW()),
+  ('STOCK', 'ACTIVE', 120, 480, 1440, 1, '09:00', '17:30', '1,2,3,4,5', 'Asia/Shanghai', '', 'SUPER_ADMIN', 'system', NOW()),
+  ('FUTURES', 'ACTIVE', 120, 480, 1440, 1, '09:00', '17:30', '1,2,3,4,5', 'Asia/Shanghai', '', 'SUPER_ADMIN', 'system', NOW()),
+  ('NEWS', 'ACTIVE', 60, 240, 1440, 0, '09:00', '18:00', '1,2,3,4,5,6,7', 'Asia/Shanghai', '', 'OPS_ADMIN', 'system', NOW()),
+  ('COMMUNITY', 'ACTIVE', 120, 720, 2880, 0, '09:00', '18:00', '1,2,3,4,5,6,7', 'Asia/Shanghai', '', 'OPS_ADMIN', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  updated_at = updated_at;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_review_sla_enabled', 'review.sla.enabled', 'true', '审核SLA超时检测与升级开关', 'system', NOW()),
+  ('cfg_review_sla_interval', 'review.sla.interval_minutes', '5', '审核SLA超时检测间隔(分钟)', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_review_sla_escalation', 'review_sla_escalation', '审核SLA超时升级', 'SYSTEM', 'EVERY_5_MINUTES', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,65 @@
+-- Background admin exports: requested jobs, produced files and every
+-- download, kept for data-protection auditing
+
+CREATE TABLE IF NOT EXISTS export_jobs (
+  id                 varchar(64) NOT NULL,
+  export_type        varchar(32) NOT NULL,
+  format             varchar(8) NOT NULL,
+  filters            text NOT NULL,
+  status             varchar(16) NOT NULL,
+  row_count          int NOT NULL DEFAULT 0,
+  file_name          varchar(128) NOT NULL DEFAULT '',
+  file_size          bigint NOT NULL DEFAULT 0,
+  content_sha256     varchar(64) NOT NULL DEFAULT '',
+  storage_ref        varchar(512) NOT NULL DEFAULT '',
+  error_message      varchar(1024) NOT NULL DEFAULT '',
+  requested_by       varchar(32) NOT NULL,
+  request_ip         varchar(64) NOT NULL DEFAULT '',
+  download_count     int NOT NULL DEFAULT 0,
+  created_at         datetime NOT NULL,
+  started_at         datetime NULL,
+  finished_at        datetime NULL,
+  expires_at         datetime NULL,
+  last_downloaded_at datetime NULL,
+  PRIMARY KEY (id),
+  INDEX idx_export_jobs_status (status, created_at),
+  INDEX idx_export_jobs_requester (requested_by, created_at),
+  INDEX idx_export_jobs_expiry (status, expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS export_job_downloads (
+  id            varchar(64) NOT NULL,
+  job_id        varchar(64) NOT NULL,
+  user_id       varchar(32) NOT NULL,
+  channel       varchar(16) NOT NULL,
+  client_ip     varchar(64) NOT NULL DEFAULT '',
+  user_agent    varchar(255) NOT NULL DEFAULT '',
+  downloaded_at datetime NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_export_job_downloads_job (job_id, downloaded_at),
+  INDEX idx_export_job_downloads_user (user_id, downloaded_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO system_configs (id, config_key, config_value, description, updated_by, updated_at)
+VALUES
+  ('cfg_export_worker_enabled', 'export.worker.enabled', 'true', '后台导出任务处理开关', 'system', NOW()),
+  ('cfg_export_worker_interval', 'export.worker.interval_minutes', '1', '后台导出任务轮询间隔(分钟)', 'system', NOW()),
+  ('cfg_export_max_rows', 'export.max_rows', '200000', '单个导出文件最大行数', 'system', NOW()),
+  ('cfg_export_retention_hours', 'export.retention_hours', '72', '导出文件保留时长(小时)，过期后删除文件', 'system', NOW()),
+  ('cfg_export_link_ttl', 'export.link_ttl_minutes', '15', '导出文件下载链接有效期(分钟)', 'system', NOW())
+ON DUPLICATE KEY UPDATE
+  description = VALUES(description),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO scheduler_job_definitions
+  (id, job_name, display_name, module, cron_expr, status, last_run_at, updated_by, created_at, updated_at)
+VALUES
+  ('jobdef_export_jobs', 'export_jobs', '后台导出任务处理', 'SYSTEM', 'EVERY_MINUTE', 'ACTIVE', NULL, 'system', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  display_name = VALUES(display_name),
+  module = VALUES(module),
+  cron_expr = VALUES(cron_expr),
+  status = VALUES(status),
+  updated_by = VALUES(updated_by),
+  updated_at = VALUES(updated_at);
@@ -0,0 +1,19 @@
+-- Personal data is masked in admin views and exports; revealing one
+-- record's full phone, email or IP needs this permission
+
+INSERT INTO rbac_permissions (code, name, module, action, description, status, created_at, updated_at)
+VALUES
+  ('users.pii_reveal', 'Users PII Reveal', 'USERS', 'REVEAL', 'reveal the unmasked phone, email or IP of a single record', 'ACTIVE', NOW(), NOW())
+ON DUPLICATE KEY UPDATE
+  name = VALUES(name),
+  module = VALUES(module),
+  action = VALUES(action),
+  description = VALUES(description),
+  status = VALUES(status),
+  updated_at = VALUES(updated_at);
+
+INSERT INTO rbac_role_permissions (role_id, permission_code, created_at)
+SELECT 'role_super_admin', p.code, NOW()
+FROM rbac_permissions p
+WHERE p.code IN ('users.pii_reveal')
+ON DUPLICATE KEY UPDATE created_at = VALUES(created_at);
@@ -0,0 +1,44 @@
+-- Subscriber notifications for scheduled news articles wait for the embargo
+-- to lift and remember who has been messaged, so a failed pass is retried
+-- without messaging anyone twice.
+
+SET @has_news_subscriber_notify_status := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND COLUMN_NAME = 'subscriber_notify_status'
+);
+SET @sql_news_subscriber_notify_status := IF(
+  @has_news_subscriber_notify_status = 0,
+  'ALTER TABLE news_articles ADD COLUMN subscriber_notify_status varchar(16) NULL AFTER embargo_until',
+  'SELECT 1'
+);
+PREPARE stmt_news_subscriber_notify_status FROM @sql_news_subscriber_notify_status;
+EXECUTE stmt_news_subscriber_notify_status;
+DEALLOCATE PREPARE stmt_news_subscriber_notify_status;
+
+SET @has_news_notify_pending_idx := (
+  SELECT COUNT(*)
+  FROM information_schema.STATISTICS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND INDEX_NAME = 'idx_news_articles_notify_status'
+);
+SET @sql_news_notify_pending_idx := IF(
+  @has_news_notify_pending_idx = 0,
+  'ALTER TABLE news_articles ADD INDEX idx_news_articles_notify_status (subscriber_notify_status, published_at)',
+  'SELECT 1'
+);
+PREPARE stmt_news_notify_pending_idx FROM @sql_news_notify_pending_idx;
+EXECUTE stmt_news_notify_pending_idx;
+DEALLOCATE PREPARE stmt_news_notify_pending_idx;
+
+CREATE TABLE IF NOT EXISTS news_article_notifications (
+  article_id varchar(32) NOT NULL,
+  user_id    varchar(32) NOT NULL,
+  message_id varchar(32) NOT NULL,
+  created_at datetime NOT NULL,
+  PRIMARY KEY (article_id, user_id),
+  KEY idx_news_article_notifications_user (user_id, created_at)
+);
@@ -0,0 +1,36 @@
+-- Insert sequences for the real-time message streams, which page on seq
+-- instead of (created_at, id). A row whose transaction commits after a
+-- later insert shows up below a stream's cursor; streams re-read a short
+-- window behind the cursor to pick it up.
+
+SET @has_messages_seq := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'messages'
+    AND COLUMN_NAME = 'seq'
+);
+SET @sql_messages_seq := IF(
+  @has_messages_seq = 0,
+  'ALTER TABLE messages ADD COLUMN seq bigint unsigned NOT NULL AUTO_INCREMENT, ADD UNIQUE KEY uk_messages_seq (seq), ADD INDEX idx_messages_user_seq (user_id, seq)',
+  'SELECT 1'
+);
+PREPARE stmt_messages_seq FROM @sql_messages_seq;
+EXECUTE stmt_messages_seq;
+DEALLOCATE PREPARE stmt_messages_seq;
+
+SET @has_workflow_messages_seq := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'workflow_messages'
+    AND COLUMN_NAME = 'seq'
+);
+SET @sql_workflow_messages_seq := IF(
+  @has_workflow_messages_seq = 0,
+  'ALTER TABLE workflow_messages ADD COLUMN seq bigint unsigned NOT NULL AUTO_INCREMENT, ADD UNIQUE KEY uk_workflow_messages_seq (seq)',
+  'SELECT 1'
+);
+PREPARE stmt_workflow_messages_seq FROM @sql_workflow_messages_seq;
+EXECUTE stmt_workflow_messages_seq;
+DEALLOCATE PREPARE stmt_workflow_messages_seq;
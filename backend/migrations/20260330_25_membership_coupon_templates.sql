@@ -0,0 +1,18 @@
+-- The invitee coupon template used to be an ACTIVE coupon, so its code could
+-- be redeemed directly by anyone. Templates now carry status TEMPLATE, which
+-- checkout refuses; only the per-invitee copies are ACTIVE.
+
+UPDATE membership_coupons
+SET status = 'TEMPLATE', updated_at = NOW()
+WHERE status = 'ACTIVE'
+  AND owner_user_id IS NULL
+  AND code = (
+    SELECT UPPER(TRIM(config_value))
+    FROM system_configs
+    WHERE config_key = 'coupon.invitee.template_code'
+    LIMIT 1
+  );
+
+UPDATE system_configs
+SET description = '邀请注册发放给被邀请人的优惠券模板码(须为 TEMPLATE 状态的优惠券,留空不发放)', updated_by = 'system', updated_at = NOW()
+WHERE config_key = 'coupon.invitee.template_code';
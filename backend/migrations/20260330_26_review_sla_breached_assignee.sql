@@ -0,0 +1,19 @@
+-- Escalation hands a breached review task to the backup reviewer, so
+-- resolved_by no longer says who let it breach. breached_assignee_id keeps
+-- the assignee at breach time, and MISSED items are charged to them.
+
+SET @has_sla_breached_assignee := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'review_sla_items'
+    AND COLUMN_NAME = 'breached_assignee_id'
+);
+SET @sql_sla_breached_assignee := IF(
+  @has_sla_breached_assignee = 0,
+  'ALTER TABLE review_sla_items ADD COLUMN breached_assignee_id varchar(32) NOT NULL DEFAULT '''' AFTER breached_at',
+  'SELECT 1'
+);
+PREPARE stmt_sla_breached_assignee FROM @sql_sla_breached_assignee;
+EXECUTE stmt_sla_breached_assignee;
+DEALLOCATE PREPARE stmt_sla_breached_assignee;
@@ -0,0 +1,19 @@
+-- duplicate_manual marks a duplicate_of link made by hand through
+-- POST /admin/news/articles/:id/merge. Re-syncing such an article keeps the
+-- link instead of re-running automatic near-duplicate linking.
+
+SET @has_news_duplicate_manual := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'news_articles'
+    AND COLUMN_NAME = 'duplicate_manual'
+);
+SET @sql_news_duplicate_manual := IF(
+  @has_news_duplicate_manual = 0,
+  'ALTER TABLE news_articles ADD COLUMN duplicate_manual tinyint(1) NOT NULL DEFAULT 0 AFTER duplicate_of',
+  'SELECT 1'
+);
+PREPARE stmt_news_duplicate_manual FROM @sql_news_duplicate_manual;
+EXECUTE stmt_news_duplicate_manual;
+DEALLOCATE PREPARE stmt_news_duplicate_manual;
@@ -0,0 +1,35 @@
+-- Step-up and recovery-code checks are not tied to a login challenge, so
+-- wrong codes are counted per user. Reaching the limit sets locked_until and
+-- starts a new count.
+
+SET @has_mfa_failed_attempts := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'auth_user_mfa'
+    AND COLUMN_NAME = 'failed_attempts'
+);
+SET @sql_mfa_failed_attempts := IF(
+  @has_mfa_failed_attempts = 0,
+  'ALTER TABLE auth_user_mfa ADD COLUMN failed_attempts int NOT NULL DEFAULT 0 AFTER last_used_step',
+  'SELECT 1'
+);
+PREPARE stmt_mfa_failed_attempts FROM @sql_mfa_failed_attempts;
+EXECUTE stmt_mfa_failed_attempts;
+DEALLOCATE PREPARE stmt_mfa_failed_attempts;
+
+SET @has_mfa_locked_until := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'auth_user_mfa'
+    AND COLUMN_NAME = 'locked_until'
+);
+SET @sql_mfa_locked_until := IF(
+  @has_mfa_locked_until = 0,
+  'ALTER TABLE auth_user_mfa ADD COLUMN locked_until datetime NULL AFTER failed_attempts',
+  'SELECT 1'
+);
+PREPARE stmt_mfa_locked_until FROM @sql_mfa_locked_until;
+EXECUTE stmt_mfa_locked_until;
+DEALLOCATE PREPARE stmt_mfa_locked_until;
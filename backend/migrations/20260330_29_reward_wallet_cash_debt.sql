@@ -0,0 +1,25 @@
+-- Reversing an issued commission the inviter already withdrew used to push
+-- cash_balance below zero. The uncovered part is now kept in cash_debt and
+-- repaid from the next commissions that reach cash_balance.
+
+SET @has_wallet_cash_debt := (
+  SELECT COUNT(*)
+  FROM information_schema.COLUMNS
+  WHERE TABLE_SCHEMA = DATABASE()
+    AND TABLE_NAME = 'reward_wallets'
+    AND COLUMN_NAME = 'cash_debt'
+);
+SET @sql_wallet_cash_debt := IF(
+  @has_wallet_cash_debt = 0,
+  'ALTER TABLE reward_wallets ADD COLUMN cash_debt decimal(12,2) NOT NULL DEFAULT 0 AFTER cash_frozen',
+  'SELECT 1'
+);
+PREPARE stmt_wallet_cash_debt FROM @sql_wallet_cash_debt;
+EXECUTE stmt_wallet_cash_debt;
+DEALLOCATE PREPARE stmt_wallet_cash_debt;
+
+-- Move balances that already went negative into the new column.
+UPDATE reward_wallets
+SET cash_debt = cash_debt - cash_balance,
+    cash_balance = 0
+WHERE cash_balance < 0;
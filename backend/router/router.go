@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"log"
 	"strconv"
 	"strings"
@@ -15,6 +16,9 @@ import (
 	"sercherai/backend/internal/growth/service"
 	"sercherai/backend/internal/platform/config"
 	"sercherai/backend/internal/platform/middleware"
+	"sercherai/backend/internal/platform/payment"
+	"sercherai/backend/internal/platform/secrets"
+	"sercherai/backend/internal/platform/session"
 	"sercherai/backend/internal/platform/storage"
 )
 
@@ -36,6 +40,19 @@ func Register(r *gin.Engine) {
 		growthRepo = repo.NewMySQLGrowthRepo(db, redisClient, cfg)
 	}
 
+	configKeyring, err := secrets.Load(cfg)
+	if err != nil {
+		log.Printf("config master keys unavailable, sensitive config writes will be rejected: %v", err)
+	} else if !configKeyring.Enabled() {
+		log.Printf("CONFIG_MASTER_KEYS is not set, sensitive configs are stored in plaintext")
+	}
+	if strings.TrimSpace(cfg.AuditSigningSecret) == "" {
+		log.Printf("AUDIT_SIGNING_SECRET is not set, audit ledger checkpoints are signed with a key derived from JWT_SECRET")
+	} else if strings.TrimSpace(cfg.AuditSigningSecret) == strings.TrimSpace(cfg.JWTSecret) {
+		log.Printf("AUDIT_SIGNING_SECRET equals JWT_SECRET, rotating the JWT secret will change the audit ledger key")
+	}
+
+	sessionStore := session.NewStore(db, redisClient)
 	growthSvc := service.NewGrowthService(growthRepo)
 	userGrowthHandler := handler.NewUserGrowthHandler(growthSvc, cfg)
 	adminGrowthHandler := handler.NewAdminGrowthHandler(growthSvc, cfg)
@@ -51,12 +68,26 @@ func Register(r *gin.Engine) {
 		strings.EqualFold(strings.TrimSpace(cfg.AppEnv), "dev"),
 		db,
 		redisClient,
+		sessionStore,
+		configKeyring,
+		growthSvc,
 	)
+	stepUp := middleware.StepUpRequired(sessionStore)
+	for action, executor := range authHandler.ApprovalExecutors() {
+		adminGrowthHandler.RegisterApprovalExecutor(action, executor)
+	}
 
 	if db != nil {
-		startDocFastIncrementalSyncWorker(growthSvc)
+		startDocFastIncrementalSyncWorker(growthSvc, adminGrowthHandler.SyncDocFastNews)
 		startTushareNewsIncrementalSyncWorker(growthSvc)
 		startVIPMembershipLifecycleWorker(growthSvc)
+		startInviteCommissionReleaseWorker(growthSvc)
+		startMembershipOrderPollWorker(growthSvc, adminGrowthHandler.PaymentChannels())
+		startMembershipAutoRenewWorker(growthSvc, adminGrowthHandler.PaymentChannels())
+		startCommunitySentimentWorker(growthSvc)
+		startNewsScheduledPublishWorker(growthSvc)
+		startReviewSLAEscalationWorker(growthSvc)
+		startExportJobsWorker(growthSvc, adminGrowthHandler.RunExportJobs)
 		startForecastL3DispatchWorker(growthSvc)
 		startForecastL3QualityWorker(growthSvc)
 	}
@@ -67,20 +98,31 @@ func Register(r *gin.Engine) {
 		{
 			authGroup.POST("/register", authHandler.Register)
 			authGroup.POST("/login", authHandler.Login)
+			authGroup.POST("/login/2fa", authHandler.LoginTwoFactor)
+			authGroup.POST("/login/2fa/enroll", authHandler.LoginTwoFactorEnroll)
 			authGroup.POST("/refresh", authHandler.Refresh)
 			authGroup.POST("/logout", authHandler.Logout)
-			authGroup.POST("/logout-all", middleware.AuthRequired(cfg.JWTSecret), authHandler.LogoutAll)
+			authGroup.POST("/logout-all", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.LogoutAll)
 			if cfg.AllowMockLogin {
 				authGroup.POST("/mock-login", authHandler.MockLogin)
 			}
-			authGroup.GET("/me", middleware.AuthRequired(cfg.JWTSecret), authHandler.Me)
+			authGroup.GET("/me", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.Me)
+			authGroup.POST("/stream-ticket", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.IssueStreamTicket)
+			authGroup.GET("/sessions", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.ListSessions)
+			authGroup.DELETE("/sessions/:id", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.RevokeSession)
+			authGroup.GET("/2fa", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.GetTwoFactorStatus)
+			authGroup.POST("/2fa/enroll", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.EnrollTwoFactor)
+			authGroup.POST("/2fa/activate", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.ActivateTwoFactor)
+			authGroup.POST("/2fa/verify", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.VerifyTwoFactor)
+			authGroup.POST("/2fa/recovery-codes", middleware.AuthRequired(cfg.JWTSecret, sessionStore), authHandler.RegenerateTwoFactorRecoveryCodes)
 		}
 
 		adminAuth := v1.Group("/admin/auth")
-		adminAuth.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminAuth.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminAuth.GET("/login-logs", middleware.PermissionRequired(db, "auth_security.view"), authHandler.AdminListLoginLogs)
-			adminAuth.GET("/login-logs/export.csv", middleware.PermissionRequired(db, "auth_security.view"), authHandler.AdminExportLoginLogsCSV)
+			adminAuth.GET("/login-logs/export.csv", middleware.PermissionRequired(db, "auth_security.view"), adminGrowthHandler.ExportLoginLogsCSV)
+			adminAuth.POST("/login-logs/:id/reveal", middleware.PermissionRequired(db, "users.pii_reveal"), authHandler.AdminRevealLoginLogPII)
 			adminAuth.GET("/risk-config", middleware.PermissionRequired(db, "auth_security.view"), authHandler.AdminGetRiskConfig)
 			adminAuth.PUT("/risk-config", middleware.PermissionRequired(db, "auth_security.edit"), authHandler.AdminUpdateRiskConfig)
 			adminAuth.GET("/risk-config-logs", middleware.PermissionRequired(db, "auth_security.view"), authHandler.AdminListRiskConfigLogs)
@@ -89,24 +131,25 @@ func Register(r *gin.Engine) {
 		}
 
 		adminAccess := v1.Group("/admin/access")
-		adminAccess.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminAccess.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminAccess.GET("/me", authHandler.AdminGetAccessProfile)
 			adminAccess.GET("/permissions", middleware.PermissionRequired(db, "access.view"), authHandler.AdminListPermissions)
 			adminAccess.GET("/roles", middleware.PermissionRequired(db, "access.view"), authHandler.AdminListRoles)
-			adminAccess.POST("/roles", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminCreateRole)
-			adminAccess.PUT("/roles/:id", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminUpdateRole)
-			adminAccess.PUT("/roles/:id/status", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminUpdateRoleStatus)
+			adminAccess.POST("/roles", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminCreateRole)
+			adminAccess.PUT("/roles/:id", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminUpdateRole)
+			adminAccess.PUT("/roles/:id/status", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminUpdateRoleStatus)
 
 			adminAccess.GET("/admin-users", middleware.PermissionRequired(db, "access.view"), authHandler.AdminListAdminUsers)
-			adminAccess.POST("/admin-users", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminCreateAdminUser)
-			adminAccess.PUT("/admin-users/:id/status", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminUpdateAdminUserStatus)
-			adminAccess.PUT("/admin-users/:id/roles", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminAssignAdminUserRoles)
-			adminAccess.PUT("/admin-users/:id/password", middleware.PermissionRequired(db, "access.edit"), authHandler.AdminResetAdminUserPassword)
+			adminAccess.POST("/admin-users", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminCreateAdminUser)
+			adminAccess.PUT("/admin-users/:id/status", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminUpdateAdminUserStatus)
+			adminAccess.PUT("/admin-users/:id/roles", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminAssignAdminUserRoles)
+			adminAccess.PUT("/admin-users/:id/password", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminResetAdminUserPassword)
+			adminAccess.POST("/admin-users/:id/2fa/reset", middleware.PermissionRequired(db, "access.edit"), stepUp, authHandler.AdminResetUserTwoFactor)
 		}
 
 		user := v1.Group("/user")
-		user.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		user.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			user.GET("/profile", userGrowthHandler.GetUserProfile)
 			user.PUT("/profile", userGrowthHandler.UpdateUserProfile)
@@ -129,37 +172,48 @@ func Register(r *gin.Engine) {
 		}
 
 		subscriptions := v1.Group("/subscriptions")
-		subscriptions.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		subscriptions.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			subscriptions.GET("", userGrowthHandler.ListSubscriptions)
 			subscriptions.POST("", userGrowthHandler.CreateSubscription)
 			subscriptions.PUT("/:id", userGrowthHandler.UpdateSubscription)
 		}
 
+		// EventSource cannot set headers, so the streams also accept a
+		// single-use ?ticket= from /auth/stream-ticket.
+		streamAuth := middleware.StreamAuthRequired(cfg.JWTSecret, sessionStore, authHandler.StreamTickets())
+		v1.GET("/messages/stream", streamAuth, middleware.RoleRequired("USER", "ADMIN"), userGrowthHandler.StreamMessages)
+		v1.GET("/admin/workflow/messages/stream", streamAuth, middleware.RoleRequired("ADMIN"), middleware.PermissionRequired(db, "workflow.view"), adminGrowthHandler.StreamWorkflowMessages)
+
 		messages := v1.Group("/messages")
-		messages.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		messages.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			messages.GET("", userGrowthHandler.ListMessages)
 			messages.PUT("/:id/read", userGrowthHandler.ReadMessage)
 		}
 
 		search := v1.Group("/search")
-		search.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		search.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			search.GET("/global", userGrowthHandler.SearchGlobal)
 		}
 
 		membership := v1.Group("/membership")
-		membership.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		membership.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			membership.GET("/products", userGrowthHandler.ListMembershipProducts)
 			membership.POST("/orders", userGrowthHandler.CreateMembershipOrder)
 			membership.GET("/orders", userGrowthHandler.ListMembershipOrders)
+			membership.GET("/coupons", userGrowthHandler.ListMembershipCoupons)
+			membership.POST("/coupons/quote", userGrowthHandler.QuoteMembershipCoupon)
+			membership.GET("/auto-renew", userGrowthHandler.GetMembershipAutoRenew)
+			membership.POST("/auto-renew", userGrowthHandler.EnableMembershipAutoRenew)
+			membership.DELETE("/auto-renew", userGrowthHandler.CancelMembershipAutoRenew)
 			membership.GET("/quota", userGrowthHandler.GetMembershipQuota)
 		}
 
 		futures := v1.Group("/futures")
-		futures.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		futures.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			futures.GET("/arbitrage", userGrowthHandler.ListFuturesArbitrage)
 			futures.GET("/arbitrage/:id", userGrowthHandler.GetFuturesArbitrageDetail)
@@ -174,7 +228,7 @@ func Register(r *gin.Engine) {
 		}
 
 		stocks := v1.Group("/stocks")
-		stocks.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		stocks.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			stocks.GET("/recommendations", userGrowthHandler.ListStockRecommendations)
 			stocks.GET("/recommendations/:id", userGrowthHandler.GetStockRecommendationDetail)
@@ -184,7 +238,7 @@ func Register(r *gin.Engine) {
 		}
 
 		forecast := v1.Group("/forecast")
-		forecast.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		forecast.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			forecast.POST("/runs", userGrowthHandler.CreateForecastL3Run)
 			forecast.GET("/runs", userGrowthHandler.ListForecastL3Runs)
@@ -192,7 +246,7 @@ func Register(r *gin.Engine) {
 		}
 
 		news := v1.Group("/news")
-		news.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		news.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			news.GET("/categories", userGrowthHandler.ListNewsCategories)
 			news.GET("/articles", userGrowthHandler.ListNewsArticles)
@@ -201,9 +255,10 @@ func Register(r *gin.Engine) {
 			news.GET("/attachments/:id/signed-url", userGrowthHandler.GetAttachmentSignedURL)
 		}
 		v1.GET("/news/attachments/:id/download", userGrowthHandler.DownloadAttachment)
+		v1.GET("/exports/:id/download", adminGrowthHandler.DownloadExportFile)
 
 		community := v1.Group("/community")
-		community.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		community.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			community.GET("/topics", userGrowthHandler.ListCommunityTopics)
 			community.GET("/me/topics", userGrowthHandler.ListMyCommunityTopics)
@@ -215,6 +270,14 @@ func Register(r *gin.Engine) {
 			community.POST("/reactions", userGrowthHandler.CreateCommunityReaction)
 			community.DELETE("/reactions", userGrowthHandler.DeleteCommunityReaction)
 			community.POST("/reports", userGrowthHandler.CreateCommunityReport)
+			community.GET("/feed", userGrowthHandler.ListCommunityFeed)
+			community.GET("/follows", userGrowthHandler.ListCommunityFollows)
+			community.POST("/follows", userGrowthHandler.CreateCommunityFollow)
+			community.DELETE("/follows/:id", userGrowthHandler.DeleteCommunityFollow)
+			community.GET("/blocks", userGrowthHandler.ListCommunityBlocks)
+			community.POST("/blocks", userGrowthHandler.CreateCommunityBlock)
+			community.DELETE("/blocks/:id", userGrowthHandler.DeleteCommunityBlock)
+			community.GET("/mentions", userGrowthHandler.ListCommunityMentions)
 		}
 
 		public := v1.Group("/public")
@@ -233,21 +296,21 @@ func Register(r *gin.Engine) {
 		}
 
 		market := v1.Group("/market")
-		market.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		market.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			market.GET("/events", userGrowthHandler.ListMarketEvents)
 			market.GET("/events/:id", userGrowthHandler.GetMarketEventDetail)
 		}
 
 		payment := v1.Group("/payment")
-		payment.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("USER", "ADMIN"))
+		payment.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("USER", "ADMIN"))
 		{
 			payment.POST("/callbacks/:channel", userGrowthHandler.HandlePaymentCallback)
 		}
-		v1.Any("/payment/callbacks/yolkpay/notify", userGrowthHandler.HandleYolkPayCallback)
+		v1.Any("/payment/callbacks/:channel/notify", userGrowthHandler.HandlePaymentNotify)
 
 		admin := v1.Group("/admin/growth")
-		admin.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		admin.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			admin.GET("/invite-records", middleware.PermissionRequired(db, "growth.view"), adminGrowthHandler.ListInviteRecords)
 			admin.GET("/reward-records", middleware.PermissionRequired(db, "growth.view"), adminGrowthHandler.ListRewardRecords)
@@ -255,14 +318,15 @@ func Register(r *gin.Engine) {
 		}
 
 		adminPayment := v1.Group("/admin/payment")
-		adminPayment.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminPayment.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminPayment.GET("/reconciliation", middleware.PermissionRequired(db, "payment.view"), adminGrowthHandler.ListReconciliation)
-			adminPayment.POST("/reconciliation/:batch_id/retry", middleware.PermissionRequired(db, "payment.edit"), adminGrowthHandler.RetryReconciliation)
+			adminPayment.POST("/reconciliation/:batch_id/retry", middleware.PermissionRequired(db, "payment.edit"), stepUp, adminGrowthHandler.RetryReconciliation)
+			adminPayment.GET("/poll-logs", middleware.PermissionRequired(db, "payment.view"), adminGrowthHandler.ListPaymentPollLogs)
 		}
 
 		adminRisk := v1.Group("/admin/risk")
-		adminRisk.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminRisk.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminRisk.GET("/rules", middleware.PermissionRequired(db, "risk.view"), adminGrowthHandler.ListRiskRules)
 			adminRisk.POST("/rules", middleware.PermissionRequired(db, "risk.edit"), adminGrowthHandler.CreateRiskRule)
@@ -272,14 +336,14 @@ func Register(r *gin.Engine) {
 		}
 
 		adminRewardWallet := v1.Group("/admin/reward-wallet")
-		adminRewardWallet.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminRewardWallet.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminRewardWallet.GET("/withdraw-requests", middleware.PermissionRequired(db, "reward_wallet.view"), adminGrowthHandler.ListWithdrawRequests)
-			adminRewardWallet.PUT("/withdraw-requests/:id/review", middleware.PermissionRequired(db, "reward_wallet.edit"), adminGrowthHandler.ReviewWithdrawRequest)
+			adminRewardWallet.PUT("/withdraw-requests/:id/review", middleware.PermissionRequired(db, "reward_wallet.edit"), stepUp, adminGrowthHandler.ReviewWithdrawRequest)
 		}
 
 		adminNews := v1.Group("/admin/news")
-		adminNews.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminNews.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminNews.GET("/categories", middleware.PermissionRequired(db, "news.view"), adminGrowthHandler.ListNewsCategories)
 			adminNews.POST("/categories", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.CreateNewsCategory)
@@ -290,16 +354,28 @@ func Register(r *gin.Engine) {
 			adminNews.POST("/articles", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.CreateNewsArticle)
 			adminNews.PUT("/articles/:id", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.UpdateNewsArticle)
 			adminNews.PUT("/articles/:id/publish", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.PublishNewsArticle)
+			adminNews.POST("/articles/:id/schedule", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.ScheduleNewsArticle)
+			adminNews.DELETE("/articles/:id/schedule", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.CancelNewsArticleSchedule)
+			adminNews.GET("/articles/:id/revisions", middleware.PermissionRequired(db, "news.view"), adminGrowthHandler.ListNewsArticleRevisions)
+			adminNews.GET("/articles/:id/revisions/:revision_no", middleware.PermissionRequired(db, "news.view"), adminGrowthHandler.GetNewsArticleRevision)
+			adminNews.GET("/articles/:id/revisions/:revision_no/diff", middleware.PermissionRequired(db, "news.view"), adminGrowthHandler.DiffNewsArticleRevision)
+			adminNews.POST("/articles/:id/revisions/:revision_no/restore", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.RestoreNewsArticleRevision)
+			adminNews.POST("/articles/:id/merge", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.MergeNewsArticleDuplicate)
+			adminNews.DELETE("/articles/:id/merge", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.UnmergeNewsArticleDuplicate)
 
 			adminNews.POST("/attachments/upload", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.UploadNewsAttachment)
 			adminNews.GET("/articles/:id/attachments", middleware.PermissionRequired(db, "news.view"), adminGrowthHandler.ListNewsAttachments)
 			adminNews.POST("/articles/:id/attachments", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.CreateNewsAttachment)
 			adminNews.DELETE("/attachments/:id", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.DeleteNewsAttachment)
+			adminNews.GET("/attachments/:id/download", middleware.PermissionRequired(db, "news.view"), adminGrowthHandler.DownloadNewsAttachment)
+			adminNews.POST("/attachments/:id/text/extract", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.ExtractNewsAttachmentText)
+			adminNews.POST("/attachments/migrate-legacy", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.MigrateLegacyNewsAttachments)
+			adminNews.POST("/attachments/watermark/trace", middleware.PermissionRequired(db, "users.view"), adminGrowthHandler.TraceAttachmentWatermark)
 			adminNews.POST("/market-sync", middleware.PermissionRequired(db, "news.edit"), adminGrowthHandler.SyncMarketNewsSource)
 		}
 
 		adminCommunity := v1.Group("/admin/community")
-		adminCommunity.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminCommunity.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminCommunity.GET("/topics", middleware.PermissionRequired(db, "community.view"), adminGrowthHandler.ListCommunityTopics)
 			adminCommunity.PUT("/topics/:id/status", middleware.PermissionRequired(db, "community.edit"), adminGrowthHandler.UpdateCommunityTopicStatus)
@@ -307,10 +383,19 @@ func Register(r *gin.Engine) {
 			adminCommunity.PUT("/comments/:id/status", middleware.PermissionRequired(db, "community.edit"), adminGrowthHandler.UpdateCommunityCommentStatus)
 			adminCommunity.GET("/reports", middleware.PermissionRequired(db, "community.view"), adminGrowthHandler.ListCommunityReports)
 			adminCommunity.PUT("/reports/:id/review", middleware.PermissionRequired(db, "community.review"), adminGrowthHandler.ReviewCommunityReport)
+			adminCommunity.GET("/sensitive-words", middleware.PermissionRequired(db, "community.view"), adminGrowthHandler.ListCommunitySensitiveWords)
+			adminCommunity.POST("/sensitive-words", middleware.PermissionRequired(db, "community.edit"), adminGrowthHandler.CreateCommunitySensitiveWord)
+			adminCommunity.PUT("/sensitive-words/:id/status", middleware.PermissionRequired(db, "community.edit"), adminGrowthHandler.UpdateCommunitySensitiveWordStatus)
+			adminCommunity.GET("/moderation-flags", middleware.PermissionRequired(db, "community.view"), adminGrowthHandler.ListCommunityModerationFlags)
+			adminCommunity.GET("/bans", middleware.PermissionRequired(db, "community.view"), adminGrowthHandler.ListCommunityUserBans)
+			adminCommunity.POST("/bans", middleware.PermissionRequired(db, "community.review"), adminGrowthHandler.CreateCommunityUserBan)
+			adminCommunity.POST("/bans/:id/lift", middleware.PermissionRequired(db, "community.review"), adminGrowthHandler.LiftCommunityUserBan)
+			adminCommunity.GET("/sentiment", middleware.PermissionRequired(db, "community.view"), adminGrowthHandler.ListCommunitySentiment)
+			adminCommunity.POST("/sentiment/rebuild", middleware.PermissionRequired(db, "community.edit"), adminGrowthHandler.RebuildCommunitySentiment)
 		}
 
 		adminDataSources := v1.Group("/admin/data-sources")
-		adminDataSources.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminDataSources.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminDataSources.GET("", middleware.PermissionRequired(db, "data_source.view"), adminGrowthHandler.ListDataSources)
 			adminDataSources.GET("/governance/overview", middleware.PermissionRequired(db, "data_source.view"), adminGrowthHandler.GetMarketProviderGovernanceOverview)
@@ -329,7 +414,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminMarketData := v1.Group("/admin/market-data")
-		adminMarketData.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminMarketData.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminMarketData.POST("/backfill", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.CreateMarketDataBackfillRun)
 			adminMarketData.POST("/master/sync", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.SyncMarketDataMaster)
@@ -347,7 +432,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminForecast := v1.Group("/admin/forecast")
-		adminForecast.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminForecast.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminForecast.GET("/runs", middleware.PermissionRequired(db, "forecast_l3.view"), adminGrowthHandler.ListForecastL3Runs)
 			adminForecast.POST("/runs", middleware.PermissionRequired(db, "forecast_l3.edit"), adminGrowthHandler.CreateForecastL3Run)
@@ -358,7 +443,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminStocks := v1.Group("/admin/stocks")
-		adminStocks.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminStocks.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminStocks.GET("/recommendations", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.ListStockRecommendations)
 			adminStocks.POST("/recommendations", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.CreateStockRecommendation)
@@ -381,7 +466,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminStockSelection := v1.Group("/admin/stock-selection")
-		adminStockSelection.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminStockSelection.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminStockSelection.GET("/overview", middleware.PermissionRequired(db, "stock_selection.view"), adminGrowthHandler.GetStockSelectionOverview)
 			adminStockSelection.GET("/runs", middleware.PermissionRequired(db, "stock_selection.view"), adminGrowthHandler.ListStockSelectionRuns)
@@ -412,7 +497,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminFuturesSelection := v1.Group("/admin/futures-selection")
-		adminFuturesSelection.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminFuturesSelection.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminFuturesSelection.GET("/overview", middleware.PermissionRequired(db, "futures_selection.view"), adminGrowthHandler.GetFuturesSelectionOverview)
 			adminFuturesSelection.GET("/runs", middleware.PermissionRequired(db, "futures_selection.view"), adminGrowthHandler.ListFuturesSelectionRuns)
@@ -439,14 +524,14 @@ func Register(r *gin.Engine) {
 		}
 
 		adminStrategyGraph := v1.Group("/admin/strategy-graph")
-		adminStrategyGraph.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminStrategyGraph.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminStrategyGraph.GET("/snapshots/:snapshot_id", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.GetStrategyGraphSnapshot)
 			adminStrategyGraph.GET("/subgraph", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.QueryStrategyGraphSubgraph)
 		}
 
 		adminFutures := v1.Group("/admin/futures")
-		adminFutures.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminFutures.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminFutures.GET("/strategies", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.ListFuturesStrategies)
 			adminFutures.POST("/strategies", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.CreateFuturesStrategy)
@@ -455,6 +540,7 @@ func Register(r *gin.Engine) {
 			adminFutures.POST("/quotes/rebuild-derived-truth", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.RebuildFuturesDerivedTruth)
 			adminFutures.POST("/inventory/sync", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.SyncFuturesInventory)
 			adminFutures.POST("/strategies/generate-daily", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.GenerateDailyFuturesStrategies)
+			adminFutures.POST("/arbitrage/compute", middleware.PermissionRequired(db, "market.edit"), adminGrowthHandler.RunFuturesArbitrageEngine)
 			adminFutures.GET("/strategy-engine/publish-history", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.ListStrategyEngineFuturesPublishHistory)
 			adminFutures.GET("/strategy-engine/publish-records/:publish_id", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.GetStrategyEngineFuturesPublishRecord)
 			adminFutures.GET("/strategy-engine/publish-records/:publish_id/replay", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.GetStrategyEngineFuturesPublishReplay)
@@ -462,7 +548,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminMarket := v1.Group("/admin/market")
-		adminMarket.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminMarket.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminMarket.GET("/events", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.ListMarketEvents)
 			adminMarket.GET("/rhythm-tasks", middleware.PermissionRequired(db, "market.view"), adminGrowthHandler.ListMarketRhythmTasks)
@@ -490,7 +576,7 @@ func Register(r *gin.Engine) {
 		}
 
 		adminUsers := v1.Group("/admin/users")
-		adminUsers.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminUsers.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminUsers.GET("", middleware.PermissionRequired(db, "users.view"), adminGrowthHandler.ListUsers)
 			adminUsers.GET("/source-summary", middleware.PermissionRequired(db, "users.view"), adminGrowthHandler.UserSourceSummary)
@@ -503,29 +589,47 @@ func Register(r *gin.Engine) {
 			adminUsers.GET("/messages", middleware.PermissionRequired(db, "users.view"), adminGrowthHandler.ListUserMessages)
 			adminUsers.POST("/messages", middleware.PermissionRequired(db, "users.edit"), adminGrowthHandler.CreateUserMessages)
 			adminUsers.GET("/:id/center-overview", middleware.PermissionRequired(db, "users.view"), adminGrowthHandler.GetUserCenterOverview)
+			adminUsers.POST("/:id/pii/reveal", middleware.PermissionRequired(db, "users.pii_reveal"), adminGrowthHandler.RevealUserPII)
 			adminUsers.PUT("/:id/subscriptions/:sub_id", middleware.PermissionRequired(db, "users.edit"), adminGrowthHandler.UpdateUserSubscription)
 			adminUsers.PUT("/:id/status", middleware.PermissionRequired(db, "users.edit"), adminGrowthHandler.UpdateUserStatus)
 			adminUsers.PUT("/:id/member-level", middleware.PermissionRequired(db, "users.edit"), adminGrowthHandler.UpdateUserMemberLevel)
 			adminUsers.PUT("/:id/password", middleware.PermissionRequired(db, "users.edit"), adminGrowthHandler.ResetUserPassword)
+			adminUsers.GET("/:id/sessions", middleware.PermissionRequired(db, "users.view"), authHandler.AdminListUserSessions)
+			adminUsers.DELETE("/:id/sessions/:session_id", middleware.PermissionRequired(db, "users.edit"), authHandler.AdminRevokeUserSession)
 		}
 
 		adminDashboard := v1.Group("/admin/dashboard")
-		adminDashboard.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminDashboard.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminDashboard.GET("/overview", middleware.PermissionRequired(db, "dashboard.view"), adminGrowthHandler.DashboardOverview)
 		}
 
+		adminExports := v1.Group("/admin/exports")
+		adminExports.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
+		{
+			adminExports.POST("/:type", middleware.PermissionRequiredFor(db, adminGrowthHandler.ExportPermissions), adminGrowthHandler.RequestExportJob)
+			adminExports.GET("/jobs", adminGrowthHandler.ListExportJobs)
+			adminExports.GET("/jobs/:id", adminGrowthHandler.GetExportJob)
+			adminExports.POST("/jobs/:id/link", adminGrowthHandler.CreateExportDownloadLink)
+		}
+
 		adminAudit := v1.Group("/admin/audit")
-		adminAudit.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminAudit.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminAudit.GET("/events/summary", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.GetAuditEventSummary)
 			adminAudit.GET("/events", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ListAuditEvents)
 			adminAudit.GET("/operation-logs", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ListOperationLogs)
 			adminAudit.GET("/operation-logs/export.csv", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ExportOperationLogsCSV)
+			adminAudit.GET("/ledger/verify", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.VerifyAuditLedger)
+			adminAudit.GET("/ledger/checkpoints", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ListAuditCheckpoints)
+			adminAudit.POST("/ledger/checkpoints", middleware.PermissionRequired(db, "audit.edit"), adminGrowthHandler.CreateAuditCheckpoint)
+			adminAudit.GET("/ledger/export", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ExportAuditLedger)
+			adminAudit.GET("/exports", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ListAllExportJobs)
+			adminAudit.GET("/exports/:id/downloads", middleware.PermissionRequired(db, "audit.view"), adminGrowthHandler.ListExportDownloads)
 		}
 
 		adminMembership := v1.Group("/admin/membership")
-		adminMembership.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminMembership.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminMembership.GET("/products", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipProducts)
 			adminMembership.POST("/products", middleware.PermissionRequired(db, "membership.edit"), adminGrowthHandler.CreateMembershipProduct)
@@ -535,6 +639,14 @@ func Register(r *gin.Engine) {
 			adminMembership.GET("/orders", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipOrders)
 			adminMembership.GET("/orders/export.csv", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ExportMembershipOrdersCSV)
 			adminMembership.PUT("/orders/:id/status", middleware.PermissionRequired(db, "membership.edit"), adminGrowthHandler.UpdateMembershipOrderStatus)
+			adminMembership.POST("/orders/:id/refund", middleware.PermissionRequired(db, "payment.edit"), stepUp, adminGrowthHandler.RefundMembershipOrder)
+			adminMembership.GET("/refunds", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipRefunds)
+			adminMembership.GET("/coupons", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipCoupons)
+			adminMembership.POST("/coupons", middleware.PermissionRequired(db, "membership.edit"), adminGrowthHandler.CreateMembershipCoupon)
+			adminMembership.PUT("/coupons/:id/status", middleware.PermissionRequired(db, "membership.edit"), adminGrowthHandler.UpdateMembershipCouponStatus)
+			adminMembership.GET("/coupon-redemptions", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipCouponRedemptions)
+			adminMembership.GET("/auto-renewals", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipAutoRenewals)
+			adminMembership.GET("/renewal-attempts", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListMembershipRenewalAttempts)
 
 			adminMembership.GET("/quota-configs", middleware.PermissionRequired(db, "membership.view"), adminGrowthHandler.ListVIPQuotaConfigs)
 			adminMembership.POST("/quota-configs", middleware.PermissionRequired(db, "membership.edit"), adminGrowthHandler.CreateVIPQuotaConfig)
@@ -544,12 +656,13 @@ func Register(r *gin.Engine) {
 		}
 
 		adminSystem := v1.Group("/admin/system")
-		adminSystem.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminSystem.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminSystem.GET("/configs", middleware.PermissionRequired(db, "system_config.view"), adminGrowthHandler.ListSystemConfigs)
-			adminSystem.PUT("/configs", middleware.PermissionRequired(db, "system_config.edit"), adminGrowthHandler.UpsertSystemConfig)
-			adminSystem.POST("/configs/oss/qiniu/test", middleware.PermissionRequired(db, "system_config.edit"), adminGrowthHandler.TestOSSQiniuConfig)
-			adminSystem.POST("/configs/payment/yolkpay/test", middleware.PermissionRequired(db, "system_config.edit"), adminGrowthHandler.TestYolkPayConfig)
+			adminSystem.PUT("/configs", middleware.PermissionRequired(db, "system_config.edit"), stepUp, adminGrowthHandler.UpsertSystemConfig)
+			adminSystem.POST("/configs/secrets/rotate", middleware.PermissionRequired(db, "system_config.edit"), stepUp, adminGrowthHandler.RotateConfigSecrets)
+			adminSystem.POST("/configs/oss/qiniu/test", middleware.PermissionRequired(db, "system_config.edit"), stepUp, adminGrowthHandler.TestOSSQiniuConfig)
+			adminSystem.POST("/configs/payment/yolkpay/test", middleware.PermissionRequired(db, "system_config.edit"), stepUp, adminGrowthHandler.TestYolkPayConfig)
 
 			adminSystem.GET("/job-definitions", middleware.PermissionRequired(db, "system_job.view"), adminGrowthHandler.ListSchedulerJobDefinitions)
 			adminSystem.GET("/job-definitions/supported", middleware.PermissionRequired(db, "system_job.view"), adminGrowthHandler.ListSupportedSchedulerJobs)
@@ -565,10 +678,16 @@ func Register(r *gin.Engine) {
 			adminSystem.POST("/job-runs/trigger", middleware.PermissionRequired(db, "system_job.edit"), adminGrowthHandler.TriggerSchedulerJob)
 			adminSystem.POST("/job-runs/:id/retry", middleware.PermissionRequired(db, "system_job.edit"), adminGrowthHandler.RetrySchedulerJobRun)
 			adminSystem.POST("/job-runs/:id/retry-news-sync-item", middleware.PermissionRequired(db, "system_job.edit"), adminGrowthHandler.RetryNewsSyncItem)
+			adminSystem.GET("/pipelines", middleware.PermissionRequired(db, "system_job.view"), adminGrowthHandler.ListSchedulerPipelines)
+			adminSystem.PUT("/pipelines/:key", middleware.PermissionRequired(db, "system_job.edit"), adminGrowthHandler.SaveSchedulerPipeline)
+			adminSystem.POST("/pipelines/:key/runs", middleware.PermissionRequired(db, "system_job.edit"), adminGrowthHandler.TriggerSchedulerPipeline)
+			adminSystem.GET("/pipeline-runs", middleware.PermissionRequired(db, "system_job.view"), adminGrowthHandler.ListSchedulerPipelineRuns)
+			adminSystem.GET("/pipeline-runs/:id", middleware.PermissionRequired(db, "system_job.view"), adminGrowthHandler.GetSchedulerPipelineRun)
+			adminSystem.POST("/pipeline-runs/:id/nodes/:job_name/rerun", middleware.PermissionRequired(db, "system_job.edit"), adminGrowthHandler.RerunSchedulerPipelineNode)
 		}
 
 		adminWorkflow := v1.Group("/admin/workflow")
-		adminWorkflow.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.RoleRequired("ADMIN"))
+		adminWorkflow.Use(middleware.AuthRequired(cfg.JWTSecret, sessionStore), middleware.RoleRequired("ADMIN"))
 		{
 			adminWorkflow.GET("/reviews", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ListReviewTasks)
 			adminWorkflow.GET("/reviews/export.csv", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ExportReviewTasksCSV)
@@ -576,6 +695,16 @@ func Register(r *gin.Engine) {
 			adminWorkflow.POST("/reviews/submit", middleware.PermissionRequired(db, "review.edit"), adminGrowthHandler.SubmitReviewTask)
 			adminWorkflow.PUT("/reviews/:id/assign", middleware.PermissionRequired(db, "review.edit"), adminGrowthHandler.AssignReviewTask)
 			adminWorkflow.PUT("/reviews/:id/decision", middleware.PermissionRequired(db, "review.edit"), adminGrowthHandler.ReviewTaskDecision)
+			adminWorkflow.GET("/approval-policies", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ListApprovalPolicies)
+			adminWorkflow.PUT("/approval-policies/:module", middleware.PermissionRequired(db, "approval_policy.edit"), stepUp, adminGrowthHandler.SaveApprovalPolicy)
+			adminWorkflow.GET("/approvals", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ListApprovalRequests)
+			adminWorkflow.GET("/approvals/:id", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.GetApprovalRequest)
+			adminWorkflow.POST("/approvals/:id/decision", middleware.PermissionRequired(db, "review.edit"), stepUp, adminGrowthHandler.DecideApprovalRequest)
+			adminWorkflow.POST("/approvals/:id/execute", middleware.PermissionRequired(db, "review.edit"), stepUp, adminGrowthHandler.RetryApprovalExecution)
+			adminWorkflow.GET("/sla/policies", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ListReviewSLAPolicies)
+			adminWorkflow.PUT("/sla/policies/:module", middleware.PermissionRequired(db, "review.edit"), adminGrowthHandler.SaveReviewSLAPolicy)
+			adminWorkflow.GET("/sla/items", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ListReviewSLAItems)
+			adminWorkflow.GET("/sla/metrics", middleware.PermissionRequired(db, "review.view"), adminGrowthHandler.ReviewSLAMetrics)
 			adminWorkflow.GET("/messages", middleware.PermissionRequired(db, "workflow.view"), adminGrowthHandler.ListWorkflowMessages)
 			adminWorkflow.GET("/messages/export.csv", middleware.PermissionRequired(db, "workflow.view"), adminGrowthHandler.ExportWorkflowMessagesCSV)
 			adminWorkflow.GET("/messages/unread-count", middleware.PermissionRequired(db, "workflow.view"), adminGrowthHandler.CountUnreadWorkflowMessages)
@@ -596,28 +725,49 @@ func Register(r *gin.Engine) {
 }
 
 const (
-	docFastIncrementalJobName            = "doc_fast_news_incremental"
-	docFastIncrementalDefaultMinutes     = 100
-	docFastIncrementalMaxMinutes         = 24 * 60
-	tushareNewsIncrementalJobName        = "tushare_news_incremental"
-	tushareNewsIncrementalDefaultMinutes = 20
-	tushareNewsIncrementalMaxMinutes     = 24 * 60
-	vipLifecycleJobName                  = "vip_membership_lifecycle"
-	vipLifecycleDefaultMinutes           = 30
-	vipLifecycleMaxMinutes               = 24 * 60
-	forecastL3DispatchJobName            = "forecast_l3_dispatch_pending"
-	forecastL3DispatchDefaultMinutes     = 5
-	forecastL3QualityJobName             = "forecast_l3_quality_backfill"
-	forecastL3QualityDefaultMinutes      = 60
+	docFastIncrementalJobName             = "doc_fast_news_incremental"
+	docFastIncrementalDefaultMinutes      = 100
+	docFastIncrementalMaxMinutes          = 24 * 60
+	tushareNewsIncrementalJobName         = "tushare_news_incremental"
+	tushareNewsIncrementalDefaultMinutes  = 20
+	tushareNewsIncrementalMaxMinutes      = 24 * 60
+	vipLifecycleJobName                   = "vip_membership_lifecycle"
+	vipLifecycleDefaultMinutes            = 30
+	vipLifecycleMaxMinutes                = 24 * 60
+	inviteCommissionReleaseJobName        = "invite_commission_release"
+	inviteCommissionReleaseDefaultMinutes = 60
+	inviteCommissionReleaseMaxMinutes     = 24 * 60
+	membershipOrderPollJobName            = "membership_order_poll"
+	membershipOrderPollDefaultMinutes     = 5
+	membershipOrderPollMaxMinutes         = 24 * 60
+	membershipAutoRenewJobName            = "membership_auto_renew"
+	membershipAutoRenewDefaultMinutes     = 30
+	membershipAutoRenewMaxMinutes         = 24 * 60
+	communitySentimentJobName             = "community_sentiment_index"
+	communitySentimentDefaultMinutes      = 60
+	communitySentimentMaxMinutes          = 24 * 60
+	newsScheduledPublishJobName           = "news_scheduled_publish"
+	newsScheduledPublishDefaultMinutes    = 1
+	newsScheduledPublishMaxMinutes        = 60
+	reviewSLAEscalationJobName            = "review_sla_escalation"
+	reviewSLAEscalationDefaultMinutes     = 5
+	reviewSLAEscalationMaxMinutes         = 60
+	exportJobsJobName                     = "export_jobs"
+	exportJobsDefaultMinutes              = 1
+	exportJobsMaxMinutes                  = 60
+	forecastL3DispatchJobName             = "forecast_l3_dispatch_pending"
+	forecastL3DispatchDefaultMinutes      = 5
+	forecastL3QualityJobName              = "forecast_l3_quality_backfill"
+	forecastL3QualityDefaultMinutes       = 60
 )
 
-func startDocFastIncrementalSyncWorker(growthSvc service.GrowthService) {
+func startDocFastIncrementalSyncWorker(growthSvc service.GrowthService, syncDocFast func(context.Context) (string, error)) {
 	go func() {
 		log.Printf("[scheduler] start doc_fast incremental worker")
 		for {
 			enabled, intervalMinutes := loadDocFastIncrementalWorkerConfig(growthSvc)
 			if enabled {
-				runDocFastIncrementalJob(growthSvc, "SYSTEM_TIMER")
+				runDocFastIncrementalJob(growthSvc, syncDocFast, "SYSTEM_TIMER")
 			}
 			if intervalMinutes <= 0 {
 				intervalMinutes = docFastIncrementalDefaultMinutes
@@ -627,8 +777,10 @@ func startDocFastIncrementalSyncWorker(growthSvc service.GrowthService) {
 	}()
 }
 
-func runDocFastIncrementalJob(growthSvc service.GrowthService, triggerSource string) {
-	summary, runErr := growthSvc.AdminSyncDocFastNewsIncremental(0)
+// runDocFastIncrementalJob runs syncDocFast, which also extracts text from
+// newly synced attachments, and records the run.
+func runDocFastIncrementalJob(growthSvc service.GrowthService, syncDocFast func(context.Context) (string, error), triggerSource string) {
+	summary, runErr := syncDocFast(context.Background())
 	status := "SUCCESS"
 	errorMessage := ""
 	if runErr != nil {
@@ -741,6 +893,312 @@ func runVIPMembershipLifecycleJob(growthSvc service.GrowthService, triggerSource
 	log.Printf("[scheduler] job success(%s): %s", vipLifecycleJobName, strings.TrimSpace(summary))
 }
 
+func startInviteCommissionReleaseWorker(growthSvc service.GrowthService) {
+	go func() {
+		log.Printf("[scheduler] start invite commission release worker")
+		for {
+			enabled, intervalMinutes := loadInviteCommissionReleaseWorkerConfig(growthSvc)
+			if enabled {
+				runInviteCommissionReleaseJob(growthSvc, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = inviteCommissionReleaseDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runInviteCommissionReleaseJob(growthSvc service.GrowthService, triggerSource string) {
+	summary, runErr := growthSvc.AdminReleaseInviteCommissions()
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		inviteCommissionReleaseJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", inviteCommissionReleaseJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", inviteCommissionReleaseJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", inviteCommissionReleaseJobName, strings.TrimSpace(summary))
+}
+
+func startMembershipOrderPollWorker(growthSvc service.GrowthService, channels payment.Resolver) {
+	go func() {
+		log.Printf("[scheduler] start membership order poll worker")
+		for {
+			enabled, intervalMinutes := loadMembershipOrderPollWorkerConfig(growthSvc)
+			if enabled {
+				runMembershipOrderPollJob(growthSvc, channels, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = membershipOrderPollDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runMembershipOrderPollJob(growthSvc service.GrowthService, channels payment.Resolver, triggerSource string) {
+	summary, runErr := growthSvc.AdminPollPendingMembershipOrders(channels)
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		membershipOrderPollJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", membershipOrderPollJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", membershipOrderPollJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", membershipOrderPollJobName, strings.TrimSpace(summary))
+}
+
+func startMembershipAutoRenewWorker(growthSvc service.GrowthService, channels payment.Resolver) {
+	go func() {
+		log.Printf("[scheduler] start membership auto-renew worker")
+		for {
+			enabled, intervalMinutes := loadMembershipAutoRenewWorkerConfig(growthSvc)
+			if enabled {
+				runMembershipAutoRenewJob(growthSvc, channels, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = membershipAutoRenewDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runMembershipAutoRenewJob(growthSvc service.GrowthService, channels payment.Resolver, triggerSource string) {
+	summary, runErr := growthSvc.AdminRunMembershipAutoRenewals(channels)
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		membershipAutoRenewJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", membershipAutoRenewJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", membershipAutoRenewJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", membershipAutoRenewJobName, strings.TrimSpace(summary))
+}
+
+func startCommunitySentimentWorker(growthSvc service.GrowthService) {
+	go func() {
+		log.Printf("[scheduler] start community sentiment worker")
+		for {
+			enabled, intervalMinutes := loadCommunitySentimentWorkerConfig(growthSvc)
+			if enabled {
+				runCommunitySentimentJob(growthSvc, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = communitySentimentDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runCommunitySentimentJob(growthSvc service.GrowthService, triggerSource string) {
+	summary, runErr := growthSvc.AdminRunCommunitySentimentIndex()
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		communitySentimentJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", communitySentimentJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", communitySentimentJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", communitySentimentJobName, strings.TrimSpace(summary))
+}
+
+func startNewsScheduledPublishWorker(growthSvc service.GrowthService) {
+	go func() {
+		log.Printf("[scheduler] start news scheduled publish worker")
+		for {
+			enabled, intervalMinutes := loadNewsScheduledPublishWorkerConfig(growthSvc)
+			if enabled {
+				runNewsScheduledPublishJob(growthSvc, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = newsScheduledPublishDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runNewsScheduledPublishJob(growthSvc service.GrowthService, triggerSource string) {
+	summary, runErr := growthSvc.AdminRunNewsScheduledPublish()
+	// Idle ticks run every minute; only record runs that did something.
+	if runErr == nil && summary == "published=0 notified=0" {
+		return
+	}
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		newsScheduledPublishJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", newsScheduledPublishJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", newsScheduledPublishJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", newsScheduledPublishJobName, strings.TrimSpace(summary))
+}
+
+func startReviewSLAEscalationWorker(growthSvc service.GrowthService) {
+	go func() {
+		log.Printf("[scheduler] start review sla escalation worker")
+		for {
+			enabled, intervalMinutes := loadReviewSLAEscalationWorkerConfig(growthSvc)
+			if enabled {
+				runReviewSLAEscalationJob(growthSvc, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = reviewSLAEscalationDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runReviewSLAEscalationJob(growthSvc service.GrowthService, triggerSource string) {
+	summary, runErr := growthSvc.AdminRunReviewSLAEscalation()
+	// Most ticks find nothing overdue; only record runs that did something.
+	if runErr == nil && strings.HasPrefix(summary, "breached=0 ") {
+		return
+	}
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		reviewSLAEscalationJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", reviewSLAEscalationJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", reviewSLAEscalationJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", reviewSLAEscalationJobName, strings.TrimSpace(summary))
+}
+
+func startExportJobsWorker(growthSvc service.GrowthService, runExports func(context.Context) (string, error)) {
+	go func() {
+		log.Printf("[scheduler] start export jobs worker")
+		for {
+			enabled, intervalMinutes := loadExportJobsWorkerConfig(growthSvc)
+			if enabled {
+				runExportJobsJob(growthSvc, runExports, "SYSTEM_TIMER")
+			}
+			if intervalMinutes <= 0 {
+				intervalMinutes = exportJobsDefaultMinutes
+			}
+			time.Sleep(time.Duration(intervalMinutes) * time.Minute)
+		}
+	}()
+}
+
+func runExportJobsJob(growthSvc service.GrowthService, runExports func(context.Context) (string, error), triggerSource string) {
+	summary, runErr := runExports(context.Background())
+	// The worker ticks every minute; only record runs that did something.
+	if runErr == nil && summary == "exported=0 failed=0 expired=0" {
+		return
+	}
+	status := "SUCCESS"
+	errorMessage := ""
+	if runErr != nil {
+		status = "FAILED"
+		errorMessage = runErr.Error()
+	}
+	_, logErr := growthSvc.AdminCreateSchedulerJobRun(
+		exportJobsJobName,
+		triggerSource,
+		status,
+		summary,
+		errorMessage,
+		"system",
+	)
+	if logErr != nil {
+		log.Printf("[scheduler] create job run failed(%s): %v", exportJobsJobName, logErr)
+	}
+	if runErr != nil {
+		log.Printf("[scheduler] job failed(%s): %v", exportJobsJobName, runErr)
+		return
+	}
+	log.Printf("[scheduler] job success(%s): %s", exportJobsJobName, strings.TrimSpace(summary))
+}
+
 func startForecastL3DispatchWorker(growthSvc service.GrowthService) {
 	go func() {
 		log.Printf("[scheduler] start forecast l3 dispatch worker")
@@ -960,6 +1418,195 @@ func loadVIPMembershipLifecycleWorkerConfig(growthSvc service.GrowthService) (bo
 	return enabled, intervalMinutes
 }
 
+func loadInviteCommissionReleaseWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := inviteCommissionReleaseDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("invite.commission.release.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "invite.commission.release.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "invite.commission.release.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = inviteCommissionReleaseDefaultMinutes
+	}
+	if intervalMinutes > inviteCommissionReleaseMaxMinutes {
+		intervalMinutes = inviteCommissionReleaseMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
+func loadMembershipOrderPollWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := membershipOrderPollDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("payment.order_poll.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "payment.order_poll.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "payment.order_poll.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = membershipOrderPollDefaultMinutes
+	}
+	if intervalMinutes > membershipOrderPollMaxMinutes {
+		intervalMinutes = membershipOrderPollMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
+func loadMembershipAutoRenewWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := membershipAutoRenewDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("membership.auto_renew.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "membership.auto_renew.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "membership.auto_renew.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = membershipAutoRenewDefaultMinutes
+	}
+	if intervalMinutes > membershipAutoRenewMaxMinutes {
+		intervalMinutes = membershipAutoRenewMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
+func loadCommunitySentimentWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := communitySentimentDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("community.sentiment.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "community.sentiment.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "community.sentiment.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = communitySentimentDefaultMinutes
+	}
+	if intervalMinutes > communitySentimentMaxMinutes {
+		intervalMinutes = communitySentimentMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
+func loadNewsScheduledPublishWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := newsScheduledPublishDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("news.schedule.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "news.schedule.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "news.schedule.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = newsScheduledPublishDefaultMinutes
+	}
+	if intervalMinutes > newsScheduledPublishMaxMinutes {
+		intervalMinutes = newsScheduledPublishMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
+func loadReviewSLAEscalationWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := reviewSLAEscalationDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("review.sla.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "review.sla.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "review.sla.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = reviewSLAEscalationDefaultMinutes
+	}
+	if intervalMinutes > reviewSLAEscalationMaxMinutes {
+		intervalMinutes = reviewSLAEscalationMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
+func loadExportJobsWorkerConfig(growthSvc service.GrowthService) (bool, int) {
+	enabled := true
+	intervalMinutes := exportJobsDefaultMinutes
+
+	items, _, err := growthSvc.AdminListSystemConfigs("export.worker.", 1, 50)
+	if err != nil {
+		return enabled, intervalMinutes
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.ConfigKey))
+		value := strings.TrimSpace(item.ConfigValue)
+		switch key {
+		case "export.worker.enabled":
+			enabled = parseRouterBoolConfig(value, enabled)
+		case "export.worker.interval_minutes":
+			intervalMinutes = parseRouterIntConfig(value, intervalMinutes)
+		}
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = exportJobsDefaultMinutes
+	}
+	if intervalMinutes > exportJobsMaxMinutes {
+		intervalMinutes = exportJobsMaxMinutes
+	}
+	return enabled, intervalMinutes
+}
+
 func parseRouterBoolConfig(raw string, fallback bool) bool {
 	text := strings.ToLower(strings.TrimSpace(raw))
 	if text == "" {
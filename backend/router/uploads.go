@@ -0,0 +1,38 @@
+package router
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicUploadImageExts are the files RegisterPublicUploads serves. Cover
+// images are the only uploads that stay public; attachments live in the
+// attachment store and legacy ones are moved there by
+// POST /api/v1/admin/news/attachments/migrate-legacy.
+var publicUploadImageExts = map[string]struct{}{
+	".jpg":  {},
+	".jpeg": {},
+	".png":  {},
+	".gif":  {},
+	".webp": {},
+	".bmp":  {},
+}
+
+// RegisterPublicUploads serves cover images from uploadDir under /uploads.
+// Any other file, including attachments not yet migrated, gets a 404, and
+// directories are never listed.
+func RegisterPublicUploads(r *gin.Engine, uploadDir string) {
+	fileServer := http.StripPrefix("/uploads", http.FileServer(gin.Dir(uploadDir, false)))
+	serve := func(c *gin.Context) {
+		if _, ok := publicUploadImageExts[strings.ToLower(path.Ext(c.Param("filepath")))]; !ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		fileServer.ServeHTTP(c.Writer, c.Request)
+	}
+	r.GET("/uploads/*filepath", serve)
+	r.HEAD("/uploads/*filepath", serve)
+}
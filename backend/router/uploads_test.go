@@ -0,0 +1,41 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterPublicUploadsServesCoverImagesOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uploadDir := t.TempDir()
+	newsDir := filepath.Join(uploadDir, "news", "20260120")
+	if err := os.MkdirAll(newsDir, 0o755); err != nil {
+		t.Fatalf("create upload dir: %v", err)
+	}
+	for name, body := range map[string]string{"cover.PNG": "png", "report.pdf": "%PDF-1.4"} {
+		if err := os.WriteFile(filepath.Join(newsDir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	r := gin.New()
+	RegisterPublicUploads(r, uploadDir)
+
+	cases := map[string]int{
+		"/uploads/news/20260120/cover.PNG":  http.StatusOK,
+		"/uploads/news/20260120/report.pdf": http.StatusNotFound,
+		"/uploads/news/20260120/":           http.StatusNotFound,
+		"/uploads/news/20260120/gone.png":   http.StatusNotFound,
+	}
+	for target, want := range cases {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != want {
+			t.Fatalf("GET %s: status=%d, want %d", target, rec.Code, want)
+		}
+	}
+}
@@ -47,6 +47,10 @@ export function normalizeGlobalSearchResult(payload, keyword) {
     news: {
       items: normalizeGlobalSearchItems(result?.news?.items),
       total: Number(result?.news?.total || 0)
+    },
+    attachments: {
+      items: normalizeGlobalSearchItems(result?.attachments?.items),
+      total: Number(result?.attachments?.total || 0)
     }
   };
 }
@@ -152,6 +156,19 @@ function buildNewsSearchMeta(item) {
   return pieces.join(" · ");
 }
 
+function buildAttachmentSearchMeta(item) {
+  const pieces = [];
+  const page = Number(item?.page);
+  if (Number.isFinite(page) && page > 0) {
+    pieces.push(`第 ${page} 页`);
+  }
+  const articleTitle = normalizeText(item?.article_title);
+  if (articleTitle) {
+    pieces.push(`来自「${articleTitle}」`);
+  }
+  return pieces.join(" · ") || "附件";
+}
+
 export function buildGlobalSearchGroups(result) {
   const payload = normalizeGlobalSearchResult(result);
   return [
@@ -190,6 +207,21 @@ export function buildGlobalSearchGroups(result) {
         summary: summarizeText(item.summary || item.content),
         meta: buildNewsSearchMeta(item)
       }))
+    },
+    {
+      key: "attachments",
+      title: "研报附件",
+      total: Number(payload?.attachments?.total || 0),
+      emptyText: "当前关键词未命中附件正文。",
+      items: normalizeGlobalSearchItems(payload?.attachments?.items).map((item) => ({
+        id: item.attachment_id || "",
+        articleID: item.article_id || "",
+        articleTitle: item.article_title || "",
+        page: Number(item.page || 0),
+        title: item.file_name || "未命名附件",
+        summary: normalizeText(item.snippet) || "暂无摘要",
+        meta: buildAttachmentSearchMeta(item)
+      }))
     }
   ];
 }
@@ -335,6 +367,17 @@ export function buildSearchItemRoute(groupKey, item = {}) {
   if (groupKey === "strategies") {
     return { path: "/strategies", query: { futures_id: item.id || "" } };
   }
+  if (groupKey === "attachments") {
+    return {
+      path: "/news",
+      query: {
+        article_id: item.articleID || "",
+        keyword: normalizeText(item.articleTitle || ""),
+        attachment_id: item.id || "",
+        page: item.page ? String(item.page) : ""
+      }
+    };
+  }
   return {
     path: "/news",
     query: {
@@ -201,3 +201,34 @@ test("buildSearchItemRoute carries keyword when opening news result", () => {
     }
   );
 });
+
+test("attachment hits point at the article with the matched page", () => {
+  const groups = buildGlobalSearchGroups({
+    attachments: {
+      items: [
+        {
+          attachment_id: "att_001",
+          article_id: "na_001",
+          article_title: "白酒行业深度报告",
+          file_name: "report.pdf",
+          page: 3,
+          snippet: "…行业 估值 处于低位…"
+        }
+      ],
+      total: 1
+    }
+  });
+  const attachments = groups.find((group) => group.key === "attachments");
+
+  assert.equal(attachments.total, 1);
+  assert.equal(attachments.items[0].meta, "第 3 页 · 来自「白酒行业深度报告」");
+  assert.deepEqual(buildSearchItemRoute("attachments", attachments.items[0]), {
+    path: "/news",
+    query: {
+      article_id: "na_001",
+      keyword: "白酒行业深度报告",
+      attachment_id: "att_001",
+      page: "3"
+    }
+  });
+});